@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 )
 
 // AIAPI provides access to AI detection and tracking API endpoints
@@ -11,20 +13,25 @@ type AIAPI struct {
 	client *Client
 }
 
-// AiDetectType represents AI detection type configuration
+// AiDetectType represents AI detection type configuration. Types without a
+// dedicated field, such as AITypePackage on newer firmware, are kept in
+// Other so they survive a read-modify-write round trip.
 type AiDetectType struct {
-	People  int `json:"people"`  // 0=disabled, 1=enabled
-	Vehicle int `json:"vehicle"` // 0=disabled, 1=enabled
-	DogCat  int `json:"dog_cat"` // 0=disabled, 1=enabled
-	Face    int `json:"face"`    // 0=disabled, 1=enabled
+	People  int            `json:"people"`  // 0=disabled, 1=enabled
+	Vehicle int            `json:"vehicle"` // 0=disabled, 1=enabled
+	DogCat  int            `json:"dog_cat"` // 0=disabled, 1=enabled
+	Face    int            `json:"face"`    // 0=disabled, 1=enabled
+	Other   map[string]int `json:"-"`       // Additional types reported by the firmware
 }
 
-// AiTrackType represents AI tracking type configuration
+// AiTrackType represents AI tracking type configuration. Types without a
+// dedicated field are kept in Other.
 type AiTrackType struct {
-	People  int `json:"people"`  // 0=disabled, 1=enabled
-	Vehicle int `json:"vehicle"` // 0=disabled, 1=enabled
-	DogCat  int `json:"dog_cat"` // 0=disabled, 1=enabled
-	Face    int `json:"face"`    // 0=disabled, 1=enabled
+	People  int            `json:"people"`  // 0=disabled, 1=enabled
+	Vehicle int            `json:"vehicle"` // 0=disabled, 1=enabled
+	DogCat  int            `json:"dog_cat"` // 0=disabled, 1=enabled
+	Face    int            `json:"face"`    // 0=disabled, 1=enabled
+	Other   map[string]int `json:"-"`       // Additional types reported by the firmware
 }
 
 // AiCfg represents AI configuration
@@ -37,22 +44,24 @@ type AiCfg struct {
 
 // AiDetectState represents AI detection state for a specific type
 type AiDetectState struct {
-	AlarmState int `json:"alarm_state"` // 0=no alarm, 1=alarm detected
-	Support    int `json:"support"`     // 0=not supported, 1=supported
+	AlarmState FlexInt `json:"alarm_state"` // 0=no alarm, 1=alarm detected
+	Support    FlexInt `json:"support"`     // 0=not supported, 1=supported
 }
 
-// AiState represents AI alarm state
+// AiState represents AI alarm state. Types without a dedicated field are
+// kept in Other.
 type AiState struct {
-	Channel int           `json:"channel"` // Channel number
-	People  AiDetectState `json:"people"`  // People detection state
-	Vehicle AiDetectState `json:"vehicle"` // Vehicle detection state
-	DogCat  AiDetectState `json:"dog_cat"` // Dog/cat detection state
-	Face    AiDetectState `json:"face"`    // Face detection state
+	Channel int                      `json:"channel"` // Channel number
+	People  AiDetectState            `json:"people"`  // People detection state
+	Vehicle AiDetectState            `json:"vehicle"` // Vehicle detection state
+	DogCat  AiDetectState            `json:"dog_cat"` // Dog/cat detection state
+	Face    AiDetectState            `json:"face"`    // Face detection state
+	Other   map[string]AiDetectState `json:"-"`       // Additional types reported by the firmware
 }
 
 // GetAiCfg gets AI configuration
 func (a *AIAPI) GetAiCfg(ctx context.Context, channel int) (*AiCfg, error) {
-	a.client.logger.Debug("getting AI configuration: channel=%d", channel)
+	a.client.log(ctx).Debug("getting AI configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetAiCfg",
@@ -64,24 +73,24 @@ func (a *AIAPI) GetAiCfg(ctx context.Context, channel int) (*AiCfg, error) {
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to get AI configuration: %v", err)
+		a.client.log(ctx).Error("failed to get AI configuration: %v", err)
 		return nil, fmt.Errorf("GetAiCfg request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to get AI configuration: %v", err)
+		a.client.log(ctx).Error("failed to get AI configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to get AI configuration: %v", apiErr)
+		a.client.log(ctx).Error("failed to get AI configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var cfg AiCfg
-	if err := json.Unmarshal(resp[0].Value, &cfg); err != nil {
-		a.client.logger.Error("failed to parse AI configuration response: %v", err)
+	if err := a.client.unmarshal(resp[0].Value, &cfg); err != nil {
+		a.client.log(ctx).Error("failed to parse AI configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -90,7 +99,7 @@ func (a *AIAPI) GetAiCfg(ctx context.Context, channel int) (*AiCfg, error) {
 
 // SetAiCfg sets AI configuration
 func (a *AIAPI) SetAiCfg(ctx context.Context, config AiCfg) error {
-	a.client.logger.Info("setting AI configuration: channel=%d people=%d vehicle=%d dog_cat=%d face=%d",
+	a.client.log(ctx).Info("setting AI configuration: channel=%d people=%d vehicle=%d dog_cat=%d face=%d",
 		config.Channel, config.AiDetectType.People, config.AiDetectType.Vehicle,
 		config.AiDetectType.DogCat, config.AiDetectType.Face)
 
@@ -102,28 +111,28 @@ func (a *AIAPI) SetAiCfg(ctx context.Context, config AiCfg) error {
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to set AI configuration: %v", err)
+		a.client.log(ctx).Error("failed to set AI configuration: %v", err)
 		return fmt.Errorf("SetAiCfg request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to set AI configuration: %v", err)
+		a.client.log(ctx).Error("failed to set AI configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to set AI configuration: %v", apiErr)
+		a.client.log(ctx).Error("failed to set AI configuration: %v", apiErr)
 		return apiErr
 	}
 
-	a.client.logger.Info("successfully set AI configuration")
+	a.client.log(ctx).Info("successfully set AI configuration")
 	return nil
 }
 
 // GetAiState gets AI alarm state
 func (a *AIAPI) GetAiState(ctx context.Context, channel int) (*AiState, error) {
-	a.client.logger.Debug("getting AI state: channel=%d", channel)
+	a.client.log(ctx).Debug("getting AI state: channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetAiState",
@@ -134,28 +143,306 @@ func (a *AIAPI) GetAiState(ctx context.Context, channel int) (*AiState, error) {
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to get AI state: %v", err)
+		a.client.log(ctx).Error("failed to get AI state: %v", err)
 		return nil, fmt.Errorf("GetAiState request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to get AI state: %v", err)
+		a.client.log(ctx).Error("failed to get AI state: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to get AI state: %v", apiErr)
+		a.client.log(ctx).Error("failed to get AI state: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var state AiState
-	if err := json.Unmarshal(resp[0].Value, &state); err != nil {
-		a.client.logger.Error("failed to parse AI state response: %v", err)
+	if err := a.client.unmarshal(resp[0].Value, &state); err != nil {
+		a.client.log(ctx).Error("failed to parse AI state response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	a.client.logger.Info("successfully retrieved AI state: people=%d vehicle=%d dog_cat=%d face=%d",
+	a.client.log(ctx).Info("successfully retrieved AI state: people=%d vehicle=%d dog_cat=%d face=%d",
 		state.People.AlarmState, state.Vehicle.AlarmState, state.DogCat.AlarmState, state.Face.AlarmState)
 	return &state, nil
 }
+
+// aiTypes lists the AI types with dedicated fields in the order the API
+// reports them
+var aiTypes = []string{AITypePeople, AITypeVehicle, AITypeDogCat, AITypeFace}
+
+// aiSwitches maps the dedicated AI type fields of a detection or tracking
+// type set
+func aiSwitches(people, vehicle, dogCat, face *int) map[string]*int {
+	return map[string]*int{AITypePeople: people, AITypeVehicle: vehicle, AITypeDogCat: dogCat, AITypeFace: face}
+}
+
+// switches returns the dedicated fields of t
+func (t *AiDetectType) switches() map[string]*int {
+	return aiSwitches(&t.People, &t.Vehicle, &t.DogCat, &t.Face)
+}
+
+// Get returns the switch for an AI type (0 if absent)
+func (t *AiDetectType) Get(aiType string) int {
+	return getAiSwitch(t.switches(), t.Other, aiType)
+}
+
+// Set sets the switch for an AI type
+func (t *AiDetectType) Set(aiType string, value int) {
+	setAiSwitch(t.switches(), &t.Other, aiType, value)
+}
+
+// Types returns the AI types present, dedicated fields first
+func (t *AiDetectType) Types() []string {
+	return aiTypeNames(t.Other)
+}
+
+// MarshalJSON encodes the dedicated fields and Other as one object
+func (t AiDetectType) MarshalJSON() ([]byte, error) {
+	return marshalAiSwitches(t.switches(), t.Other)
+}
+
+// UnmarshalJSON decodes known types into their fields and the rest into Other
+func (t *AiDetectType) UnmarshalJSON(data []byte) error {
+	return unmarshalAiSwitches(data, t.switches(), &t.Other)
+}
+
+// switches returns the dedicated fields of t
+func (t *AiTrackType) switches() map[string]*int {
+	return aiSwitches(&t.People, &t.Vehicle, &t.DogCat, &t.Face)
+}
+
+// Get returns the switch for an AI type (0 if absent)
+func (t *AiTrackType) Get(aiType string) int {
+	return getAiSwitch(t.switches(), t.Other, aiType)
+}
+
+// Set sets the switch for an AI type
+func (t *AiTrackType) Set(aiType string, value int) {
+	setAiSwitch(t.switches(), &t.Other, aiType, value)
+}
+
+// Types returns the AI types present, dedicated fields first
+func (t *AiTrackType) Types() []string {
+	return aiTypeNames(t.Other)
+}
+
+// MarshalJSON encodes the dedicated fields and Other as one object
+func (t AiTrackType) MarshalJSON() ([]byte, error) {
+	return marshalAiSwitches(t.switches(), t.Other)
+}
+
+// UnmarshalJSON decodes known types into their fields and the rest into Other
+func (t *AiTrackType) UnmarshalJSON(data []byte) error {
+	return unmarshalAiSwitches(data, t.switches(), &t.Other)
+}
+
+func getAiSwitch(fields map[string]*int, other map[string]int, aiType string) int {
+	if f, ok := fields[aiType]; ok {
+		return *f
+	}
+	return other[aiType]
+}
+
+func setAiSwitch(fields map[string]*int, other *map[string]int, aiType string, value int) {
+	if f, ok := fields[aiType]; ok {
+		*f = value
+		return
+	}
+	if *other == nil {
+		*other = make(map[string]int)
+	}
+	(*other)[aiType] = value
+}
+
+func marshalAiSwitches(fields map[string]*int, other map[string]int) ([]byte, error) {
+	m := make(map[string]int, len(fields)+len(other))
+	maps.Copy(m, other)
+	for name, f := range fields {
+		m[name] = *f
+	}
+	return json.Marshal(m)
+}
+
+func unmarshalAiSwitches(data []byte, fields map[string]*int, other *map[string]int) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*other = nil
+	for name, v := range raw {
+		var value FlexInt
+		if err := json.Unmarshal(v, &value); err != nil {
+			continue // Not a switch
+		}
+		setAiSwitch(fields, other, name, int(value))
+	}
+	return nil
+}
+
+// aiTypeNames returns the dedicated AI types followed by the keys of other
+// in sorted order
+func aiTypeNames[V any](other map[string]V) []string {
+	return append(slices.Clone(aiTypes), slices.Sorted(maps.Keys(other))...)
+}
+
+// Get returns the state of an AI type (zero if absent)
+func (s *AiState) Get(aiType string) AiDetectState {
+	switch aiType {
+	case AITypePeople:
+		return s.People
+	case AITypeVehicle:
+		return s.Vehicle
+	case AITypeDogCat:
+		return s.DogCat
+	case AITypeFace:
+		return s.Face
+	}
+	return s.Other[aiType]
+}
+
+// Supported returns the AI types the channel supports, dedicated fields
+// first and additional types in sorted order
+func (s *AiState) Supported() []string {
+	var types []string
+	for _, t := range aiTypeNames(s.Other) {
+		if s.Get(t).Support == 1 {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// MarshalJSON encodes the dedicated fields and Other as one object
+func (s AiState) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(s.Other)+5)
+	for name, st := range s.Other {
+		m[name] = st
+	}
+	m["channel"] = s.Channel
+	m[AITypePeople] = s.People
+	m[AITypeVehicle] = s.Vehicle
+	m[AITypeDogCat] = s.DogCat
+	m[AITypeFace] = s.Face
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes known types into their fields and other detection
+// states into Other
+func (s *AiState) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = AiState{}
+	for name, v := range raw {
+		if name == "channel" {
+			var channel FlexInt
+			if err := json.Unmarshal(v, &channel); err != nil {
+				return fmt.Errorf("invalid channel: %w", err)
+			}
+			s.Channel = int(channel)
+			continue
+		}
+		var st AiDetectState
+		if v[0] != '{' || json.Unmarshal(v, &st) != nil {
+			continue // Not a detection state
+		}
+		switch name {
+		case AITypePeople:
+			s.People = st
+		case AITypeVehicle:
+			s.Vehicle = st
+		case AITypeDogCat:
+			s.DogCat = st
+		case AITypeFace:
+			s.Face = st
+		default:
+			if s.Other == nil {
+				s.Other = make(map[string]AiDetectState)
+			}
+			s.Other[name] = st
+		}
+	}
+	return nil
+}
+
+// Validate checks that cfg only enables detection and tracking for AI
+// types the channel supports according to state
+func (c *AiCfg) Validate(state *AiState) error {
+	if c.AiTrack != 0 && c.AiTrack != 1 {
+		return fmt.Errorf("aiTrack must be 0 or 1, got %d", c.AiTrack)
+	}
+	supported := state.Supported()
+	for _, t := range append(c.AiDetectType.Types(), c.TrackType.Types()...) {
+		if (c.AiDetectType.Get(t) == 1 || c.TrackType.Get(t) == 1) && !slices.Contains(supported, t) {
+			return fmt.Errorf("AI type %q is not supported on channel %d", t, c.Channel)
+		}
+	}
+	return nil
+}
+
+// SetAiDetection enables or disables detection of one AI type (AITypePeople,
+// AITypeVehicle, AITypeDogCat, AITypeFace or a type reported by newer
+// firmware such as AITypePackage), leaving the other settings unchanged.
+// Enabling a type the channel does not support is rejected.
+func (a *AIAPI) SetAiDetection(ctx context.Context, channel int, aiType string, enable bool) error {
+	return a.updateAiCfg(ctx, channel, func(cfg *AiCfg, state *AiState) error {
+		if !slices.Contains(cfg.AiDetectType.Types(), aiType) && !slices.Contains(state.Supported(), aiType) {
+			return fmt.Errorf("unknown AI type %q", aiType)
+		}
+		cfg.AiDetectType.Set(aiType, boolToInt(enable))
+		return nil
+	})
+}
+
+// SetAiTracking turns AI auto-tracking on or off. When enabling, aiTypes
+// selects the types to track (all currently tracked types are kept if none
+// are given); when disabling, aiTypes is ignored.
+func (a *AIAPI) SetAiTracking(ctx context.Context, channel int, enable bool, aiTypes ...string) error {
+	return a.updateAiCfg(ctx, channel, func(cfg *AiCfg, state *AiState) error {
+		cfg.AiTrack = boolToInt(enable)
+		if !enable || len(aiTypes) == 0 {
+			return nil
+		}
+		for _, t := range cfg.TrackType.Types() {
+			cfg.TrackType.Set(t, 0)
+		}
+		for _, t := range aiTypes {
+			cfg.TrackType.Set(t, 1)
+		}
+		return nil
+	})
+}
+
+// updateAiCfg reads the AI configuration, applies fn, validates the result
+// against GetAiState and writes it back
+func (a *AIAPI) updateAiCfg(ctx context.Context, channel int, fn func(*AiCfg, *AiState) error) error {
+	cfg, err := a.GetAiCfg(ctx, channel)
+	if err != nil {
+		return fmt.Errorf("failed to read AI configuration: %w", err)
+	}
+	state, err := a.GetAiState(ctx, channel)
+	if err != nil {
+		return fmt.Errorf("failed to read AI support: %w", err)
+	}
+
+	cfg.Channel = channel
+	if err := fn(cfg, state); err != nil {
+		return err
+	}
+	if err := cfg.Validate(state); err != nil {
+		return err
+	}
+	return a.SetAiCfg(ctx, *cfg)
+}
+
+// boolToInt converts a switch to the API's 0/1 representation
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,108 @@
+package reolink
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTargetSize_Validate(t *testing.T) {
+	valid := []TargetSize{
+		{},
+		{MinWidth: 0.1, MaxWidth: 0.5, MinHeight: 0.2, MaxHeight: 1},
+		{MinHeight: 0.3}, // No maximum
+	}
+	for _, s := range valid {
+		if err := s.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", s, err)
+		}
+	}
+
+	invalid := []TargetSize{
+		{MinWidth: -0.1},
+		{MaxHeight: 1.5},
+		{MinWidth: 0.6, MaxWidth: 0.5},
+	}
+	for _, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("%+v: expected validation error", s)
+		}
+	}
+}
+
+func TestAIAPI_TargetSize(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetAiAlarm": `{"AiAlarm": {"channel": 0, "ai_type": "vehicle", "sensitivity": 10, "stay_time": 0, "width": 80, "height": 60,
+			"min_target_height": 0.0, "max_target_height": 0.0, "min_target_width": 0.0, "max_target_width": 0.0}}`,
+		"SetAiAlarm": "",
+	})
+	client := server.client()
+
+	size, err := client.AI.GetTargetSize(t.Context(), 0, AITypeVehicle)
+	if err != nil {
+		t.Fatalf("GetTargetSize failed: %v", err)
+	}
+	if *size != (TargetSize{}) {
+		t.Errorf("expected no size limits, got %+v", size)
+	}
+	var getParam map[string]interface{}
+	json.Unmarshal(server.lastParam("GetAiAlarm"), &getParam)
+	if getParam["ai_type"] != AITypeVehicle {
+		t.Errorf("expected ai_type in GetAiAlarm param, got %v", getParam)
+	}
+
+	want := TargetSize{MinWidth: 0.05, MaxWidth: 0.6, MinHeight: 0.1, MaxHeight: 0.8}
+	if err := client.AI.SetTargetSize(t.Context(), 0, AITypeVehicle, want); err != nil {
+		t.Fatalf("SetTargetSize failed: %v", err)
+	}
+	var sent AiAlarmParam
+	if err := json.Unmarshal(server.lastParam("SetAiAlarm"), &sent); err != nil {
+		t.Fatalf("failed to parse SetAiAlarm param: %v", err)
+	}
+	if sent.AiAlarm.MinTargetWidth != 0.05 || sent.AiAlarm.MaxTargetHeight != 0.8 {
+		t.Errorf("expected target sizes to be sent, got %+v", sent.AiAlarm)
+	}
+	if sent.AiAlarm.Sensitivity != 10 || sent.AiAlarm.Width != 80 {
+		t.Errorf("expected other AI alarm settings kept, got %+v", sent.AiAlarm)
+	}
+
+	if err := client.AI.SetTargetSize(t.Context(), 0, AITypeVehicle, TargetSize{MinWidth: 2}); err == nil {
+		t.Error("expected validation error")
+	}
+	if n := server.callCount("SetAiAlarm"); n != 1 {
+		t.Errorf("expected 1 SetAiAlarm call, got %d", n)
+	}
+}
+
+func TestAIAPI_AiSensitivity(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetAiAlarm": `{"AiAlarm": {"channel": 0, "ai_type": "people", "sensitivity": 10, "stay_time": 0, "width": 80, "height": 60,
+			"min_target_height": 0.1, "max_target_height": 0.0, "min_target_width": 0.0, "max_target_width": 0.0}}`,
+		"SetAiAlarm": "",
+	})
+	client := server.client()
+
+	got, err := client.AI.GetAiSensitivity(t.Context(), 0, AITypePeople)
+	if err != nil {
+		t.Fatalf("GetAiSensitivity failed: %v", err)
+	}
+	if got.Sensitivity != 10 || got.StayTime != 0 {
+		t.Errorf("unexpected sensitivity: %+v", got)
+	}
+
+	if err := client.AI.SetAiSensitivity(t.Context(), 0, AITypePeople, AiSensitivity{Sensitivity: 60, StayTime: 2}); err != nil {
+		t.Fatalf("SetAiSensitivity failed: %v", err)
+	}
+	var sent AiAlarmParam
+	if err := json.Unmarshal(server.lastParam("SetAiAlarm"), &sent); err != nil {
+		t.Fatalf("failed to parse SetAiAlarm param: %v", err)
+	}
+	if sent.AiAlarm.Sensitivity != 60 || sent.AiAlarm.StayTime != 2 || sent.AiAlarm.MinTargetHeight != 0.1 {
+		t.Errorf("expected sensitivity and delay set with target size kept, got %+v", sent.AiAlarm)
+	}
+
+	for _, s := range []AiSensitivity{{Sensitivity: 101}, {Sensitivity: 50, StayTime: -1}} {
+		if err := client.AI.SetAiSensitivity(t.Context(), 0, AITypePeople, s); err == nil {
+			t.Errorf("%+v: expected validation error", s)
+		}
+	}
+}
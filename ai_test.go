@@ -219,3 +219,154 @@ func TestAIAPI_GetAiState(t *testing.T) {
 		t.Errorf("Expected face support 0, got %d", state.Face.Support)
 	}
 }
+
+func newAiServer(t *testing.T) *cmdServer {
+	t.Helper()
+	return newCmdServer(t, map[string]string{
+		"GetAiCfg": `{"channel": 0, "aiTrack": 0, "AiDetectType": {"people": 1, "vehicle": 0, "dog_cat": 0, "face": 0}, "trackType": {"people": 0, "vehicle": 0, "dog_cat": 0, "face": 0}}`,
+		"GetAiState": `{"channel": 0, "people": {"alarm_state": 0, "support": 1}, "vehicle": {"alarm_state": 0, "support": 1},
+			"dog_cat": {"alarm_state": 0, "support": 0}, "face": {"alarm_state": 0, "support": 0}}`,
+		"SetAiCfg": "",
+	})
+}
+
+func TestAIAPI_SetAiDetection(t *testing.T) {
+	server := newAiServer(t)
+	client := server.client()
+
+	if err := client.AI.SetAiDetection(t.Context(), 0, AITypeVehicle, true); err != nil {
+		t.Fatalf("SetAiDetection failed: %v", err)
+	}
+	var sent AiCfg
+	if err := json.Unmarshal(server.lastParam("SetAiCfg"), &sent); err != nil {
+		t.Fatalf("failed to parse SetAiCfg param: %v", err)
+	}
+	if sent.AiDetectType.Vehicle != 1 || sent.AiDetectType.People != 1 {
+		t.Errorf("expected vehicle enabled and people kept, got %+v", sent.AiDetectType)
+	}
+
+	if err := client.AI.SetAiDetection(t.Context(), 0, AITypeDogCat, true); err == nil {
+		t.Error("expected error enabling an unsupported AI type")
+	}
+	if err := client.AI.SetAiDetection(t.Context(), 0, AITypeDogCat, false); err != nil {
+		t.Errorf("disabling an unsupported AI type should succeed: %v", err)
+	}
+	if err := client.AI.SetAiDetection(t.Context(), 0, "cat", true); err == nil {
+		t.Error("expected error for an unknown AI type")
+	}
+	if n := server.callCount("SetAiCfg"); n != 2 {
+		t.Errorf("expected 2 SetAiCfg calls, got %d", n)
+	}
+}
+
+func TestAIAPI_SetAiTracking(t *testing.T) {
+	server := newAiServer(t)
+	client := server.client()
+
+	if err := client.AI.SetAiTracking(t.Context(), 0, true, AITypePeople); err != nil {
+		t.Fatalf("SetAiTracking failed: %v", err)
+	}
+	var sent AiCfg
+	if err := json.Unmarshal(server.lastParam("SetAiCfg"), &sent); err != nil {
+		t.Fatalf("failed to parse SetAiCfg param: %v", err)
+	}
+	if sent.AiTrack != 1 || sent.TrackType.People != 1 || sent.TrackType.Vehicle != 0 || sent.TrackType.Other != nil {
+		t.Errorf("expected people tracking enabled, got %+v", sent)
+	}
+
+	if err := client.AI.SetAiTracking(t.Context(), 0, true, AITypeFace); err == nil {
+		t.Error("expected error tracking an unsupported AI type")
+	}
+}
+
+func TestAiState_Supported(t *testing.T) {
+	state := AiState{People: AiDetectState{Support: 1}, Face: AiDetectState{Support: 1}}
+	got := state.Supported()
+	if len(got) != 2 || got[0] != AITypePeople || got[1] != AITypeFace {
+		t.Errorf("expected [people face], got %v", got)
+	}
+}
+
+func TestAiDetectType_UnknownTypes(t *testing.T) {
+	var cfg AiCfg
+	data := `{"channel": 0, "aiTrack": 1, "AiDetectType": {"people": 1, "vehicle": 0, "dog_cat": 1, "face": 0, "package": 1, "cry": 0},
+		"trackType": {"people": 1, "vehicle": 0, "dog_cat": 0, "face": 0}}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("failed to parse AiCfg: %v", err)
+	}
+	if cfg.AiDetectType.People != 1 || cfg.AiDetectType.DogCat != 1 {
+		t.Errorf("expected known types in their fields, got %+v", cfg.AiDetectType)
+	}
+	if cfg.AiDetectType.Get(AITypePackage) != 1 || len(cfg.AiDetectType.Other) != 2 {
+		t.Errorf("expected unknown types in Other, got %v", cfg.AiDetectType.Other)
+	}
+	want := []string{AITypePeople, AITypeVehicle, AITypeDogCat, AITypeFace, "cry", AITypePackage}
+	if got := cfg.AiDetectType.Types(); len(got) != len(want) || got[4] != "cry" || got[5] != AITypePackage {
+		t.Errorf("expected types %v, got %v", want, got)
+	}
+
+	cfg.AiDetectType.Set(AITypePackage, 0)
+	cfg.AiDetectType.Set(AITypeFace, 1)
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("failed to encode AiCfg: %v", err)
+	}
+	var round struct {
+		AiDetectType map[string]int `json:"AiDetectType"`
+	}
+	json.Unmarshal(out, &round)
+	if round.AiDetectType["package"] != 0 || round.AiDetectType["face"] != 1 || len(round.AiDetectType) != 6 {
+		t.Errorf("expected all types to round-trip, got %v", round.AiDetectType)
+	}
+}
+
+func TestAiState_UnknownTypes(t *testing.T) {
+	var state AiState
+	data := `{"channel": 1, "people": {"alarm_state": 1, "support": 1}, "package": {"alarm_state": 1, "support": 1}, "cry": {"alarm_state": 0, "support": 0}}`
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		t.Fatalf("failed to parse AiState: %v", err)
+	}
+	if state.Channel != 1 || state.People.AlarmState != 1 {
+		t.Errorf("unexpected known fields: %+v", state)
+	}
+	if state.Get(AITypePackage).AlarmState != 1 {
+		t.Error("expected package state in Other")
+	}
+	got := state.Supported()
+	if len(got) != 2 || got[0] != AITypePeople || got[1] != AITypePackage {
+		t.Errorf("expected [people package], got %v", got)
+	}
+}
+
+func TestAi_QuotedNumbers(t *testing.T) {
+	var state AiState
+	if err := json.Unmarshal([]byte(`{"channel": "1", "people": {"alarm_state": "1", "support": "1"}}`), &state); err != nil {
+		t.Fatalf("failed to parse AiState: %v", err)
+	}
+	if state.Channel != 1 || state.People.AlarmState != 1 {
+		t.Errorf("unexpected state: %+v", state)
+	}
+
+	var detect AiDetectType
+	if err := json.Unmarshal([]byte(`{"people": "1", "vehicle": 0, "package": "1"}`), &detect); err != nil {
+		t.Fatalf("failed to parse AiDetectType: %v", err)
+	}
+	if detect.People != 1 || detect.Get(AITypePackage) != 1 {
+		t.Errorf("expected quoted switches to decode, got %+v", detect)
+	}
+}
+
+func TestAIAPI_SetAiDetection_NewType(t *testing.T) {
+	server := newAiServer(t)
+	server.set("GetAiState", `{"channel": 0, "people": {"alarm_state": 0, "support": 1}, "package": {"alarm_state": 0, "support": 1}}`)
+	client := server.client()
+
+	if err := client.AI.SetAiDetection(t.Context(), 0, AITypePackage, true); err != nil {
+		t.Fatalf("SetAiDetection failed: %v", err)
+	}
+	var sent AiCfg
+	json.Unmarshal(server.lastParam("SetAiCfg"), &sent)
+	if sent.AiDetectType.Get(AITypePackage) != 1 {
+		t.Errorf("expected package detection enabled, got %+v", sent.AiDetectType)
+	}
+}
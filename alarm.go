@@ -2,7 +2,7 @@ package reolink
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -20,14 +20,14 @@ type MdScope struct {
 
 // MdSensitivity represents time-based sensitivity settings
 type MdSensitivity struct {
-	ID          int `json:"id"`          // Time period ID (0-3)
-	BeginHour   int `json:"beginHour"`   // Start hour (0-23)
-	BeginMin    int `json:"beginMin"`    // Start minute (0-59)
-	EndHour     int `json:"endHour"`     // End hour (0-23)
-	EndMin      int `json:"endMin"`      // End minute (0-59)
-	Enable      int `json:"enable"`      // 0=disabled, 1=enabled
-	Priority    int `json:"priority"`    // Priority level
-	Sensitivity int `json:"sensitivity"` // Sensitivity (0-100, higher = more sensitive)
+	ID          int     `json:"id"`          // Time period ID (0-3)
+	BeginHour   int     `json:"beginHour"`   // Start hour (0-23)
+	BeginMin    int     `json:"beginMin"`    // Start minute (0-59)
+	EndHour     int     `json:"endHour"`     // End hour (0-23)
+	EndMin      int     `json:"endMin"`      // End minute (0-59)
+	Enable      BoolInt `json:"enable"`      // 0=disabled, 1=enabled
+	Priority    int     `json:"priority"`    // Priority level
+	Sensitivity int     `json:"sensitivity"` // Sensitivity (0-100, higher = more sensitive)
 }
 
 // MdNewSens wraps sensitivity array
@@ -54,7 +54,7 @@ type MdAlarmParam struct {
 
 // MdStateValue represents motion detection state
 type MdStateValue struct {
-	State int `json:"state"` // 0=no motion, 1=motion detected
+	State FlexInt `json:"state"` // 0=no motion, 1=motion detected
 }
 
 // AudioAlarmPlayParam represents parameters for AudioAlarmPlay
@@ -67,7 +67,7 @@ type AudioAlarmPlayParam struct {
 
 // GetMdState gets current motion detection state
 func (a *AlarmAPI) GetMdState(ctx context.Context, channel int) (int, error) {
-	a.client.logger.Debug("getting motion detection state: channel=%d", channel)
+	a.client.log(ctx).Debug("getting motion detection state: channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetMdState",
@@ -78,34 +78,34 @@ func (a *AlarmAPI) GetMdState(ctx context.Context, channel int) (int, error) {
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to get motion detection state: %v", err)
+		a.client.log(ctx).Error("failed to get motion detection state: %v", err)
 		return 0, fmt.Errorf("GetMdState request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to get motion detection state: %v", err)
+		a.client.log(ctx).Error("failed to get motion detection state: %v", err)
 		return 0, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to get motion detection state: %v", apiErr)
+		a.client.log(ctx).Error("failed to get motion detection state: %v", apiErr)
 		return 0, apiErr
 	}
 
 	var value MdStateValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		a.client.logger.Error("failed to parse motion detection state response: %v", err)
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse motion detection state response: %v", err)
 		return 0, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	a.client.logger.Info("successfully retrieved motion detection state: state=%d", value.State)
-	return value.State, nil
+	a.client.log(ctx).Info("successfully retrieved motion detection state: state=%d", value.State)
+	return int(value.State), nil
 }
 
 // GetMdAlarm gets motion detection alarm configuration
 func (a *AlarmAPI) GetMdAlarm(ctx context.Context, channel int) (*MdAlarm, error) {
-	a.client.logger.Debug("getting motion detection alarm configuration: channel=%d", channel)
+	a.client.log(ctx).Debug("getting motion detection alarm configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetMdAlarm",
@@ -117,35 +117,35 @@ func (a *AlarmAPI) GetMdAlarm(ctx context.Context, channel int) (*MdAlarm, error
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to get motion detection alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to get motion detection alarm configuration: %v", err)
 		return nil, fmt.Errorf("GetMdAlarm request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to get motion detection alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to get motion detection alarm configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to get motion detection alarm configuration: %v", apiErr)
+		a.client.log(ctx).Error("failed to get motion detection alarm configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value MdAlarmValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		a.client.logger.Error("failed to parse motion detection alarm configuration response: %v", err)
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse motion detection alarm configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	a.client.logger.Info("successfully retrieved motion detection alarm configuration: channel=%d",
+	a.client.log(ctx).Info("successfully retrieved motion detection alarm configuration: channel=%d",
 		value.MdAlarm.Channel)
 	return &value.MdAlarm, nil
 }
 
 // SetMdAlarm sets motion detection alarm configuration
 func (a *AlarmAPI) SetMdAlarm(ctx context.Context, config MdAlarm) error {
-	a.client.logger.Info("setting motion detection alarm configuration: channel=%d",
+	a.client.log(ctx).Info("setting motion detection alarm configuration: channel=%d",
 		config.Channel)
 
 	req := []Request{{
@@ -157,28 +157,28 @@ func (a *AlarmAPI) SetMdAlarm(ctx context.Context, config MdAlarm) error {
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to set motion detection alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to set motion detection alarm configuration: %v", err)
 		return fmt.Errorf("SetMdAlarm request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to set motion detection alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to set motion detection alarm configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to set motion detection alarm configuration: %v", apiErr)
+		a.client.log(ctx).Error("failed to set motion detection alarm configuration: %v", apiErr)
 		return apiErr
 	}
 
-	a.client.logger.Info("successfully set motion detection alarm configuration")
+	a.client.log(ctx).Info("successfully set motion detection alarm configuration")
 	return nil
 }
 
 // AudioAlarmPlay plays audio alarm sound
 func (a *AlarmAPI) AudioAlarmPlay(ctx context.Context, param AudioAlarmPlayParam) error {
-	a.client.logger.Info("playing audio alarm: channel=%d", param.Channel)
+	a.client.log(ctx).Info("playing audio alarm: channel=%d", param.Channel)
 
 	req := []Request{{
 		Cmd:   "AudioAlarmPlay",
@@ -187,30 +187,54 @@ func (a *AlarmAPI) AudioAlarmPlay(ctx context.Context, param AudioAlarmPlayParam
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to play audio alarm: %v", err)
+		a.client.log(ctx).Error("failed to play audio alarm: %v", err)
 		return fmt.Errorf("AudioAlarmPlay request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to play audio alarm: %v", err)
+		a.client.log(ctx).Error("failed to play audio alarm: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to play audio alarm: %v", apiErr)
+		a.client.log(ctx).Error("failed to play audio alarm: %v", apiErr)
 		return apiErr
 	}
 
-	a.client.logger.Info("successfully played audio alarm")
+	a.client.log(ctx).Info("successfully played audio alarm")
 	return nil
 }
 
+// Alarm types accepted by GetAlarm. Older firmware only supports
+// AlarmTypeMD.
+const (
+	AlarmTypeMD      = "md"      // Motion detection
+	AlarmTypePeople  = "people"  // AI person detection
+	AlarmTypeVehicle = "vehicle" // AI vehicle detection
+	AlarmTypeDogCat  = "dog_cat" // AI pet detection
+	AlarmTypeVisitor = "visitor" // Doorbell visitor (button press)
+	AlarmTypeIO      = "io"      // IO alarm input
+)
+
+// alarmTypeAbilities maps each alarm type to the channel ability that
+// reports it. Visitor alarms have no documented ability.
+var alarmTypeAbilities = []struct {
+	alarmType, ability string
+}{
+	{AlarmTypeMD, "alarmMd"},
+	{AlarmTypePeople, "supportAiPeople"},
+	{AlarmTypeVehicle, "supportAiVehicle"},
+	{AlarmTypeDogCat, "supportAiDogCat"},
+	{AlarmTypeVisitor, ""},
+	{AlarmTypeIO, "alarmIoIn"},
+}
+
 // Alarm represents general alarm configuration
 type Alarm struct {
 	Channel int      `json:"channel"` // Channel number
-	Type    string   `json:"type"`    // Alarm type (e.g., "md" for motion detection)
-	Enable  int      `json:"enable"`  // 0=disabled, 1=enabled
+	Type    string   `json:"type"`    // Alarm type, one of the AlarmType constants
+	Enable  BoolInt  `json:"enable"`  // 0=disabled, 1=enabled
 	Scope   MdScope  `json:"scope"`   // Detection area
 	Sens    []MdSens `json:"sens"`    // Time-based sensitivity settings
 }
@@ -232,14 +256,14 @@ type AlarmValue struct {
 // AudioAlarm represents audio detection alarm configuration
 type AudioAlarm struct {
 	Channel     int                `json:"channel"`     // Channel number
-	Enable      int                `json:"enable"`      // 0=disabled, 1=enabled
+	Enable      BoolInt            `json:"enable"`      // 0=disabled, 1=enabled
 	Sensitivity int                `json:"sensitivity"` // Audio sensitivity (0-100)
 	Schedule    AudioAlarmSchedule `json:"schedule"`    // Schedule configuration
 }
 
 // AudioAlarmSchedule represents audio alarm schedule
 type AudioAlarmSchedule struct {
-	Enable int         `json:"enable"` // 0=disabled, 1=enabled
+	Enable BoolInt     `json:"enable"` // 0=disabled, 1=enabled
 	Table  interface{} `json:"table"`  // string for v1, map for v2.0
 }
 
@@ -251,13 +275,13 @@ type AudioAlarmValue struct {
 // BuzzerAlarm represents buzzer alarm configuration
 type BuzzerAlarm struct {
 	Channel  int                 `json:"channel"`  // Channel number
-	Enable   int                 `json:"enable"`   // 0=disabled, 1=enabled
+	Enable   BoolInt             `json:"enable"`   // 0=disabled, 1=enabled
 	Schedule BuzzerAlarmSchedule `json:"schedule"` // Schedule configuration
 }
 
 // BuzzerAlarmSchedule represents buzzer alarm schedule
 type BuzzerAlarmSchedule struct {
-	Enable int         `json:"enable"` // 0=disabled, 1=enabled
+	Enable BoolInt     `json:"enable"` // 0=disabled, 1=enabled
 	Table  interface{} `json:"table"`  // string for v1, map for v2.0
 }
 
@@ -268,7 +292,7 @@ type BuzzerAlarmValue struct {
 
 // GetAlarm gets general alarm configuration
 func (a *AlarmAPI) GetAlarm(ctx context.Context, channel int, alarmType string) (*Alarm, error) {
-	a.client.logger.Debug("getting alarm configuration: channel=%d type=%s", channel, alarmType)
+	a.client.log(ctx).Debug("getting alarm configuration: channel=%d type=%s", channel, alarmType)
 
 	req := []Request{{
 		Cmd:    "GetAlarm",
@@ -283,34 +307,34 @@ func (a *AlarmAPI) GetAlarm(ctx context.Context, channel int, alarmType string)
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to get alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to get alarm configuration: %v", err)
 		return nil, fmt.Errorf("GetAlarm request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to get alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to get alarm configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to get alarm configuration: %v", apiErr)
+		a.client.log(ctx).Error("failed to get alarm configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value AlarmValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		a.client.logger.Error("failed to parse alarm configuration response: %v", err)
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse alarm configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	a.client.logger.Info("successfully retrieved alarm configuration: type=%s enable=%d", value.Alarm.Type, value.Alarm.Enable)
+	a.client.log(ctx).Info("successfully retrieved alarm configuration: type=%s enable=%d", value.Alarm.Type, value.Alarm.Enable)
 	return &value.Alarm, nil
 }
 
 // SetAlarm sets general alarm configuration
 func (a *AlarmAPI) SetAlarm(ctx context.Context, alarm Alarm) error {
-	a.client.logger.Info("setting alarm configuration: channel=%d type=%s enable=%d", alarm.Channel, alarm.Type, alarm.Enable)
+	a.client.log(ctx).Info("setting alarm configuration: channel=%d type=%s enable=%d", alarm.Channel, alarm.Type, alarm.Enable)
 
 	req := []Request{{
 		Cmd: "SetAlarm",
@@ -321,28 +345,98 @@ func (a *AlarmAPI) SetAlarm(ctx context.Context, alarm Alarm) error {
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to set alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to set alarm configuration: %v", err)
 		return fmt.Errorf("SetAlarm request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to set alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to set alarm configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to set alarm configuration: %v", apiErr)
+		a.client.log(ctx).Error("failed to set alarm configuration: %v", apiErr)
 		return apiErr
 	}
 
-	a.client.logger.Info("successfully set alarm configuration")
+	a.client.log(ctx).Info("successfully set alarm configuration")
 	return nil
 }
 
+// GetAllAlarms gets the configuration of every alarm type of channel in a
+// single request, keyed by type. Types the ability set reports as
+// unsupported (ver 0) are not requested, and types the camera rejects as
+// not supported are left out of the result. If other types fail,
+// GetAllAlarms returns the rest with a *MultiError.
+func (a *AlarmAPI) GetAllAlarms(ctx context.Context, channel int) (map[string]*Alarm, error) {
+	a.client.log(ctx).Debug("getting all alarm configurations: channel=%d", channel)
+
+	ability, err := a.client.abilities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get abilities: %w", err)
+	}
+
+	var (
+		types []string
+		reqs  []Request
+	)
+	for _, t := range alarmTypeAbilities {
+		if t.ability != "" {
+			if ver, _, ok := ability.Version(channel, t.ability); ok && ver == 0 {
+				continue
+			}
+		}
+		types = append(types, t.alarmType)
+		reqs = append(reqs, Request{
+			Cmd:    "GetAlarm",
+			Action: 1,
+			Param: map[string]interface{}{
+				"Alarm": map[string]interface{}{
+					"channel": channel,
+					"type":    t.alarmType,
+				},
+			},
+		})
+	}
+
+	resp, err := a.client.Batch(ctx, reqs)
+	var multi *MultiError
+	if err != nil && !errors.As(err, &multi) {
+		return nil, fmt.Errorf("failed to get alarm configurations: %w", err)
+	}
+
+	alarms := make(map[string]*Alarm)
+	var failed MultiError
+	for i, alarmType := range types {
+		apiErr := resp[i].ToAPIError()
+		if apiErr != nil {
+			if !IsNotSupported(apiErr) {
+				a.client.log(ctx).Warn("failed to get %s alarm configuration: %v", alarmType, apiErr)
+				failed.Errors = append(failed.Errors, apiErr)
+			}
+			continue
+		}
+		var value AlarmValue
+		if err := a.client.unmarshal(resp[i].Value, &value); err != nil {
+			return nil, fmt.Errorf("failed to parse %s alarm configuration: %w", alarmType, err)
+		}
+		if value.Alarm.Type == "" {
+			value.Alarm.Type = alarmType
+		}
+		alarms[alarmType] = &value.Alarm
+	}
+
+	a.client.log(ctx).Info("successfully retrieved %d alarm configurations", len(alarms))
+	if len(failed.Errors) > 0 {
+		return alarms, &failed
+	}
+	return alarms, nil
+}
+
 // GetAudioAlarm gets audio detection alarm configuration
 func (a *AlarmAPI) GetAudioAlarm(ctx context.Context, channel int) (*AudioAlarm, error) {
-	a.client.logger.Debug("getting audio alarm configuration: channel=%d", channel)
+	a.client.log(ctx).Debug("getting audio alarm configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetAudioAlarm",
@@ -354,35 +448,35 @@ func (a *AlarmAPI) GetAudioAlarm(ctx context.Context, channel int) (*AudioAlarm,
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to get audio alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to get audio alarm configuration: %v", err)
 		return nil, fmt.Errorf("GetAudioAlarm request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to get audio alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to get audio alarm configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to get audio alarm configuration: %v", apiErr)
+		a.client.log(ctx).Error("failed to get audio alarm configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value AudioAlarmValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		a.client.logger.Error("failed to parse audio alarm configuration response: %v", err)
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse audio alarm configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	a.client.logger.Info("successfully retrieved audio alarm configuration: enable=%d sensitivity=%d",
+	a.client.log(ctx).Info("successfully retrieved audio alarm configuration: enable=%d sensitivity=%d",
 		value.AudioAlarm.Enable, value.AudioAlarm.Sensitivity)
 	return &value.AudioAlarm, nil
 }
 
 // SetAudioAlarm sets audio detection alarm configuration
 func (a *AlarmAPI) SetAudioAlarm(ctx context.Context, audioAlarm AudioAlarm) error {
-	a.client.logger.Info("setting audio alarm configuration: channel=%d enable=%d sensitivity=%d",
+	a.client.log(ctx).Info("setting audio alarm configuration: channel=%d enable=%d sensitivity=%d",
 		audioAlarm.Channel, audioAlarm.Enable, audioAlarm.Sensitivity)
 
 	req := []Request{{
@@ -394,28 +488,28 @@ func (a *AlarmAPI) SetAudioAlarm(ctx context.Context, audioAlarm AudioAlarm) err
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to set audio alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to set audio alarm configuration: %v", err)
 		return fmt.Errorf("SetAudioAlarm request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to set audio alarm configuration: %v", err)
+		a.client.log(ctx).Error("failed to set audio alarm configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to set audio alarm configuration: %v", apiErr)
+		a.client.log(ctx).Error("failed to set audio alarm configuration: %v", apiErr)
 		return apiErr
 	}
 
-	a.client.logger.Info("successfully set audio alarm configuration")
+	a.client.log(ctx).Info("successfully set audio alarm configuration")
 	return nil
 }
 
 // GetAudioAlarmV20 gets audio detection alarm configuration (v2.0)
 func (a *AlarmAPI) GetAudioAlarmV20(ctx context.Context, channel int) (*AudioAlarm, error) {
-	a.client.logger.Debug("getting audio alarm configuration (v2.0): channel=%d", channel)
+	a.client.log(ctx).Debug("getting audio alarm configuration (v2.0): channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetAudioAlarmV20",
@@ -427,35 +521,35 @@ func (a *AlarmAPI) GetAudioAlarmV20(ctx context.Context, channel int) (*AudioAla
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to get audio alarm configuration (v2.0): %v", err)
+		a.client.log(ctx).Error("failed to get audio alarm configuration (v2.0): %v", err)
 		return nil, fmt.Errorf("GetAudioAlarmV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to get audio alarm configuration (v2.0): %v", err)
+		a.client.log(ctx).Error("failed to get audio alarm configuration (v2.0): %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to get audio alarm configuration (v2.0): %v", apiErr)
+		a.client.log(ctx).Error("failed to get audio alarm configuration (v2.0): %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value AudioAlarmValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		a.client.logger.Error("failed to parse audio alarm configuration (v2.0) response: %v", err)
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse audio alarm configuration (v2.0) response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	a.client.logger.Info("successfully retrieved audio alarm configuration (v2.0): enable=%d sensitivity=%d",
+	a.client.log(ctx).Info("successfully retrieved audio alarm configuration (v2.0): enable=%d sensitivity=%d",
 		value.AudioAlarm.Enable, value.AudioAlarm.Sensitivity)
 	return &value.AudioAlarm, nil
 }
 
 // SetAudioAlarmV20 sets audio detection alarm configuration (v2.0)
 func (a *AlarmAPI) SetAudioAlarmV20(ctx context.Context, audioAlarm AudioAlarm) error {
-	a.client.logger.Info("setting audio alarm configuration (v2.0): channel=%d enable=%d sensitivity=%d",
+	a.client.log(ctx).Info("setting audio alarm configuration (v2.0): channel=%d enable=%d sensitivity=%d",
 		audioAlarm.Channel, audioAlarm.Enable, audioAlarm.Sensitivity)
 
 	req := []Request{{
@@ -467,28 +561,28 @@ func (a *AlarmAPI) SetAudioAlarmV20(ctx context.Context, audioAlarm AudioAlarm)
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to set audio alarm configuration (v2.0): %v", err)
+		a.client.log(ctx).Error("failed to set audio alarm configuration (v2.0): %v", err)
 		return fmt.Errorf("SetAudioAlarmV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to set audio alarm configuration (v2.0): %v", err)
+		a.client.log(ctx).Error("failed to set audio alarm configuration (v2.0): %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to set audio alarm configuration (v2.0): %v", apiErr)
+		a.client.log(ctx).Error("failed to set audio alarm configuration (v2.0): %v", apiErr)
 		return apiErr
 	}
 
-	a.client.logger.Info("successfully set audio alarm configuration (v2.0)")
+	a.client.log(ctx).Info("successfully set audio alarm configuration (v2.0)")
 	return nil
 }
 
 // GetBuzzerAlarmV20 gets buzzer alarm configuration (v2.0)
 func (a *AlarmAPI) GetBuzzerAlarmV20(ctx context.Context, channel int) (*BuzzerAlarm, error) {
-	a.client.logger.Debug("getting buzzer alarm configuration (v2.0): channel=%d", channel)
+	a.client.log(ctx).Debug("getting buzzer alarm configuration (v2.0): channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetBuzzerAlarmV20",
@@ -500,35 +594,35 @@ func (a *AlarmAPI) GetBuzzerAlarmV20(ctx context.Context, channel int) (*BuzzerA
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to get buzzer alarm configuration (v2.0): %v", err)
+		a.client.log(ctx).Error("failed to get buzzer alarm configuration (v2.0): %v", err)
 		return nil, fmt.Errorf("GetBuzzerAlarmV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to get buzzer alarm configuration (v2.0): %v", err)
+		a.client.log(ctx).Error("failed to get buzzer alarm configuration (v2.0): %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to get buzzer alarm configuration (v2.0): %v", apiErr)
+		a.client.log(ctx).Error("failed to get buzzer alarm configuration (v2.0): %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value BuzzerAlarmValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		a.client.logger.Error("failed to parse buzzer alarm configuration (v2.0) response: %v", err)
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse buzzer alarm configuration (v2.0) response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	a.client.logger.Info("successfully retrieved buzzer alarm configuration (v2.0): enable=%d",
+	a.client.log(ctx).Info("successfully retrieved buzzer alarm configuration (v2.0): enable=%d",
 		value.BuzzerAlarm.Enable)
 	return &value.BuzzerAlarm, nil
 }
 
 // SetBuzzerAlarmV20 sets buzzer alarm configuration (v2.0)
 func (a *AlarmAPI) SetBuzzerAlarmV20(ctx context.Context, buzzerAlarm BuzzerAlarm) error {
-	a.client.logger.Info("setting buzzer alarm configuration (v2.0): channel=%d enable=%d",
+	a.client.log(ctx).Info("setting buzzer alarm configuration (v2.0): channel=%d enable=%d",
 		buzzerAlarm.Channel, buzzerAlarm.Enable)
 
 	req := []Request{{
@@ -540,21 +634,21 @@ func (a *AlarmAPI) SetBuzzerAlarmV20(ctx context.Context, buzzerAlarm BuzzerAlar
 
 	var resp []Response
 	if err := a.client.do(ctx, req, &resp); err != nil {
-		a.client.logger.Error("failed to set buzzer alarm configuration (v2.0): %v", err)
+		a.client.log(ctx).Error("failed to set buzzer alarm configuration (v2.0): %v", err)
 		return fmt.Errorf("SetBuzzerAlarmV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		a.client.logger.Error("failed to set buzzer alarm configuration (v2.0): %v", err)
+		a.client.log(ctx).Error("failed to set buzzer alarm configuration (v2.0): %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		a.client.logger.Error("failed to set buzzer alarm configuration (v2.0): %v", apiErr)
+		a.client.log(ctx).Error("failed to set buzzer alarm configuration (v2.0): %v", apiErr)
 		return apiErr
 	}
 
-	a.client.logger.Info("successfully set buzzer alarm configuration (v2.0)")
+	a.client.log(ctx).Info("successfully set buzzer alarm configuration (v2.0)")
 	return nil
 }
@@ -2,6 +2,7 @@ package reolink
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -676,3 +677,53 @@ func TestAlarmAPI_SetAudioAlarmV20(t *testing.T) {
 		t.Fatalf("SetAudioAlarmV20 failed: %v", err)
 	}
 }
+
+func TestAlarmAPI_GetAllAlarms(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			Cmd   string `json:"cmd"`
+			Param struct {
+				Alarm struct {
+					Type string `json:"type"`
+				} `json:"Alarm"`
+			} `json:"param"`
+		}
+		json.NewDecoder(r.Body).Decode(&reqs)
+
+		var resps []string
+		for _, req := range reqs {
+			switch req.Cmd {
+			case "GetAbility":
+				resps = append(resps, `{"cmd": "GetAbility", "code": 0, "value": {"Ability": {"abilityChn": [{"alarmMd": {"permit": 6, "ver": 1}, "supportAiPeople": {"permit": 6, "ver": 1}, "supportAiVehicle": {"permit": 6, "ver": 1}, "supportAiDogCat": {"permit": 0, "ver": 0}}]}}}`)
+			case "GetAlarm":
+				requested = append(requested, req.Param.Alarm.Type)
+				switch req.Param.Alarm.Type {
+				case AlarmTypeMD, AlarmTypePeople:
+					resps = append(resps, `{"cmd": "GetAlarm", "code": 0, "value": {"Alarm": {"channel": 0, "type": "`+req.Param.Alarm.Type+`", "enable": 1}}}`)
+				case AlarmTypeVehicle:
+					resps = append(resps, `{"cmd": "GetAlarm", "code": 1, "error": {"rspCode": -4, "detail": "param error"}}`)
+				default:
+					resps = append(resps, `{"cmd": "GetAlarm", "code": 1, "error": {"rspCode": -9, "detail": "not support"}}`)
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[" + strings.Join(resps, ",") + "]"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	alarms, err := client.Alarm.GetAllAlarms(t.Context(), 0)
+
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 1 {
+		t.Fatalf("expected a MultiError for the vehicle alarm, got %v", err)
+	}
+	if len(alarms) != 2 || alarms[AlarmTypeMD] == nil || alarms[AlarmTypePeople] == nil {
+		t.Errorf("unexpected alarms: %v", alarms)
+	}
+	if got := strings.Join(requested, ","); got != "md,people,vehicle,visitor,io" {
+		t.Errorf("unexpected alarm types requested: %s", got)
+	}
+}
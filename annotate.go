@@ -0,0 +1,216 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"strings"
+	"time"
+)
+
+// Annotation is the provenance text burned into a snapshot by
+// AnnotateSnapshot
+type Annotation struct {
+	Time   time.Time // Capture time, formatted as "2006-01-02 15:04:05 MST"
+	Camera string    // Camera or channel name
+	Labels []string  // Extra labels such as AI detection types
+}
+
+// Lines returns the text lines drawn for the annotation, skipping empty
+// fields
+func (a Annotation) Lines() []string {
+	var lines []string
+	if !a.Time.IsZero() {
+		lines = append(lines, a.Time.Format("2006-01-02 15:04:05 MST"))
+	}
+	if a.Camera != "" {
+		lines = append(lines, a.Camera)
+	}
+	if len(a.Labels) > 0 {
+		lines = append(lines, strings.Join(a.Labels, ", "))
+	}
+	return lines
+}
+
+// AnnotationPosition selects the image corner the annotation is drawn in
+type AnnotationPosition int
+
+// Annotation positions
+const (
+	AnnotateTopLeft AnnotationPosition = iota
+	AnnotateBottomLeft
+)
+
+// AnnotateOptions configures AnnotateSnapshot
+type AnnotateOptions struct {
+	// Position is the corner the text is drawn in (default: top left)
+	Position AnnotationPosition
+	// Scale is the size of a font pixel in image pixels (default: image
+	// width / 480, at least 1)
+	Scale int
+	// Quality is the JPEG quality of the output (default: 90)
+	Quality int
+}
+
+// annotateBackground is drawn behind the text to keep it legible on bright
+// scenes
+var annotateBackground = color.NRGBA{0, 0, 0, 160}
+
+// AnnotateSnapshot draws the annotation onto a JPEG snapshot and returns the
+// re-encoded JPEG. It is meant for firmware or streams where the camera's
+// own OSD is disabled, so exported images still carry their time and
+// source. Text is drawn with a built-in 5x7 bitmap font covering digits,
+// letters and common punctuation; lowercase letters are drawn as uppercase
+// and other characters as '?'.
+func AnnotateSnapshot(jpegData []byte, a Annotation, opts AnnotateOptions) ([]byte, error) {
+	src, err := jpeg.Decode(bytes.NewReader(jpegData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	bounds := src.Bounds()
+	img := image.NewRGBA(bounds)
+	draw.Draw(img, bounds, src, bounds.Min, draw.Src)
+
+	scale := opts.Scale
+	if scale <= 0 {
+		scale = max(bounds.Dx()/480, 1)
+	}
+	quality := opts.Quality
+	if quality <= 0 {
+		quality = 90
+	}
+
+	lines := a.Lines()
+	lineHeight := (glyphHeight + 2) * scale
+	pad := 2 * scale
+	y := bounds.Min.Y + pad
+	if opts.Position == AnnotateBottomLeft {
+		y = bounds.Max.Y - pad - len(lines)*lineHeight
+	}
+	for _, line := range lines {
+		drawText(img, bounds.Min.X+pad, y, line, scale)
+		y += lineHeight
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// SnapAnnotated captures a snapshot and burns in the capture time, the
+// channel's OSD name (if it can be read) and labels
+func (e *EncodingAPI) SnapAnnotated(ctx context.Context, channel int, opts AnnotateOptions, labels ...string) ([]byte, error) {
+	image, err := e.Snap(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	a := Annotation{Time: time.Now(), Labels: labels}
+
+	if osd, err := e.client.Video.GetOsd(ctx, channel); err != nil {
+		e.client.log(ctx).Warn("failed to read channel name for annotation: %v", err)
+	} else {
+		a.Camera = osd.OsdChannel.Name
+	}
+
+	return AnnotateSnapshot(image, a, opts)
+}
+
+// drawText draws a line of text with its top-left corner at (x, y) on a
+// translucent background
+func drawText(img draw.Image, x, y int, text string, scale int) {
+	runes := []rune(strings.ToUpper(text))
+	if len(runes) == 0 {
+		return
+	}
+
+	width := len(runes)*(glyphWidth+1)*scale - scale
+	box := image.Rect(x-scale, y-scale, x+width+scale, y+glyphHeight*scale+scale)
+	draw.Draw(img, box, image.NewUniform(annotateBackground), image.Point{}, draw.Over)
+
+	white := image.NewUniform(color.White)
+	for i, r := range runes {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = glyphs['?']
+		}
+		gx := x + i*(glyphWidth+1)*scale
+		for row, bits := range glyph {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				px := image.Rect(gx+col*scale, y+row*scale, gx+(col+1)*scale, y+(row+1)*scale)
+				draw.Draw(img, px, white, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
+
+// Bitmap font dimensions
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyphs is a 5x7 bitmap font; each row's low five bits are the pixels
+// from left to right
+var glyphs = map[rune][glyphHeight]byte{
+	' ':  {},
+	'0':  {0x0e, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0e},
+	'1':  {0x04, 0x0c, 0x04, 0x04, 0x04, 0x04, 0x0e},
+	'2':  {0x0e, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1f},
+	'3':  {0x1f, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0e},
+	'4':  {0x02, 0x06, 0x0a, 0x12, 0x1f, 0x02, 0x02},
+	'5':  {0x1f, 0x10, 0x1e, 0x01, 0x01, 0x11, 0x0e},
+	'6':  {0x06, 0x08, 0x10, 0x1e, 0x11, 0x11, 0x0e},
+	'7':  {0x1f, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8':  {0x0e, 0x11, 0x11, 0x0e, 0x11, 0x11, 0x0e},
+	'9':  {0x0e, 0x11, 0x11, 0x0f, 0x01, 0x02, 0x0c},
+	'A':  {0x0e, 0x11, 0x11, 0x1f, 0x11, 0x11, 0x11},
+	'B':  {0x1e, 0x11, 0x11, 0x1e, 0x11, 0x11, 0x1e},
+	'C':  {0x0e, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0e},
+	'D':  {0x1c, 0x12, 0x11, 0x11, 0x11, 0x12, 0x1c},
+	'E':  {0x1f, 0x10, 0x10, 0x1e, 0x10, 0x10, 0x1f},
+	'F':  {0x1f, 0x10, 0x10, 0x1e, 0x10, 0x10, 0x10},
+	'G':  {0x0e, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0f},
+	'H':  {0x11, 0x11, 0x11, 0x1f, 0x11, 0x11, 0x11},
+	'I':  {0x0e, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0e},
+	'J':  {0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0c},
+	'K':  {0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11},
+	'L':  {0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1f},
+	'M':  {0x11, 0x1b, 0x15, 0x15, 0x11, 0x11, 0x11},
+	'N':  {0x11, 0x11, 0x19, 0x15, 0x13, 0x11, 0x11},
+	'O':  {0x0e, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0e},
+	'P':  {0x1e, 0x11, 0x11, 0x1e, 0x10, 0x10, 0x10},
+	'Q':  {0x0e, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0d},
+	'R':  {0x1e, 0x11, 0x11, 0x1e, 0x14, 0x12, 0x11},
+	'S':  {0x0f, 0x10, 0x10, 0x0e, 0x01, 0x01, 0x1e},
+	'T':  {0x1f, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04},
+	'U':  {0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0e},
+	'V':  {0x11, 0x11, 0x11, 0x11, 0x11, 0x0a, 0x04},
+	'W':  {0x11, 0x11, 0x11, 0x15, 0x15, 0x15, 0x0a},
+	'X':  {0x11, 0x11, 0x0a, 0x04, 0x0a, 0x11, 0x11},
+	'Y':  {0x11, 0x11, 0x11, 0x0a, 0x04, 0x04, 0x04},
+	'Z':  {0x1f, 0x01, 0x02, 0x04, 0x08, 0x10, 0x1f},
+	':':  {0x00, 0x0c, 0x0c, 0x00, 0x0c, 0x0c, 0x00},
+	'-':  {0x00, 0x00, 0x00, 0x1f, 0x00, 0x00, 0x00},
+	'.':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x0c},
+	',':  {0x00, 0x00, 0x00, 0x00, 0x0c, 0x04, 0x08},
+	'/':  {0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00},
+	'_':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1f},
+	'(':  {0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02},
+	')':  {0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08},
+	'+':  {0x00, 0x04, 0x04, 0x1f, 0x04, 0x04, 0x00},
+	'=':  {0x00, 0x00, 0x1f, 0x00, 0x1f, 0x00, 0x00},
+	'%':  {0x18, 0x19, 0x02, 0x04, 0x08, 0x13, 0x03},
+	'#':  {0x0a, 0x0a, 0x1f, 0x0a, 0x1f, 0x0a, 0x0a},
+	'\'': {0x0c, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00},
+	'?':  {0x0e, 0x11, 0x01, 0x02, 0x04, 0x00, 0x04},
+}
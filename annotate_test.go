@@ -0,0 +1,100 @@
+package reolink
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"testing"
+	"time"
+)
+
+// grayJPEG encodes a uniform mid-gray JPEG of the given size
+func grayJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{128}), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func luma(c color.Color) uint32 {
+	r, g, b, _ := c.RGBA()
+	return (r + g + b) / 3 >> 8
+}
+
+func TestAnnotation_Lines(t *testing.T) {
+	a := Annotation{
+		Time:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Camera: "Front Door",
+		Labels: []string{AITypePeople, AITypeVehicle},
+	}
+	lines := a.Lines()
+	want := []string{"2025-01-02 03:04:05 UTC", "Front Door", "people, vehicle"}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %v", len(want), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d: expected %q, got %q", i, want[i], lines[i])
+		}
+	}
+	if len((Annotation{}).Lines()) != 0 {
+		t.Error("expected no lines for an empty annotation")
+	}
+}
+
+func TestDrawText(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{128}), image.Point{}, draw.Src)
+
+	drawText(img, 2, 2, "1", 2)
+
+	// '1' has its top-row pixel in column 2 and the full bottom row set
+	if luma(img.At(2+2*2, 2)) != 255 {
+		t.Error("expected white pixel at the top of the glyph")
+	}
+	if luma(img.At(2+1*2, 2+6*2)) != 255 {
+		t.Error("expected white pixel on the bottom row of the glyph")
+	}
+	if l := luma(img.At(2, 2)); l >= 128 || l == 0 {
+		t.Errorf("expected darkened background behind text, got luma %d", l)
+	}
+	if luma(img.At(39, 19)) != 128 {
+		t.Error("expected pixels outside the text box to be unchanged")
+	}
+}
+
+func TestAnnotateSnapshot(t *testing.T) {
+	src := grayJPEG(t, 320, 180)
+
+	out, err := AnnotateSnapshot(src, Annotation{Camera: "CAM 1"}, AnnotateOptions{Scale: 2, Position: AnnotateBottomLeft})
+	if err != nil {
+		t.Fatalf("AnnotateSnapshot failed: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("annotated snapshot is not a JPEG: %v", err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 320, 180) {
+		t.Errorf("expected size to be kept, got %v", img.Bounds())
+	}
+
+	// The box sits in the bottom-left corner: darker there, untouched at top
+	if luma(img.At(3, 160)) >= 110 {
+		t.Error("expected annotation background in the bottom-left corner")
+	}
+	if l := luma(img.At(5, 5)); l < 120 || l > 136 {
+		t.Errorf("expected top-left corner unchanged, got luma %d", l)
+	}
+}
+
+func TestAnnotateSnapshot_InvalidJPEG(t *testing.T) {
+	if _, err := AnnotateSnapshot(testJPEG, Annotation{Camera: "x"}, AnnotateOptions{}); err == nil {
+		t.Error("expected error for an undecodable image")
+	}
+}
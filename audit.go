@@ -0,0 +1,307 @@
+package reolink
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Severity ranks the impact of an audit finding
+type Severity int
+
+const (
+	// SeverityInfo is informational only
+	SeverityInfo Severity = iota
+	// SeverityLow is a minor hardening opportunity
+	SeverityLow
+	// SeverityMedium should be addressed
+	SeverityMedium
+	// SeverityHigh should be addressed immediately
+	SeverityHigh
+)
+
+// String returns the string representation of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// AuditFinding is a single issue found by Audit
+type AuditFinding struct {
+	ID             string   // Stable identifier, e.g. "weak-admin-password"
+	Severity       Severity // Impact of the finding
+	Title          string   // Short description
+	Recommendation string   // How to fix it
+}
+
+// AuditReport is the structured result of a security posture audit
+type AuditReport struct {
+	Host     string         // Audited camera
+	Time     time.Time      // When the audit ran
+	Findings []AuditFinding // Issues found, in check order
+	Errors   []string       // Checks that could not run (e.g. unsupported commands)
+}
+
+// HighestSeverity returns the most severe finding, or SeverityInfo if none
+func (r *AuditReport) HighestSeverity() Severity {
+	highest := SeverityInfo
+	for _, f := range r.Findings {
+		if f.Severity > highest {
+			highest = f.Severity
+		}
+	}
+	return highest
+}
+
+// defaultPasswords are factory or commonly used passwords flagged by Audit
+var defaultPasswords = map[string]bool{
+	"":         true,
+	"admin":    true,
+	"123456":   true,
+	"12345678": true,
+	"password": true,
+	"reolink":  true,
+}
+
+// certExpiryWarning is how far ahead of expiry a certificate is flagged
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// Audit inspects the camera's security posture: user accounts (including a
+// default-password heuristic for the client's own credentials), exposed
+// services, P2P/UPnP state, available firmware updates, login lock settings
+// and the HTTPS certificate. Checks that fail (for example because the
+// firmware does not support a command) are recorded in AuditReport.Errors
+// rather than aborting the audit.
+func (s *SecurityAPI) Audit(ctx context.Context) (*AuditReport, error) {
+	s.client.log(ctx).Info("auditing security posture of camera at %s", s.client.Host())
+
+	report := &AuditReport{
+		Host: s.client.Host(),
+		Time: time.Now(),
+	}
+	add := func(f AuditFinding) {
+		report.Findings = append(report.Findings, f)
+	}
+	checkErr := func(check string, err error) {
+		report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", check, err))
+	}
+
+	// Users
+	if s.client.username == "admin" && defaultPasswords[s.client.password] {
+		add(AuditFinding{
+			ID:             "weak-admin-password",
+			Severity:       SeverityHigh,
+			Title:          "admin account uses a default or trivial password",
+			Recommendation: "set a strong admin password with ModifyUser",
+		})
+	}
+	if users, err := s.GetUsers(ctx); err != nil {
+		checkErr("users", err)
+	} else {
+		admins := 0
+		for _, u := range users {
+			if u.Level == "admin" {
+				admins++
+			}
+		}
+		if admins > 1 {
+			add(AuditFinding{
+				ID:             "multiple-admins",
+				Severity:       SeverityLow,
+				Title:          fmt.Sprintf("%d accounts have admin level", admins),
+				Recommendation: "downgrade accounts that do not need administration to guest",
+			})
+		}
+	}
+
+	// Exposed services
+	httpsEnabled := false
+	httpsPort := 443
+	if netPort, err := s.client.Network.GetNetPort(ctx); err != nil {
+		checkErr("ports", err)
+	} else {
+		httpsEnabled = netPort.HTTPSEnable.Bool()
+		httpsPort = netPort.HTTPSPort
+		if netPort.HTTPEnable.Bool() {
+			add(AuditFinding{
+				ID:             "http-enabled",
+				Severity:       SeverityMedium,
+				Title:          fmt.Sprintf("plain HTTP is enabled on port %d; credentials and tokens travel unencrypted", netPort.HTTPPort),
+				Recommendation: "enable HTTPS and disable HTTP with SetNetPort",
+			})
+		}
+		if netPort.RTMPEnable.Bool() {
+			add(AuditFinding{
+				ID:             "rtmp-enabled",
+				Severity:       SeverityMedium,
+				Title:          fmt.Sprintf("RTMP is enabled on port %d; RTMP URLs carry credentials in the query string", netPort.RTMPPort),
+				Recommendation: "disable RTMP unless it is in use",
+			})
+		}
+		if netPort.OnvifEnable.Bool() {
+			add(AuditFinding{
+				ID:             "onvif-enabled",
+				Severity:       SeverityLow,
+				Title:          fmt.Sprintf("ONVIF is enabled on port %d", netPort.OnvifPort),
+				Recommendation: "disable ONVIF unless an NVR or VMS relies on it",
+			})
+		}
+		if !httpsEnabled {
+			add(AuditFinding{
+				ID:             "https-disabled",
+				Severity:       SeverityMedium,
+				Title:          "HTTPS is disabled",
+				Recommendation: "enable HTTPS with SetNetPort",
+			})
+		}
+	}
+
+	// Remote access
+	if p2p, err := s.client.Network.GetP2p(ctx); err != nil {
+		checkErr("p2p", err)
+	} else if p2p.Enable.Bool() {
+		add(AuditFinding{
+			ID:             "p2p-enabled",
+			Severity:       SeverityLow,
+			Title:          "P2P cloud relay is enabled",
+			Recommendation: "disable P2P for LAN-only deployments (see HardenLANOnly)",
+		})
+	}
+	if upnp, err := s.client.Network.GetUpnp(ctx); err != nil {
+		checkErr("upnp", err)
+	} else if upnp.Enable.Bool() {
+		add(AuditFinding{
+			ID:             "upnp-enabled",
+			Severity:       SeverityMedium,
+			Title:          "UPnP is enabled; the camera may open router ports automatically",
+			Recommendation: "disable UPnP with SetUpnp",
+		})
+	}
+
+	// Firmware
+	if fw, err := s.client.System.CheckFirmware(ctx); err != nil {
+		checkErr("firmware", err)
+	} else if fw.NewFirmware == 1 {
+		add(AuditFinding{
+			ID:             "firmware-outdated",
+			Severity:       SeverityMedium,
+			Title:          "newer firmware is available",
+			Recommendation: "upgrade firmware with UpgradeOnline",
+		})
+	}
+
+	// Login lock
+	if sysCfg, err := s.client.System.GetSysCfg(ctx); err != nil {
+		checkErr("login lock", err)
+	} else if sysCfg.LoginLock == 0 {
+		add(AuditFinding{
+			ID:             "login-lock-disabled",
+			Severity:       SeverityMedium,
+			Title:          "login lock is disabled; passwords can be brute-forced",
+			Recommendation: "enable login lock with SetSysCfg",
+		})
+	}
+
+	// Certificate
+	if httpsEnabled {
+		if cert, err := s.GetCertificateInfo(ctx); err != nil {
+			checkErr("certificate", err)
+		} else if !cert.Enable.Bool() {
+			add(AuditFinding{
+				ID:             "default-certificate",
+				Severity:       SeverityLow,
+				Title:          "HTTPS uses the factory self-signed certificate",
+				Recommendation: "install a certificate issued by a trusted CA",
+			})
+		}
+		if s.client.Host() != "" {
+			findings, err := s.client.auditCertificateExpiry(ctx, httpsPort)
+			if err != nil {
+				checkErr("certificate expiry", err)
+			}
+			report.Findings = append(report.Findings, findings...)
+		}
+	}
+
+	s.client.log(ctx).Info("security audit complete: findings=%d highest=%s errors=%d",
+		len(report.Findings), report.HighestSeverity(), len(report.Errors))
+	return report, nil
+}
+
+// auditCertificateExpiry connects to the camera's HTTPS port and checks the
+// validity window of the presented certificate. It connects through a copy
+// of the client's transport, so a P2P dialer or proxy applies as for API
+// requests; a client already using HTTPS keeps its host and port, which may
+// be forwarded, instead of the camera's configured port.
+func (c *Client) auditCertificateExpiry(ctx context.Context, port int) ([]AuditFinding, error) {
+	host, https := c.endpoint()
+	if !https {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = net.JoinHostPort(host, strconv.Itoa(port))
+	}
+
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("cannot inspect the certificate through a %T, only through an *http.Transport", base)
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = true // Expired and self-signed certificates are what is checked
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://"+host+"/", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("no certificate presented")
+	}
+	certs := resp.TLS.PeerCertificates
+
+	notAfter := certs[0].NotAfter
+	switch {
+	case time.Now().After(notAfter):
+		return []AuditFinding{{
+			ID:             "certificate-expired",
+			Severity:       SeverityHigh,
+			Title:          fmt.Sprintf("HTTPS certificate expired on %s", notAfter.Format(time.DateOnly)),
+			Recommendation: "install a new certificate",
+		}}, nil
+	case time.Until(notAfter) < certExpiryWarning:
+		return []AuditFinding{{
+			ID:             "certificate-expiring",
+			Severity:       SeverityMedium,
+			Title:          fmt.Sprintf("HTTPS certificate expires on %s", notAfter.Format(time.DateOnly)),
+			Recommendation: "renew the certificate before it expires",
+		}}, nil
+	}
+	return nil, nil
+}
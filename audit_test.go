@@ -0,0 +1,123 @@
+package reolink
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestSecurityAPI_Audit(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetUser":            `{"User": [{"userName": "admin", "level": "admin"}, {"userName": "ops", "level": "admin"}]}`,
+		"GetNetPort":         `{"NetPort": {"httpEnable": 1, "httpPort": 80, "httpsEnable": 1, "httpsPort": 443, "rtmpEnable": 1, "rtmpPort": 1935, "onvifEnable": 0}}`,
+		"GetP2p":             `{"P2p": {"enable": 0}}`,
+		"GetUpnp":            `{"Upnp": {"enable": 1}}`,
+		"CheckFirmware":      `{"newFirmware": 1}`,
+		"GetCertificateInfo": `{"CertificateInfo": {"enable": 0}}`,
+		"GetSysCfg":          `{"SysCfg": {"LockTime": 300, "allowedTimes": 5, "loginLock": 0}}`,
+	})
+	client := server.client()
+	client.username = "admin"
+	client.password = "admin"
+
+	report, err := client.Security.Audit(t.Context())
+	if err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+
+	want := map[string]Severity{
+		"weak-admin-password": SeverityHigh,
+		"multiple-admins":     SeverityLow,
+		"http-enabled":        SeverityMedium,
+		"rtmp-enabled":        SeverityMedium,
+		"upnp-enabled":        SeverityMedium,
+		"firmware-outdated":   SeverityMedium,
+		"default-certificate": SeverityLow,
+		"login-lock-disabled": SeverityMedium,
+	}
+	if len(report.Findings) != len(want) {
+		t.Errorf("expected %d findings, got %d: %+v", len(want), len(report.Findings), report.Findings)
+	}
+	for _, f := range report.Findings {
+		sev, ok := want[f.ID]
+		if !ok {
+			t.Errorf("unexpected finding %s", f.ID)
+			continue
+		}
+		if f.Severity != sev {
+			t.Errorf("finding %s: expected severity %s, got %s", f.ID, sev, f.Severity)
+		}
+	}
+	if report.HighestSeverity() != SeverityHigh {
+		t.Errorf("expected highest severity high, got %s", report.HighestSeverity())
+	}
+	if len(report.Errors) != 0 {
+		t.Errorf("unexpected errors: %v", report.Errors)
+	}
+}
+
+func TestSecurityAPI_Audit_UnsupportedChecks(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetUser":    `{"User": [{"userName": "admin", "level": "admin"}]}`,
+		"GetNetPort": `{"NetPort": {"httpEnable": 0, "httpsEnable": 1, "httpsPort": 443}}`,
+	})
+	client := server.client()
+	client.username = "admin"
+	client.password = "S3cure!Passw0rd"
+
+	report, err := client.Security.Audit(t.Context())
+	if err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+
+	if len(report.Findings) != 0 {
+		t.Errorf("expected no findings, got %+v", report.Findings)
+	}
+	// p2p, upnp, firmware, login lock, certificate
+	if len(report.Errors) != 5 {
+		t.Errorf("expected 5 check errors, got %v", report.Errors)
+	}
+}
+
+func TestAuditCertificateExpiry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	client := NewClient(server.Listener.Addr().String())
+	findings, err := client.auditCertificateExpiry(t.Context(), p)
+	if err != nil {
+		t.Fatalf("auditCertificateExpiry failed: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected valid certificate, got %+v", findings)
+	}
+
+	// Through a P2P dialer, the camera's port is never dialed directly
+	dials := 0
+	client = NewClient("", WithP2PDialer("UID123", P2PDialerFunc(func(ctx context.Context, uid string) (net.Conn, error) {
+		dials++
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", server.Listener.Addr().String())
+	})))
+	if _, err := client.auditCertificateExpiry(t.Context(), 443); err != nil {
+		t.Fatalf("auditCertificateExpiry through the P2P dialer failed: %v", err)
+	}
+	if dials != 1 {
+		t.Errorf("expected 1 dial through the P2P dialer, got %d", dials)
+	}
+}
+
+func TestSeverity_String(t *testing.T) {
+	if SeverityHigh.String() != "high" {
+		t.Errorf("expected 'high', got %q", SeverityHigh.String())
+	}
+	if Severity(42).String() != "unknown(42)" {
+		t.Errorf("unexpected string for unknown severity: %q", Severity(42).String())
+	}
+}
@@ -0,0 +1,60 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+)
+
+// Batch sends requests to the camera in a single round trip and returns
+// their responses in request order.
+//
+// A camera answers each command of a batch on its own, so one unsupported
+// command does not spoil the others: when some commands fail, Batch returns
+// all responses together with a *MultiError holding an *APIError per failed
+// command. A command the camera leaves out of its answer is reported as
+// not supported. Transport failures return a nil slice and the error.
+func (c *Client) Batch(ctx context.Context, requests []Request) ([]Response, error) {
+	c.log(ctx).Debug("sending batch of %d commands", len(requests))
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	var resp []Response
+	if err := c.do(ctx, requests, &resp); err != nil {
+		c.log(ctx).Error("failed to send batch: %v", err)
+		return nil, fmt.Errorf("batch request failed: %w", err)
+	}
+
+	// Match responses to requests by command, in order, so a response
+	// missing from the middle of the batch does not shift the rest
+	results := make([]Response, len(requests))
+	used := make([]bool, len(resp))
+	var multi MultiError
+	for i, req := range requests {
+		found := false
+		for j := range resp {
+			if !used[j] && resp[j].Cmd == req.Cmd {
+				used[j] = true
+				results[i] = resp[j]
+				found = true
+				break
+			}
+		}
+		if !found {
+			results[i] = Response{
+				Cmd:   req.Cmd,
+				Code:  1,
+				Error: &ErrorDetail{RspCode: ErrCodeNotSupported, Detail: "no response in batch"},
+			}
+		}
+		if apiErr := results[i].ToAPIError(); apiErr != nil {
+			multi.Errors = append(multi.Errors, apiErr)
+		}
+	}
+
+	if len(multi.Errors) > 0 {
+		c.log(ctx).Warn("%d of %d batched commands failed: %v", len(multi.Errors), len(requests), &multi)
+		return results, &multi
+	}
+	return results, nil
+}
@@ -0,0 +1,71 @@
+package reolink
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Batch(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"httpPort": 80}}`,
+		"GetNtp":     `{"Ntp": {"enable": 1}}`,
+	})
+	client := server.client()
+
+	resp, err := client.Batch(t.Context(), []Request{
+		{Cmd: "GetNetPort"},
+		{Cmd: "GetRtspAuth"},
+		{Cmd: "GetNtp"},
+	})
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected *MultiError, got %v", err)
+	}
+	if len(resp) != 3 || resp[0].ToAPIError() != nil || resp[2].ToAPIError() != nil {
+		t.Fatalf("unexpected responses: %+v", resp)
+	}
+	if len(multi.Errors) != 1 || multi.Err("GetRtspAuth") == nil || multi.Err("GetNtp") != nil {
+		t.Errorf("unexpected errors: %v", multi)
+	}
+	if !IsNotSupported(err) {
+		t.Error("expected IsNotSupported to see through the MultiError")
+	}
+
+	resp, err = client.Batch(t.Context(), []Request{{Cmd: "GetNtp"}})
+	if err != nil || len(resp) != 1 {
+		t.Errorf("Batch = %v, %v", resp, err)
+	}
+}
+
+func TestClient_Batch_MissingResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"cmd": "GetNtp", "code": 0, "value": {"Ntp": {"enable": 1}}}]`))
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	resp, err := client.Batch(t.Context(), []Request{{Cmd: "GetPerformance"}, {Cmd: "GetNtp"}})
+	var multi *MultiError
+	if !errors.As(err, &multi) || multi.Err("GetPerformance") == nil {
+		t.Fatalf("expected GetPerformance to be reported, got %v", err)
+	}
+	if resp[1].Cmd != "GetNtp" || resp[1].ToAPIError() != nil {
+		t.Errorf("GetNtp response shifted: %+v", resp)
+	}
+}
+
+func TestClient_Batch_TransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	resp, err := client.Batch(t.Context(), []Request{{Cmd: "GetNtp"}})
+	var multi *MultiError
+	if err == nil || errors.As(err, &multi) || resp != nil {
+		t.Errorf("expected a plain transport error, got %v, %v", resp, err)
+	}
+}
@@ -0,0 +1,224 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ExportJob is one recording for BulkExport
+type ExportJob struct {
+	Client *Client // Camera holding the recording
+	Camera string  // Name for per-camera limits and reports (default: the client's host)
+	Source string  // Recording file name, e.g. SearchResult.FileName
+	Key    string  // Object key (default: the file name)
+	Size   int64   // Expected size, e.g. SearchResult.FileSize; 0 if unknown
+}
+
+// ExportJobs returns a job for every search result of client, keyed
+// "<camera>/<file name>"
+func ExportJobs(client *Client, camera string, results []SearchResult) []ExportJob {
+	if camera == "" {
+		camera = client.Host()
+	}
+	jobs := make([]ExportJob, len(results))
+	for i, res := range results {
+		jobs[i] = ExportJob{
+			Client: client,
+			Camera: camera,
+			Source: res.FileName,
+			Key:    camera + "/" + path.Base(res.FileName),
+			Size:   res.FileSize,
+		}
+	}
+	return jobs
+}
+
+// BulkExportOptions configures BulkExport
+type BulkExportOptions struct {
+	// Concurrency is how many recordings are exported at once (default: 4)
+	Concurrency int
+	// PerCamera is how many recordings are downloaded from one camera at
+	// once; cameras serve downloads slowly and drop live streams when
+	// overloaded (default: 2)
+	PerCamera int
+	// BytesPerSecond limits the combined download rate; 0 means unlimited
+	BytesPerSecond int64
+	// Export configures each export; its Key and Size are replaced by the
+	// job's
+	Export ExportOptions
+}
+
+// BulkExportResult is the outcome of one job
+type BulkExportResult struct {
+	Index  int // Position of the job in the jobs passed to BulkExport
+	Job    ExportJob
+	Result *ExportResult // nil if Err is set
+	Err    error
+}
+
+// BulkExport exports recordings, possibly from several cameras, to bucket
+// with bounded parallelism. report, if not nil, is called once per job in
+// the order of jobs, as soon as the job and all before it are done, so
+// progress can be logged or checkpointed in a stable order; it is never
+// called concurrently.
+//
+// BulkExport returns an error if any job failed, after all jobs finished,
+// or ctx.Err() if ctx is done first.
+func BulkExport(ctx context.Context, jobs []ExportJob, store ObjectStore, bucket string, opts BulkExportOptions, report func(BulkExportResult)) error {
+	if store == nil {
+		return fmt.Errorf("store must not be nil")
+	}
+	jobs = slices.Clone(jobs)
+	for i, job := range jobs {
+		if job.Client == nil {
+			return fmt.Errorf("job %d: client must not be nil", i)
+		}
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.PerCamera <= 0 {
+		opts.PerCamera = 2
+	}
+	if opts.BytesPerSecond > 0 {
+		opts.Export.limiter = &bandwidthLimiter{rate: float64(opts.BytesPerSecond)}
+	}
+
+	global := make(chan struct{}, opts.Concurrency)
+	cameras := make(map[string]chan struct{})
+	for i := range jobs {
+		if jobs[i].Camera == "" {
+			jobs[i].Camera = jobs[i].Client.Host()
+		}
+		if cameras[jobs[i].Camera] == nil {
+			cameras[jobs[i].Camera] = make(chan struct{}, opts.PerCamera)
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		results = make(map[int]BulkExportResult)
+		next    int
+		failed  int
+		wg      sync.WaitGroup
+	)
+	// finish records a result and reports every result that is now next
+	finish := func(res BulkExportResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		if res.Err != nil {
+			failed++
+		}
+		results[res.Index] = res
+		for {
+			r, ok := results[next]
+			if !ok {
+				return
+			}
+			delete(results, next)
+			next++
+			if report != nil {
+				report(r)
+			}
+		}
+	}
+
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := BulkExportResult{Index: i, Job: job}
+			camera := cameras[job.Camera]
+			if err := acquire(ctx, camera); err != nil {
+				res.Err = err
+				finish(res)
+				return
+			}
+			defer func() { <-camera }()
+			if err := acquire(ctx, global); err != nil {
+				res.Err = err
+				finish(res)
+				return
+			}
+			defer func() { <-global }()
+
+			exportOpts := opts.Export
+			exportOpts.Key = job.Key
+			exportOpts.Size = job.Size
+			res.Result, res.Err = job.Client.Recording.ExportToObjectStore(ctx, job.Source, store, bucket, exportOpts)
+			finish(res)
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d exports failed", failed, len(jobs))
+	}
+	return nil
+}
+
+// acquire takes a slot of sem unless ctx is done first
+func acquire(ctx context.Context, sem chan struct{}) error {
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// bandwidthLimiter paces reads so that all readers sharing it together
+// stay below rate bytes per second
+type bandwidthLimiter struct {
+	rate float64
+
+	mu   sync.Mutex
+	next time.Time // When the bytes read so far have been paid for
+}
+
+// wait blocks until n more bytes fit into the rate
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+	return sleepCtx(ctx, delay)
+}
+
+// limitedReader reads through a bandwidthLimiter
+type limitedReader struct {
+	ctx     context.Context
+	r       io.ReadCloser
+	limiter *bandwidthLimiter
+}
+
+// Read implements io.Reader, in chunks small enough to keep the rate smooth
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > 32<<10 {
+		p = p[:32<<10]
+	}
+	n, err := l.r.Read(p)
+	if n > 0 {
+		if werr := l.limiter.wait(l.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (l *limitedReader) Close() error {
+	return l.r.Close()
+}
@@ -0,0 +1,102 @@
+package reolink
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyServer serves recordings and records the most downloads it
+// served at once
+type concurrencyServer struct {
+	*httptest.Server
+	active, peak atomic.Int32
+}
+
+func newConcurrencyServer(t *testing.T, size int) *concurrencyServer {
+	t.Helper()
+	s := &concurrencyServer{}
+	recording := bytes.Repeat([]byte{0x42}, size)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.active.Add(1)
+		defer s.active.Add(-1)
+		for {
+			peak := s.peak.Load()
+			if n <= peak || s.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		if strings.Contains(r.URL.RawQuery, "source=fail") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write(recording)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestBulkExport(t *testing.T) {
+	front, back := newConcurrencyServer(t, 1024), newConcurrencyServer(t, 1024)
+	frontClient, backClient := newTestClient(front.Server), newTestClient(back.Server)
+
+	var jobs []ExportJob
+	for i := range 6 {
+		jobs = append(jobs,
+			ExportJob{Client: frontClient, Camera: "front", Source: "Mp4Record/front" + string(rune('a'+i)) + ".mp4"},
+			ExportJob{Client: backClient, Camera: "back", Source: "Mp4Record/back" + string(rune('a'+i)) + ".mp4"})
+	}
+	jobs[5].Source = "fail"
+
+	store := newMemoryStore()
+	var reported []int
+	err := BulkExport(t.Context(), jobs, plainStore{store}, "evidence",
+		BulkExportOptions{Concurrency: 3, PerCamera: 2, Export: ExportOptions{Retries: 1, RetryDelay: time.Millisecond}},
+		func(res BulkExportResult) {
+			reported = append(reported, res.Index)
+			if (res.Err != nil) != (res.Index == 5) {
+				t.Errorf("job %d: unexpected error %v", res.Index, res.Err)
+			}
+		})
+	if err == nil || !strings.Contains(err.Error(), "1 of 12") {
+		t.Errorf("expected one failed export, got %v", err)
+	}
+	for i, idx := range reported {
+		if idx != i {
+			t.Fatalf("results reported out of order: %v", reported)
+		}
+	}
+	if len(reported) != len(jobs) || len(store.objects) != 11 {
+		t.Errorf("reported %d results and stored %d objects", len(reported), len(store.objects))
+	}
+	if front.peak.Load() > 2 || back.peak.Load() > 2 {
+		t.Errorf("per-camera limit exceeded: front=%d back=%d", front.peak.Load(), back.peak.Load())
+	}
+}
+
+func TestBulkExport_BandwidthLimit(t *testing.T) {
+	camera := newConcurrencyServer(t, 64<<10)
+	client := newTestClient(camera.Server)
+	jobs := ExportJobs(client, "cam", []SearchResult{{FileName: "a.mp4"}, {FileName: "b.mp4"}, {FileName: "c.mp4"}})
+	if jobs[0].Key != "cam/a.mp4" {
+		t.Errorf("unexpected key %q", jobs[0].Key)
+	}
+
+	start := time.Now()
+	store := newMemoryStore()
+	if err := BulkExport(t.Context(), jobs, plainStore{store}, "evidence", BulkExportOptions{BytesPerSecond: 1 << 20}, nil); err != nil {
+		t.Fatalf("BulkExport failed: %v", err)
+	}
+	// 192 KiB at 1 MiB/s take at least 187ms
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("bandwidth limit not applied: took %s", elapsed)
+	}
+	if len(store.objects["evidence/cam/b.mp4"]) != 64<<10 {
+		t.Error("export incomplete")
+	}
+}
@@ -0,0 +1,86 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// buzzerStopTimeout bounds the request that silences the buzzer after
+// TriggerBuzzer's context is done
+const buzzerStopTimeout = 10 * time.Second
+
+// TriggerBuzzer sounds the device buzzer for the given number of seconds,
+// e.g. for installer walk tests, and blocks until it is silenced again. If
+// ctx is done first the buzzer is silenced early and ctx.Err() is returned.
+//
+// The buzzer is switched with BuzzerAlarmPlay, the buzzer counterpart of
+// AudioAlarmPlay, which is not part of the public API guide. Devices whose
+// abilities report no buzzer (supportBuzzer ver 0) are rejected with a not
+// supported *APIError without sending it.
+func (a *AlarmAPI) TriggerBuzzer(ctx context.Context, seconds int) error {
+	if seconds <= 0 {
+		return fmt.Errorf("seconds must be positive")
+	}
+
+	ability, err := a.client.abilities(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get abilities: %w", err)
+	}
+	if ver, _, ok := ability.Version(0, "supportBuzzer"); ok && ver == 0 {
+		return &APIError{Code: 1, RspCode: ErrCodeNotSupported, Detail: "device has no buzzer", Cmd: "BuzzerAlarmPlay"}
+	}
+
+	a.client.log(ctx).Info("triggering buzzer: seconds=%d", seconds)
+	if err := a.buzzerAlarmPlay(ctx, true); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(time.Duration(seconds) * time.Second)
+	defer timer.Stop()
+	var waitErr error
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		waitErr = ctx.Err()
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), buzzerStopTimeout)
+	defer cancel()
+	if err := a.buzzerAlarmPlay(stopCtx, false); err != nil {
+		return errors.Join(waitErr, fmt.Errorf("failed to silence buzzer: %w", err))
+	}
+
+	a.client.log(ctx).Info("successfully triggered buzzer")
+	return waitErr
+}
+
+// buzzerAlarmPlay switches the buzzer on or off
+func (a *AlarmAPI) buzzerAlarmPlay(ctx context.Context, on bool) error {
+	req := []Request{{
+		Cmd: "BuzzerAlarmPlay",
+		Param: map[string]interface{}{
+			"alarm_mode":    "manu",
+			"manual_switch": boolToInt(on),
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to switch buzzer: %v", err)
+		return fmt.Errorf("BuzzerAlarmPlay request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from BuzzerAlarmPlay")
+		a.client.log(ctx).Error("failed to switch buzzer: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to switch buzzer: %v", apiErr)
+		return apiErr
+	}
+	return nil
+}
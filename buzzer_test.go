@@ -0,0 +1,45 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAlarmAPI_TriggerBuzzer(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetAbility":      `{"Ability": {"supportBuzzer": {"permit": 6, "ver": 1}}}`,
+		"BuzzerAlarmPlay": "",
+	})
+	client := server.client()
+
+	if err := client.Alarm.TriggerBuzzer(t.Context(), 0); err == nil {
+		t.Error("expected error for zero seconds")
+	}
+
+	// Cancelled early: the buzzer is still silenced
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Alarm.TriggerBuzzer(ctx, 60); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	if n := server.callCount("BuzzerAlarmPlay"); n != 2 {
+		t.Fatalf("expected 2 BuzzerAlarmPlay calls, got %d", n)
+	}
+	var param struct {
+		ManualSwitch int `json:"manual_switch"`
+	}
+	if err := json.Unmarshal(server.lastParam("BuzzerAlarmPlay"), &param); err != nil {
+		t.Fatal(err)
+	}
+	if param.ManualSwitch != 0 {
+		t.Error("expected the buzzer to be switched off last")
+	}
+
+	server.set("GetAbility", `{"Ability": {"supportBuzzer": {"permit": 0, "ver": 0}}}`)
+	if err := client.Alarm.TriggerBuzzer(t.Context(), 1); !IsNotSupported(err) {
+		t.Errorf("expected not supported, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package reolink
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores raw API response bodies for read-heavy commands.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached value for key if present and not expired
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for ttl
+	Set(key string, value []byte, ttl time.Duration)
+	// DeletePrefix removes every entry whose key starts with prefix
+	DeletePrefix(prefix string)
+}
+
+// DefaultCacheTTLs are the per-command TTLs used by WithCache when none are
+// given. Only slowly-changing reads are cached.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"GetAbility": 10 * time.Minute,
+	"GetDevInfo": 10 * time.Minute,
+	"GetNetPort": 5 * time.Minute,
+	"GetEnc":     5 * time.Minute,
+}
+
+// cacheInvalidations maps Set commands whose Get counterpart is not simply
+// the same name with a "Get" prefix
+var cacheInvalidations = map[string][]string{
+	"SetDevName": {"GetDevInfo", "GetDevName"},
+	"Upgrade":    {"GetDevInfo", "GetAbility"},
+}
+
+// cacheFlushAll lists commands after which every cached response is stale
+var cacheFlushAll = map[string]bool{
+	"Reboot":    true,
+	"Restore":   true,
+	"SetSysCfg": true,
+	"AddIpc":    true,
+	"DelIpc":    true,
+}
+
+// WithCache enables response caching for the commands in ttls (cmd -> TTL).
+// If ttls is nil, DefaultCacheTTLs is used. Cached entries are invalidated
+// automatically when the corresponding Set command succeeds.
+func WithCache(cache Cache, ttls map[string]time.Duration) Option {
+	return func(c *Client) {
+		if ttls == nil {
+			ttls = DefaultCacheTTLs
+		}
+		c.cache = cache
+		c.cacheTTLs = ttls
+	}
+}
+
+// InvalidateCache drops cached responses for the given commands, or all
+// cached responses for the client's camera if no command is given. The
+// responses of every user of the camera sharing the cache are dropped,
+// since a change made by one user is seen by all.
+func (c *Client) InvalidateCache(cmds ...string) {
+	if c.cache == nil {
+		return
+	}
+	prefix := c.cachePrefix()
+	if len(cmds) == 0 {
+		c.cache.DeletePrefix(prefix)
+		return
+	}
+	for _, cmd := range cmds {
+		c.cache.DeletePrefix(prefix + cmd + "|")
+	}
+}
+
+// cachePrefix returns the prefix of the cache keys of the client's camera.
+// Keys have the form host|cmd|user|param|action: the user follows the
+// command, since cameras answer some reads according to its permissions,
+// while invalidation by host and command covers every user.
+func (c *Client) cachePrefix() string {
+	return c.Host() + "|"
+}
+
+// cacheLookup returns the cache key and TTL for a cacheable request batch.
+// Only single-command batches of a command with a configured TTL are cached.
+func (c *Client) cacheLookup(requests []Request) (string, time.Duration) {
+	if c.cache == nil || len(requests) != 1 {
+		return "", 0
+	}
+	ttl, ok := c.cacheTTLs[requests[0].Cmd]
+	if !ok || ttl <= 0 {
+		return "", 0
+	}
+	param, err := json.Marshal(requests[0].Param)
+	if err != nil {
+		return "", 0
+	}
+	return c.cachePrefix() + requests[0].Cmd + "|" + c.username + "|" + string(param) + "|" + strconv.Itoa(requests[0].Action), ttl
+}
+
+// invalidateAfter drops cached responses made stale by the given requests.
+// Requests whose response in body failed changed nothing and are skipped.
+func (c *Client) invalidateAfter(requests []Request, body []byte) {
+	if c.cache == nil {
+		return
+	}
+	failed := failedRequests(requests, body)
+	for i, req := range requests {
+		if failed[i] {
+			continue
+		}
+		if cacheFlushAll[req.Cmd] {
+			c.InvalidateCache()
+			return
+		}
+		if cmds, ok := cacheInvalidations[req.Cmd]; ok {
+			c.InvalidateCache(cmds...)
+		}
+		if strings.HasPrefix(req.Cmd, "Set") {
+			c.InvalidateCache("Get" + strings.TrimPrefix(req.Cmd, "Set"))
+		}
+	}
+}
+
+// failedRequests reports for each request whether its response in body
+// failed. Responses are matched to requests by command, in order, as in
+// Batch; a request without a response counts as succeeded, so that its
+// cached reads are dropped.
+func failedRequests(requests []Request, body []byte) []bool {
+	failed := make([]bool, len(requests))
+	var resps []Response
+	if err := json.Unmarshal(body, &resps); err != nil {
+		return failed
+	}
+	used := make([]bool, len(resps))
+	for i, req := range requests {
+		for j := range resps {
+			if !used[j] && resps[j].Cmd == req.Cmd {
+				used[j] = true
+				failed[i] = resps[j].ToAPIError() != nil
+				break
+			}
+		}
+	}
+	return failed
+}
+
+// responsesOK reports whether every response in body succeeded
+func responsesOK(body []byte) bool {
+	var resps []Response
+	if err := json.Unmarshal(body, &resps); err != nil || len(resps) == 0 {
+		return false
+	}
+	for i := range resps {
+		if resps[i].ToAPIError() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// MemoryCache is an in-memory Cache with per-entry expiry
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	now     func() time.Time
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached value for key if present and not expired
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if m.now().After(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key for ttl
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	m.entries[key] = memoryCacheEntry{value: value, expires: m.now().Add(ttl)}
+	m.mu.Unlock()
+}
+
+// DeletePrefix removes every entry whose key starts with prefix
+func (m *MemoryCache) DeletePrefix(prefix string) {
+	m.mu.Lock()
+	for key := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.entries, key)
+		}
+	}
+	m.mu.Unlock()
+}
@@ -0,0 +1,169 @@
+package reolink
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithCache_CachesReads(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"httpPort": 80, "rtspPort": 554}}`,
+		"SetNetPort": "",
+		"GetTime":    `{"Time": {"year": 2024}}`,
+	})
+	client := server.client()
+	WithCache(NewMemoryCache(), nil)(client)
+	ctx := t.Context()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Network.GetNetPort(ctx); err != nil {
+			t.Fatalf("GetNetPort failed: %v", err)
+		}
+	}
+	if n := server.callCount("GetNetPort"); n != 1 {
+		t.Errorf("expected 1 GetNetPort request, got %d", n)
+	}
+
+	// Commands without a TTL are never cached
+	for i := 0; i < 2; i++ {
+		if _, err := client.System.GetTime(ctx); err != nil {
+			t.Fatalf("GetTime failed: %v", err)
+		}
+	}
+	if n := server.callCount("GetTime"); n != 2 {
+		t.Errorf("expected 2 GetTime requests, got %d", n)
+	}
+
+	// A successful Set invalidates the matching Get
+	if err := client.Network.SetNetPort(ctx, NetPort{HTTPPort: 8080, RTSPPort: 554}); err != nil {
+		t.Fatalf("SetNetPort failed: %v", err)
+	}
+	netPort, err := client.Network.GetNetPort(ctx)
+	if err != nil {
+		t.Fatalf("GetNetPort failed: %v", err)
+	}
+	if netPort.HTTPPort != 8080 {
+		t.Errorf("expected updated port 8080, got %d", netPort.HTTPPort)
+	}
+	if n := server.callCount("GetNetPort"); n != 2 {
+		t.Errorf("expected 2 GetNetPort requests, got %d", n)
+	}
+}
+
+func TestWithCache_SkipsErrors(t *testing.T) {
+	server := newCmdServer(t, map[string]string{})
+	client := server.client()
+	WithCache(NewMemoryCache(), nil)(client)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.System.GetAbility(t.Context()); err == nil {
+			t.Fatal("expected error for unsupported command")
+		}
+	}
+	if n := server.callCount("GetAbility"); n != 2 {
+		t.Errorf("expected errors not to be cached, got %d requests", n)
+	}
+}
+
+func TestWithCache_BatchInvalidation(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"httpPort": 80}}`,
+		"GetEnc":     `{"Enc": {"channel": 0}}`,
+		"SetNetPort": "",
+	})
+	client := server.client()
+	WithCache(NewMemoryCache(), nil)(client)
+	ctx := t.Context()
+
+	client.Network.GetNetPort(ctx)
+	client.Encoding.GetEnc(ctx, 0)
+	// SetEnc is not supported by the server, so only SetNetPort took effect
+	client.Batch(ctx, []Request{
+		{Cmd: "SetNetPort", Param: map[string]interface{}{"NetPort": map[string]int{"httpPort": 80}}},
+		{Cmd: "SetEnc", Param: map[string]interface{}{"Enc": map[string]int{"channel": 0}}},
+	})
+	client.Network.GetNetPort(ctx)
+	client.Encoding.GetEnc(ctx, 0)
+	if n := server.callCount("GetNetPort"); n != 2 {
+		t.Errorf("expected the successful Set to invalidate GetNetPort, got %d requests", n)
+	}
+	if n := server.callCount("GetEnc"); n != 1 {
+		t.Errorf("expected the failed Set to keep GetEnc cached, got %d requests", n)
+	}
+}
+
+func TestWithCache_PerUser(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetEnc": `{"Enc": {"channel": 0}}`,
+		"SetEnc": "",
+	})
+	cache := NewMemoryCache()
+	admin, guest := server.client(), server.client()
+	admin.username, guest.username = "admin", "guest"
+	WithCache(cache, nil)(admin)
+	WithCache(cache, nil)(guest)
+
+	admin.Encoding.GetEnc(t.Context(), 0)
+	guest.Encoding.GetEnc(t.Context(), 0)
+	admin.Encoding.GetEnc(t.Context(), 0)
+	if n := server.callCount("GetEnc"); n != 2 {
+		t.Errorf("expected one GetEnc request per user, got %d", n)
+	}
+
+	// A change made by one user invalidates the responses cached for all
+	if err := admin.Encoding.SetEnc(t.Context(), EncConfig{Channel: 0}); err != nil {
+		t.Fatalf("SetEnc failed: %v", err)
+	}
+	guest.Encoding.GetEnc(t.Context(), 0)
+	if n := server.callCount("GetEnc"); n != 3 {
+		t.Errorf("expected the admin's SetEnc to invalidate the guest's GetEnc, got %d requests", n)
+	}
+}
+
+func TestClient_InvalidateCache(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetEnc": `{"Enc": {"channel": 0}}`,
+	})
+	client := server.client()
+	WithCache(NewMemoryCache(), nil)(client)
+	ctx := t.Context()
+
+	client.Encoding.GetEnc(ctx, 0)
+	client.Encoding.GetEnc(ctx, 1) // different param, separate entry
+	client.Encoding.GetEnc(ctx, 0)
+	if n := server.callCount("GetEnc"); n != 2 {
+		t.Errorf("expected 2 GetEnc requests, got %d", n)
+	}
+
+	client.InvalidateCache()
+	client.Encoding.GetEnc(ctx, 0)
+	if n := server.callCount("GetEnc"); n != 3 {
+		t.Errorf("expected 3 GetEnc requests after invalidation, got %d", n)
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+
+	cache.Set("host|GetDevInfo|null|0", []byte("x"), time.Minute)
+	if _, ok := cache.Get("host|GetDevInfo|null|0"); !ok {
+		t.Error("expected cache hit")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.Get("host|GetDevInfo|null|0"); ok {
+		t.Error("expected entry to expire")
+	}
+
+	cache.Set("host|GetEnc|a", []byte("x"), time.Minute)
+	cache.Set("host|GetNetPort|b", []byte("x"), time.Minute)
+	cache.DeletePrefix("host|GetEnc|")
+	if _, ok := cache.Get("host|GetEnc|a"); ok {
+		t.Error("expected prefix delete")
+	}
+	if _, ok := cache.Get("host|GetNetPort|b"); !ok {
+		t.Error("expected unrelated entry to survive")
+	}
+}
@@ -0,0 +1,42 @@
+package reolink
+
+import (
+	"context"
+	"io"
+)
+
+// cancelBody is a response body that is closed when its context is done
+type cancelBody struct {
+	io.ReadCloser
+	ctx  context.Context
+	stop func() bool
+}
+
+// closeOnCancel makes body close when ctx is done, so a Read blocked on a
+// camera that stopped sending returns promptly even through transports
+// that do not watch the request context, such as P2P dialers. A cancelled
+// transfer also drops the client's idle connections: cameras serve only a
+// few sockets and refuse new sessions while abandoned ones linger. Reads
+// after cancellation return ctx.Err().
+func (c *Client) closeOnCancel(ctx context.Context, body io.ReadCloser) io.ReadCloser {
+	stop := context.AfterFunc(ctx, func() {
+		body.Close()
+		c.httpClient.CloseIdleConnections()
+	})
+	return &cancelBody{ReadCloser: body, ctx: ctx, stop: stop}
+}
+
+// Read implements io.Reader
+func (b *cancelBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.ctx.Err() != nil {
+		err = b.ctx.Err()
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (b *cancelBody) Close() error {
+	b.stop()
+	return b.ReadCloser.Close()
+}
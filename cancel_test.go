@@ -0,0 +1,84 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// detachedTransport drops the request context, like transports that tunnel
+// through a connection of their own
+type detachedTransport struct{ next http.RoundTripper }
+
+func (t detachedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(context.Background()))
+}
+
+// newStallingServer sends the start of a response of contentType and then
+// stalls until the client goes away, which it reports on the returned
+// channel
+func newStallingServer(t *testing.T, contentType string) (*httptest.Server, <-chan struct{}) {
+	t.Helper()
+	gone := make(chan struct{}, 4)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", "1000000")
+		w.Write(make([]byte, 1000))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+			gone <- struct{}{}
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+	return server, gone
+}
+
+func TestClient_CancelTransfers(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		call        func(ctx context.Context, c *Client) error
+	}{
+		"Snap": {"image/jpeg", func(ctx context.Context, c *Client) error {
+			_, err := c.Encoding.Snap(ctx, 0)
+			return err
+		}},
+		"DownloadTo": {"application/octet-stream", func(ctx context.Context, c *Client) error {
+			_, err := c.Recording.DownloadTo(ctx, "RecM01.mp4", io.Discard, DownloadOptions{Retries: 1, RetryDelay: time.Millisecond})
+			return err
+		}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server, gone := newStallingServer(t, tt.contentType)
+			client := newTestClient(server)
+			client.httpClient.Transport = detachedTransport{next: server.Client().Transport}
+
+			ctx, cancel := context.WithCancel(t.Context())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := tt.call(ctx, client)
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("cancellation took %v", elapsed)
+			}
+			select {
+			case <-gone:
+			case <-time.After(2 * time.Second):
+				t.Error("connection to the camera was not closed")
+			}
+		})
+	}
+}
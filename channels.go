@@ -0,0 +1,310 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// onlineChannels lists the online channels of an NVR. Standalone cameras
+// that do not support GetChannelStatus report channel 0.
+func (c *Client) onlineChannels(ctx context.Context) ([]int, error) {
+	status, err := c.System.GetChannelStatus(ctx)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RspCode == ErrCodeNotSupported {
+			return []int{0}, nil
+		}
+		return nil, fmt.Errorf("failed to list channels: %w", err)
+	}
+
+	var channels []int
+	for _, cs := range status.Online() {
+		channels = append(channels, cs.Channel)
+	}
+	return channels, nil
+}
+
+// RenameChannel sets the display name of an NVR channel. The name is stored
+// in the channel's OSD configuration, so the rest of the OSD settings are
+// read first and written back unchanged.
+func (s *SystemAPI) RenameChannel(ctx context.Context, channel int, name string) error {
+	if name == "" {
+		return fmt.Errorf("channel name must not be empty")
+	}
+
+	s.client.log(ctx).Info("renaming channel: channel=%d name=%s", channel, name)
+
+	osd, err := s.client.Video.GetOsd(ctx, channel)
+	if err != nil {
+		return fmt.Errorf("failed to read channel %d OSD: %w", channel, err)
+	}
+
+	osd.Channel = channel
+	osd.OsdChannel.Name = name
+	if err := s.client.Video.SetOsd(ctx, *osd); err != nil {
+		return fmt.Errorf("failed to rename channel %d: %w", channel, err)
+	}
+
+	s.client.log(ctx).Info("successfully renamed channel %d", channel)
+	return nil
+}
+
+// RenameChannels renames several NVR channels (channel -> name). It stops at
+// the first failure.
+func (s *SystemAPI) RenameChannels(ctx context.Context, names map[int]string) error {
+	for channel, name := range names {
+		if err := s.RenameChannel(ctx, channel, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChannelSetup is applied to every channel of an NVR by SetupChannels
+type ChannelSetup struct {
+	// Channels to set up (default: all online channels)
+	Channels []int
+	// NamePattern names each channel, e.g. "{site}-{channel:02d}". {site}
+	// is replaced by Site and {channel} by the channel number as the NVR
+	// shows it, starting at 1; {channel:0Nd} pads it to N digits. Empty
+	// leaves the names alone.
+	NamePattern string
+	// Site replaces {site} in NamePattern
+	Site string
+	// NamePos and TimePos, when set, move the name and timestamp
+	NamePos OsdPos
+	TimePos OsdPos
+	// ShowName and ShowTime, when not nil, show or hide the name and
+	// timestamp
+	ShowName *bool
+	ShowTime *bool
+	// Time, when not nil, sets the NVR's clock, which it passes on to the
+	// cameras it records
+	Time *TimeSyncSource
+}
+
+// channelPlaceholder matches the placeholders of ChannelSetup.NamePattern
+var channelPlaceholder = regexp.MustCompile(`\{([a-z]+)(?::0(\d)d)?\}`)
+
+// ChannelName expands the name pattern for channel (0-based, as in the API)
+func (s ChannelSetup) ChannelName(channel int) (string, error) {
+	var err error
+	name := channelPlaceholder.ReplaceAllStringFunc(s.NamePattern, func(m string) string {
+		parts := channelPlaceholder.FindStringSubmatch(m)
+		switch parts[1] {
+		case "site":
+			return s.Site
+		case "channel":
+			n := strconv.Itoa(channel + 1)
+			if width, _ := strconv.Atoi(parts[2]); len(n) < width {
+				n = strings.Repeat("0", width-len(n)) + n
+			}
+			return n
+		}
+		err = fmt.Errorf("unknown placeholder %s in channel name pattern", m)
+		return m
+	})
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(name, "{}") {
+		return "", fmt.Errorf("invalid channel name pattern %q", s.NamePattern)
+	}
+	return name, nil
+}
+
+// Validate checks the name pattern and positions
+func (s *ChannelSetup) Validate() error {
+	if s.NamePattern != "" {
+		if _, err := s.ChannelName(0); err != nil {
+			return err
+		}
+	}
+	if err := s.NamePos.Validate(); err != nil {
+		return err
+	}
+	return s.TimePos.Validate()
+}
+
+// SetupChannels applies names, OSD and time settings to all channels of an
+// NVR in one go, the most repetitive part of bringing one up. Each
+// channel's OSD is read and written back with only the requested changes,
+// and only if something changed. If some channels fail, the others are
+// still set up and an error describing the failures is returned.
+func (s *SystemAPI) SetupChannels(ctx context.Context, setup ChannelSetup) error {
+	if err := setup.Validate(); err != nil {
+		return err
+	}
+
+	channels := setup.Channels
+	if len(channels) == 0 {
+		var err error
+		if channels, err = s.client.onlineChannels(ctx); err != nil {
+			return err
+		}
+	}
+
+	s.client.log(ctx).Info("setting up channels: channels=%v pattern=%q", channels, setup.NamePattern)
+
+	if setup.Time != nil {
+		source := *setup.Time
+		if source.Reference == nil {
+			source.Reference = time.Now
+		}
+		if source.NTPPort == 0 {
+			source.NTPPort = 123
+		}
+		if _, err := syncCameraTime(ctx, s.client, source); err != nil {
+			return fmt.Errorf("failed to set NVR time: %w", err)
+		}
+	}
+
+	var errs []error
+	for _, channel := range channels {
+		if err := s.setupChannel(ctx, channel, setup); err != nil {
+			errs = append(errs, fmt.Errorf("channel %d: %w", channel, err))
+		}
+	}
+	if len(errs) > 0 {
+		joined := errors.Join(errs...)
+		err := fmt.Errorf("setup failed on %d of %d channels: %w", len(errs), len(channels), joined)
+		s.client.log(ctx).Error("failed to set up channels: %v", err)
+		return err
+	}
+
+	s.client.log(ctx).Info("successfully set up %d channels", len(channels))
+	return nil
+}
+
+// setupChannel applies setup to the OSD of one channel
+func (s *SystemAPI) setupChannel(ctx context.Context, channel int, setup ChannelSetup) error {
+	osd, err := s.client.Video.GetOsd(ctx, channel)
+	if err != nil {
+		return fmt.Errorf("failed to read OSD: %w", err)
+	}
+	before := *osd
+
+	osd.Channel = channel
+	if setup.NamePattern != "" {
+		// Validate already checked the pattern
+		osd.OsdChannel.Name, _ = setup.ChannelName(channel)
+	}
+	if setup.NamePos != "" {
+		osd.OsdChannel.Pos = setup.NamePos
+	}
+	if setup.TimePos != "" {
+		osd.OsdTime.Pos = setup.TimePos
+	}
+	if setup.ShowName != nil {
+		osd.OsdChannel.Enable.Set(*setup.ShowName)
+	}
+	if setup.ShowTime != nil {
+		osd.OsdTime.Enable.Set(*setup.ShowTime)
+	}
+	if *osd == before {
+		return nil
+	}
+	return s.client.Video.SetOsd(ctx, *osd)
+}
+
+// SetupChannels runs System.SetupChannels on every NVR in the fleet
+// concurrently. An empty setup.Site is replaced by each NVR's name in the
+// fleet. It returns the errors keyed by NVR name.
+func (f *Fleet) SetupChannels(ctx context.Context, setup ChannelSetup) map[string]error {
+	return f.Each(ctx, func(ctx context.Context, name string, c *Client) error {
+		s := setup
+		if s.Site == "" {
+			s.Site = name
+		}
+		return c.System.SetupChannels(ctx, s)
+	})
+}
+
+// WatchChannelStatus polls GetChannelStatus every interval and calls handler
+// with EventChannelOnline or EventChannelOffline whenever a channel changes
+// state, and with EventCameraAsleep or EventCameraAwake when a battery
+// camera on the channel falls asleep or wakes up. The first poll only
+// records the initial state. Polling errors are logged and retried on the
+// next tick. It blocks until ctx is done and returns ctx.Err().
+func (s *SystemAPI) WatchChannelStatus(ctx context.Context, interval time.Duration, handler EventHandler) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler must not be nil")
+	}
+
+	var previous map[int]ChannelStatus
+
+	poll := func() {
+		status, err := s.GetChannelStatus(ctx)
+		if err != nil {
+			s.client.log(ctx).Warn("channel status poll failed: %v", err)
+			return
+		}
+		current := make(map[int]ChannelStatus, len(status.Status))
+		for _, cs := range status.Status {
+			current[cs.Channel] = cs
+		}
+		if previous != nil {
+			for _, ev := range channelStatusEvents(s.client.Host(), previous, status.Status, time.Now()) {
+				handler(ev)
+			}
+		}
+		previous = current
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+// channelStatusEvents returns the online/offline and sleep transitions
+// between two channel status snapshots. Channels missing from the previous
+// snapshot are reported if they appear online.
+func channelStatusEvents(host string, previous map[int]ChannelStatus, current []ChannelStatus, now time.Time) []Event {
+	var events []Event
+	for _, cs := range current {
+		prev, known := previous[cs.Channel]
+		if known && cs.Online == 1 && prev.Online == 1 && prev.Sleep != cs.Sleep {
+			ev := Event{Type: EventCameraAwake, Host: host, Channel: cs.Channel, Time: now, Detail: cs.Name}
+			if cs.Sleep == 1 {
+				ev.Type = EventCameraAsleep
+			}
+			events = append(events, ev)
+			continue
+		}
+		if known && prev.Online == cs.Online {
+			continue
+		}
+		if !known && cs.Online != 1 {
+			continue
+		}
+		ev := Event{
+			Type:    EventChannelOffline,
+			Host:    host,
+			Channel: cs.Channel,
+			Time:    now,
+			Detail:  cs.Name,
+		}
+		if cs.Online == 1 {
+			ev.Type = EventChannelOnline
+		}
+		events = append(events, ev)
+	}
+	return events
+}
@@ -0,0 +1,247 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetChannelStatus_Normalized(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"Getchannelstatus": `{"count": 3, "status": [
+			{"channel": 2, "name": "Yard", "online": 0, "sleep": 1, "typeInfo": "Argus3"},
+			{"channel": 0, "name": "Door", "online": 1, "typeInfo": "RLC-810A", "uid": "95270000ABCDEFGH"},
+			{"channel": 0, "name": "Door", "online": 1, "typeInfo": "RLC-810A"},
+			{"channel": 1, "name": "Drive", "online": 1, "typeInfo": "RLC-520A"}
+		]}`,
+	})
+
+	status, err := srv.client().System.GetChannelStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetChannelStatus failed: %v", err)
+	}
+
+	if len(status.Status) != 3 {
+		t.Fatalf("expected 3 channels after dedupe, got %d", len(status.Status))
+	}
+	for i, cs := range status.Status {
+		if cs.Channel != i {
+			t.Errorf("expected channel %d at index %d, got %d", i, i, cs.Channel)
+		}
+	}
+	if status.Status[0].UID != "95270000ABCDEFGH" {
+		t.Errorf("expected uid to be parsed, got %q", status.Status[0].UID)
+	}
+
+	yard, ok := status.Channel(2)
+	if !ok {
+		t.Fatal("expected channel 2 to be present")
+	}
+	if yard.Sleep != 1 || yard.TypeInfo != "Argus3" {
+		t.Errorf("unexpected channel 2 status: %+v", yard)
+	}
+	if _, ok := status.Channel(7); ok {
+		t.Error("expected channel 7 to be absent")
+	}
+	if online := status.Online(); len(online) != 2 {
+		t.Errorf("expected 2 online channels, got %d", len(online))
+	}
+}
+
+func TestRenameChannel(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetOsd": `{"Osd": {"channel": 1, "bgcolor": 0, "osdChannel": {"enable": 1, "name": "Camera 2", "pos": "Lower Right"}}}`,
+		"SetOsd": "",
+	})
+
+	if err := srv.client().System.RenameChannel(t.Context(), 1, "Driveway"); err != nil {
+		t.Fatalf("RenameChannel failed: %v", err)
+	}
+
+	var param struct {
+		Osd Osd `json:"Osd"`
+	}
+	if err := json.Unmarshal(srv.lastParam("SetOsd"), &param); err != nil {
+		t.Fatalf("failed to decode SetOsd param: %v", err)
+	}
+	if param.Osd.OsdChannel.Name != "Driveway" {
+		t.Errorf("expected name Driveway, got %q", param.Osd.OsdChannel.Name)
+	}
+	if param.Osd.OsdChannel.Pos != "Lower Right" || param.Osd.OsdChannel.Enable != 1 {
+		t.Errorf("expected other OSD settings to be preserved, got %+v", param.Osd.OsdChannel)
+	}
+}
+
+func TestRenameChannel_EmptyName(t *testing.T) {
+	srv := newCmdServer(t, nil)
+
+	if err := srv.client().System.RenameChannel(t.Context(), 0, ""); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+	if srv.callCount("GetOsd") != 0 {
+		t.Error("expected no request for invalid name")
+	}
+}
+
+func TestChannelStatusEvents(t *testing.T) {
+	now := time.Now()
+	previous := map[int]ChannelStatus{
+		0: {Channel: 0, Online: 1},
+		1: {Channel: 1, Online: 0},
+		2: {Channel: 2, Online: 1},
+	}
+	current := []ChannelStatus{
+		{Channel: 0, Name: "Door", Online: 0},
+		{Channel: 1, Name: "Drive", Online: 1},
+		{Channel: 2, Name: "Yard", Online: 1},
+		{Channel: 3, Name: "New", Online: 1},
+		{Channel: 4, Name: "Unplugged", Online: 0},
+	}
+
+	events := channelStatusEvents("nvr", previous, current, now)
+	want := []struct {
+		typ     EventType
+		channel int
+	}{
+		{EventChannelOffline, 0},
+		{EventChannelOnline, 1},
+		{EventChannelOnline, 3},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i, w := range want {
+		if events[i].Type != w.typ || events[i].Channel != w.channel {
+			t.Errorf("event %d: expected %s on channel %d, got %s on channel %d",
+				i, w.typ, w.channel, events[i].Type, events[i].Channel)
+		}
+		if events[i].Host != "nvr" || !events[i].Time.Equal(now) {
+			t.Errorf("event %d: unexpected host/time: %+v", i, events[i])
+		}
+	}
+}
+
+func TestWatchChannelStatus(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"Getchannelstatus": `{"count": 1, "status": [{"channel": 0, "name": "Door", "online": 1}]}`,
+	})
+	client := srv.client()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan Event, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.System.WatchChannelStatus(ctx, 10*time.Millisecond, func(ev Event) {
+			events <- ev
+		})
+	}()
+
+	// Wait for the initial poll before changing state
+	for srv.callCount("Getchannelstatus") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.set("Getchannelstatus", `{"count": 1, "status": [{"channel": 0, "name": "Door", "online": 0}]}`)
+
+	select {
+	case ev := <-events:
+		if ev.Type != EventChannelOffline || ev.Channel != 0 || ev.Detail != "Door" {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for offline event")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWatchChannelStatus_InvalidArgs(t *testing.T) {
+	client := newCmdServer(t, nil).client()
+
+	if err := client.System.WatchChannelStatus(t.Context(), 0, func(Event) {}); err == nil {
+		t.Error("expected error for zero interval")
+	}
+	if err := client.System.WatchChannelStatus(t.Context(), time.Second, nil); err == nil {
+		t.Error("expected error for nil handler")
+	}
+}
+
+func TestChannelSetup_ChannelName(t *testing.T) {
+	setup := ChannelSetup{Site: "depot", NamePattern: "{site}-{channel:02d}"}
+	for channel, want := range map[int]string{0: "depot-01", 11: "depot-12", 99: "depot-100"} {
+		if got, err := setup.ChannelName(channel); err != nil || got != want {
+			t.Errorf("channel %d: expected %q, got %q (%v)", channel, want, got, err)
+		}
+	}
+	if got, _ := (ChannelSetup{NamePattern: "Cam {channel}"}).ChannelName(2); got != "Cam 3" {
+		t.Errorf("expected Cam 3, got %q", got)
+	}
+	for _, pattern := range []string{"{site}-{chan}", "{site}-{channel:2d}", "{site"} {
+		setup := ChannelSetup{NamePattern: pattern}
+		if err := setup.Validate(); err == nil {
+			t.Errorf("expected error for pattern %q", pattern)
+		}
+	}
+}
+
+func TestSystemAPI_SetupChannels(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"Getchannelstatus": `{"count": 2, "status": [{"channel": 0, "online": 1}, {"channel": 1, "online": 1}]}`,
+		"GetOsd":           `{"Osd": {"channel": 0, "bgcolor": 0, "osdChannel": {"enable": 1, "name": "Camera", "pos": "Lower Right"}, "osdTime": {"enable": 0, "pos": "Top Center"}}}`,
+		"SetOsd":           "",
+	})
+	client := srv.client()
+
+	show := true
+	setup := ChannelSetup{Site: "depot", NamePattern: "{site}-{channel:02d}", TimePos: OsdPosUpperRight, ShowTime: &show}
+	if err := client.System.SetupChannels(t.Context(), setup); err != nil {
+		t.Fatalf("SetupChannels failed: %v", err)
+	}
+	if n := srv.callCount("SetOsd"); n != 2 {
+		t.Fatalf("expected 2 SetOsd calls, got %d", n)
+	}
+	var param struct {
+		Osd Osd `json:"Osd"`
+	}
+	json.Unmarshal(srv.lastParam("SetOsd"), &param)
+	osd := param.Osd
+	if osd.Channel != 1 || osd.OsdChannel.Name != "depot-02" || osd.OsdChannel.Pos != OsdPosLowerRight ||
+		!osd.OsdTime.Enable.Bool() || osd.OsdTime.Pos != OsdPosUpperRight {
+		t.Errorf("unexpected OSD: %+v", osd)
+	}
+
+	// Nothing to change: no writes
+	calls := srv.callCount("SetOsd")
+	if err := client.System.SetupChannels(t.Context(), ChannelSetup{Channels: []int{1}, ShowTime: &show}); err != nil {
+		t.Fatalf("SetupChannels failed: %v", err)
+	}
+	if srv.callCount("SetOsd") != calls {
+		t.Error("expected no SetOsd call for an unchanged channel")
+	}
+}
+
+func TestFleet_SetupChannels(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetOsd": `{"Osd": {"channel": 0, "osdChannel": {"enable": 1, "name": "Camera", "pos": "Lower Right"}}}`,
+		"SetOsd": "",
+	})
+	fleet := NewFleet()
+	fleet.Add("warehouse", srv.client())
+
+	if errs := fleet.SetupChannels(t.Context(), ChannelSetup{Channels: []int{0}, NamePattern: "{site} {channel}"}); len(errs) != 0 {
+		t.Fatalf("SetupChannels failed: %v", errs)
+	}
+	var param struct {
+		Osd Osd `json:"Osd"`
+	}
+	json.Unmarshal(srv.lastParam("SetOsd"), &param)
+	if param.Osd.OsdChannel.Name != "warehouse 1" {
+		t.Errorf("expected the fleet name as site, got %q", param.Osd.OsdChannel.Name)
+	}
+}
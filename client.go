@@ -27,11 +27,12 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"runtime/pprof"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mosleyit/reolink_api_wrapper/pkg/logger"
@@ -47,20 +48,54 @@ type Client struct {
 	token      string
 	tokenMu    sync.RWMutex
 	useHTTPS   bool
+	endpointMu sync.RWMutex // Guards host, useHTTPS and baseURL, which SetNetPort can change
+	uid        string
+	p2pDialer  P2PDialer
 	logger     logger.Logger
+	cache      Cache
+	cacheTTLs  map[string]time.Duration
+	state      StateStore
+	stateMu    sync.Mutex
+	signer     RequestSigner
+	userAgent  string
+	headers    http.Header
+	metrics    MetricsRecorder
+	codec      Codec
+
+	decodeMode       DecodeMode
+	decodeChecked    sync.Map // Response types checked for unknown fields in lenient mode
+	decodeMismatched sync.Map // Response types seen with mismatched types in lenient mode
+
+	portGuard          bool          // Refuse SetNetPort disabling the active transport
+	transliterateNames bool          // Transliterate device names the device cannot store
+	sleep              *SleepOptions // Battery camera sleep handling, see WithSleepHandling
+	policy             *Policy       // Commands the client may execute, see WithPolicy
+	asleep             atomic.Bool   // Last request found the camera asleep
+	legacy             atomic.Bool   // Query-style API of pre-2019 firmware, see WithLegacyAPI
+	debugLog           *debugCapture
+
+	customQuirks []Quirk
+	quirkMu      sync.RWMutex
+	quirks       []Quirk      // Quirks matching the device, set by GetDeviceInfo
+	httpPort     atomic.Int32 // Camera's HTTP port for Quirk.HTTPCmds: 0 if unknown, -1 if disabled
+
+	ability atomic.Pointer[Ability] // Ability snapshot kept with a state store, see abilities
 
 	// API modules
-	System    *SystemAPI
-	Security  *SecurityAPI
-	Network   *NetworkAPI
-	Video     *VideoAPI
-	Encoding  *EncodingAPI
-	Recording *RecordingAPI
-	PTZ       *PTZAPI
-	Alarm     *AlarmAPI
-	LED       *LEDAPI
-	AI        *AIAPI
-	Streaming *StreamingAPI
+	System      *SystemAPI
+	Security    *SecurityAPI
+	Network     *NetworkAPI
+	Video       *VideoAPI
+	Encoding    *EncodingAPI
+	Recording   *RecordingAPI
+	PTZ         *PTZAPI
+	Alarm       *AlarmAPI
+	LED         *LEDAPI
+	AI          *AIAPI
+	Streaming   *StreamingAPI
+	ONVIF       *ONVIFAPI
+	Storage     *StorageAPI
+	Diagnostics *DiagnosticsAPI
 }
 
 // NewClient creates a new Reolink API client
@@ -84,6 +119,8 @@ func NewClient(host string, opts ...Option) *Client {
 		opt(c)
 	}
 
+	c.installP2PDialer()
+
 	// Set base URL
 	c.updateBaseURL()
 
@@ -99,12 +136,22 @@ func NewClient(host string, opts ...Option) *Client {
 	c.LED = &LEDAPI{client: c}
 	c.AI = &AIAPI{client: c}
 	c.Streaming = &StreamingAPI{client: c}
+	c.ONVIF = &ONVIFAPI{client: c}
+	c.Storage = &StorageAPI{client: c}
+	c.Diagnostics = &DiagnosticsAPI{client: c}
 
 	return c
 }
 
 // updateBaseURL updates the base URL based on current settings
 func (c *Client) updateBaseURL() {
+	c.endpointMu.Lock()
+	defer c.endpointMu.Unlock()
+	c.updateBaseURLLocked()
+}
+
+// updateBaseURLLocked updates the base URL. The caller holds endpointMu.
+func (c *Client) updateBaseURLLocked() {
 	scheme := "http"
 	if c.useHTTPS {
 		scheme = "https"
@@ -112,8 +159,55 @@ func (c *Client) updateBaseURL() {
 	c.baseURL = fmt.Sprintf("%s://%s/cgi-bin/api.cgi", scheme, c.host)
 }
 
-// do executes an API request
+// endpoint returns the host the client connects to and whether it uses
+// HTTPS, read together so they match
+func (c *Client) endpoint() (host string, https bool) {
+	c.endpointMu.RLock()
+	defer c.endpointMu.RUnlock()
+	return c.host, c.useHTTPS
+}
+
+// setEndpoint points the client at host over HTTP or HTTPS
+func (c *Client) setEndpoint(host string, https bool) {
+	c.endpointMu.Lock()
+	defer c.endpointMu.Unlock()
+	c.host = host
+	c.useHTTPS = https
+	c.updateBaseURLLocked()
+}
+
+// do executes an API request, labelled with its command for profiling
 func (c *Client) do(ctx context.Context, requests []Request, response interface{}) error {
+	cmd := ""
+	if len(requests) > 0 {
+		cmd = requests[0].Cmd
+	}
+	if err := c.checkRequestPolicy(ctx, requests); err != nil {
+		return err
+	}
+	requests = c.quirkRequests(requests)
+	return withCmdLabels(ctx, cmd, func(ctx context.Context) error {
+		if c.sleep != nil {
+			return c.doSleepAware(ctx, func(ctx context.Context) error {
+				return c.doRequest(ctx, requests, response)
+			})
+		}
+		return c.doRequest(ctx, requests, response)
+	})
+}
+
+// withCmdLabels runs fn with a pprof label identifying cmd, so CPU profiles
+// of applications using the client break down per command
+func withCmdLabels(ctx context.Context, cmd string, fn func(context.Context) error) error {
+	var err error
+	pprof.Do(ctx, pprof.Labels("reolink_cmd", cmd), func(ctx context.Context) {
+		err = fn(ctx)
+	})
+	return err
+}
+
+// doRequest executes an API request
+func (c *Client) doRequest(ctx context.Context, requests []Request, response interface{}) error {
 	// Add token to requests if available
 	c.tokenMu.RLock()
 	token := c.token
@@ -125,62 +219,165 @@ func (c *Client) do(ctx context.Context, requests []Request, response interface{
 		}
 	}
 
+	// Serve cacheable reads from the cache
+	cacheKey, cacheTTL := c.cacheLookup(requests)
+	if cacheKey != "" {
+		if cached, ok := c.cache.Get(cacheKey); ok {
+			c.log(ctx).Debug("API request served from cache: cmd=%s", requests[0].Cmd)
+			if err := c.unmarshal(cached, response); err != nil {
+				return fmt.Errorf("failed to unmarshal cached response: %w", err)
+			}
+			captureRaw(ctx, cached, true)
+			return nil
+		}
+	}
+
 	// Marshal request
-	reqBody, err := json.Marshal(requests)
+	reqBody, err := c.marshal(requests)
 	if err != nil {
-		c.logger.Error("failed to marshal request: %v", err)
+		c.log(ctx).Error("failed to marshal request: %v", err)
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Build URL with cmd parameter
-	url := c.baseURL
+	cmd := ""
 	if len(requests) > 0 {
-		url = fmt.Sprintf("%s?cmd=%s", c.baseURL, requests[0].Cmd)
+		cmd = requests[0].Cmd
+		c.log(ctx).Debug("API request: cmd=%s", cmd)
+	}
+
+	var buf bytes.Buffer
+	if c.legacy.Load() {
+		err = c.postLegacy(ctx, requests, &buf)
+	} else {
+		err = c.post(ctx, cmd, token, reqBody, &buf)
+	}
+	if err != nil {
+		return err
+	}
+	respBody := buf.Bytes()
+
+	// Unmarshal response
+	if err := c.unmarshal(respBody, response); err != nil {
+		c.log(ctx).Error("failed to unmarshal response: %v", err)
+		return fmt.Errorf("failed to unmarshal response: %w, body: %s", err, string(respBody))
+	}
+	captureRaw(ctx, respBody, false)
+
+	if c.cache != nil {
+		if cacheKey != "" && responsesOK(respBody) {
+			c.cache.Set(cacheKey, respBody, cacheTTL)
+		}
+		c.invalidateAfter(requests, respBody)
+	}
+
+	// A rejected token must not be reused by the next run
+	if c.state != nil && token != "" && sessionRejected(respBody) {
+		c.updateState(ctx, func(s *HostState) {
+			if s.Token == token {
+				s.Token = ""
+				s.TokenExpiry = time.Time{}
+			}
+		})
+	}
+
+	return nil
+}
+
+// post sends a JSON request body for cmd and reads the response body into
+// dst. It is the transport shared by do and the polling fast path.
+func (c *Client) post(ctx context.Context, cmd, token string, body []byte, dst *bytes.Buffer) error {
+	httpResp, err := c.send(ctx, cmd, token, body)
+	if err != nil {
+		return err
+	}
+	respBody := c.closeOnCancel(ctx, httpResp.Body)
+	defer respBody.Close()
+
+	// Read response body
+	dst.Reset()
+	if _, err := dst.ReadFrom(respBody); err != nil {
+		c.log(ctx).Error("failed to read response: %v", err)
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	c.log(ctx).Debug("API response: status=%d, body_len=%d", httpResp.StatusCode, dst.Len())
+	return nil
+}
+
+// send sends a JSON request body for cmd and returns the response once its
+// status is known to be OK. The caller must close the response body.
+func (c *Client) send(ctx context.Context, cmd, token string, body []byte) (*http.Response, error) {
+	// Build URL with cmd parameter
+	url := c.quirkBaseURL(ctx, cmd)
+	if cmd != "" {
+		url = fmt.Sprintf("%s?cmd=%s", url, cmd)
 		if token != "" {
 			url = fmt.Sprintf("%s&token=%s", url, token)
 		}
-		c.logger.Debug("API request: cmd=%s", requests[0].Cmd)
 	}
 
 	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
-		c.logger.Error("failed to create request: %v", err)
-		return fmt.Errorf("failed to create request: %w", err)
+		c.log(ctx).Error("failed to create request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	c.setHeaders(httpReq)
 
-	// Execute request
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		c.logger.Error("failed to execute request: %v", err)
-		return fmt.Errorf("failed to execute request: %w", err)
+	if err := c.signRequest(httpReq, body); err != nil {
+		return nil, err
 	}
-	defer httpResp.Body.Close()
 
-	// Read response body
-	respBody, err := io.ReadAll(httpResp.Body)
+	// Execute request
+	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		c.logger.Error("failed to read response: %v", err)
-		return fmt.Errorf("failed to read response: %w", err)
+		c.log(ctx).Error("failed to execute request: %v", err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 
-	c.logger.Debug("API response: status=%d, body_len=%d", httpResp.StatusCode, len(respBody))
-
 	// Check HTTP status
 	if httpResp.StatusCode != http.StatusOK {
-		c.logger.Warn("unexpected status code: %d", httpResp.StatusCode)
-		return fmt.Errorf("unexpected status code: %d, body: %s", httpResp.StatusCode, string(respBody))
+		defer httpResp.Body.Close()
+		respBody, _ := io.ReadAll(httpResp.Body)
+		c.log(ctx).Warn("unexpected status code: %d", httpResp.StatusCode)
+		return nil, &statusError{code: httpResp.StatusCode, body: string(respBody)}
 	}
+	return httpResp, nil
+}
 
-	// Unmarshal response
-	if err := json.Unmarshal(respBody, response); err != nil {
-		c.logger.Error("failed to unmarshal response: %v", err)
-		return fmt.Errorf("failed to unmarshal response: %w, body: %s", err, string(respBody))
+// statusError is returned for API responses with an HTTP status other
+// than 200
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d, body: %s", e.code, e.body)
+}
+
+// log returns the client's logger bound to ctx, so loggers implementing
+// logger.LoggerWithContext can include request-scoped values
+func (c *Client) log(ctx context.Context) logger.Logger {
+	l := logger.FromContext(ctx, c.logger)
+	if c.debugLog != nil {
+		return debugTee{next: l, capture: c.debugLog}
 	}
+	return l
+}
 
-	return nil
+// setHeaders applies the configured User-Agent and extra headers to req
+func (c *Client) setHeaders(req *http.Request) {
+	userAgent := c.userAgent
+	if userAgent == "" {
+		userAgent = UserAgent()
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
 }
 
 // Login authenticates with the camera and obtains a token
@@ -189,7 +386,15 @@ func (c *Client) Login(ctx context.Context) error {
 		return fmt.Errorf("username and password are required")
 	}
 
-	c.logger.Info("logging in to camera at %s", c.host)
+	if c.legacy.Load() {
+		return nil // No sessions; every request carries the credentials
+	}
+
+	if c.restoreSession(ctx) {
+		return nil
+	}
+
+	c.log(ctx).Info("logging in to camera at %s", c.Host())
 
 	req := []Request{{
 		Cmd: "Login",
@@ -204,7 +409,10 @@ func (c *Client) Login(ctx context.Context) error {
 
 	var resp []Response
 	if err := c.do(ctx, req, &resp); err != nil {
-		c.logger.Error("login failed: %v", err)
+		if ctx.Err() == nil && legacyCandidate(err) && c.detectLegacy(ctx) {
+			return nil
+		}
+		c.log(ctx).Error("login failed: %v", err)
 		return fmt.Errorf("login request failed: %w", err)
 	}
 
@@ -214,13 +422,16 @@ func (c *Client) Login(ctx context.Context) error {
 
 	// Check for errors
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		c.logger.Error("login failed with API error: %v", apiErr)
+		if (apiErr.RspCode == ErrCodeNotSupported || apiErr.RspCode == ErrCodeCommandError) && c.detectLegacy(ctx) {
+			return nil
+		}
+		c.log(ctx).Error("login failed with API error: %v", apiErr)
 		return apiErr
 	}
 
 	// Parse login response
 	var loginValue LoginValue
-	if err := json.Unmarshal(resp[0].Value, &loginValue); err != nil {
+	if err := c.unmarshal(resp[0].Value, &loginValue); err != nil {
 		return fmt.Errorf("failed to parse login response: %w", err)
 	}
 
@@ -229,14 +440,23 @@ func (c *Client) Login(ctx context.Context) error {
 	c.token = loginValue.Token.Name
 	c.tokenMu.Unlock()
 
-	c.logger.Info("successfully logged in, token lease time: %d seconds", loginValue.Token.LeaseTime)
+	c.updateState(ctx, func(s *HostState) {
+		s.Token = loginValue.Token.Name
+		s.TokenExpiry = time.Now().Add(time.Duration(loginValue.Token.LeaseTime) * time.Second)
+	})
+
+	c.log(ctx).Info("successfully logged in, token lease time: %d seconds", loginValue.Token.LeaseTime)
 
 	return nil
 }
 
 // Logout invalidates the current token
 func (c *Client) Logout(ctx context.Context) error {
-	c.logger.Info("logging out from camera at %s", c.host)
+	if c.legacy.Load() {
+		return nil
+	}
+
+	c.log(ctx).Info("logging out from camera at %s", c.Host())
 
 	req := []Request{{
 		Cmd: "Logout",
@@ -244,7 +464,7 @@ func (c *Client) Logout(ctx context.Context) error {
 
 	var resp []Response
 	if err := c.do(ctx, req, &resp); err != nil {
-		c.logger.Error("logout failed: %v", err)
+		c.log(ctx).Error("logout failed: %v", err)
 		return fmt.Errorf("logout request failed: %w", err)
 	}
 
@@ -254,7 +474,7 @@ func (c *Client) Logout(ctx context.Context) error {
 
 	// Check for errors
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		c.logger.Error("logout failed with API error: %v", apiErr)
+		c.log(ctx).Error("logout failed with API error: %v", apiErr)
 		return apiErr
 	}
 
@@ -263,7 +483,12 @@ func (c *Client) Logout(ctx context.Context) error {
 	c.token = ""
 	c.tokenMu.Unlock()
 
-	c.logger.Info("successfully logged out")
+	c.updateState(ctx, func(s *HostState) {
+		s.Token = ""
+		s.TokenExpiry = time.Time{}
+	})
+
+	c.log(ctx).Info("successfully logged out")
 
 	return nil
 }
@@ -291,10 +516,14 @@ func (c *Client) IsAuthenticated() bool {
 
 // Host returns the camera host address
 func (c *Client) Host() string {
+	c.endpointMu.RLock()
+	defer c.endpointMu.RUnlock()
 	return c.host
 }
 
 // BaseURL returns the base API URL
 func (c *Client) BaseURL() string {
+	c.endpointMu.RLock()
+	defer c.endpointMu.RUnlock()
 	return c.baseURL
 }
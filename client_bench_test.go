@@ -0,0 +1,91 @@
+package reolink
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+)
+
+// labelTransport records the reolink_cmd pprof label of each request
+type labelTransport struct {
+	labels []string
+}
+
+func (l *labelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	label, _ := pprof.Label(req.Context(), "reolink_cmd")
+	l.labels = append(l.labels, label)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClient_PprofLabels(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"GetDevInfo": `{"DevInfo": {}}`})
+	client := server.client()
+	transport := &labelTransport{}
+	client.httpClient = &http.Client{Transport: transport}
+
+	client.System.GetDeviceInfo(t.Context())
+	client.Encoding.Snap(t.Context(), 0)
+
+	if len(transport.labels) != 2 || transport.labels[0] != "GetDevInfo" || transport.labels[1] != "Snap" {
+		t.Errorf("expected GetDevInfo and Snap labels, got %v", transport.labels)
+	}
+}
+
+func BenchmarkClientDo(b *testing.B) {
+	server := newUnstartedCmdServer(map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A", "firmVer": "v3.1.0.2174", "channelNum": 1}}`,
+	})
+	server.Start()
+	defer server.Close()
+	client := newTestClient(server.Server)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var resp []Response
+		if err := client.do(b.Context(), []Request{{Cmd: "GetDevInfo"}}, &resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkClientDo_Batch(b *testing.B) {
+	server := newUnstartedCmdServer(map[string]string{
+		"GetMdState": `{"state": 0}`,
+	})
+	server.Start()
+	defer server.Close()
+	client := newTestClient(server.Server)
+
+	reqs := make([]Request, 16)
+	for i := range reqs {
+		reqs[i] = Request{Cmd: "GetMdState", Param: map[string]interface{}{"channel": i}}
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var resp []Response
+		if err := client.do(b.Context(), reqs, &resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSnap(b *testing.B) {
+	image := append([]byte{0xff, 0xd8}, bytes.Repeat([]byte{0x55}, 256<<10)...)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(image)
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	b.SetBytes(int64(len(image)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := client.Encoding.Snap(b.Context(), 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
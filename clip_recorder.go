@@ -0,0 +1,378 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FLV tag types
+const (
+	flvTagAudio  = 8
+	flvTagVideo  = 9
+	flvTagScript = 18
+)
+
+// flvHeaderSize is the size of the FLV file header, excluding the first
+// PreviousTagSize field
+const flvHeaderSize = 9
+
+// flvDefaultHeader is written when the stream's own header was not seen
+// (audio and video present)
+var flvDefaultHeader = []byte{'F', 'L', 'V', 1, 0x05, 0, 0, 0, flvHeaderSize}
+
+// flvTag is one buffered FLV tag
+type flvTag struct {
+	typ       byte
+	timestamp uint32 // Stream timestamp in milliseconds
+	data      []byte
+	arrived   time.Time
+}
+
+// keyframe reports whether t is a video keyframe
+func (t *flvTag) keyframe() bool {
+	return t.typ == flvTagVideo && len(t.data) > 0 && t.data[0]>>4 == 1
+}
+
+// sequenceHeader reports whether t is an AVC or AAC sequence header, which
+// decoders need before any other frame
+func (t *flvTag) sequenceHeader() bool {
+	switch t.typ {
+	case flvTagVideo:
+		return len(t.data) > 1 && t.data[0]&0x0f == 7 && t.data[1] == 0
+	case flvTagAudio:
+		return len(t.data) > 1 && t.data[0]>>4 == 10 && t.data[1] == 0
+	}
+	return false
+}
+
+// ClipRecorderOptions configures a ClipRecorder
+type ClipRecorderOptions struct {
+	// Channel is the camera channel to record
+	Channel int
+	// StreamType selects the stream to buffer (default: StreamSub, which
+	// keeps memory use low)
+	StreamType StreamType
+	// Buffer is how much of the stream is kept in memory (default: 30s).
+	// It bounds the pre-event duration a clip can cover.
+	Buffer time.Duration
+	// URL overrides the FLV stream URL (default: Streaming.GetFLVURL)
+	URL string
+}
+
+// ClipRecorder continuously buffers a camera's FLV stream in memory and
+// writes clips spanning a window around events, without needing an NVR or
+// SD card recording. Clips start at a keyframe and are written as FLV.
+type ClipRecorder struct {
+	client *Client
+	opts   ClipRecorderOptions
+	now    func() time.Time
+
+	mu        sync.Mutex
+	header    []byte
+	meta      *flvTag
+	videoSeq  *flvTag
+	audioSeq  *flvTag
+	tags      []flvTag
+	streaming bool
+	updated   chan struct{} // closed and replaced whenever tags are added
+}
+
+// NewClipRecorder creates a clip recorder for client, filling in defaults
+// for unset options. Call Run to start buffering.
+func NewClipRecorder(client *Client, opts ClipRecorderOptions) *ClipRecorder {
+	if opts.StreamType == "" {
+		opts.StreamType = StreamSub
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 30 * time.Second
+	}
+	return &ClipRecorder{
+		client:  client,
+		opts:    opts,
+		now:     time.Now,
+		updated: make(chan struct{}),
+	}
+}
+
+// Run connects to the FLV stream and buffers it until ctx is done or the
+// stream fails. It returns ctx.Err() on cancellation and the stream error
+// otherwise; callers may call Run again to reconnect.
+func (r *ClipRecorder) Run(ctx context.Context) error {
+	url := r.opts.URL
+	if url == "" {
+		url = r.client.Streaming.GetFLVURL(r.opts.StreamType, r.opts.Channel)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create stream request: %w", err)
+	}
+	r.client.setHeaders(req)
+	if err := r.client.signRequest(req, nil); err != nil {
+		return err
+	}
+
+	// The stream is long-lived, so the client's request timeout must not apply
+	httpClient := *r.client.httpClient
+	httpClient.Timeout = 0
+
+	r.client.log(ctx).Info("starting clip recorder: channel=%d stream=%s", r.opts.Channel, r.opts.StreamType)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("stream request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	err = r.consume(resp.Body)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	r.client.log(ctx).Warn("clip recorder stream ended: %v", err)
+	return err
+}
+
+// consume reads an FLV stream into the buffer until it ends
+func (r *ClipRecorder) consume(body io.Reader) error {
+	r.setStreaming(true)
+	defer r.setStreaming(false)
+
+	header := make([]byte, flvHeaderSize+4)
+	if _, err := io.ReadFull(body, header); err != nil {
+		return fmt.Errorf("failed to read FLV header: %w", err)
+	}
+	if !bytes.HasPrefix(header, []byte("FLV")) {
+		return fmt.Errorf("not an FLV stream")
+	}
+	r.mu.Lock()
+	r.header = header[:flvHeaderSize]
+	r.mu.Unlock()
+
+	tagHeader := make([]byte, 11)
+	for {
+		if _, err := io.ReadFull(body, tagHeader); err != nil {
+			return fmt.Errorf("failed to read FLV tag: %w", err)
+		}
+		size := int(tagHeader[1])<<16 | int(tagHeader[2])<<8 | int(tagHeader[3])
+		timestamp := uint32(tagHeader[7])<<24 | uint32(tagHeader[4])<<16 | uint32(tagHeader[5])<<8 | uint32(tagHeader[6])
+
+		// Tag data followed by its PreviousTagSize field
+		data := make([]byte, size+4)
+		if _, err := io.ReadFull(body, data); err != nil {
+			return fmt.Errorf("failed to read FLV tag: %w", err)
+		}
+
+		r.add(flvTag{
+			typ:       tagHeader[0] & 0x1f,
+			timestamp: timestamp,
+			data:      data[:size],
+			arrived:   r.now(),
+		})
+	}
+}
+
+// add buffers a tag and trims the buffer to the configured duration,
+// keeping it starting at a keyframe
+func (r *ClipRecorder) add(tag flvTag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case tag.typ == flvTagScript:
+		r.meta = &tag
+		return
+	case tag.sequenceHeader() && tag.typ == flvTagVideo:
+		r.videoSeq = &tag
+		return
+	case tag.sequenceHeader():
+		r.audioSeq = &tag
+		return
+	}
+
+	r.tags = append(r.tags, tag)
+
+	cutoff := tag.arrived.Add(-r.opts.Buffer)
+	drop := 0
+	for i := range r.tags {
+		if r.tags[i].arrived.After(cutoff) {
+			break
+		}
+		if r.tags[i].keyframe() {
+			drop = i
+		}
+	}
+	if drop > 0 {
+		r.tags = append(r.tags[:0:0], r.tags[drop:]...)
+	}
+
+	close(r.updated)
+	r.updated = make(chan struct{})
+}
+
+// setStreaming records whether a stream is being consumed and wakes waiters
+func (r *ClipRecorder) setStreaming(streaming bool) {
+	r.mu.Lock()
+	r.streaming = streaming
+	close(r.updated)
+	r.updated = make(chan struct{})
+	r.mu.Unlock()
+}
+
+// Buffered returns the time span currently held in the buffer
+func (r *ClipRecorder) Buffered() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.tags) == 0 {
+		return 0
+	}
+	return r.tags[len(r.tags)-1].arrived.Sub(r.tags[0].arrived)
+}
+
+// SaveClip writes an FLV clip covering pre before now through post after
+// now to w. It blocks until post has elapsed (or the stream stops) so the
+// clip includes the footage after the trigger. The clip starts at the last
+// keyframe at or before the window start, or the oldest buffered keyframe
+// if the buffer does not reach back that far.
+func (r *ClipRecorder) SaveClip(ctx context.Context, w io.Writer, pre, post time.Duration) error {
+	trigger := r.now()
+	start, end := trigger.Add(-pre), trigger.Add(post)
+
+	for {
+		r.mu.Lock()
+		done := !r.streaming || (len(r.tags) > 0 && !r.tags[len(r.tags)-1].arrived.Before(end))
+		updated := r.updated
+		r.mu.Unlock()
+		if done {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-updated:
+		}
+	}
+
+	r.mu.Lock()
+	header := r.header
+	if header == nil {
+		header = flvDefaultHeader
+	}
+	var prefix []flvTag
+	for _, t := range []*flvTag{r.meta, r.videoSeq, r.audioSeq} {
+		if t != nil {
+			prefix = append(prefix, *t)
+		}
+	}
+	first := -1
+	for i := range r.tags {
+		if !r.tags[i].keyframe() {
+			continue
+		}
+		if first == -1 || !r.tags[i].arrived.After(start) {
+			first = i
+		}
+		if r.tags[i].arrived.After(start) {
+			break
+		}
+	}
+	var clip []flvTag
+	if first >= 0 {
+		for _, t := range r.tags[first:] {
+			if t.arrived.After(end) {
+				break
+			}
+			clip = append(clip, t)
+		}
+	}
+	r.mu.Unlock()
+
+	if len(clip) == 0 {
+		return fmt.Errorf("no keyframe buffered")
+	}
+
+	r.client.log(ctx).Info("saving clip: channel=%d tags=%d", r.opts.Channel, len(clip))
+	return writeFLV(w, header, prefix, clip)
+}
+
+// OnEvent returns an EventHandler that saves a clip around each event whose
+// type is in triggers (all events if none are given). open is called with
+// the event to obtain the destination, which is closed after writing. Clips
+// are saved in the background; failures are logged.
+func (r *ClipRecorder) OnEvent(ctx context.Context, pre, post time.Duration, open func(Event) (io.WriteCloser, error), triggers ...EventType) EventHandler {
+	return func(ev Event) {
+		if len(triggers) > 0 {
+			match := false
+			for _, t := range triggers {
+				match = match || t == ev.Type
+			}
+			if !match {
+				return
+			}
+		}
+
+		go func() {
+			w, err := open(ev)
+			if err != nil {
+				r.client.log(ctx).Error("failed to open clip destination: %v", err)
+				return
+			}
+			err = r.SaveClip(ctx, w, pre, post)
+			if cerr := w.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil && !errors.Is(err, context.Canceled) {
+				r.client.log(ctx).Error("failed to save clip: %v", err)
+			}
+		}()
+	}
+}
+
+// writeFLV writes an FLV file with the prefix tags at timestamp 0 followed
+// by tags rebased so the first starts at 0
+func writeFLV(w io.Writer, header []byte, prefix, tags []flvTag) error {
+	buf := make([]byte, 0, len(header)+4)
+	buf = append(buf, header...)
+	buf = append(buf, 0, 0, 0, 0)
+	if _, err := w.Write(buf); err != nil {
+		return fmt.Errorf("failed to write clip: %w", err)
+	}
+
+	base := tags[0].timestamp
+	for i, t := range append(prefix, tags...) {
+		// Audio interleaved right after the keyframe may be stamped
+		// slightly earlier; clamp it to the start
+		ts := uint32(0)
+		if i >= len(prefix) && t.timestamp > base {
+			ts = t.timestamp - base
+		}
+		if err := writeFLVTag(w, t.typ, ts, t.data); err != nil {
+			return fmt.Errorf("failed to write clip: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeFLVTag writes a single tag and its PreviousTagSize field
+func writeFLVTag(w io.Writer, typ byte, timestamp uint32, data []byte) error {
+	size := len(data)
+	buf := make([]byte, 0, 11+size+4)
+	buf = append(buf,
+		typ,
+		byte(size>>16), byte(size>>8), byte(size),
+		byte(timestamp>>16), byte(timestamp>>8), byte(timestamp), byte(timestamp>>24),
+		0, 0, 0)
+	buf = append(buf, data...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(11+size))
+	_, err := w.Write(buf)
+	return err
+}
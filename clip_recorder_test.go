@@ -0,0 +1,195 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testFLVTag describes a tag for buildFLV
+type testFLVTag struct {
+	typ       byte
+	timestamp uint32
+	data      []byte
+}
+
+var (
+	flvScript    = testFLVTag{flvTagScript, 0, []byte{0x02, 0x00, 0x0a}}
+	flvAVCHeader = testFLVTag{flvTagVideo, 0, []byte{0x17, 0x00, 0xaa}}
+	flvAACHeader = testFLVTag{flvTagAudio, 0, []byte{0xaf, 0x00, 0xbb}}
+)
+
+func flvKeyframe(ts uint32) testFLVTag {
+	return testFLVTag{flvTagVideo, ts, []byte{0x17, 0x01, byte(ts)}}
+}
+
+func flvInter(ts uint32) testFLVTag {
+	return testFLVTag{flvTagVideo, ts, []byte{0x27, 0x01, byte(ts)}}
+}
+
+// buildFLV encodes a complete FLV stream
+func buildFLV(tags ...testFLVTag) []byte {
+	var buf bytes.Buffer
+	buf.Write(flvDefaultHeader)
+	buf.Write([]byte{0, 0, 0, 0})
+	for _, t := range tags {
+		writeFLVTag(&buf, t.typ, t.timestamp, t.data)
+	}
+	return buf.Bytes()
+}
+
+// parseFLV decodes an FLV stream written by writeFLV
+func parseFLV(t *testing.T, data []byte) []testFLVTag {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("FLV")) {
+		t.Fatalf("missing FLV header")
+	}
+	data = data[flvHeaderSize+4:]
+	var tags []testFLVTag
+	for len(data) > 0 {
+		size := int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+		ts := uint32(data[7])<<24 | uint32(data[4])<<16 | uint32(data[5])<<8 | uint32(data[6])
+		tags = append(tags, testFLVTag{data[0], ts, data[11 : 11+size]})
+		if prev := binary.BigEndian.Uint32(data[11+size:]); prev != uint32(11+size) {
+			t.Fatalf("bad PreviousTagSize %d for tag of size %d", prev, size)
+		}
+		data = data[11+size+4:]
+	}
+	return tags
+}
+
+// fakeClock advances by step on every call
+type fakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func TestClipRecorder_SaveClip(t *testing.T) {
+	recorder := NewClipRecorder(newCmdServer(t, nil).client(), ClipRecorderOptions{Buffer: time.Hour})
+	clock := &fakeClock{now: time.Unix(0, 0), step: time.Second}
+	recorder.now = clock.Now
+
+	stream := buildFLV(
+		flvScript, flvAVCHeader, flvAACHeader,
+		flvKeyframe(1000), flvInter(2000), flvInter(3000),
+		flvKeyframe(4000), flvInter(5000), flvInter(6000),
+	)
+	if err := recorder.consume(bytes.NewReader(stream)); err == nil {
+		t.Fatal("expected error at end of stream")
+	}
+
+	// Tags arrived at t=1s..9s with the keyframes at t=4s and t=7s. The
+	// stream has stopped, so SaveClip does not wait for post footage. The
+	// trigger is at t=10s, so a 3s pre-window starts at the second keyframe.
+	var out bytes.Buffer
+	if err := recorder.SaveClip(t.Context(), &out, 3*time.Second, 0); err != nil {
+		t.Fatalf("SaveClip failed: %v", err)
+	}
+
+	tags := parseFLV(t, out.Bytes())
+	if len(tags) != 6 {
+		t.Fatalf("expected 3 prefix tags and 3 frames, got %d tags", len(tags))
+	}
+	if tags[0].typ != flvTagScript || !bytes.Equal(tags[1].data, flvAVCHeader.data) || !bytes.Equal(tags[2].data, flvAACHeader.data) {
+		t.Error("expected script data and sequence headers first")
+	}
+	frames := tags[3:]
+	if frames[0].data[0]>>4 != 1 {
+		t.Error("expected clip to start at a keyframe")
+	}
+	for i, want := range []uint32{0, 1000, 2000} {
+		if frames[i].timestamp != want {
+			t.Errorf("frame %d: expected rebased timestamp %d, got %d", i, want, frames[i].timestamp)
+		}
+	}
+}
+
+func TestClipRecorder_FallsBackToOldestKeyframe(t *testing.T) {
+	recorder := NewClipRecorder(newCmdServer(t, nil).client(), ClipRecorderOptions{Buffer: time.Hour})
+	recorder.now = (&fakeClock{now: time.Unix(0, 0), step: time.Second}).Now
+
+	recorder.consume(bytes.NewReader(buildFLV(flvInter(0), flvKeyframe(1000), flvInter(2000))))
+
+	var out bytes.Buffer
+	if err := recorder.SaveClip(t.Context(), &out, time.Hour, 0); err != nil {
+		t.Fatalf("SaveClip failed: %v", err)
+	}
+	tags := parseFLV(t, out.Bytes())
+	if len(tags) != 2 || tags[0].data[0]>>4 != 1 {
+		t.Errorf("expected clip from the oldest keyframe, got %d tags", len(tags))
+	}
+}
+
+func TestClipRecorder_NoKeyframe(t *testing.T) {
+	recorder := NewClipRecorder(newCmdServer(t, nil).client(), ClipRecorderOptions{})
+	recorder.consume(bytes.NewReader(buildFLV(flvInter(0))))
+
+	if err := recorder.SaveClip(t.Context(), io.Discard, time.Second, 0); err == nil {
+		t.Error("expected error without a buffered keyframe")
+	}
+}
+
+func TestClipRecorder_TrimsBuffer(t *testing.T) {
+	recorder := NewClipRecorder(newCmdServer(t, nil).client(), ClipRecorderOptions{Buffer: 3 * time.Second})
+	recorder.now = (&fakeClock{now: time.Unix(0, 0), step: time.Second}).Now
+
+	recorder.consume(bytes.NewReader(buildFLV(
+		flvKeyframe(0), flvInter(1000), flvKeyframe(2000), flvInter(3000), flvInter(4000), flvInter(5000),
+	)))
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.tags) != 4 || !recorder.tags[0].keyframe() {
+		t.Errorf("expected buffer trimmed to the keyframe at ts 2000, got %d tags", len(recorder.tags))
+	}
+}
+
+func TestClipRecorder_Run(t *testing.T) {
+	stream := buildFLV(flvAVCHeader, flvKeyframe(0), flvInter(40))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/x-flv")
+		w.Write(stream)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	recorder := NewClipRecorder(newTestClient(server), ClipRecorderOptions{URL: server.URL + "/flv"})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- recorder.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		recorder.mu.Lock()
+		n := len(recorder.tags)
+		recorder.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for buffered tags, have %d", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Cloud represents the Reolink Cloud account binding of the device.
+//
+// The API guide lists GetCloud and SetCloud without documenting their
+// fields; Raw holds the full value for firmware that reports more.
+type Cloud struct {
+	Bind     int    `json:"bind"`               // 1 if the device is bound to a cloud account
+	UserName string `json:"userName,omitempty"` // Bound account, if the firmware reports it
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// Bound reports whether the device is bound to a Reolink Cloud account
+func (c *Cloud) Bound() bool {
+	return c.Bind == 1
+}
+
+// GetCloud gets the Reolink Cloud account binding status. Firmware without
+// cloud support returns an APIError with ErrCodeNotSupported or
+// ErrCodeCloudNotSupported.
+func (n *NetworkAPI) GetCloud(ctx context.Context) (*Cloud, error) {
+	n.client.log(ctx).Debug("getting cloud binding status")
+
+	req := []Request{{
+		Cmd: "GetCloud",
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to get cloud binding status: %v", err)
+		return nil, fmt.Errorf("GetCloud request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetCloud")
+		n.client.log(ctx).Error("failed to get cloud binding status: %v", err)
+		return nil, err
+	}
+
+	if err := resp[0].ToAPIError(); err != nil {
+		n.client.log(ctx).Error("failed to get cloud binding status: %v", err)
+		return nil, err
+	}
+
+	var value struct {
+		Cloud json.RawMessage `json:"Cloud"`
+	}
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse cloud binding status response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetCloud response: %w", err)
+	}
+	cloud := &Cloud{Raw: value.Cloud}
+	if len(value.Cloud) > 0 {
+		if err := n.client.unmarshal(value.Cloud, cloud); err != nil {
+			n.client.log(ctx).Error("failed to parse cloud binding status response: %v", err)
+			return nil, fmt.Errorf("failed to parse GetCloud response: %w", err)
+		}
+	}
+
+	n.client.log(ctx).Info("successfully retrieved cloud binding status: bind=%d", cloud.Bind)
+	return cloud, nil
+}
+
+// UnbindCloud unbinds the device from its Reolink Cloud account. Cameras
+// that only allow unbinding from the app return an APIError with
+// ErrCodeCloudUnbindFailed or ErrCodeNotSupported.
+func (n *NetworkAPI) UnbindCloud(ctx context.Context) error {
+	n.client.log(ctx).Info("unbinding cloud account")
+
+	req := []Request{{
+		Cmd: "SetCloud",
+		Param: map[string]interface{}{
+			"Cloud": map[string]interface{}{
+				"bind": 0,
+			},
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to unbind cloud account: %v", err)
+		return fmt.Errorf("SetCloud request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetCloud")
+		n.client.log(ctx).Error("failed to unbind cloud account: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		n.client.log(ctx).Error("failed to unbind cloud account: %v", apiErr)
+		return apiErr
+	}
+
+	n.client.log(ctx).Info("successfully unbound cloud account")
+	return nil
+}
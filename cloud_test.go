@@ -0,0 +1,48 @@
+package reolink
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNetworkAPI_GetCloud(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetCloud": `{"Cloud": {"bind": 1, "userName": "owner@example.com", "region": "eu"}}`,
+		"SetCloud": "",
+	})
+	client := server.client()
+
+	cloud, err := client.Network.GetCloud(t.Context())
+	if err != nil {
+		t.Fatalf("GetCloud failed: %v", err)
+	}
+	if !cloud.Bound() || cloud.UserName != "owner@example.com" {
+		t.Errorf("unexpected cloud status: %+v", cloud)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(cloud.Raw, &raw); err != nil || raw["region"] != "eu" {
+		t.Errorf("unexpected raw value: %s", cloud.Raw)
+	}
+
+	if err := client.Network.UnbindCloud(t.Context()); err != nil {
+		t.Fatalf("UnbindCloud failed: %v", err)
+	}
+	cloud, err = client.Network.GetCloud(t.Context())
+	if err != nil {
+		t.Fatalf("GetCloud failed: %v", err)
+	}
+	if cloud.Bound() {
+		t.Error("expected the device to be unbound")
+	}
+}
+
+func TestNetworkAPI_GetCloud_NotSupported(t *testing.T) {
+	client := newCmdServer(t, nil).client()
+
+	_, err := client.Network.GetCloud(t.Context())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RspCode != ErrCodeNotSupported {
+		t.Errorf("expected not supported error, got %v", err)
+	}
+}
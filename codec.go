@@ -0,0 +1,323 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Codec encodes requests and decodes responses. The default is
+// encoding/json; a faster drop-in library such as jsoniter or sonic can be
+// plugged in with WithCodec where JSON handling dominates CPU, e.g. when
+// polling motion state on many NVR channels every second. Implementations
+// must honour encoding/json struct tags and json.RawMessage.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdCodec is the Codec backed by encoding/json
+type StdCodec struct{}
+
+// Marshal implements Codec
+func (StdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec
+func (StdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// marshal encodes v with the client's codec
+func (c *Client) marshal(v interface{}) ([]byte, error) {
+	if c.codec == nil {
+		return json.Marshal(v)
+	}
+	return c.codec.Marshal(v)
+}
+
+// DecodeMode selects how responses that do not match the SDK's models are
+// handled, see WithDecodeMode
+type DecodeMode int
+
+const (
+	// DecodeLenient accepts numbers and strings in place of each other, as
+	// some firmware returns them, and logs a warning when it does so or
+	// when a response type first shows fields the SDK does not model
+	DecodeLenient DecodeMode = iota
+	// DecodeStrict fails on unknown fields and mismatched types, to catch
+	// model drift when testing against recorded responses
+	DecodeStrict
+)
+
+// unmarshal decodes data into v with the client's codec and decode mode
+func (c *Client) unmarshal(data []byte, v interface{}) error {
+	err := c.decode(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && c.decodeMode == DecodeLenient {
+		coerced, cerr := coerceJSON(data, reflect.TypeOf(v))
+		if cerr != nil || c.decode(coerced, v) != nil {
+			return err
+		}
+		c.log(context.Background()).Warn("decoded %T after converting mismatched types: %v", v, err)
+		c.decodeMismatched.Store(reflect.TypeOf(v), struct{}{})
+		return c.checkUnknownFields(coerced, v)
+	}
+	if err != nil {
+		return err
+	}
+	return c.checkUnknownFields(data, v)
+}
+
+// decode decodes data into v with the client's codec
+func (c *Client) decode(data []byte, v interface{}) error {
+	if c.codec == nil {
+		return json.Unmarshal(data, v)
+	}
+	return c.codec.Unmarshal(data, v)
+}
+
+// checkUnknownFields reports fields of data that v's type does not model:
+// as an error in strict mode, and otherwise as a warning the first time a
+// type is decoded
+func (c *Client) checkUnknownFields(data []byte, v interface{}) error {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Pointer {
+		return nil
+	}
+	if c.decodeMode == DecodeLenient {
+		if _, seen := c.decodeChecked.LoadOrStore(t, struct{}{}); seen {
+			return nil
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil // Decoding already succeeded, so this cannot fail
+	}
+	unknown := unknownFields(tree, t.Elem(), "")
+	if len(unknown) == 0 {
+		return nil
+	}
+	if c.decodeMode == DecodeStrict {
+		return fmt.Errorf("strict decoding of %s: unknown fields %s", t.Elem(), strings.Join(unknown, ", "))
+	}
+	c.log(context.Background()).Warn("response for %s has data the SDK does not model: %s", t.Elem(), strings.Join(unknown, ", "))
+	return nil
+}
+
+// streamDecodable reports whether a response decoding into v can be
+// decoded as it arrives. Decode modes need the raw body for values of a
+// type lenient mode has not checked for unknown fields yet, or has seen
+// with mismatched types, and for every value in strict mode.
+func (c *Client) streamDecodable(v interface{}) bool {
+	if c.decodeMode == DecodeStrict {
+		return false
+	}
+	t := reflect.TypeOf(v)
+	if _, checked := c.decodeChecked.Load(t); !checked {
+		return false
+	}
+	_, mismatched := c.decodeMismatched.Load(t)
+	return !mismatched
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonShaper is implemented by response types with their own UnmarshalJSON
+// that read fixed fields, so that decode modes can look through it.
+// jsonShape returns the type the JSON value v, decoded with UseNumber, is
+// read as. Other types with their own UnmarshalJSON, such as FlexInt and
+// the AI switch maps, accept any keys and quoted numbers themselves.
+type jsonShaper interface {
+	jsonShape(v interface{}) reflect.Type
+}
+
+// unmarshalerShape returns the type decode modes treat t as, and false if
+// t decodes itself in a way they do not look into
+func unmarshalerShape(v interface{}, t reflect.Type) (reflect.Type, bool) {
+	if s, ok := reflect.New(t).Interface().(jsonShaper); ok {
+		return s.jsonShape(v), true
+	}
+	return t, !reflect.PointerTo(t).Implements(jsonUnmarshalerType)
+}
+
+// coerceJSON rewrites data so that quoted numbers, numeric strings and
+// booleans match the kinds of the fields of t they decode into
+func coerceJSON(data []byte, t reflect.Type) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return json.Marshal(coerceValue(tree, t))
+}
+
+// coerceValue converts v, decoded with UseNumber, towards the kind of t.
+// Types with their own UnmarshalJSON handle their formats themselves
+// unless they are a jsonShaper.
+func coerceValue(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	t, ok := unmarshalerShape(v, t)
+	if !ok {
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		switch x := v.(type) {
+		case string:
+			if s := strings.TrimSpace(x); s != "" {
+				if _, err := strconv.ParseFloat(s, 64); err == nil {
+					return json.Number(s)
+				}
+			}
+		case bool:
+			if x {
+				return json.Number("1")
+			}
+			return json.Number("0")
+		}
+	case reflect.String:
+		if n, ok := v.(json.Number); ok {
+			return n.String()
+		}
+	case reflect.Bool:
+		switch x := v.(type) {
+		case json.Number:
+			return x.String() != "0"
+		case string:
+			if b, err := strconv.ParseBool(x); err == nil {
+				return b
+			}
+		}
+	case reflect.Struct:
+		if m, ok := v.(map[string]interface{}); ok {
+			fields := jsonFields(t)
+			for key, val := range m {
+				if ft, ok := fieldType(fields, key); ok {
+					m[key] = coerceValue(val, ft)
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if a, ok := v.([]interface{}); ok {
+			for i := range a {
+				a[i] = coerceValue(a[i], t.Elem())
+			}
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for key, val := range m {
+				m[key] = coerceValue(val, t.Elem())
+			}
+		}
+	}
+	return v
+}
+
+// unknownFields returns the paths of the object keys in v, decoded with
+// UseNumber, that t does not model, in sorted order
+func unknownFields(v interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	t, ok := unmarshalerShape(v, t)
+	if !ok {
+		return nil
+	}
+
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(t)
+		for _, key := range slices.Sorted(maps.Keys(m)) {
+			ft, ok := fieldType(fields, key)
+			if !ok {
+				unknown = append(unknown, path+key)
+				continue
+			}
+			unknown = append(unknown, unknownFields(m[key], ft, path+key+".")...)
+		}
+	case reflect.Slice, reflect.Array:
+		if a, ok := v.([]interface{}); ok {
+			for i := range a {
+				unknown = append(unknown, unknownFields(a[i], t.Elem(), fmt.Sprintf("%s[%d].", strings.TrimSuffix(path, "."), i))...)
+			}
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for _, key := range slices.Sorted(maps.Keys(m)) {
+				unknown = append(unknown, unknownFields(m[key], t.Elem(), path+key+".")...)
+			}
+		}
+	}
+	return unknown
+}
+
+// fieldType returns the type of the field key decodes into, matching names
+// like encoding/json: exactly, or else case-insensitively
+func fieldType(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if ft, ok := fields[key]; ok {
+		return ft, true
+	}
+	for name, ft := range fields {
+		if strings.EqualFold(name, key) {
+			return ft, true
+		}
+	}
+	return nil, false
+}
+
+// jsonFields returns the types of t's fields by JSON name, including those
+// of embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n, t := range jsonFields(ft) {
+					fields[n] = t
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
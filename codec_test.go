@@ -0,0 +1,119 @@
+package reolink
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingCodec wraps StdCodec and counts calls
+type countingCodec struct {
+	StdCodec
+	marshals, unmarshals atomic.Int32
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals.Add(1)
+	return c.StdCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals.Add(1)
+	return c.StdCodec.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": 1}`,
+	})
+	codec := &countingCodec{}
+	client := server.client()
+	WithCodec(codec)(client)
+
+	state, err := client.Alarm.GetMdState(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("GetMdState failed: %v", err)
+	}
+	if state != 1 {
+		t.Errorf("expected state 1, got %d", state)
+	}
+	// Request body, response envelope and value
+	if codec.marshals.Load() != 1 || codec.unmarshals.Load() != 2 {
+		t.Errorf("expected 1 marshal and 2 unmarshals, got %d and %d",
+			codec.marshals.Load(), codec.unmarshals.Load())
+	}
+}
+
+func TestDecodeMode(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": "1"}`,
+		"GetNetPort": `{"NetPort": {"httpEnable": 1, "httpPort": "80", "httpsEnable": true, "httpsPort": 443, "mediaPort": 9000, "futurePort": 1}}`,
+	})
+
+	lenient := server.client()
+	state, err := lenient.Alarm.GetMdState(t.Context(), 0)
+	if err != nil || state != 1 {
+		t.Errorf("lenient GetMdState = %d, %v", state, err)
+	}
+	port, err := lenient.Network.GetNetPort(t.Context())
+	if err != nil {
+		t.Fatalf("lenient GetNetPort failed: %v", err)
+	}
+	if port.HTTPPort != 80 || port.HTTPSEnable != 1 || port.MediaPort != 9000 {
+		t.Errorf("unexpected coerced port configuration: %+v", port)
+	}
+
+	strict := server.client()
+	WithDecodeMode(DecodeStrict)(strict)
+	if _, err := strict.Network.GetNetPort(t.Context()); err == nil {
+		t.Error("expected strict mode to reject a quoted number")
+	}
+	server.set("GetMdState", `{"state": 1, "extra": 0}`)
+	if _, err := strict.Alarm.GetMdState(t.Context(), 0); err == nil || !strings.Contains(err.Error(), "extra") {
+		t.Errorf("expected strict mode to reject an unknown field, got %v", err)
+	}
+	if _, err := lenient.Alarm.GetMdState(t.Context(), 0); err != nil {
+		t.Errorf("lenient mode rejected an unknown field: %v", err)
+	}
+}
+
+func TestDecodeMode_Streamed(t *testing.T) {
+	const file = `{"name": "a.mp4", "size": 1, "type": "main",
+		"StartTime": {"year": 2024, "mon": 1, "day": 2, "hour": 3, "min": 0, "sec": 0},
+		"EndTime": {"year": 2024, "mon": 1, "day": 2, "hour": 3, "min": 1, "sec": 0}%s}`
+	server := newCmdServer(t, map[string]string{
+		"Search": `{"SearchResult": {"channel": 0, "File": [` + fmt.Sprintf(file, "") + `]}}`,
+	})
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	lenient := server.client()
+	search := func(name string) {
+		t.Helper()
+		results, err := lenient.Recording.Search(t.Context(), 0, from, from.Add(24*time.Hour), "main")
+		if err != nil || len(results) != 1 || results[0].FileName != "a.mp4" {
+			t.Fatalf("lenient Search (%s) = %+v, %v", name, results, err)
+		}
+	}
+	search("checked")
+	search("streamed")
+	if n := server.callCount("Search"); n != 2 {
+		t.Errorf("expected 2 Search requests, got %d", n)
+	}
+	// A quoted channel fails the streamed decode, so the value is requested
+	// again buffered and converted, and is not streamed any more
+	server.set("Search", `{"SearchResult": {"channel": "0", "File": [`+fmt.Sprintf(file, "")+`]}}`)
+	search("mismatched")
+	search("buffered")
+	if n := server.callCount("Search"); n != 5 {
+		t.Errorf("expected 5 Search requests, got %d", n)
+	}
+
+	strict := server.client()
+	WithDecodeMode(DecodeStrict)(strict)
+	server.set("Search", `{"SearchResult": {"channel": 0, "File": [`+fmt.Sprintf(file, `, "codec": "h265"`)+`]}}`)
+	if _, err := strict.Recording.Search(t.Context(), 0, from, from.Add(24*time.Hour), "main"); err == nil || !strings.Contains(err.Error(), "File[0].codec") {
+		t.Errorf("expected strict mode to reject an unknown file field, got %v", err)
+	}
+}
@@ -26,6 +26,23 @@ func WithHTTPS(useHTTPS bool) Option {
 	}
 }
 
+// WithPortGuard makes Network.SetNetPort refuse configurations that
+// disable the transport (HTTP or HTTPS) the client is using
+func WithPortGuard(enabled bool) Option {
+	return func(c *Client) {
+		c.portGuard = enabled
+	}
+}
+
+// WithNameTransliteration makes SetDeviceName transliterate names the
+// device cannot store (non-ASCII names on firmware that mangles them, or
+// names too long in UTF-8) with TransliterateName instead of rejecting them
+func WithNameTransliteration(enabled bool) Option {
+	return func(c *Client) {
+		c.transliterateNames = enabled
+	}
+}
+
 // WithTimeout sets the HTTP client timeout
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Client) {
@@ -76,3 +93,63 @@ func WithLogger(log logger.Logger) Option {
 		}
 	}
 }
+
+// WithUserAgent sets the User-Agent header sent with every request
+// (default: UserAgent())
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader adds a header sent with every request. It may be repeated to
+// add several values for the same key.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+// WithLocale asks the device for error details in a language, e.g. "en" or
+// "de", by sending it as Accept-Language. Firmware that ignores the header
+// keeps answering in its own language; APIError messages include the
+// English description of the code either way.
+func WithLocale(locale string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Set("Accept-Language", locale)
+	}
+}
+
+// WithCodec sets the JSON codec used for requests and responses (default:
+// StdCodec)
+func WithCodec(codec Codec) Option {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
+// WithDecodeMode sets how responses that do not match the SDK's models are
+// handled (default: DecodeLenient). Use DecodeStrict in tests against
+// recorded responses to catch fields and types the models are missing.
+// Both modes also apply to streamed responses such as Search and
+// GetAbility. Types that decode free-form maps, such as Ability and the AI
+// switches and states, accept any keys and quoted numbers in either mode.
+func WithDecodeMode(mode DecodeMode) Option {
+	return func(c *Client) {
+		c.decodeMode = mode
+	}
+}
+
+// WithMetrics sets the recorder that receives gauges and counters from
+// monitoring helpers such as Storage.Forecast
+func WithMetrics(m MetricsRecorder) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
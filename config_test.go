@@ -1,8 +1,13 @@
 package reolink
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -186,3 +191,112 @@ func TestWithToken(t *testing.T) {
 		t.Errorf("expected token '%s', got '%s'", token, client.token)
 	}
 }
+
+func TestWithUserAgentAndHeaders(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"cmd": "GetDevInfo", "code": 0, "value": {"DevInfo": {}}}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL[7:],
+		WithUserAgent("site-nvr-poller/2.1"),
+		WithHeader("X-Site", "warehouse"),
+		WithHeader("X-Tag", "a"),
+		WithHeader("X-Tag", "b"))
+	client.baseURL = server.URL
+
+	if _, err := client.System.GetDeviceInfo(t.Context()); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+
+	if got := headers.Get("User-Agent"); got != "site-nvr-poller/2.1" {
+		t.Errorf("expected custom User-Agent, got %q", got)
+	}
+	if got := headers.Get("X-Site"); got != "warehouse" {
+		t.Errorf("expected X-Site header, got %q", got)
+	}
+	if got := headers.Values("X-Tag"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected both X-Tag values, got %v", got)
+	}
+}
+
+func TestDefaultUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte{0xff, 0xd8})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL[7:])
+	client.baseURL = server.URL
+
+	if _, err := client.Encoding.Snap(t.Context(), 0); err != nil {
+		t.Fatalf("Snap failed: %v", err)
+	}
+	if userAgent != UserAgent() {
+		t.Errorf("expected default User-Agent %q, got %q", UserAgent(), userAgent)
+	}
+}
+
+type traceIDKey struct{}
+
+func TestWithLogger_ContextPropagation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"cmd": "GetDevInfo", "code": 0, "value": {"DevInfo": {}}}]`))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	log := logger.NewContextLogger(logger.NewStdLogger(buf), func(ctx context.Context) string {
+		id, _ := ctx.Value(traceIDKey{}).(string)
+		return id
+	})
+	client := NewClient(server.URL[7:], WithLogger(log))
+	client.baseURL = server.URL
+
+	ctx := context.WithValue(t.Context(), traceIDKey{}, "trace=7f3a")
+	if _, err := client.System.GetDeviceInfo(ctx); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		t.Fatal("expected log output")
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "trace=7f3a") {
+			t.Errorf("expected trace ID in every log line, got: %s", line)
+		}
+	}
+}
+
+func TestWithLocale(t *testing.T) {
+	var language string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		language = r.Header.Get("Accept-Language")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"cmd": "GetDevInfo", "code": 1, "error": {"rspCode": -6, "detail": "请先登录"}}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL[7:], WithLocale("en"))
+	client.baseURL = server.URL
+
+	_, err := client.System.GetDeviceInfo(t.Context())
+	if language != "en" {
+		t.Errorf("expected Accept-Language en, got %q", language)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.Key() != "login_required" || !strings.Contains(err.Error(), "(login required)") {
+		t.Errorf("expected stable key and English description, got %q", err.Error())
+	}
+}
@@ -0,0 +1,157 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DeviceNameMaxLength is the longest device name in bytes. Firmware stores
+// names in a fixed 32-byte field, so longer names are cut, possibly in the
+// middle of a multi-byte character.
+const DeviceNameMaxLength = 31
+
+// ErrDeviceNameMangled is returned by SetDeviceNameVerified when the device
+// stored a different name than the one sent
+var ErrDeviceNameMangled = errors.New("device stored a different name")
+
+// NormalizeDeviceName trims a name, collapses runs of white space into one
+// space and drops control characters and invalid UTF-8
+func NormalizeDeviceName(name string) string {
+	name = strings.ToValidUTF8(name, "")
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// ValidateDeviceName checks a name against the limits firmware enforces or
+// silently breaks on: it must not be empty, must fit DeviceNameMaxLength
+// bytes of UTF-8 and must not contain control characters. With asciiOnly,
+// for firmware that mangles other characters, it must be ASCII as well.
+func ValidateDeviceName(name string, asciiOnly bool) error {
+	if name == "" {
+		return fmt.Errorf("invalid device name: must not be empty")
+	}
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("invalid device name: not valid UTF-8")
+	}
+	if len(name) > DeviceNameMaxLength {
+		return fmt.Errorf("invalid device name: %d bytes, at most %d allowed (non-ASCII characters take 2-4 bytes)", len(name), DeviceNameMaxLength)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("invalid device name: control character %q", r)
+		}
+		if asciiOnly && r >= utf8.RuneSelf {
+			return fmt.Errorf("invalid device name: %q is not ASCII, which this firmware mangles (see WithNameTransliteration)", r)
+		}
+	}
+	return nil
+}
+
+// transliterations spells out letters that do not decompose into an ASCII
+// letter and a combining mark
+var transliterations = map[rune]string{
+	'ß': "ss", 'Æ': "AE", 'æ': "ae", 'Œ': "OE", 'œ': "oe", 'Ø': "O", 'ø': "o",
+	'Đ': "D", 'đ': "d", 'Ð': "D", 'ð': "d", 'Þ': "Th", 'þ': "th", 'Ł': "L",
+	'ł': "l", 'ı': "i", 'Ħ': "H", 'ħ': "h", 'Ŋ': "N", 'ŋ': "n",
+	'‘': "'", '’': "'", '“': `"`, '”': `"`, '–': "-", '—': "-", '…': "...",
+}
+
+// accents maps precomposed Latin letters to their base letter
+var accents = map[rune]rune{}
+
+func init() {
+	for base, letters := range map[rune]string{
+		'A': "ÀÁÂÃÄÅĀĂĄǍ", 'a': "àáâãäåāăąǎ", 'C': "ÇĆĈĊČ", 'c': "çćĉċč",
+		'D': "Ď", 'd': "ď", 'E': "ÈÉÊËĒĔĖĘĚ", 'e': "èéêëēĕėęě",
+		'G': "ĜĞĠĢ", 'g': "ĝğġģ", 'H': "Ĥ", 'h': "ĥ", 'I': "ÌÍÎÏĨĪĬĮİǏ",
+		'i': "ìíîïĩīĭįǐ", 'J': "Ĵ", 'j': "ĵ", 'K': "Ķ", 'k': "ķ",
+		'L': "ĹĻĽĿ", 'l': "ĺļľŀ", 'N': "ÑŃŅŇ", 'n': "ñńņň",
+		'O': "ÒÓÔÕÖŌŎŐǑ", 'o': "òóôõöōŏőǒ", 'R': "ŔŖŘ", 'r': "ŕŗř",
+		'S': "ŚŜŞŠȘ", 's': "śŝşšș", 'T': "ŢŤȚ", 't': "ţťț",
+		'U': "ÙÚÛÜŨŪŬŮŰŲǓ", 'u': "ùúûüũūŭůűųǔ", 'W': "Ŵ", 'w': "ŵ",
+		'Y': "ÝŶŸ", 'y': "ýÿŷ", 'Z': "ŹŻŽ", 'z': "źżž",
+	} {
+		for _, r := range letters {
+			accents[r] = base
+		}
+	}
+}
+
+// TransliterateName returns name in ASCII: accented Latin letters lose
+// their accents, letters such as ß and æ are spelled out, and characters
+// without an ASCII equivalent become '_'. The result is normalized with
+// NormalizeDeviceName and cut to DeviceNameMaxLength bytes.
+func TransliterateName(name string) string {
+	var b strings.Builder
+	for _, r := range NormalizeDeviceName(name) {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks of decomposed letters
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+		case accents[r] != 0:
+			b.WriteRune(accents[r])
+		default:
+			b.WriteByte('_')
+		}
+	}
+	out := b.String()
+	if len(out) > DeviceNameMaxLength {
+		out = strings.TrimSpace(out[:DeviceNameMaxLength])
+	}
+	return out
+}
+
+// prepareDeviceName normalizes name and checks it for the device,
+// transliterating it first if the client is configured to and the device
+// cannot store it as is
+func (c *Client) prepareDeviceName(name string) (string, error) {
+	name = NormalizeDeviceName(name)
+	asciiOnly := c.quirkASCIINames()
+	if c.transliterateNames && ValidateDeviceName(name, asciiOnly) != nil {
+		name = TransliterateName(name)
+	}
+	if err := ValidateDeviceName(name, asciiOnly); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// SetDeviceNameVerified sets the device name like SetDeviceName, reads it
+// back and returns the name the device stored. If that differs from the
+// (normalized or transliterated) name sent, it returns an error wrapping
+// ErrDeviceNameMangled, so firmware that corrupts names is caught at
+// provisioning time.
+func (s *SystemAPI) SetDeviceNameVerified(ctx context.Context, name string) (string, error) {
+	want, err := s.client.prepareDeviceName(name)
+	if err != nil {
+		return "", err
+	}
+	if err := s.SetDeviceName(ctx, want); err != nil {
+		return "", err
+	}
+	stored, err := s.GetDeviceName(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read back device name: %w", err)
+	}
+	if stored != want {
+		err := fmt.Errorf("%w: sent %q, stored %q", ErrDeviceNameMangled, want, stored)
+		s.client.log(ctx).Error("device name mangled: %v", err)
+		return stored, err
+	}
+	return stored, nil
+}
@@ -0,0 +1,117 @@
+package reolink
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDeviceName(t *testing.T) {
+	tests := map[string]string{
+		"  Front   Door ": "Front Door",
+		"Gate\tNorth\n":   "Gate North",
+		"Bad\x00Name\x7f": "BadName",
+		"Gar\xffage":      "Garage",
+		"Einfahrt Süd":    "Einfahrt Süd",
+		"":                "",
+	}
+	for in, want := range tests {
+		if got := NormalizeDeviceName(in); got != want {
+			t.Errorf("NormalizeDeviceName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValidateDeviceName(t *testing.T) {
+	valid := []string{"Front Door", "Einfahrt Süd", strings.Repeat("a", DeviceNameMaxLength)}
+	for _, name := range valid {
+		if err := ValidateDeviceName(name, false); err != nil {
+			t.Errorf("expected %q to be valid: %v", name, err)
+		}
+	}
+	invalid := []string{"", strings.Repeat("a", DeviceNameMaxLength+1), strings.Repeat("ü", 16), "a\x01b"}
+	for _, name := range invalid {
+		if err := ValidateDeviceName(name, false); err == nil {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+	if err := ValidateDeviceName("Einfahrt Süd", true); err == nil {
+		t.Error("expected a non-ASCII name to be rejected for ASCII-only firmware")
+	}
+}
+
+func TestTransliterateName(t *testing.T) {
+	tests := map[string]string{
+		"Einfahrt Süd":                "Einfahrt Sud",
+		"Straße":                      "Strasse",
+		"Café Terrasse":              "Cafe Terrasse",
+		"Łódź – Brama":                "Lodz - Brama",
+		"Kamera 東":                    "Kamera _",
+		strings.Repeat("é", 40):       strings.Repeat("e", DeviceNameMaxLength),
+		"Øresund Bridge Camera Æbelø": "Oresund Bridge Camera AEbelo",
+	}
+	for in, want := range tests {
+		if got := TransliterateName(in); got != want {
+			t.Errorf("TransliterateName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSystemAPI_SetDeviceName_Quirks(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetDevName": `{"DevName": {"name": "Camera"}}`,
+		"SetDevName": "",
+	})
+	client := srv.client()
+	client.quirks = []Quirk{{Name: "ascii-names", ASCIINames: true}}
+
+	if err := client.System.SetDeviceName(t.Context(), "Einfahrt Süd"); err == nil {
+		t.Error("expected a non-ASCII name to be rejected")
+	}
+	if srv.callCount("SetDevName") != 0 {
+		t.Error("invalid name was sent")
+	}
+
+	WithNameTransliteration(true)(client)
+	if err := client.System.SetDeviceName(t.Context(), " Einfahrt  Süd "); err != nil {
+		t.Fatalf("SetDeviceName failed: %v", err)
+	}
+	var param DeviceNameParam
+	json.Unmarshal(srv.lastParam("SetDevName"), &param)
+	if param.DevName.Name != "Einfahrt Sud" {
+		t.Errorf("expected the transliterated name, got %q", param.DevName.Name)
+	}
+}
+
+func TestSystemAPI_SetDeviceNameVerified(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetDevName": `{"DevName": {"name": "Camera"}}`,
+		"SetDevName": "",
+	})
+	client := srv.client()
+
+	stored, err := client.System.SetDeviceNameVerified(t.Context(), "Einfahrt Süd")
+	if err != nil || stored != "Einfahrt Süd" {
+		t.Fatalf("SetDeviceNameVerified = %q, %v", stored, err)
+	}
+
+	// Firmware that stores the UTF-8 bytes as Latin-1
+	mangled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []Request
+		json.NewDecoder(r.Body).Decode(&reqs)
+		w.Header().Set("Content-Type", "application/json")
+		if reqs[0].Cmd == "GetDevName" {
+			w.Write([]byte(`[{"cmd": "GetDevName", "code": 0, "value": {"DevName": {"name": "Einfahrt SÃ¼d"}}}]`))
+			return
+		}
+		w.Write([]byte(`[{"cmd": "SetDevName", "code": 0, "value": {"rspCode": 200}}]`))
+	}))
+	defer mangled.Close()
+	_, err = newTestClient(mangled).System.SetDeviceNameVerified(t.Context(), "Einfahrt Süd")
+	if !errors.Is(err, ErrDeviceNameMangled) {
+		t.Errorf("expected ErrDeviceNameMangled, got %v", err)
+	}
+}
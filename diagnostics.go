@@ -0,0 +1,257 @@
+package reolink
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mosleyit/reolink_api_wrapper/pkg/logger"
+)
+
+// redacted replaces secrets in diagnostic bundles
+const redacted = "<redacted>"
+
+// DiagnosticsAPI collects the state of a camera and of the client into a
+// bundle that can be attached to support tickets
+type DiagnosticsAPI struct {
+	client *Client
+}
+
+// DiagnosticNetwork is the network configuration in a diagnostic bundle,
+// with passwords redacted
+type DiagnosticNetwork struct {
+	NetPort   *NetPort   `json:"netPort,omitempty"`
+	LocalLink *LocalLink `json:"localLink,omitempty"`
+	Ntp       *Ntp       `json:"ntp,omitempty"`
+	Wifi      *Wifi      `json:"wifi,omitempty"`
+	Ddns      *Ddns      `json:"ddns,omitempty"`
+	Email     *Email     `json:"email,omitempty"`
+	Ftp       *Ftp       `json:"ftp,omitempty"`
+	P2p       *P2p       `json:"p2p,omitempty"`
+	Upnp      *Upnp      `json:"upnp,omitempty"`
+}
+
+// DiagnosticBundle is the outcome of Diagnostics.Collect. Sections the
+// camera could not provide are left empty and the reason is recorded in
+// Errors.
+type DiagnosticBundle struct {
+	Time        time.Time         `json:"time"`
+	Host        string            `json:"host"`
+	SDKVersion  string            `json:"sdkVersion"`
+	LegacyAPI   bool              `json:"legacyAPI"`
+	DeviceInfo  *DeviceInfo       `json:"deviceInfo,omitempty"`
+	Ability     *Ability          `json:"ability,omitempty"`
+	Network     DiagnosticNetwork `json:"network"`
+	Storage     []HddInfo         `json:"storage,omitempty"`
+	Performance *Performance      `json:"performance,omitempty"`
+	Quirks      []Quirk           `json:"quirks,omitempty"`
+	Metrics     []Metric          `json:"metrics,omitempty"`
+	Log         []string          `json:"log,omitempty"` // SDK log lines recorded with WithDebugCapture
+	Errors      []string          `json:"errors,omitempty"`
+}
+
+// Collect gathers device information, capabilities, the redacted network
+// configuration, storage and performance statistics, and the client's
+// quirks, metrics and captured log into a bundle. Failing sections do not
+// abort the collection.
+func (d *DiagnosticsAPI) Collect(ctx context.Context) (*DiagnosticBundle, error) {
+	c := d.client
+	c.log(ctx).Info("collecting diagnostics for camera at %s", c.Host())
+
+	b := &DiagnosticBundle{
+		Time:       time.Now(),
+		Host:       c.Host(),
+		SDKVersion: Version,
+	}
+	fail := func(section string, err error) {
+		b.Errors = append(b.Errors, fmt.Sprintf("%s: %v", section, err))
+	}
+
+	var err error
+	if b.DeviceInfo, err = c.System.GetDeviceInfo(ctx); err != nil {
+		fail("deviceInfo", err)
+	}
+	if b.Ability, err = c.System.GetAbility(ctx); err != nil {
+		fail("ability", err)
+	}
+
+	n := &b.Network
+	if n.NetPort, err = c.Network.GetNetPort(ctx); err != nil {
+		fail("netPort", err)
+	}
+	if n.LocalLink, err = c.Network.GetLocalLink(ctx); err != nil {
+		fail("localLink", err)
+	}
+	if n.Ntp, err = c.Network.GetNtp(ctx); err != nil {
+		fail("ntp", err)
+	}
+	if n.Wifi, err = c.Network.GetWifi(ctx); err != nil {
+		fail("wifi", err)
+	} else {
+		n.Wifi.Password = redact(n.Wifi.Password)
+	}
+	if n.Ddns, err = c.Network.GetDdns(ctx); err != nil {
+		fail("ddns", err)
+	} else {
+		n.Ddns.Password = redact(n.Ddns.Password)
+	}
+	if n.Email, err = c.Network.GetEmail(ctx); err != nil {
+		fail("email", err)
+	} else {
+		n.Email.Password = redact(n.Email.Password)
+	}
+	if n.Ftp, err = c.Network.GetFtp(ctx); err != nil {
+		fail("ftp", err)
+	} else {
+		n.Ftp.Password = redact(n.Ftp.Password)
+	}
+	if n.P2p, err = c.Network.GetP2p(ctx); err != nil {
+		fail("p2p", err)
+	}
+	if n.Upnp, err = c.Network.GetUpnp(ctx); err != nil {
+		fail("upnp", err)
+	}
+
+	if b.Storage, err = c.System.GetHddInfo(ctx); err != nil {
+		fail("storage", err)
+	}
+	if b.Performance, err = c.System.GetPerformance(ctx); err != nil {
+		fail("performance", err)
+	}
+
+	b.LegacyAPI = c.LegacyAPI()
+	b.Quirks = c.Quirks()
+	if m, ok := c.metrics.(*Metrics); ok {
+		b.Metrics = m.Snapshot()
+	}
+	if c.debugLog != nil {
+		b.Log = c.debugLog.lines()
+	}
+
+	c.log(ctx).Info("successfully collected diagnostics: %d sections failed", len(b.Errors))
+	return b, nil
+}
+
+// redact hides a non-empty secret
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// WriteJSON writes the bundle as indented JSON
+func (b *DiagnosticBundle) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(b)
+}
+
+// WriteTar writes the bundle as a tar archive holding bundle.json and, if
+// a log was captured, sdk.log
+func (b *DiagnosticBundle) WriteTar(w io.Writer) error {
+	var sb strings.Builder
+	if err := b.WriteJSON(&sb); err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+
+	files := []struct {
+		name string
+		data string
+	}{{"bundle.json", sb.String()}}
+	if len(b.Log) > 0 {
+		files = append(files, struct {
+			name string
+			data string
+		}{"sdk.log", strings.Join(b.Log, "\n") + "\n"})
+	}
+
+	tw := tar.NewWriter(w)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    f.name,
+			Mode:    0o644,
+			Size:    int64(len(f.data)),
+			ModTime: b.Time,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		if _, err := io.WriteString(tw, f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	return tw.Close()
+}
+
+// WithDebugCapture keeps the last n SDK log lines, at every level and in
+// addition to the configured logger, for Diagnostics.Collect
+func WithDebugCapture(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.debugLog = &debugCapture{max: n}
+		}
+	}
+}
+
+// debugCapture is a ring buffer of formatted log lines
+type debugCapture struct {
+	mu    sync.Mutex
+	max   int
+	buf   []string
+	start int
+}
+
+// add records a log line
+func (d *debugCapture) add(level, msg string, args []interface{}) {
+	line := time.Now().Format(time.RFC3339Nano) + " " + level + " " + fmt.Sprintf(msg, args...)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.buf) < d.max {
+		d.buf = append(d.buf, line)
+		return
+	}
+	d.buf[d.start] = line
+	d.start = (d.start + 1) % d.max
+}
+
+// lines returns the recorded lines, oldest first
+func (d *debugCapture) lines() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]string, 0, len(d.buf))
+	out = append(out, d.buf[d.start:]...)
+	return append(out, d.buf[:d.start]...)
+}
+
+// debugTee logs to next and records in capture
+type debugTee struct {
+	next    logger.Logger
+	capture *debugCapture
+}
+
+func (t debugTee) Debug(msg string, args ...interface{}) {
+	t.capture.add("DEBUG", msg, args)
+	t.next.Debug(msg, args...)
+}
+
+func (t debugTee) Info(msg string, args ...interface{}) {
+	t.capture.add("INFO", msg, args)
+	t.next.Info(msg, args...)
+}
+
+func (t debugTee) Warn(msg string, args ...interface{}) {
+	t.capture.add("WARN", msg, args)
+	t.next.Warn(msg, args...)
+}
+
+func (t debugTee) Error(msg string, args ...interface{}) {
+	t.capture.add("ERROR", msg, args)
+	t.next.Error(msg, args...)
+}
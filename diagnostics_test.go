@@ -0,0 +1,100 @@
+package reolink
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticsAPI_Collect(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetDevInfo":     `{"DevInfo": {"model": "RLC-811A", "firmVer": "v3.1.0.2368_23062700"}}`,
+		"GetAbility":     `{"Ability": {"abilityChn": []}}`,
+		"GetNetPort":     `{"NetPort": {"httpPort": 80, "httpsPort": 443}}`,
+		"GetWifi":        `{"Wifi": {"ssid": "home", "password": "wifi-secret"}}`,
+		"GetEmail":       `{"Email": {"smtpServer": "smtp.example.com", "password": "mail-secret"}}`,
+		"GetFtp":         `{"Ftp": {"server": "ftp.example.com", "password": "ftp-secret"}}`,
+		"GetHddInfo":     `{"HddInfo": [{"capacity": 1000, "mount": 1}]}`,
+		"GetPerformance": `{"Performance": {"cpuUsed": 42, "codeRate": 4096, "netThroughput": 0}}`,
+	})
+	client := server.client()
+	WithDebugCapture(3)(client)
+
+	bundle, err := client.Diagnostics.Collect(t.Context())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if bundle.DeviceInfo == nil || bundle.DeviceInfo.Model != "RLC-811A" {
+		t.Errorf("unexpected device info: %+v", bundle.DeviceInfo)
+	}
+	if bundle.Performance == nil || bundle.Performance.CPUUsed != 42 {
+		t.Errorf("unexpected performance: %+v", bundle.Performance)
+	}
+	if len(bundle.Storage) != 1 {
+		t.Errorf("unexpected storage: %+v", bundle.Storage)
+	}
+	// LocalLink, Ntp, Ddns, P2p and Upnp are not served
+	if len(bundle.Errors) != 5 {
+		t.Errorf("expected 5 section errors, got %v", bundle.Errors)
+	}
+	if len(bundle.Log) != 3 {
+		t.Errorf("expected the last 3 log lines, got %v", bundle.Log)
+	}
+
+	var buf bytes.Buffer
+	if err := bundle.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"wifi-secret", "mail-secret", "ftp-secret"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("bundle contains %s", secret)
+		}
+	}
+	if !strings.Contains(buf.String(), redacted) {
+		t.Error("expected redacted passwords")
+	}
+}
+
+func TestDiagnosticBundle_WriteTar(t *testing.T) {
+	bundle := &DiagnosticBundle{Host: "cam", Log: []string{"one", "two"}}
+
+	var buf bytes.Buffer
+	if err := bundle.WriteTar(&buf); err != nil {
+		t.Fatalf("WriteTar failed: %v", err)
+	}
+
+	files := map[string]string{}
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(data)
+	}
+	var decoded DiagnosticBundle
+	if err := json.Unmarshal([]byte(files["bundle.json"]), &decoded); err != nil || decoded.Host != "cam" {
+		t.Errorf("unexpected bundle.json: %s", files["bundle.json"])
+	}
+	if files["sdk.log"] != "one\ntwo\n" {
+		t.Errorf("unexpected sdk.log: %q", files["sdk.log"])
+	}
+}
+
+func TestDebugCapture_Wraps(t *testing.T) {
+	d := &debugCapture{max: 2}
+	for _, msg := range []string{"a", "b", "c"} {
+		d.add("INFO", msg, nil)
+	}
+	lines := d.lines()
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "INFO b") || !strings.HasSuffix(lines[1], "INFO c") {
+		t.Errorf("unexpected lines: %v", lines)
+	}
+}
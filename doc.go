@@ -55,6 +55,9 @@
 //   - LED: IR lights, white LED, power LED control
 //   - AI: AI detection, auto-tracking, auto-focus
 //   - Streaming: RTSP, RTMP, FLV URL helpers
+//   - ONVIF: ONVIF Media profiles, stream and snapshot URIs
+//   - Storage: Retention forecasts and overwrite policy
+//   - Diagnostics: Support bundles with device state and SDK log
 //
 // # Configuration Options
 //
@@ -114,10 +117,13 @@
 // Control Pan/Tilt/Zoom operations:
 //
 //	// Move camera right
-//	err := client.PTZ.PtzCtrl(ctx, "Right", 32, 0, 1)
+//	err := client.PTZ.PtzCtrl(ctx, reolink.PtzCtrlParam{Channel: 0, Op: reolink.PTZOpRight, Speed: 32})
 //
-//	// Go to preset position
-//	err := client.PTZ.SetPtzPreset(ctx, "call", 1, "Preset1")
+//	// Save the current position as preset 1
+//	err = client.PTZ.SetPtzPreset(ctx, reolink.PtzPreset{Channel: 0, Enable: 1, ID: 1, Name: "Gate"})
+//
+//	// Go to preset 1
+//	err = client.PTZ.CallPreset(ctx, 0, 1)
 //
 // # Motion Detection
 //
@@ -0,0 +1,106 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// emailRestoreTimeout bounds restoring the email configuration after
+// SendTestEmailWithSnapshot, which runs even if ctx was cancelled
+const emailRestoreTimeout = 10 * time.Second
+
+// SendTestEmailWithSnapshot validates alarm emails end to end: it enables
+// picture attachments in the channel's v2.0 email configuration if needed,
+// sends a test email and restores the previous configuration. The
+// configuration is round-tripped as raw JSON, so fields the SDK does not
+// model are kept.
+func (n *NetworkAPI) SendTestEmailWithSnapshot(ctx context.Context, channel int) error {
+	n.client.log(ctx).Info("sending test email with snapshot: channel=%d", channel)
+
+	original, err := n.getEmailV20Raw(ctx, channel)
+	if err != nil {
+		return err
+	}
+
+	modified := make(map[string]interface{}, len(original)+1)
+	for k, v := range original {
+		modified[k] = v
+	}
+	modified["attachmentType"] = EmailAttachmentPicture
+
+	changed := fmt.Sprint(original["attachmentType"]) != fmt.Sprint(int(EmailAttachmentPicture))
+	if changed {
+		if err := n.setEmailV20Raw(ctx, modified); err != nil {
+			return fmt.Errorf("failed to enable picture attachments: %w", err)
+		}
+	}
+
+	testErr := n.testEmail(ctx, map[string]interface{}{"Email": modified})
+
+	if changed {
+		restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), emailRestoreTimeout)
+		defer cancel()
+		if err := n.setEmailV20Raw(restoreCtx, original); err != nil {
+			n.client.log(ctx).Error("failed to restore email configuration: %v", err)
+			return errors.Join(testErr, fmt.Errorf("failed to restore email configuration: %w", err))
+		}
+	}
+	return testErr
+}
+
+// getEmailV20Raw reads the channel's v2.0 email configuration as a JSON
+// object
+func (n *NetworkAPI) getEmailV20Raw(ctx context.Context, channel int) (map[string]interface{}, error) {
+	req := []Request{{
+		Cmd: "GetEmailV20",
+		Param: map[string]interface{}{
+			"channel": channel,
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("GetEmailV20 request failed: %w", err)
+	}
+	if len(resp) == 0 {
+		return nil, fmt.Errorf("empty response from GetEmailV20")
+	}
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var value struct {
+		Email map[string]interface{} `json:"Email"`
+	}
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse GetEmailV20 response: %w", err)
+	}
+	if value.Email == nil {
+		return nil, fmt.Errorf("GetEmailV20 response contains no email configuration")
+	}
+	return value.Email, nil
+}
+
+// setEmailV20Raw writes a v2.0 email configuration read by getEmailV20Raw
+func (n *NetworkAPI) setEmailV20Raw(ctx context.Context, email map[string]interface{}) error {
+	req := []Request{{
+		Cmd: "SetEmailV20",
+		Param: map[string]interface{}{
+			"Email": email,
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("SetEmailV20 request failed: %w", err)
+	}
+	if len(resp) == 0 {
+		return fmt.Errorf("empty response from SetEmailV20")
+	}
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		return apiErr
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package reolink
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNetworkAPI_SendTestEmailWithSnapshot(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetEmailV20": `{"Email": {"attachmentType": 0, "ssl": 1, "smtpPort": 465, "schedule": {"table": "111"}}}`,
+		"SetEmailV20": "",
+		"TestEmail":   "",
+	})
+	client := server.client()
+
+	if err := client.Network.SendTestEmailWithSnapshot(t.Context(), 0); err != nil {
+		t.Fatalf("SendTestEmailWithSnapshot failed: %v", err)
+	}
+
+	var sent struct {
+		Email map[string]interface{} `json:"Email"`
+	}
+	if err := json.Unmarshal(server.lastParam("TestEmail"), &sent); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Email["attachmentType"] != float64(1) {
+		t.Errorf("test email sent with attachmentType %v", sent.Email["attachmentType"])
+	}
+
+	if n := server.callCount("SetEmailV20"); n != 2 {
+		t.Fatalf("SetEmailV20 called %d times, want 2", n)
+	}
+	var restored struct {
+		Email map[string]interface{} `json:"Email"`
+	}
+	if err := json.Unmarshal(server.lastParam("SetEmailV20"), &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Email["attachmentType"] != float64(0) || restored.Email["ssl"] != float64(1) || restored.Email["schedule"] == nil {
+		t.Errorf("unexpected restored configuration: %v", restored.Email)
+	}
+}
+
+func TestNetworkAPI_SendTestEmailWithSnapshot_AlreadyEnabled(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetEmailV20": `{"Email": {"attachmentType": 1}}`,
+		"SetEmailV20": "",
+		"TestEmail":   "",
+	})
+	client := server.client()
+
+	if err := client.Network.SendTestEmailWithSnapshot(t.Context(), 0); err != nil {
+		t.Fatalf("SendTestEmailWithSnapshot failed: %v", err)
+	}
+	if n := server.callCount("SetEmailV20"); n != 0 {
+		t.Errorf("SetEmailV20 called %d times, want 0", n)
+	}
+	if server.callCount("TestEmail") != 1 {
+		t.Error("expected one TestEmail request")
+	}
+}
+
+func TestNetworkAPI_SendTestEmailWithSnapshot_RestoresOnFailure(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetEmailV20": `{"Email": {"attachmentType": 2}}`,
+		"SetEmailV20": "",
+	})
+	client := server.client()
+
+	if err := client.Network.SendTestEmailWithSnapshot(t.Context(), 0); err == nil {
+		t.Fatal("expected TestEmail to fail")
+	}
+	var restored struct {
+		Email map[string]interface{} `json:"Email"`
+	}
+	if err := json.Unmarshal(server.lastParam("SetEmailV20"), &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Email["attachmentType"] != float64(2) {
+		t.Errorf("attachmentType restored to %v, want 2", restored.Email["attachmentType"])
+	}
+}
@@ -0,0 +1,206 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// EncPreset names a set of encoder settings applied by ApplyPreset
+type EncPreset string
+
+// Encoder presets. Each picks values from the ranges the camera reports for
+// its current resolution, so the result is always accepted by SetEnc.
+const (
+	// PresetLowBandwidth minimizes bitrate for metered or congested links:
+	// lowest bitrate, frame rate of about 10 fps and the longest GOP
+	PresetLowBandwidth EncPreset = "LowBandwidth"
+	// PresetBalanced restores the manufacturer defaults for the resolution
+	PresetBalanced EncPreset = "Balanced"
+	// PresetArchive maximizes quality for evidential recording: highest
+	// bitrate and frame rate and the shortest GOP
+	PresetArchive EncPreset = "Archive"
+)
+
+// EncStreamRange describes the settings a stream accepts at one resolution
+type EncStreamRange struct {
+	Size      string   `json:"size"`      // Resolution, e.g. "2560*1440"
+	Width     int      `json:"width"`     // Video width in pixels
+	Height    int      `json:"height"`    // Video height in pixels
+	VType     string   `json:"vType"`     // Video codec
+	BitRate   []int    `json:"bitRate"`   // Allowed bitrates in kbps
+	FrameRate []int    `json:"frameRate"` // Allowed frame rates
+	Profile   []string `json:"profile"`   // Allowed H.264/H.265 profiles
+	GOP       struct {
+		Min int `json:"min"`
+		Max int `json:"max"`
+	} `json:"gop"` // Allowed GOP range (in seconds of frames per keyframe)
+	Default struct {
+		BitRate   int `json:"bitRate"`
+		FrameRate int `json:"frameRate"`
+		GOP       int `json:"gop"`
+	} `json:"default"` // Manufacturer defaults
+}
+
+// EncRange is one resolution combination a channel supports
+type EncRange struct {
+	ChnBit     int            `json:"chnBit"`
+	MainStream EncStreamRange `json:"mainStream"`
+	SubStream  EncStreamRange `json:"subStream"`
+}
+
+// EncRangeValue wraps the encoding ranges returned by GetEnc with action 1
+type EncRangeValue struct {
+	Enc []EncRange `json:"Enc"`
+}
+
+// Validate checks that s uses a bitrate, frame rate, GOP and profile the
+// range allows
+func (r *EncStreamRange) Validate(s Stream) error {
+	if len(r.BitRate) > 0 && !slices.Contains(r.BitRate, s.BitRate) {
+		return fmt.Errorf("bitrate %d not supported at %s (allowed: %v)", s.BitRate, r.Size, r.BitRate)
+	}
+	if len(r.FrameRate) > 0 && !slices.Contains(r.FrameRate, s.FrameRate) {
+		return fmt.Errorf("frame rate %d not supported at %s (allowed: %v)", s.FrameRate, r.Size, r.FrameRate)
+	}
+	if r.GOP.Max > 0 && (s.GOP < r.GOP.Min || s.GOP > r.GOP.Max) {
+		return fmt.Errorf("GOP %d not supported at %s (allowed: %d-%d)", s.GOP, r.Size, r.GOP.Min, r.GOP.Max)
+	}
+	if s.Profile != "" && len(r.Profile) > 0 && !slices.Contains(r.Profile, s.Profile) {
+		return fmt.Errorf("profile %q not supported at %s (allowed: %v)", s.Profile, r.Size, r.Profile)
+	}
+	return nil
+}
+
+// GetEncRange gets the encoding settings a channel accepts, one entry per
+// supported resolution combination
+func (e *EncodingAPI) GetEncRange(ctx context.Context, channel int) ([]EncRange, error) {
+	e.client.log(ctx).Debug("getting encoding ranges: channel=%d", channel)
+
+	req := []Request{{
+		Cmd:    "GetEnc",
+		Action: 1, // Get value, initial and range
+		Param: map[string]interface{}{
+			"channel": channel,
+		},
+	}}
+
+	var resp []Response
+	if err := e.client.do(ctx, req, &resp); err != nil {
+		e.client.log(ctx).Error("failed to get encoding ranges: %v", err)
+		return nil, fmt.Errorf("GetEnc request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		e.client.log(ctx).Error("failed to get encoding ranges: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		e.client.log(ctx).Error("failed to get encoding ranges: %v", apiErr)
+		return nil, apiErr
+	}
+
+	if len(resp[0].Range) == 0 {
+		return nil, fmt.Errorf("GetEnc response contains no range")
+	}
+
+	var value EncRangeValue
+	if err := e.client.unmarshal(resp[0].Range, &value); err != nil {
+		e.client.log(ctx).Error("failed to parse encoding ranges response: %v", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	e.client.log(ctx).Info("successfully retrieved encoding ranges: combinations=%d", len(value.Enc))
+	return value.Enc, nil
+}
+
+// ApplyPreset applies an encoder preset to both streams of a channel at
+// their current resolutions and returns the configuration that was set. It
+// fails without changing anything if the camera reports no range for the
+// current main stream resolution.
+func (e *EncodingAPI) ApplyPreset(ctx context.Context, channel int, preset EncPreset) (*EncConfig, error) {
+	e.client.log(ctx).Info("applying encoder preset: channel=%d preset=%s", channel, preset)
+
+	current, err := e.GetEnc(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encoding configuration: %w", err)
+	}
+	ranges, err := e.GetEncRange(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encoding ranges: %w", err)
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("camera reported no encoding ranges")
+	}
+
+	i := slices.IndexFunc(ranges, func(r EncRange) bool { return r.MainStream.Size == current.MainStream.Size })
+	if i < 0 {
+		return nil, fmt.Errorf("camera reported no encoding range for the current resolution %s", current.MainStream.Size)
+	}
+	r := ranges[i]
+
+	config := *current
+	config.Channel = channel
+	if config.MainStream, err = preset.apply(r.MainStream, current.MainStream); err != nil {
+		return nil, err
+	}
+	if config.SubStream, err = preset.apply(r.SubStream, current.SubStream); err != nil {
+		return nil, err
+	}
+
+	if err := e.SetEnc(ctx, config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// apply returns stream s with the preset's settings chosen from r
+func (p EncPreset) apply(r EncStreamRange, s Stream) (Stream, error) {
+	if len(r.BitRate) == 0 || len(r.FrameRate) == 0 {
+		return s, fmt.Errorf("incomplete encoding range for %s", r.Size)
+	}
+
+	s.Size, s.Width, s.Height = r.Size, r.Width, r.Height
+	switch p {
+	case PresetLowBandwidth:
+		s.BitRate = slices.Min(r.BitRate)
+		s.FrameRate = closest(r.FrameRate, 10)
+		s.GOP = r.GOP.Max
+	case PresetBalanced:
+		s.BitRate = r.Default.BitRate
+		s.FrameRate = r.Default.FrameRate
+		s.GOP = r.Default.GOP
+	case PresetArchive:
+		s.BitRate = slices.Max(r.BitRate)
+		s.FrameRate = slices.Max(r.FrameRate)
+		s.GOP = r.GOP.Min
+	default:
+		return s, fmt.Errorf("unknown encoder preset %q", p)
+	}
+
+	if err := r.Validate(s); err != nil {
+		return s, fmt.Errorf("preset %s: %w", p, err)
+	}
+	return s, nil
+}
+
+// closest returns the value in values nearest to target, preferring the
+// lower one on ties
+func closest(values []int, target int) int {
+	best := values[0]
+	for _, v := range values[1:] {
+		d, bd := v-target, best-target
+		if d < 0 {
+			d = -d
+		}
+		if bd < 0 {
+			bd = -bd
+		}
+		if d < bd || (d == bd && v < best) {
+			best = v
+		}
+	}
+	return best
+}
@@ -0,0 +1,171 @@
+package reolink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testEncValue = `{"Enc": {"audio": 0, "channel": 0,
+	"mainStream": {"bitRate": 4096, "frameRate": 15, "gop": 2, "height": 1440, "profile": "High", "size": "2560*1440", "vType": "h264", "width": 2560},
+	"subStream": {"bitRate": 256, "frameRate": 10, "gop": 4, "height": 360, "profile": "High", "size": "640*360", "vType": "h264", "width": 640}}}`
+
+const testEncRange = `{"Enc": [
+	{"chnBit": 1,
+	 "mainStream": {"size": "2560*1440", "width": 2560, "height": 1440, "vType": "h264", "bitRate": [1024, 1536, 2048, 3072, 4096, 5120, 6144, 8192],
+	  "frameRate": [25, 22, 20, 18, 16, 15, 12, 10, 8, 6, 4, 2], "gop": {"min": 1, "max": 4}, "profile": ["Base", "Main", "High"],
+	  "default": {"bitRate": 6144, "frameRate": 25, "gop": 2}},
+	 "subStream": {"size": "640*360", "width": 640, "height": 360, "vType": "h264", "bitRate": [64, 128, 160, 192, 256, 384, 512],
+	  "frameRate": [15, 10, 7, 4], "gop": {"min": 1, "max": 4}, "profile": ["Base", "Main", "High"],
+	  "default": {"bitRate": 256, "frameRate": 10, "gop": 4}}}
+]}`
+
+// newEncRangeServer serves GetEnc with its range for action 1, records the
+// last SetEnc param and serves a single 1 TB disk
+func newEncRangeServer(t *testing.T) (*httptest.Server, *json.RawMessage) {
+	t.Helper()
+	return newEncValueServer(t, testEncValue)
+}
+
+// newEncValueServer is newEncRangeServer with value as the current
+// encoding configuration
+func newEncValueServer(t *testing.T, value string) (*httptest.Server, *json.RawMessage) {
+	t.Helper()
+	var (
+		mu     sync.Mutex
+		setEnc json.RawMessage
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			Cmd    string          `json:"cmd"`
+			Action int             `json:"action"`
+			Param  json.RawMessage `json:"param"`
+		}
+		json.NewDecoder(r.Body).Decode(&reqs)
+
+		resp := Response{Cmd: reqs[0].Cmd}
+		switch reqs[0].Cmd {
+		case "GetEnc":
+			resp.Value = json.RawMessage(value)
+			if reqs[0].Action == 1 {
+				resp.Range = json.RawMessage(testEncRange)
+			}
+		case "SetEnc":
+			mu.Lock()
+			setEnc = reqs[0].Param
+			mu.Unlock()
+			resp.Value = json.RawMessage(`{"rspCode": 200}`)
+		}
+		json.NewEncoder(w).Encode([]Response{resp})
+	}))
+	t.Cleanup(server.Close)
+	return server, &setEnc
+}
+
+func TestEncodingAPI_GetEncRange(t *testing.T) {
+	server, _ := newEncRangeServer(t)
+	client := newTestClient(server)
+
+	ranges, err := client.Encoding.GetEncRange(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("GetEncRange failed: %v", err)
+	}
+	if len(ranges) != 1 {
+		t.Fatalf("expected 1 range, got %d", len(ranges))
+	}
+	main := ranges[0].MainStream
+	if main.Size != "2560*1440" || len(main.BitRate) != 8 || main.GOP.Max != 4 || main.Default.BitRate != 6144 {
+		t.Errorf("unexpected main stream range: %+v", main)
+	}
+}
+
+func TestEncodingAPI_ApplyPreset(t *testing.T) {
+	tests := []struct {
+		preset   EncPreset
+		mainRate int
+		mainFPS  int
+		mainGOP  int
+		subRate  int
+		subFPS   int
+	}{
+		{PresetLowBandwidth, 1024, 10, 4, 64, 10},
+		{PresetBalanced, 6144, 25, 2, 256, 10},
+		{PresetArchive, 8192, 25, 1, 512, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.preset), func(t *testing.T) {
+			server, setEnc := newEncRangeServer(t)
+			client := newTestClient(server)
+
+			config, err := client.Encoding.ApplyPreset(t.Context(), 0, tt.preset)
+			if err != nil {
+				t.Fatalf("ApplyPreset failed: %v", err)
+			}
+			if config.MainStream.BitRate != tt.mainRate || config.MainStream.FrameRate != tt.mainFPS || config.MainStream.GOP != tt.mainGOP {
+				t.Errorf("unexpected main stream: %+v", config.MainStream)
+			}
+			if config.SubStream.BitRate != tt.subRate || config.SubStream.FrameRate != tt.subFPS {
+				t.Errorf("unexpected sub stream: %+v", config.SubStream)
+			}
+
+			var sent EncParam
+			if err := json.Unmarshal(*setEnc, &sent); err != nil {
+				t.Fatalf("failed to parse SetEnc param: %v", err)
+			}
+			if sent.Enc.MainStream != config.MainStream {
+				t.Errorf("SetEnc sent %+v, expected %+v", sent.Enc.MainStream, config.MainStream)
+			}
+		})
+	}
+}
+
+func TestEncodingAPI_ApplyPreset_Unknown(t *testing.T) {
+	server, setEnc := newEncRangeServer(t)
+	client := newTestClient(server)
+
+	if _, err := client.Encoding.ApplyPreset(t.Context(), 0, "Cinema"); err == nil {
+		t.Error("expected error for unknown preset")
+	}
+	if *setEnc != nil {
+		t.Error("SetEnc must not be sent for an unknown preset")
+	}
+}
+
+func TestEncodingAPI_ApplyPreset_NoMatchingRange(t *testing.T) {
+	server, setEnc := newEncValueServer(t, strings.ReplaceAll(testEncValue, "2560*1440", "3840*2160"))
+	client := newTestClient(server)
+
+	if _, err := client.Encoding.ApplyPreset(t.Context(), 0, PresetBalanced); err == nil || !strings.Contains(err.Error(), "3840*2160") {
+		t.Errorf("expected an error for a resolution without range, got %v", err)
+	}
+	if *setEnc != nil {
+		t.Error("SetEnc must not be sent without a matching range")
+	}
+}
+
+func TestEncStreamRange_Validate(t *testing.T) {
+	var r EncRangeValue
+	if err := json.Unmarshal([]byte(testEncRange), &r); err != nil {
+		t.Fatal(err)
+	}
+	main := r.Enc[0].MainStream
+
+	valid := Stream{BitRate: 4096, FrameRate: 15, GOP: 2, Profile: "High"}
+	if err := main.Validate(valid); err != nil {
+		t.Errorf("expected valid stream, got %v", err)
+	}
+	for name, s := range map[string]Stream{
+		"bitrate":   {BitRate: 4000, FrameRate: 15, GOP: 2},
+		"framerate": {BitRate: 4096, FrameRate: 30, GOP: 2},
+		"gop":       {BitRate: 4096, FrameRate: 15, GOP: 8},
+		"profile":   {BitRate: 4096, FrameRate: 15, GOP: 2, Profile: "Extended"},
+	} {
+		if err := main.Validate(s); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
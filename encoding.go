@@ -2,10 +2,11 @@ package reolink
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"strconv"
 )
 
 // EncodingAPI provides access to encoding/video stream configuration endpoints
@@ -33,7 +34,7 @@ type EncParam struct {
 
 // GetEnc gets encoding configuration for a channel
 func (e *EncodingAPI) GetEnc(ctx context.Context, channel int) (*EncConfig, error) {
-	e.client.logger.Debug("getting encoding configuration: channel=%d", channel)
+	e.client.log(ctx).Debug("getting encoding configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetEnc",
@@ -45,24 +46,24 @@ func (e *EncodingAPI) GetEnc(ctx context.Context, channel int) (*EncConfig, erro
 
 	var resp []Response
 	if err := e.client.do(ctx, req, &resp); err != nil {
-		e.client.logger.Error("failed to get encoding configuration: %v", err)
+		e.client.log(ctx).Error("failed to get encoding configuration: %v", err)
 		return nil, fmt.Errorf("GetEnc request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		e.client.logger.Error("failed to get encoding configuration: %v", err)
+		e.client.log(ctx).Error("failed to get encoding configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		e.client.logger.Error("failed to get encoding configuration: %v", apiErr)
+		e.client.log(ctx).Error("failed to get encoding configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value EncValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		e.client.logger.Error("failed to parse encoding configuration response: %v", err)
+	if err := e.client.unmarshal(resp[0].Value, &value); err != nil {
+		e.client.log(ctx).Error("failed to parse encoding configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -71,7 +72,7 @@ func (e *EncodingAPI) GetEnc(ctx context.Context, channel int) (*EncConfig, erro
 
 // SetEnc sets encoding configuration for a channel
 func (e *EncodingAPI) SetEnc(ctx context.Context, config EncConfig) error {
-	e.client.logger.Info("setting encoding configuration: channel=%d main_res=%dx%d bitrate=%d",
+	e.client.log(ctx).Info("setting encoding configuration: channel=%d main_res=%dx%d bitrate=%d",
 		config.Channel, config.MainStream.Width, config.MainStream.Height, config.MainStream.BitRate)
 
 	req := []Request{{
@@ -83,61 +84,84 @@ func (e *EncodingAPI) SetEnc(ctx context.Context, config EncConfig) error {
 
 	var resp []Response
 	if err := e.client.do(ctx, req, &resp); err != nil {
-		e.client.logger.Error("failed to set encoding configuration: %v", err)
+		e.client.log(ctx).Error("failed to set encoding configuration: %v", err)
 		return fmt.Errorf("SetEnc request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		e.client.logger.Error("failed to set encoding configuration: %v", err)
+		e.client.log(ctx).Error("failed to set encoding configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		e.client.logger.Error("failed to set encoding configuration: %v", apiErr)
+		e.client.log(ctx).Error("failed to set encoding configuration: %v", apiErr)
 		return apiErr
 	}
 
-	e.client.logger.Info("successfully set encoding configuration")
+	e.client.log(ctx).Info("successfully set encoding configuration")
 	return nil
 }
 
 // Snap captures a snapshot image from the specified channel
 // Returns the image data as a byte slice
 func (e *EncodingAPI) Snap(ctx context.Context, channel int) ([]byte, error) {
-	e.client.logger.Debug("capturing snapshot: channel=%d", channel)
+	var image []byte
+	err := withCmdLabels(ctx, "Snap", func(ctx context.Context) error {
+		var err error
+		image, err = e.snap(ctx, channel)
+		return err
+	})
+	return image, err
+}
+
+// snap captures a snapshot; see Snap
+func (e *EncodingAPI) snap(ctx context.Context, channel int) ([]byte, error) {
+	e.client.log(ctx).Debug("capturing snapshot: channel=%d", channel)
+	if err := e.client.checkPolicy(ctx, "Snap"); err != nil {
+		return nil, err
+	}
 
 	// Build URL with query parameters
-	url := fmt.Sprintf("%s?cmd=Snap&channel=%d&rs=snapshot", e.client.baseURL, channel)
+	url := fmt.Sprintf("%s?cmd=Snap&channel=%d&rs=snapshot", e.client.quirkBaseURL(ctx, "Snap"), channel)
 
 	// Add token if available
 	e.client.tokenMu.RLock()
 	token := e.client.token
 	e.client.tokenMu.RUnlock()
 
-	if token != "" {
+	if e.client.legacy.Load() {
+		url = e.client.legacyURL(ctx, "Snap", neturl.Values{"channel": {strconv.Itoa(channel)}, "rs": {"snapshot"}})
+	} else if token != "" {
 		url = fmt.Sprintf("%s&token=%s", url, token)
 	}
 
 	// Create HTTP request
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		e.client.logger.Error("failed to create snapshot request: %v", err)
+		e.client.log(ctx).Error("failed to create snapshot request: %v", err)
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	e.client.setHeaders(httpReq)
+	if err := e.client.signRequest(httpReq, nil); err != nil {
+		return nil, err
+	}
+
 	// Execute request
 	httpResp, err := e.client.httpClient.Do(httpReq)
 	if err != nil {
-		e.client.logger.Error("snapshot request failed: %v", err)
+		err = redactURL(err)
+		e.client.log(ctx).Error("snapshot request failed: %v", err)
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
-	defer httpResp.Body.Close()
+	body := e.client.closeOnCancel(ctx, httpResp.Body)
+	defer body.Close()
 
 	// Check status code
 	if httpResp.StatusCode != http.StatusOK {
 		err := fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
-		e.client.logger.Error("snapshot request failed: %v", err)
+		e.client.log(ctx).Error("snapshot request failed: %v", err)
 		return nil, err
 	}
 
@@ -145,17 +169,17 @@ func (e *EncodingAPI) Snap(ctx context.Context, channel int) ([]byte, error) {
 	contentType := httpResp.Header.Get("Content-Type")
 	if contentType != "image/jpeg" && contentType != "image/jpg" {
 		err := fmt.Errorf("unexpected content type: %s", contentType)
-		e.client.logger.Error("snapshot request failed: %v", err)
+		e.client.log(ctx).Error("snapshot request failed: %v", err)
 		return nil, err
 	}
 
 	// Read image data
-	imageData, err := io.ReadAll(httpResp.Body)
+	imageData, err := io.ReadAll(body)
 	if err != nil {
-		e.client.logger.Error("failed to read snapshot image data: %v", err)
+		e.client.log(ctx).Error("failed to read snapshot image data: %v", err)
 		return nil, fmt.Errorf("failed to read image data: %w", err)
 	}
 
-	e.client.logger.Info("successfully captured snapshot: size=%d bytes", len(imageData))
+	e.client.log(ctx).Info("successfully captured snapshot: size=%d bytes", len(imageData))
 	return imageData, nil
 }
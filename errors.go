@@ -1,7 +1,9 @@
 package reolink
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
 // Error codes from the Reolink API specification
@@ -109,14 +111,27 @@ type APIError struct {
 	Cmd     string // Command that caused the error
 }
 
-// Error implements the error interface
+// Error implements the error interface. The message always includes the
+// key and English description of RspCode, followed by the device's own
+// detail if present. Detail is in the firmware's language, so match errors
+// on RspCode or Key rather than on the message.
 func (e *APIError) Error() string {
 	if e.Detail != "" {
-		return fmt.Sprintf("reolink api error: cmd=%s code=%d rspCode=%d detail=%s",
-			e.Cmd, e.Code, e.RspCode, e.Detail)
+		return fmt.Sprintf("reolink api error: cmd=%s code=%d rspCode=%d key=%s (%s) detail=%s",
+			e.Cmd, e.Code, e.RspCode, e.Key(), DescribeCode(e.RspCode), e.Detail)
 	}
-	return fmt.Sprintf("reolink api error: cmd=%s code=%d rspCode=%d (%s)",
-		e.Cmd, e.Code, e.RspCode, errorCodeToString(e.RspCode))
+	return fmt.Sprintf("reolink api error: cmd=%s code=%d rspCode=%d key=%s (%s)",
+		e.Cmd, e.Code, e.RspCode, e.Key(), DescribeCode(e.RspCode))
+}
+
+// Key returns the stable key of RspCode
+func (e *APIError) Key() string {
+	return CodeKey(e.RspCode)
+}
+
+// Description returns the description of RspCode
+func (e *APIError) Description() string {
+	return DescribeCode(e.RspCode)
 }
 
 // Is implements error comparison for errors.Is
@@ -128,166 +143,190 @@ func (e *APIError) Is(target error) bool {
 	return e.RspCode == t.RspCode
 }
 
-// errorCodeToString returns a human-readable description of an error code
-func errorCodeToString(code int) string {
-	switch code {
-	case ErrCodeSuccess:
-		return "success"
-	case ErrCodeMissingParameters, ErrCodeMissingParametersAlt:
-		return "missing parameters"
-	case ErrCodeUsedUpMemory:
-		return "used up memory"
-	case ErrCodeCheckError:
-		return "check error"
-	case ErrCodeParametersError:
-		return "parameters error"
-	case ErrCodeMaxSessionNumber:
-		return "reached the max session number"
-	case ErrCodeLoginRequired:
-		return "login required"
-	case ErrCodeLoginError:
-		return "login error"
-	case ErrCodeOperationTimeout:
-		return "operation timeout"
-	case ErrCodeNotSupported:
-		return "not supported"
-	case ErrCodeProtocolError:
-		return "protocol error"
-	case ErrCodeFailedReadOperation:
-		return "failed to read operation"
-	case ErrCodeFailedGetConfiguration:
-		return "failed to get configuration"
-	case ErrCodeFailedSetConfiguration:
-		return "failed to set configuration"
-	case ErrCodeFailedApplyMemory:
-		return "failed to apply for memory"
-	case ErrCodeFailedCreateSocket:
-		return "failed to create socket"
-	case ErrCodeFailedSendData:
-		return "failed to send data"
-	case ErrCodeFailedReceiveData:
-		return "failed to receive data"
-	case ErrCodeFailedOpenFile:
-		return "failed to open file"
-	case ErrCodeFailedReadFile:
-		return "failed to read file"
-	case ErrCodeFailedWriteFile:
-		return "failed to write file"
-	case ErrCodeTokenError:
-		return "token error"
-	case ErrCodeStringLengthExceeded:
-		return "the length of the string exceeds the limitation"
-	case ErrCodeCommandError:
-		return "command error"
-	case ErrCodeInternalError:
-		return "internal error"
-	case ErrCodeAbilityError:
-		return "ability error"
-	case ErrCodeInvalidUser:
-		return "invalid user"
-	case ErrCodeUserAlreadyExists:
-		return "user already exists"
-	case ErrCodeMaxUsersReached:
-		return "reached the maximum number of users"
-	case ErrCodeVersionIdentical:
-		return "the version is identical to the current one"
-	case ErrCodeUpgradeBusy:
-		return "ensure only one user can upgrade (busy)"
-	case ErrCodeIPConflict:
-		return "modify IP conflicted with used IP"
-	case ErrCodeCloudBindEmailFirst:
-		return "cloud login need bind email first"
-	case ErrCodeCloudUnbindCamera:
-		return "cloud login unbind camera"
-	case ErrCodeCloudInfoTimeout:
-		return "cloud login get information out of time"
-	case ErrCodeCloudPasswordError:
-		return "cloud login password error"
-	case ErrCodeCloudUIDError:
-		return "cloud bind camera uid error"
-	case ErrCodeCloudUserNotExist:
-		return "cloud login user doesn't exist"
-	case ErrCodeCloudUnbindFailed:
-		return "cloud unbind camera failed"
-	case ErrCodeCloudNotSupported:
-		return "the device doesn't support cloud"
-	case ErrCodeCloudServerFailed:
-		return "cloud login server failed"
-	case ErrCodeCloudBindFailed:
-		return "cloud bind camera failed"
-	case ErrCodeCloudUnknownError:
-		return "cloud unknown error"
-	case ErrCodeCloudNeedVerifyCode:
-		return "cloud bind camera need verify code"
-	case ErrCodeDigestAuthFailed:
-		return "digest authentication failed"
-	case ErrCodeDigestNonceExpires:
-		return "digest authentication nonce expires"
-	case ErrCodeSnapFailed:
-		return "snap a picture failed"
-	case ErrCodeChannelInvalid:
-		return "channel is invalid"
-	case ErrCodeDeviceOffline:
-		return "device offline"
-	case ErrCodeTestFailed:
-		return "test email/ftp/wifi failed"
-	case ErrCodeUpgradeCheckFailed:
-		return "upgrade checking firmware failed"
-	case ErrCodeUpgradeDownloadFailed:
-		return "upgrade download online failed"
-	case ErrCodeUpgradeStatusFailed:
-		return "upgrade get upgrade status failed"
-	case ErrCodeFrequentLogins:
-		return "frequent logins, please try again later"
-	case ErrCodeVideoDownloadError:
-		return "error downloading video file"
-	case ErrCodeVideoBusy:
-		return "busy video recording task"
-	case ErrCodeVideoNotExist:
-		return "the video file does not exist"
-	case ErrCodeDigestNonceError:
-		return "digest authentication nonce error"
-	case ErrCodeAESDecryptFailed:
-		return "AES decryption failure"
-	case ErrCodeFTPLoginFailed:
-		return "FTP test login failed"
-	case ErrCodeFTPCreateDirFailed:
-		return "create FTP directory failed"
-	case ErrCodeFTPUploadFailed:
-		return "upload FTP file failed"
-	case ErrCodeFTPConnectFailed:
-		return "cannot connect FTP server"
-	case ErrCodeEmailUndefined:
-		return "email undefined error"
-	case ErrCodeEmailConnectFailed:
-		return "cannot connect email server"
-	case ErrCodeEmailAuthFailed:
-		return "email auth user failed"
-	case ErrCodeEmailNetworkError:
-		return "email network error"
-	case ErrCodeEmailServerError:
-		return "something wrong with email server"
-	case ErrCodeEmailMemoryError:
-		return "something wrong with memory"
-	case ErrCodeIPLimitReached:
-		return "the number of IP addresses reaches the upper limit"
-	case ErrCodeUserLocked:
-		return "user locked"
-	case ErrCodeUserNotOnline:
-		return "user not online"
-	case ErrCodeInvalidUsername:
-		return "invalid username"
-	case ErrCodeInvalidPassword:
-		return "invalid password"
-	case ErrCodeUserAlreadyLoggedIn:
-		return "user already logged in"
-	case ErrCodeAccountLocked:
-		return "account locked"
-	case ErrCodeAccountNotActivated:
-		return "account not activated"
-	default:
-		return fmt.Sprintf("unknown error code: %d", code)
+// codeDescriptions maps rspCode values to the English descriptions from the
+// API specification. Devices return the detail string in their firmware
+// language, so these descriptions are the stable reference.
+var codeDescriptions = map[int]string{
+	ErrCodeSuccess:                "success",
+	ErrCodeMissingParameters:      "missing parameters",
+	ErrCodeMissingParametersAlt:   "missing parameters",
+	ErrCodeUsedUpMemory:           "used up memory",
+	ErrCodeCheckError:             "check error",
+	ErrCodeParametersError:        "parameters error",
+	ErrCodeMaxSessionNumber:       "reached the max session number",
+	ErrCodeLoginRequired:          "login required",
+	ErrCodeLoginError:             "login error",
+	ErrCodeOperationTimeout:       "operation timeout",
+	ErrCodeNotSupported:           "not supported",
+	ErrCodeProtocolError:          "protocol error",
+	ErrCodeFailedReadOperation:    "failed to read operation",
+	ErrCodeFailedGetConfiguration: "failed to get configuration",
+	ErrCodeFailedSetConfiguration: "failed to set configuration",
+	ErrCodeFailedApplyMemory:      "failed to apply for memory",
+	ErrCodeFailedCreateSocket:     "failed to create socket",
+	ErrCodeFailedSendData:         "failed to send data",
+	ErrCodeFailedReceiveData:      "failed to receive data",
+	ErrCodeFailedOpenFile:         "failed to open file",
+	ErrCodeFailedReadFile:         "failed to read file",
+	ErrCodeFailedWriteFile:        "failed to write file",
+	ErrCodeTokenError:             "token error",
+	ErrCodeStringLengthExceeded:   "the length of the string exceeds the limitation",
+	ErrCodeCommandError:           "command error",
+	ErrCodeInternalError:          "internal error",
+	ErrCodeAbilityError:           "ability error",
+	ErrCodeInvalidUser:            "invalid user",
+	ErrCodeUserAlreadyExists:      "user already exists",
+	ErrCodeMaxUsersReached:        "reached the maximum number of users",
+	ErrCodeVersionIdentical:       "the version is identical to the current one",
+	ErrCodeUpgradeBusy:            "ensure only one user can upgrade (busy)",
+	ErrCodeIPConflict:             "modify IP conflicted with used IP",
+	ErrCodeCloudBindEmailFirst:    "cloud login need bind email first",
+	ErrCodeCloudUnbindCamera:      "cloud login unbind camera",
+	ErrCodeCloudInfoTimeout:       "cloud login get information out of time",
+	ErrCodeCloudPasswordError:     "cloud login password error",
+	ErrCodeCloudUIDError:          "cloud bind camera uid error",
+	ErrCodeCloudUserNotExist:      "cloud login user doesn't exist",
+	ErrCodeCloudUnbindFailed:      "cloud unbind camera failed",
+	ErrCodeCloudNotSupported:      "the device doesn't support cloud",
+	ErrCodeCloudServerFailed:      "cloud login server failed",
+	ErrCodeCloudBindFailed:        "cloud bind camera failed",
+	ErrCodeCloudUnknownError:      "cloud unknown error",
+	ErrCodeCloudNeedVerifyCode:    "cloud bind camera need verify code",
+	ErrCodeDigestAuthFailed:       "digest authentication failed",
+	ErrCodeDigestNonceExpires:     "digest authentication nonce expires",
+	ErrCodeSnapFailed:             "snap a picture failed",
+	ErrCodeChannelInvalid:         "channel is invalid",
+	ErrCodeDeviceOffline:          "device offline",
+	ErrCodeTestFailed:             "test email/ftp/wifi failed",
+	ErrCodeUpgradeCheckFailed:     "upgrade checking firmware failed",
+	ErrCodeUpgradeDownloadFailed:  "upgrade download online failed",
+	ErrCodeUpgradeStatusFailed:    "upgrade get upgrade status failed",
+	ErrCodeFrequentLogins:         "frequent logins, please try again later",
+	ErrCodeVideoDownloadError:     "error downloading video file",
+	ErrCodeVideoBusy:              "busy video recording task",
+	ErrCodeVideoNotExist:          "the video file does not exist",
+	ErrCodeDigestNonceError:       "digest authentication nonce error",
+	ErrCodeAESDecryptFailed:       "AES decryption failure",
+	ErrCodeFTPLoginFailed:         "FTP test login failed",
+	ErrCodeFTPCreateDirFailed:     "create FTP directory failed",
+	ErrCodeFTPUploadFailed:        "upload FTP file failed",
+	ErrCodeFTPConnectFailed:       "cannot connect FTP server",
+	ErrCodeEmailUndefined:         "email undefined error",
+	ErrCodeEmailConnectFailed:     "cannot connect email server",
+	ErrCodeEmailAuthFailed:        "email auth user failed",
+	ErrCodeEmailNetworkError:      "email network error",
+	ErrCodeEmailServerError:       "something wrong with email server",
+	ErrCodeEmailMemoryError:       "something wrong with memory",
+	ErrCodeIPLimitReached:         "the number of IP addresses reaches the upper limit",
+	ErrCodeUserLocked:             "user locked",
+	ErrCodeUserNotOnline:          "user not online",
+	ErrCodeInvalidUsername:        "invalid username",
+	ErrCodeInvalidPassword:        "invalid password",
+	ErrCodeUserAlreadyLoggedIn:    "user already logged in",
+	ErrCodeAccountLocked:          "account locked",
+	ErrCodeAccountNotActivated:    "account not activated",
+}
+
+// codeKeys maps rspCode values to stable machine-readable keys, for
+// matching errors without depending on numeric codes or description text
+var codeKeys = map[int]string{
+	ErrCodeSuccess:                "success",
+	ErrCodeMissingParameters:      "missing_parameters",
+	ErrCodeUsedUpMemory:           "used_up_memory",
+	ErrCodeCheckError:             "check_error",
+	ErrCodeParametersError:        "parameters_error",
+	ErrCodeMaxSessionNumber:       "max_session_number",
+	ErrCodeLoginRequired:          "login_required",
+	ErrCodeLoginError:             "login_error",
+	ErrCodeOperationTimeout:       "operation_timeout",
+	ErrCodeNotSupported:           "not_supported",
+	ErrCodeProtocolError:          "protocol_error",
+	ErrCodeFailedReadOperation:    "failed_read_operation",
+	ErrCodeFailedGetConfiguration: "failed_get_configuration",
+	ErrCodeFailedSetConfiguration: "failed_set_configuration",
+	ErrCodeFailedApplyMemory:      "failed_apply_memory",
+	ErrCodeFailedCreateSocket:     "failed_create_socket",
+	ErrCodeFailedSendData:         "failed_send_data",
+	ErrCodeFailedReceiveData:      "failed_receive_data",
+	ErrCodeFailedOpenFile:         "failed_open_file",
+	ErrCodeFailedReadFile:         "failed_read_file",
+	ErrCodeFailedWriteFile:        "failed_write_file",
+	ErrCodeTokenError:             "token_error",
+	ErrCodeStringLengthExceeded:   "string_length_exceeded",
+	ErrCodeMissingParametersAlt:   "missing_parameters",
+	ErrCodeCommandError:           "command_error",
+	ErrCodeInternalError:          "internal_error",
+	ErrCodeAbilityError:           "ability_error",
+	ErrCodeInvalidUser:            "invalid_user",
+	ErrCodeUserAlreadyExists:      "user_already_exists",
+	ErrCodeMaxUsersReached:        "max_users_reached",
+	ErrCodeVersionIdentical:       "version_identical",
+	ErrCodeUpgradeBusy:            "upgrade_busy",
+	ErrCodeIPConflict:             "ip_conflict",
+	ErrCodeCloudBindEmailFirst:    "cloud_bind_email_first",
+	ErrCodeCloudUnbindCamera:      "cloud_unbind_camera",
+	ErrCodeCloudInfoTimeout:       "cloud_info_timeout",
+	ErrCodeCloudPasswordError:     "cloud_password_error",
+	ErrCodeCloudUIDError:          "cloud_uid_error",
+	ErrCodeCloudUserNotExist:      "cloud_user_not_exist",
+	ErrCodeCloudUnbindFailed:      "cloud_unbind_failed",
+	ErrCodeCloudNotSupported:      "cloud_not_supported",
+	ErrCodeCloudServerFailed:      "cloud_server_failed",
+	ErrCodeCloudBindFailed:        "cloud_bind_failed",
+	ErrCodeCloudUnknownError:      "cloud_unknown_error",
+	ErrCodeCloudNeedVerifyCode:    "cloud_need_verify_code",
+	ErrCodeDigestAuthFailed:       "digest_auth_failed",
+	ErrCodeDigestNonceExpires:     "digest_nonce_expires",
+	ErrCodeSnapFailed:             "snap_failed",
+	ErrCodeChannelInvalid:         "channel_invalid",
+	ErrCodeDeviceOffline:          "device_offline",
+	ErrCodeTestFailed:             "test_failed",
+	ErrCodeUpgradeCheckFailed:     "upgrade_check_failed",
+	ErrCodeUpgradeDownloadFailed:  "upgrade_download_failed",
+	ErrCodeUpgradeStatusFailed:    "upgrade_status_failed",
+	ErrCodeFrequentLogins:         "frequent_logins",
+	ErrCodeVideoDownloadError:     "video_download_error",
+	ErrCodeVideoBusy:              "video_busy",
+	ErrCodeVideoNotExist:          "video_not_exist",
+	ErrCodeDigestNonceError:       "digest_nonce_error",
+	ErrCodeAESDecryptFailed:       "aes_decrypt_failed",
+	ErrCodeFTPLoginFailed:         "ftp_login_failed",
+	ErrCodeFTPCreateDirFailed:     "ftp_create_dir_failed",
+	ErrCodeFTPUploadFailed:        "ftp_upload_failed",
+	ErrCodeFTPConnectFailed:       "ftp_connect_failed",
+	ErrCodeEmailUndefined:         "email_undefined",
+	ErrCodeEmailConnectFailed:     "email_connect_failed",
+	ErrCodeEmailAuthFailed:        "email_auth_failed",
+	ErrCodeEmailNetworkError:      "email_network_error",
+	ErrCodeEmailServerError:       "email_server_error",
+	ErrCodeEmailMemoryError:       "email_memory_error",
+	ErrCodeIPLimitReached:         "ip_limit_reached",
+	ErrCodeUserLocked:             "user_locked",
+	ErrCodeUserNotOnline:          "user_not_online",
+	ErrCodeInvalidUsername:        "invalid_username",
+	ErrCodeInvalidPassword:        "invalid_password",
+	ErrCodeUserAlreadyLoggedIn:    "user_already_logged_in",
+	ErrCodeAccountLocked:          "account_locked",
+	ErrCodeAccountNotActivated:    "account_not_activated",
+}
+
+// CodeKey returns the stable key of a Reolink rspCode, e.g. "login_required"
+// for ErrCodeLoginRequired, or "unknown" for codes not in the API
+// specification
+func CodeKey(code int) string {
+	if key, ok := codeKeys[code]; ok {
+		return key
 	}
+	return "unknown"
+}
+
+// DescribeCode returns the English description of a Reolink rspCode, or
+// "unknown error code: N" for codes not in the API specification
+func DescribeCode(code int) string {
+	if desc, ok := codeDescriptions[code]; ok {
+		return desc
+	}
+	return fmt.Sprintf("unknown error code: %d", code)
 }
 
 // NewAPIError creates a new APIError
@@ -299,3 +338,48 @@ func NewAPIError(cmd string, code, rspCode int, detail string) *APIError {
 		Detail:  detail,
 	}
 }
+
+// IsNotSupported reports whether err is, or wraps, an APIError for a
+// command the device does not support
+func IsNotSupported(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.RspCode == ErrCodeNotSupported
+}
+
+// MultiError holds the per-command errors of a batch in which some
+// commands failed. Calls returning it also return the results of the
+// commands that succeeded.
+type MultiError struct {
+	Errors []*APIError
+}
+
+// Error implements the error interface
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d commands failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the per-command errors for errors.Is and errors.As
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Err returns the error for cmd, or nil if cmd succeeded
+func (e *MultiError) Err(cmd string) *APIError {
+	for _, err := range e.Errors {
+		if err.Cmd == cmd {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,13 +2,15 @@ package reolink
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
 func TestAPIError_Error(t *testing.T) {
 	err := NewAPIError("GetDevInfo", 0, ErrCodeLoginRequired, "please login first")
 
-	expected := "reolink api error: cmd=GetDevInfo code=0 rspCode=-6 detail=please login first"
+	expected := "reolink api error: cmd=GetDevInfo code=0 rspCode=-6 key=login_required (login required) detail=please login first"
 	if err.Error() != expected {
 		t.Errorf("expected error message %q, got %q", expected, err.Error())
 	}
@@ -22,7 +24,7 @@ func TestAPIError_ErrorWithoutDetail(t *testing.T) {
 	}
 
 	// Should include the error code description
-	if err.Error() != "reolink api error: cmd=GetDevInfo code=0 rspCode=-6 (login required)" {
+	if err.Error() != "reolink api error: cmd=GetDevInfo code=0 rspCode=-6 key=login_required (login required)" {
 		t.Errorf("unexpected error message: %s", err.Error())
 	}
 }
@@ -41,7 +43,7 @@ func TestAPIError_Is(t *testing.T) {
 	}
 }
 
-func TestErrorCodeToString(t *testing.T) {
+func TestDescribeCode(t *testing.T) {
 	tests := []struct {
 		code     int
 		expected string
@@ -63,7 +65,7 @@ func TestErrorCodeToString(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.expected, func(t *testing.T) {
-			result := errorCodeToString(tt.code)
+			result := DescribeCode(tt.code)
 			if result != tt.expected {
 				t.Errorf("expected '%s', got '%s'", tt.expected, result)
 			}
@@ -121,3 +123,59 @@ func TestResponse_ToAPIError(t *testing.T) {
 		t.Errorf("expected nil for successful response, got %v", apiErr3)
 	}
 }
+
+func TestAPIError_Description(t *testing.T) {
+	err := NewAPIError("Login", 1, ErrCodeInvalidPassword, "密码错误")
+	if err.Description() != "invalid password" {
+		t.Errorf("unexpected description: %q", err.Description())
+	}
+	if !strings.Contains(err.Error(), "(invalid password)") {
+		t.Errorf("expected description in error message, got %q", err.Error())
+	}
+}
+
+func TestCodeKey(t *testing.T) {
+	tests := map[int]string{
+		ErrCodeLoginRequired:        "login_required",
+		ErrCodeMissingParametersAlt: "missing_parameters",
+		ErrCodeIPConflict:           "ip_conflict",
+		ErrCodeAESDecryptFailed:     "aes_decrypt_failed",
+		-9999:                       "unknown",
+	}
+	for code, want := range tests {
+		if got := CodeKey(code); got != want {
+			t.Errorf("CodeKey(%d) = %q, expected %q", code, got, want)
+		}
+	}
+	for code := range codeDescriptions {
+		if _, ok := codeKeys[code]; !ok {
+			t.Errorf("code %d has a description but no key", code)
+		}
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	err := &MultiError{Errors: []*APIError{
+		NewAPIError("GetRtspAuth", 1, ErrCodeNotSupported, "not support"),
+		NewAPIError("GetNtp", 1, ErrCodeFailedGetConfiguration, ""),
+	}}
+
+	if !strings.HasPrefix(err.Error(), "2 commands failed: ") {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+	if !errors.Is(err, &APIError{RspCode: ErrCodeFailedGetConfiguration}) {
+		t.Error("errors.Is does not match a wrapped APIError")
+	}
+	if got := err.Err("GetNtp"); got == nil || got.RspCode != ErrCodeFailedGetConfiguration {
+		t.Errorf("Err(GetNtp) = %v", got)
+	}
+	if err.Err("GetNetPort") != nil {
+		t.Error("Err returned an error for a command that succeeded")
+	}
+	if !IsNotSupported(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("IsNotSupported does not see through wrapping")
+	}
+	if IsNotSupported(errors.New("other")) {
+		t.Error("IsNotSupported matched a plain error")
+	}
+}
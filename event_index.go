@@ -0,0 +1,324 @@
+package reolink
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sqlIndexSchema creates the index tables. Times are stored as Unix
+// nanoseconds so they sort and compare as integers.
+var sqlIndexSchema = []string{
+	`CREATE TABLE IF NOT EXISTS reolink_events (
+		id INTEGER PRIMARY KEY,
+		time INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		host TEXT NOT NULL,
+		channel INTEGER NOT NULL,
+		detail TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS reolink_events_time ON reolink_events (time)`,
+	`CREATE INDEX IF NOT EXISTS reolink_events_host_time ON reolink_events (host, time)`,
+	`CREATE TABLE IF NOT EXISTS reolink_recordings (
+		host TEXT NOT NULL,
+		channel INTEGER NOT NULL,
+		file_name TEXT NOT NULL,
+		file_size INTEGER NOT NULL,
+		start_time INTEGER NOT NULL,
+		end_time INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		PRIMARY KEY (host, channel, file_name)
+	)`,
+	`CREATE INDEX IF NOT EXISTS reolink_recordings_start ON reolink_recordings (start_time)`,
+}
+
+// SQLIndex indexes events and recording search results in an SQLite
+// database for local review UIs. The SDK links no database driver: open db
+// with the SQLite driver of your choice, e.g. modernc.org/sqlite or
+// github.com/mattn/go-sqlite3, imported by your application. The tables
+// are prefixed with reolink_ and created if missing.
+type SQLIndex struct {
+	db *sql.DB
+
+	mu  sync.Mutex
+	err error
+}
+
+// EventQuery selects indexed events. Zero fields do not filter.
+type EventQuery struct {
+	From    time.Time   // Earliest event time, inclusive
+	To      time.Time   // Latest event time, exclusive
+	Host    string      // Camera or NVR host
+	Channel *int        // Channel
+	Types   []EventType // Event types, any of
+	Limit   int         // Maximum number of events
+	Newest  bool        // Newest first instead of oldest first
+}
+
+// RecordingQuery selects indexed recordings, overlapping From-To. Zero
+// fields do not filter.
+type RecordingQuery struct {
+	From    time.Time // Recordings ending after From
+	To      time.Time // Recordings starting before To
+	Host    string    // Camera or NVR host
+	Channel *int      // Channel
+	Type    string    // Recording type, e.g. "MD"
+	Limit   int       // Maximum number of recordings
+}
+
+// IndexedRecording is a recording search result and the host it is on
+type IndexedRecording struct {
+	Host string
+	SearchResult
+}
+
+// OpenSQLIndex creates the index tables in db if needed
+func OpenSQLIndex(ctx context.Context, db *sql.DB) (*SQLIndex, error) {
+	for _, stmt := range sqlIndexSchema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("failed to create index schema: %w", err)
+		}
+	}
+	return &SQLIndex{db: db}, nil
+}
+
+// AddEvent indexes ev
+func (x *SQLIndex) AddEvent(ctx context.Context, ev Event) error {
+	_, err := x.db.ExecContext(ctx,
+		`INSERT INTO reolink_events (time, type, host, channel, detail) VALUES (?, ?, ?, ?, ?)`,
+		ev.Time.UnixNano(), string(ev.Type), ev.Host, ev.Channel, ev.Detail)
+	if err != nil {
+		return fmt.Errorf("failed to index event: %w", err)
+	}
+	return nil
+}
+
+// Handle indexes ev like AddEvent, for use as an EventHandler. Errors are
+// kept and returned by Err.
+func (x *SQLIndex) Handle(ev Event) {
+	if err := x.AddEvent(context.Background(), ev); err != nil {
+		x.mu.Lock()
+		x.err = errors.Join(x.err, err)
+		x.mu.Unlock()
+	}
+}
+
+// Err returns the errors Handle ran into
+func (x *SQLIndex) Err() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.err
+}
+
+// AddRecordings indexes the Search results of host in one transaction,
+// updating recordings indexed before, e.g. when a search is repeated
+func (x *SQLIndex) AddRecordings(ctx context.Context, host string, results []SearchResult) error {
+	tx, err := x.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to index recordings: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, r := range results {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO reolink_recordings (host, channel, file_name, file_size, start_time, end_time, type)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (host, channel, file_name) DO UPDATE SET
+				file_size = excluded.file_size, start_time = excluded.start_time,
+				end_time = excluded.end_time, type = excluded.type`,
+			host, r.Channel, r.FileName, r.FileSize, r.StartTime.UnixNano(), r.EndTime.UnixNano(), r.Type)
+		if err != nil {
+			return fmt.Errorf("failed to index recording %s: %w", r.FileName, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to index recordings: %w", err)
+	}
+	return nil
+}
+
+// RemoveRecording drops a recording from the index, e.g. after
+// Recording.DeleteRecording
+func (x *SQLIndex) RemoveRecording(ctx context.Context, host string, channel int, fileName string) error {
+	_, err := x.db.ExecContext(ctx,
+		`DELETE FROM reolink_recordings WHERE host = ? AND channel = ? AND file_name = ?`,
+		host, channel, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to remove recording: %w", err)
+	}
+	return nil
+}
+
+// Events returns the indexed events matching q, ordered by time
+func (x *SQLIndex) Events(ctx context.Context, q EventQuery) ([]Event, error) {
+	query, args := q.sql()
+	rows, err := x.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var (
+			ev  Event
+			typ string
+			ns  int64
+		)
+		if err := rows.Scan(&ns, &typ, &ev.Host, &ev.Channel, &ev.Detail); err != nil {
+			return nil, fmt.Errorf("failed to read events: %w", err)
+		}
+		ev.Type, ev.Time = EventType(typ), time.Unix(0, ns)
+		events = append(events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return events, nil
+}
+
+// EventCounts returns how many indexed events matching q there are per
+// type, ignoring q.Limit and q.Newest
+func (x *SQLIndex) EventCounts(ctx context.Context, q EventQuery) (map[EventType]int, error) {
+	where, args := q.where()
+	rows, err := x.db.QueryContext(ctx, `SELECT type, COUNT(*) FROM reolink_events`+where+` GROUP BY type`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[EventType]int)
+	for rows.Next() {
+		var (
+			typ string
+			n   int
+		)
+		if err := rows.Scan(&typ, &n); err != nil {
+			return nil, fmt.Errorf("failed to count events: %w", err)
+		}
+		counts[EventType(typ)] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+	return counts, nil
+}
+
+// Recordings returns the indexed recordings matching q, ordered by start
+// time
+func (x *SQLIndex) Recordings(ctx context.Context, q RecordingQuery) ([]IndexedRecording, error) {
+	query, args := q.sql()
+	rows, err := x.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recordings: %w", err)
+	}
+	defer rows.Close()
+
+	var recordings []IndexedRecording
+	for rows.Next() {
+		var (
+			r          IndexedRecording
+			start, end int64
+		)
+		if err := rows.Scan(&r.Host, &r.Channel, &r.FileName, &r.FileSize, &start, &end, &r.Type); err != nil {
+			return nil, fmt.Errorf("failed to read recordings: %w", err)
+		}
+		r.StartTime, r.EndTime = time.Unix(0, start), time.Unix(0, end)
+		recordings = append(recordings, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recordings: %w", err)
+	}
+	return recordings, nil
+}
+
+// sqlFilter collects WHERE conditions and their arguments
+type sqlFilter struct {
+	conds []string
+	args  []any
+}
+
+func (f *sqlFilter) add(cond string, args ...any) {
+	f.conds = append(f.conds, cond)
+	f.args = append(f.args, args...)
+}
+
+// where returns the WHERE clause, empty without conditions
+func (f *sqlFilter) where() string {
+	if len(f.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(f.conds, " AND ")
+}
+
+// where returns the WHERE clause selecting q's events
+func (q EventQuery) where() (string, []any) {
+	var f sqlFilter
+	if !q.From.IsZero() {
+		f.add("time >= ?", q.From.UnixNano())
+	}
+	if !q.To.IsZero() {
+		f.add("time < ?", q.To.UnixNano())
+	}
+	if q.Host != "" {
+		f.add("host = ?", q.Host)
+	}
+	if q.Channel != nil {
+		f.add("channel = ?", *q.Channel)
+	}
+	if len(q.Types) > 0 {
+		args := make([]any, len(q.Types))
+		for i, t := range q.Types {
+			args[i] = string(t)
+		}
+		f.add("type IN (?"+strings.Repeat(", ?", len(q.Types)-1)+")", args...)
+	}
+	return f.where(), f.args
+}
+
+// sql returns the query selecting q's events
+func (q EventQuery) sql() (string, []any) {
+	where, args := q.where()
+	query := `SELECT time, type, host, channel, detail FROM reolink_events` + where + ` ORDER BY time`
+	if q.Newest {
+		query += ` DESC`
+	}
+	query += `, id`
+	if q.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, q.Limit)
+	}
+	return query, args
+}
+
+// sql returns the query selecting q's recordings
+func (q RecordingQuery) sql() (string, []any) {
+	var f sqlFilter
+	if !q.From.IsZero() {
+		f.add("end_time > ?", q.From.UnixNano())
+	}
+	if !q.To.IsZero() {
+		f.add("start_time < ?", q.To.UnixNano())
+	}
+	if q.Host != "" {
+		f.add("host = ?", q.Host)
+	}
+	if q.Channel != nil {
+		f.add("channel = ?", *q.Channel)
+	}
+	if q.Type != "" {
+		f.add("type = ?", q.Type)
+	}
+	query := `SELECT host, channel, file_name, file_size, start_time, end_time, type FROM reolink_recordings` +
+		f.where() + ` ORDER BY start_time, host, channel`
+	args := f.args
+	if q.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, q.Limit)
+	}
+	return query, args
+}
@@ -0,0 +1,245 @@
+//go:build reolink_sqlite
+
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sqliteCLI is a database/sql driver running each statement through the
+// sqlite3 command-line shell on a database file, so the index is tested
+// against real SQLite without linking a driver module. Arguments are
+// inlined as SQL literals, and each statement is its own transaction.
+type sqliteCLI struct {
+	path string
+}
+
+func (d sqliteCLI) Connect(context.Context) (driver.Conn, error) { return d, nil }
+func (d sqliteCLI) Driver() driver.Driver                        { return nil }
+func (d sqliteCLI) Prepare(query string) (driver.Stmt, error) {
+	return sqliteCLIStmt{d.path, query}, nil
+}
+func (d sqliteCLI) Close() error              { return nil }
+func (d sqliteCLI) Begin() (driver.Tx, error) { return sqliteCLITx{}, nil }
+
+type sqliteCLITx struct{}
+
+func (sqliteCLITx) Commit() error   { return nil }
+func (sqliteCLITx) Rollback() error { return nil }
+
+type sqliteCLIStmt struct {
+	path  string
+	query string
+}
+
+func (s sqliteCLIStmt) Close() error  { return nil }
+func (s sqliteCLIStmt) NumInput() int { return -1 }
+
+// run executes the statement with args and returns the shell's output
+func (s sqliteCLIStmt) run(args []driver.Value, flags ...string) ([]byte, error) {
+	var query strings.Builder
+	rest := s.query
+	for _, arg := range args {
+		i := strings.IndexByte(rest, '?')
+		if i < 0 {
+			return nil, fmt.Errorf("too many arguments for %s", s.query)
+		}
+		query.WriteString(rest[:i])
+		switch v := arg.(type) {
+		case string:
+			query.WriteString("'" + strings.ReplaceAll(v, "'", "''") + "'")
+		default:
+			fmt.Fprint(&query, v)
+		}
+		rest = rest[i+1:]
+	}
+	query.WriteString(rest)
+
+	cmd := exec.Command("sqlite3", append(flags, s.path)...)
+	cmd.Stdin = strings.NewReader(query.String() + ";\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil || stderr.Len() > 0 {
+		return nil, fmt.Errorf("sqlite3: %v: %s", err, stderr.Bytes())
+	}
+	return out, nil
+}
+
+func (s sqliteCLIStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if _, err := s.run(args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+// Query reads the rows in the shell's ASCII mode, with the header as the
+// first row; values arrive as text and database/sql converts them on Scan
+func (s sqliteCLIStmt) Query(args []driver.Value) (driver.Rows, error) {
+	out, err := s.run(args, "-ascii", "-header")
+	if err != nil {
+		return nil, err
+	}
+	rows := &sqliteCLIRows{}
+	for _, record := range strings.Split(strings.TrimSuffix(string(out), "\x1e"), "\x1e") {
+		if record == "" {
+			continue
+		}
+		fields := strings.Split(record, "\x1f")
+		if rows.columns == nil {
+			rows.columns = fields
+			continue
+		}
+		rows.rows = append(rows.rows, fields)
+	}
+	return rows, nil
+}
+
+type sqliteCLIRows struct {
+	columns []string
+	rows    [][]string
+}
+
+func (r *sqliteCLIRows) Columns() []string { return r.columns }
+func (r *sqliteCLIRows) Close() error      { return nil }
+
+func (r *sqliteCLIRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	for i, v := range r.rows[0] {
+		dest[i] = v
+	}
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func openSQLiteIndex(t *testing.T) *SQLIndex {
+	t.Helper()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	db := sql.OpenDB(sqliteCLI{filepath.Join(t.TempDir(), "index.db")})
+	t.Cleanup(func() { db.Close() })
+
+	index, err := OpenSQLIndex(t.Context(), db)
+	if err != nil {
+		t.Fatalf("OpenSQLIndex failed: %v", err)
+	}
+	// The schema is created only if missing
+	if _, err := OpenSQLIndex(t.Context(), db); err != nil {
+		t.Fatalf("reopening the index failed: %v", err)
+	}
+	return index
+}
+
+func TestSQLIndex_SQLiteEvents(t *testing.T) {
+	index := openSQLiteIndex(t)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Type: EventMotionStart, Host: "cam1", Channel: 0, Time: at},
+		{Type: EventAIStart, Host: "cam1", Channel: 2, Time: at.Add(time.Minute), Detail: AITypePeople},
+		{Type: EventMotionStart, Host: "cam2", Channel: 0, Time: at.Add(2 * time.Minute)},
+		{Type: EventMotionStop, Host: "cam1", Channel: 0, Time: at.Add(3 * time.Minute), Detail: "it's over"},
+	}
+	for _, ev := range events {
+		index.Handle(ev)
+	}
+	if err := index.Err(); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	got, err := index.Events(t.Context(), EventQuery{Host: "cam1"})
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	if len(got) != 3 || got[0].Type != EventMotionStart || got[1].Detail != AITypePeople || got[1].Channel != 2 ||
+		!got[1].Time.Equal(at.Add(time.Minute)) || got[2].Detail != "it's over" {
+		t.Errorf("unexpected cam1 events %+v", got)
+	}
+
+	channel := 0
+	got, err = index.Events(t.Context(), EventQuery{
+		From:    at.Add(time.Minute),
+		To:      at.Add(3 * time.Minute),
+		Channel: &channel,
+		Types:   []EventType{EventMotionStart, EventMotionStop},
+	})
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Host != "cam2" {
+		t.Errorf("expected only cam2's motion in the range, got %+v", got)
+	}
+
+	got, err = index.Events(t.Context(), EventQuery{Newest: true, Limit: 2})
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Type != EventMotionStop || got[1].Host != "cam2" {
+		t.Errorf("expected the two newest events, got %+v", got)
+	}
+
+	counts, err := index.EventCounts(t.Context(), EventQuery{Host: "cam1"})
+	if err != nil {
+		t.Fatalf("EventCounts failed: %v", err)
+	}
+	if len(counts) != 3 || counts[EventMotionStart] != 1 || counts[EventAIStart] != 1 || counts[EventMotionStop] != 1 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+}
+
+func TestSQLIndex_SQLiteRecordings(t *testing.T) {
+	index := openSQLiteIndex(t)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	recording := func(channel int, name string, start time.Duration, size int64) SearchResult {
+		return SearchResult{Channel: channel, FileName: name, FileSize: size, StartTime: at.Add(start), EndTime: at.Add(start + 10*time.Minute), Type: "MD"}
+	}
+
+	if err := index.AddRecordings(t.Context(), "nvr", []SearchResult{
+		recording(0, "a.mp4", 0, 100),
+		recording(1, "b.mp4", 20*time.Minute, 200),
+		recording(0, "c.mp4", 40*time.Minute, 300),
+	}); err != nil {
+		t.Fatalf("AddRecordings failed: %v", err)
+	}
+	// A repeated search updates a recording instead of duplicating it
+	if err := index.AddRecordings(t.Context(), "nvr", []SearchResult{recording(0, "a.mp4", 0, 150)}); err != nil {
+		t.Fatalf("AddRecordings failed: %v", err)
+	}
+	if err := index.RemoveRecording(t.Context(), "nvr", 0, "c.mp4"); err != nil {
+		t.Fatalf("RemoveRecording failed: %v", err)
+	}
+
+	got, err := index.Recordings(t.Context(), RecordingQuery{})
+	if err != nil {
+		t.Fatalf("Recordings failed: %v", err)
+	}
+	if len(got) != 2 || got[0].FileName != "a.mp4" || got[0].FileSize != 150 || got[1].FileName != "b.mp4" ||
+		got[0].Host != "nvr" || !got[1].EndTime.Equal(at.Add(30*time.Minute)) {
+		t.Errorf("unexpected recordings %+v", got)
+	}
+
+	// Overlapping 05-25 minutes: a ends at 10, b starts at 20
+	got, err = index.Recordings(t.Context(), RecordingQuery{From: at.Add(5 * time.Minute), To: at.Add(25 * time.Minute), Type: "MD", Limit: 1})
+	if err != nil {
+		t.Fatalf("Recordings failed: %v", err)
+	}
+	if len(got) != 1 || got[0].FileName != "a.mp4" {
+		t.Errorf("expected the first overlapping recording, got %+v", got)
+	}
+	channel := 1
+	if got, err := index.Recordings(t.Context(), RecordingQuery{Channel: &channel, Host: "nvr"}); err != nil || len(got) != 1 || got[0].FileName != "b.mp4" {
+		t.Errorf("unexpected channel 1 recordings %+v, %v", got, err)
+	}
+}
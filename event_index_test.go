@@ -0,0 +1,193 @@
+package reolink
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sqlFake is a database/sql driver that records statements and answers
+// queries with canned rows, standing in for SQLite, which the SDK does not
+// link
+type sqlFake struct {
+	mu      sync.Mutex
+	execs   []sqlFakeExec
+	queries []sqlFakeExec
+	rows    []sqlFakeResult // Canned rows, by the first match
+}
+
+type sqlFakeResult struct {
+	match string // Substring of the query
+	rows  [][]driver.Value
+}
+
+type sqlFakeExec struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *sqlFake) Connect(context.Context) (driver.Conn, error) { return sqlFakeConn{d}, nil }
+func (d *sqlFake) Driver() driver.Driver                        { return nil }
+
+type sqlFakeConn struct{ db *sqlFake }
+
+func (c sqlFakeConn) Prepare(query string) (driver.Stmt, error) { return sqlFakeStmt{c.db, query}, nil }
+func (c sqlFakeConn) Close() error                              { return nil }
+func (c sqlFakeConn) Begin() (driver.Tx, error)                 { return sqlFakeTx{}, nil }
+
+type sqlFakeTx struct{}
+
+func (sqlFakeTx) Commit() error   { return nil }
+func (sqlFakeTx) Rollback() error { return nil }
+
+type sqlFakeStmt struct {
+	db    *sqlFake
+	query string
+}
+
+func (s sqlFakeStmt) Close() error  { return nil }
+func (s sqlFakeStmt) NumInput() int { return -1 }
+
+func (s sqlFakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.execs = append(s.db.execs, sqlFakeExec{s.query, args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s sqlFakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.queries = append(s.db.queries, sqlFakeExec{s.query, args})
+	for _, r := range s.db.rows {
+		if strings.Contains(s.query, r.match) {
+			return &sqlFakeRows{rows: r.rows}, nil
+		}
+	}
+	return &sqlFakeRows{}, nil
+}
+
+type sqlFakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *sqlFakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *sqlFakeRows) Close() error { return nil }
+
+func (r *sqlFakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestSQLIndex(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fake := &sqlFake{rows: []sqlFakeResult{
+		{"COUNT(*)", [][]driver.Value{{"motion_start", int64(4)}}},
+		{"reolink_events", [][]driver.Value{{at.UnixNano(), "ai_start", "cam1", int64(2), "people"}}},
+		{"reolink_recordings", [][]driver.Value{{"nvr", int64(1), "RecM01.mp4", int64(1024), at.UnixNano(), at.Add(time.Minute).UnixNano(), "MD"}}},
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	index, err := OpenSQLIndex(t.Context(), db)
+	if err != nil {
+		t.Fatalf("OpenSQLIndex failed: %v", err)
+	}
+	if len(fake.execs) != len(sqlIndexSchema) {
+		t.Errorf("expected %d schema statements, got %d", len(sqlIndexSchema), len(fake.execs))
+	}
+
+	index.Handle(Event{Type: EventMotionStart, Host: "cam1", Channel: 2, Time: at})
+	last := fake.execs[len(fake.execs)-1]
+	if want := []driver.Value{at.UnixNano(), "motion_start", "cam1", int64(2), ""}; !reflect.DeepEqual(last.args, want) {
+		t.Errorf("AddEvent args = %v, want %v", last.args, want)
+	}
+
+	recs := []SearchResult{{Channel: 1, FileName: "a.mp4"}, {Channel: 1, FileName: "b.mp4"}}
+	if err := index.AddRecordings(t.Context(), "nvr", recs); err != nil {
+		t.Fatalf("AddRecordings failed: %v", err)
+	}
+	if n := len(fake.execs); !strings.Contains(fake.execs[n-1].query, "ON CONFLICT") || fake.execs[n-1].args[2] != "b.mp4" {
+		t.Errorf("unexpected recording insert %+v", fake.execs[n-1])
+	}
+
+	events, err := index.Events(t.Context(), EventQuery{Host: "cam1"})
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	want := Event{Type: EventAIStart, Host: "cam1", Channel: 2, Time: at, Detail: AITypePeople}
+	if len(events) != 1 || events[0].Type != want.Type || !events[0].Time.Equal(at) || events[0].Detail != want.Detail || events[0].Channel != 2 {
+		t.Errorf("Events = %+v, want %+v", events, want)
+	}
+
+	counts, err := index.EventCounts(t.Context(), EventQuery{})
+	if err != nil || counts[EventMotionStart] != 4 {
+		t.Errorf("EventCounts = %v, %v", counts, err)
+	}
+
+	recordings, err := index.Recordings(t.Context(), RecordingQuery{})
+	if err != nil {
+		t.Fatalf("Recordings failed: %v", err)
+	}
+	if len(recordings) != 1 || recordings[0].Host != "nvr" || recordings[0].FileSize != 1024 || recordings[0].EndTime.Sub(recordings[0].StartTime) != time.Minute {
+		t.Errorf("unexpected recordings %+v", recordings)
+	}
+
+	if err := index.Err(); err != nil {
+		t.Errorf("unexpected Handle error: %v", err)
+	}
+}
+
+func TestEventQuery_SQL(t *testing.T) {
+	from := time.Unix(100, 0)
+	channel := 3
+	q := EventQuery{
+		From:    from,
+		To:      from.Add(time.Hour),
+		Host:    "cam1",
+		Channel: &channel,
+		Types:   []EventType{EventMotionStart, EventAIStart},
+		Limit:   50,
+		Newest:  true,
+	}
+	query, args := q.sql()
+	wantQuery := `SELECT time, type, host, channel, detail FROM reolink_events WHERE time >= ? AND time < ? AND host = ? AND channel = ? AND type IN (?, ?) ORDER BY time DESC, id LIMIT ?`
+	if query != wantQuery {
+		t.Errorf("query = %s\nwant %s", query, wantQuery)
+	}
+	wantArgs := []any{from.UnixNano(), from.Add(time.Hour).UnixNano(), "cam1", 3, "motion_start", "ai_start", 50}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+
+	if query, args := (EventQuery{}).sql(); strings.Contains(query, "WHERE") || len(args) != 0 {
+		t.Errorf("empty query should not filter: %s %v", query, args)
+	}
+}
+
+func TestRecordingQuery_SQL(t *testing.T) {
+	from := time.Unix(100, 0)
+	query, args := RecordingQuery{From: from, To: from.Add(time.Hour), Type: "MD"}.sql()
+	if !strings.Contains(query, "WHERE end_time > ? AND start_time < ? AND type = ? ORDER BY start_time") {
+		t.Errorf("unexpected query %s", query)
+	}
+	if len(args) != 3 || args[2] != "MD" {
+		t.Errorf("unexpected args %v", args)
+	}
+}
@@ -0,0 +1,52 @@
+package reolink
+
+import (
+	"context"
+	"time"
+)
+
+// EventType identifies the kind of an Event
+type EventType string
+
+// Event types emitted by the SDK's watchers
+const (
+	EventChannelOnline  EventType = "channel_online"  // NVR channel came online
+	EventChannelOffline EventType = "channel_offline" // NVR channel went offline
+	EventMotionStart    EventType = "motion_start"    // Motion detection alarm started
+	EventMotionStop     EventType = "motion_stop"     // Motion detection alarm ended
+	EventAIStart        EventType = "ai_start"        // AI detection alarm started; Detail is the AI type
+	EventAIStop         EventType = "ai_stop"         // AI detection alarm ended; Detail is the AI type
+	EventWeakSignal     EventType = "weak_signal"     // WiFi signal fell below the threshold; Detail is the signal
+	EventSignalRestored EventType = "signal_restored" // WiFi signal recovered to the threshold; Detail is the signal
+	EventImageDrift     EventType = "image_drift"     // Image or ISP settings drifted and were re-applied; Detail lists them
+	EventVideoLoss      EventType = "video_loss"      // Channel lost its video signal
+	EventVideoRestored  EventType = "video_restored"  // Channel's video signal returned
+	EventTamperStart    EventType = "tamper_start"    // Tamper (scene change) alarm started
+	EventTamperStop     EventType = "tamper_stop"     // Tamper (scene change) alarm ended
+	EventUploadMissing  EventType = "upload_missing"  // No FTP upload arrived for an event; Detail is the event type
+	EventDiskUnhealthy  EventType = "disk_unhealthy"  // A disk's health degraded; Channel is the disk, Detail its DiskHealth
+	EventDiskHealthy    EventType = "disk_healthy"    // A disk is healthy again; Channel is the disk
+	EventCameraAsleep   EventType = "camera_asleep"   // Battery camera went to sleep
+	EventCameraAwake    EventType = "camera_awake"    // Battery camera woke up
+
+	EventWatchdogAction     EventType = "watchdog_action"     // Watchdog took a recovery action; Detail is WatchdogRelogin or WatchdogReboot
+	EventWatchdogEscalation EventType = "watchdog_escalation" // Camera kept failing despite recovery; Detail is the last error
+	EventWatchdogRecovered  EventType = "watchdog_recovered"  // Camera works again after watchdog recovery
+)
+
+// Event is a state change observed on a camera or NVR channel
+type Event struct {
+	Type    EventType // Kind of event
+	Host    string    // Camera or NVR host the event came from
+	Channel int       // Channel the event applies to
+	Time    time.Time // When the SDK observed the event
+	Detail  string    // Optional type-specific detail, e.g. a channel name or AI type
+}
+
+// EventHandler receives events from a watcher. Handlers are called
+// synchronously from the watcher goroutine and should return quickly.
+type EventHandler func(Event)
+
+// EventSource produces events for handler until ctx is done, like
+// Alarm.WatchEvents or MotionDetector.Run
+type EventSource func(ctx context.Context, handler EventHandler) error
@@ -332,7 +332,7 @@ func main() {
 		fmt.Printf("   SMTP Port:     %d\n", email.SMTPPort)
 		fmt.Printf("   Username:      %s\n", email.UserName)
 		fmt.Printf("   Receiver 1:    %s\n", email.Addr1)
-		fmt.Printf("   Interval:      %d\n", email.Interval)
+		fmt.Printf("   Interval:      %v\n", email.Interval)
 	}
 
 	// Test 22: FTP Configuration
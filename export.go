@@ -0,0 +1,513 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minPartSize is the smallest part S3 accepts in a multipart upload, except
+// for the last one
+const minPartSize = 5 << 20
+
+// ObjectStore uploads objects to S3-compatible storage. Implement it with
+// the S3 or MinIO client of your choice; size is -1 when the length is not
+// known in advance.
+type ObjectStore interface {
+	PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64) error
+}
+
+// MultipartObjectStore is an ObjectStore that can upload an object in
+// parts. ExportToObjectStore uses it to keep only one part in memory and
+// to retry single parts instead of the whole recording.
+type MultipartObjectStore interface {
+	ObjectStore
+	CreateMultipartUpload(ctx context.Context, bucket, key string) (uploadID string, err error)
+	UploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int, body io.Reader, size int64) (etag string, err error)
+	CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []ObjectPart) error
+	AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error
+}
+
+// ObjectPart is an uploaded part of a multipart upload
+type ObjectPart struct {
+	Number int
+	ETag   string
+}
+
+// ExportOptions configures ExportToObjectStore
+type ExportOptions struct {
+	// Key is the object key (default: the recording's file name)
+	Key string
+	// Size is the expected size, e.g. SearchResult.FileSize; 0 takes it
+	// from the camera's response. Short transfers are resumed.
+	Size int64
+	// PartSize is the size of multipart upload parts, at least 5 MiB
+	// (default: 8 MiB)
+	PartSize int64
+	// Retries is how often a failed part upload or download is retried
+	// (default: 3)
+	Retries int
+	// RetryDelay is the delay before the first retry, growing linearly
+	// (default: 1s)
+	RetryDelay time.Duration
+
+	limiter *bandwidthLimiter // Shared download rate limit of BulkExport
+}
+
+// ExportResult describes a completed export
+type ExportResult struct {
+	Bucket string
+	Key    string
+	Bytes  int64
+	Parts  int // Uploaded parts, 0 for a single PutObject
+}
+
+// ExportToObjectStore streams a recording, e.g. SearchResult.FileName,
+// from the camera to bucket without writing it to disk. With a
+// MultipartObjectStore the recording is uploaded in parts of PartSize;
+// a failed part is retried and an interrupted download resumed at the
+// failed offset with an HTTP Range request. If the export fails the
+// multipart upload is aborted. A plain ObjectStore gets the download
+// stream in one PutObject, restarted from the beginning if the upload
+// fails. Either way the download is checked against opts.Size or the size
+// the camera announces, and truncated transfers are resumed (see
+// DownloadTo).
+func (r *RecordingAPI) ExportToObjectStore(ctx context.Context, source string, store ObjectStore, bucket string, opts ExportOptions) (*ExportResult, error) {
+	if store == nil {
+		return nil, fmt.Errorf("store must not be nil")
+	}
+	if opts.Key == "" {
+		opts.Key = path.Base(source)
+	}
+	if opts.PartSize == 0 {
+		opts.PartSize = 8 << 20
+	}
+	if opts.PartSize < minPartSize {
+		return nil, fmt.Errorf("part size must be at least %d bytes", minPartSize)
+	}
+	if opts.Retries <= 0 {
+		opts.Retries = 3
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = time.Second
+	}
+
+	r.client.log(ctx).Info("exporting recording: source=%s bucket=%s key=%s", source, bucket, opts.Key)
+
+	var (
+		result *ExportResult
+		err    error
+	)
+	if multipart, ok := store.(MultipartObjectStore); ok {
+		result, err = r.exportMultipart(ctx, source, multipart, bucket, opts)
+	} else {
+		result, err = r.exportSingle(ctx, source, store, bucket, opts)
+	}
+	if err != nil {
+		r.client.log(ctx).Error("failed to export recording %s: %v", source, err)
+		return nil, err
+	}
+
+	r.client.log(ctx).Info("successfully exported recording: key=%s bytes=%d", result.Key, result.Bytes)
+	return result, nil
+}
+
+// exportSingle uploads the download with one PutObject per attempt. The
+// download resumes broken transfers itself; a failed upload starts over.
+func (r *RecordingAPI) exportSingle(ctx context.Context, source string, store ObjectStore, bucket string, opts ExportOptions) (*ExportResult, error) {
+	var lastErr error
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			if err := sleepCtx(ctx, time.Duration(attempt)*opts.RetryDelay); err != nil {
+				return nil, err
+			}
+			r.client.log(ctx).Warn("retrying export of %s (attempt %d): %v", source, attempt+1, lastErr)
+		}
+		download := r.newResumingDownload(ctx, source, opts.Size, opts.Retries, opts.RetryDelay, opts.limiter)
+		if err := download.open(); err != nil {
+			return nil, err
+		}
+		size := download.size
+		if size == 0 {
+			size = -1
+		}
+		counter := &countingReader{r: download}
+		err := store.PutObject(ctx, bucket, opts.Key, counter, size)
+		download.Close()
+		if err == nil {
+			return &ExportResult{Bucket: bucket, Key: opts.Key, Bytes: counter.n}, nil
+		}
+		if errors.Is(err, ErrDownloadIncomplete) || errors.Is(err, ErrChecksumMismatch) || ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = fmt.Errorf("failed to upload %s: %w", opts.Key, err)
+	}
+	return nil, lastErr
+}
+
+// exportMultipart uploads the download in parts, resuming the download and
+// retrying parts as needed
+func (r *RecordingAPI) exportMultipart(ctx context.Context, source string, store MultipartObjectStore, bucket string, opts ExportOptions) (result *ExportResult, err error) {
+	uploadID, err := store.CreateMultipartUpload(ctx, bucket, opts.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			defer cancel()
+			if abortErr := store.AbortMultipartUpload(abortCtx, bucket, opts.Key, uploadID); abortErr != nil {
+				r.client.log(ctx).Warn("failed to abort multipart upload of %s: %v", opts.Key, abortErr)
+			}
+		}
+	}()
+
+	download := r.newResumingDownload(ctx, source, opts.Size, opts.Retries, opts.RetryDelay, opts.limiter)
+	defer download.Close()
+
+	var (
+		parts  []ObjectPart
+		offset int64
+		buf    = make([]byte, opts.PartSize)
+	)
+	for {
+		n, readErr := io.ReadFull(download, buf)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return nil, readErr
+		}
+		if n > 0 {
+			number := len(parts) + 1
+			etag, err := r.uploadPart(ctx, store, bucket, opts, uploadID, number, buf[:n])
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, ObjectPart{Number: number, ETag: etag})
+			offset += int64(n)
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	if err := store.CompleteMultipartUpload(ctx, bucket, opts.Key, uploadID, parts); err != nil {
+		return nil, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return &ExportResult{Bucket: bucket, Key: opts.Key, Bytes: offset, Parts: len(parts)}, nil
+}
+
+// uploadPart uploads one part, retrying failures
+func (r *RecordingAPI) uploadPart(ctx context.Context, store MultipartObjectStore, bucket string, opts ExportOptions, uploadID string, number int, data []byte) (string, error) {
+	var lastErr error
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			if err := sleepCtx(ctx, time.Duration(attempt)*opts.RetryDelay); err != nil {
+				return "", err
+			}
+			r.client.log(ctx).Warn("retrying part %d of %s (attempt %d): %v", number, opts.Key, attempt+1, lastErr)
+		}
+		etag, err := store.UploadPart(ctx, bucket, opts.Key, uploadID, number, bytes.NewReader(data), int64(len(data)))
+		if err == nil {
+			return etag, nil
+		}
+		lastErr = fmt.Errorf("failed to upload part %d: %w", number, err)
+	}
+	return "", lastErr
+}
+
+// ErrDownloadIncomplete is returned when a download keeps ending before
+// the recording's full size was transferred
+var ErrDownloadIncomplete = errors.New("download incomplete")
+
+// ErrChecksumMismatch is returned when a downloaded recording does not
+// match the checksum the camera sent with it
+var ErrChecksumMismatch = errors.New("download checksum mismatch")
+
+// resumingDownload reads a recording, reopening it at the current offset
+// when the connection fails or ends before the expected size. The size is
+// taken from the caller (the Search result), else from Content-Length or
+// the ETag, which cameras form from the modification time and size. A
+// Content-MD5 header sent with the full file is checked at the end.
+type resumingDownload struct {
+	ctx        context.Context
+	recording  *RecordingAPI
+	source     string
+	retries    int
+	retryDelay time.Duration
+	limiter    *bandwidthLimiter
+
+	size     int64 // Expected size, 0 if unknown
+	checksum []byte
+	hash     hash.Hash
+
+	body     io.ReadCloser
+	offset   int64
+	failures int
+}
+
+// newResumingDownload returns a download of source expected to be size
+// bytes long, 0 if unknown
+func (r *RecordingAPI) newResumingDownload(ctx context.Context, source string, size int64, retries int, retryDelay time.Duration, limiter *bandwidthLimiter) *resumingDownload {
+	return &resumingDownload{
+		ctx:        ctx,
+		recording:  r,
+		source:     source,
+		retries:    retries,
+		retryDelay: retryDelay,
+		limiter:    limiter,
+		size:       size,
+	}
+}
+
+// open connects at the current offset, retrying failures
+func (d *resumingDownload) open() error {
+	for {
+		if d.failures > 0 {
+			if err := sleepCtx(d.ctx, time.Duration(d.failures)*d.retryDelay); err != nil {
+				return err
+			}
+		}
+		resp, err := d.recording.openDownload(d.ctx, d.source, d.offset, d.limiter)
+		if errors.Is(err, ErrForbiddenByPolicy) {
+			return err
+		}
+		if err != nil {
+			if d.failures++; d.failures > d.retries {
+				return d.giveUp(err)
+			}
+			continue
+		}
+		if d.offset == 0 {
+			d.inspect(resp.Header, resp.ContentLength)
+		}
+		d.body = resp.Body
+		return nil
+	}
+}
+
+// inspect takes the expected size and checksum from the first response
+func (d *resumingDownload) inspect(header http.Header, contentLength int64) {
+	if d.size == 0 {
+		d.size = max(contentLength, 0)
+	}
+	if d.size == 0 {
+		d.size = etagSize(header.Get("ETag"))
+	}
+	if sum, err := base64.StdEncoding.DecodeString(header.Get("Content-MD5")); err == nil && len(sum) == md5.Size {
+		d.checksum, d.hash = sum, md5.New()
+	}
+}
+
+// Read implements io.Reader
+func (d *resumingDownload) Read(p []byte) (int, error) {
+	for {
+		if d.body == nil {
+			if err := d.open(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := d.body.Read(p)
+		d.offset += int64(n)
+		if d.hash != nil {
+			d.hash.Write(p[:n])
+		}
+		if n > 0 {
+			d.failures = 0
+		}
+		if err == nil {
+			return n, nil
+		}
+		if err == io.EOF {
+			if d.size == 0 || d.offset == d.size {
+				return n, d.verify()
+			}
+			if d.offset > d.size {
+				return n, fmt.Errorf("%w: %s is %d bytes, expected %d", ErrDownloadIncomplete, d.source, d.offset, d.size)
+			}
+			err = fmt.Errorf("transfer ended after %d of %d bytes", d.offset, d.size)
+		}
+
+		d.body.Close()
+		d.body = nil
+		if d.failures++; d.failures > d.retries {
+			return n, d.giveUp(err)
+		}
+		d.recording.client.log(d.ctx).Warn("download of %s interrupted at byte %d, resuming: %v", d.source, d.offset, err)
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// giveUp returns the error for a download that failed for good. Once part
+// of the file was received, it wraps ErrDownloadIncomplete.
+func (d *resumingDownload) giveUp(err error) error {
+	if d.offset == 0 {
+		return err
+	}
+	if d.size > 0 {
+		return fmt.Errorf("%w: %s stopped at byte %d of %d: %w", ErrDownloadIncomplete, d.source, d.offset, d.size, err)
+	}
+	return fmt.Errorf("%w: %s stopped at byte %d: %w", ErrDownloadIncomplete, d.source, d.offset, err)
+}
+
+// verify checks the checksum once the whole file was read
+func (d *resumingDownload) verify() error {
+	if d.hash != nil && !bytes.Equal(d.hash.Sum(nil), d.checksum) {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, d.source)
+	}
+	return io.EOF
+}
+
+// Close closes the current connection
+func (d *resumingDownload) Close() error {
+	if d.body == nil {
+		return nil
+	}
+	return d.body.Close()
+}
+
+// etagSize returns the size encoded in an nginx-style ETag such as
+// "5fe0136c-2240a8" (modification time and size in hex), 0 if there is none
+func etagSize(etag string) int64 {
+	etag = strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+	_, sizeHex, ok := strings.Cut(etag, "-")
+	if !ok {
+		return 0
+	}
+	size, err := strconv.ParseInt(sizeHex, 16, 64)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
+// DownloadOptions configures DownloadTo
+type DownloadOptions struct {
+	// Size is the expected size, e.g. SearchResult.FileSize; 0 takes it
+	// from the camera's response
+	Size int64
+	// Retries is how often an interrupted or truncated transfer is resumed
+	// (default: 3)
+	Retries int
+	// RetryDelay is the delay before the first retry, growing linearly
+	// (default: 1s)
+	RetryDelay time.Duration
+}
+
+// DownloadTo downloads a recording to w and returns the bytes written.
+// Cameras under load often end long downloads early; DownloadTo checks the
+// received size against opts.Size or the size the camera announces and
+// resumes truncated or broken transfers with HTTP Range requests. It
+// returns an error wrapping ErrDownloadIncomplete if the file stays short
+// and ErrChecksumMismatch if the camera sent a Content-MD5 that does not
+// match. w may have received data even when an error is returned.
+func (r *RecordingAPI) DownloadTo(ctx context.Context, source string, w io.Writer, opts DownloadOptions) (int64, error) {
+	if opts.Retries <= 0 {
+		opts.Retries = 3
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = time.Second
+	}
+	r.client.log(ctx).Info("downloading recording: source=%s", source)
+
+	download := r.newResumingDownload(ctx, source, opts.Size, opts.Retries, opts.RetryDelay, nil)
+	defer download.Close()
+	n, err := io.Copy(w, download)
+	if err != nil {
+		r.client.log(ctx).Error("failed to download recording %s: %v", source, err)
+		return n, err
+	}
+
+	r.client.log(ctx).Info("successfully downloaded recording: source=%s bytes=%d", source, n)
+	return n, nil
+}
+
+// openDownload starts downloading source at offset and returns the
+// response, whose body is read through limiter if not nil
+func (r *RecordingAPI) openDownload(ctx context.Context, source string, offset int64, limiter *bandwidthLimiter) (*http.Response, error) {
+	if err := r.client.checkPolicy(ctx, "Download"); err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s?cmd=Download&source=%s&output=%s&token=%s",
+		r.client.quirkBaseURL(ctx, "Download"), escapeFileName(source), escapeFileName(path.Base(source)), r.client.GetToken())
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	r.client.setHeaders(httpReq)
+	if offset > 0 {
+		httpReq.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	if err := r.client.signRequest(httpReq, nil); err != nil {
+		return nil, err
+	}
+
+	httpResp, err := r.client.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("download request failed: %w", err)
+	}
+	httpResp.Body = r.client.closeOnCancel(ctx, httpResp.Body)
+
+	switch {
+	case offset > 0 && httpResp.StatusCode != http.StatusPartialContent:
+		httpResp.Body.Close()
+		return nil, fmt.Errorf("camera cannot resume the download at byte %d: status %d", offset, httpResp.StatusCode)
+	case offset == 0 && httpResp.StatusCode != http.StatusOK:
+		httpResp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
+	}
+
+	// Errors such as an expired token come back as a JSON body
+	if strings.Contains(httpResp.Header.Get("Content-Type"), "json") {
+		defer httpResp.Body.Close()
+		var resp []Response
+		if err := json.NewDecoder(httpResp.Body).Decode(&resp); err == nil && len(resp) > 0 {
+			if apiErr := resp[0].ToAPIError(); apiErr != nil {
+				return nil, apiErr
+			}
+		}
+		return nil, fmt.Errorf("unexpected JSON response to download")
+	}
+	if limiter != nil {
+		httpResp.Body = &limitedReader{ctx: ctx, r: httpResp.Body, limiter: limiter}
+	}
+	return httpResp, nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// sleepCtx waits for d or until ctx is done
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,271 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryStore is an in-memory MultipartObjectStore whose first UploadPart
+// call fails
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	parts   map[int][]byte
+	failed  bool
+	aborted bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{objects: make(map[string][]byte), parts: make(map[int][]byte)}
+}
+
+func (s *memoryStore) PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[bucket+"/"+key] = data
+	return nil
+}
+
+func (s *memoryStore) CreateMultipartUpload(ctx context.Context, bucket, key string) (string, error) {
+	return "upload-1", nil
+}
+
+func (s *memoryStore) UploadPart(ctx context.Context, bucket, key, uploadID string, number int, body io.Reader, size int64) (string, error) {
+	data, err := io.ReadAll(body)
+	if err != nil || int64(len(data)) != size {
+		return "", fmt.Errorf("short part: %d of %d bytes", len(data), size)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.failed {
+		s.failed = true
+		return "", errors.New("slow down")
+	}
+	s.parts[number] = data
+	return fmt.Sprintf("etag-%d", number), nil
+}
+
+func (s *memoryStore) CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []ObjectPart) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	var data []byte
+	for _, p := range parts {
+		data = append(data, s.parts[p.Number]...)
+	}
+	s.objects[bucket+"/"+key] = data
+	return nil
+}
+
+func (s *memoryStore) AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aborted = true
+	return nil
+}
+
+// plainStore hides the multipart methods of a memoryStore
+type plainStore struct{ store *memoryStore }
+
+func (p plainStore) PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64) error {
+	return p.store.PutObject(ctx, bucket, key, body, size)
+}
+
+// newRecordingServer serves recording for Download requests and breaks
+// the first connection halfway through
+func newRecordingServer(t *testing.T, recording []byte) *httptest.Server {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Content-Length", fmt.Sprint(len(recording)))
+			w.Write(recording[:len(recording)/2])
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "RecM01.mp4", time.Time{}, bytes.NewReader(recording))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testRecording() []byte {
+	recording := make([]byte, 11<<20)
+	for i := range recording {
+		recording[i] = byte(i % 251)
+	}
+	return recording
+}
+
+func TestRecordingAPI_ExportToObjectStore_Multipart(t *testing.T) {
+	recording := testRecording()
+	client := newTestClient(newRecordingServer(t, recording))
+	store := newMemoryStore()
+
+	result, err := client.Recording.ExportToObjectStore(t.Context(), "Mp4Record/2024-01-01/RecM01.mp4", store, "cams",
+		ExportOptions{PartSize: 5 << 20, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("ExportToObjectStore failed: %v", err)
+	}
+	if result.Key != "RecM01.mp4" || result.Parts != 3 || result.Bytes != int64(len(recording)) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if !bytes.Equal(store.objects["cams/RecM01.mp4"], recording) {
+		t.Error("uploaded object differs from the recording")
+	}
+	if store.aborted {
+		t.Error("upload was aborted")
+	}
+}
+
+func TestRecordingAPI_ExportToObjectStore_Single(t *testing.T) {
+	recording := testRecording()
+	client := newTestClient(newRecordingServer(t, recording))
+	store := newMemoryStore()
+
+	result, err := client.Recording.ExportToObjectStore(t.Context(), "RecM01.mp4", plainStore{store}, "cams",
+		ExportOptions{Key: "front/RecM01.mp4", RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("ExportToObjectStore failed: %v", err)
+	}
+	if result.Parts != 0 || !bytes.Equal(store.objects["cams/front/RecM01.mp4"], recording) {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestRecordingAPI_ExportToObjectStore_Aborts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"cmd": "Download", "code": 1, "error": {"rspCode": -6, "detail": "please login first"}}]`))
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+	store := newMemoryStore()
+
+	_, err := client.Recording.ExportToObjectStore(t.Context(), "RecM01.mp4", store, "cams", ExportOptions{Retries: 1, RetryDelay: time.Millisecond})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if !store.aborted {
+		t.Error("multipart upload was not aborted")
+	}
+}
+
+// newTruncatingServer serves recording, ending the first response cleanly
+// after a third of it, as cameras under load do, with headers set by header
+func newTruncatingServer(t *testing.T, recording []byte, header func(http.Header)) *httptest.Server {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header != nil {
+			header(w.Header())
+		}
+		if requests.Add(1) == 1 {
+			w.Write(recording[:len(recording)/3])
+			return
+		}
+		http.ServeContent(w, r, "RecM01.mp4", time.Time{}, bytes.NewReader(recording))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecordingAPI_DownloadTo(t *testing.T) {
+	recording := testRecording()
+
+	t.Run("size from search result", func(t *testing.T) {
+		client := newTestClient(newTruncatingServer(t, recording, nil))
+		var buf bytes.Buffer
+		n, err := client.Recording.DownloadTo(t.Context(), "RecM01.mp4", &buf,
+			DownloadOptions{Size: int64(len(recording)), RetryDelay: time.Millisecond})
+		if err != nil {
+			t.Fatalf("DownloadTo failed: %v", err)
+		}
+		if n != int64(len(recording)) || !bytes.Equal(buf.Bytes(), recording) {
+			t.Errorf("downloaded %d bytes, want the full recording", n)
+		}
+	})
+
+	t.Run("size from etag", func(t *testing.T) {
+		client := newTestClient(newTruncatingServer(t, recording, func(h http.Header) {
+			h.Set("ETag", fmt.Sprintf(`"5fe0136c-%x"`, len(recording)))
+		}))
+		var buf bytes.Buffer
+		if _, err := client.Recording.DownloadTo(t.Context(), "RecM01.mp4", &buf, DownloadOptions{RetryDelay: time.Millisecond}); err != nil {
+			t.Fatalf("DownloadTo failed: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), recording) {
+			t.Errorf("downloaded %d bytes, want the full recording", buf.Len())
+		}
+	})
+
+	t.Run("stays short", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(recording[:100])
+		}))
+		defer server.Close()
+		client := newTestClient(server)
+		_, err := client.Recording.DownloadTo(t.Context(), "RecM01.mp4", io.Discard,
+			DownloadOptions{Size: int64(len(recording)), Retries: 2, RetryDelay: time.Millisecond})
+		if !errors.Is(err, ErrDownloadIncomplete) {
+			t.Errorf("expected ErrDownloadIncomplete, got %v", err)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-MD5", "AAAAAAAAAAAAAAAAAAAAAA==")
+			w.Write(recording[:1000])
+		}))
+		defer server.Close()
+		client := newTestClient(server)
+		_, err := client.Recording.DownloadTo(t.Context(), "RecM01.mp4", io.Discard, DownloadOptions{})
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("expected ErrChecksumMismatch, got %v", err)
+		}
+	})
+}
+
+func TestRecordingAPI_ExportToObjectStore_Truncated(t *testing.T) {
+	recording := testRecording()
+	client := newTestClient(newTruncatingServer(t, recording, nil))
+	store := newMemoryStore()
+
+	result, err := client.Recording.ExportToObjectStore(t.Context(), "RecM01.mp4", plainStore{store}, "cams",
+		ExportOptions{Size: int64(len(recording)), RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("ExportToObjectStore failed: %v", err)
+	}
+	if result.Bytes != int64(len(recording)) || !bytes.Equal(store.objects["cams/RecM01.mp4"], recording) {
+		t.Errorf("exported %d bytes, want the full recording", result.Bytes)
+	}
+}
+
+func TestEtagSize(t *testing.T) {
+	tests := map[string]int64{
+		`"5fe0136c-2240a8"`:   0x2240a8,
+		`W/"5fe0136c-2240a8"`: 0x2240a8,
+		`"abc"`:               0,
+		"":                    0,
+		`"5fe0136c-zz"`:       0,
+	}
+	for etag, want := range tests {
+		if got := etagSize(etag); got != want {
+			t.Errorf("etagSize(%s) = %d, want %d", etag, got, want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxFaceImageSize is the largest enrollment image accepted by the firmware
+const maxFaceImageSize = 512 << 10
+
+// FaceProfile is a face enrolled in the camera's face recognition database.
+// The face database commands are not part of the public API guide; they are
+// only available on models reporting supportAiFace, and other devices
+// answer them with ErrCodeNotSupported.
+type FaceProfile struct {
+	ID         int    `json:"id"`                   // Database ID assigned by the camera
+	Name       string `json:"name"`                 // Display name
+	CreateTime int64  `json:"createTime,omitempty"` // Enrollment time (Unix seconds)
+	Image      []byte `json:"image,omitempty"`      // JPEG thumbnail (base64 on the wire)
+}
+
+// FaceMatch is a face recognition event
+type FaceMatch struct {
+	Channel    int       `json:"channel"`
+	FaceID     int       `json:"faceId"`          // Matched profile ID, 0 for an unknown face
+	Name       string    `json:"name"`            // Matched profile name
+	Similarity int       `json:"similarity"`      // Match confidence in percent
+	Time       time.Time `json:"time"`            // When the face was seen
+	Image      []byte    `json:"image,omitempty"` // JPEG crop of the face (base64 on the wire)
+}
+
+// Known reports whether the match is an enrolled face
+func (m *FaceMatch) Known() bool {
+	return m.FaceID != 0
+}
+
+// FaceListValue represents the response value for GetFaceList
+type FaceListValue struct {
+	FaceList []FaceProfile `json:"FaceList"`
+}
+
+// FaceValue represents the response value for AddFace
+type FaceValue struct {
+	Face FaceProfile `json:"Face"`
+}
+
+// FaceMatchValue represents the response value for SearchFaceMatch
+type FaceMatchValue struct {
+	FaceMatch []FaceMatch `json:"FaceMatch"`
+}
+
+// ListFaces lists the faces enrolled on a channel
+func (a *AIAPI) ListFaces(ctx context.Context, channel int) ([]FaceProfile, error) {
+	a.client.log(ctx).Debug("listing enrolled faces: channel=%d", channel)
+
+	req := []Request{{
+		Cmd:    "GetFaceList",
+		Action: 0,
+		Param: map[string]interface{}{
+			"channel": channel,
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to list enrolled faces: %v", err)
+		return nil, fmt.Errorf("GetFaceList request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		a.client.log(ctx).Error("failed to list enrolled faces: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to list enrolled faces: %v", apiErr)
+		return nil, apiErr
+	}
+
+	var value FaceListValue
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse face list response: %v", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	a.client.log(ctx).Info("successfully listed enrolled faces: count=%d", len(value.FaceList))
+	return value.FaceList, nil
+}
+
+// AddFace enrolls a face from a JPEG image showing a single face and returns
+// the new profile. Images must be JPEG and at most 512 KiB; crop or
+// re-encode larger photos first.
+func (a *AIAPI) AddFace(ctx context.Context, channel int, name string, image []byte) (*FaceProfile, error) {
+	if name == "" {
+		return nil, fmt.Errorf("face name must not be empty")
+	}
+	if !bytes.HasPrefix(image, []byte{0xff, 0xd8}) {
+		return nil, fmt.Errorf("face image must be a JPEG")
+	}
+	if len(image) > maxFaceImageSize {
+		return nil, fmt.Errorf("face image too large: %d bytes (max %d)", len(image), maxFaceImageSize)
+	}
+
+	a.client.log(ctx).Info("enrolling face: channel=%d name=%s size=%d bytes", channel, name, len(image))
+
+	req := []Request{{
+		Cmd:    "AddFace",
+		Action: 0,
+		Param: map[string]interface{}{
+			"channel": channel,
+			"Face": FaceProfile{
+				Name:  name,
+				Image: image,
+			},
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to enroll face: %v", err)
+		return nil, fmt.Errorf("AddFace request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		a.client.log(ctx).Error("failed to enroll face: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to enroll face: %v", apiErr)
+		return nil, apiErr
+	}
+
+	var value FaceValue
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse add face response: %v", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if value.Face.Name == "" {
+		value.Face.Name = name
+	}
+
+	a.client.log(ctx).Info("successfully enrolled face: id=%d", value.Face.ID)
+	return &value.Face, nil
+}
+
+// RemoveFace deletes an enrolled face
+func (a *AIAPI) RemoveFace(ctx context.Context, channel, id int) error {
+	a.client.log(ctx).Info("removing enrolled face: channel=%d id=%d", channel, id)
+
+	req := []Request{{
+		Cmd:    "DelFace",
+		Action: 0,
+		Param: map[string]interface{}{
+			"channel": channel,
+			"id":      id,
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to remove enrolled face: %v", err)
+		return fmt.Errorf("DelFace request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		a.client.log(ctx).Error("failed to remove enrolled face: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to remove enrolled face: %v", apiErr)
+		return apiErr
+	}
+
+	a.client.log(ctx).Info("successfully removed enrolled face")
+	return nil
+}
+
+// SearchFaceMatches retrieves face recognition events on a channel between
+// startTime and endTime
+func (a *AIAPI) SearchFaceMatches(ctx context.Context, channel int, startTime, endTime time.Time) ([]FaceMatch, error) {
+	a.client.log(ctx).Debug("searching face matches: channel=%d start=%s end=%s",
+		channel, startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+
+	req := []Request{{
+		Cmd:    "SearchFaceMatch",
+		Action: 0,
+		Param: map[string]interface{}{
+			"Search": map[string]interface{}{
+				"channel":   channel,
+				"startTime": startTime,
+				"endTime":   endTime,
+			},
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to search face matches: %v", err)
+		return nil, fmt.Errorf("SearchFaceMatch request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		a.client.log(ctx).Error("failed to search face matches: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to search face matches: %v", apiErr)
+		return nil, apiErr
+	}
+
+	var value FaceMatchValue
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse face match response: %v", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	a.client.log(ctx).Info("successfully searched face matches: found=%d", len(value.FaceMatch))
+	return value.FaceMatch, nil
+}
@@ -0,0 +1,112 @@
+package reolink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAIAPI_ListFaces(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetFaceList": `{"FaceList": [{"id": 1, "name": "Alice", "createTime": 1700000000, "image": "/9j/2Q=="}, {"id": 2, "name": "Bob"}]}`,
+	})
+
+	faces, err := server.client().AI.ListFaces(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("ListFaces failed: %v", err)
+	}
+	if len(faces) != 2 || faces[0].Name != "Alice" || faces[1].ID != 2 {
+		t.Errorf("unexpected faces: %+v", faces)
+	}
+	if !bytes.Equal(faces[0].Image, testJPEG) {
+		t.Errorf("expected decoded thumbnail, got %v", faces[0].Image)
+	}
+}
+
+func TestAIAPI_AddFace(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"AddFace": `{"Face": {"id": 7}}`,
+	})
+	client := server.client()
+
+	face, err := client.AI.AddFace(t.Context(), 0, "Alice", testJPEG)
+	if err != nil {
+		t.Fatalf("AddFace failed: %v", err)
+	}
+	if face.ID != 7 || face.Name != "Alice" {
+		t.Errorf("unexpected profile: %+v", face)
+	}
+
+	var param struct {
+		Channel int         `json:"channel"`
+		Face    FaceProfile `json:"Face"`
+	}
+	if err := json.Unmarshal(server.lastParam("AddFace"), &param); err != nil {
+		t.Fatalf("failed to parse AddFace param: %v", err)
+	}
+	if param.Face.Name != "Alice" || !bytes.Equal(param.Face.Image, testJPEG) {
+		t.Errorf("expected name and image to be sent, got %+v", param.Face)
+	}
+
+	for name, tc := range map[string]struct {
+		name  string
+		image []byte
+	}{
+		"empty name": {"", testJPEG},
+		"not a JPEG": {"Bob", []byte("\x89PNG")},
+		"too large":  {"Bob", append([]byte{0xff, 0xd8}, make([]byte, maxFaceImageSize)...)},
+	} {
+		if _, err := client.AI.AddFace(t.Context(), 0, tc.name, tc.image); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if n := server.callCount("AddFace"); n != 1 {
+		t.Errorf("expected invalid enrollments not to be sent, got %d calls", n)
+	}
+}
+
+func TestAIAPI_RemoveFace(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"DelFace": ""})
+
+	if err := server.client().AI.RemoveFace(t.Context(), 0, 7); err != nil {
+		t.Fatalf("RemoveFace failed: %v", err)
+	}
+	var param struct {
+		ID int `json:"id"`
+	}
+	json.Unmarshal(server.lastParam("DelFace"), &param)
+	if param.ID != 7 {
+		t.Errorf("expected id 7, got %d", param.ID)
+	}
+}
+
+func TestAIAPI_SearchFaceMatches(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"SearchFaceMatch": `{"FaceMatch": [{"channel": 0, "faceId": 1, "name": "Alice", "similarity": 92, "time": "2025-01-02T03:04:05Z"},
+			{"channel": 0, "faceId": 0, "similarity": 0, "time": "2025-01-02T03:05:00Z"}]}`,
+	})
+
+	end := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+	matches, err := server.client().AI.SearchFaceMatches(t.Context(), 0, end.Add(-24*time.Hour), end)
+	if err != nil {
+		t.Fatalf("SearchFaceMatches failed: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+	if !matches[0].Known() || matches[0].Similarity != 92 || matches[1].Known() {
+		t.Errorf("unexpected matches: %+v", matches)
+	}
+}
+
+func TestAIAPI_FacesNotSupported(t *testing.T) {
+	client := newCmdServer(t, nil).client()
+
+	_, err := client.AI.ListFaces(t.Context(), 0)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RspCode != ErrCodeNotSupported {
+		t.Errorf("expected not supported error, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureChecks calls the wrapper for each fixture command and checks that
+// the fields firmware is known to rename or reshape were decoded. Every
+// fixture file needs an entry here.
+var fixtureChecks = map[string]func(ctx context.Context, c *Client) error{
+	"GetDevInfo": func(ctx context.Context, c *Client) error {
+		info, err := c.System.GetDeviceInfo(ctx)
+		if err != nil {
+			return err
+		}
+		if info.FirmVer == "" || info.Model == "" || info.ChannelNum == 0 {
+			return fmt.Errorf("incomplete device info: %+v", info)
+		}
+		return nil
+	},
+	"GetAbility": func(ctx context.Context, c *Client) error {
+		ability, err := c.System.GetAbility(ctx)
+		if err != nil {
+			return err
+		}
+		if _, ok := ability.AbilityInfo["abilityChn"]; !ok {
+			return fmt.Errorf("abilityChn missing from %v", ability.AbilityInfo)
+		}
+		return nil
+	},
+	"UpgradeStatus": func(ctx context.Context, c *Client) error {
+		status, err := c.System.UpgradeStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if status.Percent == 0 {
+			return fmt.Errorf("Persent not decoded: %+v", status)
+		}
+		return nil
+	},
+	"GetRec": func(ctx context.Context, c *Client) error {
+		rec, err := c.Recording.GetRec(ctx, 0)
+		if err != nil {
+			return err
+		}
+		if table, ok := rec.Schedule.Table.(string); !ok || len(table) != 168 {
+			return fmt.Errorf("v1 schedule table not a 168 character string: %#v", rec.Schedule.Table)
+		}
+		return nil
+	},
+	"GetRecV20": func(ctx context.Context, c *Client) error {
+		rec, err := c.Recording.GetRecV20(ctx, 0)
+		if err != nil {
+			return err
+		}
+		table, ok := rec.Schedule.Table.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("v2 schedule table not an object: %#v", rec.Schedule.Table)
+		}
+		for _, key := range []string{"MD", "TIMING", "AI_PEOPLE"} {
+			if s, _ := table[key].(string); len(s) != 168 {
+				return fmt.Errorf("v2 schedule table %s is %q", key, s)
+			}
+		}
+		return nil
+	},
+	"GetHddInfo": func(ctx context.Context, c *Client) error {
+		disks, err := c.System.GetHddInfo(ctx)
+		if err != nil {
+			return err
+		}
+		if len(disks) == 0 || disks[0].Capacity == 0 {
+			return fmt.Errorf("no disk decoded: %+v", disks)
+		}
+		return nil
+	},
+	"GetMdState": func(ctx context.Context, c *Client) error {
+		state, err := c.Alarm.GetMdState(ctx, 0)
+		if err != nil {
+			return err
+		}
+		if state != 1 {
+			return fmt.Errorf("motion state = %d, want 1", state)
+		}
+		return nil
+	},
+	"GetNetPort": func(ctx context.Context, c *Client) error {
+		ports, err := c.Network.GetNetPort(ctx)
+		if err != nil {
+			return err
+		}
+		if ports.RTSPPort == 0 || ports.OnvifPort == 0 {
+			return fmt.Errorf("ports not decoded: %+v", ports)
+		}
+		return nil
+	},
+	"GetTime": func(ctx context.Context, c *Client) error {
+		tm, err := c.System.GetTime(ctx)
+		if err != nil {
+			return err
+		}
+		if tm.Year == 0 || tm.TimeZone == 0 {
+			return fmt.Errorf("time not decoded: %+v", tm)
+		}
+		return nil
+	},
+	"GetPtzTattern": func(ctx context.Context, c *Client) error {
+		tattern, err := c.PTZ.GetPtzTattern(ctx, 0)
+		if err != nil {
+			return err
+		}
+		if len(tattern.Track) == 0 || tattern.Track[0].ID == 0 {
+			return fmt.Errorf("tracks not decoded: %+v", tattern)
+		}
+		return nil
+	},
+	"GetAiState": func(ctx context.Context, c *Client) error {
+		state, err := c.AI.GetAiState(ctx, 0)
+		if err != nil {
+			return err
+		}
+		if state.People.Support == 0 || state.Vehicle.Support == 0 {
+			return fmt.Errorf("AI state not decoded: %+v", state)
+		}
+		return nil
+	},
+	"Search": func(ctx context.Context, c *Client) error {
+		results, err := c.Recording.Search(ctx, 0, time.Time{}, time.Time{}, "main")
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			return fmt.Errorf("no search results decoded")
+		}
+		for _, r := range results {
+			if r.FileName == "" || r.FileSize == 0 || r.StartTime.IsZero() || !r.EndTime.After(r.StartTime) {
+				return fmt.Errorf("incomplete search result: %+v", r)
+			}
+		}
+		return nil
+	},
+}
+
+// TestFirmwareFixtures replays the recorded responses under
+// testdata/fixtures/<firmware>/<cmd>.json through the wrappers
+func TestFirmwareFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "fixtures", "*", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no fixtures found")
+	}
+
+	for _, file := range files {
+		firmware := filepath.Base(filepath.Dir(file))
+		cmd := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(firmware+"/"+cmd, func(t *testing.T) {
+			check, ok := fixtureChecks[cmd]
+			if !ok {
+				t.Fatalf("no check registered for %s", cmd)
+			}
+			body, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("cmd"); got != cmd {
+					t.Errorf("unexpected cmd %q", got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(body)
+			}))
+			defer server.Close()
+
+			client := newTestClient(server)
+			WithDecodeMode(DecodeStrict)(client)
+			if err := check(t.Context(), client); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
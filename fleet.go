@@ -0,0 +1,271 @@
+package reolink
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultFleetConcurrency bounds how many cameras a Fleet talks to at once
+const defaultFleetConcurrency = 8
+
+// defaultFleetTimeout is the HTTP timeout of cameras added with a custom
+// transport, matching NewClient
+const defaultFleetTimeout = 30 * time.Second
+
+// Fleet manages a group of camera clients addressed by name and runs
+// operations across them concurrently
+type Fleet struct {
+	mu          sync.RWMutex
+	clients     map[string]*Client
+	tenants     map[string]string // Camera name to tenant, for cameras added with AddCamera
+	concurrency int
+	credentials CredentialsProvider
+}
+
+// FleetOption is a functional option for configuring a Fleet
+type FleetOption func(*Fleet)
+
+// WithFleetConcurrency sets how many cameras are contacted in parallel
+// (default: 8)
+func WithFleetConcurrency(n int) FleetOption {
+	return func(f *Fleet) {
+		if n > 0 {
+			f.concurrency = n
+		}
+	}
+}
+
+// WithFleetCredentials sets the provider that resolves the credential
+// names in FleetCamera.Credentials
+func WithFleetCredentials(p CredentialsProvider) FleetOption {
+	return func(f *Fleet) {
+		f.credentials = p
+	}
+}
+
+// NewFleet creates an empty Fleet
+func NewFleet(opts ...FleetOption) *Fleet {
+	f := &Fleet{
+		clients:     make(map[string]*Client),
+		tenants:     make(map[string]string),
+		concurrency: defaultFleetConcurrency,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Add registers a client under name, replacing any existing client with the
+// same name
+func (f *Fleet) Add(name string, client *Client) {
+	f.mu.Lock()
+	f.clients[name] = client
+	delete(f.tenants, name)
+	f.mu.Unlock()
+}
+
+// Credentials are a camera's API user and password
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// CredentialsProvider resolves credentials by name, so fleet configuration
+// can reference secrets held in a vault or secret store rather than
+// embedding them
+type CredentialsProvider interface {
+	Credentials(ctx context.Context, name string) (Credentials, error)
+}
+
+// StaticCredentials is a CredentialsProvider backed by a map
+type StaticCredentials map[string]Credentials
+
+// Credentials returns the credentials stored under name
+func (s StaticCredentials) Credentials(_ context.Context, name string) (Credentials, error) {
+	creds, ok := s[name]
+	if !ok {
+		return Credentials{}, fmt.Errorf("unknown credentials %q", name)
+	}
+	return creds, nil
+}
+
+// FleetCamera describes a camera for Fleet.AddCamera. Each camera gets its
+// own client, HTTP transport and session, so cameras of different tenants
+// share no connections, tokens or TLS state.
+type FleetCamera struct {
+	Host        string // Camera host or host:port
+	Tenant      string // Owner of the camera, e.g. a customer ID
+	Credentials string // Name of the credentials in the fleet's CredentialsProvider; "" for none
+	HTTPS       bool   // Use HTTPS
+
+	// TLSConfig is cloned for the camera; nil keeps the client default
+	TLSConfig *tls.Config
+	// Transport replaces the HTTP transport, e.g. to route a tenant's
+	// cameras through its own proxy or VPN. An *http.Transport is cloned
+	// per camera; nil creates a fresh one. TLSConfig requires an
+	// *http.Transport, since other transports cannot take it.
+	Transport http.RoundTripper
+	// Options are applied after the settings above
+	Options []Option
+}
+
+// AddCamera creates a client for cam, resolving its credentials through the
+// fleet's CredentialsProvider, and registers it under name, replacing any
+// existing client with that name. Calling it again re-resolves the
+// credentials, e.g. after a rotation.
+func (f *Fleet) AddCamera(ctx context.Context, name string, cam FleetCamera) (*Client, error) {
+	f.mu.RLock()
+	provider := f.credentials
+	f.mu.RUnlock()
+
+	var opts []Option
+	if cam.Transport != nil {
+		transport := cam.Transport
+		t, ok := transport.(*http.Transport)
+		if !ok && cam.TLSConfig != nil {
+			return nil, fmt.Errorf("camera %s: TLSConfig cannot be applied to a %T transport; configure TLS on the transport instead", name, transport)
+		}
+		if ok {
+			// A shared *http.Transport would pool connections and TLS
+			// sessions across cameras; TLSConfig below must not leak into it
+			transport = t.Clone()
+		}
+		opts = append(opts, WithHTTPClient(&http.Client{
+			Timeout:   defaultFleetTimeout,
+			Transport: transport,
+		}))
+	}
+	if cam.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(cam.TLSConfig.Clone()))
+	}
+	if cam.HTTPS {
+		opts = append(opts, WithHTTPS(true))
+	}
+	if cam.Credentials != "" {
+		if provider == nil {
+			return nil, fmt.Errorf("camera %s: credentials %q referenced but the fleet has no CredentialsProvider", name, cam.Credentials)
+		}
+		creds, err := provider.Credentials(ctx, cam.Credentials)
+		if err != nil {
+			return nil, fmt.Errorf("camera %s: failed to resolve credentials: %w", name, err)
+		}
+		opts = append(opts, WithCredentials(creds.Username, creds.Password))
+	}
+	opts = append(opts, cam.Options...)
+
+	client := NewClient(cam.Host, opts...)
+
+	f.mu.Lock()
+	f.clients[name] = client
+	f.tenants[name] = cam.Tenant
+	f.mu.Unlock()
+	return client, nil
+}
+
+// Tenant returns the tenant of the camera registered under name, or "" for
+// cameras added with Add
+func (f *Fleet) Tenant(name string) string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.tenants[name]
+}
+
+// Remove unregisters the client with the given name
+func (f *Fleet) Remove(name string) {
+	f.mu.Lock()
+	delete(f.clients, name)
+	delete(f.tenants, name)
+	f.mu.Unlock()
+}
+
+// Get returns the client registered under name
+func (f *Fleet) Get(name string) (*Client, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	c, ok := f.clients[name]
+	return c, ok
+}
+
+// Names returns the registered camera names in sorted order
+func (f *Fleet) Names() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.clients))
+	for name := range f.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Len returns the number of registered cameras
+func (f *Fleet) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.clients)
+}
+
+// Each calls fn for every camera concurrently, bounded by the fleet's
+// concurrency limit, and returns the errors keyed by camera name. Cameras
+// for which fn succeeded are absent from the returned map.
+func (f *Fleet) Each(ctx context.Context, fn func(ctx context.Context, name string, client *Client) error) map[string]error {
+	return f.each(ctx, func(string) bool { return true }, fn)
+}
+
+// EachTenant is Each restricted to the cameras of one tenant
+func (f *Fleet) EachTenant(ctx context.Context, tenant string, fn func(ctx context.Context, name string, client *Client) error) map[string]error {
+	return f.each(ctx, func(name string) bool { return f.tenants[name] == tenant }, fn)
+}
+
+// each runs fn for the cameras whose names satisfy match, which is called
+// with f.mu held
+func (f *Fleet) each(ctx context.Context, match func(name string) bool, fn func(ctx context.Context, name string, client *Client) error) map[string]error {
+	f.mu.RLock()
+	clients := make(map[string]*Client, len(f.clients))
+	for name, c := range f.clients {
+		if match(name) {
+			clients[name] = c
+		}
+	}
+	concurrency := f.concurrency
+	f.mu.RUnlock()
+
+	var (
+		mu   sync.Mutex
+		errs = make(map[string]error)
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, concurrency)
+	)
+
+	for name, c := range clients {
+		wg.Add(1)
+		go func(name string, c *Client) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				errs[name] = ctx.Err()
+				mu.Unlock()
+				return
+			}
+
+			if err := fn(ctx, name, c); err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, c)
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,164 @@
+package reolink
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFleet_Registry(t *testing.T) {
+	fleet := NewFleet()
+	fleet.Add("garage", NewClient("192.168.1.11"))
+	fleet.Add("door", NewClient("192.168.1.10"))
+
+	if fleet.Len() != 2 {
+		t.Errorf("expected 2 cameras, got %d", fleet.Len())
+	}
+	names := fleet.Names()
+	if len(names) != 2 || names[0] != "door" || names[1] != "garage" {
+		t.Errorf("expected sorted names, got %v", names)
+	}
+
+	c, ok := fleet.Get("door")
+	if !ok || c.Host() != "192.168.1.10" {
+		t.Error("expected to find door camera")
+	}
+
+	fleet.Remove("door")
+	if _, ok := fleet.Get("door"); ok {
+		t.Error("expected door camera to be removed")
+	}
+}
+
+func TestFleet_Each(t *testing.T) {
+	fleet := NewFleet(WithFleetConcurrency(2))
+	for _, name := range []string{"a", "b", "c", "d"} {
+		fleet.Add(name, NewClient(name))
+	}
+
+	var calls, running, maxRunning int32
+	errBroken := errors.New("broken")
+	errs := fleet.Each(t.Context(), func(ctx context.Context, name string, c *Client) error {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		if name == "c" {
+			return errBroken
+		}
+		return nil
+	})
+
+	if calls != 4 {
+		t.Errorf("expected 4 calls, got %d", calls)
+	}
+	if maxRunning > 2 {
+		t.Errorf("expected at most 2 concurrent calls, got %d", maxRunning)
+	}
+	if len(errs) != 1 || !errors.Is(errs["c"], errBroken) {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestFleet_AddCamera(t *testing.T) {
+	var gotUser [2]string
+	servers := [2]*httptest.Server{}
+	for i := range servers {
+		i := i
+		servers[i] = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqs []Request
+			json.NewDecoder(r.Body).Decode(&reqs)
+			if len(reqs) > 0 && reqs[0].Cmd == "Login" {
+				param, _ := json.Marshal(reqs[0].Param)
+				var login LoginParam
+				json.Unmarshal(param, &login)
+				gotUser[i] = login.User.UserName
+			}
+			w.Write([]byte(`[{"cmd": "Login", "code": 0, "value": {"Token": {"name": "tok", "leaseTime": 3600}}}]`))
+		}))
+		defer servers[i].Close()
+	}
+
+	shared := &http.Transport{}
+	fleet := NewFleet(WithFleetCredentials(StaticCredentials{
+		"acme":   {Username: "acme-admin", Password: "a"},
+		"globex": {Username: "globex-admin", Password: "g"},
+	}))
+	for i, tenant := range []string{"acme", "globex"} {
+		_, err := fleet.AddCamera(t.Context(), tenant+"-cam", FleetCamera{
+			Host:        strings.TrimPrefix(servers[i].URL, "https://"),
+			Tenant:      tenant,
+			Credentials: tenant,
+			HTTPS:       true,
+			TLSConfig:   &tls.Config{InsecureSkipVerify: true},
+			Transport:   shared,
+		})
+		if err != nil {
+			t.Fatalf("AddCamera(%s) failed: %v", tenant, err)
+		}
+	}
+	if shared.TLSClientConfig != nil && shared.TLSClientConfig.InsecureSkipVerify {
+		t.Error("the shared transport was modified")
+	}
+
+	errs := fleet.Each(t.Context(), func(ctx context.Context, name string, c *Client) error {
+		return c.Login(ctx)
+	})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if gotUser[0] != "acme-admin" || gotUser[1] != "globex-admin" {
+		t.Errorf("unexpected logins: %v", gotUser)
+	}
+
+	var names []string
+	fleet.EachTenant(t.Context(), "globex", func(ctx context.Context, name string, c *Client) error {
+		names = append(names, name)
+		return nil
+	})
+	if len(names) != 1 || names[0] != "globex-cam" || fleet.Tenant("globex-cam") != "globex" {
+		t.Errorf("unexpected tenant cameras: %v", names)
+	}
+}
+
+func TestFleet_AddCamera_Credentials(t *testing.T) {
+	fleet := NewFleet()
+	if _, err := fleet.AddCamera(t.Context(), "cam", FleetCamera{Host: "cam", Credentials: "x"}); err == nil {
+		t.Error("expected error without a CredentialsProvider")
+	}
+
+	fleet = NewFleet(WithFleetCredentials(StaticCredentials{}))
+	if _, err := fleet.AddCamera(t.Context(), "cam", FleetCamera{Host: "cam", Credentials: "x"}); err == nil {
+		t.Error("expected error for unknown credentials")
+	}
+	if fleet.Len() != 0 {
+		t.Error("camera registered despite the error")
+	}
+}
+
+func TestFleet_AddCamera_TLSConfigNeedsHTTPTransport(t *testing.T) {
+	fleet := NewFleet()
+	_, err := fleet.AddCamera(t.Context(), "cam", FleetCamera{
+		Host:      "cam",
+		HTTPS:     true,
+		TLSConfig: &tls.Config{ServerName: "cam.example"},
+		Transport: detachedTransport{next: http.DefaultTransport},
+	})
+	if err == nil {
+		t.Fatal("expected error for a TLSConfig the transport cannot take")
+	}
+	if fleet.Len() != 0 {
+		t.Error("camera registered despite the error")
+	}
+}
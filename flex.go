@@ -0,0 +1,115 @@
+package reolink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// FlexInt is an int that also decodes from a quoted number, as some
+// firmware versions return numeric fields such as "code":"0" or
+// "state":"1". An empty string decodes as 0. It encodes as a plain number.
+//
+// State values use FlexInt, and the response envelope decodes its code and
+// rspCode the same way, so that every call survives such firmware; other
+// response fields are converted by DecodeLenient.
+type FlexInt int
+
+// UnmarshalJSON accepts a number or a quoted number
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	s, err := flexNumber(data)
+	if err != nil || s == "" {
+		return err
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		// Integral values written as floats, e.g. "1.0"
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || f != float64(int64(f)) {
+			return fmt.Errorf("invalid integer %s", data)
+		}
+		n = int64(f)
+	}
+	*i = FlexInt(n)
+	return nil
+}
+
+// FlexFloat is a float64 that also decodes from a quoted number, like
+// FlexInt
+type FlexFloat float64
+
+// UnmarshalJSON accepts a number or a quoted number
+func (f *FlexFloat) UnmarshalJSON(data []byte) error {
+	s, err := flexNumber(data)
+	if err != nil || s == "" {
+		return err
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", data)
+	}
+	*f = FlexFloat(v)
+	return nil
+}
+
+// flexNumber returns the text of a JSON number or quoted number, or "" for
+// null and empty strings
+func flexNumber(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", err
+		}
+		return string(bytes.TrimSpace([]byte(s))), nil
+	}
+	return string(data), nil
+}
+
+// BoolInt is a switch the API encodes as 0 or 1, such as the enable and
+// state fields. It encodes as a number and also decodes from quoted
+// numbers and JSON booleans.
+//
+//	ftp.Schedule.Enable = reolink.BoolOf(true)
+//	if status.Online.Bool() { ... }
+type BoolInt int
+
+// BoolOf returns 1 for true and 0 for false
+func BoolOf(b bool) BoolInt {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Bool reports whether the switch is on
+func (b BoolInt) Bool() bool {
+	return b != 0
+}
+
+// Set turns the switch on or off
+func (b *BoolInt) Set(on bool) {
+	*b = BoolOf(on)
+}
+
+// UnmarshalJSON accepts a number, a quoted number or a boolean
+func (b *BoolInt) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		*b = 1
+		return nil
+	case "false":
+		*b = 0
+		return nil
+	}
+	var i FlexInt
+	if err := i.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*b = BoolInt(i)
+	return nil
+}
@@ -0,0 +1,122 @@
+package reolink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFlexInt(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    FlexInt
+		wantErr bool
+	}{
+		{`1`, 1, false},
+		{`"1"`, 1, false},
+		{`" -9 "`, -9, false},
+		{`"2.0"`, 2, false},
+		{`""`, 0, false},
+		{`null`, 0, false},
+		{`"2.5"`, 0, true},
+		{`"on"`, 0, true},
+	}
+	for _, tt := range tests {
+		var got FlexInt
+		err := json.Unmarshal([]byte(tt.input), &got)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, %v; want %d, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	data, err := json.Marshal(struct{ N FlexInt }{7})
+	if err != nil || string(data) != `{"N":7}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+}
+
+func TestFlexFloat(t *testing.T) {
+	var f struct{ A, B FlexFloat }
+	if err := json.Unmarshal([]byte(`{"A": 0.25, "B": "0.5"}`), &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.A != 0.25 || f.B != 0.5 {
+		t.Errorf("unexpected values: %+v", f)
+	}
+}
+
+func TestQuotedNumbersInResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cmd") {
+		case "GetMdState":
+			w.Write([]byte(`[{"cmd": "GetMdState", "code": "0", "value": {"state": "1"}}]`))
+		default:
+			w.Write([]byte(`[{"cmd": "GetAiState", "code": "1", "error": {"rspCode": "-9", "detail": "not support"}}]`))
+		}
+	}))
+	defer server.Close()
+
+	// Strict mode: decoded by the field types, not by lenient conversion
+	client := newTestClient(server)
+	WithDecodeMode(DecodeStrict)(client)
+
+	state, err := client.Alarm.GetMdState(t.Context(), 0)
+	if err != nil || state != 1 {
+		t.Errorf("GetMdState = %d, %v", state, err)
+	}
+	if _, err := client.AI.GetAiState(t.Context(), 0); !IsNotSupported(err) {
+		t.Errorf("expected a not supported error, got %v", err)
+	}
+}
+
+func TestBoolInt(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BoolInt
+		wantErr bool
+	}{
+		{`0`, 0, false},
+		{`1`, 1, false},
+		{`"1"`, 1, false},
+		{`true`, 1, false},
+		{`false`, 0, false},
+		{`null`, 0, false},
+		{`"yes"`, 0, true},
+	}
+	for _, tt := range tests {
+		var got BoolInt
+		err := json.Unmarshal([]byte(tt.input), &got)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, %v; want %d, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	var b BoolInt
+	if b.Bool() {
+		t.Error("zero BoolInt is on")
+	}
+	b.Set(true)
+	if !b.Bool() || b != 1 {
+		t.Errorf("after Set(true) = %d", b)
+	}
+	if BoolOf(false) != 0 || BoolOf(true) != 1 {
+		t.Error("BoolOf did not map to 0/1")
+	}
+
+	// The wire format stays 0/1
+	data, err := json.Marshal(Osd{OsdTime: OsdTime{Enable: BoolOf(true)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		OsdTime map[string]interface{} `json:"osdTime"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw.OsdTime["enable"] != float64(1) {
+		t.Errorf("osdTime.enable = %v, want 1", raw.OsdTime["enable"])
+	}
+}
@@ -0,0 +1,191 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrUploadMissing is returned by VerifyFTPUpload when no upload for an
+// event appeared on the FTP server in time
+var ErrUploadMissing = errors.New("upload not found on FTP server")
+
+// FTPFile is a file on the FTP server a camera uploads to
+type FTPFile struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// FTPLister lists the files on the FTP server a camera uploads to. The
+// camera's API does not report upload results, so delivery is checked on
+// the server itself; implement FTPLister with the FTP client of your
+// choice.
+type FTPLister interface {
+	// List returns the files below dir, including its subdirectories,
+	// where cameras create per-day folders
+	List(ctx context.Context, dir string) ([]FTPFile, error)
+}
+
+// UploadCheckOptions configures VerifyFTPUpload and MonitorFTPUploads
+type UploadCheckOptions struct {
+	// Dir is the directory to search (default: the camera's configured
+	// remote directory)
+	Dir string
+	// Timeout is how long after the event the upload may take (default: 2m)
+	Timeout time.Duration
+	// Interval is how often the server is listed (default: 5s)
+	Interval time.Duration
+	// ClockSkew is how far the server's clock may lag the SDK's (default: 30s)
+	ClockSkew time.Duration
+}
+
+// UploadResult is a confirmed upload for an event
+type UploadResult struct {
+	Event Event
+	File  FTPFile
+	Delay time.Duration // From the event to the file's modification time
+}
+
+// VerifyFTPUpload polls the FTP server through lister until a file modified
+// at or after ev.Time appears, and returns it. If none appears within
+// Timeout it returns ErrUploadMissing, which usually means the camera's FTP
+// configuration is broken even though TestFtp succeeds.
+//
+// With a MetricsRecorder configured, every check counts towards
+// reolink_ftp_uploads_total with a result label of "delivered" or
+// "missing".
+func (n *NetworkAPI) VerifyFTPUpload(ctx context.Context, lister FTPLister, ev Event, opts UploadCheckOptions) (*UploadResult, error) {
+	if lister == nil {
+		return nil, fmt.Errorf("lister must not be nil")
+	}
+	opts, err := n.uploadCheckDefaults(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	n.client.log(ctx).Debug("verifying FTP upload: event=%s channel=%d dir=%s", ev.Type, ev.Channel, opts.Dir)
+
+	deadline := time.NewTimer(time.Until(ev.Time.Add(opts.Timeout)))
+	defer deadline.Stop()
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		files, err := lister.List(ctx, opts.Dir)
+		if err != nil {
+			n.client.log(ctx).Warn("failed to list FTP directory %s: %v", opts.Dir, err)
+		}
+		if file, ok := firstUploadSince(files, ev.Time.Add(-opts.ClockSkew)); ok {
+			n.recordUpload("delivered")
+			n.client.log(ctx).Info("FTP upload confirmed: event=%s file=%s", ev.Type, file.Path)
+			return &UploadResult{Event: ev, File: file, Delay: file.ModTime.Sub(ev.Time)}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			n.recordUpload("missing")
+			n.client.log(ctx).Warn("no FTP upload for %s event on channel %d within %s", ev.Type, ev.Channel, opts.Timeout)
+			return nil, fmt.Errorf("%w: %s event on channel %d at %s", ErrUploadMissing, ev.Type, ev.Channel, ev.Time.Format(time.RFC3339))
+		case <-ticker.C:
+		}
+	}
+}
+
+// MonitorFTPUploads runs source and verifies an FTP upload for every
+// EventMotionStart and EventAIStart it emits. Events are passed on to
+// handler, followed by an EventUploadMissing whose Detail is the triggering
+// event type when an upload does not arrive. It blocks until source
+// returns and all checks are done, and returns source's error.
+func (n *NetworkAPI) MonitorFTPUploads(ctx context.Context, source EventSource, lister FTPLister, opts UploadCheckOptions, handler EventHandler) error {
+	if source == nil || lister == nil || handler == nil {
+		return fmt.Errorf("source, lister and handler must not be nil")
+	}
+	opts, err := n.uploadCheckDefaults(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	// Checks report from their own goroutines; handler is still called
+	// from one goroutine at a time
+	var (
+		mu     sync.Mutex
+		checks sync.WaitGroup
+	)
+	emit := func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		handler(ev)
+	}
+	defer checks.Wait()
+
+	return source(ctx, func(ev Event) {
+		emit(ev)
+		if ev.Type != EventMotionStart && ev.Type != EventAIStart {
+			return
+		}
+		checks.Add(1)
+		go func() {
+			defer checks.Done()
+			_, err := n.VerifyFTPUpload(ctx, lister, ev, opts)
+			if errors.Is(err, ErrUploadMissing) {
+				emit(Event{
+					Type:    EventUploadMissing,
+					Host:    ev.Host,
+					Channel: ev.Channel,
+					Time:    time.Now(),
+					Detail:  string(ev.Type),
+				})
+			}
+		}()
+	})
+}
+
+// uploadCheckDefaults fills in defaults for unset options, reading the
+// remote directory from the camera if needed
+func (n *NetworkAPI) uploadCheckDefaults(ctx context.Context, opts UploadCheckOptions) (UploadCheckOptions, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.ClockSkew <= 0 {
+		opts.ClockSkew = 30 * time.Second
+	}
+	if opts.Dir == "" {
+		ftp, err := n.GetFtp(ctx)
+		if err != nil {
+			return opts, fmt.Errorf("failed to get FTP configuration: %w", err)
+		}
+		opts.Dir = ftp.RemoteDir
+		if opts.Dir == "" {
+			opts.Dir = "/"
+		}
+	}
+	return opts, nil
+}
+
+// recordUpload counts an upload check result
+func (n *NetworkAPI) recordUpload(result string) {
+	n.client.addCounter("reolink_ftp_uploads_total", 1, map[string]string{"result": result})
+}
+
+// firstUploadSince returns the earliest file modified at or after since
+func firstUploadSince(files []FTPFile, since time.Time) (FTPFile, bool) {
+	var first FTPFile
+	found := false
+	for _, f := range files {
+		if f.ModTime.Before(since) {
+			continue
+		}
+		if !found || f.ModTime.Before(first.ModTime) {
+			first, found = f, true
+		}
+	}
+	return first, found
+}
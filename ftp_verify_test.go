@@ -0,0 +1,99 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeFTP is an FTPLister over an in-memory file list
+type fakeFTP struct {
+	mu    sync.Mutex
+	dirs  []string
+	files []FTPFile
+}
+
+func (f *fakeFTP) List(ctx context.Context, dir string) ([]FTPFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dirs = append(f.dirs, dir)
+	return append([]FTPFile(nil), f.files...), nil
+}
+
+func (f *fakeFTP) add(file FTPFile) {
+	f.mu.Lock()
+	f.files = append(f.files, file)
+	f.mu.Unlock()
+}
+
+func TestNetworkAPI_VerifyFTPUpload(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetFtp": `{"Ftp": {"server": "ftp.local", "port": 21, "remoteDir": "/cams/front"}}`,
+	})
+	client := server.client()
+	metrics := NewMetrics()
+	WithMetrics(metrics)(client)
+
+	now := time.Now()
+	lister := &fakeFTP{files: []FTPFile{{Path: "/cams/front/old.jpg", ModTime: now.Add(-time.Hour)}}}
+	ev := Event{Type: EventMotionStart, Channel: 0, Time: now}
+	opts := UploadCheckOptions{Timeout: 2 * time.Second, Interval: 10 * time.Millisecond}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		lister.add(FTPFile{Path: "/cams/front/2026/clip.mp4", ModTime: now.Add(2 * time.Second)})
+	}()
+	result, err := client.Network.VerifyFTPUpload(t.Context(), lister, ev, opts)
+	if err != nil {
+		t.Fatalf("VerifyFTPUpload failed: %v", err)
+	}
+	if result.File.Path != "/cams/front/2026/clip.mp4" || result.Delay != 2*time.Second {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if lister.dirs[0] != "/cams/front" {
+		t.Errorf("expected the camera's remote directory, got %s", lister.dirs[0])
+	}
+
+	opts.Dir = "/elsewhere"
+	opts.Timeout = 50 * time.Millisecond
+	ev.Time = time.Now()
+	if _, err := client.Network.VerifyFTPUpload(t.Context(), &fakeFTP{}, ev, opts); !errors.Is(err, ErrUploadMissing) {
+		t.Errorf("expected ErrUploadMissing, got %v", err)
+	}
+
+	results := map[string]float64{}
+	for _, m := range metrics.Snapshot() {
+		if m.Name == "reolink_ftp_uploads_total" {
+			results[m.Labels["result"]] = m.Value
+		}
+	}
+	if results["delivered"] != 1 || results["missing"] != 1 {
+		t.Errorf("unexpected upload counters: %v", results)
+	}
+}
+
+func TestNetworkAPI_MonitorFTPUploads(t *testing.T) {
+	client := newCmdServer(t, map[string]string{}).client()
+	source := func(ctx context.Context, handler EventHandler) error {
+		handler(Event{Type: EventMotionStart, Channel: 2, Time: time.Now()})
+		handler(Event{Type: EventMotionStop, Channel: 2, Time: time.Now()})
+		return nil
+	}
+
+	var events []Event
+	err := client.Network.MonitorFTPUploads(t.Context(), source, &fakeFTP{}, UploadCheckOptions{
+		Dir:      "/",
+		Timeout:  30 * time.Millisecond,
+		Interval: 10 * time.Millisecond,
+	}, func(ev Event) {
+		events = append(events, ev)
+	})
+	if err != nil {
+		t.Fatalf("MonitorFTPUploads failed: %v", err)
+	}
+	if len(events) != 3 || events[2].Type != EventUploadMissing || events[2].Detail != string(EventMotionStart) || events[2].Channel != 2 {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
@@ -0,0 +1,164 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+)
+
+// HardeningOptions configures HardenLANOnly
+type HardeningOptions struct {
+	// KeepHTTP leaves plain HTTP enabled alongside HTTPS, e.g. for NVRs or
+	// integrations that cannot speak TLS
+	KeepHTTP bool
+}
+
+// HardeningChange describes a single setting changed by HardenLANOnly
+type HardeningChange struct {
+	Setting string // Setting name, e.g. "P2P"
+	Before  string // Value before hardening
+	After   string // Value after hardening
+}
+
+// HardeningReport summarizes the result of HardenLANOnly
+type HardeningReport struct {
+	Changes  []HardeningChange // Settings that were changed
+	Verified bool              // Whether every setting read back as expected
+	Failures []string          // Settings that did not verify
+}
+
+// HardenLANOnly restricts the camera to LAN-only operation: it disables P2P,
+// UPnP, DDNS and push notifications, enables HTTPS and disables plain HTTP
+// (unless opts.KeepHTTP is set), then reads every setting back to verify it.
+//
+// Settings that are already hardened are left untouched. If the client is
+// connected over HTTP and HTTP gets disabled, the client switches itself to
+// HTTPS so the session keeps working.
+func (s *SecurityAPI) HardenLANOnly(ctx context.Context, opts HardeningOptions) (*HardeningReport, error) {
+	s.client.log(ctx).Warn("applying LAN-only hardening to camera at %s", s.client.Host())
+
+	network := s.client.Network
+	report := &HardeningReport{}
+
+	p2p, err := network.GetP2p(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get P2P configuration: %w", err)
+	}
+	if p2p.Enable.Bool() {
+		p2p.Enable = 0
+		if err := network.SetP2p(ctx, *p2p); err != nil {
+			return report, fmt.Errorf("failed to disable P2P: %w", err)
+		}
+		report.Changes = append(report.Changes, HardeningChange{Setting: "P2P", Before: "enabled", After: "disabled"})
+	}
+
+	upnp, err := network.GetUpnp(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get UPnP configuration: %w", err)
+	}
+	if upnp.Enable.Bool() {
+		upnp.Enable = 0
+		if err := network.SetUpnp(ctx, *upnp); err != nil {
+			return report, fmt.Errorf("failed to disable UPnP: %w", err)
+		}
+		report.Changes = append(report.Changes, HardeningChange{Setting: "UPnP", Before: "enabled", After: "disabled"})
+	}
+
+	ddns, err := network.GetDdns(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get DDNS configuration: %w", err)
+	}
+	if ddns.Enable.Bool() {
+		ddns.Enable = 0
+		if err := network.SetDdns(ctx, *ddns); err != nil {
+			return report, fmt.Errorf("failed to disable DDNS: %w", err)
+		}
+		report.Changes = append(report.Changes, HardeningChange{Setting: "DDNS", Before: "enabled", After: "disabled"})
+	}
+
+	push, err := network.GetPush(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get push configuration: %w", err)
+	}
+	if push.Schedule.Enable.Bool() {
+		push.Schedule.Enable = 0
+		if err := network.SetPush(ctx, *push); err != nil {
+			return report, fmt.Errorf("failed to disable push: %w", err)
+		}
+		report.Changes = append(report.Changes, HardeningChange{Setting: "Push", Before: "enabled", After: "disabled"})
+	}
+
+	netPort, err := network.GetNetPort(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get port configuration: %w", err)
+	}
+	var wantHTTP BoolInt
+	if opts.KeepHTTP {
+		wantHTTP = netPort.HTTPEnable
+	}
+	if !netPort.HTTPSEnable.Bool() || netPort.HTTPEnable != wantHTTP {
+		if !netPort.HTTPSEnable.Bool() {
+			report.Changes = append(report.Changes, HardeningChange{Setting: "HTTPS", Before: "disabled", After: "enabled"})
+		}
+		if netPort.HTTPEnable != wantHTTP {
+			report.Changes = append(report.Changes, HardeningChange{Setting: "HTTP", Before: "enabled", After: "disabled"})
+		}
+		netPort.HTTPSEnable = 1
+		netPort.HTTPEnable = wantHTTP
+		if err := network.SetNetPort(ctx, *netPort); err != nil {
+			return report, fmt.Errorf("failed to update port configuration: %w", err)
+		}
+	}
+
+	s.verifyHardening(ctx, report, wantHTTP)
+
+	s.client.log(ctx).Info("LAN-only hardening complete: changes=%d verified=%t", len(report.Changes), report.Verified)
+	return report, nil
+}
+
+// verifyHardening reads back every hardened setting and records mismatches
+func (s *SecurityAPI) verifyHardening(ctx context.Context, report *HardeningReport, wantHTTP BoolInt) {
+	network := s.client.Network
+	fail := func(format string, args ...interface{}) {
+		report.Failures = append(report.Failures, fmt.Sprintf(format, args...))
+	}
+
+	if p2p, err := network.GetP2p(ctx); err != nil {
+		fail("P2P: %v", err)
+	} else if p2p.Enable.Bool() {
+		fail("P2P: still enabled")
+	}
+
+	if upnp, err := network.GetUpnp(ctx); err != nil {
+		fail("UPnP: %v", err)
+	} else if upnp.Enable.Bool() {
+		fail("UPnP: still enabled")
+	}
+
+	if ddns, err := network.GetDdns(ctx); err != nil {
+		fail("DDNS: %v", err)
+	} else if ddns.Enable.Bool() {
+		fail("DDNS: still enabled")
+	}
+
+	if push, err := network.GetPush(ctx); err != nil {
+		fail("Push: %v", err)
+	} else if push.Schedule.Enable.Bool() {
+		fail("Push: still enabled")
+	}
+
+	if netPort, err := network.GetNetPort(ctx); err != nil {
+		fail("NetPort: %v", err)
+	} else {
+		if !netPort.HTTPSEnable.Bool() {
+			fail("HTTPS: still disabled")
+		}
+		if netPort.HTTPEnable != wantHTTP {
+			fail("HTTP: still enabled")
+		}
+	}
+
+	report.Verified = len(report.Failures) == 0
+	for _, f := range report.Failures {
+		s.client.log(ctx).Warn("hardening verification failed: %s", f)
+	}
+}
@@ -0,0 +1,89 @@
+package reolink
+
+import (
+	"net/url"
+	"testing"
+)
+
+func hardeningValues() map[string]string {
+	return map[string]string{
+		"GetP2p":     `{"P2p": {"enable": 1, "uid": "95270000ABCDEFGH"}}`,
+		"SetP2p":     "",
+		"GetUpnp":    `{"Upnp": {"enable": 1}}`,
+		"SetUpnp":    "",
+		"GetDdns":    `{"Ddns": {"enable": 0, "type": "3322"}}`,
+		"SetDdns":    "",
+		"GetPush":    `{"Push": {"schedule": {"enable": 1, "table": ""}}}`,
+		"SetPush":    "",
+		"GetNetPort": `{"NetPort": {"httpEnable": 1, "httpPort": 80, "httpsEnable": 0, "httpsPort": 443, "rtspEnable": 1, "rtspPort": 554}}`,
+		"SetNetPort": "",
+	}
+}
+
+func TestSecurityAPI_HardenLANOnly(t *testing.T) {
+	server := newCmdTLSServer(t, hardeningValues())
+	u, _ := url.Parse(server.URL)
+	client := NewClient(u.Host, WithHTTPS(true))
+
+	report, err := client.Security.HardenLANOnly(t.Context(), HardeningOptions{})
+	if err != nil {
+		t.Fatalf("HardenLANOnly failed: %v", err)
+	}
+
+	if !report.Verified {
+		t.Errorf("expected verified report, failures: %v", report.Failures)
+	}
+
+	// P2P, UPnP, Push, HTTPS, HTTP (DDNS already disabled)
+	if len(report.Changes) != 5 {
+		t.Errorf("expected 5 changes, got %d: %+v", len(report.Changes), report.Changes)
+	}
+	if server.callCount("SetDdns") != 0 {
+		t.Error("expected DDNS to be left untouched")
+	}
+}
+
+func TestSecurityAPI_HardenLANOnly_KeepHTTP(t *testing.T) {
+	server := newCmdServer(t, hardeningValues())
+	client := server.client()
+
+	report, err := client.Security.HardenLANOnly(t.Context(), HardeningOptions{KeepHTTP: true})
+	if err != nil {
+		t.Fatalf("HardenLANOnly failed: %v", err)
+	}
+
+	if !report.Verified {
+		t.Errorf("expected verified report, failures: %v", report.Failures)
+	}
+	for _, c := range report.Changes {
+		if c.Setting == "HTTP" {
+			t.Error("expected HTTP to be kept")
+		}
+	}
+	if client.useHTTPS {
+		t.Error("expected client to stay on HTTP")
+	}
+}
+
+func TestSecurityAPI_HardenLANOnly_VerificationFailure(t *testing.T) {
+	server := newCmdServer(t, hardeningValues())
+	client := server.client()
+
+	// Firmware that acknowledges SetUpnp but keeps UPnP enabled
+	server.set("GetUpnp", `{"Upnp": {"enable": 1}}`)
+	report := &HardeningReport{}
+	client.Security.verifyHardening(t.Context(), report, 1)
+
+	if report.Verified {
+		t.Error("expected verification failure")
+	}
+	found := false
+	for _, f := range report.Failures {
+		if f == "UPnP: still enabled" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected UPnP failure, got %v", report.Failures)
+	}
+}
@@ -0,0 +1,221 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ImageProfile is the desired image and ISP configuration of a channel.
+// Some firmware resets these settings after a reboot or a day/night
+// transition; EnforceImageProfile and WatchImageProfile put them back.
+type ImageProfile struct {
+	Channel int
+	Image   *Image // Desired image settings; nil leaves them unchecked
+	Isp     *Isp   // Desired ISP settings; nil leaves them unchecked
+	// Ignore lists settings that may change freely, by JSON name with a
+	// section prefix, e.g. "Isp.dayNight"
+	Ignore []string
+}
+
+// ImageDrift is a setting that differs from its ImageProfile
+type ImageDrift struct {
+	Setting string // Section and JSON name, e.g. "Image.bright"
+	Want    interface{}
+	Got     interface{}
+}
+
+// String returns e.g. "Image.bright: 128 -> 140"
+func (d ImageDrift) String() string {
+	return fmt.Sprintf("%s: %v -> %v", d.Setting, d.Want, d.Got)
+}
+
+// CaptureImageProfile returns the channel's current image and ISP settings
+// as a profile, for enforcing later
+func (v *VideoAPI) CaptureImageProfile(ctx context.Context, channel int) (*ImageProfile, error) {
+	image, err := v.GetImage(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image settings: %w", err)
+	}
+	isp, err := v.GetIsp(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ISP settings: %w", err)
+	}
+	return &ImageProfile{Channel: channel, Image: image, Isp: isp}, nil
+}
+
+// CheckImageProfile compares the channel's settings with p and returns the
+// settings that drifted, sorted by name
+func (v *VideoAPI) CheckImageProfile(ctx context.Context, p ImageProfile) ([]ImageDrift, error) {
+	drift, _, _, err := v.checkImageProfile(ctx, p)
+	return drift, err
+}
+
+// EnforceImageProfile re-applies the sections of p that drifted, keeping
+// ignored settings at their current values, and returns the settings it
+// corrected
+func (v *VideoAPI) EnforceImageProfile(ctx context.Context, p ImageProfile) ([]ImageDrift, error) {
+	drift, image, isp, err := v.checkImageProfile(ctx, p)
+	if err != nil || len(drift) == 0 {
+		return drift, err
+	}
+
+	for _, d := range drift {
+		v.client.log(ctx).Warn("image setting drifted on channel %d, re-applying: %s", p.Channel, d)
+	}
+	if image != nil {
+		if err := v.SetImage(ctx, *image); err != nil {
+			return drift, fmt.Errorf("failed to re-apply image settings: %w", err)
+		}
+	}
+	if isp != nil {
+		if err := v.SetIsp(ctx, *isp); err != nil {
+			return drift, fmt.Errorf("failed to re-apply ISP settings: %w", err)
+		}
+	}
+	return drift, nil
+}
+
+// WatchImageProfile enforces p every interval and calls handler with
+// EventImageDrift for each check that corrected settings; Detail lists
+// them. Errors are logged and retried on the next tick. It blocks until ctx
+// is done and returns ctx.Err().
+func (v *VideoAPI) WatchImageProfile(ctx context.Context, interval time.Duration, p ImageProfile, handler EventHandler) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler must not be nil")
+	}
+
+	poll := func() {
+		drift, err := v.EnforceImageProfile(ctx, p)
+		if err != nil {
+			v.client.log(ctx).Warn("image profile check failed: %v", err)
+			return
+		}
+		if len(drift) == 0 {
+			return
+		}
+		details := make([]string, len(drift))
+		for i, d := range drift {
+			details[i] = d.String()
+		}
+		handler(Event{
+			Type:    EventImageDrift,
+			Host:    v.client.Host(),
+			Channel: p.Channel,
+			Time:    time.Now(),
+			Detail:  strings.Join(details, ", "),
+		})
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+// checkImageProfile returns the drifted settings and, for each drifted
+// section, the settings to apply
+func (v *VideoAPI) checkImageProfile(ctx context.Context, p ImageProfile) ([]ImageDrift, *Image, *Isp, error) {
+	var drift []ImageDrift
+	var fixImage *Image
+	var fixIsp *Isp
+
+	if p.Image != nil {
+		current, err := v.GetImage(ctx, p.Channel)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get image settings: %w", err)
+		}
+		d, err := diffSettings("Image", p.Image, current, p.Ignore, &fixImage)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		drift = append(drift, d...)
+	}
+	if p.Isp != nil {
+		current, err := v.GetIsp(ctx, p.Channel)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get ISP settings: %w", err)
+		}
+		d, err := diffSettings("Isp", p.Isp, current, p.Ignore, &fixIsp)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		drift = append(drift, d...)
+	}
+
+	if fixImage != nil {
+		fixImage.Channel = p.Channel
+	}
+	if fixIsp != nil {
+		fixIsp.Channel = p.Channel
+	}
+	return drift, fixImage, fixIsp, nil
+}
+
+// diffSettings compares want and got field by field through their JSON
+// form. If any non-ignored field differs, fix is set to got with those
+// fields taken from want.
+func diffSettings[T any](section string, want, got *T, ignore []string, fix **T) ([]ImageDrift, error) {
+	wantFields, err := settingsFields(want)
+	if err != nil {
+		return nil, err
+	}
+	gotFields, err := settingsFields(got)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []ImageDrift
+	for name, w := range wantFields {
+		if name == "channel" || slices.Contains(ignore, section+"."+name) {
+			continue
+		}
+		if g := gotFields[name]; !reflect.DeepEqual(w, g) {
+			drift = append(drift, ImageDrift{Setting: section + "." + name, Want: w, Got: g})
+			gotFields[name] = w
+		}
+	}
+	if len(drift) == 0 {
+		return nil, nil
+	}
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Setting < drift[j].Setting })
+
+	data, err := json.Marshal(gotFields)
+	if err != nil {
+		return nil, err
+	}
+	*fix = new(T)
+	if err := json.Unmarshal(data, *fix); err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// settingsFields returns the JSON fields of v
+func settingsFields(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
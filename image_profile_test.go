@@ -0,0 +1,93 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVideoAPI_EnforceImageProfile(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetImage": `{"Image": {"channel": 0, "bright": 128, "contrast": 128, "saturation": 128, "hue": 128, "sharpen": 128}}`,
+		"GetIsp":   `{"Isp": {"channel": 0, "dayNight": "Auto", "antiFlicker": "50HZ", "rotation": 0, "gain": {"min": 1, "max": 62}}}`,
+		"SetImage": "",
+		"SetIsp":   "",
+	})
+	client := server.client()
+
+	profile, err := client.Video.CaptureImageProfile(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("CaptureImageProfile failed: %v", err)
+	}
+	profile.Ignore = []string{"Isp.dayNight"}
+
+	// Firmware reset after a reboot
+	server.set("GetImage", `{"Image": {"channel": 0, "bright": 100, "contrast": 128, "saturation": 128, "hue": 128, "sharpen": 128}}`)
+	server.set("GetIsp", `{"Isp": {"channel": 0, "dayNight": "Black&White", "antiFlicker": "60HZ", "rotation": 0, "gain": {"min": 1, "max": 62}}}`)
+
+	drift, err := client.Video.CheckImageProfile(t.Context(), *profile)
+	if err != nil {
+		t.Fatalf("CheckImageProfile failed: %v", err)
+	}
+	if len(drift) != 2 || drift[0].Setting != "Image.bright" || drift[1].Setting != "Isp.antiFlicker" {
+		t.Fatalf("unexpected drift: %v", drift)
+	}
+	if server.callCount("SetImage") != 0 {
+		t.Error("CheckImageProfile changed settings")
+	}
+
+	if _, err := client.Video.EnforceImageProfile(t.Context(), *profile); err != nil {
+		t.Fatalf("EnforceImageProfile failed: %v", err)
+	}
+	var isp struct{ Isp Isp }
+	if err := json.Unmarshal(server.lastParam("SetIsp"), &isp); err != nil {
+		t.Fatal(err)
+	}
+	if isp.Isp.AntiFlicker != "50HZ" || isp.Isp.DayNight != "Black&White" || isp.Isp.Gain.Max != 62 {
+		t.Errorf("unexpected re-applied ISP settings: %+v", isp.Isp)
+	}
+
+	drift, err = client.Video.EnforceImageProfile(t.Context(), *profile)
+	if err != nil || len(drift) != 0 {
+		t.Errorf("expected no drift after enforcing, got %v, %v", drift, err)
+	}
+}
+
+func TestVideoAPI_WatchImageProfile(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetImage": `{"Image": {"channel": 1, "bright": 90}}`,
+		"SetImage": "",
+	})
+	client := server.client()
+	profile := ImageProfile{Channel: 1, Image: &Image{Channel: 1, Bright: 128}}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	events := make(chan Event, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Video.WatchImageProfile(ctx, 10*time.Millisecond, profile, func(ev Event) {
+			events <- ev
+		})
+	}()
+
+	select {
+	case ev := <-events:
+		if ev.Type != EventImageDrift || ev.Channel != 1 || !strings.Contains(ev.Detail, "Image.bright: 128 -> 90") {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for EventImageDrift")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if err := client.Video.WatchImageProfile(t.Context(), 0, profile, func(Event) {}); err == nil {
+		t.Error("expected error for zero interval")
+	}
+}
@@ -0,0 +1,154 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// influxUDPPayload is the largest datagram NewInfluxUDPWriter sends, small
+// enough to avoid IP fragmentation on common links
+const influxUDPPayload = 1400
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `)
+	influxTagEscaper         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `)
+)
+
+// WriteInflux writes all series in the InfluxDB line protocol, one line per
+// series: the metric name is the measurement, labels become tags and the
+// value is the float field "value", timestamped ts. Series with NaN or
+// infinite values, which the protocol cannot carry, are skipped.
+func (m *Metrics) WriteInflux(w io.Writer, ts time.Time) error {
+	var b strings.Builder
+	for _, s := range m.Snapshot() {
+		if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+			continue
+		}
+		b.WriteString(influxMeasurementEscaper.Replace(s.Name))
+		for _, k := range slices.Sorted(maps.Keys(s.Labels)) {
+			if s.Labels[k] == "" {
+				continue // Empty tag values are invalid
+			}
+			fmt.Fprintf(&b, ",%s=%s", influxTagEscaper.Replace(k), influxTagEscaper.Replace(s.Labels[k]))
+		}
+		fmt.Fprintf(&b, " value=%s %d\n", strconv.FormatFloat(s.Value, 'g', -1, 64), ts.UnixNano())
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// CountEvents returns an EventHandler that counts events in
+// reolink_events_total, labeled with host, channel and type, and passes
+// them on to next if it is not nil, e.g. to feed event counts from
+// Alarm.WatchEvents to an exporter
+func CountEvents(rec MetricsRecorder, next EventHandler) EventHandler {
+	return func(ev Event) {
+		rec.AddCounter("reolink_events_total", 1, map[string]string{
+			"host":    ev.Host,
+			"channel": strconv.Itoa(ev.Channel),
+			"type":    string(ev.Type),
+		})
+		if next != nil {
+			next(ev)
+		}
+	}
+}
+
+// InfluxExporter periodically writes Metrics in the InfluxDB line protocol,
+// e.g. to a file tailed by Telegraf or, with NewInfluxUDPWriter, to
+// Telegraf's socket_listener or InfluxDB's UDP input
+type InfluxExporter struct {
+	Metrics  *Metrics
+	Writer   io.Writer
+	Interval time.Duration // Export interval (default: 10s, Telegraf's default)
+	// OnError, if not nil, receives write errors; export continues with
+	// the next interval either way
+	OnError func(error)
+}
+
+// Run exports every Interval until ctx is done, with a final export on
+// the way out, and returns ctx.Err()
+func (e *InfluxExporter) Run(ctx context.Context) error {
+	if e.Metrics == nil || e.Writer == nil {
+		return fmt.Errorf("metrics and writer must not be nil")
+	}
+	interval := e.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	export := func() {
+		if err := e.Metrics.WriteInflux(e.Writer, time.Now()); err != nil && e.OnError != nil {
+			e.OnError(err)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			export()
+			return ctx.Err()
+		case <-ticker.C:
+			export()
+		}
+	}
+}
+
+// influxUDPWriter sends line protocol over UDP, packing whole lines into
+// datagrams of at most influxUDPPayload bytes
+type influxUDPWriter struct {
+	conn net.Conn
+}
+
+// NewInfluxUDPWriter returns a writer that sends line protocol to addr
+// ("host:port") over UDP, never splitting a line across datagrams. Lines
+// longer than a datagram are sent on their own. Close it when done.
+func NewInfluxUDPWriter(addr string) (io.WriteCloser, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+	return &influxUDPWriter{conn: conn}, nil
+}
+
+// Write implements io.Writer; p should hold complete lines
+func (u *influxUDPWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := len(p)
+		if n > influxUDPPayload {
+			// Cut after the last newline that fits, or after the first
+			// newline for an oversized line
+			n = bytes.LastIndexByte(p[:influxUDPPayload], '\n') + 1
+			if n == 0 {
+				n = bytes.IndexByte(p, '\n') + 1
+				if n == 0 {
+					n = len(p)
+				}
+			}
+		}
+		if _, err := u.conn.Write(p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Close closes the UDP socket
+func (u *influxUDPWriter) Close() error {
+	return u.conn.Close()
+}
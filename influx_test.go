@@ -0,0 +1,106 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_WriteInflux(t *testing.T) {
+	m := NewMetrics()
+	m.SetGauge("reolink_disk_health", 1, map[string]string{"host": "cam 1", "disk": "0", "empty": ""})
+	m.AddCounter("reolink_events_total", 4, map[string]string{"type": "a,b=c"})
+	m.SetGauge("reolink_storage_days_until_full", math.Inf(1), nil)
+
+	var b strings.Builder
+	ts := time.Unix(1700000000, 5)
+	if err := m.WriteInflux(&b, ts); err != nil {
+		t.Fatalf("WriteInflux failed: %v", err)
+	}
+	want := "reolink_disk_health,disk=0,host=cam\\ 1 value=1 1700000000000000005\n" +
+		"reolink_events_total,type=a\\,b\\=c value=4 1700000000000000005\n"
+	if b.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestCountEvents(t *testing.T) {
+	m := NewMetrics()
+	var passed int
+	handler := CountEvents(m, func(Event) { passed++ })
+	handler(Event{Type: EventMotionStart, Host: "cam1", Channel: 2})
+	handler(Event{Type: EventMotionStart, Host: "cam1", Channel: 2})
+
+	snapshot := m.Snapshot()
+	if len(snapshot) != 1 || snapshot[0].Value != 2 || snapshot[0].Labels["type"] != "motion_start" || snapshot[0].Labels["channel"] != "2" {
+		t.Errorf("unexpected series %+v", snapshot)
+	}
+	if passed != 2 {
+		t.Errorf("expected events to be passed on, got %d", passed)
+	}
+}
+
+func TestInfluxExporter_Run(t *testing.T) {
+	m := NewMetrics()
+	m.SetGauge("reolink_temp", 40, nil)
+
+	var b bytes.Buffer
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	exporter := &InfluxExporter{Metrics: m, Writer: &b}
+	if err := exporter.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "reolink_temp value=40 ") {
+		t.Errorf("expected final export, got %q", b.String())
+	}
+
+	if err := (&InfluxExporter{Metrics: m}).Run(t.Context()); err == nil {
+		t.Error("expected error without writer")
+	}
+}
+
+func TestInfluxUDPWriter(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer pc.Close()
+
+	w, err := NewInfluxUDPWriter(pc.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("NewInfluxUDPWriter failed: %v", err)
+	}
+	defer w.Close()
+
+	line := "reolink_temp,host=cam value=40 1\n"
+	var lines strings.Builder
+	for range 100 {
+		lines.WriteString(line)
+	}
+	if n, err := w.Write([]byte(lines.String())); err != nil || n != lines.Len() {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+
+	var received strings.Builder
+	buf := make([]byte, 65536)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for received.Len() < lines.Len() {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if n > influxUDPPayload || buf[n-1] != '\n' {
+			t.Errorf("datagram of %d bytes not cut at a line end", n)
+		}
+		received.Write(buf[:n])
+	}
+	if received.String() != lines.String() {
+		t.Error("received lines differ from the written ones")
+	}
+}
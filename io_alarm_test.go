@@ -0,0 +1,82 @@
+package reolink
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testIoAlarm = `{"IoAlarm": {"channel": 0,
+	"AlarmIn": [{"index": 0, "name": "Gate", "enable": 1, "type": "NO", "state": 1}, {"index": 1, "enable": 0, "type": "NC", "state": 1}],
+	"AlarmOut": [{"index": 0, "name": "Siren", "enable": 1, "duration": 10, "state": 0}]}}`
+
+func TestAlarmAPI_GetIoAlarm(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"GetIoAlarm": testIoAlarm})
+
+	io, err := server.client().Alarm.GetIoAlarm(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("GetIoAlarm failed: %v", err)
+	}
+	if len(io.In) != 2 || len(io.Out) != 1 {
+		t.Fatalf("expected 2 inputs and 1 output, got %+v", io)
+	}
+	if !io.In[0].Active() {
+		t.Error("expected enabled input in alarm to be active")
+	}
+	if io.In[1].Active() {
+		t.Error("expected disabled input not to be active")
+	}
+	if io.Out[0].Name != "Siren" || io.Out[0].Duration != 10 {
+		t.Errorf("unexpected output: %+v", io.Out[0])
+	}
+}
+
+func TestAlarmAPI_SetIoAlarm(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"GetIoAlarm": testIoAlarm, "SetIoAlarm": ""})
+	client := server.client()
+
+	io, err := client.Alarm.GetIoAlarm(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("GetIoAlarm failed: %v", err)
+	}
+	io.In[1].Enable = 1
+	if err := client.Alarm.SetIoAlarm(t.Context(), *io); err != nil {
+		t.Fatalf("SetIoAlarm failed: %v", err)
+	}
+	var sent IoAlarmValue
+	if err := json.Unmarshal(server.lastParam("SetIoAlarm"), &sent); err != nil {
+		t.Fatalf("failed to parse SetIoAlarm param: %v", err)
+	}
+	if sent.IoAlarm.In[1].Enable != 1 {
+		t.Errorf("expected input 1 enabled, got %+v", sent.IoAlarm.In[1])
+	}
+
+	io.In[0].Type = "open"
+	if err := client.Alarm.SetIoAlarm(t.Context(), *io); err == nil {
+		t.Error("expected error for an invalid contact type")
+	}
+	if n := server.callCount("SetIoAlarm"); n != 1 {
+		t.Errorf("expected 1 SetIoAlarm call, got %d", n)
+	}
+}
+
+func TestAlarmAPI_TriggerAlarmOut(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"AlarmOutCtrl": ""})
+	client := server.client()
+
+	if err := client.Alarm.TriggerAlarmOut(t.Context(), 0, 1, true, 30); err != nil {
+		t.Fatalf("TriggerAlarmOut failed: %v", err)
+	}
+	var param struct {
+		Index    int `json:"index"`
+		State    int `json:"state"`
+		Duration int `json:"duration"`
+	}
+	json.Unmarshal(server.lastParam("AlarmOutCtrl"), &param)
+	if param.Index != 1 || param.State != 1 || param.Duration != 30 {
+		t.Errorf("unexpected param: %+v", param)
+	}
+
+	if err := client.Alarm.TriggerAlarmOut(t.Context(), 0, 1, true, -1); err == nil {
+		t.Error("expected error for a negative duration")
+	}
+}
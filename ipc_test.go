@@ -0,0 +1,97 @@
+package reolink
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIpc_Validate(t *testing.T) {
+	valid := []Ipc{
+		{IP: "192.168.1.20", UserName: "admin"}, // Port 0 selects the default
+		{IP: "192.168.1.20", Port: 65535, UserName: "admin", Protocol: IpcProtocolONVIF},
+	}
+	for _, ipc := range valid {
+		if err := ipc.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", ipc, err)
+		}
+	}
+
+	invalid := []Ipc{
+		{IP: "192.168.1.20", Port: -1, UserName: "admin"},
+		{IP: "192.168.1.20", Port: 65536, UserName: "admin"},
+	}
+	for _, ipc := range invalid {
+		if err := ipc.Validate(); err == nil {
+			t.Errorf("%+v: expected validation error", ipc)
+		}
+	}
+}
+
+func TestSystemAPI_Ipc(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetIpc": `{"Ipc": [{"channel": 0, "ip": "192.168.1.20", "port": 9000, "protocol": "private", "userName": "admin"},
+			{"channel": 1, "ip": "192.168.1.21", "port": 80, "protocol": "onvif", "userName": "viewer"}]}`,
+		"AddIpc": "",
+		"DelIpc": "",
+		"SetIpc": "",
+	})
+	client := server.client()
+
+	ipcs, err := client.System.GetIpcs(t.Context())
+	if err != nil {
+		t.Fatalf("GetIpcs failed: %v", err)
+	}
+	if len(ipcs) != 2 || ipcs[1].Protocol != IpcProtocolONVIF {
+		t.Errorf("unexpected cameras: %+v", ipcs)
+	}
+
+	for _, ipc := range []Ipc{
+		{Channel: 2, UserName: "admin"},
+		{Channel: 2, IP: "192.168.1.22"},
+		{Channel: 2, IP: "192.168.1.22", UserName: "admin", Protocol: "rtsp"},
+	} {
+		if err := client.System.AddIpc(t.Context(), ipc); err == nil {
+			t.Errorf("expected error for %+v", ipc)
+		}
+	}
+	if server.callCount("AddIpc") != 0 {
+		t.Error("invalid camera was sent")
+	}
+
+	if err := client.System.AddIpc(t.Context(), Ipc{Channel: 2, IP: "192.168.1.22", UserName: "admin", Password: "secret"}); err != nil {
+		t.Fatalf("AddIpc failed: %v", err)
+	}
+	var added struct{ Ipc Ipc }
+	json.Unmarshal(server.lastParam("AddIpc"), &added)
+	if added.Ipc.Port != 9000 || added.Ipc.Protocol != IpcProtocolPrivate || added.Ipc.Password != "secret" {
+		t.Errorf("unexpected AddIpc request: %+v", added.Ipc)
+	}
+
+	if err := client.System.SetIpcCredentials(t.Context(), 1, "admin", "rotated"); err != nil {
+		t.Fatalf("SetIpcCredentials failed: %v", err)
+	}
+	var set struct{ Ipc Ipc }
+	json.Unmarshal(server.lastParam("SetIpc"), &set)
+	if set.Ipc.IP != "192.168.1.21" || set.Ipc.Protocol != IpcProtocolONVIF || set.Ipc.UserName != "admin" || set.Ipc.Password != "rotated" {
+		t.Errorf("unexpected SetIpc request: %+v", set.Ipc)
+	}
+	if err := client.System.SetIpcCredentials(t.Context(), 7, "admin", "rotated"); err == nil {
+		t.Error("expected error for a channel without camera")
+	}
+
+	if err := client.System.RemoveIpc(t.Context(), 1); err != nil {
+		t.Fatalf("RemoveIpc failed: %v", err)
+	}
+	var removed struct{ Ipc Ipc }
+	json.Unmarshal(server.lastParam("DelIpc"), &removed)
+	if removed.Ipc.Channel != 1 {
+		t.Errorf("unexpected DelIpc request: %+v", removed.Ipc)
+	}
+}
+
+func TestSystemAPI_Ipc_NotSupported(t *testing.T) {
+	client := newCmdServer(t, map[string]string{}).client()
+	if err := client.System.RemoveIpc(t.Context(), 0); !IsNotSupported(err) {
+		t.Errorf("expected a not supported error, got %v", err)
+	}
+}
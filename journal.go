@@ -0,0 +1,253 @@
+package reolink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// JournalFormat is the file format of a Journal
+type JournalFormat string
+
+// Journal formats
+const (
+	JournalJSONL JournalFormat = "jsonl" // One JSON object per line
+	JournalCSV   JournalFormat = "csv"   // CSV with a header row per file
+)
+
+// journalColumns are the CSV columns, in order
+var journalColumns = []string{"time", "type", "host", "channel", "detail"}
+
+// JournalOptions configures OpenJournal
+type JournalOptions struct {
+	Format   JournalFormat // File format (default: JournalJSONL)
+	MaxSize  int64         // Size in bytes at which the file is rotated (default: 10 MiB)
+	MaxFiles int           // Rotated files kept as path.1 (newest) to path.N (default: 5)
+}
+
+// Journal appends events to a file as JSONL or CSV, rotating it when it
+// grows past MaxSize, so small deployments keep an event history without a
+// database. Pass Handle, or CountEvents(m, journal.Handle), as the handler
+// of watchers such as Alarm.WatchEvents. A Journal is safe for concurrent
+// use.
+type Journal struct {
+	path string
+	opts JournalOptions
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	err  error
+}
+
+// journalRecord is the JSONL form of an event
+type journalRecord struct {
+	Time    time.Time `json:"time"`
+	Type    EventType `json:"type"`
+	Host    string    `json:"host"`
+	Channel int       `json:"channel"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// OpenJournal opens the journal at path, appending to an existing file
+func OpenJournal(path string, opts JournalOptions) (*Journal, error) {
+	if opts.Format == "" {
+		opts.Format = JournalJSONL
+	}
+	if opts.Format != JournalJSONL && opts.Format != JournalCSV {
+		return nil, fmt.Errorf("invalid journal format %q", opts.Format)
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = 10 << 20
+	}
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = 5
+	}
+
+	j := &Journal{path: path, opts: opts}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// open opens the current file for appending
+func (j *Journal) open() error {
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	j.file, j.size = f, info.Size()
+	return nil
+}
+
+// Write appends ev to the journal, rotating the file first if ev would
+// take it past MaxSize
+func (j *Journal) Write(ev Event) error {
+	line, err := j.encode(ev)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return fmt.Errorf("journal is closed")
+	}
+	if j.size > 0 && j.size+int64(len(line)) > j.opts.MaxSize {
+		if err := j.rotate(); err != nil {
+			return err
+		}
+	}
+	if j.size == 0 && j.opts.Format == JournalCSV {
+		header, _ := encodeCSV(journalColumns)
+		line = append(header, line...)
+	}
+	n, err := j.file.Write(line)
+	j.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// Handle writes ev like Write, for use as an EventHandler. Write errors are
+// kept and returned by Err and Close.
+func (j *Journal) Handle(ev Event) {
+	if err := j.Write(ev); err != nil {
+		j.mu.Lock()
+		j.err = errors.Join(j.err, err)
+		j.mu.Unlock()
+	}
+}
+
+// Err returns the errors Handle ran into
+func (j *Journal) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Close closes the journal file and returns any errors Handle ran into
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return j.err
+	}
+	err := j.file.Close()
+	j.file = nil
+	return errors.Join(j.err, err)
+}
+
+// rotate shifts path.N-1 to path.N and so on, moves the current file to
+// path.1, dropping the oldest, and starts a new file
+func (j *Journal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate journal: %w", err)
+	}
+	j.file = nil
+	for i := j.opts.MaxFiles - 1; i >= 1; i-- {
+		err := os.Rename(j.rotated(i), j.rotated(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate journal: %w", err)
+		}
+	}
+	if err := os.Rename(j.path, j.rotated(1)); err != nil {
+		return fmt.Errorf("failed to rotate journal: %w", err)
+	}
+	return j.open()
+}
+
+// rotated returns the name of the n-th rotated file
+func (j *Journal) rotated(n int) string {
+	return j.path + "." + strconv.Itoa(n)
+}
+
+// encode returns ev as one line in the journal's format
+func (j *Journal) encode(ev Event) ([]byte, error) {
+	if j.opts.Format == JournalCSV {
+		return encodeCSV([]string{
+			ev.Time.Format(time.RFC3339Nano), string(ev.Type), ev.Host, strconv.Itoa(ev.Channel), ev.Detail,
+		})
+	}
+	line, err := json.Marshal(journalRecord{Time: ev.Time, Type: ev.Type, Host: ev.Host, Channel: ev.Channel, Detail: ev.Detail})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return append(line, '\n'), nil
+}
+
+// encodeCSV returns one CSV row
+func encodeCSV(fields []string) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write(fields); err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+// ReadJournal reads the events of one journal file in the given format,
+// e.g. to replay history or import it elsewhere
+func ReadJournal(r io.Reader, format JournalFormat) ([]Event, error) {
+	var events []Event
+	switch format {
+	case JournalJSONL, "":
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var rec journalRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				return events, fmt.Errorf("journal line %d: %w", line, err)
+			}
+			events = append(events, Event{Type: rec.Type, Host: rec.Host, Channel: rec.Channel, Time: rec.Time, Detail: rec.Detail})
+		}
+		return events, scanner.Err()
+
+	case JournalCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = len(journalColumns)
+		for {
+			row, err := cr.Read()
+			if err == io.EOF {
+				return events, nil
+			}
+			if err != nil {
+				return events, fmt.Errorf("journal: %w", err)
+			}
+			if row[0] == journalColumns[0] {
+				continue // Header
+			}
+			t, err := time.Parse(time.RFC3339Nano, row[0])
+			if err != nil {
+				return events, fmt.Errorf("journal: %w", err)
+			}
+			channel, err := strconv.Atoi(row[3])
+			if err != nil {
+				return events, fmt.Errorf("journal: invalid channel %q", row[3])
+			}
+			events = append(events, Event{Type: EventType(row[1]), Host: row[2], Channel: channel, Time: t, Detail: row[4]})
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid journal format %q", format)
+	}
+}
@@ -0,0 +1,117 @@
+package reolink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEvents() []Event {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []Event{
+		{Type: EventMotionStart, Host: "cam1", Channel: 0, Time: now},
+		{Type: EventAIStart, Host: "cam1", Channel: 0, Time: now.Add(time.Second), Detail: AITypePeople},
+		{Type: EventImageDrift, Host: "nvr", Channel: 3, Time: now.Add(2 * time.Second), Detail: `bright, "contrast"`},
+	}
+}
+
+func TestJournal_RoundTrip(t *testing.T) {
+	for _, format := range []JournalFormat{JournalJSONL, JournalCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "events."+string(format))
+			j, err := OpenJournal(path, JournalOptions{Format: format})
+			if err != nil {
+				t.Fatalf("OpenJournal failed: %v", err)
+			}
+			events := testEvents()
+			j.Handle(events[0])
+			j.Handle(events[1])
+			if err := j.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			// Reopening appends, without a second CSV header
+			j, err = OpenJournal(path, JournalOptions{Format: format})
+			if err != nil {
+				t.Fatalf("OpenJournal failed: %v", err)
+			}
+			if err := j.Write(events[2]); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			j.Close()
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format == JournalCSV && strings.Count(string(data), "time,type") != 1 {
+				t.Errorf("expected one CSV header:\n%s", data)
+			}
+			got, err := ReadJournal(strings.NewReader(string(data)), format)
+			if err != nil {
+				t.Fatalf("ReadJournal failed: %v", err)
+			}
+			if len(got) != len(events) {
+				t.Fatalf("expected %d events, got %d", len(events), len(got))
+			}
+			for i := range events {
+				if got[i] != events[i] {
+					t.Errorf("event %d: got %+v, want %+v", i, got[i], events[i])
+				}
+			}
+		})
+	}
+}
+
+func TestJournal_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.csv")
+	j, err := OpenJournal(path, JournalOptions{Format: JournalCSV, MaxSize: 150, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	defer j.Close()
+
+	ev := testEvents()[1]
+	for range 20 {
+		if err := j.Write(ev); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+		if len(data) > 150 {
+			t.Errorf("%s has %d bytes, more than MaxSize", name, len(data))
+		}
+		if !strings.HasPrefix(string(data), "time,type,host,channel,detail\n") {
+			t.Errorf("%s lacks the CSV header", name)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected at most MaxFiles rotated files")
+	}
+}
+
+func TestJournal_Errors(t *testing.T) {
+	if _, err := OpenJournal(filepath.Join(t.TempDir(), "x"), JournalOptions{Format: "xml"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if _, err := OpenJournal(filepath.Join(t.TempDir(), "missing", "x"), JournalOptions{}); err == nil {
+		t.Error("expected error for missing directory")
+	}
+
+	j, err := OpenJournal(filepath.Join(t.TempDir(), "events.jsonl"), JournalOptions{})
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	j.Close()
+	j.Handle(testEvents()[0])
+	if j.Err() == nil {
+		t.Error("expected write to closed journal to be recorded")
+	}
+}
@@ -2,7 +2,6 @@ package reolink
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 )
 
@@ -73,10 +72,10 @@ type WhiteLedAiDetect struct {
 // WhiteLed represents white LED configuration
 type WhiteLed struct {
 	Channel          int              `json:"channel"`          // Channel number
-	State            int              `json:"state"`            // 0=off, 1=on
+	State            BoolInt          `json:"state"`            // 0=off, 1=on
 	Mode             int              `json:"mode"`             // 0=always on, 1=alarm trigger, 2=auto with AI
 	Bright           int              `json:"bright"`           // Brightness (0-100)
-	LightingSchedule WhiteLedSchedule `json:"LightingSchedule"` // Schedule for mode 2
+	LightingSchedule WhiteLedSchedule `json:"LightingSchedule"` // Schedule for mode 2, may cross midnight
 	WlAiDetectType   WhiteLedAiDetect `json:"wlAiDetectType"`   // AI detection types
 }
 
@@ -92,7 +91,7 @@ type WhiteLedParam struct {
 
 // GetIrLights gets IR lights configuration
 func (l *LEDAPI) GetIrLights(ctx context.Context) (*IrLights, error) {
-	l.client.logger.Debug("getting IR lights configuration")
+	l.client.log(ctx).Debug("getting IR lights configuration")
 
 	req := []Request{{
 		Cmd:    "GetIrLights",
@@ -101,34 +100,34 @@ func (l *LEDAPI) GetIrLights(ctx context.Context) (*IrLights, error) {
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to get IR lights configuration: %v", err)
+		l.client.log(ctx).Error("failed to get IR lights configuration: %v", err)
 		return nil, fmt.Errorf("GetIrLights request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to get IR lights configuration: %v", err)
+		l.client.log(ctx).Error("failed to get IR lights configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to get IR lights configuration: %v", apiErr)
+		l.client.log(ctx).Error("failed to get IR lights configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value IrLightsValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		l.client.logger.Error("failed to parse IR lights configuration response: %v", err)
+	if err := l.client.unmarshal(resp[0].Value, &value); err != nil {
+		l.client.log(ctx).Error("failed to parse IR lights configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	l.client.logger.Info("successfully retrieved IR lights configuration: state=%s", value.IrLights.State)
+	l.client.log(ctx).Info("successfully retrieved IR lights configuration: state=%s", value.IrLights.State)
 	return &value.IrLights, nil
 }
 
 // SetIrLights sets IR lights configuration
 func (l *LEDAPI) SetIrLights(ctx context.Context, channel int, state string) error {
-	l.client.logger.Info("setting IR lights configuration: channel=%d state=%s", channel, state)
+	l.client.log(ctx).Info("setting IR lights configuration: channel=%d state=%s", channel, state)
 
 	var param IrLightsParam
 	param.IrLights.Channel = channel
@@ -141,28 +140,28 @@ func (l *LEDAPI) SetIrLights(ctx context.Context, channel int, state string) err
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to set IR lights configuration: %v", err)
+		l.client.log(ctx).Error("failed to set IR lights configuration: %v", err)
 		return fmt.Errorf("SetIrLights request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to set IR lights configuration: %v", err)
+		l.client.log(ctx).Error("failed to set IR lights configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to set IR lights configuration: %v", apiErr)
+		l.client.log(ctx).Error("failed to set IR lights configuration: %v", apiErr)
 		return apiErr
 	}
 
-	l.client.logger.Info("successfully set IR lights configuration")
+	l.client.log(ctx).Info("successfully set IR lights configuration")
 	return nil
 }
 
 // GetPowerLed gets power LED configuration
 func (l *LEDAPI) GetPowerLed(ctx context.Context, channel int) (*PowerLed, error) {
-	l.client.logger.Debug("getting power LED configuration: channel=%d", channel)
+	l.client.log(ctx).Debug("getting power LED configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetPowerLed",
@@ -174,34 +173,34 @@ func (l *LEDAPI) GetPowerLed(ctx context.Context, channel int) (*PowerLed, error
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to get power LED configuration: %v", err)
+		l.client.log(ctx).Error("failed to get power LED configuration: %v", err)
 		return nil, fmt.Errorf("GetPowerLed request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to get power LED configuration: %v", err)
+		l.client.log(ctx).Error("failed to get power LED configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to get power LED configuration: %v", apiErr)
+		l.client.log(ctx).Error("failed to get power LED configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value PowerLedValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		l.client.logger.Error("failed to parse power LED configuration response: %v", err)
+	if err := l.client.unmarshal(resp[0].Value, &value); err != nil {
+		l.client.log(ctx).Error("failed to parse power LED configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	l.client.logger.Info("successfully retrieved power LED configuration: state=%s", value.PowerLed.State)
+	l.client.log(ctx).Info("successfully retrieved power LED configuration: state=%s", value.PowerLed.State)
 	return &value.PowerLed, nil
 }
 
 // SetPowerLed sets power LED configuration
 func (l *LEDAPI) SetPowerLed(ctx context.Context, channel int, state string) error {
-	l.client.logger.Info("setting power LED configuration: channel=%d state=%s", channel, state)
+	l.client.log(ctx).Info("setting power LED configuration: channel=%d state=%s", channel, state)
 
 	var param PowerLedParam
 	param.PowerLed.Channel = channel
@@ -214,28 +213,28 @@ func (l *LEDAPI) SetPowerLed(ctx context.Context, channel int, state string) err
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to set power LED configuration: %v", err)
+		l.client.log(ctx).Error("failed to set power LED configuration: %v", err)
 		return fmt.Errorf("SetPowerLed request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to set power LED configuration: %v", err)
+		l.client.log(ctx).Error("failed to set power LED configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to set power LED configuration: %v", apiErr)
+		l.client.log(ctx).Error("failed to set power LED configuration: %v", apiErr)
 		return apiErr
 	}
 
-	l.client.logger.Info("successfully set power LED configuration")
+	l.client.log(ctx).Info("successfully set power LED configuration")
 	return nil
 }
 
 // GetWhiteLed gets white LED configuration
 func (l *LEDAPI) GetWhiteLed(ctx context.Context, channel int) (*WhiteLed, error) {
-	l.client.logger.Debug("getting white LED configuration: channel=%d", channel)
+	l.client.log(ctx).Debug("getting white LED configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetWhiteLed",
@@ -247,35 +246,42 @@ func (l *LEDAPI) GetWhiteLed(ctx context.Context, channel int) (*WhiteLed, error
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to get white LED configuration: %v", err)
+		l.client.log(ctx).Error("failed to get white LED configuration: %v", err)
 		return nil, fmt.Errorf("GetWhiteLed request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to get white LED configuration: %v", err)
+		l.client.log(ctx).Error("failed to get white LED configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to get white LED configuration: %v", apiErr)
+		l.client.log(ctx).Error("failed to get white LED configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value WhiteLedValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		l.client.logger.Error("failed to parse white LED configuration response: %v", err)
+	if err := l.client.unmarshal(resp[0].Value, &value); err != nil {
+		l.client.log(ctx).Error("failed to parse white LED configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	l.client.logger.Info("successfully retrieved white LED configuration: state=%d mode=%d bright=%d",
+	l.client.log(ctx).Info("successfully retrieved white LED configuration: state=%d mode=%d bright=%d",
 		value.WhiteLed.State, value.WhiteLed.Mode, value.WhiteLed.Bright)
 	return &value.WhiteLed, nil
 }
 
-// SetWhiteLed sets white LED configuration
+// SetWhiteLed sets white LED configuration. The lighting schedule is
+// validated, and in schedule mode it must not be empty or, on firmware with
+// the NoOvernightSchedule quirk, cross midnight.
 func (l *LEDAPI) SetWhiteLed(ctx context.Context, config WhiteLed) error {
-	l.client.logger.Info("setting white LED configuration: channel=%d state=%d mode=%d bright=%d",
+	config, err := l.client.prepareWhiteLed(config)
+	if err != nil {
+		return err
+	}
+
+	l.client.log(ctx).Info("setting white LED configuration: channel=%d state=%d mode=%d bright=%d",
 		config.Channel, config.State, config.Mode, config.Bright)
 
 	req := []Request{{
@@ -287,22 +293,22 @@ func (l *LEDAPI) SetWhiteLed(ctx context.Context, config WhiteLed) error {
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to set white LED configuration: %v", err)
+		l.client.log(ctx).Error("failed to set white LED configuration: %v", err)
 		return fmt.Errorf("SetWhiteLed request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to set white LED configuration: %v", err)
+		l.client.log(ctx).Error("failed to set white LED configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to set white LED configuration: %v", apiErr)
+		l.client.log(ctx).Error("failed to set white LED configuration: %v", apiErr)
 		return apiErr
 	}
 
-	l.client.logger.Info("successfully set white LED configuration")
+	l.client.log(ctx).Info("successfully set white LED configuration")
 	return nil
 }
 
@@ -339,7 +345,7 @@ type AiAlarmParam struct {
 
 // GetAiAlarm gets AI-based alarm configuration
 func (l *LEDAPI) GetAiAlarm(ctx context.Context, channel int, aiType string) (*AiAlarm, error) {
-	l.client.logger.Debug("getting AI alarm configuration: channel=%d aiType=%s", channel, aiType)
+	l.client.log(ctx).Debug("getting AI alarm configuration: channel=%d aiType=%s", channel, aiType)
 
 	req := []Request{{
 		Cmd:    "GetAiAlarm",
@@ -351,35 +357,35 @@ func (l *LEDAPI) GetAiAlarm(ctx context.Context, channel int, aiType string) (*A
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to get AI alarm configuration: %v", err)
+		l.client.log(ctx).Error("failed to get AI alarm configuration: %v", err)
 		return nil, fmt.Errorf("GetAiAlarm request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to get AI alarm configuration: %v", err)
+		l.client.log(ctx).Error("failed to get AI alarm configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to get AI alarm configuration: %v", apiErr)
+		l.client.log(ctx).Error("failed to get AI alarm configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value AiAlarmValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		l.client.logger.Error("failed to parse AI alarm configuration response: %v", err)
+	if err := l.client.unmarshal(resp[0].Value, &value); err != nil {
+		l.client.log(ctx).Error("failed to parse AI alarm configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetAiAlarm response: %w", err)
 	}
 
-	l.client.logger.Info("successfully retrieved AI alarm configuration: aiType=%s sensitivity=%d",
+	l.client.log(ctx).Info("successfully retrieved AI alarm configuration: aiType=%s sensitivity=%d",
 		value.AiAlarm.AiType, value.AiAlarm.Sensitivity)
 	return &value.AiAlarm, nil
 }
 
 // SetAiAlarm sets AI-based alarm configuration
 func (l *LEDAPI) SetAiAlarm(ctx context.Context, channel int, alarm AiAlarm) error {
-	l.client.logger.Info("setting AI alarm configuration: channel=%d aiType=%s sensitivity=%d",
+	l.client.log(ctx).Info("setting AI alarm configuration: channel=%d aiType=%s sensitivity=%d",
 		channel, alarm.AiType, alarm.Sensitivity)
 
 	req := []Request{{
@@ -392,28 +398,28 @@ func (l *LEDAPI) SetAiAlarm(ctx context.Context, channel int, alarm AiAlarm) err
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to set AI alarm configuration: %v", err)
+		l.client.log(ctx).Error("failed to set AI alarm configuration: %v", err)
 		return fmt.Errorf("SetAiAlarm request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to set AI alarm configuration: %v", err)
+		l.client.log(ctx).Error("failed to set AI alarm configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to set AI alarm configuration: %v", apiErr)
+		l.client.log(ctx).Error("failed to set AI alarm configuration: %v", apiErr)
 		return apiErr
 	}
 
-	l.client.logger.Info("successfully set AI alarm configuration")
+	l.client.log(ctx).Info("successfully set AI alarm configuration")
 	return nil
 }
 
 // SetAlarmArea sets alarm detection area/zone
 func (l *LEDAPI) SetAlarmArea(ctx context.Context, params map[string]interface{}) error {
-	l.client.logger.Info("setting alarm detection area")
+	l.client.log(ctx).Info("setting alarm detection area")
 
 	req := []Request{{
 		Cmd:   "SetAlarmArea",
@@ -422,21 +428,21 @@ func (l *LEDAPI) SetAlarmArea(ctx context.Context, params map[string]interface{}
 
 	var resp []Response
 	if err := l.client.do(ctx, req, &resp); err != nil {
-		l.client.logger.Error("failed to set alarm detection area: %v", err)
+		l.client.log(ctx).Error("failed to set alarm detection area: %v", err)
 		return fmt.Errorf("SetAlarmArea request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		l.client.logger.Error("failed to set alarm detection area: %v", err)
+		l.client.log(ctx).Error("failed to set alarm detection area: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		l.client.logger.Error("failed to set alarm detection area: %v", apiErr)
+		l.client.log(ctx).Error("failed to set alarm detection area: %v", apiErr)
 		return apiErr
 	}
 
-	l.client.logger.Info("successfully set alarm detection area")
+	l.client.log(ctx).Info("successfully set alarm detection area")
 	return nil
 }
@@ -0,0 +1,151 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WhiteLedModeSchedule is the white LED mode that follows LightingSchedule
+const WhiteLedModeSchedule = 2
+
+// ErrOvernightSchedule is returned by SetWhiteLed when the schedule crosses
+// midnight and the firmware cannot apply such schedules (see
+// Quirk.NoOvernightSchedule). RunWhiteLedSchedule applies them anyway.
+var ErrOvernightSchedule = errors.New("firmware does not support schedules across midnight")
+
+// Validate checks that the schedule's times are valid times of day
+func (s WhiteLedSchedule) Validate() error {
+	if s.StartHour < 0 || s.StartHour > 23 || s.EndHour < 0 || s.EndHour > 23 {
+		return fmt.Errorf("invalid lighting schedule: hours must be between 0 and 23")
+	}
+	if s.StartMin < 0 || s.StartMin > 59 || s.EndMin < 0 || s.EndMin > 59 {
+		return fmt.Errorf("invalid lighting schedule: minutes must be between 0 and 59")
+	}
+	return nil
+}
+
+// Normalize returns the schedule with an end of 24:00, which firmware
+// rejects, moved to 23:59
+func (s WhiteLedSchedule) Normalize() WhiteLedSchedule {
+	if s.EndHour == 24 && s.EndMin == 0 {
+		s.EndHour, s.EndMin = 23, 59
+	}
+	return s
+}
+
+// Empty reports whether the schedule starts when it ends
+func (s WhiteLedSchedule) Empty() bool {
+	return s.start() == s.end()
+}
+
+// Overnight reports whether the schedule crosses midnight, e.g. 18:00-06:00
+func (s WhiteLedSchedule) Overnight() bool {
+	return s.end() < s.start()
+}
+
+// Contains reports whether the light is scheduled on at t's time of day
+func (s WhiteLedSchedule) Contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if s.Overnight() {
+		return m >= s.start() || m < s.end()
+	}
+	return m >= s.start() && m < s.end()
+}
+
+// Split returns the schedule as same-day windows: the schedule itself, or
+// for an overnight schedule the evening window up to 23:59 and the morning
+// window from 00:00
+func (s WhiteLedSchedule) Split() []WhiteLedSchedule {
+	if !s.Overnight() {
+		return []WhiteLedSchedule{s}
+	}
+	evening := WhiteLedSchedule{StartHour: s.StartHour, StartMin: s.StartMin, EndHour: 23, EndMin: 59}
+	morning := WhiteLedSchedule{EndHour: s.EndHour, EndMin: s.EndMin}
+	if morning.Empty() {
+		return []WhiteLedSchedule{evening}
+	}
+	return []WhiteLedSchedule{evening, morning}
+}
+
+func (s WhiteLedSchedule) start() int { return s.StartHour*60 + s.StartMin }
+func (s WhiteLedSchedule) end() int   { return s.EndHour*60 + s.EndMin }
+
+// prepareWhiteLed normalizes and checks config's schedule for the device
+func (c *Client) prepareWhiteLed(config WhiteLed) (WhiteLed, error) {
+	config.LightingSchedule = config.LightingSchedule.Normalize()
+	if err := config.LightingSchedule.Validate(); err != nil {
+		return config, err
+	}
+	if config.Mode != WhiteLedModeSchedule {
+		return config, nil
+	}
+	if config.LightingSchedule.Empty() {
+		return config, fmt.Errorf("invalid lighting schedule: starts when it ends")
+	}
+	if config.LightingSchedule.Overnight() && c.quirkNoOvernightSchedule() {
+		return config, fmt.Errorf("%w: %02d:%02d-%02d:%02d, use RunWhiteLedSchedule", ErrOvernightSchedule,
+			config.LightingSchedule.StartHour, config.LightingSchedule.StartMin,
+			config.LightingSchedule.EndHour, config.LightingSchedule.EndMin)
+	}
+	return config, nil
+}
+
+// RunWhiteLedSchedule applies a white LED configuration whose schedule may
+// cross midnight on any firmware. Where the firmware handles overnight
+// schedules it sets config once; otherwise it splits the schedule (see
+// WhiteLedSchedule.Split) and keeps the window for the current time of day
+// applied, switching to the morning window at midnight and back to the
+// evening window when the morning one ends. Times are taken from the local
+// clock, so it should agree with the camera's (see Fleet.SyncTime).
+//
+// It blocks until ctx is done and returns ctx.Err(). Failed switches are
+// logged and retried a minute later.
+func (l *LEDAPI) RunWhiteLedSchedule(ctx context.Context, config WhiteLed) error {
+	config.LightingSchedule = config.LightingSchedule.Normalize()
+	if err := config.LightingSchedule.Validate(); err != nil {
+		return err
+	}
+
+	split := config.Mode == WhiteLedModeSchedule && config.LightingSchedule.Overnight() && l.client.quirkNoOvernightSchedule()
+	if !split {
+		if err := l.SetWhiteLed(ctx, config); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	l.client.log(ctx).Info("splitting overnight lighting schedule: channel=%d", config.Channel)
+	first := true
+	for {
+		window, next := overnightWindow(config.LightingSchedule, time.Now())
+		applied := config
+		applied.LightingSchedule = window
+		if err := l.SetWhiteLed(ctx, applied); err != nil {
+			if first {
+				return err
+			}
+			l.client.log(ctx).Warn("failed to switch lighting schedule: %v", err)
+			next = time.Now().Add(time.Minute)
+		}
+		first = false
+
+		if err := sleepCtx(ctx, time.Until(next)); err != nil {
+			return err
+		}
+	}
+}
+
+// overnightWindow returns the window of an overnight schedule to apply at
+// now, and when to switch to the other one
+func overnightWindow(s WhiteLedSchedule, now time.Time) (WhiteLedSchedule, time.Time) {
+	windows := s.Split()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	morningEnd := midnight.Add(time.Duration(s.end()) * time.Minute)
+	if len(windows) == 2 && now.Before(morningEnd) {
+		return windows[1], morningEnd
+	}
+	return windows[0], midnight.AddDate(0, 0, 1)
+}
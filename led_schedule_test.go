@@ -0,0 +1,123 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWhiteLedSchedule(t *testing.T) {
+	overnight := WhiteLedSchedule{StartHour: 18, StartMin: 30, EndHour: 6, EndMin: 15}
+	daytime := WhiteLedSchedule{StartHour: 8, EndHour: 17}
+
+	if !overnight.Overnight() || daytime.Overnight() {
+		t.Error("Overnight misreported")
+	}
+	at := func(hour, min int) time.Time {
+		return time.Date(2024, 1, 1, hour, min, 0, 0, time.Local)
+	}
+	for _, tt := range []struct {
+		s    WhiteLedSchedule
+		t    time.Time
+		want bool
+	}{
+		{overnight, at(23, 0), true},
+		{overnight, at(6, 0), true},
+		{overnight, at(6, 15), false},
+		{overnight, at(12, 0), false},
+		{daytime, at(12, 0), true},
+		{daytime, at(17, 0), false},
+	} {
+		if got := tt.s.Contains(tt.t); got != tt.want {
+			t.Errorf("%+v.Contains(%s) = %v, want %v", tt.s, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+
+	split := overnight.Split()
+	want := []WhiteLedSchedule{
+		{StartHour: 18, StartMin: 30, EndHour: 23, EndMin: 59},
+		{EndHour: 6, EndMin: 15},
+	}
+	if len(split) != 2 || split[0] != want[0] || split[1] != want[1] {
+		t.Errorf("Split() = %+v, want %+v", split, want)
+	}
+	if split := (WhiteLedSchedule{StartHour: 20}).Split(); len(split) != 1 {
+		t.Errorf("schedule ending at midnight should give one window, got %+v", split)
+	}
+	if split := daytime.Split(); len(split) != 1 || split[0] != daytime {
+		t.Errorf("daytime schedule should not be split, got %+v", split)
+	}
+
+	if got := (WhiteLedSchedule{StartHour: 18, EndHour: 24}).Normalize(); got.EndHour != 23 || got.EndMin != 59 {
+		t.Errorf("Normalize() = %+v", got)
+	}
+	if err := (WhiteLedSchedule{StartHour: 25}).Validate(); err == nil {
+		t.Error("expected error for hour 25")
+	}
+	if err := (WhiteLedSchedule{EndMin: 60}).Validate(); err == nil {
+		t.Error("expected error for minute 60")
+	}
+}
+
+func TestOvernightWindow(t *testing.T) {
+	s := WhiteLedSchedule{StartHour: 18, EndHour: 6}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	window, next := overnightWindow(s, day.Add(3*time.Hour))
+	if window != (WhiteLedSchedule{EndHour: 6}) || !next.Equal(day.Add(6*time.Hour)) {
+		t.Errorf("at 03:00: got %+v until %s", window, next)
+	}
+	window, next = overnightWindow(s, day.Add(12*time.Hour))
+	if window != (WhiteLedSchedule{StartHour: 18, EndHour: 23, EndMin: 59}) || !next.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("at 12:00: got %+v until %s", window, next)
+	}
+}
+
+func TestLEDAPI_SetWhiteLed_Schedule(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"SetWhiteLed": "",
+	})
+	client := srv.client()
+	overnight := WhiteLed{Mode: WhiteLedModeSchedule, LightingSchedule: WhiteLedSchedule{StartHour: 18, EndHour: 6}}
+
+	if err := client.LED.SetWhiteLed(t.Context(), overnight); err != nil {
+		t.Fatalf("overnight schedule rejected without quirk: %v", err)
+	}
+	if err := client.LED.SetWhiteLed(t.Context(), WhiteLed{Mode: WhiteLedModeSchedule}); err == nil {
+		t.Error("expected error for empty schedule")
+	}
+	if err := client.LED.SetWhiteLed(t.Context(), WhiteLed{Mode: 1}); err != nil {
+		t.Errorf("empty schedule should be fine outside schedule mode: %v", err)
+	}
+
+	client.quirks = []Quirk{{Name: "test", NoOvernightSchedule: true}}
+	calls := srv.callCount("SetWhiteLed")
+	if err := client.LED.SetWhiteLed(t.Context(), overnight); !errors.Is(err, ErrOvernightSchedule) {
+		t.Errorf("expected ErrOvernightSchedule, got %v", err)
+	}
+	if n := srv.callCount("SetWhiteLed"); n != calls {
+		t.Error("rejected schedule was sent")
+	}
+
+	// RunWhiteLedSchedule applies one half of it
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- client.LED.RunWhiteLedSchedule(ctx, overnight) }()
+	for srv.callCount("SetWhiteLed") == calls {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	var param WhiteLedParam
+	if err := json.Unmarshal(srv.lastParam("SetWhiteLed"), &param); err != nil {
+		t.Fatalf("bad SetWhiteLed param: %v", err)
+	}
+	if s := param.WhiteLed.LightingSchedule; s.Overnight() || !s.Contains(time.Now()) && s != (WhiteLedSchedule{StartHour: 18, EndHour: 23, EndMin: 59}) {
+		t.Errorf("unexpected applied window %+v", s)
+	}
+}
@@ -0,0 +1,193 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// WithLegacyAPI makes the client use the query-style API of pre-2019
+// firmware, which takes cmd, user and password as URL parameters instead
+// of a session token. Login and Logout become no-ops. Login also switches
+// to this mode by itself when the camera rejects the JSON login but
+// answers a query-style request.
+func WithLegacyAPI(enabled bool) Option {
+	return func(c *Client) {
+		c.legacy.Store(enabled)
+	}
+}
+
+// LegacyAPI reports whether the client uses the query-style API
+func (c *Client) LegacyAPI() bool {
+	return c.legacy.Load()
+}
+
+// legacyURL returns the API URL for cmd with the credentials and params as
+// query parameters
+func (c *Client) legacyURL(ctx context.Context, cmd string, params url.Values) string {
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	q.Set("cmd", cmd)
+	q.Set("user", c.username)
+	q.Set("password", c.password)
+	return c.quirkBaseURL(ctx, cmd) + "?" + q.Encode()
+}
+
+// legacyParams flattens a request's action and param into query
+// parameters. It reports false when param has nested values, which only a
+// JSON body can carry.
+func legacyParams(req Request) (url.Values, bool, error) {
+	q := url.Values{}
+	if req.Action != 0 {
+		q.Set("action", strconv.Itoa(req.Action))
+	}
+	if req.Param == nil {
+		return q, true, nil
+	}
+
+	data, err := json.Marshal(req.Param)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal param: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, false, nil
+	}
+	for k, v := range fields {
+		v = bytes.TrimSpace(v)
+		switch {
+		case len(v) == 0:
+		case v[0] == '{' || v[0] == '[':
+			return nil, false, nil
+		case v[0] == '"':
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return nil, false, err
+			}
+			q.Set(k, s)
+		default:
+			q.Set(k, string(v))
+		}
+	}
+	return q, true, nil
+}
+
+// postLegacy sends each request on its own, as a GET with query parameters
+// or, for params with nested values, as a POST of the JSON request with
+// the credentials in the query, and writes the combined response array to
+// dst
+func (c *Client) postLegacy(ctx context.Context, requests []Request, dst *bytes.Buffer) error {
+	var combined []json.RawMessage
+	for _, req := range requests {
+		params, flat, err := legacyParams(req)
+		if err != nil {
+			return err
+		}
+
+		var httpReq *http.Request
+		var body []byte
+		if flat {
+			httpReq, err = http.NewRequestWithContext(ctx, http.MethodGet, c.legacyURL(ctx, req.Cmd, params), nil)
+		} else {
+			req.Token = ""
+			if body, err = c.marshal([]Request{req}); err != nil {
+				return fmt.Errorf("failed to marshal request: %w", err)
+			}
+			httpReq, err = http.NewRequestWithContext(ctx, http.MethodPost, c.legacyURL(ctx, req.Cmd, nil), bytes.NewReader(body))
+			if err == nil {
+				httpReq.Header.Set("Content-Type", "application/json")
+			}
+		}
+		if err != nil {
+			err = redactURL(err)
+			c.log(ctx).Error("failed to create request: %v", err)
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		c.setHeaders(httpReq)
+		if err := c.signRequest(httpReq, body); err != nil {
+			return err
+		}
+
+		c.log(ctx).Debug("API request (legacy): cmd=%s", req.Cmd)
+		httpResp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			err = redactURL(err)
+			c.log(ctx).Error("failed to execute request: %v", err)
+			return fmt.Errorf("failed to execute request: %w", err)
+		}
+		respBody, err := io.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if httpResp.StatusCode != http.StatusOK {
+			c.log(ctx).Warn("unexpected status code: %d", httpResp.StatusCode)
+			return &statusError{code: httpResp.StatusCode, body: string(respBody)}
+		}
+
+		var resps []json.RawMessage
+		if err := json.Unmarshal(respBody, &resps); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w, body: %s", err, string(respBody))
+		}
+		combined = append(combined, resps...)
+	}
+
+	data, err := json.Marshal(combined)
+	if err != nil {
+		return err
+	}
+	dst.Reset()
+	dst.Write(data)
+	return nil
+}
+
+// legacyCandidate reports whether a failed JSON login suggests firmware
+// without the JSON API: the camera answered, but with 404 Not Found or a
+// body that is not JSON. Transport failures never qualify, so the probe,
+// which carries the password in its URL, is not sent to cameras that are
+// merely unreachable.
+func legacyCandidate(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code == http.StatusNotFound
+	}
+	var syntaxErr *json.SyntaxError
+	return errors.As(err, &syntaxErr)
+}
+
+// redactURL strips the query, which holds the credentials in legacy mode,
+// from the URL of a *url.Error so it can be logged and returned
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if i := strings.IndexByte(urlErr.URL, '?'); i >= 0 {
+			urlErr.URL = urlErr.URL[:i]
+		}
+	}
+	return err
+}
+
+// detectLegacy checks whether the camera answers a query-style GetDevInfo,
+// switching the client to the legacy API if it does
+func (c *Client) detectLegacy(ctx context.Context) bool {
+	var buf bytes.Buffer
+	if err := c.postLegacy(ctx, []Request{{Cmd: "GetDevInfo"}}, &buf); err != nil {
+		return false
+	}
+	var resps []Response
+	if err := json.Unmarshal(buf.Bytes(), &resps); err != nil || len(resps) == 0 || resps[0].ToAPIError() != nil {
+		return false
+	}
+	c.log(ctx).Warn("camera at %s only accepts the legacy query-style API, switching to it", c.Host())
+	c.legacy.Store(true)
+	return true
+}
@@ -0,0 +1,218 @@
+package reolink
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mosleyit/reolink_api_wrapper/pkg/logger"
+)
+
+// legacyServer emulates pre-2019 firmware: commands are only accepted with
+// user and password in the query, and the JSON Login is not supported
+type legacyServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	queries []url.Values
+	bodies  []string
+}
+
+func newLegacyServer(t *testing.T) *legacyServer {
+	t.Helper()
+	s := &legacyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		body, _ := io.ReadAll(r.Body)
+		cmd := q.Get("cmd")
+
+		if q.Get("user") == "" {
+			fmt.Fprintf(w, `[{"cmd": %q, "code": 1, "error": {"rspCode": -9, "detail": "not support"}}]`, cmd)
+			return
+		}
+		if q.Get("user") != "admin" || q.Get("password") != "secret" {
+			fmt.Fprintf(w, `[{"cmd": %q, "code": 1, "error": {"rspCode": -7, "detail": "login failed"}}]`, cmd)
+			return
+		}
+
+		s.mu.Lock()
+		s.queries = append(s.queries, q)
+		s.bodies = append(s.bodies, string(body))
+		s.mu.Unlock()
+
+		switch cmd {
+		case "GetDevInfo":
+			fmt.Fprint(w, `[{"cmd": "GetDevInfo", "code": 0, "value": {"DevInfo": {"model": "RLC-410", "firmVer": "v2.0.0.1441_19032101"}}}]`)
+		case "GetMdState":
+			fmt.Fprint(w, `[{"cmd": "GetMdState", "code": 0, "value": {"state": 1}}]`)
+		case "Snap":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write(testJPEG)
+		default:
+			fmt.Fprintf(w, `[{"cmd": %q, "code": 0, "value": {"rspCode": 200}}]`, cmd)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *legacyServer) lastQuery() url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries[len(s.queries)-1]
+}
+
+func (s *legacyServer) client(opts ...Option) *Client {
+	client := newTestClient(s.Server)
+	client.username, client.password = "admin", "secret"
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+func TestClient_LegacyAutoDetect(t *testing.T) {
+	server := newLegacyServer(t)
+	client := server.client()
+
+	if err := client.Login(t.Context()); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !client.LegacyAPI() {
+		t.Fatal("expected the legacy API to be detected")
+	}
+
+	info, err := client.System.GetDeviceInfo(t.Context())
+	if err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if info.Model != "RLC-410" {
+		t.Errorf("unexpected device info: %+v", info)
+	}
+	if err := client.Logout(t.Context()); err != nil {
+		t.Errorf("Logout failed: %v", err)
+	}
+}
+
+func TestClient_LegacyLoginError(t *testing.T) {
+	server := newLegacyServer(t)
+	client := server.client()
+	client.password = "wrong"
+
+	if err := client.Login(t.Context()); err == nil {
+		t.Fatal("expected login to fail")
+	}
+	if client.LegacyAPI() {
+		t.Error("legacy API enabled although the probe failed")
+	}
+}
+
+func TestClient_LegacyNoProbe(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		probe   bool
+	}{
+		{"server error", func(w http.ResponseWriter, r *http.Request) { http.Error(w, "busy", http.StatusInternalServerError) }, false},
+		{"not found", func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }, true},
+		{"html body", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "<html>login</html>") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var probes int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("password") != "" {
+					probes++
+				}
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+			client := newTestClient(server)
+			client.username, client.password = "admin", "secret"
+
+			if err := client.Login(t.Context()); err == nil {
+				t.Fatal("expected login to fail")
+			}
+			if got := probes > 0; got != tt.probe {
+				t.Errorf("legacy probe sent = %v, want %v", got, tt.probe)
+			}
+		})
+	}
+}
+
+func TestClient_LegacyRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	var logs strings.Builder
+	client := newTestClient(server)
+	client.username, client.password = "admin", "S3cretPW"
+	client.logger = logger.NewStdLogger(&logs)
+
+	if err := client.Login(t.Context()); err == nil {
+		t.Fatal("expected login to fail")
+	}
+	WithLegacyAPI(true)(client)
+	_, err := client.System.GetDeviceInfo(t.Context())
+	if err == nil {
+		t.Fatal("expected GetDeviceInfo to fail")
+	}
+	if strings.Contains(err.Error(), "S3cretPW") || strings.Contains(logs.String(), "S3cretPW") {
+		t.Errorf("password leaked: %v\n%s", err, logs.String())
+	}
+}
+
+func TestClient_LegacyRequests(t *testing.T) {
+	server := newLegacyServer(t)
+	client := server.client(WithLegacyAPI(true))
+
+	state, err := client.Alarm.GetMdState(t.Context(), 2)
+	if err != nil {
+		t.Fatalf("GetMdState failed: %v", err)
+	}
+	if state != 1 {
+		t.Errorf("state = %d, want 1", state)
+	}
+	if q := server.lastQuery(); q.Get("cmd") != "GetMdState" || q.Get("channel") != "2" {
+		t.Errorf("unexpected query: %v", q)
+	}
+
+	// Nested params go in a JSON body, credentials in the query
+	err = client.System.SetTime(t.Context(), &TimeConfig{Year: 2024, Mon: 1, Day: 2})
+	if err != nil {
+		t.Fatalf("SetTime failed: %v", err)
+	}
+	server.mu.Lock()
+	body := server.bodies[len(server.bodies)-1]
+	server.mu.Unlock()
+	var reqs []Request
+	if err := json.Unmarshal([]byte(body), &reqs); err != nil || len(reqs) != 1 || reqs[0].Cmd != "SetTime" {
+		t.Errorf("unexpected body: %s", body)
+	}
+
+	image, err := client.Encoding.Snap(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("Snap failed: %v", err)
+	}
+	if len(image) != len(testJPEG) {
+		t.Errorf("unexpected image: %x", image)
+	}
+}
+
+func TestStatePoller_Legacy(t *testing.T) {
+	server := newLegacyServer(t)
+	client := server.client(WithLegacyAPI(true))
+
+	states, err := client.Alarm.NewStatePoller(false, 0, 1).Poll(t.Context())
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(states) != 2 || !states[0].Motion || !states[1].Motion {
+		t.Errorf("unexpected states: %+v", states)
+	}
+}
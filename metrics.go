@@ -0,0 +1,132 @@
+package reolink
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MetricsRecorder receives gauge and counter updates from the client's
+// monitoring helpers. Implementations adapt them to a metrics backend such
+// as Prometheus or InfluxDB. Every update carries a "host" label.
+type MetricsRecorder interface {
+	SetGauge(name string, value float64, labels map[string]string)
+	AddCounter(name string, delta float64, labels map[string]string)
+}
+
+// setGauge forwards a gauge update to the configured MetricsRecorder
+func (c *Client) setGauge(name string, value float64, labels map[string]string) {
+	if c.metrics != nil {
+		c.metrics.SetGauge(name, value, c.metricLabels(labels))
+	}
+}
+
+// addCounter forwards a counter update to the configured MetricsRecorder
+func (c *Client) addCounter(name string, delta float64, labels map[string]string) {
+	if c.metrics != nil {
+		c.metrics.AddCounter(name, delta, c.metricLabels(labels))
+	}
+}
+
+// metricLabels returns a copy of labels with the host label added
+func (c *Client) metricLabels(labels map[string]string) map[string]string {
+	out := make(map[string]string, len(labels)+1)
+	maps.Copy(out, labels)
+	out["host"] = c.Host()
+	return out
+}
+
+// Metric is one series held by Metrics
+type Metric struct {
+	Name    string
+	Labels  map[string]string
+	Value   float64
+	Counter bool // Counter rather than gauge
+}
+
+// Metrics is an in-memory MetricsRecorder. It keeps the latest value of
+// every series and can write them in the Prometheus text format.
+type Metrics struct {
+	mu     sync.Mutex
+	series map[string]*Metric
+}
+
+// NewMetrics creates an empty in-memory metrics registry
+func NewMetrics() *Metrics {
+	return &Metrics{series: make(map[string]*Metric)}
+}
+
+// SetGauge implements MetricsRecorder
+func (m *Metrics) SetGauge(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(name, labels, false).Value = value
+}
+
+// AddCounter implements MetricsRecorder
+func (m *Metrics) AddCounter(name string, delta float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(name, labels, true).Value += delta
+}
+
+// get returns the series for name and labels, creating it if needed
+func (m *Metrics) get(name string, labels map[string]string, counter bool) *Metric {
+	key := name + "{" + formatLabels(labels) + "}"
+	s, ok := m.series[key]
+	if !ok {
+		s = &Metric{Name: name, Labels: maps.Clone(labels), Counter: counter}
+		m.series[key] = s
+	}
+	return s
+}
+
+// Snapshot returns a copy of all series sorted by name and labels
+func (m *Metrics) Snapshot() []Metric {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := slices.Sorted(maps.Keys(m.series))
+	out := make([]Metric, 0, len(keys))
+	for _, k := range keys {
+		s := *m.series[k]
+		s.Labels = maps.Clone(s.Labels)
+		out = append(out, s)
+	}
+	return out
+}
+
+// WritePrometheus writes all series in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	var b strings.Builder
+	typed := make(map[string]bool)
+	for _, s := range m.Snapshot() {
+		if !typed[s.Name] {
+			typ := "gauge"
+			if s.Counter {
+				typ = "counter"
+			}
+			fmt.Fprintf(&b, "# TYPE %s %s\n", s.Name, typ)
+			typed[s.Name] = true
+		}
+		if len(s.Labels) > 0 {
+			fmt.Fprintf(&b, "%s{%s} %g\n", s.Name, formatLabels(s.Labels), s.Value)
+		} else {
+			fmt.Fprintf(&b, "%s %g\n", s.Name, s.Value)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatLabels renders labels as sorted name="value" pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		v := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[k])
+		pairs = append(pairs, k+`="`+v+`"`)
+	}
+	return strings.Join(pairs, ",")
+}
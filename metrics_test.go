@@ -0,0 +1,58 @@
+package reolink
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	m.SetGauge("reolink_temp", 1, map[string]string{"host": "cam1"})
+	m.SetGauge("reolink_temp", 2, map[string]string{"host": "cam1"})
+	m.AddCounter("reolink_events_total", 1, map[string]string{"host": "cam1", "type": "motion"})
+	m.AddCounter("reolink_events_total", 2, map[string]string{"type": "motion", "host": "cam1"})
+
+	snapshot := m.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(snapshot))
+	}
+	if snapshot[0].Name != "reolink_events_total" || snapshot[0].Value != 3 || !snapshot[0].Counter {
+		t.Errorf("unexpected counter: %+v", snapshot[0])
+	}
+	if snapshot[1].Value != 2 {
+		t.Errorf("expected gauge to hold the latest value, got %v", snapshot[1].Value)
+	}
+}
+
+func TestMetrics_WritePrometheus(t *testing.T) {
+	m := NewMetrics()
+	m.SetGauge("reolink_storage_retention_days", 12.5, map[string]string{"host": `cam"1`})
+	m.AddCounter("reolink_events_total", 4, nil)
+
+	var b strings.Builder
+	if err := m.WritePrometheus(&b); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	want := "# TYPE reolink_events_total counter\n" +
+		"reolink_events_total 4\n" +
+		"# TYPE reolink_storage_retention_days gauge\n" +
+		"reolink_storage_retention_days{host=\"cam\\\"1\"} 12.5\n"
+	if b.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestClient_MetricLabels(t *testing.T) {
+	m := NewMetrics()
+	client := NewClient("192.168.1.100", WithMetrics(m))
+
+	labels := map[string]string{"channel": "1"}
+	client.setGauge("reolink_test", 1, labels)
+	if _, ok := labels["host"]; ok {
+		t.Error("caller's labels must not be modified")
+	}
+	snapshot := m.Snapshot()
+	if len(snapshot) != 1 || snapshot[0].Labels["host"] != "192.168.1.100" || snapshot[0].Labels["channel"] != "1" {
+		t.Errorf("expected host and channel labels, got %+v", snapshot)
+	}
+}
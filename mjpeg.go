@@ -0,0 +1,110 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// mjpegBoundary separates the frames of an MJPEG stream
+const mjpegBoundary = "reolinkframe"
+
+// MJPEGOptions configures an MJPEG handler
+type MJPEGOptions struct {
+	Channel int
+	// FPS is the frame rate, limited by how fast the camera takes snapshots
+	// (default: 2)
+	FPS float64
+	// Cache shares snapshots between viewers (default: a cache whose
+	// MaxAge is one frame interval)
+	Cache *SnapshotCache
+}
+
+// mjpegHandler serves an MJPEG stream of snapshots
+type mjpegHandler struct {
+	client   *Client
+	channel  int
+	interval time.Duration
+	cache    *SnapshotCache
+}
+
+// NewMJPEGHandler returns an http.Handler streaming the channel as MJPEG
+// (multipart/x-mixed-replace) assembled from snapshots, which browsers
+// show in an <img> tag. It needs no media server or decoder, at the cost
+// of a low frame rate. Viewers share snapshots through the cache, so the
+// camera is asked for at most one snapshot per frame interval however many
+// are watching.
+func NewMJPEGHandler(client *Client, opts MJPEGOptions) http.Handler {
+	if opts.FPS <= 0 {
+		opts.FPS = 2
+	}
+	interval := time.Duration(float64(time.Second) / opts.FPS)
+	if opts.Cache == nil {
+		opts.Cache = NewSnapshotCache(client, SnapshotCacheOptions{MaxAge: interval})
+	}
+	return &mjpegHandler{client: client, channel: opts.Channel, interval: interval, cache: opts.Cache}
+}
+
+// ServeHTTP streams frames until the viewer disconnects. If the first
+// snapshot fails it answers 502.
+func (h *mjpegHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	flusher, _ := w.(http.Flusher)
+
+	snap, err := h.frame(ctx)
+	if err != nil {
+		h.client.log(ctx).Error("failed to start MJPEG stream: %v", err)
+		http.Error(w, "snapshot failed", http.StatusBadGateway)
+		return
+	}
+
+	h.client.log(ctx).Info("MJPEG viewer connected: channel=%d remote=%s", h.channel, r.RemoteAddr)
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mjpegBoundary)
+	w.Header().Set("Cache-Control", "no-cache, no-store")
+	w.Header().Set("Connection", "close")
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	var sent time.Time
+	for {
+		if !snap.Time.Equal(sent) {
+			if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", mjpegBoundary, len(snap.Image)); err != nil {
+				break
+			}
+			if _, err := w.Write(snap.Image); err != nil {
+				break
+			}
+			if _, err := w.Write([]byte("\r\n")); err != nil {
+				break
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			sent = snap.Time
+		}
+
+		select {
+		case <-ctx.Done():
+			h.client.log(ctx).Info("MJPEG viewer disconnected: channel=%d remote=%s", h.channel, r.RemoteAddr)
+			return
+		case <-ticker.C:
+		}
+		next, err := h.frame(ctx)
+		if err != nil {
+			h.client.log(ctx).Warn("MJPEG snapshot failed, repeating the last frame: %v", err)
+			continue
+		}
+		snap = next
+	}
+	h.client.log(ctx).Info("MJPEG viewer disconnected: channel=%d remote=%s", h.channel, r.RemoteAddr)
+}
+
+// frame returns a snapshot for the next frame, waiting at most a few frame
+// intervals for the camera
+func (h *mjpegHandler) frame(ctx context.Context) (Snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, max(5*h.interval, 5*time.Second))
+	defer cancel()
+	return h.cache.Get(ctx, h.channel)
+}
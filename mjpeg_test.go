@@ -0,0 +1,56 @@
+package reolink
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMJPEGHandler(t *testing.T) {
+	camera := newSlowSnapServer(t)
+	client := newTestClient(camera.Server)
+
+	server := httptest.NewServer(NewMJPEGHandler(client, MJPEGOptions{FPS: 20}))
+	defer server.Close()
+
+	// Two viewers share the snapshots
+	for range 2 {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil || mediaType != "multipart/x-mixed-replace" {
+			t.Fatalf("unexpected content type %q", resp.Header.Get("Content-Type"))
+		}
+		reader := multipart.NewReader(resp.Body, params["boundary"])
+		for i := range 3 {
+			part, err := reader.NextPart()
+			if err != nil {
+				t.Fatalf("frame %d: %v", i, err)
+			}
+			image, _ := io.ReadAll(part)
+			if part.Header.Get("Content-Type") != "image/jpeg" || !bytes.Equal(image, testJPEG) {
+				t.Errorf("frame %d: unexpected part %v %x", i, part.Header, image)
+			}
+		}
+		resp.Body.Close()
+	}
+}
+
+func TestMJPEGHandler_SnapshotFails(t *testing.T) {
+	camera := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"cmd": "Snap", "code": 1, "error": {"rspCode": -6, "detail": "please login first"}}]`))
+	}))
+	defer camera.Close()
+
+	rec := httptest.NewRecorder()
+	NewMJPEGHandler(newTestClient(camera), MJPEGOptions{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status %d, want 502", rec.Code)
+	}
+}
@@ -0,0 +1,149 @@
+package reolink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cmdServer is a mock camera that answers each command in a request batch
+// with a canned value keyed by command name, and serves testJPEG for Snap.
+// Commands without a canned value are answered with a "not supported"
+// error, matching real firmware. A SetX command whose param has the same
+// shape as the GetX value replaces it, so read-after-write checks observe
+// the update. Requests with action 1 also get the range set with setRange.
+type cmdServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	values map[string]string
+	ranges map[string]string
+	calls  map[string][]json.RawMessage
+}
+
+// testJPEG is the image served for Snap requests.
+var testJPEG = []byte{0xff, 0xd8, 0xff, 0xd9}
+
+// newCmdServer starts a cmdServer with the given cmd -> value JSON mapping.
+func newCmdServer(t *testing.T, values map[string]string) *cmdServer {
+	t.Helper()
+	s := newUnstartedCmdServer(values)
+	s.Start()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newCmdTLSServer starts a cmdServer serving HTTPS.
+func newCmdTLSServer(t *testing.T, values map[string]string) *cmdServer {
+	t.Helper()
+	s := newUnstartedCmdServer(values)
+	s.StartTLS()
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newUnstartedCmdServer(values map[string]string) *cmdServer {
+	s := &cmdServer{
+		values: make(map[string]string),
+		ranges: make(map[string]string),
+		calls:  make(map[string][]json.RawMessage),
+	}
+	for k, v := range values {
+		s.values[k] = v
+	}
+
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Query().Get("cmd") == "Snap" {
+			s.mu.Lock()
+			s.calls["Snap"] = append(s.calls["Snap"], nil)
+			s.mu.Unlock()
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write(testJPEG)
+			return
+		}
+
+		var reqs []struct {
+			Cmd    string          `json:"cmd"`
+			Action int             `json:"action"`
+			Param  json.RawMessage `json:"param"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resps := make([]Response, 0, len(reqs))
+		s.mu.Lock()
+		for _, req := range reqs {
+			s.calls[req.Cmd] = append(s.calls[req.Cmd], req.Param)
+			if strings.HasPrefix(req.Cmd, "Set") && len(req.Param) > 0 {
+				if _, ok := s.values["Get"+req.Cmd[3:]]; ok {
+					s.values["Get"+req.Cmd[3:]] = string(req.Param)
+				}
+			}
+			value, ok := s.values[req.Cmd]
+			if !ok {
+				resps = append(resps, Response{
+					Cmd:   req.Cmd,
+					Code:  1,
+					Error: &ErrorDetail{RspCode: ErrCodeNotSupported, Detail: "not support"},
+				})
+				continue
+			}
+			if value == "" {
+				value = `{"rspCode": 200}`
+			}
+			resp := Response{Cmd: req.Cmd, Code: 0, Value: json.RawMessage(value)}
+			if rng, ok := s.ranges[req.Cmd]; ok && req.Action == 1 {
+				resp.Range = json.RawMessage(rng)
+			}
+			resps = append(resps, resp)
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resps)
+	}))
+
+	return s
+}
+
+// set replaces the canned value for cmd.
+func (s *cmdServer) set(cmd, value string) {
+	s.mu.Lock()
+	s.values[cmd] = value
+	s.mu.Unlock()
+}
+
+// setRange sets the range returned for cmd requests with action 1.
+func (s *cmdServer) setRange(cmd, value string) {
+	s.mu.Lock()
+	s.ranges[cmd] = value
+	s.mu.Unlock()
+}
+
+// callCount returns how many times cmd was received.
+func (s *cmdServer) callCount(cmd string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.calls[cmd])
+}
+
+// lastParam returns the param of the most recent cmd request.
+func (s *cmdServer) lastParam(cmd string) json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := s.calls[cmd]
+	if len(calls) == 0 {
+		return nil
+	}
+	return calls[len(calls)-1]
+}
+
+// client returns a test client pointed at the server.
+func (s *cmdServer) client() *Client {
+	return newTestClient(s.Server)
+}
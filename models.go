@@ -28,6 +28,34 @@ type ErrorDetail struct {
 	Detail  string `json:"detail"`  // Error detail message
 }
 
+// UnmarshalJSON decodes a response, accepting a quoted code
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+	aux := struct {
+		*response
+		Code FlexInt `json:"code"`
+	}{response: (*response)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.Code = int(aux.Code)
+	return nil
+}
+
+// UnmarshalJSON decodes error details, accepting a quoted rspCode
+func (e *ErrorDetail) UnmarshalJSON(data []byte) error {
+	type errorDetail ErrorDetail
+	aux := struct {
+		*errorDetail
+		RspCode FlexInt `json:"rspCode"`
+	}{errorDetail: (*errorDetail)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	e.RspCode = int(aux.RspCode)
+	return nil
+}
+
 // ToAPIError converts a Response to an APIError if it contains an error
 func (r *Response) ToAPIError() *APIError {
 	if r.Error != nil {
@@ -115,11 +143,16 @@ type TimeConfig struct {
 	Sec        int    `json:"sec"`
 	TimeZone   int    `json:"timeZone"`
 	TimeFormat string `json:"timeFormat,omitempty"` // "DD/MM/YYYY" or "MM/DD/YYYY" or "YYYY/MM/DD"
+	TimeFmt    string `json:"timeFmt,omitempty"`    // Date format as firmware reports it, e.g. "DD/MM/YYYY"
+	HourFmt    int    `json:"hourFmt,omitempty"`    // 0=24-hour, 1=12-hour
+
+	Dst *DstConfig `json:"-"` // DST rule from GetTime, applied by Time and SetFromTime; not sent by SetTime
 }
 
 // TimeValue wraps TimeConfig for API response
 type TimeValue struct {
 	Time TimeConfig `json:"Time"`
+	Dst  *DstConfig `json:"Dst,omitempty"`
 }
 
 // TimeParam represents parameters for SetTime
@@ -127,18 +160,23 @@ type TimeParam struct {
 	Time TimeConfig `json:"Time"`
 }
 
-// DstConfig represents daylight saving time configuration
+// DstConfig represents daylight saving time configuration. DST starts and
+// ends on a weekday (0=Sunday) of a week (1-5, 5=last) of a month.
 type DstConfig struct {
-	Enable    int `json:"enable"`
-	Offset    int `json:"offset"`
-	BeginMon  int `json:"beginMon"`
-	BeginWeek int `json:"beginWeek"`
-	BeginDay  int `json:"beginDay"`
-	BeginHour int `json:"beginHour"`
-	EndMon    int `json:"endMon"`
-	EndWeek   int `json:"endWeek"`
-	EndDay    int `json:"endDay"`
-	EndHour   int `json:"endHour"`
+	Enable    BoolInt `json:"enable"`
+	Offset    int     `json:"offset"` // Hours the clock moves forward
+	BeginMon  int     `json:"startMon"`
+	BeginWeek int     `json:"startWeek"`
+	BeginDay  int     `json:"startWeekday"`
+	BeginHour int     `json:"startHour"`
+	BeginMin  int     `json:"startMin"`
+	BeginSec  int     `json:"startSec"`
+	EndMon    int     `json:"endMon"`
+	EndWeek   int     `json:"endWeek"`
+	EndDay    int     `json:"endWeekday"`
+	EndHour   int     `json:"endHour"`
+	EndMin    int     `json:"endMin"`
+	EndSec    int     `json:"endSec"`
 }
 
 // Channel represents a camera channel
@@ -151,7 +189,7 @@ type Channel struct {
 
 // Schedule represents a time schedule configuration
 type Schedule struct {
-	Enable int        `json:"enable"`
+	Enable BoolInt    `json:"enable"`
 	Table  [][]string `json:"table"` // 7x48 array representing week schedule
 }
 
@@ -188,6 +226,43 @@ type Ability struct {
 	AbilityInfo map[string]interface{} `json:"Ability"`
 }
 
+// UnmarshalJSON decodes the ability map. Firmware returns it directly under
+// value.Ability; a further "Ability" level is unwrapped when present.
+func (a *Ability) UnmarshalJSON(data []byte) error {
+	var info map[string]interface{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return err
+	}
+	if inner, ok := info["Ability"].(map[string]interface{}); ok && len(info) == 1 {
+		info = inner
+	}
+	a.AbilityInfo = info
+	return nil
+}
+
+// Version returns the ver and permit of an ability of channel, looked up in
+// abilityChn and then among the device-wide abilities. ok is false if the
+// device does not report the ability.
+func (a *Ability) Version(channel int, key string) (ver, permit int, ok bool) {
+	entry := func(m map[string]interface{}) (int, int, bool) {
+		e, found := m[key].(map[string]interface{})
+		if !found {
+			return 0, 0, false
+		}
+		v, _ := e["ver"].(float64)
+		p, _ := e["permit"].(float64)
+		return int(v), int(p), true
+	}
+	if chans, found := a.AbilityInfo["abilityChn"].([]interface{}); found && channel >= 0 && channel < len(chans) {
+		if m, found := chans[channel].(map[string]interface{}); found {
+			if ver, permit, ok = entry(m); ok {
+				return ver, permit, ok
+			}
+		}
+	}
+	return entry(a.AbilityInfo)
+}
+
 // AbilityValue wraps Ability for API response
 type AbilityValue struct {
 	Ability Ability `json:"Ability"`
@@ -222,9 +297,13 @@ type DelUserParam struct {
 
 // OnlineUser represents an online user session
 type OnlineUser struct {
-	UserName  string `json:"userName"`
-	IP        string `json:"ip"`
-	LoginTime string `json:"loginTime"`
+	UserName          string `json:"userName"`
+	IP                string `json:"ip"`
+	Level             string `json:"level,omitempty"`        // User level: "admin" or "guest"
+	SessionID         int    `json:"sessionId,omitempty"`    // Session ID used to force the session offline
+	CanBeDisconnected int    `json:"canbeDisconn,omitempty"` // 1 if the session can be forced offline
+	LoginTime         string `json:"loginTime,omitempty"`    // Login time (not reported by all firmware)
+	ClientType        string `json:"clientType,omitempty"`   // Client type, e.g. "web" or "app" (not reported by all firmware)
 }
 
 // OnlineUserList represents a list of online users
@@ -232,25 +311,35 @@ type OnlineUserList struct {
 	Users []OnlineUser `json:"User"`
 }
 
-// OnlineValue wraps OnlineUserList for API response
+// OnlineValue wraps OnlineUserList for API response. Firmware reports the
+// sessions either directly under "User" or nested under "Online".
 type OnlineValue struct {
+	User   []OnlineUser   `json:"User"`
 	Online OnlineUserList `json:"Online"`
 }
 
+// DisconnectTarget identifies the session to force offline
+type DisconnectTarget struct {
+	UserName  string `json:"userName"`
+	SessionID int    `json:"sessionId,omitempty"`
+}
+
 // DisconnectParam represents parameters for Disconnect
 type DisconnectParam struct {
-	User struct {
-		UserName string `json:"userName"`
-	} `json:"User"`
+	User DisconnectTarget `json:"User"`
 }
 
-// HddInfo represents hard disk information
+// HddInfo represents hard disk information. NVRs add per-disk health
+// fields that are not in the API guide; they are zero when not reported.
 type HddInfo struct {
-	Capacity int    `json:"capacity"` // Total capacity in MB
-	Format   int    `json:"format"`   // Format status
-	Mount    int    `json:"mount"`    // Mount status
-	Size     int    `json:"size"`     // Used size in MB
-	Status   string `json:"status"`   // "ok", "error", etc.
+	Capacity    int     `json:"capacity"`              // Total capacity in MB
+	Format      int     `json:"format"`                // Format status
+	Mount       int     `json:"mount"`                 // Mount status
+	Size        int     `json:"size"`                  // Used size in MB
+	Status      string  `json:"status"`                // "ok", "error", etc.
+	Number      int     `json:"number,omitempty"`      // Disk slot on NVRs
+	Temperature FlexInt `json:"temperature,omitempty"` // Temperature in °C
+	Smart       string  `json:"smart,omitempty"`       // SMART status, e.g. "ok", "warning" or "bad"
 }
 
 // HddInfoValue wraps HDD array for API response
@@ -267,11 +356,11 @@ type FormatParam struct {
 
 // AutoMaint represents automatic maintenance configuration
 type AutoMaint struct {
-	Enable  int    `json:"enable"`
-	WeekDay string `json:"weekDay"` // "Everyday", "Sunday", "Monday", etc.
-	Hour    int    `json:"hour"`    // 0-23
-	Min     int    `json:"min"`     // 0-59
-	Sec     int    `json:"sec"`     // 0-59
+	Enable  BoolInt `json:"enable"`
+	WeekDay string  `json:"weekDay"` // "Everyday", "Sunday", "Monday", etc.
+	Hour    int     `json:"hour"`    // 0-23
+	Min     int     `json:"min"`     // 0-59
+	Sec     int     `json:"sec"`     // 0-59
 }
 
 // AutoMaintValue wraps AutoMaint for API response
@@ -288,8 +377,10 @@ type AutoMaintParam struct {
 type ChannelStatus struct {
 	Channel  int    `json:"channel"`
 	Name     string `json:"name"`
-	Online   int    `json:"online"`   // 0=offline, 1=online
-	TypeInfo string `json:"typeInfo"` // Camera model/type
+	Online   int    `json:"online"`          // 0=offline, 1=online
+	Sleep    int    `json:"sleep,omitempty"` // 1 if a battery camera is asleep (not reported by all firmware)
+	TypeInfo string `json:"typeInfo"`        // Camera model/type
+	UID      string `json:"uid,omitempty"`   // P2P UID of the attached camera (not reported by all firmware)
 }
 
 // ChannelStatusValue wraps channel status for API response
@@ -298,11 +389,32 @@ type ChannelStatusValue struct {
 	Status []ChannelStatus `json:"status"`
 }
 
+// Channel returns the status of the given channel
+func (v *ChannelStatusValue) Channel(channel int) (ChannelStatus, bool) {
+	for _, s := range v.Status {
+		if s.Channel == channel {
+			return s, true
+		}
+	}
+	return ChannelStatus{}, false
+}
+
+// Online returns the channels that are currently online
+func (v *ChannelStatusValue) Online() []ChannelStatus {
+	var online []ChannelStatus
+	for _, s := range v.Status {
+		if s.Online == 1 {
+			online = append(online, s)
+		}
+	}
+	return online
+}
+
 // CertificateInfo represents SSL certificate information
 type CertificateInfo struct {
-	Enable  int    `json:"enable"`  // 0=disabled, 1=enabled
-	CrtName string `json:"crtName"` // Certificate file name
-	KeyName string `json:"keyName"` // Private key file name
+	Enable  BoolInt `json:"enable"`  // 0=disabled, 1=enabled
+	CrtName string  `json:"crtName"` // Certificate file name
+	KeyName string  `json:"keyName"` // Private key file name
 }
 
 // CertificateInfoValue wraps CertificateInfo for API response
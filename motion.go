@@ -0,0 +1,166 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AI detection types reported in the Detail of EventAIStart/EventAIStop
+const (
+	AITypePeople  = "people"
+	AITypeVehicle = "vehicle"
+	AITypeDogCat  = "dog_cat"
+	AITypeFace    = "face"
+	AITypePackage = "package" // Parcel detection on newer doorbells and cameras
+)
+
+// alarmState is a snapshot of the motion and AI alarm states of a channel
+type alarmState struct {
+	motion bool
+	ai     map[string]bool
+}
+
+// WatchEvents polls GetMdState and GetAiState for channel every interval and
+// calls handler with EventMotionStart/EventMotionStop and
+// EventAIStart/EventAIStop whenever an alarm state changes. AI polling is
+// skipped on cameras that do not support it. Polling errors are logged and
+// retried on the next tick. It blocks until ctx is done and returns
+// ctx.Err().
+func (a *AlarmAPI) WatchEvents(ctx context.Context, channel int, interval time.Duration, handler EventHandler) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	return a.WatchEventsProfile(ctx, channel, PollingProfile{Default: interval}, handler)
+}
+
+// WatchEventsProfile works like WatchEvents, polling GetMdState and
+// GetAiState at the intervals of profile, e.g. PollingBatterySaver(). While
+// profile.SkipAsleep is set and the client finds the camera asleep, no
+// requests are made at all.
+func (a *AlarmAPI) WatchEventsProfile(ctx context.Context, channel int, profile PollingProfile, handler EventHandler) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	if handler == nil {
+		return fmt.Errorf("handler must not be nil")
+	}
+
+	var (
+		state          = alarmState{ai: make(map[string]bool)}
+		mdKnown, aiOK  = false, true
+		aiKnown, ready = false, false
+		nextMd, nextAi time.Time
+		lastEvent      = time.Now()
+		asleepLogged   bool
+	)
+
+	// poll makes the requests that are due and returns when to poll next
+	poll := func(now time.Time) time.Duration {
+		idle := now.Sub(lastEvent)
+		if profile.SkipAsleep && a.client.Asleep() {
+			if !asleepLogged {
+				a.client.log(ctx).Info("camera asleep, pausing event polling")
+				asleepLogged = true
+			}
+			return profile.Interval("GetMdState", now, idle)
+		}
+		asleepLogged = false
+
+		current := alarmState{motion: state.motion, ai: make(map[string]bool, len(state.ai))}
+		for name, on := range state.ai {
+			current.ai[name] = on
+		}
+
+		if !now.Before(nextMd) {
+			nextMd = now.Add(profile.Interval("GetMdState", now, idle))
+			if md, err := a.GetMdState(ctx, channel); err != nil {
+				a.client.log(ctx).Warn("motion state poll failed: %v", err)
+			} else {
+				current.motion = md == 1
+				mdKnown = true
+			}
+		}
+
+		if aiOK && !now.Before(nextAi) {
+			nextAi = now.Add(profile.Interval("GetAiState", now, idle))
+			ai, err := a.client.AI.GetAiState(ctx, channel)
+			var apiErr *APIError
+			switch {
+			case errors.As(err, &apiErr) && apiErr.RspCode == ErrCodeNotSupported:
+				a.client.log(ctx).Info("AI state not supported, watching motion only")
+				aiOK = false
+			case err != nil:
+				a.client.log(ctx).Warn("AI state poll failed: %v", err)
+			default:
+				for _, name := range ai.Supported() {
+					current.ai[name] = ai.Get(name).AlarmState == 1
+				}
+				aiKnown = true
+			}
+		}
+
+		// The first complete snapshot is the baseline; events follow from
+		// changes against it
+		if mdKnown && (aiKnown || !aiOK) {
+			if ready {
+				events := alarmStateEvents(a.client.Host(), channel, &state, &current, time.Now())
+				if len(events) > 0 {
+					lastEvent = now
+				}
+				for _, ev := range events {
+					handler(ev)
+				}
+			}
+			ready = true
+		}
+		state = current
+
+		next := nextMd
+		if aiOK && nextAi.Before(next) {
+			next = nextAi
+		}
+		return max(next.Sub(time.Now()), 0)
+	}
+
+	timer := time.NewTimer(poll(time.Now()))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-timer.C:
+			timer.Reset(poll(now))
+		}
+	}
+}
+
+// alarmStateEvents returns the alarm transitions between two snapshots in a
+// stable order: motion first, then AI types alphabetically
+func alarmStateEvents(host string, channel int, previous, current *alarmState, now time.Time) []Event {
+	var events []Event
+	emit := func(typ EventType, detail string) {
+		events = append(events, Event{Type: typ, Host: host, Channel: channel, Time: now, Detail: detail})
+	}
+
+	if current.motion != previous.motion {
+		if current.motion {
+			emit(EventMotionStart, "")
+		} else {
+			emit(EventMotionStop, "")
+		}
+	}
+
+	for _, name := range []string{AITypeDogCat, AITypeFace, AITypePeople, AITypeVehicle} {
+		was, is := previous.ai[name], current.ai[name]
+		switch {
+		case is && !was:
+			emit(EventAIStart, name)
+		case was && !is:
+			emit(EventAIStop, name)
+		}
+	}
+	return events
+}
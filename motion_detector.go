@@ -0,0 +1,228 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"time"
+)
+
+// MotionDetectorDetail is the Detail of motion events emitted by a
+// MotionDetector, distinguishing them from camera-reported motion
+const MotionDetectorDetail = "snapshot_diff"
+
+// motionGridWidth is the number of columns snapshots are reduced to before
+// comparison; rows follow the aspect ratio
+const motionGridWidth = 64
+
+// MotionRegion is an area of the image, in fractions of its width and
+// height, that a MotionDetector watches
+type MotionRegion struct {
+	X, Y          float64 // Top-left corner, 0-1
+	Width, Height float64 // Size, 0-1
+}
+
+// MotionDetectorOptions configures a MotionDetector
+type MotionDetectorOptions struct {
+	// Channel is the camera channel to watch
+	Channel int
+	// Interval is the time between snapshots (default: 1s)
+	Interval time.Duration
+	// Threshold is the fraction of watched cells that must change for a
+	// frame to count as motion (default: 0.02)
+	Threshold float64
+	// Sensitivity is the brightness change (0-255) at which a cell counts
+	// as changed (default: 25)
+	Sensitivity int
+	// Regions limits detection to these areas (default: whole image)
+	Regions []MotionRegion
+	// Hold is how long without motion before EventMotionStop (default: 5s)
+	Hold time.Duration
+}
+
+// MotionDetector is a client-side motion detector for old firmware without
+// reliable motion state. It compares consecutive snapshots and emits
+// synthetic EventMotionStart and EventMotionStop events with Detail set to
+// MotionDetectorDetail, so it can replace Alarm.WatchEvents as an event
+// source.
+type MotionDetector struct {
+	client *Client
+	opts   MotionDetectorOptions
+}
+
+// NewMotionDetector creates a motion detector for client, filling in
+// defaults for unset options
+func NewMotionDetector(client *Client, opts MotionDetectorOptions) *MotionDetector {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = 0.02
+	}
+	if opts.Sensitivity <= 0 {
+		opts.Sensitivity = 25
+	}
+	if opts.Hold <= 0 {
+		opts.Hold = 5 * time.Second
+	}
+	return &MotionDetector{client: client, opts: opts}
+}
+
+// Run takes a snapshot every interval and calls handler when motion starts
+// and stops. Snapshot errors are logged and retried on the next tick. It
+// blocks until ctx is done and returns ctx.Err(). Run has the EventSource
+// signature.
+func (d *MotionDetector) Run(ctx context.Context, handler EventHandler) error {
+	if handler == nil {
+		return fmt.Errorf("handler must not be nil")
+	}
+
+	var (
+		previous   *lumaGrid
+		moving     bool
+		lastMotion time.Time
+	)
+
+	poll := func() {
+		data, err := d.client.Encoding.Snap(ctx, d.opts.Channel)
+		if err != nil {
+			d.client.log(ctx).Warn("motion detector snapshot failed: %v", err)
+			return
+		}
+		current, err := newLumaGrid(data)
+		if err != nil {
+			d.client.log(ctx).Warn("motion detector snapshot unusable: %v", err)
+			return
+		}
+		defer func() { previous = current }()
+		if previous == nil || previous.cols != current.cols || previous.rows != current.rows {
+			return
+		}
+
+		now := time.Now()
+		score := diffScore(previous, current, d.opts.Regions, d.opts.Sensitivity)
+		d.client.log(ctx).Debug("motion detector score: channel=%d score=%.3f", d.opts.Channel, score)
+
+		ev := Event{Host: d.client.Host(), Channel: d.opts.Channel, Time: now, Detail: MotionDetectorDetail}
+		switch {
+		case score >= d.opts.Threshold:
+			lastMotion = now
+			if !moving {
+				moving = true
+				ev.Type = EventMotionStart
+				handler(ev)
+			}
+		case moving && now.Sub(lastMotion) >= d.opts.Hold:
+			moving = false
+			ev.Type = EventMotionStop
+			handler(ev)
+		}
+	}
+
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+
+	poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+// lumaGrid is a snapshot reduced to the average brightness of a grid of
+// cells, which makes the comparison cheap and tolerant of JPEG noise
+type lumaGrid struct {
+	cols, rows int
+	cells      []uint8
+}
+
+// newLumaGrid decodes a JPEG and reduces it to a lumaGrid
+func newLumaGrid(data []byte) (*lumaGrid, error) {
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	b := img.Bounds()
+	if b.Empty() {
+		return nil, fmt.Errorf("empty snapshot")
+	}
+
+	cols := min(motionGridWidth, b.Dx())
+	rows := max(cols*b.Dy()/b.Dx(), 1)
+	sums := make([]uint64, cols*rows)
+	counts := make([]uint64, cols*rows)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := (y - b.Min.Y) * rows / b.Dy()
+		for x := b.Min.X; x < b.Max.X; x++ {
+			col := (x - b.Min.X) * cols / b.Dx()
+			i := row*cols + col
+			sums[i] += uint64(luma8(img, x, y))
+			counts[i]++
+		}
+	}
+
+	g := &lumaGrid{cols: cols, rows: rows, cells: make([]uint8, cols*rows)}
+	for i := range sums {
+		g.cells[i] = uint8(sums[i] / counts[i])
+	}
+	return g, nil
+}
+
+// luma8 returns the 8-bit brightness of a pixel, reading the Y plane
+// directly for the YCbCr images JPEG decoding produces
+func luma8(img image.Image, x, y int) uint8 {
+	if yc, ok := img.(*image.YCbCr); ok {
+		return yc.Y[yc.YOffset(x, y)]
+	}
+	if g, ok := img.(*image.Gray); ok {
+		return g.GrayAt(x, y).Y
+	}
+	r, gr, bl, _ := img.At(x, y).RGBA()
+	return uint8((299*r + 587*gr + 114*bl) / 1000 >> 8)
+}
+
+// diffScore returns the fraction of cells inside regions (all cells if
+// none) whose brightness changed by at least sensitivity
+func diffScore(a, b *lumaGrid, regions []MotionRegion, sensitivity int) float64 {
+	var watched, changed int
+	for row := 0; row < a.rows; row++ {
+		for col := 0; col < a.cols; col++ {
+			if !inRegions(regions, (float64(col)+0.5)/float64(a.cols), (float64(row)+0.5)/float64(a.rows)) {
+				continue
+			}
+			watched++
+			i := row*a.cols + col
+			delta := int(a.cells[i]) - int(b.cells[i])
+			if delta < 0 {
+				delta = -delta
+			}
+			if delta >= sensitivity {
+				changed++
+			}
+		}
+	}
+	if watched == 0 {
+		return 0
+	}
+	return float64(changed) / float64(watched)
+}
+
+// inRegions reports whether the point (x, y), in fractions of the image
+// size, lies in one of the regions. No regions means the whole image.
+func inRegions(regions []MotionRegion, x, y float64) bool {
+	if len(regions) == 0 {
+		return true
+	}
+	for _, r := range regions {
+		if x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// frameJPEG encodes a dark 160x90 frame with an optional bright rectangle
+func frameJPEG(t *testing.T, bright image.Rectangle) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 160, 90))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{40}), image.Point{}, draw.Src)
+	draw.Draw(img, bright, image.NewUniform(color.Gray{220}), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func mustGrid(t *testing.T, data []byte) *lumaGrid {
+	t.Helper()
+	g, err := newLumaGrid(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestDiffScore(t *testing.T) {
+	empty := mustGrid(t, frameJPEG(t, image.Rectangle{}))
+	// Bright block over the right quarter of the frame
+	block := mustGrid(t, frameJPEG(t, image.Rect(120, 0, 160, 90)))
+
+	if score := diffScore(empty, empty, nil, 25); score != 0 {
+		t.Errorf("expected no change between identical frames, got %.3f", score)
+	}
+	if score := diffScore(empty, block, nil, 25); score < 0.2 || score > 0.3 {
+		t.Errorf("expected about a quarter of cells changed, got %.3f", score)
+	}
+
+	left := []MotionRegion{{X: 0, Y: 0, Width: 0.5, Height: 1}}
+	if score := diffScore(empty, block, left, 25); score != 0 {
+		t.Errorf("expected no change inside the left region, got %.3f", score)
+	}
+	right := []MotionRegion{{X: 0.75, Y: 0, Width: 0.25, Height: 1}}
+	if score := diffScore(empty, block, right, 25); score < 0.9 {
+		t.Errorf("expected the right region to change, got %.3f", score)
+	}
+}
+
+func TestNewLumaGrid_Invalid(t *testing.T) {
+	if _, err := newLumaGrid(testJPEG); err == nil {
+		t.Error("expected error for an undecodable snapshot")
+	}
+}
+
+func TestMotionDetector_Run(t *testing.T) {
+	still := frameJPEG(t, image.Rectangle{})
+	moved := frameJPEG(t, image.Rect(40, 20, 120, 70))
+
+	var (
+		mu    sync.Mutex
+		snaps int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		snaps++
+		frame := still
+		if snaps > 2 {
+			frame = moved
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(frame)
+	}))
+	defer server.Close()
+
+	detector := NewMotionDetector(newTestClient(server), MotionDetectorOptions{
+		Interval: 5 * time.Millisecond,
+		Hold:     20 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan Event, 4)
+	done := make(chan error, 1)
+	go func() { done <- detector.Run(ctx, func(ev Event) { events <- ev }) }()
+
+	for _, want := range []EventType{EventMotionStart, EventMotionStop} {
+		select {
+		case ev := <-events:
+			if ev.Type != want || ev.Detail != MotionDetectorDetail {
+				t.Errorf("expected %s from the detector, got %+v", want, ev)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMotionDetector_NilHandler(t *testing.T) {
+	detector := NewMotionDetector(newCmdServer(t, nil).client(), MotionDetectorOptions{})
+	if err := detector.Run(t.Context(), nil); err == nil {
+		t.Error("expected error for nil handler")
+	}
+}
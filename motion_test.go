@@ -0,0 +1,118 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAlarmStateEvents(t *testing.T) {
+	now := time.Now()
+	previous := &alarmState{motion: false, ai: map[string]bool{AITypePeople: true, AITypeVehicle: false}}
+	current := &alarmState{motion: true, ai: map[string]bool{AITypePeople: false, AITypeVehicle: true}}
+
+	events := alarmStateEvents("cam", 0, previous, current, now)
+	want := []struct {
+		typ    EventType
+		detail string
+	}{
+		{EventMotionStart, ""},
+		{EventAIStop, AITypePeople},
+		{EventAIStart, AITypeVehicle},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i, w := range want {
+		if events[i].Type != w.typ || events[i].Detail != w.detail {
+			t.Errorf("event %d: expected %s/%q, got %s/%q", i, w.typ, w.detail, events[i].Type, events[i].Detail)
+		}
+	}
+
+	if events := alarmStateEvents("cam", 0, current, current, now); len(events) != 0 {
+		t.Errorf("expected no events for unchanged state, got %+v", events)
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": 0}`,
+		"GetAiState": `{"channel": 0, "people": {"alarm_state": 0, "support": 1}, "vehicle": {"alarm_state": 0, "support": 0}}`,
+	})
+	client := srv.client()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan Event, 8)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Alarm.WatchEvents(ctx, 0, 10*time.Millisecond, func(ev Event) {
+			events <- ev
+		})
+	}()
+
+	for srv.callCount("GetAiState") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.set("GetAiState", `{"channel": 0, "people": {"alarm_state": 1, "support": 1}, "vehicle": {"alarm_state": 0, "support": 0}}`)
+	srv.set("GetMdState", `{"state": 1}`)
+
+	got := map[EventType]string{}
+	for len(got) < 2 {
+		select {
+		case ev := <-events:
+			got[ev.Type] = ev.Detail
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+	}
+	if _, ok := got[EventMotionStart]; !ok {
+		t.Error("expected motion start event")
+	}
+	if got[EventAIStart] != AITypePeople {
+		t.Errorf("expected AI start for people, got %q", got[EventAIStart])
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWatchEvents_AINotSupported(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": 0}`,
+	})
+	client := srv.client()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	events := make(chan Event, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Alarm.WatchEvents(ctx, 0, 10*time.Millisecond, func(ev Event) {
+			events <- ev
+		})
+	}()
+
+	for srv.callCount("GetMdState") < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.set("GetMdState", `{"state": 1}`)
+
+	select {
+	case ev := <-events:
+		if ev.Type != EventMotionStart {
+			t.Errorf("expected motion start, got %s", ev.Type)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for motion event")
+	}
+	cancel()
+	<-done
+
+	if n := srv.callCount("GetAiState"); n != 1 {
+		t.Errorf("expected AI state to be probed once, got %d calls", n)
+	}
+}
@@ -2,8 +2,10 @@ package reolink
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 )
 
 // NetworkAPI provides methods for network configuration
@@ -13,17 +15,48 @@ type NetworkAPI struct {
 
 // NetPort represents network port configuration
 type NetPort struct {
-	HTTPEnable  int `json:"httpEnable"`  // 0=disabled, 1=enabled
-	HTTPPort    int `json:"httpPort"`    // HTTP port (default: 80)
-	HTTPSEnable int `json:"httpsEnable"` // 0=disabled, 1=enabled
-	HTTPSPort   int `json:"httpsPort"`   // HTTPS port (default: 443)
-	MediaPort   int `json:"mediaPort"`   // Media port (default: 9000)
-	OnvifEnable int `json:"onvifEnable"` // 0=disabled, 1=enabled
-	OnvifPort   int `json:"onvifPort"`   // ONVIF port (default: 8000)
-	RTMPEnable  int `json:"rtmpEnable"`  // 0=disabled, 1=enabled
-	RTMPPort    int `json:"rtmpPort"`    // RTMP port (default: 1935)
-	RTSPEnable  int `json:"rtspEnable"`  // 0=disabled, 1=enabled
-	RTSPPort    int `json:"rtspPort"`    // RTSP port (default: 554)
+	HTTPEnable  BoolInt `json:"httpEnable"`  // 0=disabled, 1=enabled
+	HTTPPort    int     `json:"httpPort"`    // HTTP port (default: 80)
+	HTTPSEnable BoolInt `json:"httpsEnable"` // 0=disabled, 1=enabled
+	HTTPSPort   int     `json:"httpsPort"`   // HTTPS port (default: 443)
+	MediaPort   int     `json:"mediaPort"`   // Media port (default: 9000)
+	OnvifEnable BoolInt `json:"onvifEnable"` // 0=disabled, 1=enabled
+	OnvifPort   int     `json:"onvifPort"`   // ONVIF port (default: 8000)
+	RTMPEnable  BoolInt `json:"rtmpEnable"`  // 0=disabled, 1=enabled
+	RTMPPort    int     `json:"rtmpPort"`    // RTMP port (default: 1935)
+	RTSPEnable  BoolInt `json:"rtspEnable"`  // 0=disabled, 1=enabled
+	RTSPPort    int     `json:"rtspPort"`    // RTSP port (default: 554)
+}
+
+// Validate checks that the ports of enabled services, and the media port,
+// are valid and distinct
+func (p NetPort) Validate() error {
+	ports := []struct {
+		name    string
+		enabled bool
+		port    int
+	}{
+		{"HTTP", p.HTTPEnable.Bool(), p.HTTPPort},
+		{"HTTPS", p.HTTPSEnable.Bool(), p.HTTPSPort},
+		{"media", true, p.MediaPort},
+		{"ONVIF", p.OnvifEnable.Bool(), p.OnvifPort},
+		{"RTMP", p.RTMPEnable.Bool(), p.RTMPPort},
+		{"RTSP", p.RTSPEnable.Bool(), p.RTSPPort},
+	}
+	used := make(map[int]string)
+	for _, pt := range ports {
+		if !pt.enabled {
+			continue
+		}
+		if pt.port < 1 || pt.port > 65535 {
+			return fmt.Errorf("%s port must be between 1 and 65535, got %d", pt.name, pt.port)
+		}
+		if other, ok := used[pt.port]; ok {
+			return fmt.Errorf("%s and %s both use port %d", other, pt.name, pt.port)
+		}
+		used[pt.port] = pt.name
+	}
+	return nil
 }
 
 // NetPortValue represents the response value for GetNetPort
@@ -59,10 +92,10 @@ type LocalLinkValue struct {
 
 // Ntp represents NTP configuration
 type Ntp struct {
-	Enable   int    `json:"enable"`   // 0=disabled, 1=enabled
-	Server   string `json:"server"`   // NTP server address
-	Port     int    `json:"port"`     // NTP server port (default: 123)
-	Interval int    `json:"interval"` // Sync interval in seconds (0=immediate, 10-65535)
+	Enable   BoolInt `json:"enable"`   // 0=disabled, 1=enabled
+	Server   string  `json:"server"`   // NTP server address
+	Port     int     `json:"port"`     // NTP server port (default: 123)
+	Interval int     `json:"interval"` // Sync interval in seconds (0=immediate, 10-65535)
 }
 
 // NtpValue represents the response value for GetNtp
@@ -72,7 +105,7 @@ type NtpValue struct {
 
 // GetNetPort gets network port configuration
 func (n *NetworkAPI) GetNetPort(ctx context.Context) (*NetPort, error) {
-	n.client.logger.Debug("getting network port configuration")
+	n.client.log(ctx).Debug("getting network port configuration")
 
 	req := []Request{{
 		Cmd:    "GetNetPort",
@@ -81,36 +114,96 @@ func (n *NetworkAPI) GetNetPort(ctx context.Context) (*NetPort, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get network port configuration: %v", err)
+		n.client.log(ctx).Error("failed to get network port configuration: %v", err)
 		return nil, fmt.Errorf("GetNetPort request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetNetPort")
-		n.client.logger.Error("failed to get network port configuration: %v", err)
+		n.client.log(ctx).Error("failed to get network port configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get network port configuration: %v", err)
+		n.client.log(ctx).Error("failed to get network port configuration: %v", err)
 		return nil, err
 	}
 
 	var value NetPortValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse network port configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse network port configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetNetPort response: %w", err)
 	}
+	n.client.rememberHTTPPort(&value.NetPort)
+	n.client.updateState(ctx, func(state *HostState) {
+		state.Ports = &value.NetPort
+	})
 
-	n.client.logger.Info("successfully retrieved network port configuration: httpPort=%d httpsPort=%d",
+	n.client.log(ctx).Info("successfully retrieved network port configuration: httpPort=%d httpsPort=%d",
 		value.NetPort.HTTPPort, value.NetPort.HTTPSPort)
 	return &value.NetPort, nil
 }
 
-// SetNetPort sets network port configuration
+// SetNetPort sets network port configuration.
+//
+// The change can affect the transport the client itself uses. When the
+// client connects to the camera's own port, a new port for that transport
+// is followed after a successful change. Disabling the transport while the
+// other one stays enabled switches the client to it. WithPortGuard refuses
+// to disable the transport in use.
 func (n *NetworkAPI) SetNetPort(ctx context.Context, netPort NetPort) error {
-	n.client.logger.Info("setting network port configuration: httpPort=%d httpsPort=%d",
+	n.client.log(ctx).Info("setting network port configuration: httpPort=%d httpsPort=%d",
+		netPort.HTTPPort, netPort.HTTPSPort)
+
+	// The current configuration tells whether the client connects to the
+	// camera's own port, which a change would move. It is only needed when
+	// netPort moves or disables the transport the client uses.
+	var old *NetPort
+	if n.client.netPortAffects(netPort) {
+		var err error
+		if old, err = n.GetNetPort(ctx); err != nil {
+			n.client.log(ctx).Debug("could not read current port configuration: %v", err)
+			old = nil
+		}
+	}
+	return n.setNetPort(ctx, old, netPort)
+}
+
+// UpdateNetPort changes the network port configuration by reading it,
+// calling update on it and writing it back if update changed it. Unlike
+// SetNetPort with a partly filled NetPort, fields update leaves alone keep
+// their values, so other ports are not disabled by accident. The result
+// must pass NetPort.Validate.
+func (n *NetworkAPI) UpdateNetPort(ctx context.Context, update func(*NetPort)) error {
+	if update == nil {
+		return fmt.Errorf("update must not be nil")
+	}
+
+	old, err := n.GetNetPort(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read current port configuration: %w", err)
+	}
+	netPort := *old
+	update(&netPort)
+	if netPort == *old {
+		n.client.log(ctx).Debug("network port configuration unchanged, not writing it")
+		return nil
+	}
+	if err := netPort.Validate(); err != nil {
+		return err
+	}
+
+	n.client.log(ctx).Info("updating network port configuration: httpPort=%d httpsPort=%d",
 		netPort.HTTPPort, netPort.HTTPSPort)
+	return n.setNetPort(ctx, old, netPort)
+}
+
+// setNetPort writes netPort, which replaces old (nil if unknown)
+func (n *NetworkAPI) setNetPort(ctx context.Context, old *NetPort, netPort NetPort) error {
+	if err := n.client.checkNetPort(ctx, old, netPort); err != nil {
+		n.client.log(ctx).Error("failed to set network port configuration: %v", err)
+		return err
+	}
 
 	req := []Request{{
 		Cmd: "SetNetPort",
@@ -121,28 +214,153 @@ func (n *NetworkAPI) SetNetPort(ctx context.Context, netPort NetPort) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set network port configuration: %v", err)
+		n.client.log(ctx).Error("failed to set network port configuration: %v", err)
 		return fmt.Errorf("SetNetPort request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetNetPort")
-		n.client.logger.Error("failed to set network port configuration: %v", err)
+		n.client.log(ctx).Error("failed to set network port configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set network port configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set network port configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set network port configuration")
+	n.client.rememberHTTPPort(&netPort)
+	n.client.updateState(ctx, func(state *HostState) {
+		state.Ports = &netPort
+	})
+	n.client.followNetPort(ctx, old, netPort)
+
+	n.client.log(ctx).Info("successfully set network port configuration")
 	return nil
 }
 
+// activeTransport returns whether the client uses HTTPS and the port it
+// connects to
+func (c *Client) activeTransport() (https bool, port int) {
+	host, https := c.endpoint()
+	port = 80
+	if https {
+		port = 443
+	}
+	if _, p, err := net.SplitHostPort(host); err == nil {
+		if n, err := strconv.Atoi(p); err == nil {
+			port = n
+		}
+	}
+	return https, port
+}
+
+// netPortAffects reports whether netPort disables the transport the client
+// uses or sets it to a port other than the one the client connects to
+func (c *Client) netPortAffects(netPort NetPort) bool {
+	https, port := c.activeTransport()
+	enabled, newPort := netPort.HTTPEnable.Bool(), netPort.HTTPPort
+	if https {
+		enabled, newPort = netPort.HTTPSEnable.Bool(), netPort.HTTPSPort
+	}
+	return !enabled || (newPort != 0 && newPort != port)
+}
+
+// connectsDirectly reports whether the client reaches the camera on the
+// port old configures for the active transport, rather than through a
+// forwarded port that a port change does not affect
+func (c *Client) connectsDirectly(old *NetPort) bool {
+	if old == nil || c.Host() == "" {
+		return false
+	}
+	https, port := c.activeTransport()
+	if https {
+		return old.HTTPSPort == port
+	}
+	return old.HTTPPort == port
+}
+
+// checkNetPort warns about a port configuration that affects the client's
+// own transport, and with WithPortGuard refuses one that disables it
+func (c *Client) checkNetPort(ctx context.Context, old *NetPort, netPort NetPort) error {
+	https, port := c.activeTransport()
+	name, disabled, newPort := "HTTP", !netPort.HTTPEnable.Bool(), netPort.HTTPPort
+	if https {
+		name, disabled, newPort = "HTTPS", !netPort.HTTPSEnable.Bool(), netPort.HTTPSPort
+	}
+	if disabled {
+		if c.portGuard {
+			return fmt.Errorf("refusing to disable %s, which this client uses (WithPortGuard)", name)
+		}
+		if !netPort.HTTPEnable.Bool() && !netPort.HTTPSEnable.Bool() {
+			c.log(ctx).Warn("SetNetPort disables both HTTP and HTTPS; the camera API will be unreachable")
+		}
+		return nil
+	}
+	if c.connectsDirectly(old) && newPort != 0 && newPort != port {
+		c.log(ctx).Warn("SetNetPort moves the %s port this client uses from %d to %d", name, port, newPort)
+	}
+	return nil
+}
+
+// followNetPort points the client at the transport and port the camera
+// uses after netPort replaced old. Ports are only followed when the client
+// connects to the camera's own port.
+func (c *Client) followNetPort(ctx context.Context, old *NetPort, netPort NetPort) {
+	https, port := c.activeTransport()
+	direct := c.connectsDirectly(old)
+
+	newHTTPS := https
+	switch {
+	case !https && !netPort.HTTPEnable.Bool() && netPort.HTTPSEnable.Bool():
+		c.log(ctx).Info("HTTP disabled, switching client to HTTPS")
+		newHTTPS = true
+	case https && !netPort.HTTPSEnable.Bool() && netPort.HTTPEnable.Bool():
+		c.log(ctx).Info("HTTPS disabled, switching client to HTTP")
+		newHTTPS = false
+	}
+	newPort, enabled := netPort.HTTPPort, netPort.HTTPEnable.Bool()
+	if newHTTPS {
+		newPort, enabled = netPort.HTTPSPort, netPort.HTTPSEnable.Bool()
+	}
+	if !enabled {
+		return // Both transports disabled; checkNetPort warned
+	}
+	if !direct || newPort == 0 {
+		newPort = port
+	}
+	if newHTTPS == https && newPort == port {
+		return
+	}
+
+	oldHost := c.Host()
+	host := oldHost
+	if direct {
+		hostname := oldHost
+		if h, _, err := net.SplitHostPort(oldHost); err == nil {
+			hostname = h
+		}
+		host = net.JoinHostPort(hostname, strconv.Itoa(newPort))
+		if (!newHTTPS && newPort == 80) || (newHTTPS && newPort == 443) {
+			host = strings.TrimSuffix(host, ":"+strconv.Itoa(newPort))
+		}
+	}
+	c.log(ctx).Info("following port change: host=%s https=%t", host, newHTTPS)
+	if host == oldHost {
+		c.setEndpoint(host, newHTTPS)
+		return
+	}
+	// Cached responses and stored state are keyed by host: drop the cache
+	// entries of the old host and carry the session over to the new one
+	c.InvalidateCache()
+	oldKey := c.stateKey()
+	c.setEndpoint(host, newHTTPS)
+	c.moveState(ctx, oldKey, c.stateKey())
+}
+
 // GetLocalLink gets local network configuration
 func (n *NetworkAPI) GetLocalLink(ctx context.Context) (*LocalLink, error) {
-	n.client.logger.Debug("getting local network configuration")
+	n.client.log(ctx).Debug("getting local network configuration")
 
 	req := []Request{{
 		Cmd:    "GetLocalLink",
@@ -151,35 +369,35 @@ func (n *NetworkAPI) GetLocalLink(ctx context.Context) (*LocalLink, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get local network configuration: %v", err)
+		n.client.log(ctx).Error("failed to get local network configuration: %v", err)
 		return nil, fmt.Errorf("GetLocalLink request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetLocalLink")
-		n.client.logger.Error("failed to get local network configuration: %v", err)
+		n.client.log(ctx).Error("failed to get local network configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get local network configuration: %v", err)
+		n.client.log(ctx).Error("failed to get local network configuration: %v", err)
 		return nil, err
 	}
 
 	var value LocalLinkValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse local network configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse local network configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetLocalLink response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved local network configuration: type=%s",
+	n.client.log(ctx).Info("successfully retrieved local network configuration: type=%s",
 		value.LocalLink.Type)
 	return &value.LocalLink, nil
 }
 
 // SetLocalLink sets local network configuration
 func (n *NetworkAPI) SetLocalLink(ctx context.Context, localLink LocalLink) error {
-	n.client.logger.Info("setting local network configuration: type=%s",
+	n.client.log(ctx).Info("setting local network configuration: type=%s",
 		localLink.Type)
 
 	req := []Request{{
@@ -191,28 +409,28 @@ func (n *NetworkAPI) SetLocalLink(ctx context.Context, localLink LocalLink) erro
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set local network configuration: %v", err)
+		n.client.log(ctx).Error("failed to set local network configuration: %v", err)
 		return fmt.Errorf("SetLocalLink request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetLocalLink")
-		n.client.logger.Error("failed to set local network configuration: %v", err)
+		n.client.log(ctx).Error("failed to set local network configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set local network configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set local network configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set local network configuration")
+	n.client.log(ctx).Info("successfully set local network configuration")
 	return nil
 }
 
 // GetNtp gets NTP configuration
 func (n *NetworkAPI) GetNtp(ctx context.Context) (*Ntp, error) {
-	n.client.logger.Debug("getting NTP configuration")
+	n.client.log(ctx).Debug("getting NTP configuration")
 
 	req := []Request{{
 		Cmd:    "GetNtp",
@@ -221,34 +439,34 @@ func (n *NetworkAPI) GetNtp(ctx context.Context) (*Ntp, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get NTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get NTP configuration: %v", err)
 		return nil, fmt.Errorf("GetNtp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetNtp")
-		n.client.logger.Error("failed to get NTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get NTP configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get NTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get NTP configuration: %v", err)
 		return nil, err
 	}
 
 	var value NtpValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse NTP configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse NTP configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetNtp response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved NTP configuration: server=%s enable=%d", value.Ntp.Server, value.Ntp.Enable)
+	n.client.log(ctx).Info("successfully retrieved NTP configuration: server=%s enable=%d", value.Ntp.Server, value.Ntp.Enable)
 	return &value.Ntp, nil
 }
 
 // SetNtp sets NTP configuration
 func (n *NetworkAPI) SetNtp(ctx context.Context, ntp Ntp) error {
-	n.client.logger.Info("setting NTP configuration: server=%s enable=%d", ntp.Server, ntp.Enable)
+	n.client.log(ctx).Info("setting NTP configuration: server=%s enable=%d", ntp.Server, ntp.Enable)
 
 	req := []Request{{
 		Cmd: "SetNtp",
@@ -259,22 +477,22 @@ func (n *NetworkAPI) SetNtp(ctx context.Context, ntp Ntp) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set NTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to set NTP configuration: %v", err)
 		return fmt.Errorf("SetNtp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetNtp")
-		n.client.logger.Error("failed to set NTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to set NTP configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set NTP configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set NTP configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set NTP configuration")
+	n.client.log(ctx).Info("successfully set NTP configuration")
 	return nil
 }
 
@@ -291,11 +509,11 @@ type WifiValue struct {
 
 // Ddns represents DDNS configuration
 type Ddns struct {
-	Enable   int    `json:"enable"`   // 0=disabled, 1=enabled
-	Type     string `json:"type"`     // "3322" or "Dyndns"
-	UserName string `json:"userName"` // DDNS username
-	Password string `json:"password"` // DDNS password
-	Domain   string `json:"domain"`   // Domain name
+	Enable   BoolInt `json:"enable"`   // 0=disabled, 1=enabled
+	Type     string  `json:"type"`     // "3322" or "Dyndns"
+	UserName string  `json:"userName"` // DDNS username
+	Password string  `json:"password"` // DDNS password
+	Domain   string  `json:"domain"`   // Domain name
 }
 
 // DdnsValue represents the response value for GetDdns
@@ -312,13 +530,31 @@ type Email struct {
 	Addr1      string        `json:"addr1"`      // Recipient email 1
 	Addr2      string        `json:"addr2"`      // Recipient email 2
 	Addr3      string        `json:"addr3"`      // Recipient email 3
-	Interval   int           `json:"interval"`   // Email interval in seconds
+	Interval   interface{}   `json:"interval"`   // Email interval: "5 Minutes" etc. on current firmware, seconds on older
 	Schedule   EmailSchedule `json:"schedule"`   // Email schedule
+
+	Attachment     string               `json:"attachment,omitempty"`     // v1 attachment: "no", "picture", "video" or "onlyPicture"
+	AttachmentType *EmailAttachmentType `json:"attachmentType,omitempty"` // v2.0 attachment; nil leaves it unchanged
+}
+
+// EmailAttachmentType is the attachment content of alarm emails (v2.0)
+type EmailAttachmentType int
+
+// Email attachment types
+const (
+	EmailAttachmentNone    EmailAttachmentType = 0 // No attachment
+	EmailAttachmentPicture EmailAttachmentType = 1 // Snapshot picture
+	EmailAttachmentVideo   EmailAttachmentType = 2 // Video clip
+)
+
+// SetAttachmentType sets the v2.0 attachment type
+func (e *Email) SetAttachmentType(t EmailAttachmentType) {
+	e.AttachmentType = &t
 }
 
 // EmailSchedule represents email schedule configuration
 type EmailSchedule struct {
-	Enable int         `json:"enable"` // 0=disabled, 1=enabled
+	Enable BoolInt     `json:"enable"` // 0=disabled, 1=enabled
 	Table  interface{} `json:"table"`  // string for v1, EmailScheduleTable for v2.0
 }
 
@@ -348,7 +584,7 @@ type Ftp struct {
 
 // FtpSchedule represents FTP schedule configuration
 type FtpSchedule struct {
-	Enable int         `json:"enable"` // 0=disabled, 1=enabled
+	Enable BoolInt     `json:"enable"` // 0=disabled, 1=enabled
 	Table  interface{} `json:"table"`  // string for v1, FtpScheduleTable for v2.0
 }
 
@@ -373,7 +609,7 @@ type Push struct {
 
 // PushSchedule represents push schedule configuration
 type PushSchedule struct {
-	Enable int         `json:"enable"` // 0=disabled, 1=enabled
+	Enable BoolInt     `json:"enable"` // 0=disabled, 1=enabled
 	Table  interface{} `json:"table"`  // string for v1, PushScheduleTable for v2.0
 }
 
@@ -393,8 +629,8 @@ type PushValue struct {
 
 // P2p represents P2P configuration
 type P2p struct {
-	Enable int    `json:"enable"` // 0=disabled, 1=enabled
-	UID    string `json:"uid"`    // P2P UID
+	Enable BoolInt `json:"enable"` // 0=disabled, 1=enabled
+	UID    string  `json:"uid"`    // P2P UID
 }
 
 // P2pValue represents the response value for GetP2p
@@ -404,7 +640,7 @@ type P2pValue struct {
 
 // Upnp represents UPnP configuration
 type Upnp struct {
-	Enable int `json:"enable"` // 0=disabled, 1=enabled
+	Enable BoolInt `json:"enable"` // 0=disabled, 1=enabled
 }
 
 // UpnpValue represents the response value for GetUpnp
@@ -414,7 +650,7 @@ type UpnpValue struct {
 
 // GetWifi gets WiFi configuration
 func (n *NetworkAPI) GetWifi(ctx context.Context) (*Wifi, error) {
-	n.client.logger.Debug("getting WiFi configuration")
+	n.client.log(ctx).Debug("getting WiFi configuration")
 
 	req := []Request{{
 		Cmd:    "GetWifi",
@@ -423,34 +659,34 @@ func (n *NetworkAPI) GetWifi(ctx context.Context) (*Wifi, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get WiFi configuration: %v", err)
+		n.client.log(ctx).Error("failed to get WiFi configuration: %v", err)
 		return nil, fmt.Errorf("GetWifi request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetWifi")
-		n.client.logger.Error("failed to get WiFi configuration: %v", err)
+		n.client.log(ctx).Error("failed to get WiFi configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get WiFi configuration: %v", err)
+		n.client.log(ctx).Error("failed to get WiFi configuration: %v", err)
 		return nil, err
 	}
 
 	var value WifiValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse WiFi configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse WiFi configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetWifi response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved WiFi configuration: ssid=%s", value.Wifi.SSID)
+	n.client.log(ctx).Info("successfully retrieved WiFi configuration: ssid=%s", value.Wifi.SSID)
 	return &value.Wifi, nil
 }
 
 // SetWifi sets WiFi configuration
 func (n *NetworkAPI) SetWifi(ctx context.Context, wifi Wifi) error {
-	n.client.logger.Info("setting WiFi configuration: ssid=%s", wifi.SSID)
+	n.client.log(ctx).Info("setting WiFi configuration: ssid=%s", wifi.SSID)
 
 	req := []Request{{
 		Cmd: "SetWifi",
@@ -461,28 +697,28 @@ func (n *NetworkAPI) SetWifi(ctx context.Context, wifi Wifi) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set WiFi configuration: %v", err)
+		n.client.log(ctx).Error("failed to set WiFi configuration: %v", err)
 		return fmt.Errorf("SetWifi request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetWifi")
-		n.client.logger.Error("failed to set WiFi configuration: %v", err)
+		n.client.log(ctx).Error("failed to set WiFi configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set WiFi configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set WiFi configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set WiFi configuration")
+	n.client.log(ctx).Info("successfully set WiFi configuration")
 	return nil
 }
 
 // GetDdns gets DDNS configuration
 func (n *NetworkAPI) GetDdns(ctx context.Context) (*Ddns, error) {
-	n.client.logger.Debug("getting DDNS configuration")
+	n.client.log(ctx).Debug("getting DDNS configuration")
 
 	req := []Request{{
 		Cmd:    "GetDdns",
@@ -491,34 +727,34 @@ func (n *NetworkAPI) GetDdns(ctx context.Context) (*Ddns, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get DDNS configuration: %v", err)
+		n.client.log(ctx).Error("failed to get DDNS configuration: %v", err)
 		return nil, fmt.Errorf("GetDdns request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetDdns")
-		n.client.logger.Error("failed to get DDNS configuration: %v", err)
+		n.client.log(ctx).Error("failed to get DDNS configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get DDNS configuration: %v", err)
+		n.client.log(ctx).Error("failed to get DDNS configuration: %v", err)
 		return nil, err
 	}
 
 	var value DdnsValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse DDNS configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse DDNS configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetDdns response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved DDNS configuration: enable=%d type=%s", value.Ddns.Enable, value.Ddns.Type)
+	n.client.log(ctx).Info("successfully retrieved DDNS configuration: enable=%d type=%s", value.Ddns.Enable, value.Ddns.Type)
 	return &value.Ddns, nil
 }
 
 // SetDdns sets DDNS configuration
 func (n *NetworkAPI) SetDdns(ctx context.Context, ddns Ddns) error {
-	n.client.logger.Info("setting DDNS configuration: enable=%d type=%s", ddns.Enable, ddns.Type)
+	n.client.log(ctx).Info("setting DDNS configuration: enable=%d type=%s", ddns.Enable, ddns.Type)
 
 	req := []Request{{
 		Cmd: "SetDdns",
@@ -529,28 +765,28 @@ func (n *NetworkAPI) SetDdns(ctx context.Context, ddns Ddns) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set DDNS configuration: %v", err)
+		n.client.log(ctx).Error("failed to set DDNS configuration: %v", err)
 		return fmt.Errorf("SetDdns request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetDdns")
-		n.client.logger.Error("failed to set DDNS configuration: %v", err)
+		n.client.log(ctx).Error("failed to set DDNS configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set DDNS configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set DDNS configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set DDNS configuration")
+	n.client.log(ctx).Info("successfully set DDNS configuration")
 	return nil
 }
 
 // GetEmail gets email configuration
 func (n *NetworkAPI) GetEmail(ctx context.Context) (*Email, error) {
-	n.client.logger.Debug("getting email configuration")
+	n.client.log(ctx).Debug("getting email configuration")
 
 	req := []Request{{
 		Cmd:    "GetEmail",
@@ -559,34 +795,34 @@ func (n *NetworkAPI) GetEmail(ctx context.Context) (*Email, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get email configuration: %v", err)
+		n.client.log(ctx).Error("failed to get email configuration: %v", err)
 		return nil, fmt.Errorf("GetEmail request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetEmail")
-		n.client.logger.Error("failed to get email configuration: %v", err)
+		n.client.log(ctx).Error("failed to get email configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get email configuration: %v", err)
+		n.client.log(ctx).Error("failed to get email configuration: %v", err)
 		return nil, err
 	}
 
 	var value EmailValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse email configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse email configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetEmail response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved email configuration: server=%s", value.Email.SMTPServer)
+	n.client.log(ctx).Info("successfully retrieved email configuration: server=%s", value.Email.SMTPServer)
 	return &value.Email, nil
 }
 
 // SetEmail sets email configuration
 func (n *NetworkAPI) SetEmail(ctx context.Context, email Email) error {
-	n.client.logger.Info("setting email configuration: server=%s", email.SMTPServer)
+	n.client.log(ctx).Info("setting email configuration: server=%s", email.SMTPServer)
 
 	req := []Request{{
 		Cmd: "SetEmail",
@@ -597,28 +833,28 @@ func (n *NetworkAPI) SetEmail(ctx context.Context, email Email) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set email configuration: %v", err)
+		n.client.log(ctx).Error("failed to set email configuration: %v", err)
 		return fmt.Errorf("SetEmail request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetEmail")
-		n.client.logger.Error("failed to set email configuration: %v", err)
+		n.client.log(ctx).Error("failed to set email configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set email configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set email configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set email configuration")
+	n.client.log(ctx).Info("successfully set email configuration")
 	return nil
 }
 
 // GetFtp gets FTP configuration
 func (n *NetworkAPI) GetFtp(ctx context.Context) (*Ftp, error) {
-	n.client.logger.Debug("getting FTP configuration")
+	n.client.log(ctx).Debug("getting FTP configuration")
 
 	req := []Request{{
 		Cmd:    "GetFtp",
@@ -627,34 +863,34 @@ func (n *NetworkAPI) GetFtp(ctx context.Context) (*Ftp, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get FTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get FTP configuration: %v", err)
 		return nil, fmt.Errorf("GetFtp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetFtp")
-		n.client.logger.Error("failed to get FTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get FTP configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get FTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get FTP configuration: %v", err)
 		return nil, err
 	}
 
 	var value FtpValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse FTP configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse FTP configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetFtp response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved FTP configuration: server=%s", value.Ftp.Server)
+	n.client.log(ctx).Info("successfully retrieved FTP configuration: server=%s", value.Ftp.Server)
 	return &value.Ftp, nil
 }
 
 // SetFtp sets FTP configuration
 func (n *NetworkAPI) SetFtp(ctx context.Context, ftp Ftp) error {
-	n.client.logger.Info("setting FTP configuration: server=%s", ftp.Server)
+	n.client.log(ctx).Info("setting FTP configuration: server=%s", ftp.Server)
 
 	req := []Request{{
 		Cmd: "SetFtp",
@@ -665,28 +901,28 @@ func (n *NetworkAPI) SetFtp(ctx context.Context, ftp Ftp) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set FTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to set FTP configuration: %v", err)
 		return fmt.Errorf("SetFtp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetFtp")
-		n.client.logger.Error("failed to set FTP configuration: %v", err)
+		n.client.log(ctx).Error("failed to set FTP configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set FTP configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set FTP configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set FTP configuration")
+	n.client.log(ctx).Info("successfully set FTP configuration")
 	return nil
 }
 
 // GetPush gets push notification configuration
 func (n *NetworkAPI) GetPush(ctx context.Context) (*Push, error) {
-	n.client.logger.Debug("getting push notification configuration")
+	n.client.log(ctx).Debug("getting push notification configuration")
 
 	req := []Request{{
 		Cmd:    "GetPush",
@@ -695,34 +931,34 @@ func (n *NetworkAPI) GetPush(ctx context.Context) (*Push, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get push notification configuration: %v", err)
+		n.client.log(ctx).Error("failed to get push notification configuration: %v", err)
 		return nil, fmt.Errorf("GetPush request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetPush")
-		n.client.logger.Error("failed to get push notification configuration: %v", err)
+		n.client.log(ctx).Error("failed to get push notification configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get push notification configuration: %v", err)
+		n.client.log(ctx).Error("failed to get push notification configuration: %v", err)
 		return nil, err
 	}
 
 	var value PushValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse push notification configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse push notification configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetPush response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved push notification configuration")
+	n.client.log(ctx).Info("successfully retrieved push notification configuration")
 	return &value.Push, nil
 }
 
 // SetPush sets push notification configuration
 func (n *NetworkAPI) SetPush(ctx context.Context, push Push) error {
-	n.client.logger.Info("setting push notification configuration")
+	n.client.log(ctx).Info("setting push notification configuration")
 
 	req := []Request{{
 		Cmd: "SetPush",
@@ -733,28 +969,28 @@ func (n *NetworkAPI) SetPush(ctx context.Context, push Push) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set push notification configuration: %v", err)
+		n.client.log(ctx).Error("failed to set push notification configuration: %v", err)
 		return fmt.Errorf("SetPush request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetPush")
-		n.client.logger.Error("failed to set push notification configuration: %v", err)
+		n.client.log(ctx).Error("failed to set push notification configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set push notification configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set push notification configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set push notification configuration")
+	n.client.log(ctx).Info("successfully set push notification configuration")
 	return nil
 }
 
 // GetP2p gets P2P configuration
 func (n *NetworkAPI) GetP2p(ctx context.Context) (*P2p, error) {
-	n.client.logger.Debug("getting P2P configuration")
+	n.client.log(ctx).Debug("getting P2P configuration")
 
 	req := []Request{{
 		Cmd:    "GetP2p",
@@ -763,34 +999,34 @@ func (n *NetworkAPI) GetP2p(ctx context.Context) (*P2p, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get P2P configuration: %v", err)
+		n.client.log(ctx).Error("failed to get P2P configuration: %v", err)
 		return nil, fmt.Errorf("GetP2p request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetP2p")
-		n.client.logger.Error("failed to get P2P configuration: %v", err)
+		n.client.log(ctx).Error("failed to get P2P configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get P2P configuration: %v", err)
+		n.client.log(ctx).Error("failed to get P2P configuration: %v", err)
 		return nil, err
 	}
 
 	var value P2pValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse P2P configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse P2P configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetP2p response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved P2P configuration: enable=%d", value.P2p.Enable)
+	n.client.log(ctx).Info("successfully retrieved P2P configuration: enable=%d", value.P2p.Enable)
 	return &value.P2p, nil
 }
 
 // SetP2p sets P2P configuration
 func (n *NetworkAPI) SetP2p(ctx context.Context, p2p P2p) error {
-	n.client.logger.Info("setting P2P configuration: enable=%d", p2p.Enable)
+	n.client.log(ctx).Info("setting P2P configuration: enable=%d", p2p.Enable)
 
 	req := []Request{{
 		Cmd: "SetP2p",
@@ -801,28 +1037,28 @@ func (n *NetworkAPI) SetP2p(ctx context.Context, p2p P2p) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set P2P configuration: %v", err)
+		n.client.log(ctx).Error("failed to set P2P configuration: %v", err)
 		return fmt.Errorf("SetP2p request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetP2p")
-		n.client.logger.Error("failed to set P2P configuration: %v", err)
+		n.client.log(ctx).Error("failed to set P2P configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set P2P configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set P2P configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set P2P configuration")
+	n.client.log(ctx).Info("successfully set P2P configuration")
 	return nil
 }
 
 // GetUpnp gets UPnP configuration
 func (n *NetworkAPI) GetUpnp(ctx context.Context) (*Upnp, error) {
-	n.client.logger.Debug("getting UPnP configuration")
+	n.client.log(ctx).Debug("getting UPnP configuration")
 
 	req := []Request{{
 		Cmd:    "GetUpnp",
@@ -831,34 +1067,34 @@ func (n *NetworkAPI) GetUpnp(ctx context.Context) (*Upnp, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get UPnP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get UPnP configuration: %v", err)
 		return nil, fmt.Errorf("GetUpnp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetUpnp")
-		n.client.logger.Error("failed to get UPnP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get UPnP configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get UPnP configuration: %v", err)
+		n.client.log(ctx).Error("failed to get UPnP configuration: %v", err)
 		return nil, err
 	}
 
 	var value UpnpValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse UPnP configuration response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse UPnP configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetUpnp response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved UPnP configuration: enable=%d", value.Upnp.Enable)
+	n.client.log(ctx).Info("successfully retrieved UPnP configuration: enable=%d", value.Upnp.Enable)
 	return &value.Upnp, nil
 }
 
 // SetUpnp sets UPnP configuration
 func (n *NetworkAPI) SetUpnp(ctx context.Context, upnp Upnp) error {
-	n.client.logger.Info("setting UPnP configuration: enable=%d", upnp.Enable)
+	n.client.log(ctx).Info("setting UPnP configuration: enable=%d", upnp.Enable)
 
 	req := []Request{{
 		Cmd: "SetUpnp",
@@ -869,57 +1105,64 @@ func (n *NetworkAPI) SetUpnp(ctx context.Context, upnp Upnp) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set UPnP configuration: %v", err)
+		n.client.log(ctx).Error("failed to set UPnP configuration: %v", err)
 		return fmt.Errorf("SetUpnp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetUpnp")
-		n.client.logger.Error("failed to set UPnP configuration: %v", err)
+		n.client.log(ctx).Error("failed to set UPnP configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set UPnP configuration: %v", apiErr)
+		n.client.log(ctx).Error("failed to set UPnP configuration: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set UPnP configuration")
+	n.client.log(ctx).Info("successfully set UPnP configuration")
 	return nil
 }
 
 // TestEmail sends a test email
 func (n *NetworkAPI) TestEmail(ctx context.Context) error {
-	n.client.logger.Info("testing email configuration")
+	return n.testEmail(ctx, nil)
+}
+
+// testEmail sends a test email, with the configuration in param if it is
+// not nil
+func (n *NetworkAPI) testEmail(ctx context.Context, param interface{}) error {
+	n.client.log(ctx).Info("testing email configuration")
 
 	req := []Request{{
-		Cmd: "TestEmail",
+		Cmd:   "TestEmail",
+		Param: param,
 	}}
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to test email: %v", err)
+		n.client.log(ctx).Error("failed to test email: %v", err)
 		return fmt.Errorf("TestEmail request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from TestEmail")
-		n.client.logger.Error("failed to test email: %v", err)
+		n.client.log(ctx).Error("failed to test email: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to test email: %v", apiErr)
+		n.client.log(ctx).Error("failed to test email: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully tested email configuration")
+	n.client.log(ctx).Info("successfully tested email configuration")
 	return nil
 }
 
 // TestFtp tests FTP connection
 func (n *NetworkAPI) TestFtp(ctx context.Context) error {
-	n.client.logger.Info("testing FTP configuration")
+	n.client.log(ctx).Info("testing FTP configuration")
 
 	req := []Request{{
 		Cmd: "TestFtp",
@@ -927,22 +1170,22 @@ func (n *NetworkAPI) TestFtp(ctx context.Context) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to test FTP: %v", err)
+		n.client.log(ctx).Error("failed to test FTP: %v", err)
 		return fmt.Errorf("TestFtp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from TestFtp")
-		n.client.logger.Error("failed to test FTP: %v", err)
+		n.client.log(ctx).Error("failed to test FTP: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to test FTP: %v", apiErr)
+		n.client.log(ctx).Error("failed to test FTP: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully tested FTP configuration")
+	n.client.log(ctx).Info("successfully tested FTP configuration")
 	return nil
 }
 
@@ -955,7 +1198,7 @@ type WifiNetwork struct {
 
 // ScanWifi scans for available WiFi networks
 func (n *NetworkAPI) ScanWifi(ctx context.Context) ([]WifiNetwork, error) {
-	n.client.logger.Info("scanning for WiFi networks")
+	n.client.log(ctx).Info("scanning for WiFi networks")
 
 	req := []Request{{
 		Cmd: "ScanWifi",
@@ -963,28 +1206,28 @@ func (n *NetworkAPI) ScanWifi(ctx context.Context) ([]WifiNetwork, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to scan WiFi networks: %v", err)
+		n.client.log(ctx).Error("failed to scan WiFi networks: %v", err)
 		return nil, fmt.Errorf("ScanWifi request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from ScanWifi")
-		n.client.logger.Error("failed to scan WiFi networks: %v", err)
+		n.client.log(ctx).Error("failed to scan WiFi networks: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to scan WiFi networks: %v", err)
+		n.client.log(ctx).Error("failed to scan WiFi networks: %v", err)
 		return nil, err
 	}
 
 	var networks []WifiNetwork
-	if err := json.Unmarshal(resp[0].Value, &networks); err != nil {
-		n.client.logger.Error("failed to parse WiFi scan response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &networks); err != nil {
+		n.client.log(ctx).Error("failed to parse WiFi scan response: %v", err)
 		return nil, fmt.Errorf("failed to parse ScanWifi response: %w", err)
 	}
 
-	n.client.logger.Info("successfully scanned WiFi networks: found %d networks", len(networks))
+	n.client.log(ctx).Info("successfully scanned WiFi networks: found %d networks", len(networks))
 	return networks, nil
 }
 
@@ -995,7 +1238,7 @@ type WifiSignal struct {
 
 // GetWifiSignal gets current WiFi signal strength
 func (n *NetworkAPI) GetWifiSignal(ctx context.Context) (*WifiSignal, error) {
-	n.client.logger.Debug("getting WiFi signal strength")
+	n.client.log(ctx).Debug("getting WiFi signal strength")
 
 	req := []Request{{
 		Cmd: "GetWifiSignal",
@@ -1003,34 +1246,34 @@ func (n *NetworkAPI) GetWifiSignal(ctx context.Context) (*WifiSignal, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get WiFi signal strength: %v", err)
+		n.client.log(ctx).Error("failed to get WiFi signal strength: %v", err)
 		return nil, fmt.Errorf("GetWifiSignal request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetWifiSignal")
-		n.client.logger.Error("failed to get WiFi signal strength: %v", err)
+		n.client.log(ctx).Error("failed to get WiFi signal strength: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get WiFi signal strength: %v", err)
+		n.client.log(ctx).Error("failed to get WiFi signal strength: %v", err)
 		return nil, err
 	}
 
 	var signal WifiSignal
-	if err := json.Unmarshal(resp[0].Value, &signal); err != nil {
-		n.client.logger.Error("failed to parse WiFi signal strength response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &signal); err != nil {
+		n.client.log(ctx).Error("failed to parse WiFi signal strength response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetWifiSignal response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved WiFi signal strength: %d", signal.Signal)
+	n.client.log(ctx).Info("successfully retrieved WiFi signal strength: %d", signal.Signal)
 	return &signal, nil
 }
 
 // GetEmailV20 gets email configuration (v2.0 with enhanced features)
 func (n *NetworkAPI) GetEmailV20(ctx context.Context, channel int) (*Email, error) {
-	n.client.logger.Debug("getting email configuration (v2.0): channel=%d", channel)
+	n.client.log(ctx).Debug("getting email configuration (v2.0): channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetEmailV20",
@@ -1041,34 +1284,34 @@ func (n *NetworkAPI) GetEmailV20(ctx context.Context, channel int) (*Email, erro
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get email configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get email configuration (v2.0): %v", err)
 		return nil, fmt.Errorf("GetEmailV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetEmailV20")
-		n.client.logger.Error("failed to get email configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get email configuration (v2.0): %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get email configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get email configuration (v2.0): %v", err)
 		return nil, err
 	}
 
 	var value EmailValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse email configuration (v2.0) response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse email configuration (v2.0) response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetEmailV20 response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved email configuration (v2.0): server=%s", value.Email.SMTPServer)
+	n.client.log(ctx).Info("successfully retrieved email configuration (v2.0): server=%s", value.Email.SMTPServer)
 	return &value.Email, nil
 }
 
 // SetEmailV20 sets email configuration (v2.0 with enhanced features)
 func (n *NetworkAPI) SetEmailV20(ctx context.Context, channel int, email Email) error {
-	n.client.logger.Info("setting email configuration (v2.0): channel=%d server=%s", channel, email.SMTPServer)
+	n.client.log(ctx).Info("setting email configuration (v2.0): channel=%d server=%s", channel, email.SMTPServer)
 
 	req := []Request{{
 		Cmd: "SetEmailV20",
@@ -1079,28 +1322,28 @@ func (n *NetworkAPI) SetEmailV20(ctx context.Context, channel int, email Email)
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set email configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to set email configuration (v2.0): %v", err)
 		return fmt.Errorf("SetEmailV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetEmailV20")
-		n.client.logger.Error("failed to set email configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to set email configuration (v2.0): %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set email configuration (v2.0): %v", apiErr)
+		n.client.log(ctx).Error("failed to set email configuration (v2.0): %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set email configuration (v2.0)")
+	n.client.log(ctx).Info("successfully set email configuration (v2.0)")
 	return nil
 }
 
 // GetFtpV20 gets FTP configuration (v2.0 with enhanced features)
 func (n *NetworkAPI) GetFtpV20(ctx context.Context, channel int) (*Ftp, error) {
-	n.client.logger.Debug("getting FTP configuration (v2.0): channel=%d", channel)
+	n.client.log(ctx).Debug("getting FTP configuration (v2.0): channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetFtpV20",
@@ -1111,34 +1354,34 @@ func (n *NetworkAPI) GetFtpV20(ctx context.Context, channel int) (*Ftp, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get FTP configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get FTP configuration (v2.0): %v", err)
 		return nil, fmt.Errorf("GetFtpV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetFtpV20")
-		n.client.logger.Error("failed to get FTP configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get FTP configuration (v2.0): %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get FTP configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get FTP configuration (v2.0): %v", err)
 		return nil, err
 	}
 
 	var value FtpValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse FTP configuration (v2.0) response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse FTP configuration (v2.0) response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetFtpV20 response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved FTP configuration (v2.0): server=%s", value.Ftp.Server)
+	n.client.log(ctx).Info("successfully retrieved FTP configuration (v2.0): server=%s", value.Ftp.Server)
 	return &value.Ftp, nil
 }
 
 // SetFtpV20 sets FTP configuration (v2.0 with enhanced features)
 func (n *NetworkAPI) SetFtpV20(ctx context.Context, channel int, ftp Ftp) error {
-	n.client.logger.Info("setting FTP configuration (v2.0): channel=%d server=%s", channel, ftp.Server)
+	n.client.log(ctx).Info("setting FTP configuration (v2.0): channel=%d server=%s", channel, ftp.Server)
 
 	req := []Request{{
 		Cmd: "SetFtpV20",
@@ -1149,28 +1392,28 @@ func (n *NetworkAPI) SetFtpV20(ctx context.Context, channel int, ftp Ftp) error
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set FTP configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to set FTP configuration (v2.0): %v", err)
 		return fmt.Errorf("SetFtpV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetFtpV20")
-		n.client.logger.Error("failed to set FTP configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to set FTP configuration (v2.0): %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set FTP configuration (v2.0): %v", apiErr)
+		n.client.log(ctx).Error("failed to set FTP configuration (v2.0): %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set FTP configuration (v2.0)")
+	n.client.log(ctx).Info("successfully set FTP configuration (v2.0)")
 	return nil
 }
 
 // GetPushV20 gets push notification configuration (v2.0 with enhanced features)
 func (n *NetworkAPI) GetPushV20(ctx context.Context, channel int) (*Push, error) {
-	n.client.logger.Debug("getting push notification configuration (v2.0): channel=%d", channel)
+	n.client.log(ctx).Debug("getting push notification configuration (v2.0): channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetPushV20",
@@ -1181,34 +1424,34 @@ func (n *NetworkAPI) GetPushV20(ctx context.Context, channel int) (*Push, error)
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get push notification configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get push notification configuration (v2.0): %v", err)
 		return nil, fmt.Errorf("GetPushV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetPushV20")
-		n.client.logger.Error("failed to get push notification configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get push notification configuration (v2.0): %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get push notification configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to get push notification configuration (v2.0): %v", err)
 		return nil, err
 	}
 
 	var value PushValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse push notification configuration (v2.0) response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse push notification configuration (v2.0) response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetPushV20 response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved push notification configuration (v2.0)")
+	n.client.log(ctx).Info("successfully retrieved push notification configuration (v2.0)")
 	return &value.Push, nil
 }
 
 // SetPushV20 sets push notification configuration (v2.0 with enhanced features)
 func (n *NetworkAPI) SetPushV20(ctx context.Context, channel int, push Push) error {
-	n.client.logger.Info("setting push notification configuration (v2.0): channel=%d", channel)
+	n.client.log(ctx).Info("setting push notification configuration (v2.0): channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "SetPushV20",
@@ -1219,29 +1462,29 @@ func (n *NetworkAPI) SetPushV20(ctx context.Context, channel int, push Push) err
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set push notification configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to set push notification configuration (v2.0): %v", err)
 		return fmt.Errorf("SetPushV20 request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetPushV20")
-		n.client.logger.Error("failed to set push notification configuration (v2.0): %v", err)
+		n.client.log(ctx).Error("failed to set push notification configuration (v2.0): %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set push notification configuration (v2.0): %v", apiErr)
+		n.client.log(ctx).Error("failed to set push notification configuration (v2.0): %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set push notification configuration (v2.0)")
+	n.client.log(ctx).Info("successfully set push notification configuration (v2.0)")
 	return nil
 }
 
 // PushCfg represents push configuration details
 type PushCfg struct {
-	Enable int    `json:"enable"` // 0=disabled, 1=enabled
-	Token  string `json:"token"`  // Push token
+	Enable BoolInt `json:"enable"` // 0=disabled, 1=enabled
+	Token  string  `json:"token"`  // Push token
 }
 
 // PushCfgValue represents the response value for GetPushCfg
@@ -1251,7 +1494,7 @@ type PushCfgValue struct {
 
 // GetPushCfg gets push configuration details
 func (n *NetworkAPI) GetPushCfg(ctx context.Context) (*PushCfg, error) {
-	n.client.logger.Debug("getting push configuration details")
+	n.client.log(ctx).Debug("getting push configuration details")
 
 	req := []Request{{
 		Cmd: "GetPushCfg",
@@ -1259,34 +1502,34 @@ func (n *NetworkAPI) GetPushCfg(ctx context.Context) (*PushCfg, error) {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get push configuration details: %v", err)
+		n.client.log(ctx).Error("failed to get push configuration details: %v", err)
 		return nil, fmt.Errorf("GetPushCfg request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetPushCfg")
-		n.client.logger.Error("failed to get push configuration details: %v", err)
+		n.client.log(ctx).Error("failed to get push configuration details: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get push configuration details: %v", err)
+		n.client.log(ctx).Error("failed to get push configuration details: %v", err)
 		return nil, err
 	}
 
 	var value PushCfgValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse push configuration details response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse push configuration details response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetPushCfg response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved push configuration details: enable=%d", value.PushCfg.Enable)
+	n.client.log(ctx).Info("successfully retrieved push configuration details: enable=%d", value.PushCfg.Enable)
 	return &value.PushCfg, nil
 }
 
 // SetPushCfg sets push configuration details
 func (n *NetworkAPI) SetPushCfg(ctx context.Context, pushCfg PushCfg) error {
-	n.client.logger.Info("setting push configuration details: enable=%d", pushCfg.Enable)
+	n.client.log(ctx).Info("setting push configuration details: enable=%d", pushCfg.Enable)
 
 	req := []Request{{
 		Cmd: "SetPushCfg",
@@ -1297,28 +1540,28 @@ func (n *NetworkAPI) SetPushCfg(ctx context.Context, pushCfg PushCfg) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to set push configuration details: %v", err)
+		n.client.log(ctx).Error("failed to set push configuration details: %v", err)
 		return fmt.Errorf("SetPushCfg request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetPushCfg")
-		n.client.logger.Error("failed to set push configuration details: %v", err)
+		n.client.log(ctx).Error("failed to set push configuration details: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to set push configuration details: %v", apiErr)
+		n.client.log(ctx).Error("failed to set push configuration details: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully set push configuration details")
+	n.client.log(ctx).Info("successfully set push configuration details")
 	return nil
 }
 
 // TestWifi tests WiFi connection
 func (n *NetworkAPI) TestWifi(ctx context.Context) error {
-	n.client.logger.Info("testing WiFi configuration")
+	n.client.log(ctx).Info("testing WiFi configuration")
 
 	req := []Request{{
 		Cmd: "TestWifi",
@@ -1326,22 +1569,22 @@ func (n *NetworkAPI) TestWifi(ctx context.Context) error {
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to test WiFi: %v", err)
+		n.client.log(ctx).Error("failed to test WiFi: %v", err)
 		return fmt.Errorf("TestWifi request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from TestWifi")
-		n.client.logger.Error("failed to test WiFi: %v", err)
+		n.client.log(ctx).Error("failed to test WiFi: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		n.client.logger.Error("failed to test WiFi: %v", apiErr)
+		n.client.log(ctx).Error("failed to test WiFi: %v", apiErr)
 		return apiErr
 	}
 
-	n.client.logger.Info("successfully tested WiFi configuration")
+	n.client.log(ctx).Info("successfully tested WiFi configuration")
 	return nil
 }
 
@@ -1359,7 +1602,7 @@ type RtspUrlValue struct {
 
 // GetRtspUrl gets RTSP streaming URL from the camera
 func (n *NetworkAPI) GetRtspUrl(ctx context.Context, channel int) (*RtspUrl, error) {
-	n.client.logger.Debug("getting RTSP URL: channel=%d", channel)
+	n.client.log(ctx).Debug("getting RTSP URL: channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetRtspUrl",
@@ -1370,27 +1613,577 @@ func (n *NetworkAPI) GetRtspUrl(ctx context.Context, channel int) (*RtspUrl, err
 
 	var resp []Response
 	if err := n.client.do(ctx, req, &resp); err != nil {
-		n.client.logger.Error("failed to get RTSP URL: %v", err)
+		n.client.log(ctx).Error("failed to get RTSP URL: %v", err)
 		return nil, fmt.Errorf("GetRtspUrl request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetRtspUrl")
-		n.client.logger.Error("failed to get RTSP URL: %v", err)
+		n.client.log(ctx).Error("failed to get RTSP URL: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		n.client.logger.Error("failed to get RTSP URL: %v", err)
+		n.client.log(ctx).Error("failed to get RTSP URL: %v", err)
 		return nil, err
 	}
 
 	var value RtspUrlValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		n.client.logger.Error("failed to parse RTSP URL response: %v", err)
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse RTSP URL response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetRtspUrl response: %w", err)
 	}
 
-	n.client.logger.Info("successfully retrieved RTSP URL: channel=%d", value.RtspUrl.Channel)
+	n.client.log(ctx).Info("successfully retrieved RTSP URL: channel=%d", value.RtspUrl.Channel)
 	return &value.RtspUrl, nil
 }
+
+// IP filter modes
+const (
+	IPFilterAllow = "allow" // Only listed addresses may connect
+	IPFilterDeny  = "deny"  // Listed addresses are blocked
+)
+
+// IPFilter represents the IP access filter (allow/deny list) configuration.
+// GetIpFilter and SetIpFilter are not in the API guide; firmware without
+// them answers with an error for which IsNotSupported is true.
+type IPFilter struct {
+	Enable BoolInt  `json:"enable"` // 0=disabled, 1=enabled
+	Mode   string   `json:"mode"`   // IPFilterAllow or IPFilterDeny
+	IPList []string `json:"ipList"` // IP addresses or CIDR subnets, e.g. "192.168.1.0/24"
+}
+
+// IPFilterValue represents the response value for GetIpFilter
+type IPFilterValue struct {
+	IPFilter IPFilter `json:"IpFilter"`
+}
+
+// Validate checks the filter mode and that every entry is an IP address or
+// CIDR subnet
+func (f *IPFilter) Validate() error {
+	if f.Mode != IPFilterAllow && f.Mode != IPFilterDeny {
+		return fmt.Errorf("invalid IP filter mode %q (allowed: %s, %s)", f.Mode, IPFilterAllow, IPFilterDeny)
+	}
+	for _, entry := range f.IPList {
+		if net.ParseIP(entry) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err == nil {
+			continue
+		}
+		return fmt.Errorf("invalid IP filter entry %q: must be an IP address or CIDR subnet", entry)
+	}
+	if f.Enable.Bool() && f.Mode == IPFilterAllow && len(f.IPList) == 0 {
+		return fmt.Errorf("an enabled allow list must contain at least one entry")
+	}
+	return nil
+}
+
+// GetIPFilter gets the IP access filter configuration. The command is not
+// in the API guide; see IPFilter.
+func (n *NetworkAPI) GetIPFilter(ctx context.Context) (*IPFilter, error) {
+	n.client.log(ctx).Debug("getting IP filter configuration")
+
+	req := []Request{{
+		Cmd:    "GetIpFilter",
+		Action: 0,
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to get IP filter configuration: %v", err)
+		return nil, fmt.Errorf("GetIpFilter request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetIpFilter")
+		n.client.log(ctx).Error("failed to get IP filter configuration: %v", err)
+		return nil, err
+	}
+
+	if err := resp[0].ToAPIError(); err != nil {
+		n.client.log(ctx).Error("failed to get IP filter configuration: %v", err)
+		return nil, err
+	}
+
+	var value IPFilterValue
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse IP filter configuration response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetIpFilter response: %w", err)
+	}
+
+	n.client.log(ctx).Info("successfully retrieved IP filter configuration: enable=%d mode=%s entries=%d",
+		value.IPFilter.Enable, value.IPFilter.Mode, len(value.IPFilter.IPList))
+	return &value.IPFilter, nil
+}
+
+// SetIPFilter sets the IP access filter configuration. The command is not
+// in the API guide; see IPFilter.
+//
+// The filter is validated before it is sent. Note that an allow list that
+// does not include the address the client connects from locks the client out.
+func (n *NetworkAPI) SetIPFilter(ctx context.Context, filter IPFilter) error {
+	n.client.log(ctx).Info("setting IP filter configuration: enable=%d mode=%s entries=%d",
+		filter.Enable, filter.Mode, len(filter.IPList))
+
+	if err := filter.Validate(); err != nil {
+		n.client.log(ctx).Error("failed to set IP filter configuration: %v", err)
+		return err
+	}
+
+	req := []Request{{
+		Cmd: "SetIpFilter",
+		Param: map[string]interface{}{
+			"IpFilter": filter,
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to set IP filter configuration: %v", err)
+		return fmt.Errorf("SetIpFilter request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetIpFilter")
+		n.client.log(ctx).Error("failed to set IP filter configuration: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		n.client.log(ctx).Error("failed to set IP filter configuration: %v", apiErr)
+		return apiErr
+	}
+
+	n.client.log(ctx).Info("successfully set IP filter configuration")
+	return nil
+}
+
+// RTSP authentication modes
+const (
+	RTSPAuthBasic  = "basic"  // HTTP Basic authentication
+	RTSPAuthDigest = "digest" // HTTP Digest authentication
+	RTSPAuthNone   = "none"   // No authentication
+)
+
+// RtspAuth represents the RTSP authentication mode configuration.
+// GetRtspAuth and SetRtspAuth are not in the API guide; firmware without
+// them answers with an error for which IsNotSupported is true.
+type RtspAuth struct {
+	Mode string `json:"mode"` // RTSPAuthBasic, RTSPAuthDigest or RTSPAuthNone
+}
+
+// RtspAuthValue represents the response value for GetRtspAuth
+type RtspAuthValue struct {
+	RtspAuth RtspAuth `json:"RtspAuth"`
+}
+
+// GetRtspAuth gets the RTSP authentication mode. The command is not in the
+// API guide; see RtspAuth.
+func (n *NetworkAPI) GetRtspAuth(ctx context.Context) (*RtspAuth, error) {
+	n.client.log(ctx).Debug("getting RTSP authentication mode")
+
+	req := []Request{{
+		Cmd:    "GetRtspAuth",
+		Action: 0,
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to get RTSP authentication mode: %v", err)
+		return nil, fmt.Errorf("GetRtspAuth request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetRtspAuth")
+		n.client.log(ctx).Error("failed to get RTSP authentication mode: %v", err)
+		return nil, err
+	}
+
+	if err := resp[0].ToAPIError(); err != nil {
+		n.client.log(ctx).Error("failed to get RTSP authentication mode: %v", err)
+		return nil, err
+	}
+
+	var value RtspAuthValue
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse RTSP authentication mode response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetRtspAuth response: %w", err)
+	}
+
+	n.client.log(ctx).Info("successfully retrieved RTSP authentication mode: mode=%s", value.RtspAuth.Mode)
+	return &value.RtspAuth, nil
+}
+
+// SetRtspAuth sets the RTSP authentication mode. The command is not in the
+// API guide; see RtspAuth.
+func (n *NetworkAPI) SetRtspAuth(ctx context.Context, mode string) error {
+	n.client.log(ctx).Info("setting RTSP authentication mode: mode=%s", mode)
+
+	switch mode {
+	case RTSPAuthBasic, RTSPAuthDigest, RTSPAuthNone:
+	default:
+		err := fmt.Errorf("invalid RTSP authentication mode %q (allowed: %s, %s, %s)",
+			mode, RTSPAuthBasic, RTSPAuthDigest, RTSPAuthNone)
+		n.client.log(ctx).Error("failed to set RTSP authentication mode: %v", err)
+		return err
+	}
+
+	req := []Request{{
+		Cmd: "SetRtspAuth",
+		Param: map[string]interface{}{
+			"RtspAuth": RtspAuth{Mode: mode},
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to set RTSP authentication mode: %v", err)
+		return fmt.Errorf("SetRtspAuth request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetRtspAuth")
+		n.client.log(ctx).Error("failed to set RTSP authentication mode: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		n.client.log(ctx).Error("failed to set RTSP authentication mode: %v", apiErr)
+		return apiErr
+	}
+
+	n.client.log(ctx).Info("successfully set RTSP authentication mode")
+	return nil
+}
+
+// Multicast is the RTSP multicast configuration of models that can send
+// their streams to a multicast group. The commands are not part of the
+// public API guide; other models reject them as not supported.
+type Multicast struct {
+	Enable BoolInt `json:"enable"` // 0=disabled, 1=enabled
+	IP     string  `json:"ip"`     // Multicast group address, e.g. "239.0.0.1"
+	Port   int     `json:"port"`   // First UDP port of the group
+}
+
+// MulticastValue represents the response value for GetMulticast
+type MulticastValue struct {
+	Multicast Multicast `json:"Multicast"`
+}
+
+// Validate checks the group address and port of an enabled configuration
+func (m *Multicast) Validate() error {
+	if !m.Enable.Bool() {
+		return nil
+	}
+	if ip := net.ParseIP(m.IP); ip == nil || !ip.IsMulticast() {
+		return fmt.Errorf("invalid multicast address %q", m.IP)
+	}
+	if m.Port < 1 || m.Port > 65535 {
+		return fmt.Errorf("multicast port must be between 1 and 65535")
+	}
+	return nil
+}
+
+// GetMulticast gets the RTSP multicast configuration
+func (n *NetworkAPI) GetMulticast(ctx context.Context) (*Multicast, error) {
+	n.client.log(ctx).Debug("getting multicast configuration")
+
+	req := []Request{{
+		Cmd:    "GetMulticast",
+		Action: 0,
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to get multicast configuration: %v", err)
+		return nil, fmt.Errorf("GetMulticast request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetMulticast")
+		n.client.log(ctx).Error("failed to get multicast configuration: %v", err)
+		return nil, err
+	}
+
+	if err := resp[0].ToAPIError(); err != nil {
+		n.client.log(ctx).Error("failed to get multicast configuration: %v", err)
+		return nil, err
+	}
+
+	var value MulticastValue
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse multicast configuration response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetMulticast response: %w", err)
+	}
+
+	n.client.log(ctx).Info("successfully retrieved multicast configuration")
+	return &value.Multicast, nil
+}
+
+// SetMulticast sets the RTSP multicast configuration
+func (n *NetworkAPI) SetMulticast(ctx context.Context, multicast Multicast) error {
+	if err := multicast.Validate(); err != nil {
+		return err
+	}
+
+	n.client.log(ctx).Info("setting multicast configuration: enable=%d ip=%s port=%d", multicast.Enable, multicast.IP, multicast.Port)
+
+	req := []Request{{
+		Cmd: "SetMulticast",
+		Param: map[string]interface{}{
+			"Multicast": multicast,
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to set multicast configuration: %v", err)
+		return fmt.Errorf("SetMulticast request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetMulticast")
+		n.client.log(ctx).Error("failed to set multicast configuration: %v", err)
+		return err
+	}
+
+	if err := resp[0].ToAPIError(); err != nil {
+		n.client.log(ctx).Error("failed to set multicast configuration: %v", err)
+		return err
+	}
+
+	n.client.log(ctx).Info("successfully set multicast configuration")
+	return nil
+}
+
+// RtmpPush represents the RTMP push (re-stream) configuration of a channel,
+// which makes the camera publish its stream to an external RTMP server.
+// Only some firmware supports it; others answer ErrCodeNotSupported.
+type RtmpPush struct {
+	Channel    int     `json:"channel"`    // Channel number
+	Enable     BoolInt `json:"enable"`     // 0=disabled, 1=enabled
+	URL        string  `json:"url"`        // Publish URL including stream key, e.g. "rtmp://relay.example.com/live/key"
+	StreamType string  `json:"streamType"` // "main" or "sub"
+}
+
+// RtmpPushValue represents the response value for GetRtmpPush
+type RtmpPushValue struct {
+	RtmpPush RtmpPush `json:"RtmpPush"`
+}
+
+// Validate checks the publish URL and stream type
+func (p *RtmpPush) Validate() error {
+	if p.Enable.Bool() && !strings.HasPrefix(p.URL, "rtmp://") && !strings.HasPrefix(p.URL, "rtmps://") {
+		return fmt.Errorf("invalid RTMP push URL %q: must start with rtmp:// or rtmps://", p.URL)
+	}
+	if p.StreamType != "" && p.StreamType != string(StreamMain) && p.StreamType != string(StreamSub) {
+		return fmt.Errorf("invalid RTMP push stream type %q (allowed: %s, %s)", p.StreamType, StreamMain, StreamSub)
+	}
+	return nil
+}
+
+// GetRtmpPush gets the RTMP push configuration of a channel
+func (n *NetworkAPI) GetRtmpPush(ctx context.Context, channel int) (*RtmpPush, error) {
+	n.client.log(ctx).Debug("getting RTMP push configuration: channel=%d", channel)
+
+	req := []Request{{
+		Cmd:    "GetRtmpPush",
+		Action: 0,
+		Param: map[string]interface{}{
+			"channel": channel,
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to get RTMP push configuration: %v", err)
+		return nil, fmt.Errorf("GetRtmpPush request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetRtmpPush")
+		n.client.log(ctx).Error("failed to get RTMP push configuration: %v", err)
+		return nil, err
+	}
+
+	if err := resp[0].ToAPIError(); err != nil {
+		n.client.log(ctx).Error("failed to get RTMP push configuration: %v", err)
+		return nil, err
+	}
+
+	var value RtmpPushValue
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse RTMP push configuration response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetRtmpPush response: %w", err)
+	}
+
+	n.client.log(ctx).Info("successfully retrieved RTMP push configuration: enable=%d stream=%s",
+		value.RtmpPush.Enable, value.RtmpPush.StreamType)
+	return &value.RtmpPush, nil
+}
+
+// SetRtmpPush sets the RTMP push configuration of a channel
+func (n *NetworkAPI) SetRtmpPush(ctx context.Context, push RtmpPush) error {
+	n.client.log(ctx).Info("setting RTMP push configuration: channel=%d enable=%d stream=%s",
+		push.Channel, push.Enable, push.StreamType)
+
+	if err := push.Validate(); err != nil {
+		n.client.log(ctx).Error("failed to set RTMP push configuration: %v", err)
+		return err
+	}
+
+	req := []Request{{
+		Cmd: "SetRtmpPush",
+		Param: map[string]interface{}{
+			"RtmpPush": push,
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to set RTMP push configuration: %v", err)
+		return fmt.Errorf("SetRtmpPush request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetRtmpPush")
+		n.client.log(ctx).Error("failed to set RTMP push configuration: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		n.client.log(ctx).Error("failed to set RTMP push configuration: %v", apiErr)
+		return apiErr
+	}
+
+	n.client.log(ctx).Info("successfully set RTMP push configuration")
+	return nil
+}
+
+// Gb28181 represents the GB/T 28181 platform registration configuration,
+// used by cameras deployed on Chinese video surveillance platforms. Only
+// some firmware supports it; others answer ErrCodeNotSupported.
+type Gb28181 struct {
+	Enable       BoolInt `json:"enable"`       // 0=disabled, 1=enabled
+	ServerID     string  `json:"serverId"`     // 20-digit SIP server ID
+	ServerDomain string  `json:"serverDomain"` // SIP server domain (usually the first 10 digits of ServerID)
+	ServerIP     string  `json:"serverIp"`     // SIP server address
+	ServerPort   int     `json:"serverPort"`   // SIP server port (default: 5060)
+	DeviceID     string  `json:"deviceId"`     // 20-digit device ID assigned by the platform
+	ChannelID    string  `json:"channelId"`    // 20-digit video channel ID (optional)
+	Password     string  `json:"password"`     // SIP registration password
+	Expires      int     `json:"expires"`      // Registration validity in seconds
+	Heartbeat    int     `json:"heartbeat"`    // Keepalive interval in seconds
+}
+
+// Gb28181Value represents the response value for GetGb28181
+type Gb28181Value struct {
+	Gb28181 Gb28181 `json:"Gb28181"`
+}
+
+// Validate checks the GB28181 IDs and server address of an enabled
+// configuration
+func (g *Gb28181) Validate() error {
+	if !g.Enable.Bool() {
+		return nil
+	}
+	ids := []struct {
+		name, id string
+		optional bool
+	}{
+		{"server ID", g.ServerID, false},
+		{"device ID", g.DeviceID, false},
+		{"channel ID", g.ChannelID, true},
+	}
+	for _, f := range ids {
+		if f.id == "" && f.optional {
+			continue
+		}
+		if len(f.id) != 20 || strings.Trim(f.id, "0123456789") != "" {
+			return fmt.Errorf("invalid GB28181 %s %q: must be 20 digits", f.name, f.id)
+		}
+	}
+	if g.ServerIP == "" {
+		return fmt.Errorf("GB28181 server IP is required")
+	}
+	if g.ServerPort < 1 || g.ServerPort > 65535 {
+		return fmt.Errorf("invalid GB28181 server port %d", g.ServerPort)
+	}
+	return nil
+}
+
+// GetGb28181 gets the GB28181 platform configuration
+func (n *NetworkAPI) GetGb28181(ctx context.Context) (*Gb28181, error) {
+	n.client.log(ctx).Debug("getting GB28181 configuration")
+
+	req := []Request{{
+		Cmd:    "GetGb28181",
+		Action: 0,
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to get GB28181 configuration: %v", err)
+		return nil, fmt.Errorf("GetGb28181 request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetGb28181")
+		n.client.log(ctx).Error("failed to get GB28181 configuration: %v", err)
+		return nil, err
+	}
+
+	if err := resp[0].ToAPIError(); err != nil {
+		n.client.log(ctx).Error("failed to get GB28181 configuration: %v", err)
+		return nil, err
+	}
+
+	var value Gb28181Value
+	if err := n.client.unmarshal(resp[0].Value, &value); err != nil {
+		n.client.log(ctx).Error("failed to parse GB28181 configuration response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetGb28181 response: %w", err)
+	}
+
+	n.client.log(ctx).Info("successfully retrieved GB28181 configuration: enable=%d server=%s:%d",
+		value.Gb28181.Enable, value.Gb28181.ServerIP, value.Gb28181.ServerPort)
+	return &value.Gb28181, nil
+}
+
+// SetGb28181 sets the GB28181 platform configuration
+func (n *NetworkAPI) SetGb28181(ctx context.Context, cfg Gb28181) error {
+	n.client.log(ctx).Info("setting GB28181 configuration: enable=%d server=%s:%d",
+		cfg.Enable, cfg.ServerIP, cfg.ServerPort)
+
+	if err := cfg.Validate(); err != nil {
+		n.client.log(ctx).Error("failed to set GB28181 configuration: %v", err)
+		return err
+	}
+
+	req := []Request{{
+		Cmd: "SetGb28181",
+		Param: map[string]interface{}{
+			"Gb28181": cfg,
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		n.client.log(ctx).Error("failed to set GB28181 configuration: %v", err)
+		return fmt.Errorf("SetGb28181 request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetGb28181")
+		n.client.log(ctx).Error("failed to set GB28181 configuration: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		n.client.log(ctx).Error("failed to set GB28181 configuration: %v", apiErr)
+		return apiErr
+	}
+
+	n.client.log(ctx).Info("successfully set GB28181 configuration")
+	return nil
+}
@@ -1,9 +1,16 @@
 package reolink
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNetworkAPI_GetNetPort(t *testing.T) {
@@ -60,6 +67,192 @@ func TestNetworkAPI_SetNetPort(t *testing.T) {
 	}
 }
 
+// newDirectPortServer starts a mock camera whose configured HTTP port is
+// the one the returned client connects to, or a forwarded port if forwarded
+func newDirectPortServer(t *testing.T, forwarded bool, opts ...Option) (*cmdServer, *Client) {
+	t.Helper()
+	server := newCmdServer(t, nil)
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	if forwarded {
+		port = 80
+	}
+	server.set("GetNetPort", fmt.Sprintf(`{"NetPort": {"httpEnable": 1, "httpPort": %d, "httpsEnable": 1, "httpsPort": 443}}`, port))
+	server.set("SetNetPort", "")
+	return server, NewClient(u.Host, opts...)
+}
+
+func TestNetworkAPI_SetNetPort_FollowsPort(t *testing.T) {
+	_, client := newDirectPortServer(t, false)
+
+	err := client.Network.SetNetPort(t.Context(), NetPort{HTTPEnable: 1, HTTPPort: 8081, HTTPSEnable: 1, HTTPSPort: 443})
+	if err != nil {
+		t.Fatalf("SetNetPort failed: %v", err)
+	}
+	if client.host != "127.0.0.1:8081" || client.baseURL != "http://127.0.0.1:8081/cgi-bin/api.cgi" {
+		t.Errorf("client not moved to the new port: host=%s baseURL=%s", client.host, client.baseURL)
+	}
+}
+
+func TestNetworkAPI_SetNetPort_ForwardedPort(t *testing.T) {
+	_, client := newDirectPortServer(t, true)
+	host := client.host
+
+	err := client.Network.SetNetPort(t.Context(), NetPort{HTTPEnable: 1, HTTPPort: 8081, HTTPSEnable: 1, HTTPSPort: 443})
+	if err != nil {
+		t.Fatalf("SetNetPort failed: %v", err)
+	}
+	if client.host != host {
+		t.Errorf("forwarded host changed to %s", client.host)
+	}
+}
+
+func TestNetworkAPI_SetNetPort_SwitchesToHTTPS(t *testing.T) {
+	_, client := newDirectPortServer(t, false)
+
+	err := client.Network.SetNetPort(t.Context(), NetPort{HTTPEnable: 0, HTTPPort: 80, HTTPSEnable: 1, HTTPSPort: 8443})
+	if err != nil {
+		t.Fatalf("SetNetPort failed: %v", err)
+	}
+	if !client.useHTTPS || client.baseURL != "https://127.0.0.1:8443/cgi-bin/api.cgi" {
+		t.Errorf("client not switched to HTTPS: %s", client.baseURL)
+	}
+}
+
+func TestNetworkAPI_SetNetPort_Unaffected(t *testing.T) {
+	server, client := newDirectPortServer(t, false)
+	_, port := client.activeTransport()
+
+	err := client.Network.SetNetPort(t.Context(), NetPort{HTTPEnable: 1, HTTPPort: port, HTTPSEnable: 1, HTTPSPort: 8443})
+	if err != nil {
+		t.Fatalf("SetNetPort failed: %v", err)
+	}
+	if n := server.callCount("GetNetPort"); n != 0 {
+		t.Errorf("expected no GetNetPort for a change that keeps the client's port, got %d", n)
+	}
+}
+
+func TestNetworkAPI_SetNetPort_MovesHostState(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore failed: %v", err)
+	}
+	cache := NewMemoryCache()
+	server, client := newDirectPortServer(t, false, WithCredentials("admin", "secret"), WithStateStore(store), WithCache(cache, nil))
+	server.set("GetDevInfo", `{"DevInfo": {"model": "RLC-811A"}}`)
+	oldKey := client.stateKey()
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := store.Save(oldKey, &HostState{Token: "stored-token", TokenExpiry: expiry}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := client.System.GetDeviceInfo(t.Context()); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+
+	err = client.Network.SetNetPort(t.Context(), NetPort{HTTPEnable: 1, HTTPPort: 8081, HTTPSEnable: 1, HTTPSPort: 443})
+	if err != nil {
+		t.Fatalf("SetNetPort failed: %v", err)
+	}
+	if state, _ := store.Load("admin@127.0.0.1:8081"); state.Token != "stored-token" || !state.TokenExpiry.Equal(expiry) {
+		t.Errorf("state not moved to the new host: %+v", state)
+	}
+	if state, _ := store.Load(oldKey); state.Token != "" {
+		t.Errorf("state left under the old host: %+v", state)
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for key := range cache.entries {
+		if strings.HasPrefix(key, oldKey+"|") {
+			t.Errorf("cache entry of the old host left behind: %s", key)
+		}
+	}
+}
+
+func TestNetworkAPI_SetNetPort_ConcurrentRequests(t *testing.T) {
+	server, client := newDirectPortServer(t, false)
+	server.set("GetDevInfo", `{"DevInfo": {"model": "RLC-811A"}}`)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				client.System.GetDeviceInfo(t.Context())
+				client.Streaming.GetFLVURL(StreamMain, 0)
+			}
+		}()
+	}
+	if err := client.Network.SetNetPort(t.Context(), NetPort{HTTPEnable: 1, HTTPPort: 8081, HTTPSEnable: 1, HTTPSPort: 443}); err != nil {
+		t.Errorf("SetNetPort failed: %v", err)
+	}
+	wg.Wait()
+	if client.Host() != "127.0.0.1:8081" {
+		t.Errorf("client not moved to the new port: %s", client.Host())
+	}
+}
+
+func TestNetworkAPI_SetNetPort_Guard(t *testing.T) {
+	server, client := newDirectPortServer(t, false, WithPortGuard(true))
+
+	err := client.Network.SetNetPort(t.Context(), NetPort{HTTPEnable: 0, HTTPSEnable: 1, HTTPSPort: 443})
+	if err == nil {
+		t.Fatal("expected the guard to refuse disabling HTTP")
+	}
+	if n := server.callCount("SetNetPort"); n != 0 {
+		t.Errorf("expected no SetNetPort request, got %d", n)
+	}
+}
+
+func TestNetworkAPI_UpdateNetPort(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"httpEnable": 1, "httpPort": 80, "httpsEnable": 1, "httpsPort": 443, "mediaPort": 9000, "onvifEnable": 1, "onvifPort": 8000, "rtmpEnable": 0, "rtmpPort": 1935, "rtspEnable": 1, "rtspPort": 554}}`,
+		"SetNetPort": "",
+	})
+	client := server.client()
+
+	if err := client.Network.UpdateNetPort(t.Context(), func(p *NetPort) { p.RTMPEnable = 1 }); err != nil {
+		t.Fatalf("UpdateNetPort failed: %v", err)
+	}
+	var param struct{ NetPort NetPort }
+	if err := json.Unmarshal(server.lastParam("SetNetPort"), &param); err != nil {
+		t.Fatal(err)
+	}
+	if p := param.NetPort; p.RTMPEnable != 1 || p.RTSPEnable != 1 || p.RTSPPort != 554 || p.OnvifPort != 8000 {
+		t.Errorf("other ports not kept: %+v", p)
+	}
+
+	// Unchanged: nothing written
+	if err := client.Network.UpdateNetPort(t.Context(), func(p *NetPort) {}); err != nil {
+		t.Fatal(err)
+	}
+	// Conflict: refused
+	if err := client.Network.UpdateNetPort(t.Context(), func(p *NetPort) { p.RTSPPort = 8000 }); err == nil {
+		t.Error("expected error for RTSP on the ONVIF port")
+	}
+	if n := server.callCount("SetNetPort"); n != 1 {
+		t.Errorf("expected 1 SetNetPort request, got %d", n)
+	}
+}
+
+func TestNetPort_Validate(t *testing.T) {
+	tests := []struct {
+		port    NetPort
+		wantErr bool
+	}{
+		{NetPort{HTTPEnable: 1, HTTPPort: 80, MediaPort: 9000}, false},
+		{NetPort{HTTPEnable: 1, HTTPPort: 80, MediaPort: 9000, RTSPEnable: 0, RTSPPort: 80}, false},
+		{NetPort{HTTPEnable: 1, HTTPPort: 80, MediaPort: 9000, RTSPEnable: 1, RTSPPort: 80}, true},
+		{NetPort{HTTPEnable: 1, HTTPPort: 70000, MediaPort: 9000}, true},
+		{NetPort{HTTPEnable: 1, HTTPPort: 80}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.port.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) = %v, wantErr %v", tt.port, err, tt.wantErr)
+		}
+	}
+}
+
 func TestNetworkAPI_GetLocalLink(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -862,3 +1055,189 @@ func TestNetworkAPI_SetUpnp(t *testing.T) {
 		t.Fatalf("SetUpnp failed: %v", err)
 	}
 }
+
+func TestNetworkAPI_GetIPFilter(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetIpFilter": `{"IpFilter": {"enable": 1, "mode": "allow", "ipList": ["192.168.1.0/24", "10.0.0.5"]}}`,
+	})
+	client := server.client()
+
+	filter, err := client.Network.GetIPFilter(t.Context())
+	if err != nil {
+		t.Fatalf("GetIPFilter failed: %v", err)
+	}
+
+	if filter.Enable != 1 || filter.Mode != IPFilterAllow {
+		t.Errorf("unexpected filter: %+v", filter)
+	}
+	if len(filter.IPList) != 2 || filter.IPList[0] != "192.168.1.0/24" {
+		t.Errorf("unexpected IP list: %v", filter.IPList)
+	}
+}
+
+func TestNetworkAPI_SetIPFilter(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"SetIpFilter": "",
+	})
+	client := server.client()
+	ctx := t.Context()
+
+	err := client.Network.SetIPFilter(ctx, IPFilter{Enable: 1, Mode: IPFilterAllow, IPList: []string{"192.168.1.0/24"}})
+	if err != nil {
+		t.Fatalf("SetIPFilter failed: %v", err)
+	}
+
+	invalid := []IPFilter{
+		{Enable: 1, Mode: "block", IPList: []string{"192.168.1.1"}},
+		{Enable: 1, Mode: IPFilterDeny, IPList: []string{"192.168.1.300"}},
+		{Enable: 1, Mode: IPFilterAllow},
+	}
+	for _, f := range invalid {
+		if err := client.Network.SetIPFilter(ctx, f); err == nil {
+			t.Errorf("expected validation error for %+v", f)
+		}
+	}
+
+	if server.callCount("SetIpFilter") != 1 {
+		t.Errorf("expected invalid filters not to be sent, got %d requests", server.callCount("SetIpFilter"))
+	}
+}
+
+func TestNetworkAPI_RtspAuth(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetRtspAuth": `{"RtspAuth": {"mode": "basic"}}`,
+		"SetRtspAuth": "",
+	})
+	client := server.client()
+	ctx := t.Context()
+
+	if err := client.Network.SetRtspAuth(ctx, RTSPAuthDigest); err != nil {
+		t.Fatalf("SetRtspAuth failed: %v", err)
+	}
+
+	auth, err := client.Network.GetRtspAuth(ctx)
+	if err != nil {
+		t.Fatalf("GetRtspAuth failed: %v", err)
+	}
+	if auth.Mode != RTSPAuthDigest {
+		t.Errorf("expected digest mode, got %s", auth.Mode)
+	}
+
+	if err := client.Network.SetRtspAuth(ctx, "ntlm"); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
+
+func TestNetworkAPI_RtmpPush(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetRtmpPush": `{"RtmpPush": {"channel": 0, "enable": 0, "url": "", "streamType": "sub"}}`,
+		"SetRtmpPush": "",
+	})
+	client := server.client()
+	ctx := t.Context()
+
+	push := RtmpPush{Channel: 0, Enable: 1, URL: "rtmp://relay.example.com/live/cam1", StreamType: "main"}
+	if err := client.Network.SetRtmpPush(ctx, push); err != nil {
+		t.Fatalf("SetRtmpPush failed: %v", err)
+	}
+
+	got, err := client.Network.GetRtmpPush(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetRtmpPush failed: %v", err)
+	}
+	if got.Enable != 1 || got.URL != push.URL || got.StreamType != "main" {
+		t.Errorf("unexpected RTMP push configuration: %+v", got)
+	}
+
+	invalid := []RtmpPush{
+		{Enable: 1, URL: "http://relay.example.com/live"},
+		{Enable: 0, StreamType: "ext"},
+	}
+	for _, p := range invalid {
+		if err := client.Network.SetRtmpPush(ctx, p); err == nil {
+			t.Errorf("expected error for %+v", p)
+		}
+	}
+	if n := server.callCount("SetRtmpPush"); n != 1 {
+		t.Errorf("expected invalid configurations not to be sent, got %d calls", n)
+	}
+}
+
+func TestNetworkAPI_Gb28181(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetGb28181": `{"Gb28181": {"enable": 0, "serverPort": 5060}}`,
+		"SetGb28181": "",
+	})
+	client := server.client()
+	ctx := t.Context()
+
+	cfg := Gb28181{
+		Enable:       1,
+		ServerID:     "34020000002000000001",
+		ServerDomain: "3402000000",
+		ServerIP:     "10.0.0.5",
+		ServerPort:   5060,
+		DeviceID:     "34020000001320000001",
+		Password:     "12345678",
+		Expires:      3600,
+		Heartbeat:    60,
+	}
+	if err := client.Network.SetGb28181(ctx, cfg); err != nil {
+		t.Fatalf("SetGb28181 failed: %v", err)
+	}
+
+	got, err := client.Network.GetGb28181(ctx)
+	if err != nil {
+		t.Fatalf("GetGb28181 failed: %v", err)
+	}
+	if got.Enable != 1 || got.DeviceID != cfg.DeviceID || got.ServerIP != "10.0.0.5" {
+		t.Errorf("unexpected GB28181 configuration: %+v", got)
+	}
+
+	bad := cfg
+	bad.DeviceID = "3402000000132000000X"
+	if err := client.Network.SetGb28181(ctx, bad); err == nil {
+		t.Error("expected error for non-numeric device ID")
+	}
+	bad = cfg
+	bad.ServerIP = ""
+	if err := client.Network.SetGb28181(ctx, bad); err == nil {
+		t.Error("expected error for missing server IP")
+	}
+
+	// Disabled configurations are not validated
+	if err := client.Network.SetGb28181(ctx, Gb28181{}); err != nil {
+		t.Errorf("expected disabled configuration to be accepted, got %v", err)
+	}
+}
+
+func TestNetworkAPI_Multicast(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetMulticast": `{"Multicast": {"enable": 0, "ip": "", "port": 0}}`,
+		"SetMulticast": "",
+	})
+	client := server.client()
+
+	for _, m := range []Multicast{
+		{Enable: 1, IP: "192.168.1.10", Port: 5000},
+		{Enable: 1, IP: "239.0.0.1", Port: 0},
+	} {
+		if err := client.Network.SetMulticast(t.Context(), m); err == nil {
+			t.Errorf("expected error for %+v", m)
+		}
+	}
+	if server.callCount("SetMulticast") != 0 {
+		t.Error("invalid multicast configuration was sent")
+	}
+
+	if err := client.Network.SetMulticast(t.Context(), Multicast{Enable: 1, IP: "239.0.0.1", Port: 5000}); err != nil {
+		t.Fatalf("SetMulticast failed: %v", err)
+	}
+	m, err := client.Network.GetMulticast(t.Context())
+	if err != nil {
+		t.Fatalf("GetMulticast failed: %v", err)
+	}
+	if !m.Enable.Bool() || m.IP != "239.0.0.1" || m.Port != 5000 {
+		t.Errorf("unexpected multicast configuration: %+v", m)
+	}
+}
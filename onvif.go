@@ -0,0 +1,293 @@
+//go:build !reolink_noonvif
+
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// onvifMediaPath is the ONVIF Media service path used by Reolink firmware
+const onvifMediaPath = "/onvif/media_service"
+
+// ONVIFAPI provides minimal ONVIF Media service calls over the camera's
+// ONVIF port. On some models the ONVIF snapshot URI works when the HTTP Snap
+// command is disabled, which makes it a useful fallback.
+type ONVIFAPI struct {
+	client   *Client
+	endpoint string
+}
+
+// ONVIFProfile is an ONVIF media profile
+type ONVIFProfile struct {
+	Token    string // Profile token used by GetStreamUri and GetSnapshotUri
+	Name     string // Profile name, e.g. "mainStream"
+	Encoding string // Video encoding, e.g. "H264"
+	Width    int    // Video width in pixels
+	Height   int    // Video height in pixels
+}
+
+// SetMediaEndpoint overrides the ONVIF Media service URL. By default it is
+// derived from the host and the ONVIF port reported by GetNetPort.
+func (o *ONVIFAPI) SetMediaEndpoint(url string) {
+	o.endpoint = url
+}
+
+// mediaEndpoint returns the ONVIF Media service URL
+func (o *ONVIFAPI) mediaEndpoint(ctx context.Context) (string, error) {
+	if o.endpoint != "" {
+		return o.endpoint, nil
+	}
+	ports, err := o.client.Network.GetNetPort(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get ONVIF port: %w", err)
+	}
+	if !ports.OnvifEnable.Bool() {
+		return "", fmt.Errorf("ONVIF is disabled on the camera")
+	}
+	return fmt.Sprintf("http://%s:%d%s", o.client.Host(), ports.OnvifPort, onvifMediaPath), nil
+}
+
+// GetProfiles lists the camera's ONVIF media profiles
+func (o *ONVIFAPI) GetProfiles(ctx context.Context) ([]ONVIFProfile, error) {
+	o.client.log(ctx).Debug("getting ONVIF profiles")
+
+	var resp struct {
+		Profiles []struct {
+			Token string `xml:"token,attr"`
+			Name  string `xml:"Name"`
+			Video struct {
+				Encoding   string `xml:"Encoding"`
+				Resolution struct {
+					Width  int `xml:"Width"`
+					Height int `xml:"Height"`
+				} `xml:"Resolution"`
+			} `xml:"VideoEncoderConfiguration"`
+		} `xml:"Body>GetProfilesResponse>Profiles"`
+	}
+	body := `<GetProfiles xmlns="http://www.onvif.org/ver10/media/wsdl"/>`
+	if err := o.call(ctx, body, &resp); err != nil {
+		o.client.log(ctx).Error("failed to get ONVIF profiles: %v", err)
+		return nil, fmt.Errorf("GetProfiles request failed: %w", err)
+	}
+
+	profiles := make([]ONVIFProfile, 0, len(resp.Profiles))
+	for _, p := range resp.Profiles {
+		profiles = append(profiles, ONVIFProfile{
+			Token:    p.Token,
+			Name:     p.Name,
+			Encoding: p.Video.Encoding,
+			Width:    p.Video.Resolution.Width,
+			Height:   p.Video.Resolution.Height,
+		})
+	}
+
+	o.client.log(ctx).Info("successfully retrieved ONVIF profiles: count=%d", len(profiles))
+	return profiles, nil
+}
+
+// GetStreamUri returns the RTSP URI of a profile
+func (o *ONVIFAPI) GetStreamUri(ctx context.Context, profileToken string) (string, error) {
+	o.client.log(ctx).Debug("getting ONVIF stream URI: profile=%s", profileToken)
+
+	var resp struct {
+		URI string `xml:"Body>GetStreamUriResponse>MediaUri>Uri"`
+	}
+	body := `<GetStreamUri xmlns="http://www.onvif.org/ver10/media/wsdl">` +
+		`<StreamSetup><Stream xmlns="http://www.onvif.org/ver10/schema">RTP-Unicast</Stream>` +
+		`<Transport xmlns="http://www.onvif.org/ver10/schema"><Protocol>RTSP</Protocol></Transport></StreamSetup>` +
+		`<ProfileToken>` + xmlEscape(profileToken) + `</ProfileToken></GetStreamUri>`
+	if err := o.call(ctx, body, &resp); err != nil {
+		o.client.log(ctx).Error("failed to get ONVIF stream URI: %v", err)
+		return "", fmt.Errorf("GetStreamUri request failed: %w", err)
+	}
+	if resp.URI == "" {
+		return "", fmt.Errorf("GetStreamUri response contains no URI")
+	}
+
+	o.client.log(ctx).Info("successfully retrieved ONVIF stream URI")
+	return resp.URI, nil
+}
+
+// GetSnapshotUri returns the JPEG snapshot URI of a profile
+func (o *ONVIFAPI) GetSnapshotUri(ctx context.Context, profileToken string) (string, error) {
+	o.client.log(ctx).Debug("getting ONVIF snapshot URI: profile=%s", profileToken)
+
+	var resp struct {
+		URI string `xml:"Body>GetSnapshotUriResponse>MediaUri>Uri"`
+	}
+	body := `<GetSnapshotUri xmlns="http://www.onvif.org/ver10/media/wsdl">` +
+		`<ProfileToken>` + xmlEscape(profileToken) + `</ProfileToken></GetSnapshotUri>`
+	if err := o.call(ctx, body, &resp); err != nil {
+		o.client.log(ctx).Error("failed to get ONVIF snapshot URI: %v", err)
+		return "", fmt.Errorf("GetSnapshotUri request failed: %w", err)
+	}
+	if resp.URI == "" {
+		return "", fmt.Errorf("GetSnapshotUri response contains no URI")
+	}
+
+	o.client.log(ctx).Info("successfully retrieved ONVIF snapshot URI")
+	return resp.URI, nil
+}
+
+// Snapshot fetches a JPEG snapshot through a profile's ONVIF snapshot URI
+func (o *ONVIFAPI) Snapshot(ctx context.Context, profileToken string) ([]byte, error) {
+	uri, err := o.GetSnapshotUri(ctx, profileToken)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if o.client.username != "" {
+		req.SetBasicAuth(o.client.username, o.client.password)
+	}
+	o.client.setHeaders(req)
+	if err := o.client.signRequest(req, nil); err != nil {
+		return nil, err
+	}
+
+	resp, err := o.client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	image, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image data: %w", err)
+	}
+
+	o.client.log(ctx).Info("successfully captured ONVIF snapshot: size=%d bytes", len(image))
+	return image, nil
+}
+
+// call sends a SOAP request with a WS-Security UsernameToken and decodes
+// the response envelope into out
+func (o *ONVIFAPI) call(ctx context.Context, body string, out interface{}) error {
+	endpoint, err := o.mediaEndpoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	envelope, err := o.envelope(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(envelope))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/soap+xml; charset=utf-8")
+	o.client.setHeaders(req)
+	if err := o.client.signRequest(req, envelope); err != nil {
+		return err
+	}
+
+	resp, err := o.client.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var fault struct {
+		Reason string `xml:"Body>Fault>Reason>Text"`
+	}
+	if xml.Unmarshal(data, &fault) == nil && fault.Reason != "" {
+		return fmt.Errorf("ONVIF fault: %s", fault.Reason)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if err := xml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// envelope wraps body in a SOAP 1.2 envelope with a WS-Security
+// UsernameToken using the password digest scheme
+func (o *ONVIFAPI) envelope(body string) ([]byte, error) {
+	var header string
+	if o.client.username != "" {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		created := time.Now().UTC().Format(time.RFC3339)
+		header = `<s:Header><wsse:Security s:mustUnderstand="1" ` +
+			`xmlns:wsse="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd" ` +
+			`xmlns:wsu="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd">` +
+			`<wsse:UsernameToken><wsse:Username>` + xmlEscape(o.client.username) + `</wsse:Username>` +
+			`<wsse:Password Type="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest">` +
+			onvifPasswordDigest(nonce, created, o.client.password) + `</wsse:Password>` +
+			`<wsse:Nonce EncodingType="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary">` +
+			base64.StdEncoding.EncodeToString(nonce) + `</wsse:Nonce>` +
+			`<wsu:Created>` + created + `</wsu:Created></wsse:UsernameToken></wsse:Security></s:Header>`
+	}
+	return []byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope">` +
+		header + `<s:Body>` + body + `</s:Body></s:Envelope>`), nil
+}
+
+// onvifPasswordDigest computes Base64(SHA1(nonce + created + password))
+func onvifPasswordDigest(nonce []byte, created, password string) string {
+	h := sha1.New()
+	h.Write(nonce)
+	h.Write([]byte(created))
+	h.Write([]byte(password))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// xmlEscape escapes s for use in XML character data
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// SnapWithFallback captures a snapshot with the HTTP Snap command and, if
+// that fails, through the ONVIF snapshot URI of the first media profile.
+// The fallback only applies to channel 0 since ONVIF profiles are not
+// mapped to NVR channels. The Snap error is returned if both fail.
+func (e *EncodingAPI) SnapWithFallback(ctx context.Context, channel int) ([]byte, error) {
+	image, snapErr := e.Snap(ctx, channel)
+	if snapErr == nil || channel != 0 {
+		return image, snapErr
+	}
+
+	e.client.log(ctx).Warn("Snap failed, trying ONVIF snapshot: %v", snapErr)
+	profiles, err := e.client.ONVIF.GetProfiles(ctx)
+	if err != nil {
+		return nil, errors.Join(snapErr, err)
+	}
+	if len(profiles) == 0 {
+		return nil, errors.Join(snapErr, fmt.Errorf("no ONVIF profiles"))
+	}
+	image, err = e.client.ONVIF.Snapshot(ctx, profiles[0].Token)
+	if err != nil {
+		return nil, errors.Join(snapErr, err)
+	}
+	return image, nil
+}
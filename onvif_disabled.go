@@ -0,0 +1,56 @@
+//go:build reolink_noonvif
+
+package reolink
+
+import (
+	"context"
+	"fmt"
+)
+
+// errONVIFDisabled is returned by every ONVIF call in builds without ONVIF
+var errONVIFDisabled = fmt.Errorf("ONVIF support is not compiled in (built with reolink_noonvif)")
+
+// ONVIFAPI is a placeholder in builds with the reolink_noonvif tag, which
+// leave out the SOAP client and its encoding/xml dependency. Every method
+// fails.
+type ONVIFAPI struct {
+	client *Client
+}
+
+// ONVIFProfile is an ONVIF media profile
+type ONVIFProfile struct {
+	Token    string
+	Name     string
+	Encoding string
+	Width    int
+	Height   int
+}
+
+// SetMediaEndpoint has no effect without ONVIF support
+func (o *ONVIFAPI) SetMediaEndpoint(url string) {}
+
+// GetProfiles fails without ONVIF support
+func (o *ONVIFAPI) GetProfiles(ctx context.Context) ([]ONVIFProfile, error) {
+	return nil, errONVIFDisabled
+}
+
+// GetStreamUri fails without ONVIF support
+func (o *ONVIFAPI) GetStreamUri(ctx context.Context, profileToken string) (string, error) {
+	return "", errONVIFDisabled
+}
+
+// GetSnapshotUri fails without ONVIF support
+func (o *ONVIFAPI) GetSnapshotUri(ctx context.Context, profileToken string) (string, error) {
+	return "", errONVIFDisabled
+}
+
+// Snapshot fails without ONVIF support
+func (o *ONVIFAPI) Snapshot(ctx context.Context, profileToken string) ([]byte, error) {
+	return nil, errONVIFDisabled
+}
+
+// SnapWithFallback captures a snapshot with the HTTP Snap command. Builds
+// without ONVIF support have no fallback.
+func (e *EncodingAPI) SnapWithFallback(ctx context.Context, channel int) ([]byte, error) {
+	return e.Snap(ctx, channel)
+}
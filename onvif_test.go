@@ -0,0 +1,159 @@
+//go:build !reolink_noonvif
+
+package reolink
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newONVIFServer starts a server with an ONVIF Media service at
+// /onvif/media_service, a basic-auth snapshot endpoint at /snap and an API
+// endpoint at / whose Snap command is disabled
+func newONVIFServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/onvif/media_service", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var env struct {
+			Username string `xml:"Header>Security>UsernameToken>Username"`
+			Password string `xml:"Header>Security>UsernameToken>Password"`
+			Nonce    string `xml:"Header>Security>UsernameToken>Nonce"`
+			Created  string `xml:"Header>Security>UsernameToken>Created"`
+			Token    string `xml:"Body>GetSnapshotUri>ProfileToken"`
+		}
+		if err := xml.Unmarshal(body, &env); err != nil {
+			t.Errorf("invalid SOAP request: %v", err)
+		}
+		nonce, _ := base64.StdEncoding.DecodeString(env.Nonce)
+		if env.Username != "admin" || env.Password != onvifPasswordDigest(nonce, env.Created, "password") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body><s:Fault>`+
+				`<s:Reason><s:Text xml:lang="en">Sender not Authorized</s:Text></s:Reason></s:Fault></s:Body></s:Envelope>`)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/soap+xml")
+		switch {
+		case bytes.Contains(body, []byte("<GetProfiles")):
+			fmt.Fprint(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:trt="http://www.onvif.org/ver10/media/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema"><s:Body>`+
+				`<trt:GetProfilesResponse>`+
+				`<trt:Profiles token="000" fixed="true"><tt:Name>mainStream</tt:Name><tt:VideoEncoderConfiguration token="000"><tt:Encoding>H264</tt:Encoding><tt:Resolution><tt:Width>2560</tt:Width><tt:Height>1440</tt:Height></tt:Resolution></tt:VideoEncoderConfiguration></trt:Profiles>`+
+				`<trt:Profiles token="001" fixed="true"><tt:Name>subStream</tt:Name><tt:VideoEncoderConfiguration token="001"><tt:Encoding>H264</tt:Encoding><tt:Resolution><tt:Width>640</tt:Width><tt:Height>360</tt:Height></tt:Resolution></tt:VideoEncoderConfiguration></trt:Profiles>`+
+				`</trt:GetProfilesResponse></s:Body></s:Envelope>`)
+		case bytes.Contains(body, []byte("<GetStreamUri")):
+			fmt.Fprint(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:trt="http://www.onvif.org/ver10/media/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema"><s:Body>`+
+				`<trt:GetStreamUriResponse><trt:MediaUri><tt:Uri>rtsp://192.168.1.100:554/</tt:Uri></trt:MediaUri></trt:GetStreamUriResponse></s:Body></s:Envelope>`)
+		case bytes.Contains(body, []byte("<GetSnapshotUri")):
+			fmt.Fprintf(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:trt="http://www.onvif.org/ver10/media/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema"><s:Body>`+
+				`<trt:GetSnapshotUriResponse><trt:MediaUri><tt:Uri>%s/snap?profile=%s</tt:Uri></trt:MediaUri></trt:GetSnapshotUriResponse></s:Body></s:Envelope>`, server.URL, env.Token)
+		}
+	})
+	mux.HandleFunc("/snap", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(testJPEG)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newONVIFTestClient(server *httptest.Server, password string) *Client {
+	client := newTestClient(server)
+	client.username = "admin"
+	client.password = password
+	client.ONVIF.SetMediaEndpoint(server.URL + onvifMediaPath)
+	return client
+}
+
+func TestONVIFAPI_GetProfiles(t *testing.T) {
+	client := newONVIFTestClient(newONVIFServer(t), "password")
+
+	profiles, err := client.ONVIF.GetProfiles(t.Context())
+	if err != nil {
+		t.Fatalf("GetProfiles failed: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %d", len(profiles))
+	}
+	want := ONVIFProfile{Token: "000", Name: "mainStream", Encoding: "H264", Width: 2560, Height: 1440}
+	if profiles[0] != want {
+		t.Errorf("expected %+v, got %+v", want, profiles[0])
+	}
+}
+
+func TestONVIFAPI_GetStreamUri(t *testing.T) {
+	client := newONVIFTestClient(newONVIFServer(t), "password")
+
+	uri, err := client.ONVIF.GetStreamUri(t.Context(), "000")
+	if err != nil {
+		t.Fatalf("GetStreamUri failed: %v", err)
+	}
+	if uri != "rtsp://192.168.1.100:554/" {
+		t.Errorf("unexpected stream URI %q", uri)
+	}
+}
+
+func TestONVIFAPI_Fault(t *testing.T) {
+	client := newONVIFTestClient(newONVIFServer(t), "wrong")
+
+	_, err := client.ONVIF.GetProfiles(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "Sender not Authorized") {
+		t.Errorf("expected ONVIF fault, got %v", err)
+	}
+}
+
+func TestONVIFAPI_MediaEndpointFromNetPort(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"onvifEnable": 1, "onvifPort": 8000}}`,
+	})
+	client := server.client()
+	client.host = "192.168.1.100"
+
+	endpoint, err := client.ONVIF.mediaEndpoint(t.Context())
+	if err != nil {
+		t.Fatalf("mediaEndpoint failed: %v", err)
+	}
+	if endpoint != "http://192.168.1.100:8000/onvif/media_service" {
+		t.Errorf("unexpected endpoint %q", endpoint)
+	}
+
+	server.set("GetNetPort", `{"NetPort": {"onvifEnable": 0, "onvifPort": 8000}}`)
+	if _, err := client.ONVIF.mediaEndpoint(t.Context()); err == nil {
+		t.Error("expected error when ONVIF is disabled")
+	}
+}
+
+func TestEncodingAPI_SnapWithFallback(t *testing.T) {
+	client := newONVIFTestClient(newONVIFServer(t), "password")
+
+	image, err := client.Encoding.SnapWithFallback(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("SnapWithFallback failed: %v", err)
+	}
+	if !bytes.Equal(image, testJPEG) {
+		t.Error("expected ONVIF snapshot image")
+	}
+
+	if _, err := client.Encoding.SnapWithFallback(t.Context(), 1); err == nil {
+		t.Error("expected no fallback for NVR channels")
+	}
+}
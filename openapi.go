@@ -0,0 +1,9 @@
+package reolink
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI 3.0 specification of the camera HTTP API, as
+// shipped in docs/reolink-camera-api-openapi.yaml
+//
+//go:embed docs/reolink-camera-api-openapi.yaml
+var OpenAPISpec []byte
@@ -0,0 +1,266 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// operationPollInterval is the time between status reads of a running
+// Operation
+var operationPollInterval = 2 * time.Second
+
+// OperationState is the lifecycle state of an Operation
+type OperationState int
+
+// Operation states
+const (
+	OperationRunning OperationState = iota
+	OperationSucceeded
+	OperationFailed
+)
+
+// String returns the state name
+func (s OperationState) String() string {
+	switch s {
+	case OperationRunning:
+		return "running"
+	case OperationSucceeded:
+		return "succeeded"
+	case OperationFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("OperationState(%d)", int(s))
+	}
+}
+
+// OperationStatus is a snapshot of an Operation's progress
+type OperationStatus struct {
+	Name    string         // Operation name, e.g. "format"
+	State   OperationState // Current state
+	Percent int            // Completion percentage, or -1 if the device does not report it
+	Message string         // Latest progress detail
+	Err     error          // Failure cause once State is OperationFailed
+}
+
+// Done reports whether the operation has finished
+func (s OperationStatus) Done() bool {
+	return s.State != OperationRunning
+}
+
+// Operation is a long-running task on the device, such as a disk format,
+// firmware upgrade or PTZ calibration, that continues after the request
+// starting it returns. Progress is tracked by polling the device in the
+// background until the task finishes or the context it was started with is
+// done.
+type Operation interface {
+	// Status returns the latest progress snapshot
+	Status() OperationStatus
+	// Wait blocks until the operation finishes or ctx is done, returning
+	// the operation's error or ctx.Err()
+	Wait(ctx context.Context) error
+	// Progress returns a channel that receives status updates and is closed
+	// after the final status. Updates a slow receiver misses are dropped in
+	// favour of the latest one.
+	Progress() <-chan OperationStatus
+}
+
+// operation is the Operation implementation shared by all long-running
+// commands
+type operation struct {
+	mu       sync.Mutex
+	status   OperationStatus
+	progress chan OperationStatus
+	done     chan struct{}
+}
+
+// operationFunc performs an operation, calling report with progress; a nil
+// return means success
+type operationFunc func(ctx context.Context, report func(percent int, message string)) error
+
+// startOperation runs fn in the background as an Operation
+func startOperation(ctx context.Context, name string, fn operationFunc) Operation {
+	op := &operation{
+		status:   OperationStatus{Name: name, State: OperationRunning, Percent: -1},
+		progress: make(chan OperationStatus, 1),
+		done:     make(chan struct{}),
+	}
+	op.publish(op.status)
+
+	go func() {
+		err := fn(ctx, func(percent int, message string) {
+			op.mu.Lock()
+			op.status.Percent = percent
+			op.status.Message = message
+			status := op.status
+			op.mu.Unlock()
+			op.publish(status)
+		})
+
+		op.mu.Lock()
+		if err != nil {
+			op.status.State = OperationFailed
+			op.status.Err = err
+		} else {
+			op.status.State = OperationSucceeded
+			op.status.Percent = 100
+		}
+		status := op.status
+		op.mu.Unlock()
+
+		op.publish(status)
+		close(op.progress)
+		close(op.done)
+	}()
+	return op
+}
+
+// publish delivers status on the progress channel, replacing an update the
+// receiver has not picked up yet. Only the operation goroutine sends, so the
+// send after draining cannot block.
+func (op *operation) publish(status OperationStatus) {
+	select {
+	case op.progress <- status:
+	default:
+		select {
+		case <-op.progress:
+		default:
+		}
+		op.progress <- status
+	}
+}
+
+// Status implements Operation
+func (op *operation) Status() OperationStatus {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.status
+}
+
+// Wait implements Operation
+func (op *operation) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-op.done:
+		return op.Status().Err
+	}
+}
+
+// Progress implements Operation
+func (op *operation) Progress() <-chan OperationStatus {
+	return op.progress
+}
+
+// pollOperation calls poll every operationPollInterval until it reports
+// done or returns an error, or ctx is done
+func pollOperation(ctx context.Context, poll func() (bool, error)) error {
+	ticker := time.NewTicker(operationPollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := poll()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// StartFormat formats a storage device and returns an Operation that
+// completes once the disk reports itself formatted and mounted again
+func (s *SystemAPI) StartFormat(ctx context.Context, hddID int) (Operation, error) {
+	if err := s.Format(ctx, hddID); err != nil {
+		return nil, err
+	}
+	return startOperation(ctx, "format", func(ctx context.Context, report func(int, string)) error {
+		return pollOperation(ctx, func() (bool, error) {
+			disks, err := s.GetHddInfo(ctx)
+			if err != nil {
+				report(-1, fmt.Sprintf("waiting for disk: %v", err))
+				return false, nil
+			}
+			if hddID < 0 || hddID >= len(disks) {
+				return false, fmt.Errorf("disk %d not found", hddID)
+			}
+			disk := disks[hddID]
+			if disk.Format == 1 && disk.Mount == 1 {
+				return true, nil
+			}
+			report(-1, "formatting")
+			return false, nil
+		})
+	}), nil
+}
+
+// StartUpgradeOnline starts an online firmware upgrade and returns an
+// Operation tracking UpgradeStatus. Status read errors are tolerated while
+// the device reboots into the new firmware.
+func (s *SystemAPI) StartUpgradeOnline(ctx context.Context) (Operation, error) {
+	if err := s.UpgradeOnline(ctx); err != nil {
+		return nil, err
+	}
+	return startOperation(ctx, "upgrade", func(ctx context.Context, report func(int, string)) error {
+		percent := 0
+		return pollOperation(ctx, func() (bool, error) {
+			status, err := s.UpgradeStatus(ctx)
+			if err != nil {
+				report(percent, fmt.Sprintf("waiting for device: %v", err))
+				return false, nil
+			}
+			if status.Code != 0 {
+				return false, fmt.Errorf("upgrade failed with code %d", status.Code)
+			}
+			percent = status.Percent
+			if percent >= 100 {
+				return true, nil
+			}
+			report(percent, "upgrading")
+			return false, nil
+		})
+	}), nil
+}
+
+// StartPtzCheck starts PTZ calibration and returns an Operation that
+// completes when the check state reports it finished
+func (p *PTZAPI) StartPtzCheck(ctx context.Context, channel int) (Operation, error) {
+	if err := p.PtzCheck(ctx, channel); err != nil {
+		return nil, err
+	}
+	return startOperation(ctx, "ptz check", func(ctx context.Context, report func(int, string)) error {
+		started, idle := false, 0
+		return pollOperation(ctx, func() (bool, error) {
+			state, err := p.GetPtzCheckState(ctx, channel)
+			if err != nil {
+				return false, err
+			}
+			switch state.Status {
+			case PtzCheckRunning:
+				started = true
+				report(-1, "calibrating")
+				return false, nil
+			case PtzCheckFinished:
+				return true, nil
+			default:
+				// Idle after running also means finished on firmware that
+				// does not report PtzCheckFinished, as does staying idle
+				// when the check completed before the first poll
+				idle++
+				return started || idle >= 3, nil
+			}
+		})
+	}), nil
+}
+
+// StartTestWifi runs TestWifi in the background and returns an Operation
+// that completes when the test does
+func (n *NetworkAPI) StartTestWifi(ctx context.Context) (Operation, error) {
+	return startOperation(ctx, "wifi test", func(ctx context.Context, report func(int, string)) error {
+		report(-1, "testing")
+		return n.TestWifi(ctx)
+	}), nil
+}
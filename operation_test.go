@@ -0,0 +1,159 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fastOperationPoll(t *testing.T) {
+	t.Helper()
+	old := operationPollInterval
+	operationPollInterval = time.Millisecond
+	t.Cleanup(func() { operationPollInterval = old })
+}
+
+func TestOperation_Progress(t *testing.T) {
+	step := make(chan struct{})
+	op := startOperation(t.Context(), "test", func(ctx context.Context, report func(int, string)) error {
+		<-step
+		report(50, "halfway")
+		<-step
+		return nil
+	})
+
+	if s := op.Status(); s.State != OperationRunning || s.Percent != -1 || s.Name != "test" {
+		t.Errorf("unexpected initial status: %+v", s)
+	}
+	<-op.Progress()
+	step <- struct{}{}
+	if s := <-op.Progress(); s.Percent != 50 || s.Message != "halfway" {
+		t.Errorf("unexpected progress: %+v", s)
+	}
+	step <- struct{}{}
+
+	if err := op.Wait(t.Context()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	var last OperationStatus
+	for s := range op.Progress() {
+		last = s
+	}
+	if last.State != OperationSucceeded || last.Percent != 100 || !last.Done() {
+		t.Errorf("unexpected final status: %+v", last)
+	}
+}
+
+func TestOperation_Failure(t *testing.T) {
+	errBoom := errors.New("boom")
+	op := startOperation(t.Context(), "test", func(ctx context.Context, report func(int, string)) error {
+		return errBoom
+	})
+	if err := op.Wait(t.Context()); !errors.Is(err, errBoom) {
+		t.Errorf("expected errBoom, got %v", err)
+	}
+	if s := op.Status(); s.State != OperationFailed || s.State.String() != "failed" {
+		t.Errorf("unexpected status: %+v", s)
+	}
+}
+
+func TestOperation_WaitContext(t *testing.T) {
+	op := startOperation(t.Context(), "test", func(ctx context.Context, report func(int, string)) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if err := op.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestSystemAPI_StartUpgradeOnline(t *testing.T) {
+	fastOperationPoll(t)
+	server := newCmdServer(t, map[string]string{
+		"UpgradeOnline": "",
+		"UpgradeStatus": `{"Status": {"Persent": 40, "code": 0}}`,
+	})
+	client := server.client()
+
+	op, err := client.System.StartUpgradeOnline(t.Context())
+	if err != nil {
+		t.Fatalf("StartUpgradeOnline failed: %v", err)
+	}
+	for s := range op.Progress() {
+		if s.Percent == 40 {
+			server.set("UpgradeStatus", `{"Status": {"Persent": 100, "code": 0}}`)
+		}
+	}
+	if err := op.Wait(t.Context()); err != nil {
+		t.Errorf("expected upgrade to succeed, got %v", err)
+	}
+
+	server.set("UpgradeStatus", `{"Status": {"Persent": 10, "code": 3}}`)
+	op, _ = client.System.StartUpgradeOnline(t.Context())
+	if err := op.Wait(t.Context()); err == nil {
+		t.Error("expected error for a non-zero status code")
+	}
+}
+
+func TestSystemAPI_StartFormat(t *testing.T) {
+	fastOperationPoll(t)
+	server := newCmdServer(t, map[string]string{
+		"Format":     "",
+		"GetHddInfo": `{"HddInfo": [{"capacity": 1000, "format": 1, "mount": 1, "size": 0}]}`,
+	})
+	client := server.client()
+
+	op, err := client.System.StartFormat(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("StartFormat failed: %v", err)
+	}
+	if err := op.Wait(t.Context()); err != nil {
+		t.Errorf("expected format to succeed, got %v", err)
+	}
+
+	op, _ = client.System.StartFormat(t.Context(), 1)
+	if err := op.Wait(t.Context()); err == nil {
+		t.Error("expected error for a missing disk")
+	}
+}
+
+func TestPTZAPI_StartPtzCheck(t *testing.T) {
+	fastOperationPoll(t)
+	server := newCmdServer(t, map[string]string{
+		"PtzCheck":         "",
+		"GetPtzCheckState": `{"status": 1}`,
+	})
+	client := server.client()
+
+	op, err := client.PTZ.StartPtzCheck(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("StartPtzCheck failed: %v", err)
+	}
+	<-op.Progress()
+	server.set("GetPtzCheckState", `{"status": 0}`)
+	if err := op.Wait(t.Context()); err != nil {
+		t.Errorf("expected check to succeed, got %v", err)
+	}
+}
+
+func TestNetworkAPI_StartTestWifi(t *testing.T) {
+	server := newCmdServer(t, nil)
+	client := server.client()
+
+	op, err := client.Network.StartTestWifi(t.Context())
+	if err != nil {
+		t.Fatalf("StartTestWifi failed: %v", err)
+	}
+	if err := op.Wait(t.Context()); err == nil {
+		t.Error("expected error when TestWifi is not supported")
+	}
+
+	server.set("TestWifi", "")
+	op, _ = client.Network.StartTestWifi(t.Context())
+	if err := op.Wait(t.Context()); err != nil {
+		t.Errorf("expected WiFi test to succeed, got %v", err)
+	}
+}
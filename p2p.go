@@ -0,0 +1,167 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// P2PDialer is a dialer hook that opens a connection to a camera
+// identified by its P2P UID, for cameras without a direct IP route.
+//
+// This package does not implement P2P connectivity: Reolink's relay
+// protocol is proprietary and undocumented, so there is no WithUID option
+// that reaches a camera on its own. An implementation of P2PDialer must
+// perform the relay handshake itself, or tunnel by other means, and return
+// a connection carrying the camera's HTTP API.
+type P2PDialer interface {
+	// DialUID opens a connection to the camera with the given UID
+	DialUID(ctx context.Context, uid string) (net.Conn, error)
+}
+
+// P2PDialerFunc adapts an ordinary function to the P2PDialer interface
+type P2PDialerFunc func(ctx context.Context, uid string) (net.Conn, error)
+
+// DialUID calls f(ctx, uid)
+func (f P2PDialerFunc) DialUID(ctx context.Context, uid string) (net.Conn, error) {
+	return f(ctx, uid)
+}
+
+// WithP2PDialer routes all API traffic through the dialer hook to the
+// camera with the given P2P UID instead of dialing the host directly. The
+// package does not implement the relay, see P2PDialer. If the client was
+// created with an empty host, the UID is used as the host name in request
+// URLs. With a nil dialer only the UID is recorded, e.g. for P2PWaker.
+//
+// The dialer is installed on a copy of the client's HTTP client and
+// transport after all options are applied, so the order of WithHTTPClient
+// and WithP2PDialer does not matter and a caller's transport is never
+// modified. The transport must be an *http.Transport; with any other
+// transport, requests fail rather than bypass the relay.
+func WithP2PDialer(uid string, dialer P2PDialer) Option {
+	return func(c *Client) {
+		c.uid = uid
+		c.p2pDialer = dialer
+		if c.host == "" {
+			c.host = uid
+		}
+	}
+}
+
+// installP2PDialer points a copy of the client's HTTP client and transport
+// at the P2P dialer, see WithP2PDialer
+func (c *Client) installP2PDialer() {
+	if c.p2pDialer == nil {
+		return
+	}
+	httpClient := *c.httpClient
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		err := fmt.Errorf("p2p dial %s: cannot install the dialer on a %T, only on an *http.Transport", c.uid, base)
+		c.log(context.Background()).Error("%v", err)
+		httpClient.Transport = errTransport{err: err}
+		c.httpClient = &httpClient
+		return
+	}
+
+	transport = transport.Clone()
+	transport.Proxy = nil // The relay replaces the network path
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		conn, err := c.p2pDialer.DialUID(ctx, c.uid)
+		if err != nil {
+			return nil, fmt.Errorf("p2p dial %s failed: %w", c.uid, err)
+		}
+		return conn, nil
+	}
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+}
+
+// errTransport is an http.RoundTripper failing every request with err
+type errTransport struct {
+	err error
+}
+
+// RoundTrip implements http.RoundTripper
+func (t errTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
+
+// UID returns the P2P UID the client connects through, or "" for direct
+// connections
+func (c *Client) UID() string {
+	return c.uid
+}
+
+// P2PState is the outcome of CheckP2P
+type P2PState int
+
+// P2P states
+const (
+	P2PDisabled    P2PState = iota // P2P is disabled on the camera
+	P2PUnverified                  // P2P is enabled but no dialer was given to test the relay
+	P2PReachable                   // The camera answered through the relay
+	P2PUnreachable                 // P2P is enabled but the relay dial failed
+)
+
+// String returns the state name
+func (s P2PState) String() string {
+	switch s {
+	case P2PDisabled:
+		return "disabled"
+	case P2PUnverified:
+		return "unverified"
+	case P2PReachable:
+		return "reachable"
+	case P2PUnreachable:
+		return "unreachable"
+	default:
+		return fmt.Sprintf("P2PState(%d)", int(s))
+	}
+}
+
+// P2PStatus is the result of CheckP2P
+type P2PStatus struct {
+	UID   string
+	State P2PState
+	Err   error // Dial error when State is P2PUnreachable
+}
+
+// CheckP2P tells "P2P disabled" apart from "P2P enabled but unreachable".
+// The camera API has no command reporting the relay connection, so with
+// P2P enabled the relay is tested by dialing the camera's UID through
+// dialer, typically the one passed to WithP2PDialer. Without a dialer the state
+// is P2PUnverified. The HTTP API also offers no way to regenerate the UID.
+func (n *NetworkAPI) CheckP2P(ctx context.Context, dialer P2PDialer) (*P2PStatus, error) {
+	p2p, err := n.GetP2p(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &P2PStatus{UID: p2p.UID}
+	switch {
+	case !p2p.Enable.Bool():
+		status.State = P2PDisabled
+	case dialer == nil || p2p.UID == "":
+		status.State = P2PUnverified
+	default:
+		conn, err := dialer.DialUID(ctx, p2p.UID)
+		if err != nil {
+			status.State, status.Err = P2PUnreachable, err
+		} else {
+			conn.Close()
+			status.State = P2PReachable
+		}
+	}
+
+	n.client.log(ctx).Info("P2P check: uid=%s state=%s", status.UID, status.State)
+	return status, nil
+}
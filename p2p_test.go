@@ -0,0 +1,129 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithP2PDialer_RoutesThroughDialer(t *testing.T) {
+	camera := newCmdServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-410"}}`,
+	})
+
+	var dialed string
+	dialer := P2PDialerFunc(func(ctx context.Context, uid string) (net.Conn, error) {
+		dialed = uid
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", camera.Listener.Addr().String())
+	})
+
+	client := NewClient("", WithP2PDialer("95270000ABCDEFGH", dialer))
+	if client.Host() != "95270000ABCDEFGH" {
+		t.Errorf("expected UID as host, got %s", client.Host())
+	}
+	if client.UID() != "95270000ABCDEFGH" {
+		t.Errorf("expected UID, got %s", client.UID())
+	}
+
+	info, err := client.System.GetDeviceInfo(t.Context())
+	if err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if info.Model != "RLC-410" {
+		t.Errorf("expected model RLC-410, got %s", info.Model)
+	}
+	if dialed != "95270000ABCDEFGH" {
+		t.Errorf("expected dialer to be called with UID, got %q", dialed)
+	}
+}
+
+func TestWithP2PDialer_DialError(t *testing.T) {
+	errRelay := errors.New("relay unavailable")
+	dialer := P2PDialerFunc(func(ctx context.Context, uid string) (net.Conn, error) {
+		return nil, errRelay
+	})
+
+	client := NewClient("", WithP2PDialer("95270000ABCDEFGH", dialer))
+	_, err := client.System.GetDeviceInfo(t.Context())
+	if !errors.Is(err, errRelay) {
+		t.Errorf("expected relay error, got %v", err)
+	}
+}
+
+func TestWithP2PDialer_Transport(t *testing.T) {
+	camera := newCmdServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-410"}}`,
+	})
+	dialer := P2PDialerFunc(func(ctx context.Context, uid string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", camera.Listener.Addr().String())
+	})
+
+	// Applied before WithHTTPClient, on a transport that must not change
+	transport := &http.Transport{}
+	httpClient := &http.Client{Transport: transport}
+	client := NewClient("95270000ABCDEFGH", WithP2PDialer("95270000ABCDEFGH", dialer), WithHTTPClient(httpClient))
+	if _, err := client.System.GetDeviceInfo(t.Context()); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if transport.DialContext != nil || httpClient.Transport != transport {
+		t.Error("the caller's HTTP client or transport was modified")
+	}
+
+	// A transport the dialer cannot be installed on fails requests
+	custom := &http.Client{Transport: roundTripFunc(http.DefaultTransport.RoundTrip)}
+	client = NewClient(camera.Listener.Addr().String(), WithHTTPClient(custom), WithP2PDialer("95270000ABCDEFGH", dialer))
+	if _, err := client.System.GetDeviceInfo(t.Context()); err == nil || !strings.Contains(err.Error(), "cannot install the dialer") {
+		t.Errorf("expected an error for an unsupported transport, got %v", err)
+	}
+}
+
+func TestNetworkAPI_CheckP2P(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetP2p": `{"P2p": {"enable": 0, "uid": "95270000ABCDEFGH"}}`,
+	})
+	client := srv.client()
+
+	var dialed string
+	reachable := P2PDialerFunc(func(ctx context.Context, uid string) (net.Conn, error) {
+		dialed = uid
+		c1, c2 := net.Pipe()
+		c2.Close()
+		return c1, nil
+	})
+	unreachable := P2PDialerFunc(func(ctx context.Context, uid string) (net.Conn, error) {
+		return nil, errors.New("relay timeout")
+	})
+
+	tests := []struct {
+		enable int
+		dialer P2PDialer
+		want   P2PState
+	}{
+		{0, reachable, P2PDisabled},
+		{1, nil, P2PUnverified},
+		{1, reachable, P2PReachable},
+		{1, unreachable, P2PUnreachable},
+	}
+	for _, tt := range tests {
+		srv.set("GetP2p", fmt.Sprintf(`{"P2p": {"enable": %d, "uid": "95270000ABCDEFGH"}}`, tt.enable))
+		status, err := client.Network.CheckP2P(t.Context(), tt.dialer)
+		if err != nil {
+			t.Fatalf("CheckP2P failed: %v", err)
+		}
+		if status.State != tt.want {
+			t.Errorf("enable=%d: state = %s, want %s", tt.enable, status.State, tt.want)
+		}
+		if (status.State == P2PUnreachable) != (status.Err != nil) {
+			t.Errorf("unexpected error for state %s: %v", status.State, status.Err)
+		}
+	}
+	if dialed != "95270000ABCDEFGH" {
+		t.Errorf("dialed %q", dialed)
+	}
+}
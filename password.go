@@ -0,0 +1,103 @@
+package reolink
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Password rules accepted by Reolink firmware. The lengths are those of
+// AddUser and ModifyUser in the API guide ("limit 1~31 characters"). The
+// guide does not list the allowed characters: PasswordSymbols are the ones
+// known to pass through the CGI unchanged, while others (notably '&',
+// quotes, spaces and non-ASCII) are rejected or silently mangled by some
+// firmware, leaving the account inaccessible.
+const (
+	// PasswordMinLength is the minimum password length
+	PasswordMinLength = 1
+	// PasswordMaxLength is the maximum password length
+	PasswordMaxLength = 31
+	// PasswordSymbols lists the non-alphanumeric characters cameras accept
+	PasswordSymbols = "@$*-_.!#^~+?"
+)
+
+// generateMinLength is the shortest password GeneratePassword returns, one
+// character of each class
+const generateMinLength = 4
+
+const (
+	passwordLower  = "abcdefghijklmnopqrstuvwxyz"
+	passwordUpper  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordDigits = "0123456789"
+)
+
+// ValidatePassword checks a password against the rules cameras enforce (or
+// silently break on). It returns an error describing the first violation.
+func ValidatePassword(password string) error {
+	if len(password) < PasswordMinLength {
+		return fmt.Errorf("invalid password: must not be empty")
+	}
+	if len(password) > PasswordMaxLength {
+		return fmt.Errorf("invalid password: must be at most %d characters", PasswordMaxLength)
+	}
+	for _, r := range password {
+		if r < 128 && (strings.ContainsRune(passwordLower, r) ||
+			strings.ContainsRune(passwordUpper, r) ||
+			strings.ContainsRune(passwordDigits, r) ||
+			strings.ContainsRune(PasswordSymbols, r)) {
+			continue
+		}
+		return fmt.Errorf("invalid password: character %q is not allowed (allowed: letters, digits and %s)", r, PasswordSymbols)
+	}
+	return nil
+}
+
+// GeneratePassword returns a random password of the given length that
+// satisfies ValidatePassword and contains at least one lowercase letter,
+// uppercase letter, digit and symbol, so length must be at least 4. A
+// length of 0 selects 16.
+func GeneratePassword(length int) (string, error) {
+	if length == 0 {
+		length = 16
+	}
+	if length < generateMinLength || length > PasswordMaxLength {
+		return "", fmt.Errorf("password length must be between %d and %d", generateMinLength, PasswordMaxLength)
+	}
+
+	classes := []string{passwordLower, passwordUpper, passwordDigits, PasswordSymbols}
+	all := strings.Join(classes, "")
+
+	buf := make([]byte, length)
+	for i := range buf {
+		set := all
+		if i < len(classes) {
+			set = classes[i]
+		}
+		c, err := randomChar(set)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = c
+	}
+
+	// Shuffle so the guaranteed classes are not always in front
+	for i := len(buf) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		buf[i], buf[j.Int64()] = buf[j.Int64()], buf[i]
+	}
+
+	return string(buf), nil
+}
+
+// randomChar returns a uniformly random byte from set
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return set[n.Int64()], nil
+}
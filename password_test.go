@@ -0,0 +1,87 @@
+package reolink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		password string
+		valid    bool
+	}{
+		{"", false},
+		{"a", true},
+		{"abc123", true},
+		{"Str0ng-Pass.word", true},
+		{"with&ampersand", false},
+		{"with space", false},
+		{`quote"d`, false},
+		{"pässword", false},
+		{strings.Repeat("a", PasswordMaxLength), true},
+		{strings.Repeat("a", PasswordMaxLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			err := ValidatePassword(tt.password)
+			if tt.valid && err != nil {
+				t.Errorf("expected valid password, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected invalid password")
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	for _, length := range []int{0, generateMinLength, 12, PasswordMaxLength} {
+		pw, err := GeneratePassword(length)
+		if err != nil {
+			t.Fatalf("GeneratePassword(%d) failed: %v", length, err)
+		}
+
+		want := length
+		if want == 0 {
+			want = 16
+		}
+		if len(pw) != want {
+			t.Errorf("expected length %d, got %d", want, len(pw))
+		}
+		if err := ValidatePassword(pw); err != nil {
+			t.Errorf("generated password %q failed validation: %v", pw, err)
+		}
+		for _, set := range []string{passwordLower, passwordUpper, passwordDigits, PasswordSymbols} {
+			if !strings.ContainsAny(pw, set) {
+				t.Errorf("generated password %q is missing a character from %q", pw, set)
+			}
+		}
+	}
+
+	if _, err := GeneratePassword(PasswordMaxLength + 1); err == nil {
+		t.Error("expected error for excessive length")
+	}
+	if _, err := GeneratePassword(generateMinLength - 1); err == nil {
+		t.Error("expected error for a length below one character per class")
+	}
+}
+
+func TestSecurityAPI_AddUser_RejectsInvalidPassword(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	err := client.Security.AddUser(t.Context(), User{UserName: "bob", Password: "a&b=c123", Level: "guest"})
+	if err == nil {
+		t.Fatal("expected error for invalid password")
+	}
+	if called {
+		t.Error("expected request not to be sent")
+	}
+}
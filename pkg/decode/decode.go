@@ -0,0 +1,89 @@
+// Package decode is the integration point between the SDK and external
+// video decoders. The SDK generates stream URLs; a Decoder turns them into
+// frames. Sources carry a Refresh callback so a decoder can pick up a new
+// URL, e.g. after the RTSP port or the credentials changed, without the
+// application re-wiring the stream.
+//
+// Build with the reolink_ffmpeg tag for FFmpeg, a reference Decoder that
+// runs the ffmpeg binary. Decoders that link a media library, such as
+// go-astiav, belong in separate modules that implement Decoder.
+package decode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	reolink "github.com/mosleyit/reolink_api_wrapper"
+)
+
+// Source is a stream for a Decoder
+type Source struct {
+	// URL is the stream URL, including credentials
+	URL string
+	// Transport is the RTSP transport to request, "" for other protocols
+	Transport reolink.RTSPTransport
+	// Refresh returns the source again with a current URL; nil if the URL
+	// never changes. Decoders call it before reconnecting.
+	Refresh func(ctx context.Context) (Source, error)
+}
+
+// Frame is a decoded video frame
+type Frame struct {
+	Width  int
+	Height int
+	Pix    []byte    // Packed RGB24 pixels, 3 bytes per pixel, row by row
+	Time   time.Time // When the frame was decoded
+}
+
+// FrameHandler receives decoded frames. Returning an error stops decoding
+// and is returned by Decode.
+type FrameHandler func(Frame) error
+
+// Decoder decodes a stream until ctx is done, the stream cannot be
+// recovered or handler returns an error
+type Decoder interface {
+	Decode(ctx context.Context, src Source, handler FrameHandler) error
+}
+
+// RTSPSource returns the RTSP source of a channel's main or sub stream,
+// using the port and transport the camera reports in its stream info.
+// Refresh reads the stream info again.
+func RTSPSource(ctx context.Context, client *reolink.Client, channel int, stream reolink.StreamType) (Source, error) {
+	if stream != reolink.StreamMain && stream != reolink.StreamSub {
+		return Source{}, fmt.Errorf("unsupported stream type %q", stream)
+	}
+
+	info, err := client.Streaming.GetStreamInfo(ctx, channel)
+	var multi *reolink.MultiError
+	if err != nil && !errors.As(err, &multi) {
+		return Source{}, fmt.Errorf("failed to get stream info: %w", err)
+	}
+	if !info.RTSPEnabled {
+		return Source{}, fmt.Errorf("RTSP is disabled on the camera")
+	}
+
+	src := Source{
+		URL:       info.MainRTSPURL,
+		Transport: info.RTSPTransport,
+		Refresh: func(ctx context.Context) (Source, error) {
+			return RTSPSource(ctx, client, channel, stream)
+		},
+	}
+	if stream == reolink.StreamSub {
+		src.URL = info.SubRTSPURL
+	}
+	return src, nil
+}
+
+// FLVSource returns the HTTP-FLV source of a channel's stream. Refresh
+// generates the URL again, picking up changed credentials.
+func FLVSource(client *reolink.Client, channel int, stream reolink.StreamType) Source {
+	return Source{
+		URL: client.Streaming.GetFLVURL(stream, channel),
+		Refresh: func(context.Context) (Source, error) {
+			return FLVSource(client, channel, stream), nil
+		},
+	}
+}
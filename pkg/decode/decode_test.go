@@ -0,0 +1,61 @@
+package decode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	reolink "github.com/mosleyit/reolink_api_wrapper"
+)
+
+func newTestClient(t *testing.T, rtspPort *string) *reolink.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"cmd": "GetNetPort", "code": 0, "value": {"NetPort": {"rtspEnable": 1, "rtspPort": ` + *rtspPort + `}}}]`))
+	}))
+	t.Cleanup(server.Close)
+	client := reolink.NewClient(strings.TrimPrefix(server.URL, "http://"), reolink.WithCredentials("admin", "secret"))
+	client.SetToken("test-token")
+	return client
+}
+
+func TestRTSPSource(t *testing.T) {
+	port := "8554"
+	client := newTestClient(t, &port)
+
+	src, err := RTSPSource(t.Context(), client, 0, reolink.StreamSub)
+	if err != nil {
+		t.Fatalf("RTSPSource failed: %v", err)
+	}
+	if !strings.HasSuffix(src.URL, ":8554/Preview_01_sub") || src.Transport != reolink.RTSPTransportTCP {
+		t.Errorf("unexpected source: %s %s", src.URL, src.Transport)
+	}
+
+	port = "9554"
+	fresh, err := src.Refresh(t.Context())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if !strings.HasSuffix(fresh.URL, ":9554/Preview_01_sub") {
+		t.Errorf("refreshed URL did not pick up the new port: %s", fresh.URL)
+	}
+
+	if _, err := RTSPSource(t.Context(), client, 0, reolink.StreamExt); err == nil {
+		t.Error("expected error for the ext stream")
+	}
+}
+
+func TestFLVSource(t *testing.T) {
+	port := "554"
+	client := newTestClient(t, &port)
+
+	src := FLVSource(client, 1, reolink.StreamMain)
+	if !strings.Contains(src.URL, "stream=channel1_main.bcs") || src.Transport != "" {
+		t.Errorf("unexpected source: %s %s", src.URL, src.Transport)
+	}
+	fresh, err := src.Refresh(t.Context())
+	if err != nil || fresh.URL != src.URL {
+		t.Errorf("Refresh = %s, %v", fresh.URL, err)
+	}
+}
@@ -0,0 +1,122 @@
+//go:build reolink_ffmpeg
+
+package decode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// FFmpeg decodes streams with the ffmpeg binary, scaled to Width x Height.
+// When the stream ends or fails it refreshes the source and starts ffmpeg
+// again, giving up after MaxRestarts attempts in a row without a frame.
+type FFmpeg struct {
+	Path        string    // ffmpeg binary (default: "ffmpeg" from PATH)
+	Width       int       // Output width in pixels (required)
+	Height      int       // Output height in pixels (required)
+	MaxRestarts int       // Restarts without a frame before giving up (default: 3)
+	Stderr      io.Writer // Receives ffmpeg's error output (default: discarded)
+}
+
+// Decode implements Decoder
+func (f *FFmpeg) Decode(ctx context.Context, src Source, handler FrameHandler) error {
+	if f.Width <= 0 || f.Height <= 0 {
+		return fmt.Errorf("width and height must be positive")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler must not be nil")
+	}
+	maxRestarts := f.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = 3
+	}
+
+	failures := 0
+	for {
+		frames, err := f.run(ctx, src, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var stop handlerError
+		if errors.As(err, &stop) {
+			return stop.err
+		}
+		if frames > 0 {
+			failures = 0
+		}
+		failures++
+		if failures > maxRestarts {
+			return fmt.Errorf("ffmpeg failed %d times in a row: %w", failures, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(failures) * time.Second):
+		}
+		if src.Refresh != nil {
+			if fresh, err := src.Refresh(ctx); err == nil {
+				src = fresh
+			}
+		}
+	}
+}
+
+// handlerError marks an error returned by the frame handler
+type handlerError struct{ err error }
+
+func (e handlerError) Error() string { return e.err.Error() }
+
+// run decodes src with one ffmpeg process and returns the number of frames
+// delivered and why the process ended
+func (f *FFmpeg) run(ctx context.Context, src Source, handler FrameHandler) (int, error) {
+	path := f.Path
+	if path == "" {
+		path = "ffmpeg"
+	}
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	if src.Transport != "" {
+		args = append(args, "-rtsp_transport", string(src.Transport))
+	}
+	args = append(args,
+		"-i", src.URL,
+		"-f", "rawvideo", "-pix_fmt", "rgb24",
+		"-s", strconv.Itoa(f.Width)+"x"+strconv.Itoa(f.Height),
+		"-")
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stderr = f.Stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, err
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	frames := 0
+	var runErr error
+	for {
+		pix := make([]byte, f.Width*f.Height*3)
+		if _, err := io.ReadFull(out, pix); err != nil {
+			runErr = fmt.Errorf("stream ended: %w", err)
+			break
+		}
+		frames++
+		if err := handler(Frame{Width: f.Width, Height: f.Height, Pix: pix, Time: time.Now()}); err != nil {
+			runErr = handlerError{err}
+			break
+		}
+	}
+
+	cmd.Process.Kill()
+	if err := cmd.Wait(); err != nil && !errors.As(runErr, new(handlerError)) {
+		runErr = fmt.Errorf("%w (ffmpeg: %v)", runErr, err)
+	}
+	return frames, runErr
+}
@@ -0,0 +1,60 @@
+//go:build reolink_ffmpeg
+
+package decode
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeFFmpeg writes a script that prints two 2x1 RGB frames and exits
+func fakeFFmpeg(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nprintf 'abcdefghijkl'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFFmpeg_Decode(t *testing.T) {
+	refreshes := 0
+	src := Source{
+		URL: "rtsp://camera/Preview_01_main",
+		Refresh: func(context.Context) (Source, error) {
+			refreshes++
+			return Source{URL: "rtsp://camera/Preview_01_main"}, nil
+		},
+	}
+	decoder := &FFmpeg{Path: fakeFFmpeg(t), Width: 2, Height: 1}
+
+	var frames []string
+	stop := errors.New("enough")
+	err := decoder.Decode(t.Context(), src, func(f Frame) error {
+		frames = append(frames, string(f.Pix))
+		if len(frames) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Decode = %v, want the handler's error", err)
+	}
+	if frames[0] != "abcdef" || frames[1] != "ghijkl" || frames[2] != "abcdef" {
+		t.Errorf("unexpected frames %q", frames)
+	}
+	if refreshes != 1 {
+		t.Errorf("expected 1 refresh before restarting, got %d", refreshes)
+	}
+}
+
+func TestFFmpeg_GivesUp(t *testing.T) {
+	decoder := &FFmpeg{Path: filepath.Join(t.TempDir(), "missing"), Width: 2, Height: 1, MaxRestarts: 1}
+	err := decoder.Decode(t.Context(), Source{URL: "rtsp://camera/"}, func(Frame) error { return nil })
+	if err == nil {
+		t.Fatal("expected error when ffmpeg cannot run")
+	}
+}
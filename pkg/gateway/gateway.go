@@ -0,0 +1,196 @@
+// Package gateway serves the camera HTTP API of reolink.OpenAPISpec on top
+// of a reolink.Client, turning the SDK into a camera gateway: REST clients
+// generated from the spec talk to the gateway as if it were the camera,
+// while the gateway keeps the camera session and applies the client's
+// quirks, retries, caching and sleep handling:
+//
+//	client := reolink.NewClient("192.168.1.100", reolink.WithCredentials("admin", "password"))
+//	http.ListenAndServe(":8080", gateway.NewServer(client))
+//	// POST http://localhost:8080/cgi-bin/api.cgi?cmd=GetDevInfo
+//
+// The gateway is a hand-written proxy, not a server generated from the
+// spec: it forwards command batches as they are, without validating them
+// against the spec, and the camera answers commands it does not know.
+//
+// The gateway holds the only camera session: Login is answered with a
+// placeholder token and Logout does nothing, and the token, user and
+// password query parameters are ignored. Every caller thus acts with the
+// client's account: always put the gateway behind your own authentication,
+// even with the default policy, and narrow the policy per caller with
+// reolink.WithCallPolicy in a middleware. By default a Server only forwards
+// commands that read state, except the CredentialCommands, whose responses
+// carry passwords; WithPolicy replaces that default. Batches with a
+// forbidden command are refused with 403 Forbidden.
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	reolink "github.com/mosleyit/reolink_api_wrapper"
+)
+
+// maxBodySize limits the size of a request body
+const maxBodySize = 1 << 20
+
+// leaseTime is the lease time of the placeholder token, in seconds
+const leaseTime = 3600
+
+// CredentialCommands are the reading commands whose responses carry
+// passwords or tokens of accounts, mail and FTP servers, Wi-Fi networks and
+// the like. The default policy of a Server denies them.
+var CredentialCommands = []string{
+	"GetUser", "GetUserV20", "GetWifi", "GetDdns",
+	"GetEmail", "GetEmailV20", "GetFtp", "GetFtpV20",
+	"GetPush", "GetPushV20", "GetGb28181", "GetIpc", "GetSdCardLock",
+}
+
+// defaultPolicy allows the reading commands but the CredentialCommands
+func defaultPolicy() reolink.Policy {
+	return reolink.Policy{ReadOnly: true, Deny: slices.Clone(CredentialCommands)}
+}
+
+// Server is an http.Handler serving the camera API and its specification.
+// It answers POST /cgi-bin/api.cgi and POST /api.cgi, the path used by the
+// spec, with the camera's responses, and GET /openapi.yaml with the spec.
+type Server struct {
+	Client *reolink.Client
+	policy *reolink.Policy // Commands callers may execute; nil for defaultPolicy
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithPolicy sets the commands callers may execute, on top of the client's
+// own policy. It replaces the default, which allows the commands that read
+// state but the CredentialCommands; the zero reolink.Policy allows
+// everything.
+func WithPolicy(policy reolink.Policy) Option {
+	return func(s *Server) {
+		s.policy = &policy
+	}
+}
+
+// NewServer returns a Server forwarding to client
+func NewServer(client *reolink.Client, opts ...Option) *Server {
+	s := &Server{Client: client}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// command is a command of a request body
+type command struct {
+	Cmd    string          `json:"cmd"`
+	Action int             `json:"action"`
+	Param  json.RawMessage `json:"param"`
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/openapi.yaml":
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(reolink.OpenAPISpec)
+	case "/cgi-bin/api.cgi", "/api.cgi":
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.serveAPI(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveAPI forwards the commands of r to the camera in one batch
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	var cmds []command
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&cmds); err != nil {
+		writeError(w, http.StatusBadRequest, r.URL.Query().Get("cmd"), reolink.ErrCodeParametersError, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if len(cmds) == 0 {
+		writeError(w, http.StatusBadRequest, r.URL.Query().Get("cmd"), reolink.ErrCodeMissingParameters, "no commands")
+		return
+	}
+
+	results := make([]reolink.Response, len(cmds))
+	var (
+		forward []reolink.Request
+		index   []int // Position of each forwarded command in results
+	)
+	for i, cmd := range cmds {
+		switch cmd.Cmd {
+		case "":
+			results[i] = errorResponse("", reolink.ErrCodeMissingParameters, "missing cmd")
+		case "Login":
+			results[i] = reolink.Response{Cmd: cmd.Cmd, Value: json.RawMessage(fmt.Sprintf(`{"Token": {"leaseTime": %d, "name": "gateway"}}`, leaseTime))}
+		case "Logout":
+			results[i] = reolink.Response{Cmd: cmd.Cmd, Value: json.RawMessage(`{"rspCode": 200}`)}
+		default:
+			req := reolink.Request{Cmd: cmd.Cmd, Action: cmd.Action}
+			if len(cmd.Param) > 0 {
+				req.Param = cmd.Param
+			}
+			forward = append(forward, req)
+			index = append(index, i)
+		}
+	}
+
+	if len(forward) > 0 {
+		policy := defaultPolicy()
+		if s.policy != nil {
+			policy = *s.policy
+		}
+		resps, err := s.Client.Batch(reolink.WithCallPolicy(r.Context(), policy), forward)
+		if resps == nil && err != nil {
+			if errors.Is(err, reolink.ErrForbiddenByPolicy) {
+				writeError(w, http.StatusForbidden, forward[0].Cmd, reolink.ErrCodeAbilityError, err.Error())
+				return
+			}
+			// Transport failure: the camera did not answer
+			status := http.StatusBadGateway
+			if errors.Is(err, reolink.ErrCameraAsleep) {
+				status = http.StatusServiceUnavailable
+			}
+			writeError(w, status, forward[0].Cmd, reolink.ErrCodeFailedReceiveData, err.Error())
+			return
+		}
+		// Failed commands are in resps as the camera answered them
+		for i, resp := range resps {
+			results[index[i]] = resp
+		}
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// errorResponse returns a failed response in the camera's format
+func errorResponse(cmd string, rspCode int, detail string) reolink.Response {
+	return reolink.Response{
+		Cmd:   cmd,
+		Code:  1,
+		Error: &reolink.ErrorDetail{RspCode: rspCode, Detail: detail},
+	}
+}
+
+// writeError writes a single failed response with status
+func writeError(w http.ResponseWriter, status int, cmd string, rspCode int, detail string) {
+	writeJSON(w, status, []reolink.Response{errorResponse(cmd, rspCode, detail)})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,254 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	reolink "github.com/mosleyit/reolink_api_wrapper"
+)
+
+// camera is a fake camera answering commands with canned values; unknown
+// commands are not supported
+type camera struct {
+	mu     sync.Mutex
+	values map[string]string
+	calls  []string
+	params map[string]json.RawMessage
+}
+
+func newTestServer(t *testing.T, values map[string]string, opts ...Option) (*Server, *camera) {
+	t.Helper()
+	cam := &camera{values: values, params: make(map[string]json.RawMessage)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			Cmd   string          `json:"cmd"`
+			Param json.RawMessage `json:"param"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cam.mu.Lock()
+		defer cam.mu.Unlock()
+		var resps []string
+		for _, req := range reqs {
+			cam.calls = append(cam.calls, req.Cmd)
+			cam.params[req.Cmd] = req.Param
+			value, ok := cam.values[req.Cmd]
+			if !ok {
+				resps = append(resps, fmt.Sprintf(`{"cmd": %q, "code": 1, "error": {"rspCode": -9, "detail": "not support"}}`, req.Cmd))
+				continue
+			}
+			resps = append(resps, fmt.Sprintf(`{"cmd": %q, "code": 0, "value": %s}`, req.Cmd, value))
+		}
+		w.Write([]byte("[" + strings.Join(resps, ",") + "]"))
+	}))
+	t.Cleanup(server.Close)
+
+	client := reolink.NewClient(strings.TrimPrefix(server.URL, "http://"), reolink.WithCredentials("admin", "secret"))
+	client.SetToken("test-token")
+	return NewServer(client, opts...), cam
+}
+
+func post(t *testing.T, s *Server, path, body string) (*httptest.ResponseRecorder, []reolink.Response) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	var resps []reolink.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resps); err != nil {
+		t.Fatalf("invalid response body %q: %v", rec.Body, err)
+	}
+	return rec, resps
+}
+
+func TestServer_Forward(t *testing.T) {
+	s, cam := newTestServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A"}}`,
+		"SetOsd":     `{"rspCode": 200}`,
+	}, WithPolicy(reolink.Policy{}))
+
+	rec, resps := post(t, s, "/cgi-bin/api.cgi?cmd=GetDevInfo&token=ignored", `[
+		{"cmd": "GetDevInfo", "action": 0},
+		{"cmd": "SetOsd", "param": {"Osd": {"channel": 0}}},
+		{"cmd": "GetAiState", "param": {"channel": 0}}
+	]`)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if len(resps) != 3 {
+		t.Fatalf("expected 3 responses, got %+v", resps)
+	}
+	if resps[0].Cmd != "GetDevInfo" || resps[0].Code != 0 || !strings.Contains(string(resps[0].Value), "RLC-811A") {
+		t.Errorf("unexpected GetDevInfo response %+v", resps[0])
+	}
+	if string(cam.params["SetOsd"]) != `{"Osd":{"channel":0}}` {
+		t.Errorf("param not forwarded: %s", cam.params["SetOsd"])
+	}
+	if err := resps[2].ToAPIError(); !reolink.IsNotSupported(err) {
+		t.Errorf("expected the camera's not supported error, got %v", err)
+	}
+}
+
+func TestServer_Session(t *testing.T) {
+	s, cam := newTestServer(t, map[string]string{})
+
+	_, resps := post(t, s, "/api.cgi?cmd=Login", `[{"cmd": "Login", "param": {"User": {"userName": "admin", "password": "x"}}}, {"cmd": "Logout"}]`)
+	if len(resps) != 2 || resps[0].Code != 0 || resps[1].Code != 0 {
+		t.Fatalf("unexpected responses %+v", resps)
+	}
+	var login reolink.LoginValue
+	if err := json.Unmarshal(resps[0].Value, &login); err != nil || login.Token.Name == "" || login.Token.LeaseTime != leaseTime {
+		t.Errorf("unexpected Login value %s (%v)", resps[0].Value, err)
+	}
+	if len(cam.calls) != 0 {
+		t.Errorf("session commands reached the camera: %v", cam.calls)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	s, _ := newTestServer(t, map[string]string{})
+
+	tests := []struct {
+		name    string
+		body    string
+		rspCode int
+	}{
+		{"malformed", `{"cmd": `, reolink.ErrCodeParametersError},
+		{"empty", `[]`, reolink.ErrCodeMissingParameters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, resps := post(t, s, "/cgi-bin/api.cgi?cmd=GetDevInfo", tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", rec.Code)
+			}
+			if len(resps) != 1 || resps[0].Cmd != "GetDevInfo" || resps[0].Error == nil || resps[0].Error.RspCode != tt.rspCode {
+				t.Errorf("unexpected responses %+v", resps)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cgi-bin/api.cgi", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "POST" {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/other", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestServer_Unreachable(t *testing.T) {
+	camera := httptest.NewServer(http.NotFoundHandler())
+	camera.Close()
+	s := NewServer(reolink.NewClient(strings.TrimPrefix(camera.URL, "http://")))
+
+	rec, resps := post(t, s, "/cgi-bin/api.cgi", `[{"cmd": "GetDevInfo"}]`)
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("expected status 502, got %d", rec.Code)
+	}
+	if len(resps) != 1 || resps[0].Error == nil || resps[0].Error.Detail == "" {
+		t.Errorf("unexpected responses %+v", resps)
+	}
+}
+
+func TestServer_Spec(t *testing.T) {
+	s, _ := newTestServer(t, map[string]string{})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "openapi: 3.") {
+		t.Errorf("unexpected spec response %d %.40q", rec.Code, rec.Body)
+	}
+}
+
+func TestServer_Policy(t *testing.T) {
+	values := map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A"}}`,
+		"SetOsd":     `{"rspCode": 200}`,
+		"Reboot":     `{"rspCode": 200}`,
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		allowed map[string]bool
+	}{
+		{"default", nil, map[string]bool{"GetDevInfo": true}},
+		{"no destructive", []Option{WithPolicy(reolink.PolicyNoDestructive())}, map[string]bool{"GetDevInfo": true, "SetOsd": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, cam := newTestServer(t, values, tt.opts...)
+			for _, cmd := range []string{"GetDevInfo", "SetOsd", "Reboot"} {
+				rec, resps := post(t, s, "/cgi-bin/api.cgi", fmt.Sprintf(`[{"cmd": %q}]`, cmd))
+				if tt.allowed[cmd] {
+					if rec.Code != http.StatusOK {
+						t.Errorf("expected status 200 for %s, got %d", cmd, rec.Code)
+					}
+					continue
+				}
+				if rec.Code != http.StatusForbidden {
+					t.Errorf("expected status 403 for %s, got %d", cmd, rec.Code)
+				}
+				if len(resps) != 1 || resps[0].Error == nil || resps[0].Error.RspCode != reolink.ErrCodeAbilityError {
+					t.Errorf("unexpected responses %+v", resps)
+				}
+			}
+			for _, cmd := range cam.calls {
+				if !tt.allowed[cmd] {
+					t.Errorf("forbidden command %s reached the camera", cmd)
+				}
+			}
+		})
+	}
+
+	// The client's own policy applies on top of the gateway's
+	s, _ := newTestServer(t, values, WithPolicy(reolink.Policy{}))
+	reolink.WithPolicy(reolink.PolicyReadOnly())(s.Client)
+	if rec, _ := post(t, s, "/cgi-bin/api.cgi", `[{"cmd": "SetOsd"}]`); rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 under the client's policy, got %d", rec.Code)
+	}
+}
+
+func TestServer_Credentials(t *testing.T) {
+	values := map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A"}}`,
+		"GetDdns":    `{"Ddns": {"enable": 1, "userName": "cam", "password": "secret"}}`,
+		"GetEmail":   `{"Email": {"userName": "cam@example.com", "password": "secret"}}`,
+	}
+
+	s, cam := newTestServer(t, values)
+	for _, body := range []string{
+		`[{"cmd": "GetDevInfo"}, {"cmd": "GetDdns"}]`,
+		`[{"cmd": "GetEmail"}]`,
+	} {
+		rec, resps := post(t, s, "/cgi-bin/api.cgi", body)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected status 403 for %s, got %d", body, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "secret") {
+			t.Errorf("password leaked in %s", rec.Body.String())
+		}
+		if len(resps) != 1 || resps[0].Error == nil || resps[0].Error.RspCode != reolink.ErrCodeAbilityError {
+			t.Errorf("unexpected responses %+v", resps)
+		}
+	}
+	if len(cam.calls) != 0 {
+		t.Errorf("expected no camera calls, got %v", cam.calls)
+	}
+
+	// A policy set with WithPolicy replaces the default
+	s, _ = newTestServer(t, values, WithPolicy(reolink.PolicyReadOnly()))
+	if rec, _ := post(t, s, "/cgi-bin/api.cgi", `[{"cmd": "GetDdns"}]`); rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 with a read-only policy, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,167 @@
+// Package grpcserver serves the gRPC services of
+// proto/reolink/v1/reolink.proto for the cameras of a reolink.Fleet,
+// forwarding each RPC to the rpc.Service method of the same name:
+//
+//	fleet := reolink.NewFleet()
+//	fleet.Add("front", reolink.NewClient("192.168.1.100", reolink.WithCredentials("admin", "password")))
+//
+//	server := grpc.NewServer()
+//	grpcserver.Register(server, rpc.NewService(fleet))
+//	lis, _ := net.Listen("tcp", ":50051")
+//	server.Serve(lis)
+//
+// Errors are converted to gRPC status errors with rpc.Code. The generated
+// stubs are in the reolinkv1 subpackage, for clients in Go.
+//
+// The package is a separate module, so only applications that import it
+// depend on gRPC and protobuf; the SDK itself stays dependency-free.
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/mosleyit/reolink_api_wrapper/pkg/grpcserver/reolinkv1"
+	"github.com/mosleyit/reolink_api_wrapper/pkg/rpc"
+)
+
+// Register registers the System, PTZ, Alarm and Streaming services of svc
+// with s
+func Register(s grpc.ServiceRegistrar, svc *rpc.Service) {
+	reolinkv1.RegisterSystemServiceServer(s, &systemServer{svc: svc})
+	reolinkv1.RegisterPTZServiceServer(s, &ptzServer{svc: svc})
+	reolinkv1.RegisterAlarmServiceServer(s, &alarmServer{svc: svc})
+	reolinkv1.RegisterStreamingServiceServer(s, &streamingServer{svc: svc})
+}
+
+// statusError converts an error of the service to a gRPC status error
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return status.Error(codes.Code(rpc.Code(err)), err.Error())
+}
+
+// cameraRef converts a CameraRef message
+func cameraRef(ref *reolinkv1.CameraRef) rpc.CameraRef {
+	return rpc.CameraRef{Camera: ref.GetCamera(), Channel: int(ref.GetChannel())}
+}
+
+type systemServer struct {
+	reolinkv1.UnimplementedSystemServiceServer
+	svc *rpc.Service
+}
+
+// GetDeviceInfo forwards to rpc.Service.GetDeviceInfo
+func (s *systemServer) GetDeviceInfo(ctx context.Context, ref *reolinkv1.CameraRef) (*reolinkv1.DeviceInfo, error) {
+	info, err := s.svc.GetDeviceInfo(ctx, cameraRef(ref))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &reolinkv1.DeviceInfo{
+		Model:           info.Model,
+		Name:            info.Name,
+		Serial:          info.Serial,
+		FirmwareVersion: info.FirmwareVersion,
+		HardwareVersion: info.HardwareVersion,
+		ChannelCount:    int32(info.ChannelCount),
+	}, nil
+}
+
+// Reboot forwards to rpc.Service.Reboot
+func (s *systemServer) Reboot(ctx context.Context, ref *reolinkv1.CameraRef) (*emptypb.Empty, error) {
+	if err := s.svc.Reboot(ctx, cameraRef(ref)); err != nil {
+		return nil, statusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+type ptzServer struct {
+	reolinkv1.UnimplementedPTZServiceServer
+	svc *rpc.Service
+}
+
+// Control forwards to rpc.Service.Control
+func (s *ptzServer) Control(ctx context.Context, req *reolinkv1.PtzRequest) (*emptypb.Empty, error) {
+	err := s.svc.Control(ctx, rpc.PtzRequest{
+		Target:   cameraRef(req.GetTarget()),
+		Op:       req.GetOp(),
+		Speed:    int(req.GetSpeed()),
+		PresetID: int(req.GetPresetId()),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListPresets forwards to rpc.Service.ListPresets
+func (s *ptzServer) ListPresets(ctx context.Context, ref *reolinkv1.CameraRef) (*reolinkv1.PtzPresets, error) {
+	presets, err := s.svc.ListPresets(ctx, cameraRef(ref))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	out := &reolinkv1.PtzPresets{Presets: make([]*reolinkv1.PtzPreset, 0, len(presets))}
+	for _, p := range presets {
+		out.Presets = append(out.Presets, &reolinkv1.PtzPreset{Id: int32(p.ID), Name: p.Name, Enabled: p.Enabled})
+	}
+	return out, nil
+}
+
+type alarmServer struct {
+	reolinkv1.UnimplementedAlarmServiceServer
+	svc *rpc.Service
+}
+
+// GetAlarmState forwards to rpc.Service.GetAlarmState
+func (s *alarmServer) GetAlarmState(ctx context.Context, ref *reolinkv1.CameraRef) (*reolinkv1.AlarmState, error) {
+	state, err := s.svc.GetAlarmState(ctx, cameraRef(ref))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &reolinkv1.AlarmState{Motion: state.Motion, Ai: state.AI}, nil
+}
+
+// WatchEvents forwards to rpc.Service.WatchEvents until the client cancels
+// the stream
+func (s *alarmServer) WatchEvents(req *reolinkv1.WatchEventsRequest, stream grpc.ServerStreamingServer[reolinkv1.Event]) error {
+	err := s.svc.WatchEvents(stream.Context(), rpc.WatchEventsRequest{
+		Target:  cameraRef(req.GetTarget()),
+		Profile: req.GetProfile(),
+	}, func(ev rpc.Event) error {
+		return stream.Send(&reolinkv1.Event{
+			Camera:       ev.Camera,
+			Type:         ev.Type,
+			Channel:      int32(ev.Channel),
+			TimeUnixNano: ev.TimeUnixNano,
+			Detail:       ev.Detail,
+		})
+	})
+	return statusError(err)
+}
+
+type streamingServer struct {
+	reolinkv1.UnimplementedStreamingServiceServer
+	svc *rpc.Service
+}
+
+// GetStreamURLs forwards to rpc.Service.GetStreamURLs
+func (s *streamingServer) GetStreamURLs(ctx context.Context, ref *reolinkv1.CameraRef) (*reolinkv1.StreamURLs, error) {
+	urls, err := s.svc.GetStreamURLs(ctx, cameraRef(ref))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &reolinkv1.StreamURLs{
+		MainRtsp:      urls.MainRTSP,
+		SubRtsp:       urls.SubRTSP,
+		MainRtmp:      urls.MainRTMP,
+		SubRtmp:       urls.SubRTMP,
+		MainFlv:       urls.MainFLV,
+		SubFlv:        urls.SubFLV,
+		RtspTransport: urls.RTSPTransport,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	reolink "github.com/mosleyit/reolink_api_wrapper"
+	"github.com/mosleyit/reolink_api_wrapper/pkg/grpcserver/reolinkv1"
+	"github.com/mosleyit/reolink_api_wrapper/pkg/rpc"
+)
+
+// newTestConn serves the services for a fleet with one fake camera, "porch",
+// answering commands with canned values, and returns a connection to them
+func newTestConn(t *testing.T, values map[string]string) *grpc.ClientConn {
+	t.Helper()
+	camera := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			Cmd string `json:"cmd"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var resps []string
+		for _, req := range reqs {
+			value, ok := values[req.Cmd]
+			if !ok {
+				resps = append(resps, fmt.Sprintf(`{"cmd": %q, "code": 1, "error": {"rspCode": -9, "detail": "not support"}}`, req.Cmd))
+				continue
+			}
+			resps = append(resps, fmt.Sprintf(`{"cmd": %q, "code": 0, "value": %s}`, req.Cmd, value))
+		}
+		w.Write([]byte("[" + strings.Join(resps, ",") + "]"))
+	}))
+	t.Cleanup(camera.Close)
+
+	client := reolink.NewClient(strings.TrimPrefix(camera.URL, "http://"), reolink.WithCredentials("admin", "secret"))
+	client.SetToken("test-token")
+	fleet := reolink.NewFleet()
+	fleet.Add("porch", client)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	Register(server, rpc.NewService(fleet))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestSystemService(t *testing.T) {
+	conn := newTestConn(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-810A", "name": "Porch", "serial": "SN1", "firmVer": "v3.1.0", "hardVer": "IPC_523", "channelNum": 1}}`,
+	})
+	system := reolinkv1.NewSystemServiceClient(conn)
+
+	info, err := system.GetDeviceInfo(t.Context(), &reolinkv1.CameraRef{Camera: "porch"})
+	if err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if info.Model != "RLC-810A" || info.FirmwareVersion != "v3.1.0" || info.ChannelCount != 1 {
+		t.Errorf("unexpected device info: %v", info)
+	}
+
+	_, err = system.GetDeviceInfo(t.Context(), &reolinkv1.CameraRef{Camera: "garage"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an unknown camera, got %v", err)
+	}
+	_, err = system.Reboot(t.Context(), &reolinkv1.CameraRef{Camera: "porch"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented for an unsupported command, got %v", err)
+	}
+}
+
+func TestPTZService(t *testing.T) {
+	conn := newTestConn(t, map[string]string{
+		"PtzCtrl":      `{"rspCode": 200}`,
+		"GetPtzPreset": `{"PtzPreset": [{"channel": 0, "id": 1, "name": "Gate", "enable": 1}]}`,
+	})
+	ptz := reolinkv1.NewPTZServiceClient(conn)
+
+	if _, err := ptz.Control(t.Context(), &reolinkv1.PtzRequest{
+		Target: &reolinkv1.CameraRef{Camera: "porch"}, Op: "Left", Speed: 32,
+	}); err != nil {
+		t.Fatalf("Control failed: %v", err)
+	}
+	_, err := ptz.Control(t.Context(), &reolinkv1.PtzRequest{Target: &reolinkv1.CameraRef{Camera: "porch"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without an operation, got %v", err)
+	}
+
+	presets, err := ptz.ListPresets(t.Context(), &reolinkv1.CameraRef{Camera: "porch"})
+	if err != nil {
+		t.Fatalf("ListPresets failed: %v", err)
+	}
+	if len(presets.Presets) != 1 || presets.Presets[0].Name != "Gate" || !presets.Presets[0].Enabled {
+		t.Errorf("unexpected presets: %v", presets)
+	}
+}
+
+func TestAlarmService(t *testing.T) {
+	conn := newTestConn(t, map[string]string{
+		"GetMdState": `{"state": 1}`,
+	})
+	alarm := reolinkv1.NewAlarmServiceClient(conn)
+
+	state, err := alarm.GetAlarmState(t.Context(), &reolinkv1.CameraRef{Camera: "porch"})
+	if err != nil {
+		t.Fatalf("GetAlarmState failed: %v", err)
+	}
+	if !state.Motion || len(state.Ai) != 0 {
+		t.Errorf("unexpected alarm state: %v", state)
+	}
+
+	stream, err := alarm.WatchEvents(t.Context(), &reolinkv1.WatchEventsRequest{
+		Target:  &reolinkv1.CameraRef{Camera: "porch"},
+		Profile: "turbo",
+	})
+	if err != nil {
+		t.Fatalf("WatchEvents failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an unknown profile, got %v", err)
+	}
+}
+
+func TestStreamingService(t *testing.T) {
+	conn := newTestConn(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"rtspEnable": 1, "rtspPort": 554, "rtmpEnable": 1, "rtmpPort": 1935}}`,
+	})
+	streaming := reolinkv1.NewStreamingServiceClient(conn)
+
+	urls, err := streaming.GetStreamURLs(t.Context(), &reolinkv1.CameraRef{Camera: "porch"})
+	if err != nil {
+		t.Fatalf("GetStreamURLs failed: %v", err)
+	}
+	if !strings.HasPrefix(urls.MainRtsp, "rtsp://") || urls.MainFlv == "" {
+		t.Errorf("unexpected stream URLs: %v", urls)
+	}
+}
@@ -0,0 +1,70 @@
+//go:build reolink_ffmpeg
+
+package hls
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/mosleyit/reolink_api_wrapper/pkg/decode"
+)
+
+// FFmpegSegmenter segments streams with the ffmpeg binary. Video is copied,
+// not transcoded, so the camera's stream must use H.264 for browsers
+// without H.265 support; use the sub stream or set the encoding
+// accordingly. Audio is transcoded to AAC.
+//
+// LowLatency shortens segments to one second for a few seconds of delay
+// instead of around ten. ffmpeg does not produce LL-HLS partial segments,
+// so this is as low as plain HLS goes.
+type FFmpegSegmenter struct {
+	Path        string    // ffmpeg binary (default: "ffmpeg" from PATH)
+	SegmentTime int       // Target segment duration in seconds (default: 2, or 1 with LowLatency)
+	ListSize    int       // Segments kept in the playlist (default: 6)
+	LowLatency  bool      // Use one-second segments
+	Stderr      io.Writer // Receives ffmpeg's error output (default: discarded)
+}
+
+// Segment implements Segmenter
+func (f *FFmpegSegmenter) Segment(ctx context.Context, src decode.Source, dir string) error {
+	path := f.Path
+	if path == "" {
+		path = "ffmpeg"
+	}
+	segmentTime := f.SegmentTime
+	if segmentTime <= 0 {
+		segmentTime = 2
+		if f.LowLatency {
+			segmentTime = 1
+		}
+	}
+	listSize := f.ListSize
+	if listSize <= 0 {
+		listSize = 6
+	}
+
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	if src.Transport != "" {
+		args = append(args, "-rtsp_transport", string(src.Transport))
+	}
+	args = append(args,
+		"-i", src.URL,
+		"-c:v", "copy", "-c:a", "aac",
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(segmentTime),
+		"-hls_list_size", strconv.Itoa(listSize),
+		"-hls_flags", "delete_segments+independent_segments+omit_endlist",
+		"-hls_segment_filename", filepath.Join(dir, "segment%d.ts"),
+		filepath.Join(dir, Playlist))
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stderr = f.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w", err)
+	}
+	return fmt.Errorf("ffmpeg: stream ended")
+}
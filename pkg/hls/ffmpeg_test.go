@@ -0,0 +1,37 @@
+//go:build reolink_ffmpeg
+
+package hls
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mosleyit/reolink_api_wrapper/pkg/decode"
+)
+
+func TestFFmpegSegmenter(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "ffmpeg")
+	// The fake ffmpeg records its arguments and exits
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" > \"$(dirname \"$0\")/args\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	segmenter := &FFmpegSegmenter{Path: script, LowLatency: true}
+	src := decode.Source{URL: "rtsp://camera/Preview_01_sub", Transport: "tcp"}
+	if err := segmenter.Segment(t.Context(), src, dir); err == nil {
+		t.Error("expected error when ffmpeg exits")
+	}
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"-rtsp_transport tcp", "-i rtsp://camera/Preview_01_sub", "-hls_time 1", filepath.Join(dir, Playlist)} {
+		if !strings.Contains(string(args), want) {
+			t.Errorf("ffmpeg arguments %q lack %q", args, want)
+		}
+	}
+}
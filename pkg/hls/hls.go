@@ -0,0 +1,174 @@
+// Package hls relays a camera stream to browsers as HLS. A Segmenter turns
+// a stream into a playlist and segments in a directory, and Relay runs it
+// and serves the directory over HTTP, so dashboards can show live video
+// without reaching the camera or running a separate media server:
+//
+//	src, _ := decode.RTSPSource(ctx, client, 0, reolink.StreamSub)
+//	relay := hls.NewRelay(src, &hls.FFmpegSegmenter{})
+//	go relay.Run(ctx)
+//	http.Handle("/live/", http.StripPrefix("/live", relay))
+//	// <video src="/live/index.m3u8">
+//
+// FFmpegSegmenter, which runs the ffmpeg binary, is built with the
+// reolink_ffmpeg tag.
+package hls
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mosleyit/reolink_api_wrapper/pkg/decode"
+)
+
+// Playlist is the name of the playlist a Segmenter writes
+const Playlist = "index.m3u8"
+
+// stableRun is how long a Segmenter must run for its failure not to count
+// towards Relay.MaxRestarts
+const stableRun = 30 * time.Second
+
+// Segmenter writes the HLS playlist Playlist and its segments for src into
+// dir until ctx is done or the stream fails
+type Segmenter interface {
+	Segment(ctx context.Context, src decode.Source, dir string) error
+}
+
+// Relay runs a Segmenter and serves its output
+type Relay struct {
+	// Source is the stream to relay
+	Source decode.Source
+	// Segmenter produces the playlist and segments
+	Segmenter Segmenter
+	// Dir holds the playlist and segments (default: a temporary directory
+	// removed when Run returns)
+	Dir string
+	// MaxRestarts is how often the Segmenter is restarted in a row after
+	// failing quickly before Run gives up (default: 3)
+	MaxRestarts int
+
+	mu  sync.RWMutex
+	dir string // Directory being served, "" while not running
+}
+
+// NewRelay returns a Relay for src
+func NewRelay(src decode.Source, segmenter Segmenter) *Relay {
+	return &Relay{Source: src, Segmenter: segmenter}
+}
+
+// Run segments the stream until ctx is done, restarting the Segmenter with
+// a refreshed source when the stream fails. It returns ctx.Err(), or an
+// error if the Segmenter keeps failing.
+func (r *Relay) Run(ctx context.Context) error {
+	if r.Segmenter == nil {
+		return fmt.Errorf("segmenter must not be nil")
+	}
+	maxRestarts := r.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = 3
+	}
+
+	dir := r.Dir
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "reolink-hls-")
+		if err != nil {
+			return fmt.Errorf("failed to create segment directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create segment directory: %w", err)
+	}
+	r.mu.Lock()
+	r.dir = dir
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.dir = ""
+		r.mu.Unlock()
+	}()
+
+	src := r.Source
+	failures := 0
+	for {
+		started := time.Now()
+		err := r.Segmenter.Segment(ctx, src, dir)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if time.Since(started) >= stableRun {
+			failures = 0
+		}
+		failures++
+		if failures > maxRestarts {
+			return fmt.Errorf("segmenter failed %d times in a row: %w", failures, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(failures) * time.Second):
+		}
+		if src.Refresh != nil {
+			if fresh, err := src.Refresh(ctx); err == nil {
+				src = fresh
+			}
+		}
+	}
+}
+
+// contentTypes are the files Relay serves, by extension
+var contentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+	".m4s":  "video/iso.segment",
+	".mp4":  "video/mp4",
+}
+
+// ServeHTTP serves the playlist and segments by file name, whatever the
+// path prefix. Before the first playlist is written it answers 503 with
+// Retry-After so players try again.
+func (r *Relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := path.Base(req.URL.Path)
+	contentType, ok := contentTypes[path.Ext(name)]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	r.mu.RLock()
+	dir := r.dir
+	r.mu.RUnlock()
+	file := filepath.Join(dir, name)
+	if dir == "" || (name == Playlist && !exists(file)) {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "stream not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if name == Playlist {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.Write(data)
+}
+
+// exists reports whether file exists
+func exists(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
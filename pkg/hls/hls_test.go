@@ -0,0 +1,98 @@
+package hls
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mosleyit/reolink_api_wrapper/pkg/decode"
+)
+
+// segmenterFunc adapts a function to Segmenter
+type segmenterFunc func(ctx context.Context, src decode.Source, dir string) error
+
+func (f segmenterFunc) Segment(ctx context.Context, src decode.Source, dir string) error {
+	return f(ctx, src, dir)
+}
+
+func TestRelay_ServeHTTP(t *testing.T) {
+	written := make(chan string, 1)
+	relay := NewRelay(decode.Source{URL: "rtsp://camera/Preview_01_sub"}, segmenterFunc(func(ctx context.Context, src decode.Source, dir string) error {
+		os.WriteFile(filepath.Join(dir, "segment0.ts"), []byte("ts"), 0o644)
+		os.WriteFile(filepath.Join(dir, Playlist), []byte("#EXTM3U\n"), 0o644)
+		written <- dir
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		relay.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	if rec := get("/live/index.m3u8"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("before Run: status %d, want 503", rec.Code)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- relay.Run(ctx) }()
+	dir := <-written
+
+	rec := get("/live/index.m3u8")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/vnd.apple.mpegurl" || rec.Body.String() != "#EXTM3U\n" {
+		t.Errorf("playlist: %d %s %q", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	if rec := get("/live/segment0.ts"); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "video/mp2t" {
+		t.Errorf("segment: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	for _, path := range []string{"/live/segment9.ts", "/live/../../etc/passwd", "/live/notes.txt"} {
+		if rec := get(path); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status %d, want 404", path, rec.Code)
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("temporary directory %s was not removed", dir)
+	}
+}
+
+func TestRelay_RunRestarts(t *testing.T) {
+	runs := 0
+	refreshed := 0
+	src := decode.Source{
+		URL: "rtsp://camera/old",
+		Refresh: func(context.Context) (decode.Source, error) {
+			refreshed++
+			return decode.Source{URL: "rtsp://camera/new"}, nil
+		},
+	}
+	var urls []string
+	relay := NewRelay(src, segmenterFunc(func(ctx context.Context, src decode.Source, dir string) error {
+		runs++
+		urls = append(urls, src.URL)
+		return errors.New("stream failed")
+	}))
+	relay.MaxRestarts = 1
+	relay.Dir = t.TempDir()
+
+	start := time.Now()
+	if err := relay.Run(t.Context()); err == nil {
+		t.Fatal("expected error after repeated failures")
+	}
+	if runs != 2 || refreshed != 1 || urls[1] != "rtsp://camera/new" {
+		t.Errorf("runs=%d refreshed=%d urls=%v", runs, refreshed, urls)
+	}
+	if time.Since(start) < time.Second {
+		t.Error("expected a back-off before restarting")
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -20,6 +21,25 @@ type Logger interface {
 	Error(msg string, args ...interface{})
 }
 
+// LoggerWithContext is implemented by loggers that can include
+// request-scoped values from a context, such as trace or tenant IDs, in
+// their messages. The client calls WithContext with the context passed to
+// each API method.
+type LoggerWithContext interface {
+	Logger
+	// WithContext returns a logger that includes values from ctx.
+	WithContext(ctx context.Context) Logger
+}
+
+// FromContext returns l.WithContext(ctx) if l implements LoggerWithContext,
+// and l otherwise.
+func FromContext(ctx context.Context, l Logger) Logger {
+	if lc, ok := l.(LoggerWithContext); ok {
+		return lc.WithContext(ctx)
+	}
+	return l
+}
+
 // NoOpLogger is a logger that does nothing.
 // This is the default logger used by the client.
 type NoOpLogger struct{}
@@ -177,3 +197,93 @@ func (l *LevelLogger) Error(msg string, args ...interface{}) {
 		l.logger.Error(msg, args...)
 	}
 }
+
+// WithContext returns a LevelLogger with the same level wrapping the
+// underlying logger's context-aware logger, if it has one.
+func (l *LevelLogger) WithContext(ctx context.Context) Logger {
+	return &LevelLogger{
+		level:  l.level,
+		logger: FromContext(ctx, l.logger),
+	}
+}
+
+// ContextLogger is a logger that prefixes messages with a string derived
+// from the context, e.g. a request ID.
+type ContextLogger struct {
+	logger Logger
+	prefix func(ctx context.Context) string
+}
+
+// NewContextLogger creates a new ContextLogger. prefix extracts the string
+// to prepend from a context; messages are logged unchanged if it returns "".
+func NewContextLogger(logger Logger, prefix func(ctx context.Context) string) *ContextLogger {
+	return &ContextLogger{
+		logger: logger,
+		prefix: prefix,
+	}
+}
+
+// WithContext returns a logger that prefixes messages with the value
+// extracted from ctx.
+func (l *ContextLogger) WithContext(ctx context.Context) Logger {
+	prefix := l.prefix(ctx)
+	if prefix == "" {
+		return l.logger
+	}
+	return &prefixLogger{prefix: prefix, logger: l.logger}
+}
+
+// Debug logs a debug message without a context prefix.
+func (l *ContextLogger) Debug(msg string, args ...interface{}) {
+	l.logger.Debug(msg, args...)
+}
+
+// Info logs an informational message without a context prefix.
+func (l *ContextLogger) Info(msg string, args ...interface{}) {
+	l.logger.Info(msg, args...)
+}
+
+// Warn logs a warning message without a context prefix.
+func (l *ContextLogger) Warn(msg string, args ...interface{}) {
+	l.logger.Warn(msg, args...)
+}
+
+// Error logs an error message without a context prefix.
+func (l *ContextLogger) Error(msg string, args ...interface{}) {
+	l.logger.Error(msg, args...)
+}
+
+// prefixLogger prepends a fixed prefix to every message.
+type prefixLogger struct {
+	prefix string
+	logger Logger
+}
+
+// format returns msg and args with the prefix prepended. The prefix is
+// passed as an argument so that '%' in it is not treated as a verb.
+func (l *prefixLogger) format(msg string, args []interface{}) (string, []interface{}) {
+	if len(args) == 0 {
+		return l.prefix + " " + msg, nil
+	}
+	return "%s " + msg, append([]interface{}{l.prefix}, args...)
+}
+
+func (l *prefixLogger) Debug(msg string, args ...interface{}) {
+	msg, args = l.format(msg, args)
+	l.logger.Debug(msg, args...)
+}
+
+func (l *prefixLogger) Info(msg string, args ...interface{}) {
+	msg, args = l.format(msg, args)
+	l.logger.Info(msg, args...)
+}
+
+func (l *prefixLogger) Warn(msg string, args ...interface{}) {
+	msg, args = l.format(msg, args)
+	l.logger.Warn(msg, args...)
+}
+
+func (l *prefixLogger) Error(msg string, args ...interface{}) {
+	msg, args = l.format(msg, args)
+	l.logger.Error(msg, args...)
+}
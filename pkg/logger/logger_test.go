@@ -2,6 +2,7 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 )
@@ -207,3 +208,67 @@ func TestLevelLoggerWithArgs(t *testing.T) {
 		t.Errorf("expected formatted message, got: %s", buf.String())
 	}
 }
+
+type requestIDKey struct{}
+
+func requestIDPrefix(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	if id == "" {
+		return ""
+	}
+	return "req=" + id
+}
+
+func TestContextLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewContextLogger(NewStdLogger(buf), requestIDPrefix)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc-100%")
+	FromContext(ctx, logger).Info("login %s", "ok")
+	if !strings.Contains(buf.String(), "req=abc-100% login ok") {
+		t.Errorf("expected prefixed message, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	FromContext(ctx, logger).Warn("no args")
+	if !strings.Contains(buf.String(), "req=abc-100% no args") {
+		t.Errorf("expected prefixed message without args, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	FromContext(context.Background(), logger).Error("plain %d", 1)
+	if !strings.Contains(buf.String(), "[ERROR]") || strings.Contains(buf.String(), "req=") {
+		t.Errorf("expected unprefixed message, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Debug("direct")
+	if !strings.Contains(buf.String(), "direct") || strings.Contains(buf.String(), "req=") {
+		t.Errorf("expected unprefixed message, got: %s", buf.String())
+	}
+}
+
+func TestFromContextPlainLogger(t *testing.T) {
+	logger := NewStdLogger(&bytes.Buffer{})
+	if FromContext(context.Background(), logger) != Logger(logger) {
+		t.Error("expected plain logger to be returned unchanged")
+	}
+}
+
+func TestLevelLoggerWithContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewLevelLogger(LogLevelWarn, NewContextLogger(NewStdLogger(buf), requestIDPrefix))
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "xyz")
+	ctxLogger := FromContext(ctx, logger)
+
+	ctxLogger.Info("filtered")
+	if buf.Len() != 0 {
+		t.Errorf("expected info to be filtered, got: %s", buf.String())
+	}
+
+	ctxLogger.Warn("kept")
+	if !strings.Contains(buf.String(), "req=xyz kept") {
+		t.Errorf("expected prefixed warning, got: %s", buf.String())
+	}
+}
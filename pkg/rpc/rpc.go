@@ -0,0 +1,337 @@
+// Package rpc implements the services of proto/reolink/v1/reolink.proto on
+// top of a reolink.Fleet, so non-Go services can reuse the camera
+// connections, sessions and quirk handling of the SDK.
+//
+// The package does not depend on gRPC, keeping the SDK free of third-party
+// modules. The gRPC server is pkg/grpcserver, a separate module with the
+// generated stubs, which forwards each RPC to the Service method of the
+// same name and converts errors with Code:
+//
+//	server := grpc.NewServer()
+//	grpcserver.Register(server, rpc.NewService(fleet))
+//
+// Service can also back other transports, e.g. a Connect or JSON-RPC
+// server, by converting its errors with Code the same way.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	reolink "github.com/mosleyit/reolink_api_wrapper"
+)
+
+// ErrUnknownCamera is returned for cameras not in the fleet
+var ErrUnknownCamera = errors.New("unknown camera")
+
+// ErrInvalidArgument is returned for malformed requests
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// CameraRef addresses a channel of a fleet camera
+type CameraRef struct {
+	Camera  string // Fleet name
+	Channel int
+}
+
+// DeviceInfo is the reply of GetDeviceInfo
+type DeviceInfo struct {
+	Model           string
+	Name            string
+	Serial          string
+	FirmwareVersion string
+	HardwareVersion string
+	ChannelCount    int
+}
+
+// PtzRequest is the request of Control
+type PtzRequest struct {
+	Target   CameraRef
+	Op       string // One of the reolink.PTZOp* operations
+	Speed    int    // 1-64, 0 for the camera's default
+	PresetID int
+}
+
+// PtzPreset is a preset of ListPresets
+type PtzPreset struct {
+	ID      int
+	Name    string
+	Enabled bool
+}
+
+// AlarmState is the reply of GetAlarmState
+type AlarmState struct {
+	Motion bool
+	AI     map[string]bool // AI type to alarm state, empty without AI support
+}
+
+// WatchEventsRequest is the request of WatchEvents
+type WatchEventsRequest struct {
+	Target  CameraRef
+	Profile string // "aggressive", "balanced" (default) or "battery_saver"
+}
+
+// Event is a message of the WatchEvents stream
+type Event struct {
+	Camera       string
+	Type         string
+	Channel      int
+	TimeUnixNano int64
+	Detail       string
+}
+
+// StreamURLs is the reply of GetStreamURLs
+type StreamURLs struct {
+	MainRTSP      string
+	SubRTSP       string
+	MainRTMP      string
+	SubRTMP       string
+	MainFLV       string
+	SubFLV        string
+	RTSPTransport string
+}
+
+// Service implements the RPCs against the cameras of Fleet
+type Service struct {
+	Fleet *reolink.Fleet
+}
+
+// NewService creates a Service for fleet
+func NewService(fleet *reolink.Fleet) *Service {
+	return &Service{Fleet: fleet}
+}
+
+// client returns the client of the referenced camera
+func (s *Service) client(ref CameraRef) (*reolink.Client, error) {
+	if ref.Channel < 0 {
+		return nil, fmt.Errorf("%w: negative channel", ErrInvalidArgument)
+	}
+	c, ok := s.Fleet.Get(ref.Camera)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCamera, ref.Camera)
+	}
+	return c, nil
+}
+
+// GetDeviceInfo implements SystemService.GetDeviceInfo
+func (s *Service) GetDeviceInfo(ctx context.Context, ref CameraRef) (*DeviceInfo, error) {
+	c, err := s.client(ref)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.System.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &DeviceInfo{
+		Model:           info.Model,
+		Name:            info.Name,
+		Serial:          info.Serial,
+		FirmwareVersion: info.FirmVer,
+		HardwareVersion: info.HardVer,
+		ChannelCount:    info.ChannelNum,
+	}, nil
+}
+
+// Reboot implements SystemService.Reboot
+func (s *Service) Reboot(ctx context.Context, ref CameraRef) error {
+	c, err := s.client(ref)
+	if err != nil {
+		return err
+	}
+	return c.System.Reboot(ctx)
+}
+
+// Control implements PTZService.Control
+func (s *Service) Control(ctx context.Context, req PtzRequest) error {
+	c, err := s.client(req.Target)
+	if err != nil {
+		return err
+	}
+	if req.Op == "" {
+		return fmt.Errorf("%w: missing PTZ operation", ErrInvalidArgument)
+	}
+	if req.Speed < 0 || req.Speed > 64 {
+		return fmt.Errorf("%w: speed must be between 0 and 64, 0 for the camera's default", ErrInvalidArgument)
+	}
+	return c.PTZ.PtzCtrl(ctx, reolink.PtzCtrlParam{
+		Channel: req.Target.Channel,
+		Op:      req.Op,
+		Speed:   req.Speed,
+		ID:      req.PresetID,
+	})
+}
+
+// ListPresets implements PTZService.ListPresets
+func (s *Service) ListPresets(ctx context.Context, ref CameraRef) ([]PtzPreset, error) {
+	c, err := s.client(ref)
+	if err != nil {
+		return nil, err
+	}
+	presets, err := c.PTZ.GetPtzPreset(ctx, ref.Channel)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PtzPreset, 0, len(presets))
+	for _, p := range presets {
+		out = append(out, PtzPreset{ID: p.ID, Name: p.Name, Enabled: p.Enable.Bool()})
+	}
+	return out, nil
+}
+
+// GetAlarmState implements AlarmService.GetAlarmState. AI states are left
+// empty on cameras without AI detection.
+func (s *Service) GetAlarmState(ctx context.Context, ref CameraRef) (*AlarmState, error) {
+	c, err := s.client(ref)
+	if err != nil {
+		return nil, err
+	}
+	md, err := c.Alarm.GetMdState(ctx, ref.Channel)
+	if err != nil {
+		return nil, err
+	}
+	state := &AlarmState{Motion: md == 1, AI: make(map[string]bool)}
+
+	ai, err := c.AI.GetAiState(ctx, ref.Channel)
+	switch {
+	case reolink.IsNotSupported(err):
+	case err != nil:
+		return nil, err
+	default:
+		for _, name := range ai.Supported() {
+			state.AI[name] = ai.Get(name).AlarmState == 1
+		}
+	}
+	return state, nil
+}
+
+// WatchEvents implements the AlarmService.WatchEvents stream: it calls send
+// for every motion and AI event until ctx is done, returning ctx.Err(), or
+// until send fails, returning its error
+func (s *Service) WatchEvents(ctx context.Context, req WatchEventsRequest, send func(Event) error) error {
+	c, err := s.client(req.Target)
+	if err != nil {
+		return err
+	}
+	var profile reolink.PollingProfile
+	switch req.Profile {
+	case "aggressive":
+		profile = reolink.PollingAggressive()
+	case "", "balanced":
+		profile = reolink.PollingBalanced()
+	case "battery_saver":
+		profile = reolink.PollingBatterySaver()
+	default:
+		return fmt.Errorf("%w: unknown polling profile %q", ErrInvalidArgument, req.Profile)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var sendErr error
+	err = c.Alarm.WatchEventsProfile(ctx, req.Target.Channel, profile, func(ev reolink.Event) {
+		if sendErr != nil {
+			return
+		}
+		sendErr = send(Event{
+			Camera:       req.Target.Camera,
+			Type:         string(ev.Type),
+			Channel:      ev.Channel,
+			TimeUnixNano: ev.Time.UnixNano(),
+			Detail:       ev.Detail,
+		})
+		if sendErr != nil {
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	return err
+}
+
+// GetStreamURLs implements StreamingService.GetStreamURLs
+func (s *Service) GetStreamURLs(ctx context.Context, ref CameraRef) (*StreamURLs, error) {
+	c, err := s.client(ref)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.Streaming.GetStreamInfo(ctx, ref.Channel)
+	if info == nil {
+		return nil, err
+	}
+	// Partial information (a *MultiError) still has the URLs
+	return &StreamURLs{
+		MainRTSP:      info.MainRTSPURL,
+		SubRTSP:       info.SubRTSPURL,
+		MainRTMP:      info.MainRTMPURL,
+		SubRTMP:       info.SubRTMPURL,
+		MainFLV:       c.Streaming.GetFLVURL(reolink.StreamMain, ref.Channel),
+		SubFLV:        c.Streaming.GetFLVURL(reolink.StreamSub, ref.Channel),
+		RTSPTransport: string(info.RTSPTransport),
+	}, nil
+}
+
+// StatusCode is a gRPC status code. The values are those of
+// google.golang.org/grpc/codes, so codes.Code(Code(err)) converts them.
+type StatusCode uint32
+
+// Status codes returned by Code
+const (
+	CodeOK                 StatusCode = 0
+	CodeCanceled           StatusCode = 1
+	CodeUnknown            StatusCode = 2
+	CodeInvalidArgument    StatusCode = 3
+	CodeDeadlineExceeded   StatusCode = 4
+	CodeNotFound           StatusCode = 5
+	CodePermissionDenied   StatusCode = 7
+	CodeFailedPrecondition StatusCode = 9
+	CodeUnimplemented      StatusCode = 12
+	CodeUnavailable        StatusCode = 14
+	CodeUnauthenticated    StatusCode = 16
+)
+
+// Code maps an error of the Service or the SDK to a gRPC status code:
+// unknown cameras are NotFound, commands the camera does not support
+// Unimplemented, rejected credentials Unauthenticated, missing permissions
+// and commands refused by the client's Policy PermissionDenied, and
+// sleeping or unreachable cameras Unavailable
+func Code(err error) StatusCode {
+	var (
+		apiErr *reolink.APIError
+		netErr net.Error
+	)
+	switch {
+	case err == nil:
+		return CodeOK
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded
+	case errors.Is(err, ErrUnknownCamera):
+		return CodeNotFound
+	case errors.Is(err, ErrInvalidArgument):
+		return CodeInvalidArgument
+	case reolink.IsNotSupported(err):
+		return CodeUnimplemented
+	case errors.Is(err, reolink.ErrCameraAsleep):
+		return CodeUnavailable
+	case errors.Is(err, reolink.ErrForbiddenByPolicy):
+		return CodePermissionDenied
+	case errors.As(err, &apiErr):
+		switch apiErr.RspCode {
+		case reolink.ErrCodeLoginRequired, reolink.ErrCodeLoginError, reolink.ErrCodeTokenError, reolink.ErrCodeInvalidUser:
+			return CodeUnauthenticated
+		case reolink.ErrCodeAbilityError:
+			return CodePermissionDenied
+		case reolink.ErrCodeMissingParameters, reolink.ErrCodeMissingParametersAlt, reolink.ErrCodeParametersError, reolink.ErrCodeStringLengthExceeded:
+			return CodeInvalidArgument
+		case reolink.ErrCodeUpgradeBusy:
+			return CodeFailedPrecondition
+		}
+		return CodeUnknown
+	case errors.As(err, &netErr):
+		return CodeUnavailable
+	}
+	return CodeUnknown
+}
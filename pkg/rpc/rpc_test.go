@@ -0,0 +1,230 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	reolink "github.com/mosleyit/reolink_api_wrapper"
+)
+
+// camera is a fake camera answering commands with canned values; unknown
+// commands are not supported
+type camera struct {
+	mu     sync.Mutex
+	values map[string]string
+	calls  map[string][]json.RawMessage
+}
+
+func newTestService(t *testing.T, values map[string]string) (*Service, *camera) {
+	t.Helper()
+	cam := &camera{values: values, calls: make(map[string][]json.RawMessage)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			Cmd   string          `json:"cmd"`
+			Param json.RawMessage `json:"param"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cam.mu.Lock()
+		defer cam.mu.Unlock()
+		var resps []string
+		for _, req := range reqs {
+			cam.calls[req.Cmd] = append(cam.calls[req.Cmd], req.Param)
+			value, ok := cam.values[req.Cmd]
+			if !ok {
+				resps = append(resps, fmt.Sprintf(`{"cmd": %q, "code": 1, "error": {"rspCode": -9, "detail": "not support"}}`, req.Cmd))
+				continue
+			}
+			resps = append(resps, fmt.Sprintf(`{"cmd": %q, "code": 0, "value": %s}`, req.Cmd, value))
+		}
+		w.Write([]byte("[" + strings.Join(resps, ",") + "]"))
+	}))
+	t.Cleanup(server.Close)
+
+	client := reolink.NewClient(strings.TrimPrefix(server.URL, "http://"), reolink.WithCredentials("admin", "secret"))
+	client.SetToken("test-token")
+	fleet := reolink.NewFleet()
+	fleet.Add("porch", client)
+	return NewService(fleet), cam
+}
+
+func (c *camera) set(cmd, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[cmd] = value
+}
+
+func (c *camera) count(cmd string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.calls[cmd])
+}
+
+func (c *camera) lastParam(cmd string) json.RawMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := c.calls[cmd]
+	if len(calls) == 0 {
+		return nil
+	}
+	return calls[len(calls)-1]
+}
+
+func TestService_System(t *testing.T) {
+	svc, cam := newTestService(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A", "name": "Porch", "serial": "S1", "firmVer": "v3.1.0", "channelNum": 1}}`,
+		"Reboot":     `{"rspCode": 200}`,
+	})
+	ref := CameraRef{Camera: "porch"}
+
+	info, err := svc.GetDeviceInfo(t.Context(), ref)
+	if err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if info.Model != "RLC-811A" || info.FirmwareVersion != "v3.1.0" || info.ChannelCount != 1 {
+		t.Errorf("unexpected device info %+v", info)
+	}
+	if err := svc.Reboot(t.Context(), ref); err != nil {
+		t.Errorf("Reboot failed: %v", err)
+	}
+	if cam.count("Reboot") != 1 {
+		t.Error("Reboot not sent")
+	}
+
+	_, err = svc.GetDeviceInfo(t.Context(), CameraRef{Camera: "garage"})
+	if !errors.Is(err, ErrUnknownCamera) || Code(err) != CodeNotFound {
+		t.Errorf("expected NotFound for unknown camera, got %v", err)
+	}
+}
+
+func TestService_PTZ(t *testing.T) {
+	svc, cam := newTestService(t, map[string]string{
+		"PtzCtrl":      `{"rspCode": 200}`,
+		"GetPtzPreset": `{"PtzPreset": [{"channel": 0, "enable": 1, "id": 1, "name": "Gate"}]}`,
+	})
+	ref := CameraRef{Camera: "porch"}
+
+	if err := svc.Control(t.Context(), PtzRequest{Target: ref, Op: reolink.PTZOpToPos, PresetID: 1, Speed: 32}); err != nil {
+		t.Fatalf("Control failed: %v", err)
+	}
+	var param reolink.PtzCtrlParam
+	if err := json.Unmarshal(cam.lastParam("PtzCtrl"), &param); err != nil || param.Op != "ToPos" || param.ID != 1 || param.Speed != 32 {
+		t.Errorf("unexpected PtzCtrl param %+v (%v)", param, err)
+	}
+	if err := svc.Control(t.Context(), PtzRequest{Target: ref}); Code(err) != CodeInvalidArgument {
+		t.Errorf("expected InvalidArgument without op, got %v", err)
+	}
+	if err := svc.Control(t.Context(), PtzRequest{Target: ref, Op: reolink.PTZOpLeft}); err != nil {
+		t.Errorf("Control with the default speed failed: %v", err)
+	}
+	if err := svc.Control(t.Context(), PtzRequest{Target: ref, Op: reolink.PTZOpLeft, Speed: 65}); Code(err) != CodeInvalidArgument || !strings.Contains(err.Error(), "between 0 and 64") {
+		t.Errorf("expected InvalidArgument for speed 65, got %v", err)
+	}
+
+	presets, err := svc.ListPresets(t.Context(), ref)
+	if err != nil {
+		t.Fatalf("ListPresets failed: %v", err)
+	}
+	if len(presets) != 1 || presets[0].Name != "Gate" || !presets[0].Enabled {
+		t.Errorf("unexpected presets %+v", presets)
+	}
+}
+
+func TestService_Alarm(t *testing.T) {
+	svc, cam := newTestService(t, map[string]string{
+		"GetMdState": `{"state": 1}`,
+	})
+	ref := CameraRef{Camera: "porch"}
+
+	state, err := svc.GetAlarmState(t.Context(), ref)
+	if err != nil {
+		t.Fatalf("GetAlarmState failed: %v", err)
+	}
+	if !state.Motion || len(state.AI) != 0 {
+		t.Errorf("unexpected state %+v", state)
+	}
+
+	cam.set("GetAiState", `{"channel": 0, "people": {"alarm_state": 1, "support": 1}}`)
+	state, err = svc.GetAlarmState(t.Context(), ref)
+	if err != nil || !state.AI["people"] {
+		t.Errorf("expected people alarm, got %+v (%v)", state, err)
+	}
+}
+
+func TestService_WatchEvents(t *testing.T) {
+	svc, cam := newTestService(t, map[string]string{
+		"GetMdState": `{"state": 0}`,
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	errSent := errors.New("stream closed")
+	go func() {
+		for cam.count("GetMdState") == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cam.set("GetMdState", `{"state": 1}`)
+	}()
+	var got []Event
+	err := svc.WatchEvents(ctx, WatchEventsRequest{Target: CameraRef{Camera: "porch"}, Profile: "aggressive"}, func(ev Event) error {
+		got = append(got, ev)
+		return errSent
+	})
+	if !errors.Is(err, errSent) {
+		t.Errorf("expected the send error, got %v", err)
+	}
+	if len(got) != 1 || got[0].Type != "motion_start" || got[0].Camera != "porch" {
+		t.Errorf("unexpected events %+v", got)
+	}
+
+	err = svc.WatchEvents(ctx, WatchEventsRequest{Target: CameraRef{Camera: "porch"}, Profile: "turbo"}, nil)
+	if Code(err) != CodeInvalidArgument {
+		t.Errorf("expected InvalidArgument for unknown profile, got %v", err)
+	}
+}
+
+func TestService_GetStreamURLs(t *testing.T) {
+	svc, _ := newTestService(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"rtspEnable": 1, "rtspPort": 554, "rtmpEnable": 1, "rtmpPort": 1935}}`,
+	})
+	urls, err := svc.GetStreamURLs(t.Context(), CameraRef{Camera: "porch"})
+	if err != nil {
+		t.Fatalf("GetStreamURLs failed: %v", err)
+	}
+	if !strings.HasSuffix(urls.MainRTSP, "/Preview_01_main") || !strings.Contains(urls.SubFLV, "channel0_sub.bcs") {
+		t.Errorf("unexpected URLs %+v", urls)
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want StatusCode
+	}{
+		{nil, CodeOK},
+		{context.Canceled, CodeCanceled},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), CodeDeadlineExceeded},
+		{reolink.NewAPIError("GetAiState", 1, reolink.ErrCodeNotSupported, ""), CodeUnimplemented},
+		{reolink.NewAPIError("Login", 1, reolink.ErrCodeLoginError, ""), CodeUnauthenticated},
+		{reolink.NewAPIError("SetOsd", 1, reolink.ErrCodeAbilityError, ""), CodePermissionDenied},
+		{fmt.Errorf("%w: dial", reolink.ErrCameraAsleep), CodeUnavailable},
+		{fmt.Errorf("%w: Reboot", reolink.ErrForbiddenByPolicy), CodePermissionDenied},
+		{errors.New("boom"), CodeUnknown},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("Code(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
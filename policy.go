@@ -0,0 +1,117 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrForbiddenByPolicy is returned for commands a Policy does not allow.
+// The command is not sent to the camera.
+var ErrForbiddenByPolicy = errors.New("command forbidden by policy")
+
+// DestructiveCommands are the commands PolicyNoDestructive denies: they
+// restart the camera, erase recordings, users or settings, replace the
+// firmware, or unbind NVR channels and the cloud account. SetCloud is
+// denied as a whole since unbinding is its only use in the SDK.
+var DestructiveCommands = []string{
+	"Reboot", "Format", "Restore",
+	"Upgrade", "UpgradePrepare", "UpgradeOnline",
+	"DelRec", "DelUser", "DelFace", "CertificateClear",
+	"DelIpc", "SetCloud",
+}
+
+// readCommands are the commands besides Get* that only read state
+var readCommands = []string{
+	"Search", "SearchFaceMatch", "Snap", "Download", "NvrDownload", "Playback",
+	"CheckFirmware", "UpgradeStatus", "ScanWifi",
+}
+
+// Policy restricts the commands a client may execute, for tools shared by
+// operators who should not all be able to change settings or reboot
+// cameras. Login and Logout are always allowed so the session keeps
+// working. The zero Policy allows everything.
+type Policy struct {
+	ReadOnly bool     // Allow only commands reading state: Get*, Search, Snap, Download and the like
+	Allow    []string // If not empty, allow only these commands
+	Deny     []string // Never allow these commands
+}
+
+// PolicyReadOnly allows only commands that read state
+func PolicyReadOnly() Policy {
+	return Policy{ReadOnly: true}
+}
+
+// PolicyNoDestructive allows everything but the DestructiveCommands
+func PolicyNoDestructive() Policy {
+	return Policy{Deny: slices.Clone(DestructiveCommands)}
+}
+
+// Allows reports whether p allows cmd
+func (p Policy) Allows(cmd string) bool {
+	switch {
+	case cmd == "Login" || cmd == "Logout":
+		return true
+	case slices.Contains(p.Deny, cmd):
+		return false
+	case len(p.Allow) > 0 && !slices.Contains(p.Allow, cmd):
+		return false
+	case p.ReadOnly:
+		return strings.HasPrefix(cmd, "Get") || slices.Contains(readCommands, cmd)
+	}
+	return true
+}
+
+// WithPolicy restricts the commands the client executes to those policy
+// allows; others fail with ErrForbiddenByPolicy
+func WithPolicy(policy Policy) Option {
+	return func(c *Client) {
+		c.policy = &policy
+	}
+}
+
+// policyKey is the context key for call policies
+type policyKey struct{}
+
+// WithCallPolicy returns a context that restricts the API calls made with
+// it to the commands policy allows, on top of the client's own policy. It
+// lets a client shared by several operators apply each operator's
+// permissions, e.g. set by the HTTP middleware that authenticated them.
+func WithCallPolicy(ctx context.Context, policy Policy) context.Context {
+	policies, _ := ctx.Value(policyKey{}).([]Policy)
+	return context.WithValue(ctx, policyKey{}, append(slices.Clip(policies), policy))
+}
+
+// checkPolicy returns ErrForbiddenByPolicy if the client's policy or a call
+// policy of ctx does not allow one of cmds
+func (c *Client) checkPolicy(ctx context.Context, cmds ...string) error {
+	policies, _ := ctx.Value(policyKey{}).([]Policy)
+	if c.policy == nil && len(policies) == 0 {
+		return nil
+	}
+	for _, cmd := range cmds {
+		allowed := c.policy == nil || c.policy.Allows(cmd)
+		for _, p := range policies {
+			allowed = allowed && p.Allows(cmd)
+		}
+		if !allowed {
+			c.log(ctx).Warn("command refused by policy: cmd=%s", cmd)
+			return fmt.Errorf("%w: %s", ErrForbiddenByPolicy, cmd)
+		}
+	}
+	return nil
+}
+
+// checkRequestPolicy is checkPolicy for the commands of requests
+func (c *Client) checkRequestPolicy(ctx context.Context, requests []Request) error {
+	if c.policy == nil && ctx.Value(policyKey{}) == nil {
+		return nil
+	}
+	cmds := make([]string, len(requests))
+	for i, req := range requests {
+		cmds[i] = req.Cmd
+	}
+	return c.checkPolicy(ctx, cmds...)
+}
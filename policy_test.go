@@ -0,0 +1,98 @@
+package reolink
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicy_Allows(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		cmd    string
+		want   bool
+	}{
+		{"zero allows all", Policy{}, "Reboot", true},
+		{"read only get", PolicyReadOnly(), "GetDevInfo", true},
+		{"read only search", PolicyReadOnly(), "Search", true},
+		{"read only set", PolicyReadOnly(), "SetOsd", false},
+		{"read only ptz", PolicyReadOnly(), "PtzCtrl", false},
+		{"read only login", PolicyReadOnly(), "Login", true},
+		{"no destructive reboot", PolicyNoDestructive(), "Reboot", false},
+		{"no destructive format", PolicyNoDestructive(), "Format", false},
+		{"no destructive nvr unbind", PolicyNoDestructive(), "DelIpc", false},
+		{"no destructive cloud unbind", PolicyNoDestructive(), "SetCloud", false},
+		{"no destructive set", PolicyNoDestructive(), "SetOsd", true},
+		{"allow list", Policy{Allow: []string{"PtzCtrl"}}, "PtzCtrl", true},
+		{"allow list miss", Policy{Allow: []string{"PtzCtrl"}}, "SetOsd", false},
+		{"allow list logout", Policy{Allow: []string{"PtzCtrl"}}, "Logout", true},
+		{"deny beats allow", Policy{Allow: []string{"Reboot"}, Deny: []string{"Reboot"}}, "Reboot", false},
+		{"read only and allow", Policy{ReadOnly: true, Allow: []string{"SetOsd", "GetOsd"}}, "SetOsd", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Allows(tt.cmd); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.cmd, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPolicy(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A"}}`,
+		"Reboot":     "",
+	})
+	client := server.client()
+	WithPolicy(PolicyNoDestructive())(client)
+
+	if _, err := client.System.GetDeviceInfo(t.Context()); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if err := client.System.Reboot(t.Context()); !errors.Is(err, ErrForbiddenByPolicy) {
+		t.Errorf("expected ErrForbiddenByPolicy, got %v", err)
+	}
+	if server.callCount("Reboot") != 0 {
+		t.Error("forbidden command reached the camera")
+	}
+
+	// A batch with a forbidden command is refused as a whole
+	_, err := client.Batch(t.Context(), []Request{{Cmd: "GetDevInfo"}, {Cmd: "Reboot"}})
+	if !errors.Is(err, ErrForbiddenByPolicy) {
+		t.Errorf("expected ErrForbiddenByPolicy for batch, got %v", err)
+	}
+	if server.callCount("GetDevInfo") != 1 {
+		t.Errorf("expected the batch not to be sent, got %d GetDevInfo calls", server.callCount("GetDevInfo"))
+	}
+}
+
+func TestWithCallPolicy(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A"}}`,
+		"Reboot":     "",
+	})
+	client := server.client()
+
+	ctx := WithCallPolicy(t.Context(), PolicyReadOnly())
+	if _, err := client.System.GetDeviceInfo(ctx); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if err := client.System.Reboot(ctx); !errors.Is(err, ErrForbiddenByPolicy) {
+		t.Errorf("expected ErrForbiddenByPolicy, got %v", err)
+	}
+	// Snap is allowed; the fake camera does not serve images, so only the
+	// error kind matters
+	if _, err := client.Encoding.Snap(ctx, 0); errors.Is(err, ErrForbiddenByPolicy) {
+		t.Errorf("Snap refused by read-only policy: %v", err)
+	}
+
+	// Call policies add up
+	narrow := WithCallPolicy(ctx, Policy{Allow: []string{"GetTime"}})
+	if _, err := client.System.GetDeviceInfo(narrow); !errors.Is(err, ErrForbiddenByPolicy) {
+		t.Errorf("expected ErrForbiddenByPolicy from the inner policy, got %v", err)
+	}
+
+	if err := client.System.Reboot(t.Context()); err != nil {
+		t.Errorf("Reboot without call policy failed: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package reolink
+
+import (
+	"fmt"
+	"maps"
+	"time"
+)
+
+// QuietHours is a daily window in local time, from hour Start (inclusive)
+// to hour End (exclusive). It wraps around midnight when End <= Start, so
+// {22, 6} covers the night.
+type QuietHours struct {
+	Start int // 0-23
+	End   int // 0-23
+}
+
+// Contains reports whether t falls into the window
+func (q QuietHours) Contains(t time.Time) bool {
+	h := t.Hour()
+	if q.Start < q.End {
+		return h >= q.Start && h < q.End
+	}
+	return h >= q.Start || h < q.End
+}
+
+// PollingProfile tunes how often event watchers such as
+// Alarm.WatchEventsProfile poll each command. Intervals grow during quiet
+// hours and after a stretch without events, and polling stops while a
+// battery camera is asleep.
+type PollingProfile struct {
+	// Intervals is the base interval per command, e.g. "GetMdState"
+	Intervals map[string]time.Duration
+	// Default is the interval of commands missing from Intervals
+	Default time.Duration
+
+	// QuietHours, if set, multiplies intervals by QuietFactor within the
+	// window, e.g. at night when nothing is expected to happen
+	QuietHours  *QuietHours
+	QuietFactor float64
+
+	// IdleAfter, if positive, doubles intervals for every IdleAfter that
+	// passed without an event, up to MaxInterval. Intervals already longer
+	// than MaxInterval are left alone.
+	IdleAfter   time.Duration
+	MaxInterval time.Duration
+
+	// SkipAsleep stops polling while the client reports the camera asleep
+	// (see WithSleepHandling), so polls never keep a battery camera awake.
+	// Polling resumes once any request finds the camera awake again, e.g.
+	// one made after a push notification.
+	SkipAsleep bool
+}
+
+// PollingAggressive polls alarms twice a second around the clock, for
+// mains-powered cameras where fast events matter most
+func PollingAggressive() PollingProfile {
+	return PollingProfile{
+		Intervals: map[string]time.Duration{
+			"GetMdState": 500 * time.Millisecond,
+			"GetAiState": 500 * time.Millisecond,
+		},
+		Default: 5 * time.Second,
+	}
+}
+
+// PollingBalanced polls motion every second and AI every two, slowing to
+// at most every 10s after 10 minutes without events
+func PollingBalanced() PollingProfile {
+	return PollingProfile{
+		Intervals: map[string]time.Duration{
+			"GetMdState": time.Second,
+			"GetAiState": 2 * time.Second,
+		},
+		Default:     30 * time.Second,
+		QuietFactor: 2,
+		IdleAfter:   10 * time.Minute,
+		MaxInterval: 10 * time.Second,
+		SkipAsleep:  true,
+	}
+}
+
+// PollingBatterySaver polls rarely and not at all while the camera sleeps,
+// for battery cameras that mostly report events by push. Set QuietHours to
+// slow down further at night.
+func PollingBatterySaver() PollingProfile {
+	return PollingProfile{
+		Intervals: map[string]time.Duration{
+			"GetMdState": 5 * time.Second,
+			"GetAiState": 10 * time.Second,
+		},
+		Default:     5 * time.Minute,
+		QuietFactor: 4,
+		IdleAfter:   2 * time.Minute,
+		MaxInterval: 2 * time.Minute,
+		SkipAsleep:  true,
+	}
+}
+
+// Validate checks that every command gets a positive interval
+func (p *PollingProfile) Validate() error {
+	if p.Default <= 0 {
+		return fmt.Errorf("default interval must be positive")
+	}
+	for cmd, d := range p.Intervals {
+		if d <= 0 {
+			return fmt.Errorf("interval of %s must be positive", cmd)
+		}
+	}
+	if p.IdleAfter > 0 && p.MaxInterval <= 0 {
+		return fmt.Errorf("idle backoff needs a maximum interval")
+	}
+	if p.QuietHours != nil && (p.QuietHours.Start < 0 || p.QuietHours.Start > 23 || p.QuietHours.End < 0 || p.QuietHours.End > 23) {
+		return fmt.Errorf("quiet hours must be between 0 and 23")
+	}
+	return nil
+}
+
+// WithInterval returns a copy of the profile with cmd polled every d
+func (p PollingProfile) WithInterval(cmd string, d time.Duration) PollingProfile {
+	p.Intervals = maps.Clone(p.Intervals)
+	if p.Intervals == nil {
+		p.Intervals = make(map[string]time.Duration)
+	}
+	p.Intervals[cmd] = d
+	return p
+}
+
+// Interval returns how long to wait before polling cmd again at now, idle
+// being the time since the last event
+func (p PollingProfile) Interval(cmd string, now time.Time, idle time.Duration) time.Duration {
+	base, ok := p.Intervals[cmd]
+	if !ok {
+		base = p.Default
+	}
+	if p.QuietHours != nil && p.QuietFactor > 1 && p.QuietHours.Contains(now) {
+		base = time.Duration(float64(base) * p.QuietFactor)
+	}
+	if p.IdleAfter <= 0 || p.MaxInterval <= base {
+		return base
+	}
+	d := base
+	for n := idle / p.IdleAfter; n > 0 && d < p.MaxInterval; n-- {
+		d *= 2
+	}
+	return min(d, p.MaxInterval)
+}
@@ -0,0 +1,183 @@
+package reolink
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuietHours_Contains(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, 1, 1, hour, 30, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		hour  int
+		want  bool
+	}{
+		{"day window inside", QuietHours{9, 17}, 12, true},
+		{"day window end", QuietHours{9, 17}, 17, false},
+		{"night window late", QuietHours{22, 6}, 23, true},
+		{"night window early", QuietHours{22, 6}, 3, true},
+		{"night window day", QuietHours{22, 6}, 12, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Contains(at(tt.hour)); got != tt.want {
+				t.Errorf("Contains(%d:30) = %v, want %v", tt.hour, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollingProfile_Interval(t *testing.T) {
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	night := time.Date(2024, 1, 1, 2, 0, 0, 0, time.Local)
+
+	profile := PollingBatterySaver()
+	profile.QuietHours = &QuietHours{Start: 22, End: 6}
+
+	tests := []struct {
+		name string
+		cmd  string
+		now  time.Time
+		idle time.Duration
+		want time.Duration
+	}{
+		{"base", "GetMdState", noon, 0, 5 * time.Second},
+		{"default", "GetDevInfo", noon, 0, 5 * time.Minute},
+		{"quiet hours", "GetMdState", night, 0, 20 * time.Second},
+		{"idle", "GetMdState", noon, 5 * time.Minute, 20 * time.Second},
+		{"idle capped", "GetMdState", noon, time.Hour, 2 * time.Minute},
+		{"longer than max", "GetDevInfo", night, time.Hour, 20 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := profile.Interval(tt.cmd, tt.now, tt.idle); got != tt.want {
+				t.Errorf("Interval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := PollingAggressive().Interval("GetAiState", noon, time.Hour); got != 500*time.Millisecond {
+		t.Errorf("aggressive profile should not back off, got %v", got)
+	}
+}
+
+func TestPollingProfile_Validate(t *testing.T) {
+	for _, p := range []PollingProfile{PollingAggressive(), PollingBalanced(), PollingBatterySaver()} {
+		if err := p.Validate(); err != nil {
+			t.Errorf("preset invalid: %v", err)
+		}
+	}
+
+	invalid := []PollingProfile{
+		{},
+		{Default: time.Second, Intervals: map[string]time.Duration{"GetMdState": 0}},
+		{Default: time.Second, IdleAfter: time.Minute},
+		{Default: time.Second, QuietHours: &QuietHours{Start: 22, End: 24}},
+	}
+	for i, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("profile %d: expected error", i)
+		}
+	}
+
+	// WithInterval must not modify the preset it was called on
+	base := PollingBalanced()
+	tuned := base.WithInterval("GetMdState", time.Minute)
+	if base.Intervals["GetMdState"] != time.Second || tuned.Intervals["GetMdState"] != time.Minute {
+		t.Errorf("unexpected intervals: base=%v tuned=%v", base.Intervals, tuned.Intervals)
+	}
+}
+
+func TestWatchEventsProfile_Intervals(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": 0}`,
+		"GetAiState": `{"channel": 0, "people": {"alarm_state": 0, "support": 1}}`,
+	})
+	client := srv.client()
+
+	profile := PollingProfile{
+		Intervals: map[string]time.Duration{
+			"GetMdState": 5 * time.Millisecond,
+			"GetAiState": time.Hour,
+		},
+		Default: time.Hour,
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	events := make(chan Event, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Alarm.WatchEventsProfile(ctx, 0, profile, func(ev Event) {
+			events <- ev
+		})
+	}()
+
+	for srv.callCount("GetMdState") < 5 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.set("GetMdState", `{"state": 1}`)
+	select {
+	case ev := <-events:
+		if ev.Type != EventMotionStart {
+			t.Errorf("expected motion start, got %s", ev.Type)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for motion event")
+	}
+	cancel()
+	<-done
+
+	if n := srv.callCount("GetAiState"); n != 1 {
+		t.Errorf("expected AI state to be polled once, got %d calls", n)
+	}
+}
+
+func TestWatchEventsProfile_SkipAsleep(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": 0}`,
+	})
+	client := srv.client()
+	transport := &sleepyTransport{next: srv.Client().Transport}
+	client.httpClient.Transport = transport
+	WithSleepHandling(SleepOptions{})(client)
+
+	profile := PollingBatterySaver().
+		WithInterval("GetMdState", 5*time.Millisecond).
+		WithInterval("GetAiState", 5*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Alarm.WatchEventsProfile(ctx, 0, profile, func(Event) {})
+	}()
+
+	for srv.callCount("GetMdState") < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A request that finds the camera asleep stops polling
+	transport.asleep.Store(true)
+	for !client.Asleep() {
+		time.Sleep(time.Millisecond)
+	}
+	transport.asleep.Store(false)
+	polled := srv.callCount("GetMdState")
+	time.Sleep(50 * time.Millisecond)
+	if n := srv.callCount("GetMdState"); n != polled {
+		t.Errorf("expected no polls while asleep, got %d more", n-polled)
+	}
+
+	// Polling resumes once another request finds it awake
+	if _, err := client.Alarm.GetMdState(t.Context(), 0); err != nil {
+		t.Fatalf("GetMdState failed: %v", err)
+	}
+	for srv.callCount("GetMdState") < polled+3 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+}
@@ -2,13 +2,16 @@ package reolink
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"sync"
 )
 
 // PTZAPI provides access to Pan-Tilt-Zoom control endpoints
 type PTZAPI struct {
 	client *Client
+
+	velocityMu sync.Mutex
+	velocity   map[int]*ptzVelocity
 }
 
 // PTZ operation constants
@@ -36,11 +39,15 @@ const (
 
 // PtzPreset represents a PTZ preset position
 type PtzPreset struct {
-	Enable int    `json:"enable"` // 0=disabled, 1=enabled
-	ID     int    `json:"id"`     // Preset ID (1-64)
-	Name   string `json:"name"`   // Preset name
+	Channel int     `json:"channel"` // Channel number
+	Enable  BoolInt `json:"enable"`  // 0=disabled, 1=enabled
+	ID      int     `json:"id"`      // Preset ID (1-64)
+	Name    string  `json:"name"`    // Preset name
 }
 
+// maxPtzPresetID is the highest preset ID supported by the firmware
+const maxPtzPresetID = 64
+
 // PtzPresetValue wraps preset array for API response
 type PtzPresetValue struct {
 	PtzPreset []PtzPreset `json:"PtzPreset"`
@@ -61,7 +68,7 @@ type PtzPatrolPreset struct {
 // PtzPatrol represents a PTZ patrol/tour configuration
 type PtzPatrol struct {
 	Channel int               `json:"channel"` // Channel number
-	Enable  int               `json:"enable"`  // 0=disabled, 1=enabled
+	Enable  BoolInt           `json:"enable"`  // 0=disabled, 1=enabled
 	ID      int               `json:"id"`      // Patrol ID
 	Running int               `json:"running"` // 0=stopped, 1=running
 	Name    string            `json:"name"`    // Patrol name
@@ -80,12 +87,12 @@ type PtzPatrolParam struct {
 
 // PtzGuard represents PTZ guard/home position configuration
 type PtzGuard struct {
-	Channel         int    `json:"channel"`         // Channel number
-	CmdStr          string `json:"cmdStr"`          // Command string
-	BEnable         int    `json:"benable"`         // 0=disabled, 1=enabled
-	BExistPos       int    `json:"bexistPos"`       // Whether guard position exists
-	Timeout         int    `json:"timeout"`         // Timeout in seconds (typically 60)
-	BSaveCurrentPos int    `json:"bSaveCurrentPos"` // 1=save current position as guard
+	Channel         int     `json:"channel"`         // Channel number
+	CmdStr          string  `json:"cmdStr"`          // Command string
+	BEnable         BoolInt `json:"benable"`         // 0=disabled, 1=enabled
+	BExistPos       int     `json:"bexistPos"`       // Whether guard position exists
+	Timeout         int     `json:"timeout"`         // Timeout in seconds (typically 60)
+	BSaveCurrentPos int     `json:"bSaveCurrentPos"` // 1=save current position as guard
 }
 
 // PtzGuardValue wraps guard for API response
@@ -108,7 +115,7 @@ type PtzCtrlParam struct {
 
 // PtzCtrl controls PTZ movement
 func (p *PTZAPI) PtzCtrl(ctx context.Context, param PtzCtrlParam) error {
-	p.client.logger.Info("controlling PTZ: channel=%d op=%s speed=%d",
+	p.client.log(ctx).Info("controlling PTZ: channel=%d op=%s speed=%d",
 		param.Channel, param.Op, param.Speed)
 
 	req := []Request{{
@@ -118,28 +125,41 @@ func (p *PTZAPI) PtzCtrl(ctx context.Context, param PtzCtrlParam) error {
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to control PTZ: %v", err)
+		p.client.log(ctx).Error("failed to control PTZ: %v", err)
 		return fmt.Errorf("PtzCtrl request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to control PTZ: %v", err)
+		p.client.log(ctx).Error("failed to control PTZ: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to control PTZ: %v", apiErr)
+		p.client.log(ctx).Error("failed to control PTZ: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully controlled PTZ")
+	p.client.log(ctx).Info("successfully controlled PTZ")
 	return nil
 }
 
+// CallPreset moves the camera to a saved preset position
+func (p *PTZAPI) CallPreset(ctx context.Context, channel, id int) error {
+	if id < 1 || id > maxPtzPresetID {
+		return fmt.Errorf("preset ID must be between 1 and %d, got %d", maxPtzPresetID, id)
+	}
+
+	return p.PtzCtrl(ctx, PtzCtrlParam{
+		Channel: channel,
+		Op:      PTZOpToPos,
+		ID:      id,
+	})
+}
+
 // GetPtzPreset gets PTZ preset positions
 func (p *PTZAPI) GetPtzPreset(ctx context.Context, channel int) ([]PtzPreset, error) {
-	p.client.logger.Debug("getting PTZ presets: channel=%d", channel)
+	p.client.log(ctx).Debug("getting PTZ presets: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetPtzPreset",
@@ -151,34 +171,40 @@ func (p *PTZAPI) GetPtzPreset(ctx context.Context, channel int) ([]PtzPreset, er
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to get PTZ presets: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ presets: %v", err)
 		return nil, fmt.Errorf("GetPtzPreset request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to get PTZ presets: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ presets: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to get PTZ presets: %v", apiErr)
+		p.client.log(ctx).Error("failed to get PTZ presets: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value PtzPresetValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		p.client.logger.Error("failed to parse PTZ presets response: %v", err)
+	if err := p.client.unmarshal(resp[0].Value, &value); err != nil {
+		p.client.log(ctx).Error("failed to parse PTZ presets response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	p.client.logger.Info("successfully retrieved PTZ presets: count=%d", len(value.PtzPreset))
+	p.client.log(ctx).Info("successfully retrieved PTZ presets: count=%d", len(value.PtzPreset))
 	return value.PtzPreset, nil
 }
 
-// SetPtzPreset sets or calls a PTZ preset position
+// SetPtzPreset saves the camera's current position as a preset, or renames
+// or disables an existing one. It never moves the camera; use CallPreset to
+// go to a saved preset.
 func (p *PTZAPI) SetPtzPreset(ctx context.Context, preset PtzPreset) error {
-	p.client.logger.Info("setting PTZ preset: id=%d name=%s", preset.ID, preset.Name)
+	if preset.ID < 1 || preset.ID > maxPtzPresetID {
+		return fmt.Errorf("preset ID must be between 1 and %d, got %d", maxPtzPresetID, preset.ID)
+	}
+
+	p.client.log(ctx).Info("setting PTZ preset: channel=%d id=%d name=%s", preset.Channel, preset.ID, preset.Name)
 
 	req := []Request{{
 		Cmd: "SetPtzPreset",
@@ -189,28 +215,28 @@ func (p *PTZAPI) SetPtzPreset(ctx context.Context, preset PtzPreset) error {
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to set PTZ preset: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ preset: %v", err)
 		return fmt.Errorf("SetPtzPreset request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to set PTZ preset: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ preset: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to set PTZ preset: %v", apiErr)
+		p.client.log(ctx).Error("failed to set PTZ preset: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully set PTZ preset")
+	p.client.log(ctx).Info("successfully set PTZ preset")
 	return nil
 }
 
 // GetPtzPatrol gets PTZ patrol/tour configuration
 func (p *PTZAPI) GetPtzPatrol(ctx context.Context, channel int) (*PtzPatrol, error) {
-	p.client.logger.Debug("getting PTZ patrol configuration: channel=%d", channel)
+	p.client.log(ctx).Debug("getting PTZ patrol configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetPtzPatrol",
@@ -222,34 +248,34 @@ func (p *PTZAPI) GetPtzPatrol(ctx context.Context, channel int) (*PtzPatrol, err
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to get PTZ patrol configuration: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ patrol configuration: %v", err)
 		return nil, fmt.Errorf("GetPtzPatrol request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to get PTZ patrol configuration: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ patrol configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to get PTZ patrol configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to get PTZ patrol configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value PtzPatrolValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		p.client.logger.Error("failed to parse PTZ patrol configuration response: %v", err)
+	if err := p.client.unmarshal(resp[0].Value, &value); err != nil {
+		p.client.log(ctx).Error("failed to parse PTZ patrol configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	p.client.logger.Info("successfully retrieved PTZ patrol configuration")
+	p.client.log(ctx).Info("successfully retrieved PTZ patrol configuration")
 	return &value.PtzPatrol, nil
 }
 
 // SetPtzPatrol sets PTZ patrol/tour configuration
 func (p *PTZAPI) SetPtzPatrol(ctx context.Context, patrol PtzPatrol) error {
-	p.client.logger.Info("setting PTZ patrol configuration: channel=%d", patrol.Channel)
+	p.client.log(ctx).Info("setting PTZ patrol configuration: channel=%d", patrol.Channel)
 
 	req := []Request{{
 		Cmd: "SetPtzPatrol",
@@ -260,28 +286,28 @@ func (p *PTZAPI) SetPtzPatrol(ctx context.Context, patrol PtzPatrol) error {
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to set PTZ patrol configuration: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ patrol configuration: %v", err)
 		return fmt.Errorf("SetPtzPatrol request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to set PTZ patrol configuration: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ patrol configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to set PTZ patrol configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to set PTZ patrol configuration: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully set PTZ patrol configuration")
+	p.client.log(ctx).Info("successfully set PTZ patrol configuration")
 	return nil
 }
 
 // GetPtzGuard gets PTZ guard/home position configuration
 func (p *PTZAPI) GetPtzGuard(ctx context.Context, channel int) (*PtzGuard, error) {
-	p.client.logger.Debug("getting PTZ guard configuration: channel=%d", channel)
+	p.client.log(ctx).Debug("getting PTZ guard configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetPtzGuard",
@@ -293,35 +319,35 @@ func (p *PTZAPI) GetPtzGuard(ctx context.Context, channel int) (*PtzGuard, error
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to get PTZ guard configuration: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ guard configuration: %v", err)
 		return nil, fmt.Errorf("GetPtzGuard request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to get PTZ guard configuration: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ guard configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to get PTZ guard configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to get PTZ guard configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value PtzGuardValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		p.client.logger.Error("failed to parse PTZ guard configuration response: %v", err)
+	if err := p.client.unmarshal(resp[0].Value, &value); err != nil {
+		p.client.log(ctx).Error("failed to parse PTZ guard configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	p.client.logger.Info("successfully retrieved PTZ guard configuration: enable=%d timeout=%d",
+	p.client.log(ctx).Info("successfully retrieved PTZ guard configuration: enable=%d timeout=%d",
 		value.PtzGuard.BEnable, value.PtzGuard.Timeout)
 	return &value.PtzGuard, nil
 }
 
 // SetPtzGuard sets PTZ guard/home position configuration
 func (p *PTZAPI) SetPtzGuard(ctx context.Context, guard PtzGuard) error {
-	p.client.logger.Info("setting PTZ guard configuration: channel=%d enable=%d timeout=%d",
+	p.client.log(ctx).Info("setting PTZ guard configuration: channel=%d enable=%d timeout=%d",
 		guard.Channel, guard.BEnable, guard.Timeout)
 
 	req := []Request{{
@@ -333,33 +359,40 @@ func (p *PTZAPI) SetPtzGuard(ctx context.Context, guard PtzGuard) error {
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to set PTZ guard configuration: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ guard configuration: %v", err)
 		return fmt.Errorf("SetPtzGuard request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to set PTZ guard configuration: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ guard configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to set PTZ guard configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to set PTZ guard configuration: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully set PTZ guard configuration")
+	p.client.log(ctx).Info("successfully set PTZ guard configuration")
 	return nil
 }
 
 // PtzCheckState represents PTZ calibration check state
 type PtzCheckState struct {
-	Status int `json:"status"` // Check state status (0=idle, 1=checking)
+	Status int `json:"status"` // Check state status (use PtzCheck* constants)
 }
 
+// PTZ calibration check states
+const (
+	PtzCheckIdle     = 0
+	PtzCheckRunning  = 1
+	PtzCheckFinished = 2
+)
+
 // GetPtzCheckState gets PTZ calibration check state
 func (p *PTZAPI) GetPtzCheckState(ctx context.Context, channel int) (*PtzCheckState, error) {
-	p.client.logger.Debug("getting PTZ check state: channel=%d", channel)
+	p.client.log(ctx).Debug("getting PTZ check state: channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetPtzCheckState",
@@ -370,34 +403,34 @@ func (p *PTZAPI) GetPtzCheckState(ctx context.Context, channel int) (*PtzCheckSt
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to get PTZ check state: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ check state: %v", err)
 		return nil, fmt.Errorf("GetPtzCheckState request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to get PTZ check state: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ check state: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to get PTZ check state: %v", apiErr)
+		p.client.log(ctx).Error("failed to get PTZ check state: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var state PtzCheckState
-	if err := json.Unmarshal(resp[0].Value, &state); err != nil {
-		p.client.logger.Error("failed to parse PTZ check state response: %v", err)
+	if err := p.client.unmarshal(resp[0].Value, &state); err != nil {
+		p.client.log(ctx).Error("failed to parse PTZ check state response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	p.client.logger.Info("successfully retrieved PTZ check state: status=%d", state.Status)
+	p.client.log(ctx).Info("successfully retrieved PTZ check state: status=%d", state.Status)
 	return &state, nil
 }
 
 // PtzCheck performs PTZ calibration check
 func (p *PTZAPI) PtzCheck(ctx context.Context, channel int) error {
-	p.client.logger.Info("performing PTZ calibration check: channel=%d", channel)
+	p.client.log(ctx).Info("performing PTZ calibration check: channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "PtzCheck",
@@ -408,22 +441,22 @@ func (p *PTZAPI) PtzCheck(ctx context.Context, channel int) error {
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to perform PTZ calibration check: %v", err)
+		p.client.log(ctx).Error("failed to perform PTZ calibration check: %v", err)
 		return fmt.Errorf("PtzCheck request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to perform PTZ calibration check: %v", err)
+		p.client.log(ctx).Error("failed to perform PTZ calibration check: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to perform PTZ calibration check: %v", apiErr)
+		p.client.log(ctx).Error("failed to perform PTZ calibration check: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully performed PTZ calibration check")
+	p.client.log(ctx).Info("successfully performed PTZ calibration check")
 	return nil
 }
 
@@ -445,7 +478,7 @@ type ZoomFocusValue struct {
 
 // GetZoomFocus gets current zoom and focus position
 func (p *PTZAPI) GetZoomFocus(ctx context.Context, channel int) (*ZoomFocus, error) {
-	p.client.logger.Debug("getting zoom/focus position: channel=%d", channel)
+	p.client.log(ctx).Debug("getting zoom/focus position: channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetZoomFocus",
@@ -456,37 +489,37 @@ func (p *PTZAPI) GetZoomFocus(ctx context.Context, channel int) (*ZoomFocus, err
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to get zoom/focus position: %v", err)
+		p.client.log(ctx).Error("failed to get zoom/focus position: %v", err)
 		return nil, fmt.Errorf("GetZoomFocus request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to get zoom/focus position: %v", err)
+		p.client.log(ctx).Error("failed to get zoom/focus position: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to get zoom/focus position: %v", apiErr)
+		p.client.log(ctx).Error("failed to get zoom/focus position: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value ZoomFocusValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		p.client.logger.Error("failed to parse zoom/focus position response: %v", err)
+	if err := p.client.unmarshal(resp[0].Value, &value); err != nil {
+		p.client.log(ctx).Error("failed to parse zoom/focus position response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	p.client.logger.Info("successfully retrieved zoom/focus position: zoom=%d focus=%d",
+	p.client.log(ctx).Info("successfully retrieved zoom/focus position: zoom=%d focus=%d",
 		value.ZoomFocus.Zoom.Pos, value.ZoomFocus.Focus.Pos)
 	return &value.ZoomFocus, nil
 }
 
 // StartZoomFocus starts zoom or focus operation
-// op: ZoomInc, ZoomDec, FocusInc, FocusDec
-// pos: target position (optional, set to 0 if not used)
+// op: ZoomInc, ZoomDec, FocusInc, FocusDec, ZoomPos, FocusPos
+// pos: target position for ZoomPos and FocusPos (set to 0 if not used)
 func (p *PTZAPI) StartZoomFocus(ctx context.Context, channel int, op string, pos int) error {
-	p.client.logger.Info("starting zoom/focus operation: channel=%d op=%s pos=%d", channel, op, pos)
+	p.client.log(ctx).Info("starting zoom/focus operation: channel=%d op=%s pos=%d", channel, op, pos)
 
 	req := []Request{{
 		Cmd: "StartZoomFocus",
@@ -501,30 +534,40 @@ func (p *PTZAPI) StartZoomFocus(ctx context.Context, channel int, op string, pos
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to start zoom/focus operation: %v", err)
+		p.client.log(ctx).Error("failed to start zoom/focus operation: %v", err)
 		return fmt.Errorf("StartZoomFocus request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to start zoom/focus operation: %v", err)
+		p.client.log(ctx).Error("failed to start zoom/focus operation: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to start zoom/focus operation: %v", apiErr)
+		p.client.log(ctx).Error("failed to start zoom/focus operation: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully started zoom/focus operation")
+	p.client.log(ctx).Info("successfully started zoom/focus operation")
 	return nil
 }
 
 // PtzTattern represents PTZ pattern/track configuration
 // Note: API uses "Tattern" (typo) instead of "Pattern"
 type PtzTattern struct {
-	Enable int `json:"enable"` // 0=disabled, 1=enabled
-	ID     int `json:"id"`     // Track ID (1-6)
+	Enable  BoolInt    `json:"enable"`            // 0=disabled, 1=enabled
+	ID      int        `json:"id"`                // Track ID (1-6)
+	Channel int        `json:"channel,omitempty"` // Channel number
+	Track   []PtzTrack `json:"track,omitempty"`   // Tracks, as returned by current firmware
+}
+
+// PtzTrack is one PTZ pattern track
+type PtzTrack struct {
+	Enable  BoolInt `json:"enable"`  // 0=disabled, 1=enabled
+	ID      int     `json:"id"`      // Track ID (1-6)
+	Name    string  `json:"name"`    // Track name
+	Running int     `json:"running"` // 1 while the track is running
 }
 
 // PtzTatternValue wraps PtzTattern for API response
@@ -534,7 +577,7 @@ type PtzTatternValue struct {
 
 // GetPtzTattern gets PTZ pattern/track configuration
 func (p *PTZAPI) GetPtzTattern(ctx context.Context, channel int) (*PtzTattern, error) {
-	p.client.logger.Debug("getting PTZ pattern configuration: channel=%d", channel)
+	p.client.log(ctx).Debug("getting PTZ pattern configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd: "GetPtzTattern",
@@ -545,35 +588,35 @@ func (p *PTZAPI) GetPtzTattern(ctx context.Context, channel int) (*PtzTattern, e
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to get PTZ pattern configuration: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ pattern configuration: %v", err)
 		return nil, fmt.Errorf("GetPtzTattern request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to get PTZ pattern configuration: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ pattern configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to get PTZ pattern configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to get PTZ pattern configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value PtzTatternValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		p.client.logger.Error("failed to parse PTZ pattern configuration response: %v", err)
+	if err := p.client.unmarshal(resp[0].Value, &value); err != nil {
+		p.client.log(ctx).Error("failed to parse PTZ pattern configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	p.client.logger.Info("successfully retrieved PTZ pattern configuration: enable=%d id=%d",
+	p.client.log(ctx).Info("successfully retrieved PTZ pattern configuration: enable=%d id=%d",
 		value.PtzTattern.Enable, value.PtzTattern.ID)
 	return &value.PtzTattern, nil
 }
 
 // SetPtzTattern sets PTZ pattern/track configuration
 func (p *PTZAPI) SetPtzTattern(ctx context.Context, channel int, tattern PtzTattern) error {
-	p.client.logger.Info("setting PTZ pattern configuration: channel=%d enable=%d id=%d",
+	p.client.log(ctx).Info("setting PTZ pattern configuration: channel=%d enable=%d id=%d",
 		channel, tattern.Enable, tattern.ID)
 
 	req := []Request{{
@@ -585,22 +628,22 @@ func (p *PTZAPI) SetPtzTattern(ctx context.Context, channel int, tattern PtzTatt
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to set PTZ pattern configuration: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ pattern configuration: %v", err)
 		return fmt.Errorf("SetPtzTattern request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to set PTZ pattern configuration: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ pattern configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to set PTZ pattern configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to set PTZ pattern configuration: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully set PTZ pattern configuration")
+	p.client.log(ctx).Info("successfully set PTZ pattern configuration")
 	return nil
 }
 
@@ -623,7 +666,7 @@ type PtzSerialValue struct {
 
 // GetPtzSerial gets PTZ serial port configuration
 func (p *PTZAPI) GetPtzSerial(ctx context.Context, channel int) (*PtzSerial, error) {
-	p.client.logger.Debug("getting PTZ serial configuration: channel=%d", channel)
+	p.client.log(ctx).Debug("getting PTZ serial configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetPtzSerial",
@@ -635,35 +678,35 @@ func (p *PTZAPI) GetPtzSerial(ctx context.Context, channel int) (*PtzSerial, err
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to get PTZ serial configuration: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ serial configuration: %v", err)
 		return nil, fmt.Errorf("GetPtzSerial request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to get PTZ serial configuration: %v", err)
+		p.client.log(ctx).Error("failed to get PTZ serial configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to get PTZ serial configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to get PTZ serial configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value PtzSerialValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		p.client.logger.Error("failed to parse PTZ serial configuration response: %v", err)
+	if err := p.client.unmarshal(resp[0].Value, &value); err != nil {
+		p.client.log(ctx).Error("failed to parse PTZ serial configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	p.client.logger.Info("successfully retrieved PTZ serial configuration: protocol=%s baudRate=%d",
+	p.client.log(ctx).Info("successfully retrieved PTZ serial configuration: protocol=%s baudRate=%d",
 		value.PtzSerial.CtrlProtocol, value.PtzSerial.BaudRate)
 	return &value.PtzSerial, nil
 }
 
 // SetPtzSerial sets PTZ serial port configuration
 func (p *PTZAPI) SetPtzSerial(ctx context.Context, serial PtzSerial) error {
-	p.client.logger.Info("setting PTZ serial configuration: channel=%d protocol=%s baudRate=%d",
+	p.client.log(ctx).Info("setting PTZ serial configuration: channel=%d protocol=%s baudRate=%d",
 		serial.Channel, serial.CtrlProtocol, serial.BaudRate)
 
 	req := []Request{{
@@ -675,22 +718,22 @@ func (p *PTZAPI) SetPtzSerial(ctx context.Context, serial PtzSerial) error {
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to set PTZ serial configuration: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ serial configuration: %v", err)
 		return fmt.Errorf("SetPtzSerial request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to set PTZ serial configuration: %v", err)
+		p.client.log(ctx).Error("failed to set PTZ serial configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to set PTZ serial configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to set PTZ serial configuration: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully set PTZ serial configuration")
+	p.client.log(ctx).Info("successfully set PTZ serial configuration")
 	return nil
 }
 
@@ -707,7 +750,7 @@ type AutoFocusValue struct {
 
 // GetAutoFocus gets auto focus configuration
 func (p *PTZAPI) GetAutoFocus(ctx context.Context, channel int) (*AutoFocus, error) {
-	p.client.logger.Debug("getting auto focus configuration: channel=%d", channel)
+	p.client.log(ctx).Debug("getting auto focus configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetAutoFocus",
@@ -719,34 +762,34 @@ func (p *PTZAPI) GetAutoFocus(ctx context.Context, channel int) (*AutoFocus, err
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to get auto focus configuration: %v", err)
+		p.client.log(ctx).Error("failed to get auto focus configuration: %v", err)
 		return nil, fmt.Errorf("GetAutoFocus request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to get auto focus configuration: %v", err)
+		p.client.log(ctx).Error("failed to get auto focus configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to get auto focus configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to get auto focus configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value AutoFocusValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		p.client.logger.Error("failed to parse auto focus configuration response: %v", err)
+	if err := p.client.unmarshal(resp[0].Value, &value); err != nil {
+		p.client.log(ctx).Error("failed to parse auto focus configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	p.client.logger.Info("successfully retrieved auto focus configuration: disable=%d", value.AutoFocus.Disable)
+	p.client.log(ctx).Info("successfully retrieved auto focus configuration: disable=%d", value.AutoFocus.Disable)
 	return &value.AutoFocus, nil
 }
 
 // SetAutoFocus sets auto focus configuration
 func (p *PTZAPI) SetAutoFocus(ctx context.Context, autoFocus AutoFocus) error {
-	p.client.logger.Info("setting auto focus configuration: channel=%d disable=%d",
+	p.client.log(ctx).Info("setting auto focus configuration: channel=%d disable=%d",
 		autoFocus.Channel, autoFocus.Disable)
 
 	req := []Request{{
@@ -759,21 +802,21 @@ func (p *PTZAPI) SetAutoFocus(ctx context.Context, autoFocus AutoFocus) error {
 
 	var resp []Response
 	if err := p.client.do(ctx, req, &resp); err != nil {
-		p.client.logger.Error("failed to set auto focus configuration: %v", err)
+		p.client.log(ctx).Error("failed to set auto focus configuration: %v", err)
 		return fmt.Errorf("SetAutoFocus request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		p.client.logger.Error("failed to set auto focus configuration: %v", err)
+		p.client.log(ctx).Error("failed to set auto focus configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		p.client.logger.Error("failed to set auto focus configuration: %v", apiErr)
+		p.client.log(ctx).Error("failed to set auto focus configuration: %v", apiErr)
 		return apiErr
 	}
 
-	p.client.logger.Info("successfully set auto focus configuration")
+	p.client.log(ctx).Info("successfully set auto focus configuration")
 	return nil
 }
@@ -0,0 +1,91 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// FocusOptions configures OneTouchFocus and LockFocus
+type FocusOptions struct {
+	// PollInterval is the time between focus position reads while waiting
+	// for autofocus to settle (default: 500ms)
+	PollInterval time.Duration
+	// Timeout is how long to wait for the focus position to settle
+	// (default: 10s)
+	Timeout time.Duration
+}
+
+// OneTouchFocus enables autofocus, waits until the focus position stops
+// changing and returns it. Autofocus stays enabled; use LockFocus to hold
+// the result.
+func (p *PTZAPI) OneTouchFocus(ctx context.Context, channel int, opts FocusOptions) (int, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 500 * time.Millisecond
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	p.client.log(ctx).Info("running one-touch focus: channel=%d", channel)
+
+	if err := p.SetAutoFocus(ctx, AutoFocus{Channel: channel, Disable: 0}); err != nil {
+		return 0, fmt.Errorf("failed to enable autofocus: %w", err)
+	}
+
+	deadline := time.Now().Add(opts.Timeout)
+	last := -1
+	for {
+		zf, err := p.GetZoomFocus(ctx, channel)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read focus position: %w", err)
+		}
+		if zf.Focus.Pos == last {
+			p.client.log(ctx).Info("focus settled: channel=%d pos=%d", channel, last)
+			return last, nil
+		}
+		last = zf.Focus.Pos
+
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("focus did not settle within %s", opts.Timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(opts.PollInterval):
+		}
+	}
+}
+
+// LockFocus runs OneTouchFocus, then disables autofocus and pins the lens
+// at the settled position, so scenes seen through glass or with moving
+// foreground objects no longer hunt for focus. If pinning fails, autofocus
+// is re-enabled rather than leaving the lens unfocused. It returns the
+// locked focus position.
+func (p *PTZAPI) LockFocus(ctx context.Context, channel int, opts FocusOptions) (int, error) {
+	pos, err := p.OneTouchFocus(ctx, channel, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := p.SetAutoFocus(ctx, AutoFocus{Channel: channel, Disable: 1}); err != nil {
+		return 0, fmt.Errorf("failed to disable autofocus: %w", err)
+	}
+	if err := p.StartZoomFocus(ctx, channel, ZoomFocusOpFocusPos, pos); err != nil {
+		if rerr := p.SetAutoFocus(ctx, AutoFocus{Channel: channel, Disable: 0}); rerr != nil {
+			p.client.log(ctx).Error("failed to re-enable autofocus after failed focus lock: %v", rerr)
+		}
+		return 0, fmt.Errorf("failed to set focus position: %w", err)
+	}
+
+	p.client.log(ctx).Info("successfully locked focus: channel=%d pos=%d", channel, pos)
+	return pos, nil
+}
+
+// UnlockFocus re-enables autofocus after LockFocus
+func (p *PTZAPI) UnlockFocus(ctx context.Context, channel int) error {
+	if err := p.SetAutoFocus(ctx, AutoFocus{Channel: channel, Disable: 0}); err != nil {
+		return fmt.Errorf("failed to enable autofocus: %w", err)
+	}
+	return nil
+}
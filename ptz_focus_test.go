@@ -0,0 +1,95 @@
+package reolink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newFocusServer(t *testing.T) *cmdServer {
+	t.Helper()
+	return newCmdServer(t, map[string]string{
+		"GetAutoFocus":   `{"AutoFocus": {"channel": 0, "disable": 0}}`,
+		"SetAutoFocus":   "",
+		"GetZoomFocus":   `{"ZoomFocus": {"channel": 0, "zoom": {"pos": 5}, "focus": {"pos": 117}}}`,
+		"StartZoomFocus": "",
+	})
+}
+
+func TestPTZAPI_LockFocus(t *testing.T) {
+	server := newFocusServer(t)
+	client := server.client()
+	opts := FocusOptions{PollInterval: time.Millisecond}
+
+	pos, err := client.PTZ.LockFocus(t.Context(), 0, opts)
+	if err != nil {
+		t.Fatalf("LockFocus failed: %v", err)
+	}
+	if pos != 117 {
+		t.Errorf("expected focus position 117, got %d", pos)
+	}
+
+	af, _ := client.PTZ.GetAutoFocus(t.Context(), 0)
+	if af.Disable != 1 {
+		t.Error("expected autofocus to be disabled after locking")
+	}
+	var param struct {
+		ZoomFocus struct {
+			Op  string `json:"op"`
+			Pos int    `json:"pos"`
+		} `json:"ZoomFocus"`
+	}
+	json.Unmarshal(server.lastParam("StartZoomFocus"), &param)
+	if param.ZoomFocus.Op != ZoomFocusOpFocusPos || param.ZoomFocus.Pos != 117 {
+		t.Errorf("expected FocusPos 117, got %s %d", param.ZoomFocus.Op, param.ZoomFocus.Pos)
+	}
+
+	if err := client.PTZ.UnlockFocus(t.Context(), 0); err != nil {
+		t.Fatalf("UnlockFocus failed: %v", err)
+	}
+	af, _ = client.PTZ.GetAutoFocus(t.Context(), 0)
+	if af.Disable != 0 {
+		t.Error("expected autofocus to be enabled after unlocking")
+	}
+}
+
+func TestPTZAPI_LockFocus_RollsBack(t *testing.T) {
+	server := newFocusServer(t)
+	server.mu.Lock()
+	delete(server.values, "StartZoomFocus")
+	server.mu.Unlock()
+	client := server.client()
+
+	if _, err := client.PTZ.LockFocus(t.Context(), 0, FocusOptions{PollInterval: time.Millisecond}); err == nil {
+		t.Fatal("expected error when the focus position cannot be set")
+	}
+	af, _ := client.PTZ.GetAutoFocus(t.Context(), 0)
+	if af.Disable != 0 {
+		t.Error("expected autofocus to be re-enabled after a failed lock")
+	}
+}
+
+func TestPTZAPI_OneTouchFocus_Timeout(t *testing.T) {
+	var reads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []Request
+		json.NewDecoder(r.Body).Decode(&reqs)
+		resp := Response{Cmd: reqs[0].Cmd, Value: json.RawMessage(`{"rspCode": 200}`)}
+		if reqs[0].Cmd == "GetZoomFocus" {
+			// Each read returns a new position, so focus never settles
+			resp.Value = json.RawMessage(fmt.Sprintf(`{"ZoomFocus": {"focus": {"pos": %d}}}`, reads.Add(1)))
+		}
+		json.NewEncoder(w).Encode([]Response{resp})
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	opts := FocusOptions{PollInterval: time.Millisecond, Timeout: 20 * time.Millisecond}
+	if _, err := client.PTZ.OneTouchFocus(t.Context(), 0, opts); err == nil {
+		t.Error("expected timeout error")
+	}
+}
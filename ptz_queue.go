@@ -0,0 +1,138 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ptzStopTimeout bounds the trailing Stop sent when a PTZQueue shuts down
+const ptzStopTimeout = 5 * time.Second
+
+// continuousPtzOps are the PtzCtrl operations that keep the camera moving
+// until a Stop; consecutive pending ones are coalesced by PTZQueue
+var continuousPtzOps = map[string]bool{
+	PTZOpStop:      true,
+	PTZOpLeft:      true,
+	PTZOpRight:     true,
+	PTZOpUp:        true,
+	PTZOpDown:      true,
+	PTZOpLeftUp:    true,
+	PTZOpLeftDown:  true,
+	PTZOpRightUp:   true,
+	PTZOpRightDown: true,
+	PTZOpZoomInc:   true,
+	PTZOpZoomDec:   true,
+	PTZOpFocusInc:  true,
+	PTZOpFocusDec:  true,
+	PTZOpIrisInc:   true,
+	PTZOpIrisDec:   true,
+}
+
+// PTZQueue serializes PtzCtrl commands for one channel. It is meant for
+// interactive UIs such as joysticks: commands are sent one at a time in
+// order, a burst of movement updates submitted while a request is in flight
+// collapses to the latest one, and the camera is always sent a Stop when
+// the queue shuts down, so a dropped connection or closed UI cannot leave it
+// moving.
+type PTZQueue struct {
+	ptz     *PTZAPI
+	channel int
+
+	mu      sync.Mutex
+	pending []PtzCtrlParam
+	wake    chan struct{}
+}
+
+// NewQueue creates a PTZ command queue for a channel. Commands are only sent
+// while Run is active.
+func (p *PTZAPI) NewQueue(channel int) *PTZQueue {
+	return &PTZQueue{
+		ptz:     p,
+		channel: channel,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Submit queues a PtzCtrl command; its channel is set to the queue's. If
+// both the command and the last pending one are continuous movements (pan,
+// tilt, zoom, focus, iris or Stop), the pending one is replaced rather than
+// sent.
+func (q *PTZQueue) Submit(param PtzCtrlParam) error {
+	if param.Op == "" {
+		return fmt.Errorf("PTZ operation must not be empty")
+	}
+	param.Channel = q.channel
+
+	q.mu.Lock()
+	if n := len(q.pending); n > 0 && continuousPtzOps[param.Op] && continuousPtzOps[q.pending[n-1].Op] {
+		q.pending[n-1] = param
+	} else {
+		q.pending = append(q.pending, param)
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Move queues a continuous movement at the given speed (1-64)
+func (q *PTZQueue) Move(op string, speed int) error {
+	return q.Submit(PtzCtrlParam{Op: op, Speed: speed})
+}
+
+// Stop queues a Stop, replacing any pending movement
+func (q *PTZQueue) Stop() error {
+	return q.Submit(PtzCtrlParam{Op: PTZOpStop})
+}
+
+// Run sends queued commands until ctx is done. Failed commands are logged
+// and skipped. When ctx is done, pending commands are dropped and, if the
+// camera may still be moving, a final Stop is sent. It returns ctx.Err().
+func (q *PTZQueue) Run(ctx context.Context) error {
+	moving := false
+	defer func() {
+		q.mu.Lock()
+		q.pending = nil
+		q.mu.Unlock()
+		if !moving {
+			return
+		}
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ptzStopTimeout)
+		defer cancel()
+		if err := q.ptz.PtzCtrl(stopCtx, PtzCtrlParam{Channel: q.channel, Op: PTZOpStop}); err != nil {
+			q.ptz.client.log(ctx).Error("failed to stop PTZ on shutdown: channel=%d: %v", q.channel, err)
+		}
+	}()
+
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-q.wake:
+				continue
+			}
+		}
+		param := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Anything other than Stop may leave the camera moving, including
+		// a request interrupted by cancellation
+		moving = param.Op != PTZOpStop
+		if err := q.ptz.PtzCtrl(ctx, param); err != nil {
+			q.ptz.client.log(ctx).Warn("queued PTZ command failed: channel=%d op=%s: %v", q.channel, param.Op, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// ptzOps returns the ops of all PtzCtrl requests received by the server
+func ptzOps(s *cmdServer) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ops []string
+	for _, raw := range s.calls["PtzCtrl"] {
+		var param PtzCtrlParam
+		json.Unmarshal(raw, &param)
+		ops = append(ops, param.Op)
+	}
+	return ops
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPTZQueue_CoalescesMovement(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"PtzCtrl": ""})
+	q := server.client().PTZ.NewQueue(2)
+
+	// Submitted before Run starts, so all are pending at once
+	q.Move(PTZOpLeft, 10)
+	q.Move(PTZOpLeft, 20)
+	q.Move(PTZOpRight, 30)
+	q.Submit(PtzCtrlParam{Op: PTZOpToPos, ID: 4})
+	q.Move(PTZOpUp, 5)
+	q.Stop()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- q.Run(ctx) }()
+
+	waitFor(t, func() bool { return server.callCount("PtzCtrl") >= 3 })
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	ops := ptzOps(server)
+	want := []string{PTZOpRight, PTZOpToPos, PTZOpStop}
+	if len(ops) != len(want) {
+		t.Fatalf("expected ops %v, got %v", want, ops)
+	}
+	for i := range want {
+		if ops[i] != want[i] {
+			t.Errorf("op %d: expected %s, got %s", i, want[i], ops[i])
+		}
+	}
+
+	var param PtzCtrlParam
+	json.Unmarshal(server.lastParam("PtzCtrl"), &param)
+	if param.Channel != 2 {
+		t.Errorf("expected channel 2, got %d", param.Channel)
+	}
+}
+
+func TestPTZQueue_StopsOnCancel(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"PtzCtrl": ""})
+	q := server.client().PTZ.NewQueue(0)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- q.Run(ctx) }()
+
+	q.Move(PTZOpZoomInc, 16)
+	waitFor(t, func() bool { return server.callCount("PtzCtrl") == 1 })
+	cancel()
+	<-done
+
+	ops := ptzOps(server)
+	if len(ops) != 2 || ops[1] != PTZOpStop {
+		t.Errorf("expected a trailing Stop, got %v", ops)
+	}
+}
+
+func TestPTZQueue_NoStopWhenIdle(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"PtzCtrl": ""})
+	q := server.client().PTZ.NewQueue(0)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	q.Run(ctx)
+
+	if n := server.callCount("PtzCtrl"); n != 0 {
+		t.Errorf("expected no PtzCtrl calls, got %d", n)
+	}
+	if err := q.Submit(PtzCtrlParam{}); err == nil {
+		t.Error("expected error for an empty operation")
+	}
+}
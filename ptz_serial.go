@@ -0,0 +1,112 @@
+package reolink
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// maxSerialPayload is the largest raw payload accepted by PtzSerialSend
+const maxSerialPayload = 64
+
+// PELCO control protocols reported in PtzSerial.CtrlProtocol
+const (
+	PelcoProtocolD = "PELCO_D"
+	PelcoProtocolP = "PELCO_P"
+)
+
+// PelcoD builds a 7-byte PELCO-D frame: sync, address, command 1,
+// command 2, data 1, data 2 and the modulo-256 checksum of bytes 1-5
+func PelcoD(addr, cmd1, cmd2, data1, data2 byte) []byte {
+	frame := []byte{0xff, addr, cmd1, cmd2, data1, data2, 0}
+	var sum byte
+	for _, b := range frame[1:6] {
+		sum += b
+	}
+	frame[6] = sum
+	return frame
+}
+
+// PelcoP builds an 8-byte PELCO-P frame: STX, address, data 1-4, ETX and
+// the XOR checksum of bytes 0-6. PELCO-P addresses are zero-based on the
+// wire, so a positioner configured as address 1 is addressed as 0.
+func PelcoP(addr, data1, data2, data3, data4 byte) []byte {
+	frame := []byte{0xa0, addr, data1, data2, data3, data4, 0xaf, 0}
+	var sum byte
+	for _, b := range frame[:7] {
+		sum ^= b
+	}
+	frame[7] = sum
+	return frame
+}
+
+// SendSerial sends a raw payload out of the camera's RS-485 port, e.g. a
+// PELCO-D/P frame for a third-party positioner. The port settings (baud
+// rate, parity) come from SetPtzSerial. The command is not part of the
+// public API guide and is only available on models with an RS-485 port.
+func (p *PTZAPI) SendSerial(ctx context.Context, channel int, payload []byte) error {
+	if len(payload) == 0 {
+		return fmt.Errorf("serial payload must not be empty")
+	}
+	if len(payload) > maxSerialPayload {
+		return fmt.Errorf("serial payload too large: %d bytes (max %d)", len(payload), maxSerialPayload)
+	}
+
+	data := strings.ToUpper(hex.EncodeToString(payload))
+	p.client.log(ctx).Info("sending serial payload: channel=%d data=%s", channel, data)
+
+	req := []Request{{
+		Cmd:    "PtzSerialSend",
+		Action: 0,
+		Param: map[string]interface{}{
+			"channel": channel,
+			"data":    data,
+		},
+	}}
+
+	var resp []Response
+	if err := p.client.do(ctx, req, &resp); err != nil {
+		p.client.log(ctx).Error("failed to send serial payload: %v", err)
+		return fmt.Errorf("PtzSerialSend request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		p.client.log(ctx).Error("failed to send serial payload: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		p.client.log(ctx).Error("failed to send serial payload: %v", apiErr)
+		return apiErr
+	}
+
+	p.client.log(ctx).Info("successfully sent serial payload")
+	return nil
+}
+
+// SendPelco sends a PELCO command to the positioner configured in the
+// channel's PtzSerial settings, framing it for its protocol and address.
+// The four bytes are command 1, command 2, data 1 and data 2 for PELCO-D,
+// and data 1-4 for PELCO-P.
+func (p *PTZAPI) SendPelco(ctx context.Context, channel int, b1, b2, b3, b4 byte) error {
+	serial, err := p.GetPtzSerial(ctx, channel)
+	if err != nil {
+		return fmt.Errorf("failed to read serial configuration: %w", err)
+	}
+	if serial.CtrlAddr < 1 || serial.CtrlAddr > 255 {
+		return fmt.Errorf("invalid PTZ control address %d", serial.CtrlAddr)
+	}
+
+	var frame []byte
+	switch serial.CtrlProtocol {
+	case PelcoProtocolD:
+		frame = PelcoD(byte(serial.CtrlAddr), b1, b2, b3, b4)
+	case PelcoProtocolP:
+		frame = PelcoP(byte(serial.CtrlAddr-1), b1, b2, b3, b4)
+	default:
+		return fmt.Errorf("unsupported PTZ control protocol %q", serial.CtrlProtocol)
+	}
+	return p.SendSerial(ctx, channel, frame)
+}
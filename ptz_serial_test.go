@@ -0,0 +1,70 @@
+package reolink
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestPelcoD(t *testing.T) {
+	// Pan right at speed 0x20 on address 1
+	want := []byte{0xff, 0x01, 0x00, 0x02, 0x20, 0x00, 0x23}
+	if got := PelcoD(0x01, 0x00, 0x02, 0x20, 0x00); !bytes.Equal(got, want) {
+		t.Errorf("expected % x, got % x", want, got)
+	}
+}
+
+func TestPelcoP(t *testing.T) {
+	// Pan right at speed 0x20 on wire address 0
+	want := []byte{0xa0, 0x00, 0x00, 0x02, 0x20, 0x00, 0xaf, 0x2d}
+	if got := PelcoP(0x00, 0x00, 0x02, 0x20, 0x00); !bytes.Equal(got, want) {
+		t.Errorf("expected % x, got % x", want, got)
+	}
+}
+
+func TestPTZAPI_SendSerial(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"PtzSerialSend": ""})
+	client := server.client()
+
+	if err := client.PTZ.SendSerial(t.Context(), 0, []byte{0xff, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01}); err != nil {
+		t.Fatalf("SendSerial failed: %v", err)
+	}
+	var param struct {
+		Data string `json:"data"`
+	}
+	json.Unmarshal(server.lastParam("PtzSerialSend"), &param)
+	if param.Data != "FF010000000001" {
+		t.Errorf("expected hex payload, got %q", param.Data)
+	}
+
+	if err := client.PTZ.SendSerial(t.Context(), 0, nil); err == nil {
+		t.Error("expected error for an empty payload")
+	}
+	if err := client.PTZ.SendSerial(t.Context(), 0, make([]byte, maxSerialPayload+1)); err == nil {
+		t.Error("expected error for an oversized payload")
+	}
+}
+
+func TestPTZAPI_SendPelco(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetPtzSerial":  `{"PtzSerial": {"channel": 0, "baudRate": 2400, "ctrlAddr": 1, "ctrlProtocol": "PELCO_P", "dataBit": "CS8", "flowCtrl": "none", "parity": "none", "stopBit": 1}}`,
+		"PtzSerialSend": "",
+	})
+	client := server.client()
+
+	if err := client.PTZ.SendPelco(t.Context(), 0, 0x00, 0x02, 0x20, 0x00); err != nil {
+		t.Fatalf("SendPelco failed: %v", err)
+	}
+	var param struct {
+		Data string `json:"data"`
+	}
+	json.Unmarshal(server.lastParam("PtzSerialSend"), &param)
+	if param.Data != "A00000022000AF2D" {
+		t.Errorf("expected PELCO-P frame for wire address 0, got %q", param.Data)
+	}
+
+	server.set("GetPtzSerial", `{"PtzSerial": {"channel": 0, "ctrlAddr": 1, "ctrlProtocol": "RS485_CUSTOM"}}`)
+	if err := client.PTZ.SendPelco(t.Context(), 0, 0, 0, 0, 0); err == nil {
+		t.Error("expected error for an unsupported protocol")
+	}
+}
@@ -158,6 +158,53 @@ func TestPTZAPI_SetPtzPreset(t *testing.T) {
 	}
 }
 
+func TestPTZAPI_SetPtzPreset_SavesOnly(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"SetPtzPreset": ""})
+	client := server.client()
+
+	err := client.PTZ.SetPtzPreset(t.Context(), PtzPreset{Channel: 1, Enable: 1, ID: 3, Name: "Gate"})
+	if err != nil {
+		t.Fatalf("SetPtzPreset failed: %v", err)
+	}
+	if n := server.callCount("PtzCtrl"); n != 0 {
+		t.Errorf("expected SetPtzPreset not to move the camera, got %d PtzCtrl calls", n)
+	}
+
+	var param PtzPresetParam
+	json.Unmarshal(server.lastParam("SetPtzPreset"), &param)
+	if param.PtzPreset.Channel != 1 || param.PtzPreset.ID != 3 || param.PtzPreset.Name != "Gate" {
+		t.Errorf("unexpected preset sent: %+v", param.PtzPreset)
+	}
+
+	if err := client.PTZ.SetPtzPreset(t.Context(), PtzPreset{ID: 0}); err == nil {
+		t.Error("expected error for preset ID 0")
+	}
+}
+
+func TestPTZAPI_CallPreset(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"PtzCtrl": ""})
+	client := server.client()
+
+	if err := client.PTZ.CallPreset(t.Context(), 0, 5); err != nil {
+		t.Fatalf("CallPreset failed: %v", err)
+	}
+	if n := server.callCount("SetPtzPreset"); n != 0 {
+		t.Errorf("expected CallPreset not to save a preset, got %d SetPtzPreset calls", n)
+	}
+
+	var param PtzCtrlParam
+	json.Unmarshal(server.lastParam("PtzCtrl"), &param)
+	if param.Op != PTZOpToPos || param.ID != 5 || param.Channel != 0 {
+		t.Errorf("unexpected PtzCtrl param: %+v", param)
+	}
+
+	for _, id := range []int{0, maxPtzPresetID + 1} {
+		if err := client.PTZ.CallPreset(t.Context(), 0, id); err == nil {
+			t.Errorf("expected error for preset ID %d", id)
+		}
+	}
+}
+
 func TestPTZAPI_GetPtzPatrol(t *testing.T) {
 	// Create mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
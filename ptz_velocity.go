@@ -0,0 +1,120 @@
+package reolink
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Joystick mapping parameters
+const (
+	// velocityDeadzone is the axis magnitude below which input counts as zero
+	velocityDeadzone = 0.05
+	// velocityDiagonal is the minor/major axis ratio (tan 22.5°) above
+	// which pan and tilt combine into a diagonal move
+	velocityDiagonal = 0.414
+	// velocityMinInterval is the shortest time between speed-only updates
+	// of the same movement
+	velocityMinInterval = 100 * time.Millisecond
+)
+
+// ptzVelocity is the last movement sent by Velocity for a channel
+type ptzVelocity struct {
+	op    string
+	speed int
+	sent  time.Time
+}
+
+// Velocity drives a channel from normalized joystick axes in -1..1: pan is
+// positive to the right, tilt positive up and zoom positive in. Pan and tilt
+// take precedence over zoom, combining into a diagonal move when both are
+// significant, and the largest axis sets the speed (1-64). Input inside a
+// small deadzone on every axis stops the camera.
+//
+// Velocity is meant to be called on every joystick update. Repeated input
+// that maps to the current movement sends nothing, speed changes are sent
+// at most every 100ms, and direction changes and stops are sent at once.
+func (p *PTZAPI) Velocity(ctx context.Context, channel int, pan, tilt, zoom float64) error {
+	op, speed := velocityOp(pan, tilt, zoom)
+
+	p.velocityMu.Lock()
+	if p.velocity == nil {
+		p.velocity = make(map[int]*ptzVelocity)
+	}
+	last, ok := p.velocity[channel]
+	if !ok {
+		last = &ptzVelocity{op: PTZOpStop}
+		p.velocity[channel] = last
+	}
+	now := time.Now()
+	skip := op == last.op && (op == PTZOpStop || speed == last.speed || now.Sub(last.sent) < velocityMinInterval)
+	p.velocityMu.Unlock()
+	if skip {
+		return nil
+	}
+
+	param := PtzCtrlParam{Channel: channel, Op: op}
+	if op != PTZOpStop {
+		param.Speed = speed
+	}
+	if err := p.PtzCtrl(ctx, param); err != nil {
+		return err
+	}
+
+	p.velocityMu.Lock()
+	*last = ptzVelocity{op: op, speed: speed, sent: now}
+	p.velocityMu.Unlock()
+	return nil
+}
+
+// velocityOp maps joystick axes to a PtzCtrl operation and speed
+func velocityOp(pan, tilt, zoom float64) (string, int) {
+	pan, tilt, zoom = clampAxis(pan), clampAxis(tilt), clampAxis(zoom)
+	ap, at, az := math.Abs(pan), math.Abs(tilt), math.Abs(zoom)
+
+	if ap < velocityDeadzone && at < velocityDeadzone {
+		switch {
+		case az < velocityDeadzone:
+			return PTZOpStop, 0
+		case zoom > 0:
+			return PTZOpZoomInc, axisSpeed(az)
+		default:
+			return PTZOpZoomDec, axisSpeed(az)
+		}
+	}
+
+	speed := axisSpeed(max(ap, at))
+	horizontal := ap >= velocityDeadzone && at <= ap && at < ap*velocityDiagonal
+	vertical := at >= velocityDeadzone && ap <= at && ap < at*velocityDiagonal
+	switch {
+	case horizontal && pan > 0:
+		return PTZOpRight, speed
+	case horizontal:
+		return PTZOpLeft, speed
+	case vertical && tilt > 0:
+		return PTZOpUp, speed
+	case vertical:
+		return PTZOpDown, speed
+	case pan > 0 && tilt > 0:
+		return PTZOpRightUp, speed
+	case pan > 0:
+		return PTZOpRightDown, speed
+	case tilt > 0:
+		return PTZOpLeftUp, speed
+	default:
+		return PTZOpLeftDown, speed
+	}
+}
+
+// clampAxis limits an axis value to -1..1, treating NaN as zero
+func clampAxis(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Max(-1, math.Min(1, v))
+}
+
+// axisSpeed maps an axis magnitude above the deadzone to a speed of 1-64
+func axisSpeed(magnitude float64) int {
+	return max(1, min(64, int(math.Ceil(magnitude*64))))
+}
@@ -0,0 +1,77 @@
+package reolink
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestVelocityOp(t *testing.T) {
+	tests := []struct {
+		pan, tilt, zoom float64
+		op              string
+		speed           int
+	}{
+		{0, 0, 0, PTZOpStop, 0},
+		{0.01, -0.02, 0.03, PTZOpStop, 0},
+		{1, 0, 0, PTZOpRight, 64},
+		{-0.5, 0.1, 0, PTZOpLeft, 32},
+		{0, 0.25, 0, PTZOpUp, 16},
+		{0.2, -1, 0, PTZOpDown, 64},
+		{0.7, 0.7, 0, PTZOpRightUp, 45},
+		{0.5, -0.4, 0, PTZOpRightDown, 32},
+		{-0.6, 0.5, 0, PTZOpLeftUp, 39},
+		{-1, -1, 0, PTZOpLeftDown, 64},
+		{0, 0, 0.5, PTZOpZoomInc, 32},
+		{0, 0, -2, PTZOpZoomDec, 64},
+		{0.5, 0, 1, PTZOpRight, 32},
+		{math.NaN(), 0, 0, PTZOpStop, 0},
+	}
+	for _, tt := range tests {
+		op, speed := velocityOp(tt.pan, tt.tilt, tt.zoom)
+		if op != tt.op || speed != tt.speed {
+			t.Errorf("velocityOp(%v, %v, %v) = %s/%d, expected %s/%d",
+				tt.pan, tt.tilt, tt.zoom, op, speed, tt.op, tt.speed)
+		}
+	}
+}
+
+func TestPTZAPI_Velocity(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"PtzCtrl": ""})
+	client := server.client()
+	ctx := t.Context()
+
+	// Stopped camera and zero input: nothing to send
+	if err := client.PTZ.Velocity(ctx, 1, 0, 0, 0); err != nil {
+		t.Fatalf("Velocity failed: %v", err)
+	}
+	if n := server.callCount("PtzCtrl"); n != 0 {
+		t.Fatalf("expected no request for zero input, got %d", n)
+	}
+
+	client.PTZ.Velocity(ctx, 1, 0.5, 0, 0)
+	// Same movement and speed, then a speed change within the rate cap
+	client.PTZ.Velocity(ctx, 1, 0.5, 0, 0)
+	client.PTZ.Velocity(ctx, 1, 0.6, 0, 0)
+	if n := server.callCount("PtzCtrl"); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+
+	// Speed change after the rate cap
+	time.Sleep(velocityMinInterval)
+	client.PTZ.Velocity(ctx, 1, 0.75, 0, 0)
+	var param PtzCtrlParam
+	json.Unmarshal(server.lastParam("PtzCtrl"), &param)
+	if server.callCount("PtzCtrl") != 2 || param.Op != PTZOpRight || param.Speed != 48 || param.Channel != 1 {
+		t.Errorf("expected speed update to 48, got %+v", param)
+	}
+
+	// Direction change and stop are immediate
+	client.PTZ.Velocity(ctx, 1, 0, -0.5, 0)
+	client.PTZ.Velocity(ctx, 1, 0, 0, 0)
+	json.Unmarshal(server.lastParam("PtzCtrl"), &param)
+	if server.callCount("PtzCtrl") != 4 || param.Op != PTZOpStop {
+		t.Errorf("expected a stop as the 4th request, got %d requests, last %+v", server.callCount("PtzCtrl"), param)
+	}
+}
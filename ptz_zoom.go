@@ -0,0 +1,102 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// StartZoomFocus operations that move to an absolute position
+const (
+	ZoomFocusOpZoomPos  = "ZoomPos"
+	ZoomFocusOpFocusPos = "FocusPos"
+)
+
+// PosRange is the valid range of a zoom or focus position
+type PosRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// ZoomFocusRange is the zoom and focus position range of a channel, as
+// returned by GetZoomFocus with action 1
+type ZoomFocusRange struct {
+	Zoom struct {
+		Pos PosRange `json:"pos"`
+	} `json:"zoom"`
+	Focus struct {
+		Pos PosRange `json:"pos"`
+	} `json:"focus"`
+}
+
+// ZoomFocusRangeValue wraps ZoomFocusRange for API response
+type ZoomFocusRangeValue struct {
+	ZoomFocus ZoomFocusRange `json:"ZoomFocus"`
+}
+
+// GetZoomFocusRange gets the zoom and focus position ranges of a channel
+func (p *PTZAPI) GetZoomFocusRange(ctx context.Context, channel int) (*ZoomFocusRange, error) {
+	p.client.log(ctx).Debug("getting zoom/focus range: channel=%d", channel)
+
+	req := []Request{{
+		Cmd:    "GetZoomFocus",
+		Action: 1, // Get value, initial and range
+		Param: map[string]interface{}{
+			"channel": channel,
+		},
+	}}
+
+	var resp []Response
+	if err := p.client.do(ctx, req, &resp); err != nil {
+		p.client.log(ctx).Error("failed to get zoom/focus range: %v", err)
+		return nil, fmt.Errorf("GetZoomFocus request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		p.client.log(ctx).Error("failed to get zoom/focus range: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		p.client.log(ctx).Error("failed to get zoom/focus range: %v", apiErr)
+		return nil, apiErr
+	}
+
+	if len(resp[0].Range) == 0 {
+		return nil, fmt.Errorf("GetZoomFocus response contains no range")
+	}
+
+	var value ZoomFocusRangeValue
+	if err := p.client.unmarshal(resp[0].Range, &value); err != nil {
+		p.client.log(ctx).Error("failed to parse zoom/focus range response: %v", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	p.client.log(ctx).Info("successfully retrieved zoom/focus range: zoom=%d-%d focus=%d-%d",
+		value.ZoomFocus.Zoom.Pos.Min, value.ZoomFocus.Zoom.Pos.Max,
+		value.ZoomFocus.Focus.Pos.Min, value.ZoomFocus.Focus.Pos.Max)
+	return &value.ZoomFocus, nil
+}
+
+// SetZoomRatio zooms to a fraction of the lens's zoom range, from 0 (widest)
+// to 1 (full telephoto). Raw zoom positions differ between models, so the
+// ratio is mapped onto the range the camera reports. The mapping is linear
+// in lens position, which is not necessarily linear in magnification.
+func (p *PTZAPI) SetZoomRatio(ctx context.Context, channel int, ratio float64) error {
+	if math.IsNaN(ratio) || ratio < 0 || ratio > 1 {
+		return fmt.Errorf("zoom ratio must be between 0 and 1, got %v", ratio)
+	}
+
+	rng, err := p.GetZoomFocusRange(ctx, channel)
+	if err != nil {
+		return fmt.Errorf("failed to read zoom range: %w", err)
+	}
+	zoom := rng.Zoom.Pos
+	if zoom.Max <= zoom.Min {
+		return fmt.Errorf("camera reports no zoom range")
+	}
+
+	pos := zoom.Min + int(math.Round(ratio*float64(zoom.Max-zoom.Min)))
+	return p.StartZoomFocus(ctx, channel, ZoomFocusOpZoomPos, pos)
+}
@@ -0,0 +1,73 @@
+package reolink
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testZoomFocusRange = `{"ZoomFocus": {"zoom": {"pos": {"min": 0, "max": 33}}, "focus": {"pos": {"min": 0, "max": 223}}}}`
+
+func TestPTZAPI_GetZoomFocusRange(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetZoomFocus": `{"ZoomFocus": {"channel": 0, "zoom": {"pos": 5}, "focus": {"pos": 100}}}`,
+	})
+	client := server.client()
+
+	if _, err := client.PTZ.GetZoomFocusRange(t.Context(), 0); err == nil {
+		t.Error("expected error when the response has no range")
+	}
+
+	server.setRange("GetZoomFocus", testZoomFocusRange)
+	rng, err := client.PTZ.GetZoomFocusRange(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("GetZoomFocusRange failed: %v", err)
+	}
+	if rng.Zoom.Pos.Max != 33 || rng.Focus.Pos.Max != 223 {
+		t.Errorf("unexpected range: %+v", rng)
+	}
+}
+
+func TestPTZAPI_SetZoomRatio(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetZoomFocus":   `{"ZoomFocus": {"channel": 0, "zoom": {"pos": 5}, "focus": {"pos": 100}}}`,
+		"StartZoomFocus": "",
+	})
+	server.setRange("GetZoomFocus", `{"ZoomFocus": {"zoom": {"pos": {"min": 10, "max": 30}}}}`)
+	client := server.client()
+
+	tests := []struct {
+		ratio float64
+		pos   int
+	}{
+		{0, 10},
+		{0.5, 20},
+		{0.33, 17},
+		{1, 30},
+	}
+	for _, tt := range tests {
+		if err := client.PTZ.SetZoomRatio(t.Context(), 0, tt.ratio); err != nil {
+			t.Fatalf("SetZoomRatio(%v) failed: %v", tt.ratio, err)
+		}
+		var param struct {
+			ZoomFocus struct {
+				Op  string `json:"op"`
+				Pos int    `json:"pos"`
+			} `json:"ZoomFocus"`
+		}
+		json.Unmarshal(server.lastParam("StartZoomFocus"), &param)
+		if param.ZoomFocus.Op != ZoomFocusOpZoomPos || param.ZoomFocus.Pos != tt.pos {
+			t.Errorf("SetZoomRatio(%v): expected ZoomPos %d, got %s %d", tt.ratio, tt.pos, param.ZoomFocus.Op, param.ZoomFocus.Pos)
+		}
+	}
+
+	for _, ratio := range []float64{-0.1, 1.5} {
+		if err := client.PTZ.SetZoomRatio(t.Context(), 0, ratio); err == nil {
+			t.Errorf("expected error for ratio %v", ratio)
+		}
+	}
+
+	server.setRange("GetZoomFocus", `{"ZoomFocus": {"zoom": {"pos": {"min": 0, "max": 0}}}}`)
+	if err := client.PTZ.SetZoomRatio(t.Context(), 0, 0.5); err == nil {
+		t.Error("expected error for a camera without zoom range")
+	}
+}
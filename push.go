@@ -0,0 +1,97 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Commands the Reolink mobile app uses to register its push token with a
+// camera. They are not part of the published HTTP API guide; firmware
+// without cloud push answers them with ErrCodeNotSupported.
+const (
+	cmdPushAdd = "PushAdd"
+	cmdPushDel = "PushDel"
+)
+
+// PushRegistration identifies a mobile device to the Reolink cloud push
+// service
+type PushRegistration struct {
+	Token     string `json:"to"`                  // Push token issued to the app (FCM or APNs)
+	ClientID  string `json:"clientID,omitempty"`  // Installation ID, unique per app install
+	PhoneType string `json:"phoneType,omitempty"` // Platform, e.g. "reo_fcm" or "reo_iphoneid"
+
+	// Extra holds additional PushInfo fields to send as-is, for firmware
+	// that expects fields not modelled here
+	Extra map[string]interface{} `json:"-"`
+}
+
+// pushInfo returns the PushInfo param for r
+func (r PushRegistration) pushInfo() (map[string]interface{}, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	info := make(map[string]interface{})
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	for k, v := range r.Extra {
+		info[k] = v
+	}
+	return info, nil
+}
+
+// RegisterPush registers a push token with the camera, so the Reolink cloud
+// delivers its alarm notifications to that device. Push must also be
+// enabled with SetPush or SetPushV20.
+func (n *NetworkAPI) RegisterPush(ctx context.Context, reg PushRegistration) error {
+	n.client.log(ctx).Info("registering push token: clientID=%s", reg.ClientID)
+	if err := n.pushToken(ctx, cmdPushAdd, reg); err != nil {
+		n.client.log(ctx).Error("failed to register push token: %v", err)
+		return err
+	}
+	n.client.log(ctx).Info("successfully registered push token")
+	return nil
+}
+
+// UnregisterPush removes a push token registered with RegisterPush
+func (n *NetworkAPI) UnregisterPush(ctx context.Context, reg PushRegistration) error {
+	n.client.log(ctx).Info("unregistering push token: clientID=%s", reg.ClientID)
+	if err := n.pushToken(ctx, cmdPushDel, reg); err != nil {
+		n.client.log(ctx).Error("failed to unregister push token: %v", err)
+		return err
+	}
+	n.client.log(ctx).Info("successfully unregistered push token")
+	return nil
+}
+
+// pushToken sends cmd with reg as its PushInfo param
+func (n *NetworkAPI) pushToken(ctx context.Context, cmd string, reg PushRegistration) error {
+	if reg.Token == "" {
+		return fmt.Errorf("push token is required")
+	}
+	info, err := reg.pushInfo()
+	if err != nil {
+		return fmt.Errorf("failed to encode push registration: %w", err)
+	}
+
+	req := []Request{{
+		Cmd: cmd,
+		Param: map[string]interface{}{
+			"PushInfo": info,
+		},
+	}}
+
+	var resp []Response
+	if err := n.client.do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("%s request failed: %w", cmd, err)
+	}
+	if len(resp) == 0 {
+		return fmt.Errorf("empty response from %s", cmd)
+	}
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		return apiErr
+	}
+	return nil
+}
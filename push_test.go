@@ -0,0 +1,61 @@
+package reolink
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNetworkAPI_RegisterPush(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"PushAdd": "",
+		"PushDel": "",
+	})
+	client := server.client()
+
+	reg := PushRegistration{
+		Token:     "fcm-token",
+		ClientID:  "install-1",
+		PhoneType: "reo_fcm",
+		Extra:     map[string]interface{}{"appVersion": "4.40"},
+	}
+	if err := client.Network.RegisterPush(t.Context(), reg); err != nil {
+		t.Fatalf("RegisterPush failed: %v", err)
+	}
+
+	var param struct {
+		PushInfo map[string]interface{} `json:"PushInfo"`
+	}
+	if err := json.Unmarshal(server.lastParam("PushAdd"), &param); err != nil {
+		t.Fatal(err)
+	}
+	if param.PushInfo["to"] != "fcm-token" || param.PushInfo["clientID"] != "install-1" ||
+		param.PushInfo["phoneType"] != "reo_fcm" || param.PushInfo["appVersion"] != "4.40" {
+		t.Errorf("unexpected PushInfo: %v", param.PushInfo)
+	}
+
+	if err := client.Network.UnregisterPush(t.Context(), reg); err != nil {
+		t.Fatalf("UnregisterPush failed: %v", err)
+	}
+	if server.callCount("PushDel") != 1 {
+		t.Error("expected one PushDel request")
+	}
+}
+
+func TestNetworkAPI_RegisterPush_Errors(t *testing.T) {
+	server := newCmdServer(t, nil)
+	client := server.client()
+
+	if err := client.Network.RegisterPush(t.Context(), PushRegistration{}); err == nil {
+		t.Error("expected error for empty token")
+	}
+	if server.callCount("PushAdd") != 0 {
+		t.Error("request sent without a token")
+	}
+
+	err := client.Network.RegisterPush(t.Context(), PushRegistration{Token: "t"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RspCode != ErrCodeNotSupported {
+		t.Errorf("expected not supported error, got %v", err)
+	}
+}
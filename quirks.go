@@ -0,0 +1,282 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Quirk adjusts client behaviour for devices whose firmware deviates from
+// the API guide. Quirks matching the device are applied whenever
+// System.GetDeviceInfo succeeds.
+type Quirk struct {
+	Name           string // Short identifier used in logs
+	Model          string // Model to match, case-insensitive; a trailing "*" matches a prefix and "" any model
+	FirmwareFrom   string // First affected firmware, e.g. "v3.0.0.0"; "" for no lower bound
+	FirmwareBefore string // First fixed firmware, exclusive; "" for no upper bound
+
+	// RTSPPath replaces the RTSP path pattern. "{channel}" is replaced by
+	// the two-digit 1-based channel and "{stream}" by the stream type.
+	RTSPPath string
+	// RTSPTransport is the RTSP transport StreamInfo recommends instead of
+	// RTSPTransportTCP; RTSPTransportTCP also drops UDP from the served
+	// transports, for devices whose UDP streams break
+	RTSPTransport RTSPTransport
+	// ActionOne lists commands that only return a value with Action 1
+	ActionOne []string
+	// HTTPCmds lists commands sent over plain HTTP because the device's
+	// HTTPS endpoint fails them; they go to the camera's HTTP port with a
+	// warning, or stay on HTTPS if HTTP is disabled
+	HTTPCmds []string
+	// ASCIINames marks firmware that mangles non-ASCII device names;
+	// SetDeviceName then rejects or transliterates them
+	ASCIINames bool
+	// NoOvernightSchedule marks firmware that rejects or misapplies white
+	// LED schedules ending before they start; SetWhiteLed then rejects
+	// them and RunWhiteLedSchedule splits them
+	NoOvernightSchedule bool
+}
+
+// builtinQuirks are the quirks known for released firmware
+var builtinQuirks = []Quirk{
+	{
+		Name:           "h264-rtsp-path",
+		FirmwareBefore: "v3.0.0.0",
+		RTSPPath:       "h264Preview_{channel}_{stream}",
+	},
+}
+
+// defaultRTSPPath is the RTSP path pattern of current firmware
+const defaultRTSPPath = "Preview_{channel}_{stream}"
+
+// WithQuirks registers additional quirks, matched after the built-in ones,
+// so a later quirk's RTSPPath takes precedence
+func WithQuirks(quirks ...Quirk) Option {
+	return func(c *Client) {
+		c.customQuirks = append(c.customQuirks, quirks...)
+	}
+}
+
+// Matches reports whether q applies to model running firmware
+func (q Quirk) Matches(model, firmware string) bool {
+	if q.Model != "" {
+		if prefix, ok := strings.CutSuffix(q.Model, "*"); ok {
+			if !strings.HasPrefix(strings.ToLower(model), strings.ToLower(prefix)) {
+				return false
+			}
+		} else if !strings.EqualFold(q.Model, model) {
+			return false
+		}
+	}
+	if q.FirmwareFrom != "" && compareFirmware(firmware, q.FirmwareFrom) < 0 {
+		return false
+	}
+	if q.FirmwareBefore != "" && compareFirmware(firmware, q.FirmwareBefore) >= 0 {
+		return false
+	}
+	return true
+}
+
+// compareFirmware compares firmware versions such as "v3.0.0.59_20080734"
+// by their numeric dotted components, ignoring the build date suffix
+func compareFirmware(a, b string) int {
+	pa, pb := firmwareParts(a), firmwareParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// firmwareParts returns the numeric components of a firmware version
+func firmwareParts(version string) []int {
+	version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+	version, _, _ = strings.Cut(version, "_")
+	var parts []int
+	for _, s := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// Quirks returns the quirks applied to the device, empty until
+// System.GetDeviceInfo has succeeded
+func (c *Client) Quirks() []Quirk {
+	c.quirkMu.RLock()
+	defer c.quirkMu.RUnlock()
+	return slices.Clone(c.quirks)
+}
+
+// applyQuirks selects the quirks matching info
+func (c *Client) applyQuirks(ctx context.Context, info *DeviceInfo) {
+	var active []Quirk
+	for _, q := range append(slices.Clone(builtinQuirks), c.customQuirks...) {
+		if q.Matches(info.Model, info.FirmVer) {
+			c.log(ctx).Info("applying firmware quirk: name=%s model=%s firmware=%s", q.Name, info.Model, info.FirmVer)
+			active = append(active, q)
+		}
+	}
+	c.quirkMu.Lock()
+	c.quirks = active
+	c.quirkMu.Unlock()
+}
+
+// quirkRTSPPath returns the RTSP path pattern for the device
+func (c *Client) quirkRTSPPath() string {
+	c.quirkMu.RLock()
+	defer c.quirkMu.RUnlock()
+	path := defaultRTSPPath
+	for _, q := range c.quirks {
+		if q.RTSPPath != "" {
+			path = q.RTSPPath
+		}
+	}
+	return path
+}
+
+// quirkRTSPTransport returns the RTSP transport a quirk forces for the
+// device, or "" if none does
+func (c *Client) quirkRTSPTransport() RTSPTransport {
+	c.quirkMu.RLock()
+	defer c.quirkMu.RUnlock()
+	var transport RTSPTransport
+	for _, q := range c.quirks {
+		if q.RTSPTransport != "" {
+			transport = q.RTSPTransport
+		}
+	}
+	return transport
+}
+
+// quirkASCIINames reports whether the device only stores ASCII names
+func (c *Client) quirkASCIINames() bool {
+	c.quirkMu.RLock()
+	defer c.quirkMu.RUnlock()
+	for _, q := range c.quirks {
+		if q.ASCIINames {
+			return true
+		}
+	}
+	return false
+}
+
+// quirkNoOvernightSchedule reports whether the device cannot apply
+// lighting schedules across midnight
+func (c *Client) quirkNoOvernightSchedule() bool {
+	c.quirkMu.RLock()
+	defer c.quirkMu.RUnlock()
+	for _, q := range c.quirks {
+		if q.NoOvernightSchedule {
+			return true
+		}
+	}
+	return false
+}
+
+// quirkRequests returns requests with Action 1 set on commands that need
+// it, copying the slice only when something changes
+func (c *Client) quirkRequests(requests []Request) []Request {
+	c.quirkMu.RLock()
+	defer c.quirkMu.RUnlock()
+	if len(c.quirks) == 0 {
+		return requests
+	}
+	out, copied := requests, false
+	for i, req := range requests {
+		if req.Action != 0 || !c.quirkHas(func(q Quirk) []string { return q.ActionOne }, req.Cmd) {
+			continue
+		}
+		if !copied {
+			out, copied = slices.Clone(requests), true
+		}
+		out[i].Action = 1
+	}
+	return out
+}
+
+// quirkBaseURL returns the URL to send cmd to. Commands an active quirk
+// lists in HTTPCmds go over plain HTTP to the camera's HTTP port, read
+// with GetNetPort if not known yet, and a warning is logged since the
+// token then travels unencrypted. They stay on HTTPS if the port cannot be
+// read or HTTP is disabled. GetNetPort itself always stays on HTTPS.
+func (c *Client) quirkBaseURL(ctx context.Context, cmd string) string {
+	c.endpointMu.RLock()
+	host, https, baseURL := c.host, c.useHTTPS, c.baseURL
+	c.endpointMu.RUnlock()
+	if !https || cmd == "GetNetPort" {
+		return baseURL
+	}
+	c.quirkMu.RLock()
+	downgrade := c.quirkHas(func(q Quirk) []string { return q.HTTPCmds }, cmd)
+	c.quirkMu.RUnlock()
+	if !downgrade {
+		return baseURL
+	}
+
+	port := c.httpPort.Load()
+	if port == 0 {
+		if _, err := c.Network.GetNetPort(ctx); err != nil {
+			c.log(ctx).Warn("sending %s over HTTPS despite a firmware quirk: failed to read the HTTP port: %v", cmd, err)
+			return baseURL
+		}
+		port = c.httpPort.Load()
+	}
+	if port < 0 {
+		c.log(ctx).Warn("sending %s over HTTPS despite a firmware quirk: HTTP is disabled on the camera", cmd)
+		return baseURL
+	}
+
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if port != 80 {
+		hostname = net.JoinHostPort(hostname, strconv.Itoa(int(port)))
+	} else if strings.Contains(hostname, ":") {
+		hostname = "[" + hostname + "]" // IPv6 literal
+	}
+	c.log(ctx).Warn("sending %s over plain HTTP to port %d because of a firmware quirk", cmd, port)
+	return fmt.Sprintf("http://%s/cgi-bin/api.cgi", hostname)
+}
+
+// rememberHTTPPort records the camera's HTTP port from its port
+// configuration for quirkBaseURL, -1 if HTTP is disabled
+func (c *Client) rememberHTTPPort(netPort *NetPort) {
+	switch {
+	case !netPort.HTTPEnable.Bool() && netPort.HTTPSEnable.Bool():
+		c.httpPort.Store(-1)
+	case netPort.HTTPPort > 0:
+		c.httpPort.Store(int32(netPort.HTTPPort))
+	default:
+		c.httpPort.Store(80)
+	}
+}
+
+// quirkHas reports whether a list selected by field of an active quirk
+// contains cmd. The caller holds quirkMu.
+func (c *Client) quirkHas(field func(Quirk) []string, cmd string) bool {
+	for _, q := range c.quirks {
+		if slices.Contains(field(q), cmd) {
+			return true
+		}
+	}
+	return false
+}
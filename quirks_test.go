@@ -0,0 +1,182 @@
+package reolink
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestQuirk_Matches(t *testing.T) {
+	tests := []struct {
+		quirk    Quirk
+		model    string
+		firmware string
+		want     bool
+	}{
+		{Quirk{}, "RLC-811A", "v3.1.0.2368_23062700", true},
+		{Quirk{Model: "rlc-811a"}, "RLC-811A", "v3.1.0.2368", true},
+		{Quirk{Model: "RLC-811A"}, "RLC-810A", "v3.1.0.2368", false},
+		{Quirk{Model: "E1*"}, "E1 Zoom", "v3.0.0.716", true},
+		{Quirk{Model: "E1*"}, "RLC-510A", "v3.0.0.716", false},
+		{Quirk{FirmwareBefore: "v3.0.0.0"}, "RLC-410", "v2.0.0.1441_19032101", true},
+		{Quirk{FirmwareBefore: "v3.0.0.0"}, "RLC-410", "v3.0.0.0", false},
+		{Quirk{FirmwareFrom: "v3.1.0.2000"}, "RLC-811A", "v3.1.0.956_22041503", false},
+		{Quirk{FirmwareFrom: "v3.1.0.2000"}, "RLC-811A", "v3.1.0.2368_23062700", true},
+		{Quirk{FirmwareFrom: "v3.0", FirmwareBefore: "v3.1"}, "NVR", "v3.0.0.59_20080734", true},
+	}
+	for _, tt := range tests {
+		if got := tt.quirk.Matches(tt.model, tt.firmware); got != tt.want {
+			t.Errorf("%+v.Matches(%q, %q) = %v, want %v", tt.quirk, tt.model, tt.firmware, got, tt.want)
+		}
+	}
+}
+
+func TestClient_QuirksAppliedByGetDeviceInfo(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-410", "firmVer": "v2.0.0.1441_19032101"}}`,
+	})
+	client := server.client()
+
+	if got := client.Streaming.GetRTSPURL(StreamMain, 0); !strings.HasSuffix(got, "/Preview_01_main") {
+		t.Errorf("RTSP URL before GetDeviceInfo = %s", got)
+	}
+	if _, err := client.System.GetDeviceInfo(t.Context()); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if quirks := client.Quirks(); len(quirks) != 1 || quirks[0].Name != "h264-rtsp-path" {
+		t.Fatalf("unexpected quirks: %+v", quirks)
+	}
+	if got := client.Streaming.GetRTSPURL(StreamSub, 1); !strings.HasSuffix(got, "/h264Preview_02_sub") {
+		t.Errorf("RTSP URL after GetDeviceInfo = %s", got)
+	}
+}
+
+func TestClient_QuirkActionOne(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "E1 Zoom", "firmVer": "v3.0.0.716"}}`,
+		"GetTime":    `{"Time": {"year": 2024}}`,
+	})
+	server.setRange("GetTime", `{"Time": {"year": {"min": 2000, "max": 2037}}}`)
+	client := server.client()
+	WithQuirks(Quirk{Name: "time-action", Model: "E1*", ActionOne: []string{"GetTime"}})(client)
+
+	var resps []Response
+	if err := client.do(t.Context(), []Request{{Cmd: "GetTime"}}, &resps); err != nil {
+		t.Fatal(err)
+	}
+	if len(resps[0].Range) != 0 {
+		t.Fatal("action 1 sent before the quirk applied")
+	}
+
+	if _, err := client.System.GetDeviceInfo(t.Context()); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	req := []Request{{Cmd: "GetTime"}}
+	if err := client.do(t.Context(), req, &resps); err != nil {
+		t.Fatal(err)
+	}
+	if len(resps[0].Range) == 0 {
+		t.Error("expected action 1 after the quirk applied")
+	}
+	if req[0].Action != 0 {
+		t.Error("caller's request was modified")
+	}
+}
+
+func TestClient_QuirkHTTPCmds(t *testing.T) {
+	client := NewClient("192.168.1.10:443", WithHTTPS(true),
+		WithQuirks(Quirk{Name: "snap-http", HTTPCmds: []string{"GetOsd"}}))
+	client.applyQuirks(t.Context(), &DeviceInfo{Model: "RLC-510A", FirmVer: "v3.0.0.136"})
+
+	tests := []struct {
+		netPort NetPort
+		want    string
+	}{
+		{NetPort{HTTPEnable: 1, HTTPPort: 80, HTTPSEnable: 1}, "http://192.168.1.10/cgi-bin/api.cgi"},
+		{NetPort{HTTPEnable: 1, HTTPPort: 8080, HTTPSEnable: 1}, "http://192.168.1.10:8080/cgi-bin/api.cgi"},
+		{NetPort{HTTPEnable: 0, HTTPPort: 80, HTTPSEnable: 1}, "https://192.168.1.10:443/cgi-bin/api.cgi"},
+	}
+	for _, tt := range tests {
+		client.rememberHTTPPort(&tt.netPort)
+		if got := client.quirkBaseURL(t.Context(), "GetOsd"); got != tt.want {
+			t.Errorf("GetOsd URL with %+v = %s, want %s", tt.netPort, got, tt.want)
+		}
+	}
+	if got := client.quirkBaseURL(t.Context(), "GetTime"); got != "https://192.168.1.10:443/cgi-bin/api.cgi" {
+		t.Errorf("GetTime URL = %s", got)
+	}
+}
+
+func TestClient_QuirkHTTPCmds_ReadsPort(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`[{"cmd": "GetNetPort", "code": 0, "value": {"NetPort": {"httpEnable": 1, "httpPort": 8000, "httpsEnable": 1, "httpsPort": 443}}}]`))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+	client := NewClient(host, WithHTTPS(true), WithInsecureSkipVerify(true),
+		WithQuirks(Quirk{Name: "snap-http", HTTPCmds: []string{"GetOsd"}}))
+	client.SetToken("test-token")
+	client.applyQuirks(t.Context(), &DeviceInfo{Model: "RLC-510A"})
+
+	hostname, _, _ := net.SplitHostPort(host)
+	want := "http://" + net.JoinHostPort(hostname, "8000") + "/cgi-bin/api.cgi"
+	for range 2 {
+		if got := client.quirkBaseURL(t.Context(), "GetOsd"); got != want {
+			t.Errorf("GetOsd URL = %s, want %s", got, want)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected GetNetPort to be read once, got %d requests", calls.Load())
+	}
+
+	// Without the port the command stays on HTTPS
+	server.Close()
+	client = NewClient(host, WithHTTPS(true), WithInsecureSkipVerify(true),
+		WithQuirks(Quirk{Name: "snap-http", HTTPCmds: []string{"GetOsd"}}))
+	client.SetToken("test-token")
+	client.applyQuirks(t.Context(), &DeviceInfo{Model: "RLC-510A"})
+	if got := client.quirkBaseURL(t.Context(), "GetOsd"); got != "https://"+host+"/cgi-bin/api.cgi" {
+		t.Errorf("GetOsd URL without the HTTP port = %s", got)
+	}
+}
+
+func TestClient_QuirkHTTPCmds_DirectURLs(t *testing.T) {
+	server := newCmdServer(t, nil)
+	host := strings.TrimPrefix(server.URL, "http://")
+	_, port, _ := net.SplitHostPort(host)
+	httpPort, _ := strconv.Atoi(port)
+
+	// The client talks HTTPS to a port nothing listens on; the quirk sends
+	// Snap to the HTTP server instead
+	client := NewClient("127.0.0.1:1", WithHTTPS(true),
+		WithQuirks(Quirk{Name: "direct-http", HTTPCmds: []string{"Snap", "Playback", "Download"}}))
+	client.SetToken("test-token")
+	client.applyQuirks(t.Context(), &DeviceInfo{Model: "RLC-510A"})
+	client.rememberHTTPPort(&NetPort{HTTPEnable: 1, HTTPPort: httpPort, HTTPSEnable: 1})
+
+	if _, err := client.Encoding.Snap(t.Context(), 0); err != nil {
+		t.Fatalf("Snap failed: %v", err)
+	}
+	if n := server.callCount("Snap"); n != 1 {
+		t.Errorf("expected Snap over HTTP, got %d requests", n)
+	}
+
+	want := "http://127.0.0.1:" + port + "/cgi-bin/api.cgi?"
+	for _, build := range []func(string, string, PlaybackOptions) (string, error){
+		client.Recording.PlaybackURL, client.Recording.DownloadURL,
+	} {
+		url, err := build("rec.mp4", "rec.mp4", PlaybackOptions{})
+		if err != nil {
+			t.Fatalf("URL builder failed: %v", err)
+		}
+		if !strings.HasPrefix(url, want) {
+			t.Errorf("URL = %s, want prefix %s", url, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RawMeta receives the raw responses behind a wrapped call, so fields the
+// SDK does not model yet can be read without re-issuing the command.
+// Attach it to a context with WithRawMeta.
+type RawMeta struct {
+	// Responses are the decoded response envelopes, one per command in the
+	// batch, with Value, Initial and Range left as raw JSON
+	Responses []Response
+	// Body is the raw response body
+	Body []byte
+	// Cached reports whether the response was served from the client cache
+	Cached bool
+}
+
+// Value returns the raw value of the first response, or nil if there is
+// none
+func (m *RawMeta) Value() json.RawMessage {
+	if len(m.Responses) == 0 {
+		return nil
+	}
+	return m.Responses[0].Value
+}
+
+// Lookup returns the response for cmd, or nil if the batch did not include
+// it
+func (m *RawMeta) Lookup(cmd string) *Response {
+	for i := range m.Responses {
+		if m.Responses[i].Cmd == cmd {
+			return &m.Responses[i]
+		}
+	}
+	return nil
+}
+
+// rawMetaKey is the context key for RawMeta
+type rawMetaKey struct{}
+
+// WithRawMeta returns a context that makes API calls made with it record
+// their raw responses in meta. Helpers that issue several requests leave
+// the most recent one in meta. For example:
+//
+//	var meta reolink.RawMeta
+//	info, err := client.System.GetDeviceInfo(reolink.WithRawMeta(ctx, &meta))
+//	var extra struct{ DevInfo struct{ NewField int `json:"newField"` } }
+//	json.Unmarshal(meta.Value(), &extra)
+func WithRawMeta(ctx context.Context, meta *RawMeta) context.Context {
+	return context.WithValue(ctx, rawMetaKey{}, meta)
+}
+
+// captureRaw records body in the RawMeta attached to ctx, if any
+func captureRaw(ctx context.Context, body []byte, cached bool) {
+	meta, _ := ctx.Value(rawMetaKey{}).(*RawMeta)
+	if meta == nil {
+		return
+	}
+	var responses []Response
+	if err := json.Unmarshal(body, &responses); err != nil {
+		responses = nil
+	}
+	*meta = RawMeta{Responses: responses, Body: body, Cached: cached}
+}
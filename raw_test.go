@@ -0,0 +1,60 @@
+package reolink
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWithRawMeta(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A", "newField": 7}}`,
+	})
+	client := server.client()
+
+	var meta RawMeta
+	info, err := client.System.GetDeviceInfo(WithRawMeta(t.Context(), &meta))
+	if err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if info.Model != "RLC-811A" {
+		t.Errorf("unexpected model %q", info.Model)
+	}
+
+	var extra struct {
+		DevInfo struct {
+			NewField int `json:"newField"`
+		} `json:"DevInfo"`
+	}
+	if err := json.Unmarshal(meta.Value(), &extra); err != nil {
+		t.Fatalf("failed to parse raw value: %v", err)
+	}
+	if extra.DevInfo.NewField != 7 {
+		t.Errorf("expected unmodelled field 7, got %d", extra.DevInfo.NewField)
+	}
+	if meta.Lookup("GetDevInfo") == nil || meta.Lookup("GetTime") != nil || meta.Cached || len(meta.Body) == 0 {
+		t.Errorf("unexpected meta: %+v", meta)
+	}
+
+	// Calls without a RawMeta are unaffected
+	if _, err := client.System.GetDeviceInfo(t.Context()); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+}
+
+func TestWithRawMeta_Cached(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetDevInfo": `{"DevInfo": {"model": "RLC-811A"}}`,
+	})
+	client := server.client()
+	WithCache(NewMemoryCache(), map[string]time.Duration{"GetDevInfo": time.Minute})(client)
+
+	client.System.GetDeviceInfo(t.Context())
+	var meta RawMeta
+	if _, err := client.System.GetDeviceInfo(WithRawMeta(t.Context(), &meta)); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if !meta.Cached || meta.Value() == nil {
+		t.Errorf("expected cached raw response, got %+v", meta)
+	}
+}
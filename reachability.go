@@ -0,0 +1,186 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ReachabilityOptions configures an external reachability check
+type ReachabilityOptions struct {
+	// ExternalHost is the public IP address or DDNS name the camera should
+	// be reachable at (required)
+	ExternalHost string
+
+	// ProbeURL is an optional external probe service. When set, the probe is
+	// called as GET ProbeURL?host=<host>&port=<port> and must answer with
+	// JSON {"reachable": true|false, "error": "..."}. When empty, ports are
+	// dialed directly from the machine running the SDK, which only reflects
+	// external reachability if the router supports NAT hairpinning.
+	ProbeURL string
+
+	// Timeout bounds each individual port probe (default: 5s)
+	Timeout time.Duration
+
+	// HTTPClient is used to call ProbeURL (default: http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// PortReachability is the result of probing a single camera port
+type PortReachability struct {
+	Name      string // "RTSP", "HTTPS"
+	Port      int    // Port configured on the camera
+	Enabled   bool   // Whether the service is enabled on the camera
+	Reachable bool   // Whether the port answered from the probe vantage
+	Error     string // Probe error, if any
+}
+
+// ReachabilityReport summarizes remote-access readiness of a camera
+type ReachabilityReport struct {
+	ExternalHost string             // Host that was probed
+	UpnpEnabled  bool               // Whether UPnP port mapping is enabled on the camera
+	Ports        []PortReachability // Per-port probe results
+	Diagnostics  []string           // Actionable hints for detected problems
+}
+
+// Reachable returns true if every enabled port was reachable
+func (r *ReachabilityReport) Reachable() bool {
+	for _, p := range r.Ports {
+		if p.Enabled && !p.Reachable {
+			return false
+		}
+	}
+	return true
+}
+
+// probeResult is the JSON body returned by an external probe service
+type probeResult struct {
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error"`
+}
+
+// CheckExternalReachability checks whether the camera's RTSP and HTTPS ports
+// are reachable at opts.ExternalHost and correlates the results with the
+// camera's UPnP state to produce remote-access diagnostics
+func (n *NetworkAPI) CheckExternalReachability(ctx context.Context, opts ReachabilityOptions) (*ReachabilityReport, error) {
+	if opts.ExternalHost == "" {
+		return nil, fmt.Errorf("external host is required")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	n.client.log(ctx).Info("checking external reachability: host=%s", opts.ExternalHost)
+
+	netPort, err := n.GetNetPort(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get port configuration: %w", err)
+	}
+
+	upnp, err := n.GetUpnp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get UPnP configuration: %w", err)
+	}
+
+	report := &ReachabilityReport{
+		ExternalHost: opts.ExternalHost,
+		UpnpEnabled:  upnp.Enable.Bool(),
+		Ports: []PortReachability{
+			{Name: "RTSP", Port: netPort.RTSPPort, Enabled: netPort.RTSPEnable.Bool()},
+			{Name: "HTTPS", Port: netPort.HTTPSPort, Enabled: netPort.HTTPSEnable.Bool()},
+		},
+	}
+
+	for i := range report.Ports {
+		p := &report.Ports[i]
+		if !p.Enabled {
+			report.Diagnostics = append(report.Diagnostics,
+				fmt.Sprintf("%s is disabled on the camera; enable it with SetNetPort before configuring remote access", p.Name))
+			continue
+		}
+
+		if opts.ProbeURL != "" {
+			p.Reachable, err = n.probeExternal(ctx, opts, p.Port)
+		} else {
+			p.Reachable, err = probeDial(ctx, opts.ExternalHost, p.Port, opts.Timeout)
+		}
+		if err != nil {
+			p.Error = err.Error()
+		}
+
+		n.client.log(ctx).Debug("probed %s port %d: reachable=%t", p.Name, p.Port, p.Reachable)
+
+		if p.Reachable {
+			continue
+		}
+		if report.UpnpEnabled {
+			report.Diagnostics = append(report.Diagnostics,
+				fmt.Sprintf("%s port %d is not reachable although UPnP is enabled; the router may not support UPnP or the connection may be behind CGNAT", p.Name, p.Port))
+		} else {
+			report.Diagnostics = append(report.Diagnostics,
+				fmt.Sprintf("%s port %d is not reachable and UPnP is disabled; forward port %d on the router or enable UPnP", p.Name, p.Port, p.Port))
+		}
+	}
+
+	n.client.log(ctx).Info("external reachability check complete: reachable=%t diagnostics=%d",
+		report.Reachable(), len(report.Diagnostics))
+	return report, nil
+}
+
+// probeExternal asks the external probe service whether host:port is reachable
+func (n *NetworkAPI) probeExternal(ctx context.Context, opts ReachabilityOptions, port int) (bool, error) {
+	u, err := url.Parse(opts.ProbeURL)
+	if err != nil {
+		return false, fmt.Errorf("invalid probe URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("host", opts.ExternalHost)
+	q.Set("port", strconv.Itoa(port))
+	u.RawQuery = q.Encode()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create probe request: %w", err)
+	}
+
+	resp, err := opts.HTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("probe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("probe returned status %d", resp.StatusCode)
+	}
+
+	var result probeResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("failed to parse probe response: %w", err)
+	}
+	if result.Error != "" {
+		return result.Reachable, fmt.Errorf("probe: %s", result.Error)
+	}
+	return result.Reachable, nil
+}
+
+// probeDial attempts a TCP connection to host:port
+func probeDial(ctx context.Context, host string, port int, timeout time.Duration) (bool, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return false, err
+	}
+	conn.Close()
+	return true, nil
+}
@@ -0,0 +1,90 @@
+package reolink
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNetworkAPI_CheckExternalReachability_Probe(t *testing.T) {
+	camera := newCmdServer(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"rtspEnable": 1, "rtspPort": 554, "httpsEnable": 1, "httpsPort": 443}}`,
+		"GetUpnp":    `{"Upnp": {"enable": 0}}`,
+	})
+
+	probe := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("host") != "203.0.113.10" {
+			t.Errorf("unexpected probe host: %s", r.URL.Query().Get("host"))
+		}
+		json.NewEncoder(w).Encode(probeResult{Reachable: r.URL.Query().Get("port") == "443"})
+	}))
+	defer probe.Close()
+
+	client := camera.client()
+	report, err := client.Network.CheckExternalReachability(t.Context(), ReachabilityOptions{
+		ExternalHost: "203.0.113.10",
+		ProbeURL:     probe.URL,
+	})
+	if err != nil {
+		t.Fatalf("CheckExternalReachability failed: %v", err)
+	}
+
+	if report.UpnpEnabled {
+		t.Error("expected UPnP disabled")
+	}
+	if report.Reachable() {
+		t.Error("expected report to be unreachable")
+	}
+	if len(report.Ports) != 2 {
+		t.Fatalf("expected 2 ports, got %d", len(report.Ports))
+	}
+	if report.Ports[0].Reachable {
+		t.Error("expected RTSP to be unreachable")
+	}
+	if !report.Ports[1].Reachable {
+		t.Error("expected HTTPS to be reachable")
+	}
+	if len(report.Diagnostics) != 1 || !strings.Contains(report.Diagnostics[0], "forward port 554") {
+		t.Errorf("unexpected diagnostics: %v", report.Diagnostics)
+	}
+}
+
+func TestNetworkAPI_CheckExternalReachability_Dial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	camera := newCmdServer(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"rtspEnable": 1, "rtspPort": ` + strconv.Itoa(port) + `, "httpsEnable": 0, "httpsPort": 443}}`,
+		"GetUpnp":    `{"Upnp": {"enable": 1}}`,
+	})
+
+	client := camera.client()
+	report, err := client.Network.CheckExternalReachability(t.Context(), ReachabilityOptions{
+		ExternalHost: "127.0.0.1",
+	})
+	if err != nil {
+		t.Fatalf("CheckExternalReachability failed: %v", err)
+	}
+
+	if !report.Reachable() {
+		t.Errorf("expected enabled ports to be reachable: %+v", report.Ports)
+	}
+	if len(report.Diagnostics) != 1 || !strings.Contains(report.Diagnostics[0], "HTTPS is disabled") {
+		t.Errorf("unexpected diagnostics: %v", report.Diagnostics)
+	}
+}
+
+func TestNetworkAPI_CheckExternalReachability_RequiresHost(t *testing.T) {
+	client := NewClient("192.168.1.100")
+	if _, err := client.Network.CheckExternalReachability(t.Context(), ReachabilityOptions{}); err == nil {
+		t.Error("expected error for missing external host")
+	}
+}
@@ -0,0 +1,128 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrRecordingChanged is returned by DeleteRecording when the recording is
+// no longer listed as the caller saw it, so nothing was deleted
+var ErrRecordingChanged = errors.New("recording changed since it was searched")
+
+// ErrDeleteNotConfirmed is returned by DeleteRecording when the confirmation
+// does not name the recording
+var ErrDeleteNotConfirmed = errors.New("deletion not confirmed")
+
+// DelRecParam represents parameters for DelRec
+type DelRecParam struct {
+	DelRec DelRecFile `json:"DelRec"`
+}
+
+// DelRecFile names the file DelRec deletes
+type DelRecFile struct {
+	Channel  int    `json:"channel"`
+	FileName string `json:"fileName"`
+}
+
+// DeleteRecording deletes a recording from the camera's SD card or the NVR's
+// disk, for retention management on devices that keep recordings until the
+// disk is full. DelRec is not in the API guide and only some firmware
+// accepts it; others answer with an error for which IsNotSupported is true.
+//
+// Deleting cannot be undone, so DeleteRecording insists on confirmation:
+//   - rec must be a Search result and confirm must repeat rec.FileName,
+//     otherwise ErrDeleteNotConfirmed is returned;
+//   - the recording is searched again first and must still be listed with
+//     the same size, otherwise ErrRecordingChanged is returned, e.g. when
+//     the camera overwrote it and reused the name;
+//   - after DelRec succeeds the recording is searched once more, and an
+//     error is returned if it is still listed.
+func (r *RecordingAPI) DeleteRecording(ctx context.Context, rec SearchResult, confirm string) error {
+	if rec.FileName == "" {
+		return fmt.Errorf("recording has no file name")
+	}
+	if confirm != rec.FileName {
+		return fmt.Errorf("%w: confirmation %q does not match %q", ErrDeleteNotConfirmed, confirm, rec.FileName)
+	}
+	r.client.log(ctx).Warn("deleting recording (destructive operation): channel=%d file=%s", rec.Channel, rec.FileName)
+
+	current, err := r.findRecording(ctx, rec)
+	if err != nil {
+		r.client.log(ctx).Error("failed to delete recording: %v", err)
+		return err
+	}
+	if current == nil || current.FileSize != rec.FileSize {
+		err := fmt.Errorf("%w: %s", ErrRecordingChanged, rec.FileName)
+		r.client.log(ctx).Error("failed to delete recording: %v", err)
+		return err
+	}
+
+	req := []Request{{
+		Cmd: "DelRec",
+		Param: DelRecParam{
+			DelRec: DelRecFile{Channel: rec.Channel, FileName: rec.FileName},
+		},
+	}}
+
+	var resp []Response
+	if err := r.client.do(ctx, req, &resp); err != nil {
+		r.client.log(ctx).Error("failed to delete recording: %v", err)
+		return fmt.Errorf("DelRec request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from DelRec")
+		r.client.log(ctx).Error("failed to delete recording: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		r.client.log(ctx).Error("failed to delete recording: %v", apiErr)
+		return apiErr
+	}
+
+	remaining, err := r.findRecording(ctx, rec)
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion of %s: %w", rec.FileName, err)
+	}
+	if remaining != nil {
+		err := fmt.Errorf("camera accepted DelRec but still lists %s", rec.FileName)
+		r.client.log(ctx).Error("failed to delete recording: %v", err)
+		return err
+	}
+
+	r.client.log(ctx).Info("successfully deleted recording: channel=%d file=%s", rec.Channel, rec.FileName)
+	return nil
+}
+
+// findRecording searches rec's time range and returns rec's current entry,
+// nil if it is not listed
+func (r *RecordingAPI) findRecording(ctx context.Context, rec SearchResult) (*SearchResult, error) {
+	start, end := rec.StartTime, rec.EndTime
+	if start.IsZero() || end.IsZero() {
+		return nil, fmt.Errorf("recording %s has no time range", rec.FileName)
+	}
+	results, err := r.Search(ctx, rec.Channel, start.Add(-time.Minute), end.Add(time.Minute), recordingStream(rec.FileName))
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if results[i].FileName == rec.FileName {
+			return &results[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// recordingStream returns the stream a recording was made from, going by
+// the RecM/RecS prefix cameras give file names
+func recordingStream(fileName string) string {
+	if strings.HasPrefix(path.Base(fileName), "RecS") {
+		return "sub"
+	}
+	return "main"
+}
@@ -0,0 +1,111 @@
+package reolink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newDelRecServer lists files by name and size and deletes them on DelRec
+// if supported
+func newDelRecServer(t *testing.T, files map[string]int64, supported bool) (*httptest.Server, *[]string) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			Cmd   string `json:"cmd"`
+			Param struct {
+				DelRec DelRecFile `json:"DelRec"`
+			} `json:"param"`
+		}
+		json.NewDecoder(r.Body).Decode(&reqs)
+
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch reqs[0].Cmd {
+		case "Search":
+			var list []SearchResult
+			for name, size := range files {
+				list = append(list, SearchResult{FileName: name, FileSize: size})
+			}
+			data, _ := json.Marshal(list)
+			fmt.Fprintf(w, `[{"cmd": "Search", "code": 0, "value": {"SearchResult": %s}}]`, data)
+		case "DelRec":
+			if !supported {
+				w.Write([]byte(`[{"cmd": "DelRec", "code": 1, "error": {"rspCode": -9, "detail": "not support"}}]`))
+				return
+			}
+			delete(files, reqs[0].Param.DelRec.FileName)
+			deleted = append(deleted, reqs[0].Param.DelRec.FileName)
+			w.Write([]byte(`[{"cmd": "DelRec", "code": 0, "value": {"rspCode": 200}}]`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &deleted
+}
+
+func TestRecordingAPI_DeleteRecording(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rec := SearchResult{
+		FileName:  "Mp4Record/2024-01-01/RecM01_20240101_120000_120500.mp4",
+		FileSize:  1000,
+		StartTime: start,
+		EndTime:   start.Add(5 * time.Minute),
+	}
+
+	t.Run("deletes", func(t *testing.T) {
+		server, deleted := newDelRecServer(t, map[string]int64{rec.FileName: 1000, "other.mp4": 5}, true)
+		client := newTestClient(server)
+		if err := client.Recording.DeleteRecording(t.Context(), rec, rec.FileName); err != nil {
+			t.Fatalf("DeleteRecording failed: %v", err)
+		}
+		if len(*deleted) != 1 || (*deleted)[0] != rec.FileName {
+			t.Errorf("deleted %v", *deleted)
+		}
+	})
+
+	t.Run("not confirmed", func(t *testing.T) {
+		server, deleted := newDelRecServer(t, map[string]int64{rec.FileName: 1000}, true)
+		client := newTestClient(server)
+		err := client.Recording.DeleteRecording(t.Context(), rec, "RecM01.mp4")
+		if !errors.Is(err, ErrDeleteNotConfirmed) || len(*deleted) != 0 {
+			t.Errorf("expected ErrDeleteNotConfirmed without deleting, got %v, deleted %v", err, *deleted)
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		server, deleted := newDelRecServer(t, map[string]int64{rec.FileName: 2000}, true)
+		client := newTestClient(server)
+		err := client.Recording.DeleteRecording(t.Context(), rec, rec.FileName)
+		if !errors.Is(err, ErrRecordingChanged) || len(*deleted) != 0 {
+			t.Errorf("expected ErrRecordingChanged without deleting, got %v, deleted %v", err, *deleted)
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		server, _ := newDelRecServer(t, map[string]int64{rec.FileName: 1000}, false)
+		client := newTestClient(server)
+		err := client.Recording.DeleteRecording(t.Context(), rec, rec.FileName)
+		if !IsNotSupported(err) {
+			t.Errorf("expected a not supported error, got %v", err)
+		}
+	})
+}
+
+func TestRecordingStream(t *testing.T) {
+	if s := recordingStream("Mp4Record/2024-01-01/RecS01_20240101_120000.mp4"); s != "sub" {
+		t.Errorf("expected sub, got %s", s)
+	}
+	if s := recordingStream("RecM01_20240101_120000.mp4"); s != "main" {
+		t.Errorf("expected main, got %s", s)
+	}
+}
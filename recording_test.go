@@ -1,8 +1,12 @@
 package reolink
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 )
@@ -265,3 +269,136 @@ func TestRecordingAPI_NvrDownload(t *testing.T) {
 		t.Fatalf("NvrDownload failed: %v", err)
 	}
 }
+
+func TestRecordingAPI_SearchAll(t *testing.T) {
+	results := map[int]string{
+		0: `[{"fileName": "ch0-b.mp4", "startTime": "2020-12-21T12:30:00Z", "endTime": "2020-12-21T12:31:00Z", "type": "MD"},
+			{"fileName": "ch0-a.mp4", "startTime": "2020-12-21T12:05:00Z", "endTime": "2020-12-21T12:06:00Z", "type": "MD"}]`,
+		2: `[{"fileName": "ch2-a.mp4", "startTime": "2020-12-21T12:10:00Z", "endTime": "2020-12-21T12:11:00Z", "type": "AI_PEOPLE"}]`,
+	}
+
+	var mu sync.Mutex
+	var searched []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			Cmd   string `json:"cmd"`
+			Param struct {
+				Search SearchCriteria `json:"Search"`
+			} `json:"param"`
+		}
+		json.NewDecoder(r.Body).Decode(&reqs)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch reqs[0].Cmd {
+		case "Getchannelstatus":
+			w.Write([]byte(`[{"cmd": "Getchannelstatus", "code": 0, "value": {"count": 3, "status": [
+				{"channel": 0, "online": 1}, {"channel": 1, "online": 0}, {"channel": 2, "online": 1}]}}]`))
+		case "Search":
+			channel := reqs[0].Param.Search.Channel
+			mu.Lock()
+			searched = append(searched, channel)
+			mu.Unlock()
+			fmt.Fprintf(w, `[{"cmd": "Search", "code": 0, "value": {"SearchResult": %s}}]`, results[channel])
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	from := time.Date(2020, 12, 21, 12, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 12, 21, 13, 0, 0, 0, time.UTC)
+	timeline, err := client.Recording.SearchAll(t.Context(), from, to)
+	if err != nil {
+		t.Fatalf("SearchAll failed: %v", err)
+	}
+
+	if len(searched) != 2 {
+		t.Errorf("expected only the 2 online channels to be searched, got %v", searched)
+	}
+
+	want := []struct {
+		file    string
+		channel int
+	}{
+		{"ch0-a.mp4", 0},
+		{"ch2-a.mp4", 2},
+		{"ch0-b.mp4", 0},
+	}
+	if len(timeline) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(timeline))
+	}
+	for i, w := range want {
+		if timeline[i].FileName != w.file || timeline[i].Channel != w.channel {
+			t.Errorf("result %d: expected %s on channel %d, got %s on channel %d",
+				i, w.file, w.channel, timeline[i].FileName, timeline[i].Channel)
+		}
+	}
+}
+
+func TestRecordingAPI_SearchAll_SingleCamera(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"Search": `{"SearchResult": [{"fileName": "a.mp4", "startTime": "2020-12-21T12:05:00Z", "endTime": "2020-12-21T12:06:00Z"}]}`,
+	})
+
+	timeline, err := srv.client().Recording.SearchAll(t.Context(), time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("SearchAll failed: %v", err)
+	}
+	if len(timeline) != 1 || timeline[0].Channel != 0 {
+		t.Errorf("expected one result on channel 0, got %+v", timeline)
+	}
+}
+
+func TestRecordingAPI_SearchAll_PartialFailure(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"Getchannelstatus": `{"count": 1, "status": [{"channel": 0, "online": 1}]}`,
+	})
+
+	timeline, err := srv.client().Recording.SearchAll(t.Context(), time.Now().Add(-time.Hour), time.Now())
+	if err == nil {
+		t.Fatal("expected error when channel search fails")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RspCode != ErrCodeNotSupported {
+		t.Errorf("expected wrapped API error, got %v", err)
+	}
+	if len(timeline) != 0 {
+		t.Errorf("expected no results, got %d", len(timeline))
+	}
+}
+
+func TestRecordingAPI_PlaybackURL(t *testing.T) {
+	client := NewClient("192.168.1.100", WithHTTPS(true))
+	client.token = "test-token"
+	base := "https://192.168.1.100/cgi-bin/api.cgi?cmd=Playback&source=Mp4Record/RecM01.mp4&output=out.mp4&token=test-token"
+
+	tests := []struct {
+		opts    PlaybackOptions
+		want    string
+		wantErr bool
+	}{
+		{PlaybackOptions{}, base, false},
+		{PlaybackOptions{Speed: 4}, base + "&speed=4", false},
+		{PlaybackOptions{Speed: 2, Seek: 90 * time.Second}, base + "&speed=2&seek=90", false},
+		{PlaybackOptions{Speed: 3}, "", true},
+		{PlaybackOptions{Seek: -time.Second}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := client.Recording.PlaybackURL("Mp4Record/RecM01.mp4", "out.mp4", tt.opts)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("PlaybackURL(%+v) = %q, %v; want %q", tt.opts, got, err, tt.want)
+		}
+	}
+
+	got, err := client.Recording.DownloadURL("Mp4Record/RecM01.mp4", "out.mp4", PlaybackOptions{Speed: 8, Seek: time.Minute})
+	want := "https://192.168.1.100/cgi-bin/api.cgi?cmd=Download&source=Mp4Record/RecM01.mp4&output=out.mp4&token=test-token&seek=60"
+	if err != nil || got != want {
+		t.Errorf("DownloadURL = %q, %v; want %q", got, err, want)
+	}
+	// Characters that would end or change the query are escaped
+	got, err = client.Recording.DownloadURL("Mp4Record/Rec 01&a=#1.mp4", "Rec 01&a=#1.mp4", PlaybackOptions{})
+	want = "https://192.168.1.100/cgi-bin/api.cgi?cmd=Download&source=Mp4Record/Rec+01%26a%3D%231.mp4&output=Rec+01%26a%3D%231.mp4&token=test-token"
+	if err != nil || got != want {
+		t.Errorf("DownloadURL = %q, %v; want %q", got, err, want)
+	}
+}
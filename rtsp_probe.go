@@ -0,0 +1,282 @@
+package reolink
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rtspProbeTimeout bounds ProbeRTSP when ctx has no deadline
+const rtspProbeTimeout = 10 * time.Second
+
+// RTSPProbeStatus is the outcome of ProbeRTSP
+type RTSPProbeStatus string
+
+// RTSP probe outcomes
+const (
+	// RTSPReachable means the stream exists and DESCRIBE returned its SDP
+	RTSPReachable RTSPProbeStatus = "reachable"
+	// RTSPUnauthorized means the server rejected the credentials
+	RTSPUnauthorized RTSPProbeStatus = "unauthorized"
+	// RTSPNotFound means the server has no stream at the URL's path, e.g.
+	// because the channel number or stream name is wrong
+	RTSPNotFound RTSPProbeStatus = "not_found"
+)
+
+// RTSPProbeResult is the result of ProbeRTSP
+type RTSPProbeResult struct {
+	URL        string          // Probed URL without credentials
+	Status     RTSPProbeStatus // Outcome of DESCRIBE
+	StatusCode int             // RTSP status code of DESCRIBE
+	Methods    []string        // Methods the server listed in reply to OPTIONS
+	AuthScheme string          // "Basic" or "Digest" if the server asked for authentication
+	SDP        string          // Session description of a reachable stream
+}
+
+// ProbeRTSP checks an RTSP URL, such as one from GetRTSPURL, by sending
+// OPTIONS and DESCRIBE without setting up a media session. Credentials in
+// the URL are used if present, the client's otherwise, with Basic or
+// Digest authentication as the server asks.
+//
+// An error is returned if the server cannot be reached or answers
+// DESCRIBE with a status other than success, 401, 403 or 404.
+func (s *StreamingAPI) ProbeRTSP(ctx context.Context, rawURL string) (*RTSPProbeResult, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RTSP URL: %w", err)
+	}
+	if u.Scheme != "rtsp" {
+		return nil, fmt.Errorf("invalid RTSP URL: scheme must be rtsp")
+	}
+
+	username, password := s.client.username, s.client.password
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+	}
+	u.User = nil
+	result := &RTSPProbeResult{URL: u.String()}
+
+	s.client.log(ctx).Debug("probing RTSP URL: %s", result.URL)
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rtspProbeTimeout)
+		defer cancel()
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "554")
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		s.client.log(ctx).Warn("RTSP probe failed: %v", err)
+		return nil, fmt.Errorf("failed to connect to RTSP server: %w", err)
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	session := &rtspSession{
+		conn:     conn,
+		reader:   textproto.NewReader(bufio.NewReader(conn)),
+		uri:      result.URL,
+		username: username,
+		password: password,
+	}
+
+	options, err := session.send("OPTIONS", nil)
+	if err != nil {
+		s.client.log(ctx).Warn("RTSP probe failed: %v", err)
+		return nil, err
+	}
+	for _, m := range strings.Split(options.header.Get("Public"), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			result.Methods = append(result.Methods, m)
+		}
+	}
+
+	describe, err := session.send("DESCRIBE", map[string]string{"Accept": "application/sdp"})
+	if err != nil {
+		s.client.log(ctx).Warn("RTSP probe failed: %v", err)
+		return nil, err
+	}
+	result.StatusCode = describe.code
+	result.AuthScheme = session.scheme
+
+	switch {
+	case describe.code >= 200 && describe.code < 300:
+		result.Status = RTSPReachable
+		result.SDP = describe.body
+	case describe.code == 401 || describe.code == 403:
+		result.Status = RTSPUnauthorized
+	case describe.code == 404:
+		result.Status = RTSPNotFound
+	default:
+		err := fmt.Errorf("unexpected RTSP status %d %s", describe.code, describe.reason)
+		s.client.log(ctx).Warn("RTSP probe failed: %v", err)
+		return result, err
+	}
+
+	s.client.log(ctx).Info("RTSP probe finished: url=%s status=%s", result.URL, result.Status)
+	return result, nil
+}
+
+// rtspSession sends RTSP requests over one connection, authenticating
+// once the server asks for it
+type rtspSession struct {
+	conn     net.Conn
+	reader   *textproto.Reader
+	uri      string
+	username string
+	password string
+	cseq     int
+
+	scheme    string // Authentication scheme demanded by the server
+	challenge map[string]string
+	nc        int
+}
+
+// rtspResponse is a parsed RTSP response
+type rtspResponse struct {
+	code   int
+	reason string
+	header textproto.MIMEHeader
+	body   string
+}
+
+// send sends method and, if the server answers 401 with a challenge the
+// session has not answered yet, sends it once more with credentials
+func (r *rtspSession) send(method string, header map[string]string) (*rtspResponse, error) {
+	resp, err := r.roundTrip(method, header)
+	if err != nil || resp.code != 401 || r.scheme != "" || r.username == "" {
+		return resp, err
+	}
+	scheme, params, ok := parseRTSPChallenge(resp.header.Values("WWW-Authenticate"))
+	if !ok {
+		return resp, nil
+	}
+	r.scheme, r.challenge = scheme, params
+	return r.roundTrip(method, header)
+}
+
+// roundTrip writes one request and reads its response
+func (r *rtspSession) roundTrip(method string, header map[string]string) (*rtspResponse, error) {
+	r.cseq++
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s RTSP/1.0\r\nCSeq: %d\r\nUser-Agent: %s\r\n", method, r.uri, r.cseq, UserAgent())
+	for k, v := range header {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+	if auth := r.authorization(method); auth != "" {
+		fmt.Fprintf(&b, "Authorization: %s\r\n", auth)
+	}
+	b.WriteString("\r\n")
+	if _, err := io.WriteString(r.conn, b.String()); err != nil {
+		return nil, fmt.Errorf("failed to send RTSP %s: %w", method, err)
+	}
+
+	line, err := r.reader.ReadLine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RTSP %s response: %w", method, err)
+	}
+	proto, status, _ := strings.Cut(line, " ")
+	if !strings.HasPrefix(proto, "RTSP/") {
+		return nil, fmt.Errorf("invalid RTSP response %q", line)
+	}
+	codeStr, reason, _ := strings.Cut(status, " ")
+	code, err := strconv.Atoi(codeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RTSP response %q", line)
+	}
+	mime, err := r.reader.ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RTSP %s response: %w", method, err)
+	}
+	resp := &rtspResponse{code: code, reason: reason, header: mime}
+	if n, _ := strconv.Atoi(mime.Get("Content-Length")); n > 0 {
+		body := make([]byte, n)
+		if _, err := io.ReadFull(r.reader.R, body); err != nil {
+			return nil, fmt.Errorf("failed to read RTSP %s response: %w", method, err)
+		}
+		resp.body = string(body)
+	}
+	return resp, nil
+}
+
+// authorization returns the Authorization header for method, or "" before
+// the server asked for authentication
+func (r *rtspSession) authorization(method string) string {
+	switch r.scheme {
+	case "Basic":
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(r.username+":"+r.password))
+	case "Digest":
+		c := r.challenge
+		ha1 := md5Hex(r.username + ":" + c["realm"] + ":" + r.password)
+		ha2 := md5Hex(method + ":" + r.uri)
+		auth := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s"`, r.username, c["realm"], c["nonce"], r.uri)
+		if qop := c["qop"]; strings.Contains(qop, "auth") {
+			r.nc++
+			nc := fmt.Sprintf("%08x", r.nc)
+			cnonce := make([]byte, 8)
+			rand.Read(cnonce)
+			cn := hex.EncodeToString(cnonce)
+			response := md5Hex(ha1 + ":" + c["nonce"] + ":" + nc + ":" + cn + ":auth:" + ha2)
+			auth += fmt.Sprintf(`, qop=auth, nc=%s, cnonce="%s", response="%s"`, nc, cn, response)
+		} else {
+			auth += fmt.Sprintf(`, response="%s"`, md5Hex(ha1+":"+c["nonce"]+":"+ha2))
+		}
+		if opaque := c["opaque"]; opaque != "" {
+			auth += fmt.Sprintf(`, opaque="%s"`, opaque)
+		}
+		return auth
+	}
+	return ""
+}
+
+// parseRTSPChallenge picks the Digest challenge from WWW-Authenticate
+// headers, falling back to Basic
+func parseRTSPChallenge(values []string) (string, map[string]string, bool) {
+	basic := false
+	for _, v := range values {
+		scheme, rest, _ := strings.Cut(strings.TrimSpace(v), " ")
+		switch {
+		case strings.EqualFold(scheme, "Digest"):
+			params := make(map[string]string)
+			for _, p := range strings.Split(rest, ",") {
+				k, val, ok := strings.Cut(strings.TrimSpace(p), "=")
+				if ok {
+					params[strings.ToLower(k)] = strings.Trim(val, `"`)
+				}
+			}
+			return "Digest", params, true
+		case strings.EqualFold(scheme, "Basic"):
+			basic = true
+		}
+	}
+	if basic {
+		return "Basic", nil, true
+	}
+	return "", nil, false
+}
+
+// md5Hex returns the hex-encoded MD5 hash of s
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,105 @@
+package reolink
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// newRTSPServer starts an RTSP server that serves /Preview_01_main to
+// admin:secret with Digest authentication and returns its address
+func newRTSPServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	const realm, nonce = "IP Camera", "abc123"
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := textproto.NewReader(bufio.NewReader(conn))
+				for {
+					line, err := reader.ReadLine()
+					if err != nil {
+						return
+					}
+					header, err := reader.ReadMIMEHeader()
+					if err != nil {
+						return
+					}
+					fields := strings.Fields(line)
+					method, uri := fields[0], fields[1]
+					cseq := header.Get("CSeq")
+
+					if method == "OPTIONS" {
+						fmt.Fprintf(conn, "RTSP/1.0 200 OK\r\nCSeq: %s\r\nPublic: OPTIONS, DESCRIBE, SETUP, PLAY, TEARDOWN\r\n\r\n", cseq)
+						continue
+					}
+					ha1 := md5Hex("admin:" + realm + ":secret")
+					want := md5Hex(ha1 + ":" + nonce + ":" + md5Hex(method+":"+uri))
+					if !strings.Contains(header.Get("Authorization"), `response="`+want+`"`) {
+						fmt.Fprintf(conn, "RTSP/1.0 401 Unauthorized\r\nCSeq: %s\r\nWWW-Authenticate: Digest realm=\"%s\", nonce=\"%s\"\r\nWWW-Authenticate: Basic realm=\"%s\"\r\n\r\n", cseq, realm, nonce, realm)
+						continue
+					}
+					if !strings.HasSuffix(uri, "/Preview_01_main") {
+						fmt.Fprintf(conn, "RTSP/1.0 404 Stream Not Found\r\nCSeq: %s\r\n\r\n", cseq)
+						continue
+					}
+					sdp := "v=0\r\nm=video 0 RTP/AVP 96\r\n"
+					fmt.Fprintf(conn, "RTSP/1.0 200 OK\r\nCSeq: %s\r\nContent-Type: application/sdp\r\nContent-Length: %d\r\n\r\n%s", cseq, len(sdp), sdp)
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestStreamingAPI_ProbeRTSP(t *testing.T) {
+	addr := newRTSPServer(t)
+	client := NewClient(addr, WithCredentials("admin", "secret"))
+
+	tests := []struct {
+		name   string
+		url    string
+		status RTSPProbeStatus
+	}{
+		{"reachable", "rtsp://" + addr + "/Preview_01_main", RTSPReachable},
+		{"wrong channel", "rtsp://" + addr + "/Preview_00_main", RTSPNotFound},
+		{"wrong password", "rtsp://admin:wrong@" + addr + "/Preview_01_main", RTSPUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.Streaming.ProbeRTSP(t.Context(), tt.url)
+			if err != nil {
+				t.Fatalf("ProbeRTSP failed: %v", err)
+			}
+			if result.Status != tt.status {
+				t.Errorf("status = %s (%d), want %s", result.Status, result.StatusCode, tt.status)
+			}
+			if strings.Contains(result.URL, "@") {
+				t.Errorf("credentials in result URL: %s", result.URL)
+			}
+			if result.AuthScheme != "Digest" || len(result.Methods) != 5 {
+				t.Errorf("unexpected auth scheme %q or methods %v", result.AuthScheme, result.Methods)
+			}
+			if tt.status == RTSPReachable && !strings.Contains(result.SDP, "m=video") {
+				t.Errorf("unexpected SDP: %q", result.SDP)
+			}
+		})
+	}
+
+	if _, err := client.Streaming.ProbeRTSP(t.Context(), "http://"+addr+"/"); err == nil {
+		t.Error("expected error for non-RTSP URL")
+	}
+}
@@ -2,7 +2,6 @@ package reolink
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 )
 
@@ -13,7 +12,7 @@ type SecurityAPI struct {
 
 // GetUsers retrieves the list of users
 func (s *SecurityAPI) GetUsers(ctx context.Context) ([]User, error) {
-	s.client.logger.Debug("getting users")
+	s.client.log(ctx).Debug("getting users")
 
 	req := []Request{{
 		Cmd:    "GetUser",
@@ -22,34 +21,42 @@ func (s *SecurityAPI) GetUsers(ctx context.Context) ([]User, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get users: %v", err)
+		s.client.log(ctx).Error("failed to get users: %v", err)
 		return nil, fmt.Errorf("GetUser request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get users: %v", err)
+		s.client.log(ctx).Error("failed to get users: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get users: %v", apiErr)
+		s.client.log(ctx).Error("failed to get users: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value UserValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse users response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse users response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	s.client.logger.Info("successfully retrieved users: count=%d", len(value.User))
+	s.client.log(ctx).Info("successfully retrieved users: count=%d", len(value.User))
 	return value.User, nil
 }
 
 // AddUser adds a new user
+//
+// The password is checked with ValidatePassword before it is sent, since
+// cameras silently reject or mangle some characters.
 func (s *SecurityAPI) AddUser(ctx context.Context, user User) error {
-	s.client.logger.Info("adding user: username=%s", user.UserName)
+	s.client.log(ctx).Info("adding user: username=%s", user.UserName)
+
+	if err := ValidatePassword(user.Password); err != nil {
+		s.client.log(ctx).Error("failed to add user: %v", err)
+		return err
+	}
 
 	req := []Request{{
 		Cmd: "AddUser",
@@ -60,28 +67,37 @@ func (s *SecurityAPI) AddUser(ctx context.Context, user User) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to add user: %v", err)
+		s.client.log(ctx).Error("failed to add user: %v", err)
 		return fmt.Errorf("AddUser request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to add user: %v", err)
+		s.client.log(ctx).Error("failed to add user: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to add user: %v", apiErr)
+		s.client.log(ctx).Error("failed to add user: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully added user")
+	s.client.log(ctx).Info("successfully added user")
 	return nil
 }
 
 // ModifyUser modifies an existing user
+//
+// A non-empty password is checked with ValidatePassword before it is sent.
 func (s *SecurityAPI) ModifyUser(ctx context.Context, user User) error {
-	s.client.logger.Info("modifying user: username=%s", user.UserName)
+	s.client.log(ctx).Info("modifying user: username=%s", user.UserName)
+
+	if user.Password != "" {
+		if err := ValidatePassword(user.Password); err != nil {
+			s.client.log(ctx).Error("failed to modify user: %v", err)
+			return err
+		}
+	}
 
 	req := []Request{{
 		Cmd: "ModifyUser",
@@ -92,28 +108,28 @@ func (s *SecurityAPI) ModifyUser(ctx context.Context, user User) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to modify user: %v", err)
+		s.client.log(ctx).Error("failed to modify user: %v", err)
 		return fmt.Errorf("ModifyUser request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to modify user: %v", err)
+		s.client.log(ctx).Error("failed to modify user: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to modify user: %v", apiErr)
+		s.client.log(ctx).Error("failed to modify user: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully modified user")
+	s.client.log(ctx).Info("successfully modified user")
 	return nil
 }
 
 // DeleteUser deletes a user
 func (s *SecurityAPI) DeleteUser(ctx context.Context, username string) error {
-	s.client.logger.Warn("deleting user (destructive): username=%s", username)
+	s.client.log(ctx).Warn("deleting user (destructive): username=%s", username)
 
 	req := []Request{{
 		Cmd: "DelUser",
@@ -126,28 +142,28 @@ func (s *SecurityAPI) DeleteUser(ctx context.Context, username string) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to delete user: %v", err)
+		s.client.log(ctx).Error("failed to delete user: %v", err)
 		return fmt.Errorf("DelUser request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to delete user: %v", err)
+		s.client.log(ctx).Error("failed to delete user: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to delete user: %v", apiErr)
+		s.client.log(ctx).Error("failed to delete user: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully deleted user")
+	s.client.log(ctx).Info("successfully deleted user")
 	return nil
 }
 
 // GetOnlineUsers retrieves the list of currently online users
 func (s *SecurityAPI) GetOnlineUsers(ctx context.Context) ([]OnlineUser, error) {
-	s.client.logger.Debug("getting online users")
+	s.client.log(ctx).Debug("getting online users")
 
 	req := []Request{{
 		Cmd:    "GetOnline",
@@ -156,41 +172,44 @@ func (s *SecurityAPI) GetOnlineUsers(ctx context.Context) ([]OnlineUser, error)
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get online users: %v", err)
+		s.client.log(ctx).Error("failed to get online users: %v", err)
 		return nil, fmt.Errorf("GetOnline request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get online users: %v", err)
+		s.client.log(ctx).Error("failed to get online users: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get online users: %v", apiErr)
+		s.client.log(ctx).Error("failed to get online users: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value OnlineValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse online users response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse online users response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	s.client.logger.Info("successfully retrieved online users: count=%d", len(value.Online.Users))
-	return value.Online.Users, nil
+	users := value.User
+	if len(users) == 0 {
+		users = value.Online.Users
+	}
+
+	s.client.log(ctx).Info("successfully retrieved online users: count=%d", len(users))
+	return users, nil
 }
 
 // DisconnectUser disconnects a user session
 func (s *SecurityAPI) DisconnectUser(ctx context.Context, username string) error {
-	s.client.logger.Warn("disconnecting user: username=%s", username)
+	s.client.log(ctx).Warn("disconnecting user: username=%s", username)
 
 	req := []Request{{
 		Cmd: "Disconnect",
 		Param: DisconnectParam{
-			User: struct {
-				UserName string `json:"userName"`
-			}{
+			User: DisconnectTarget{
 				UserName: username,
 			},
 		},
@@ -198,28 +217,88 @@ func (s *SecurityAPI) DisconnectUser(ctx context.Context, username string) error
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to disconnect user: %v", err)
+		s.client.log(ctx).Error("failed to disconnect user: %v", err)
+		return fmt.Errorf("disconnect request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		s.client.log(ctx).Error("failed to disconnect user: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		s.client.log(ctx).Error("failed to disconnect user: %v", apiErr)
+		return apiErr
+	}
+
+	s.client.log(ctx).Info("successfully disconnected user")
+	return nil
+}
+
+// Disconnect forces the online session with the given session ID offline.
+// The session is looked up with GetOnlineUsers first; an error is returned if
+// it does not exist or the camera reports that it cannot be disconnected.
+func (s *SecurityAPI) Disconnect(ctx context.Context, sessionID int) error {
+	s.client.log(ctx).Warn("disconnecting session: sessionId=%d", sessionID)
+
+	users, err := s.GetOnlineUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to look up session %d: %w", sessionID, err)
+	}
+
+	var target *OnlineUser
+	for i := range users {
+		if users[i].SessionID == sessionID {
+			target = &users[i]
+			break
+		}
+	}
+	if target == nil {
+		err := fmt.Errorf("session %d is not online", sessionID)
+		s.client.log(ctx).Error("failed to disconnect session: %v", err)
+		return err
+	}
+	if target.CanBeDisconnected != 1 {
+		err := fmt.Errorf("session %d (%s) cannot be disconnected", sessionID, target.UserName)
+		s.client.log(ctx).Error("failed to disconnect session: %v", err)
+		return err
+	}
+
+	req := []Request{{
+		Cmd: "Disconnect",
+		Param: DisconnectParam{
+			User: DisconnectTarget{
+				UserName:  target.UserName,
+				SessionID: sessionID,
+			},
+		},
+	}}
+
+	var resp []Response
+	if err := s.client.do(ctx, req, &resp); err != nil {
+		s.client.log(ctx).Error("failed to disconnect session: %v", err)
 		return fmt.Errorf("disconnect request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to disconnect user: %v", err)
+		s.client.log(ctx).Error("failed to disconnect session: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to disconnect user: %v", apiErr)
+		s.client.log(ctx).Error("failed to disconnect session: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully disconnected user")
+	s.client.log(ctx).Info("successfully disconnected session %d (%s from %s)", sessionID, target.UserName, target.IP)
 	return nil
 }
 
 // GetSysCfg exports system configuration
 func (s *SecurityAPI) GetSysCfg(ctx context.Context, channel int) (map[string]interface{}, error) {
-	s.client.logger.Debug("getting system configuration export")
+	s.client.log(ctx).Debug("getting system configuration export")
 
 	req := []Request{{
 		Cmd: "GetSysCfg",
@@ -230,24 +309,24 @@ func (s *SecurityAPI) GetSysCfg(ctx context.Context, channel int) (map[string]in
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get system configuration export: %v", err)
+		s.client.log(ctx).Error("failed to get system configuration export: %v", err)
 		return nil, fmt.Errorf("GetSysCfg request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get system configuration export: %v", err)
+		s.client.log(ctx).Error("failed to get system configuration export: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get system configuration export: %v", apiErr)
+		s.client.log(ctx).Error("failed to get system configuration export: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value map[string]interface{}
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse system configuration export response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse system configuration export response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -256,7 +335,7 @@ func (s *SecurityAPI) GetSysCfg(ctx context.Context, channel int) (map[string]in
 
 // SetSysCfg imports system configuration
 func (s *SecurityAPI) SetSysCfg(ctx context.Context, config map[string]interface{}) error {
-	s.client.logger.Info("importing system configuration")
+	s.client.log(ctx).Info("importing system configuration")
 
 	req := []Request{{
 		Cmd:   "SetSysCfg",
@@ -265,28 +344,28 @@ func (s *SecurityAPI) SetSysCfg(ctx context.Context, config map[string]interface
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to import system configuration: %v", err)
+		s.client.log(ctx).Error("failed to import system configuration: %v", err)
 		return fmt.Errorf("SetSysCfg request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to import system configuration: %v", err)
+		s.client.log(ctx).Error("failed to import system configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to import system configuration: %v", apiErr)
+		s.client.log(ctx).Error("failed to import system configuration: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully imported system configuration")
+	s.client.log(ctx).Info("successfully imported system configuration")
 	return nil
 }
 
 // GetCertificateInfo gets SSL certificate information
 func (s *SecurityAPI) GetCertificateInfo(ctx context.Context) (*CertificateInfo, error) {
-	s.client.logger.Debug("getting certificate info")
+	s.client.log(ctx).Debug("getting certificate info")
 
 	req := []Request{{
 		Cmd:    "GetCertificateInfo",
@@ -296,24 +375,24 @@ func (s *SecurityAPI) GetCertificateInfo(ctx context.Context) (*CertificateInfo,
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get certificate info: %v", err)
+		s.client.log(ctx).Error("failed to get certificate info: %v", err)
 		return nil, fmt.Errorf("GetCertificateInfo request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get certificate info: %v", err)
+		s.client.log(ctx).Error("failed to get certificate info: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get certificate info: %v", apiErr)
+		s.client.log(ctx).Error("failed to get certificate info: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value CertificateInfoValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse certificate info response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse certificate info response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -322,7 +401,7 @@ func (s *SecurityAPI) GetCertificateInfo(ctx context.Context) (*CertificateInfo,
 
 // CertificateClear clears SSL certificate
 func (s *SecurityAPI) CertificateClear(ctx context.Context) error {
-	s.client.logger.Warn("clearing SSL certificate (destructive)")
+	s.client.log(ctx).Warn("clearing SSL certificate (destructive)")
 
 	req := []Request{{
 		Cmd:    "CertificateClear",
@@ -332,21 +411,191 @@ func (s *SecurityAPI) CertificateClear(ctx context.Context) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to clear certificate: %v", err)
+		s.client.log(ctx).Error("failed to clear certificate: %v", err)
 		return fmt.Errorf("CertificateClear request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to clear certificate: %v", err)
+		s.client.log(ctx).Error("failed to clear certificate: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		s.client.log(ctx).Error("failed to clear certificate: %v", apiErr)
+		return apiErr
+	}
+
+	s.client.log(ctx).Info("successfully cleared SSL certificate")
+	return nil
+}
+
+// UserPermissions is the per-feature permission set of a v2.0 user account
+// (0=denied, 1=allowed). The v2.0 user commands and this permission format
+// are not in the API guide and have not been verified against a device
+// capture; firmware without them answers with an error for which
+// IsNotSupported is true.
+type UserPermissions struct {
+	LiveView int `json:"liveView"` // Watch live streams
+	Playback int `json:"playback"` // Search and play back recordings
+	Download int `json:"download"` // Download recordings
+	PTZ      int `json:"ptz"`      // Control pan/tilt/zoom
+	Talk     int `json:"talk"`     // Two-way audio
+	Config   int `json:"config"`   // Change device settings
+}
+
+// UserV20 represents a user account with v2.0 fine-grained permissions
+// (undocumented and unverified, see UserPermissions)
+type UserV20 struct {
+	UserName    string          `json:"userName"`
+	Password    string          `json:"password,omitempty"`
+	Level       string          `json:"level"` // "admin" or "guest"
+	Permissions UserPermissions `json:"permissions"`
+}
+
+// UserV20Value wraps the v2.0 user array for API response
+type UserV20Value struct {
+	User []UserV20 `json:"User"`
+}
+
+// GrantLiveViewOnly restricts user to live view: every other permission is
+// revoked and the level is set to guest
+func GrantLiveViewOnly(user *UserV20) {
+	user.Level = "guest"
+	user.Permissions = UserPermissions{LiveView: 1}
+}
+
+// GrantViewer allows live view, playback and download but no control or
+// configuration
+func GrantViewer(user *UserV20) {
+	user.Level = "guest"
+	user.Permissions = UserPermissions{LiveView: 1, Playback: 1, Download: 1}
+}
+
+// GrantOperator allows everything except changing device settings
+func GrantOperator(user *UserV20) {
+	user.Level = "guest"
+	user.Permissions = UserPermissions{LiveView: 1, Playback: 1, Download: 1, PTZ: 1, Talk: 1}
+}
+
+// GrantFullAccess gives user admin level with every permission
+func GrantFullAccess(user *UserV20) {
+	user.Level = "admin"
+	user.Permissions = UserPermissions{LiveView: 1, Playback: 1, Download: 1, PTZ: 1, Talk: 1, Config: 1}
+}
+
+// GetUsersV20 retrieves the list of users with v2.0 permissions.
+// GetUserV20 is not in the API guide and its response format is unverified.
+func (s *SecurityAPI) GetUsersV20(ctx context.Context) ([]UserV20, error) {
+	s.client.log(ctx).Debug("getting users (v2.0)")
+
+	req := []Request{{
+		Cmd:    "GetUserV20",
+		Action: 0,
+	}}
+
+	var resp []Response
+	if err := s.client.do(ctx, req, &resp); err != nil {
+		s.client.log(ctx).Error("failed to get users (v2.0): %v", err)
+		return nil, fmt.Errorf("GetUserV20 request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		s.client.log(ctx).Error("failed to get users (v2.0): %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		s.client.log(ctx).Error("failed to get users (v2.0): %v", apiErr)
+		return nil, apiErr
+	}
+
+	var value UserV20Value
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse users (v2.0) response: %v", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	s.client.log(ctx).Info("successfully retrieved users (v2.0): count=%d", len(value.User))
+	return value.User, nil
+}
+
+// AddUserV20 adds a new user with v2.0 permissions. AddUserV20 is not in
+// the API guide and its parameter format is unverified.
+func (s *SecurityAPI) AddUserV20(ctx context.Context, user UserV20) error {
+	s.client.log(ctx).Info("adding user (v2.0): username=%s", user.UserName)
+
+	if err := ValidatePassword(user.Password); err != nil {
+		s.client.log(ctx).Error("failed to add user (v2.0): %v", err)
+		return err
+	}
+
+	req := []Request{{
+		Cmd: "AddUserV20",
+		Param: map[string]interface{}{
+			"User": user,
+		},
+	}}
+
+	var resp []Response
+	if err := s.client.do(ctx, req, &resp); err != nil {
+		s.client.log(ctx).Error("failed to add user (v2.0): %v", err)
+		return fmt.Errorf("AddUserV20 request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		s.client.log(ctx).Error("failed to add user (v2.0): %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		s.client.log(ctx).Error("failed to add user (v2.0): %v", apiErr)
+		return apiErr
+	}
+
+	s.client.log(ctx).Info("successfully added user (v2.0)")
+	return nil
+}
+
+// ModifyUserV20 modifies an existing user's password, level or
+// permissions. ModifyUserV20 is not in the API guide and its parameter
+// format is unverified.
+func (s *SecurityAPI) ModifyUserV20(ctx context.Context, user UserV20) error {
+	s.client.log(ctx).Info("modifying user (v2.0): username=%s", user.UserName)
+
+	if user.Password != "" {
+		if err := ValidatePassword(user.Password); err != nil {
+			s.client.log(ctx).Error("failed to modify user (v2.0): %v", err)
+			return err
+		}
+	}
+
+	req := []Request{{
+		Cmd: "ModifyUserV20",
+		Param: map[string]interface{}{
+			"User": user,
+		},
+	}}
+
+	var resp []Response
+	if err := s.client.do(ctx, req, &resp); err != nil {
+		s.client.log(ctx).Error("failed to modify user (v2.0): %v", err)
+		return fmt.Errorf("ModifyUserV20 request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		s.client.log(ctx).Error("failed to modify user (v2.0): %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to clear certificate: %v", apiErr)
+		s.client.log(ctx).Error("failed to modify user (v2.0): %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully cleared SSL certificate")
+	s.client.log(ctx).Info("successfully modified user (v2.0)")
 	return nil
 }
@@ -371,3 +371,125 @@ func TestSecurityAPI_SetSysCfg(t *testing.T) {
 		t.Fatalf("SetSysCfg failed: %v", err)
 	}
 }
+
+func TestSecurityAPI_GetUsersV20(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetUserV20": `{"User": [{"userName": "admin", "level": "admin", "permissions": {"liveView": 1, "playback": 1, "download": 1, "ptz": 1, "talk": 1, "config": 1}}, {"userName": "viewer", "level": "guest", "permissions": {"liveView": 1}}]}`,
+	})
+	client := server.client()
+
+	users, err := client.Security.GetUsersV20(t.Context())
+	if err != nil {
+		t.Fatalf("GetUsersV20 failed: %v", err)
+	}
+
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	if users[0].Permissions.Config != 1 {
+		t.Error("expected admin to have config permission")
+	}
+	if users[1].Permissions.LiveView != 1 || users[1].Permissions.Playback != 0 {
+		t.Errorf("unexpected viewer permissions: %+v", users[1].Permissions)
+	}
+}
+
+func TestSecurityAPI_AddUserV20_LiveViewOnly(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"AddUserV20": "",
+	})
+	client := server.client()
+
+	user := UserV20{UserName: "kiosk", Password: "Kiosk-2024"}
+	GrantLiveViewOnly(&user)
+
+	if err := client.Security.AddUserV20(t.Context(), user); err != nil {
+		t.Fatalf("AddUserV20 failed: %v", err)
+	}
+
+	var param struct {
+		User UserV20 `json:"User"`
+	}
+	if err := json.Unmarshal(server.lastParam("AddUserV20"), &param); err != nil {
+		t.Fatalf("failed to decode param: %v", err)
+	}
+	want := UserPermissions{LiveView: 1}
+	if param.User.Permissions != want {
+		t.Errorf("expected live view only permissions, got %+v", param.User.Permissions)
+	}
+	if param.User.Level != "guest" {
+		t.Errorf("expected guest level, got %s", param.User.Level)
+	}
+}
+
+func TestSecurityAPI_ModifyUserV20(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"ModifyUserV20": "",
+	})
+	client := server.client()
+
+	user := UserV20{UserName: "ops"}
+	GrantOperator(&user)
+
+	if err := client.Security.ModifyUserV20(t.Context(), user); err != nil {
+		t.Fatalf("ModifyUserV20 failed: %v", err)
+	}
+	if server.callCount("ModifyUserV20") != 1 {
+		t.Error("expected ModifyUserV20 to be sent")
+	}
+
+	user.Password = "bad&pass"
+	if err := client.Security.ModifyUserV20(t.Context(), user); err == nil {
+		t.Error("expected error for invalid password")
+	}
+}
+
+func TestSecurityAPI_GetOnlineUsers_SessionMetadata(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetOnline": `{"User": [{"canbeDisconn": 0, "ip": "192.168.2.166", "level": "admin", "sessionId": 1000, "userName": "admin"}, {"canbeDisconn": 1, "ip": "192.168.2.20", "level": "guest", "sessionId": 1001, "userName": "viewer"}]}`,
+	})
+	client := server.client()
+
+	users, err := client.Security.GetOnlineUsers(t.Context())
+	if err != nil {
+		t.Fatalf("GetOnlineUsers failed: %v", err)
+	}
+
+	if len(users) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(users))
+	}
+	if users[1].SessionID != 1001 || users[1].CanBeDisconnected != 1 || users[1].Level != "guest" {
+		t.Errorf("unexpected session metadata: %+v", users[1])
+	}
+}
+
+func TestSecurityAPI_Disconnect(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetOnline":  `{"User": [{"canbeDisconn": 0, "ip": "192.168.2.166", "sessionId": 1000, "userName": "admin"}, {"canbeDisconn": 1, "ip": "192.168.2.20", "sessionId": 1001, "userName": "viewer"}]}`,
+		"Disconnect": "",
+	})
+	client := server.client()
+	ctx := t.Context()
+
+	if err := client.Security.Disconnect(ctx, 1001); err != nil {
+		t.Fatalf("Disconnect failed: %v", err)
+	}
+
+	var param DisconnectParam
+	if err := json.Unmarshal(server.lastParam("Disconnect"), &param); err != nil {
+		t.Fatalf("failed to decode param: %v", err)
+	}
+	if param.User.UserName != "viewer" || param.User.SessionID != 1001 {
+		t.Errorf("unexpected disconnect target: %+v", param.User)
+	}
+
+	if err := client.Security.Disconnect(ctx, 1000); err == nil {
+		t.Error("expected error for session that cannot be disconnected")
+	}
+	if err := client.Security.Disconnect(ctx, 4242); err == nil {
+		t.Error("expected error for unknown session")
+	}
+	if server.callCount("Disconnect") != 1 {
+		t.Errorf("expected exactly one Disconnect request, got %d", server.callCount("Disconnect"))
+	}
+}
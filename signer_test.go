@@ -0,0 +1,81 @@
+package reolink
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACSigner(t *testing.T) {
+	signer := NewHMACSigner("proxy-1", []byte("secret"))
+	signer.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	var (
+		headers http.Header
+		uri     string
+		body    []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		uri = r.URL.RequestURI()
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"cmd": "GetDevInfo", "code": 0, "value": {"DevInfo": {"model": "RLC-810A"}}}]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	WithRequestSigner(signer)(client)
+
+	if _, err := client.System.GetDeviceInfo(t.Context()); err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+
+	if got := headers.Get(HeaderSignatureKeyID); got != "proxy-1" {
+		t.Errorf("expected key id proxy-1, got %q", got)
+	}
+	if got := headers.Get(HeaderSignatureTimestamp); got != "1700000000" {
+		t.Errorf("expected timestamp 1700000000, got %q", got)
+	}
+	nonce := headers.Get(HeaderSignatureNonce)
+	if len(nonce) != 32 {
+		t.Errorf("expected 32 hex char nonce, got %q", nonce)
+	}
+
+	want := signer.Sign(http.MethodPost, uri, "1700000000", nonce, body)
+	if got := headers.Get(HeaderSignature); got != want {
+		t.Errorf("signature mismatch: got %q, want %q", got, want)
+	}
+	if !strings.Contains(uri, "cmd=GetDevInfo") {
+		t.Errorf("expected signed URI to include the query, got %q", uri)
+	}
+
+	// The signature must cover the body
+	if signer.Sign(http.MethodPost, uri, "1700000000", nonce, []byte("tampered")) == want {
+		t.Error("expected signature to change with the body")
+	}
+}
+
+func TestRequestSigner_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent when signing fails")
+	}))
+	defer server.Close()
+
+	signErr := errors.New("key unavailable")
+	client := newTestClient(server)
+	WithRequestSigner(RequestSignerFunc(func(*http.Request, []byte) error {
+		return signErr
+	}))(client)
+
+	if _, err := client.System.GetDeviceInfo(t.Context()); !errors.Is(err, signErr) {
+		t.Errorf("expected signing error, got %v", err)
+	}
+	if _, err := client.Encoding.Snap(t.Context(), 0); !errors.Is(err, signErr) {
+		t.Errorf("expected signing error from Snap, got %v", err)
+	}
+}
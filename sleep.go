@@ -0,0 +1,139 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// ErrCameraAsleep is returned by clients configured with WithSleepHandling
+// when a battery camera does not answer because it is asleep. Battery
+// cameras turn their network off between events and only answer HTTP while
+// awake, so a connection that cannot be established means "asleep" rather
+// than "broken" for them.
+var ErrCameraAsleep = errors.New("camera is asleep")
+
+// Waker wakes a sleeping battery camera. Reolink's wake-up travels over the
+// proprietary Baichuan protocol or the P2P relay, which this package does
+// not implement beyond P2PWaker; plug in an implementation that sends it.
+type Waker interface {
+	Wake(ctx context.Context, client *Client) error
+}
+
+// WakerFunc adapts an ordinary function to the Waker interface
+type WakerFunc func(ctx context.Context, client *Client) error
+
+// Wake calls f(ctx, client)
+func (f WakerFunc) Wake(ctx context.Context, client *Client) error {
+	return f(ctx, client)
+}
+
+// P2PWaker returns a Waker that nudges the camera by opening a P2P
+// connection to its UID, which makes the relay wake it, and waits settle
+// for its HTTP server to come up (default: 2s). The client must have a UID
+// (see WithP2PDialer).
+func P2PWaker(dialer P2PDialer, settle time.Duration) Waker {
+	if settle <= 0 {
+		settle = 2 * time.Second
+	}
+	return WakerFunc(func(ctx context.Context, client *Client) error {
+		if client.UID() == "" {
+			return fmt.Errorf("camera has no P2P UID")
+		}
+		conn, err := dialer.DialUID(ctx, client.UID())
+		if err != nil {
+			return fmt.Errorf("p2p dial %s failed: %w", client.UID(), err)
+		}
+		conn.Close()
+		return sleepCtx(ctx, settle)
+	})
+}
+
+// SleepOptions configures WithSleepHandling
+type SleepOptions struct {
+	// Waker, if not nil, wakes the camera when a request finds it asleep;
+	// the request is then retried once
+	Waker Waker
+	// Handler, if not nil, receives EventCameraAsleep and EventCameraAwake
+	// when the client sees the camera fall asleep or wake up
+	Handler EventHandler
+}
+
+// WithSleepHandling marks the client as talking to a battery camera. API
+// requests that fail to connect return an error wrapping ErrCameraAsleep
+// instead of a plain network error; with a Waker they wake the camera and
+// retry first. Do not use it for mains-powered cameras, where the same
+// errors mean the camera is offline.
+func WithSleepHandling(opts SleepOptions) Option {
+	return func(c *Client) {
+		c.sleep = &opts
+	}
+}
+
+// Asleep reports whether the last API request found the camera asleep. It
+// is always false without WithSleepHandling.
+func (c *Client) Asleep() bool {
+	return c.asleep.Load()
+}
+
+// doSleepAware runs do, translating connection failures into
+// ErrCameraAsleep and waking the camera if configured
+func (c *Client) doSleepAware(ctx context.Context, do func(context.Context) error) error {
+	err := do(ctx)
+	if err == nil {
+		c.setAsleep(ctx, false)
+		return nil
+	}
+	if ctx.Err() != nil || !isSleepError(err) {
+		return err
+	}
+	c.setAsleep(ctx, true)
+
+	if c.sleep.Waker == nil {
+		return fmt.Errorf("%w: %w", ErrCameraAsleep, err)
+	}
+	c.log(ctx).Info("camera asleep, waking it")
+	if werr := c.sleep.Waker.Wake(ctx, c); werr != nil {
+		c.log(ctx).Warn("failed to wake camera: %v", werr)
+		return fmt.Errorf("%w: wake failed: %w", ErrCameraAsleep, werr)
+	}
+	if err := do(ctx); err != nil {
+		if ctx.Err() == nil && isSleepError(err) {
+			return fmt.Errorf("%w: still not answering after wake: %w", ErrCameraAsleep, err)
+		}
+		return err
+	}
+	c.setAsleep(ctx, false)
+	return nil
+}
+
+// setAsleep records the sleep state and reports transitions
+func (c *Client) setAsleep(ctx context.Context, asleep bool) {
+	if c.asleep.Swap(asleep) == asleep {
+		return
+	}
+	ev := Event{Type: EventCameraAwake, Host: c.Host(), Time: time.Now()}
+	if asleep {
+		ev.Type = EventCameraAsleep
+	}
+	c.log(ctx).Info("camera state changed: %s", ev.Type)
+	if c.sleep.Handler != nil {
+		c.sleep.Handler(ev)
+	}
+}
+
+// isSleepError reports whether err is a connection failure a sleeping
+// camera causes: a failed dial, a refused connection or an unreachable
+// host. Timeouts and resets on an established connection do not count:
+// the camera may already have run the command, so sending it again could
+// run a Reboot or a PTZ move twice.
+func isSleepError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EHOSTUNREACH)
+}
@@ -0,0 +1,178 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// sleepyTransport refuses connections while asleep is set, like a battery
+// camera with its network off
+type sleepyTransport struct {
+	next   http.RoundTripper
+	asleep atomic.Bool
+}
+
+func (t *sleepyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.asleep.Load() {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestClient_SleepHandling(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetDevName": `{"DevName": {"name": "Porch"}}`,
+	})
+
+	var (
+		mu     sync.Mutex
+		events []EventType
+	)
+	handler := func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev.Type)
+	}
+
+	t.Run("asleep", func(t *testing.T) {
+		events = nil
+		client := srv.client()
+		transport := &sleepyTransport{next: srv.Client().Transport}
+		transport.asleep.Store(true)
+		client.httpClient.Transport = transport
+		WithSleepHandling(SleepOptions{Handler: handler})(client)
+
+		_, err := client.System.GetDeviceName(t.Context())
+		if !errors.Is(err, ErrCameraAsleep) || !client.Asleep() {
+			t.Fatalf("expected ErrCameraAsleep, got %v", err)
+		}
+		transport.asleep.Store(false)
+		if _, err := client.System.GetDeviceName(t.Context()); err != nil || client.Asleep() {
+			t.Fatalf("GetDeviceName failed: %v", err)
+		}
+		if len(events) != 2 || events[0] != EventCameraAsleep || events[1] != EventCameraAwake {
+			t.Errorf("unexpected events %v", events)
+		}
+	})
+
+	t.Run("wake", func(t *testing.T) {
+		events = nil
+		client := srv.client()
+		transport := &sleepyTransport{next: srv.Client().Transport}
+		transport.asleep.Store(true)
+		client.httpClient.Transport = transport
+		woken := 0
+		WithSleepHandling(SleepOptions{
+			Handler: handler,
+			Waker: WakerFunc(func(ctx context.Context, c *Client) error {
+				woken++
+				transport.asleep.Store(false)
+				return nil
+			}),
+		})(client)
+
+		name, err := client.System.GetDeviceName(t.Context())
+		if err != nil || name != "Porch" {
+			t.Fatalf("GetDeviceName = %q, %v", name, err)
+		}
+		if woken != 1 || len(events) != 2 {
+			t.Errorf("expected one wake and asleep/awake events, got %d wakes, events %v", woken, events)
+		}
+	})
+
+	t.Run("reset not retried", func(t *testing.T) {
+		client := srv.client()
+		var calls atomic.Int32
+		client.httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls.Add(1)
+			return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+		})
+		woken := 0
+		WithSleepHandling(SleepOptions{Waker: WakerFunc(func(ctx context.Context, c *Client) error {
+			woken++
+			return nil
+		})})(client)
+
+		// The camera may have rebooted already; sending Reboot again must
+		// not happen
+		err := client.System.Reboot(t.Context())
+		if err == nil || errors.Is(err, ErrCameraAsleep) {
+			t.Errorf("expected a plain network error, got %v", err)
+		}
+		if calls.Load() != 1 || woken != 0 {
+			t.Errorf("expected one attempt and no wake, got %d attempts, %d wakes", calls.Load(), woken)
+		}
+	})
+
+	t.Run("streamed", func(t *testing.T) {
+		client := srv.client()
+		transport := &sleepyTransport{next: srv.Client().Transport}
+		transport.asleep.Store(true)
+		client.httpClient.Transport = transport
+		WithSleepHandling(SleepOptions{})(client)
+
+		if _, err := client.System.GetAbility(t.Context()); !errors.Is(err, ErrCameraAsleep) {
+			t.Errorf("expected ErrCameraAsleep from streamed GetAbility, got %v", err)
+		}
+	})
+
+	t.Run("without sleep handling", func(t *testing.T) {
+		client := srv.client()
+		transport := &sleepyTransport{next: srv.Client().Transport}
+		transport.asleep.Store(true)
+		client.httpClient.Transport = transport
+		if _, err := client.System.GetDeviceName(t.Context()); err == nil || errors.Is(err, ErrCameraAsleep) {
+			t.Errorf("expected a plain network error, got %v", err)
+		}
+	})
+}
+
+// fakeConn is a P2P connection that does nothing
+type fakeConn struct{ net.Conn }
+
+func (fakeConn) Close() error { return nil }
+
+func TestP2PWaker(t *testing.T) {
+	var dialed string
+	dialer := P2PDialerFunc(func(ctx context.Context, uid string) (net.Conn, error) {
+		dialed = uid
+		return fakeConn{}, nil
+	})
+	waker := P2PWaker(dialer, time.Millisecond)
+
+	if err := waker.Wake(t.Context(), NewClient("192.168.1.50")); err == nil {
+		t.Error("expected an error for a client without UID")
+	}
+	client := NewClient("", WithP2PDialer("95270000ABCDEFGH", nil))
+	if err := waker.Wake(t.Context(), client); err != nil || dialed != "95270000ABCDEFGH" {
+		t.Errorf("Wake = %v, dialed %q", err, dialed)
+	}
+}
+
+func TestChannelStatusEvents_Sleep(t *testing.T) {
+	previous := map[int]ChannelStatus{
+		0: {Channel: 0, Online: 1},
+		1: {Channel: 1, Online: 1, Sleep: 1},
+	}
+	current := []ChannelStatus{
+		{Channel: 0, Online: 1, Sleep: 1},
+		{Channel: 1, Online: 1},
+	}
+	events := channelStatusEvents("hub", previous, current, time.Now())
+	if len(events) != 2 || events[0].Type != EventCameraAsleep || events[0].Channel != 0 ||
+		events[1].Type != EventCameraAwake || events[1].Channel != 1 {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
@@ -0,0 +1,192 @@
+package reolink
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SnapshotCacheOptions configures a SnapshotCache
+type SnapshotCacheOptions struct {
+	// MaxAge is how long a snapshot is served without contacting the
+	// camera (default: 1s)
+	MaxAge time.Duration
+	// StaleWhileRevalidate is how long past MaxAge a snapshot is still
+	// served immediately while a fresh one is fetched in the background
+	// (default: 10s; negative disables it)
+	StaleWhileRevalidate time.Duration
+	// RefreshTimeout bounds background refreshes, which are not tied to
+	// any caller's context (default: 10s)
+	RefreshTimeout time.Duration
+}
+
+// SnapshotCacheStats counts how SnapshotCache.Get requests were served
+type SnapshotCacheStats struct {
+	Hits   uint64 // Served a snapshot younger than MaxAge
+	Stale  uint64 // Served an older snapshot, either within StaleWhileRevalidate or because the camera missed the caller's deadline
+	Misses uint64 // Waited for the camera
+}
+
+// HitRate returns the fraction of requests served from the cache
+func (s SnapshotCacheStats) HitRate() float64 {
+	total := s.Hits + s.Stale + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.Stale) / float64(total)
+}
+
+// SnapshotCache serves recent snapshots per channel, so dashboards polling
+// every second do not queue requests on a slow camera. Concurrent requests
+// for a channel share one fetch, and if the camera misses the caller's
+// deadline the last snapshot is returned instead of an error.
+//
+// With a MetricsRecorder configured, every Get counts towards
+// reolink_snapshot_cache_requests_total with a result label of "hit",
+// "stale" or "miss".
+type SnapshotCache struct {
+	client *Client
+	opts   SnapshotCacheOptions
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[int]*snapshotEntry
+
+	hits, stale, misses atomic.Uint64
+}
+
+// snapshotEntry is the cached snapshot of one channel
+type snapshotEntry struct {
+	snap     Snapshot
+	fetching chan struct{} // Closed when the running fetch ends; nil if none
+	err      error         // Result of the last fetch
+}
+
+// NewSnapshotCache creates a snapshot cache for client, filling in defaults
+// for unset options
+func NewSnapshotCache(client *Client, opts SnapshotCacheOptions) *SnapshotCache {
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = time.Second
+	}
+	if opts.StaleWhileRevalidate < 0 {
+		opts.StaleWhileRevalidate = 0
+	} else if opts.StaleWhileRevalidate == 0 {
+		opts.StaleWhileRevalidate = 10 * time.Second
+	}
+	if opts.RefreshTimeout <= 0 {
+		opts.RefreshTimeout = 10 * time.Second
+	}
+	return &SnapshotCache{
+		client:  client,
+		opts:    opts,
+		now:     time.Now,
+		entries: make(map[int]*snapshotEntry),
+	}
+}
+
+// Get returns a snapshot of channel no older than MaxAge if possible. A
+// snapshot within StaleWhileRevalidate is returned at once and refreshed in
+// the background. Otherwise Get waits for the camera until ctx is done, and
+// then falls back to the last snapshot if there is one; Snapshot.Time tells
+// how old the returned image is.
+func (s *SnapshotCache) Get(ctx context.Context, channel int) (Snapshot, error) {
+	s.mu.Lock()
+	e := s.entries[channel]
+	if e == nil {
+		e = &snapshotEntry{}
+		s.entries[channel] = e
+	}
+	age := s.now().Sub(e.snap.Time)
+	cached := e.snap
+	switch {
+	case cached.Image != nil && age < s.opts.MaxAge:
+		s.mu.Unlock()
+		s.record(channel, "hit")
+		return cached, nil
+	case cached.Image != nil && age < s.opts.MaxAge+s.opts.StaleWhileRevalidate:
+		s.startFetch(channel, e)
+		s.mu.Unlock()
+		s.record(channel, "stale")
+		return cached, nil
+	}
+	done := s.startFetch(channel, e)
+	s.mu.Unlock()
+
+	select {
+	case <-done:
+		s.mu.Lock()
+		snap, err := e.snap, e.err
+		s.mu.Unlock()
+		if err != nil && snap.Image != nil {
+			s.client.log(ctx).Warn("snapshot of channel %d failed, serving one from %s: %v", channel, snap.Time.Format(time.RFC3339), err)
+			s.record(channel, "stale")
+			return snap, nil
+		}
+		s.record(channel, "miss")
+		if err != nil {
+			return Snapshot{}, err
+		}
+		return snap, nil
+	case <-ctx.Done():
+		if cached.Image != nil {
+			s.client.log(ctx).Debug("snapshot of channel %d missed the deadline, serving one from %s", channel, cached.Time.Format(time.RFC3339))
+			s.record(channel, "stale")
+			return cached, nil
+		}
+		s.record(channel, "miss")
+		return Snapshot{}, ctx.Err()
+	}
+}
+
+// startFetch starts a background fetch for e unless one is running, and
+// returns the channel closed when it ends. It is called with s.mu held.
+func (s *SnapshotCache) startFetch(channel int, e *snapshotEntry) <-chan struct{} {
+	if e.fetching != nil {
+		return e.fetching
+	}
+	done := make(chan struct{})
+	e.fetching = done
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), s.opts.RefreshTimeout)
+		defer cancel()
+		image, err := s.client.Encoding.Snap(ctx, channel)
+
+		s.mu.Lock()
+		e.err = err
+		if err == nil {
+			e.snap = Snapshot{Time: s.now(), Image: image}
+		}
+		e.fetching = nil
+		s.mu.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// record counts a Get result
+func (s *SnapshotCache) record(channel int, result string) {
+	switch result {
+	case "hit":
+		s.hits.Add(1)
+	case "stale":
+		s.stale.Add(1)
+	default:
+		s.misses.Add(1)
+	}
+	s.client.addCounter("reolink_snapshot_cache_requests_total", 1, map[string]string{
+		"channel": strconv.Itoa(channel),
+		"result":  result,
+	})
+}
+
+// Stats returns how Get requests were served so far
+func (s *SnapshotCache) Stats() SnapshotCacheStats {
+	return SnapshotCacheStats{
+		Hits:   s.hits.Load(),
+		Stale:  s.stale.Load(),
+		Misses: s.misses.Load(),
+	}
+}
@@ -0,0 +1,140 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowSnapServer serves testJPEG for Snap, blocking while gate is held
+type slowSnapServer struct {
+	*httptest.Server
+	gate  sync.RWMutex
+	calls atomic.Int32
+}
+
+func newSlowSnapServer(t *testing.T) *slowSnapServer {
+	t.Helper()
+	s := &slowSnapServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+		s.gate.RLock()
+		defer s.gate.RUnlock()
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(testJPEG)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestSnapshotCache_Get(t *testing.T) {
+	server := newSlowSnapServer(t)
+	client := newTestClient(server.Server)
+	metrics := NewMetrics()
+	WithMetrics(metrics)(client)
+
+	cache := NewSnapshotCache(client, SnapshotCacheOptions{MaxAge: time.Second, StaleWhileRevalidate: 5 * time.Second})
+	now := time.Now()
+	var mu sync.Mutex
+	cache.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		now = now.Add(d)
+		mu.Unlock()
+	}
+
+	// Miss, then a hit within MaxAge
+	snap, err := cache.Get(t.Context(), 0)
+	if err != nil || !bytes.Equal(snap.Image, testJPEG) {
+		t.Fatalf("Get = %v, %v", snap, err)
+	}
+	if _, err := cache.Get(t.Context(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if server.calls.Load() != 1 {
+		t.Errorf("expected 1 camera request, got %d", server.calls.Load())
+	}
+
+	// Stale within the revalidate window: served at once, refreshed behind
+	advance(2 * time.Second)
+	server.gate.Lock()
+	start := time.Now()
+	if _, err := cache.Get(t.Context(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("stale Get waited for the camera")
+	}
+
+	// Past the window the camera is slow: the last snapshot is served when
+	// the caller's deadline expires
+	advance(10 * time.Second)
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	snap, err = cache.Get(ctx, 0)
+	cancel()
+	if err != nil || snap.Image == nil {
+		t.Errorf("expected the last snapshot, got %v, %v", snap, err)
+	}
+	server.gate.Unlock()
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Stale != 2 || stats.Misses != 1 || stats.HitRate() != 0.75 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	found := false
+	for _, m := range metrics.Snapshot() {
+		if m.Name == "reolink_snapshot_cache_requests_total" && m.Labels["result"] == "stale" && m.Value == 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected stale counter, got %+v", metrics.Snapshot())
+	}
+}
+
+func TestSnapshotCache_SharedFetch(t *testing.T) {
+	server := newSlowSnapServer(t)
+	cache := NewSnapshotCache(newTestClient(server.Server), SnapshotCacheOptions{})
+
+	server.gate.Lock()
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.Get(t.Context(), 1); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	server.gate.Unlock()
+	wg.Wait()
+
+	if n := server.calls.Load(); n != 1 {
+		t.Errorf("expected concurrent Gets to share 1 request, got %d", n)
+	}
+}
+
+func TestSnapshotCache_NoSnapshotYet(t *testing.T) {
+	server := newSlowSnapServer(t)
+	cache := NewSnapshotCache(newTestClient(server.Server), SnapshotCacheOptions{})
+
+	server.gate.Lock()
+	defer server.gate.Unlock()
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cache.Get(ctx, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
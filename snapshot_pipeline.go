@@ -0,0 +1,221 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SnapshotPipelineOptions configures a SnapshotPipeline
+type SnapshotPipelineOptions struct {
+	// Channel is the camera channel to watch and capture from
+	Channel int
+	// Triggers are the event types that start a capture
+	// (default: EventMotionStart and EventAIStart)
+	Triggers []EventType
+	// Count is how many snapshots to capture per trigger (default: 3)
+	Count int
+	// Spacing is the delay between snapshots (default: 1s)
+	Spacing time.Duration
+	// PollInterval is how often alarm state is polled (default: 1s)
+	PollInterval time.Duration
+	// Cooldown suppresses triggers for this long after a capture ends
+	Cooldown time.Duration
+	// Source produces the trigger events (default: Alarm.WatchEvents on
+	// Channel every PollInterval). Use a MotionDetector's Run on firmware
+	// without reliable motion state.
+	Source EventSource
+	// Annotate burns the capture time, source and AI type into each
+	// snapshot with AnnotateSnapshot, for cameras whose OSD is disabled
+	Annotate bool
+
+	// PreRecording and PostRecording, when set, attach the recording files
+	// covering the window [trigger-PreRecording, trigger+PostRecording].
+	// The set is delivered once PostRecording has elapsed.
+	PreRecording  time.Duration
+	PostRecording time.Duration
+}
+
+// Snapshot is a single captured image
+type Snapshot struct {
+	Time  time.Time // When the snapshot was captured
+	Image []byte    // JPEG image data
+}
+
+// SnapshotSet is what a SnapshotPipeline delivers for one trigger
+type SnapshotSet struct {
+	Event      Event          // Event that triggered the capture
+	Snapshots  []Snapshot     // Captured snapshots in capture order
+	Recordings []SearchResult // Recordings around the event, if requested
+	Errors     []error        // Failed captures or recording lookups
+}
+
+// SnapshotPipeline captures bursts of snapshots when motion or AI events
+// occur on a channel
+type SnapshotPipeline struct {
+	client *Client
+	opts   SnapshotPipelineOptions
+}
+
+// NewSnapshotPipeline creates a snapshot pipeline for client, filling in
+// defaults for unset options
+func NewSnapshotPipeline(client *Client, opts SnapshotPipelineOptions) *SnapshotPipeline {
+	if len(opts.Triggers) == 0 {
+		opts.Triggers = []EventType{EventMotionStart, EventAIStart}
+	}
+	if opts.Count <= 0 {
+		opts.Count = 3
+	}
+	if opts.Spacing <= 0 {
+		opts.Spacing = time.Second
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	return &SnapshotPipeline{client: client, opts: opts}
+}
+
+// Run watches for trigger events and calls deliver with a SnapshotSet for
+// each. Captures run one at a time; triggers that arrive while a capture is
+// in progress or within the cooldown are dropped. deliver is never called
+// concurrently. Run blocks until ctx is done, waits for an in-progress
+// capture to finish and returns ctx.Err().
+func (p *SnapshotPipeline) Run(ctx context.Context, deliver func(SnapshotSet)) error {
+	if deliver == nil {
+		return fmt.Errorf("deliver must not be nil")
+	}
+
+	var (
+		mu        sync.Mutex
+		busy      bool
+		lastEnded time.Time
+		wg        sync.WaitGroup
+	)
+
+	handler := func(ev Event) {
+		if !p.isTrigger(ev.Type) {
+			return
+		}
+
+		mu.Lock()
+		if busy || (p.opts.Cooldown > 0 && time.Since(lastEnded) < p.opts.Cooldown) {
+			mu.Unlock()
+			p.client.log(ctx).Debug("snapshot trigger dropped: event=%s channel=%d", ev.Type, ev.Channel)
+			return
+		}
+		busy = true
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			set := p.capture(ctx, ev)
+			if ctx.Err() == nil {
+				deliver(set)
+			}
+
+			mu.Lock()
+			busy = false
+			lastEnded = time.Now()
+			mu.Unlock()
+		}()
+	}
+
+	source := p.opts.Source
+	if source == nil {
+		source = func(ctx context.Context, handler EventHandler) error {
+			return p.client.Alarm.WatchEvents(ctx, p.opts.Channel, p.opts.PollInterval, handler)
+		}
+	}
+	err := source(ctx, handler)
+	wg.Wait()
+	return err
+}
+
+// Stream runs the pipeline in the background and returns a channel of
+// snapshot sets, which is closed when ctx is done. Sets are dropped if the
+// receiver falls behind by more than one set.
+func (p *SnapshotPipeline) Stream(ctx context.Context) <-chan SnapshotSet {
+	out := make(chan SnapshotSet, 1)
+	go func() {
+		defer close(out)
+		_ = p.Run(ctx, func(set SnapshotSet) {
+			select {
+			case out <- set:
+			default:
+				p.client.log(ctx).Warn("snapshot set dropped: receiver not keeping up")
+			}
+		})
+	}()
+	return out
+}
+
+// isTrigger reports whether events of type typ start a capture
+func (p *SnapshotPipeline) isTrigger(typ EventType) bool {
+	for _, t := range p.opts.Triggers {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// capture takes the snapshot burst for ev and looks up recordings
+func (p *SnapshotPipeline) capture(ctx context.Context, ev Event) SnapshotSet {
+	set := SnapshotSet{Event: ev}
+
+	for i := 0; i < p.opts.Count; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return set
+			case <-time.After(p.opts.Spacing):
+			}
+		}
+		image, err := p.client.Encoding.Snap(ctx, p.opts.Channel)
+		if err != nil {
+			set.Errors = append(set.Errors, fmt.Errorf("snapshot %d: %w", i+1, err))
+			continue
+		}
+		snapshot := Snapshot{Time: time.Now(), Image: image}
+		if p.opts.Annotate {
+			annotated, err := AnnotateSnapshot(image, p.annotation(ev, snapshot.Time), AnnotateOptions{})
+			if err != nil {
+				set.Errors = append(set.Errors, fmt.Errorf("snapshot %d annotation: %w", i+1, err))
+			} else {
+				snapshot.Image = annotated
+			}
+		}
+		set.Snapshots = append(set.Snapshots, snapshot)
+	}
+
+	if p.opts.PreRecording <= 0 && p.opts.PostRecording <= 0 {
+		return set
+	}
+
+	end := ev.Time.Add(p.opts.PostRecording)
+	select {
+	case <-ctx.Done():
+		return set
+	case <-time.After(time.Until(end)):
+	}
+
+	recordings, err := p.client.Recording.Search(ctx, p.opts.Channel, ev.Time.Add(-p.opts.PreRecording), end, "main")
+	if err != nil {
+		set.Errors = append(set.Errors, fmt.Errorf("recording search: %w", err))
+		return set
+	}
+	set.Recordings = recordings
+	return set
+}
+
+// annotation describes a snapshot captured for ev
+func (p *SnapshotPipeline) annotation(ev Event, at time.Time) Annotation {
+	a := Annotation{Time: at, Camera: strings.TrimSpace(fmt.Sprintf("%s CH%d", ev.Host, ev.Channel))}
+	if ev.Type == EventAIStart && ev.Detail != "" {
+		a.Labels = []string{ev.Detail}
+	}
+	return a
+}
@@ -0,0 +1,154 @@
+package reolink
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSnapshotPipeline_Run(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": 0}`,
+		"Search":     `{"SearchResult": [{"fileName": "RecM01_event.mp4", "startTime": "2020-12-21T12:00:00Z", "endTime": "2020-12-21T12:01:00Z"}]}`,
+	})
+	client := srv.client()
+
+	pipeline := NewSnapshotPipeline(client, SnapshotPipelineOptions{
+		Count:         2,
+		Spacing:       5 * time.Millisecond,
+		PollInterval:  10 * time.Millisecond,
+		PostRecording: 20 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	sets := make(chan SnapshotSet, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- pipeline.Run(ctx, func(set SnapshotSet) {
+			sets <- set
+		})
+	}()
+
+	for srv.callCount("GetMdState") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.set("GetMdState", `{"state": 1}`)
+
+	var set SnapshotSet
+	select {
+	case set = <-sets:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for snapshot set")
+	}
+	cancel()
+	<-done
+
+	if set.Event.Type != EventMotionStart {
+		t.Errorf("expected motion start trigger, got %s", set.Event.Type)
+	}
+	if len(set.Snapshots) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d (errors: %v)", len(set.Snapshots), set.Errors)
+	}
+	if !bytes.Equal(set.Snapshots[0].Image, testJPEG) {
+		t.Error("expected snapshot image data")
+	}
+	if set.Snapshots[1].Time.Before(set.Snapshots[0].Time) {
+		t.Error("expected snapshots in capture order")
+	}
+	if len(set.Recordings) != 1 || set.Recordings[0].FileName != "RecM01_event.mp4" {
+		t.Errorf("expected recording to be attached, got %+v", set.Recordings)
+	}
+	if len(set.Errors) != 0 {
+		t.Errorf("unexpected errors: %v", set.Errors)
+	}
+}
+
+func TestSnapshotPipeline_Triggers(t *testing.T) {
+	pipeline := NewSnapshotPipeline(newCmdServer(t, nil).client(), SnapshotPipelineOptions{
+		Triggers: []EventType{EventAIStart},
+	})
+
+	if pipeline.isTrigger(EventMotionStart) {
+		t.Error("expected motion start not to trigger")
+	}
+	if !pipeline.isTrigger(EventAIStart) {
+		t.Error("expected AI start to trigger")
+	}
+	if pipeline.opts.Count != 3 || pipeline.opts.Spacing != time.Second {
+		t.Errorf("expected defaults to be applied, got %+v", pipeline.opts)
+	}
+}
+
+func TestSnapshotPipeline_Stream(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": 0}`,
+	})
+	pipeline := NewSnapshotPipeline(srv.client(), SnapshotPipelineOptions{
+		Count:        1,
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	out := pipeline.Stream(ctx)
+	for srv.callCount("GetMdState") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.set("GetMdState", `{"state": 1}`)
+
+	select {
+	case set := <-out:
+		if len(set.Snapshots) != 1 {
+			t.Errorf("expected 1 snapshot, got %d", len(set.Snapshots))
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for snapshot set")
+	}
+
+	cancel()
+	for range out {
+	}
+}
+
+func TestSnapshotPipeline_Annotate(t *testing.T) {
+	client := newCmdServer(t, nil).client()
+	pipeline := NewSnapshotPipeline(client, SnapshotPipelineOptions{Count: 1, Annotate: true})
+
+	// testJPEG cannot be decoded, so the original image is kept and the
+	// failure reported
+	set := pipeline.capture(t.Context(), Event{Type: EventAIStart, Host: "cam", Detail: AITypePeople})
+	if len(set.Snapshots) != 1 || !bytes.Equal(set.Snapshots[0].Image, testJPEG) {
+		t.Fatal("expected the unannotated snapshot")
+	}
+	if len(set.Errors) != 1 {
+		t.Errorf("expected 1 annotation error, got %v", set.Errors)
+	}
+
+	a := pipeline.annotation(Event{Type: EventAIStart, Host: "cam", Channel: 2, Detail: AITypePeople}, time.Now())
+	if a.Camera != "cam CH2" || len(a.Labels) != 1 || a.Labels[0] != AITypePeople {
+		t.Errorf("unexpected annotation: %+v", a)
+	}
+}
+
+func TestSnapshotPipeline_Source(t *testing.T) {
+	client := newCmdServer(t, nil).client()
+	pipeline := NewSnapshotPipeline(client, SnapshotPipelineOptions{
+		Count: 1,
+		Source: func(ctx context.Context, handler EventHandler) error {
+			handler(Event{Type: EventMotionStart, Detail: MotionDetectorDetail})
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	set := <-pipeline.Stream(ctx)
+	if set.Event.Detail != MotionDetectorDetail || len(set.Snapshots) != 1 {
+		t.Errorf("expected a capture triggered by the custom source, got %+v", set)
+	}
+}
@@ -0,0 +1,270 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenReuseMargin is how long before expiry a stored token stops being
+// reused, so it does not lapse mid-run
+const tokenReuseMargin = time.Minute
+
+// HostState is what a client persists about one camera session between
+// runs
+type HostState struct {
+	Token       string    `json:"token,omitempty"`       // Session token from the last login
+	TokenExpiry time.Time `json:"tokenExpiry,omitempty"` // When the token lease ends
+	Ports       *NetPort  `json:"ports,omitempty"`       // Last seen port configuration
+	Ability     *Ability  `json:"ability,omitempty"`     // Last seen ability snapshot
+	Updated     time.Time `json:"updated"`               // Last time the state was saved
+}
+
+// TokenValid reports whether the stored token can still be reused at now
+func (s *HostState) TokenValid(now time.Time) bool {
+	return s.Token != "" && now.Add(tokenReuseMargin).Before(s.TokenExpiry)
+}
+
+// StateStore persists client state per camera and user. Keys have the form
+// user@host, so clients logging in as different users never share a
+// session. Implementations must be safe for concurrent use.
+type StateStore interface {
+	// Load returns the stored state for key, or an empty state if none
+	// has been saved
+	Load(key string) (*HostState, error)
+	// Save stores the state for key, replacing any previous state
+	Save(key string, state *HostState) error
+}
+
+// WithStateStore persists session tokens, port configuration and ability
+// snapshots in store. Login reuses a stored token of the same user that has
+// not expired instead of opening a new session, which keeps short-lived
+// processes from exhausting the camera's session slots. Logout ends the
+// stored session, so processes that want to share it should not call
+// Logout on exit.
+//
+// Login also adopts the stored ports and abilities, so the HTTP port of
+// Quirk.HTTPCmds and the ability checks of helpers such as GetAllAlarms
+// and TriggerBuzzer do not query the camera again. GetNetPort and
+// GetAbility always query it and refresh the snapshots.
+func WithStateStore(store StateStore) Option {
+	return func(c *Client) {
+		c.state = store
+	}
+}
+
+// LoadState returns the persisted state for this client's host and user.
+// It returns an empty state if no state store is configured.
+func (c *Client) LoadState() (*HostState, error) {
+	if c.state == nil {
+		return &HostState{}, nil
+	}
+	return c.state.Load(c.stateKey())
+}
+
+// stateKey returns the StateStore key of the client, user@host
+func (c *Client) stateKey() string {
+	return c.username + "@" + c.Host()
+}
+
+// restoreSession adopts the stored port and ability snapshots and a stored,
+// unexpired token, and reports whether it adopted a token
+func (c *Client) restoreSession(ctx context.Context) bool {
+	if c.state == nil {
+		return false
+	}
+	state, err := c.state.Load(c.stateKey())
+	if err != nil {
+		c.log(ctx).Warn("failed to load stored state: %v", err)
+		return false
+	}
+	if state.Ports != nil && c.httpPort.Load() == 0 {
+		c.rememberHTTPPort(state.Ports)
+	}
+	if state.Ability != nil {
+		c.ability.CompareAndSwap(nil, state.Ability)
+	}
+	if !state.TokenValid(time.Now()) {
+		return false
+	}
+
+	c.tokenMu.Lock()
+	c.token = state.Token
+	c.tokenMu.Unlock()
+
+	c.log(ctx).Info("reusing stored session token, expires at %s", state.TokenExpiry.Format(time.RFC3339))
+	return true
+}
+
+// updateState applies fn to the stored state and saves it. Failures are
+// logged rather than returned since persistence is best-effort.
+func (c *Client) updateState(ctx context.Context, fn func(*HostState)) {
+	if c.state == nil {
+		return
+	}
+
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	state, err := c.state.Load(c.stateKey())
+	if err != nil {
+		c.log(ctx).Warn("failed to load stored state: %v", err)
+		state = &HostState{}
+	}
+	fn(state)
+	state.Updated = time.Now()
+	if err := c.state.Save(c.stateKey(), state); err != nil {
+		c.log(ctx).Warn("failed to save state: %v", err)
+	}
+}
+
+// moveState moves the stored state from oldKey to newKey, leaving an empty
+// state under oldKey
+func (c *Client) moveState(ctx context.Context, oldKey, newKey string) {
+	if c.state == nil || oldKey == newKey {
+		return
+	}
+
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	state, err := c.state.Load(oldKey)
+	if err != nil {
+		c.log(ctx).Warn("failed to load stored state: %v", err)
+		return
+	}
+	if err := c.state.Save(newKey, state); err != nil {
+		c.log(ctx).Warn("failed to save state: %v", err)
+		return
+	}
+	if err := c.state.Save(oldKey, &HostState{Updated: time.Now()}); err != nil {
+		c.log(ctx).Warn("failed to save state: %v", err)
+	}
+}
+
+// sessionRejected reports whether any response in body says the token is
+// missing or no longer valid
+func sessionRejected(body []byte) bool {
+	var resps []Response
+	if err := json.Unmarshal(body, &resps); err != nil {
+		return false
+	}
+	for i := range resps {
+		if apiErr := resps[i].ToAPIError(); apiErr != nil &&
+			(apiErr.RspCode == ErrCodeLoginRequired || apiErr.RspCode == ErrCodeTokenError) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileStateStore is a StateStore that keeps one JSON file per key in a
+// directory
+type FileStateStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStateStore creates a file-based state store in dir. If dir is
+// empty, DefaultStateDir is used.
+func NewFileStateStore(dir string) (*FileStateStore, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultStateDir(); err != nil {
+			return nil, err
+		}
+	}
+	return &FileStateStore{dir: dir}, nil
+}
+
+// DefaultStateDir returns the per-user directory used for stored state
+func DefaultStateDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(base, "reolink"), nil
+}
+
+// Dir returns the directory state files are stored in
+func (s *FileStateStore) Dir() string {
+	return s.dir
+}
+
+// Load returns the stored state for key, or an empty state if none has
+// been saved
+func (s *FileStateStore) Load(key string) (*HostState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &HostState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	var state HostState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	return &state, nil
+}
+
+// Save stores the state for key. The file is written atomically and is
+// readable only by the current user since it contains a session token.
+func (s *FileStateStore) Save(key string, state *HostState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".state-*")
+	if err != nil {
+		return fmt.Errorf("failed to create state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
+
+// path returns the state file for key. Bytes that are not safe in file
+// names are escaped as %XX, '%' included, so distinct keys never share a
+// file.
+func (s *FileStateStore) path(key string) string {
+	var name strings.Builder
+	for i := 0; i < len(key); i++ {
+		switch b := key[i]; {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9', b == '.', b == '-', b == '@':
+			name.WriteByte(b)
+		default:
+			fmt.Fprintf(&name, "%%%02X", b)
+		}
+	}
+	return filepath.Join(s.dir, name.String()+".json")
+}
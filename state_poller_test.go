@@ -0,0 +1,100 @@
+package reolink
+
+import (
+	"testing"
+)
+
+func TestStatePoller_Poll(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetMdState": `{"state": 1}`,
+		"GetAiState": `{"channel": 0, "people": {"alarm_state": 1, "support": 1}, "package": {"alarm_state": 0, "support": 1}}`,
+	})
+	client := server.client()
+	poller := client.Alarm.NewStatePoller(true, 0, 3)
+
+	states, err := poller.Poll(t.Context())
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(states) != 2 || states[1].Channel != 3 {
+		t.Fatalf("unexpected states: %+v", states)
+	}
+	for _, s := range states {
+		if !s.Motion || s.People.AlarmState != 1 || s.Package.Support != 1 || s.Err != nil {
+			t.Errorf("unexpected state: %+v", s)
+		}
+	}
+	if server.callCount("GetMdState") != 2 || server.callCount("GetAiState") != 2 {
+		t.Errorf("expected one batched request per command and channel")
+	}
+
+	// Errors replace earlier values rather than carrying them over
+	server.mu.Lock()
+	delete(server.values, "GetMdState")
+	delete(server.values, "GetAiState")
+	server.mu.Unlock()
+	states, err = poller.Poll(t.Context())
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	for _, s := range states {
+		if s.Motion || s.People.AlarmState != 0 || s.Err == nil {
+			t.Errorf("expected cleared state with an error, got %+v", s)
+		}
+	}
+}
+
+func TestStatePoller_MotionOnly(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"GetMdState": `{"state": 0}`})
+	client := server.client()
+
+	states, err := client.Alarm.NewStatePoller(false, 1).Poll(t.Context())
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(states) != 1 || states[0].Motion || states[0].Err != nil {
+		t.Errorf("unexpected states: %+v", states)
+	}
+	if server.callCount("GetAiState") != 0 {
+		t.Error("expected no AI state request")
+	}
+}
+
+func BenchmarkStatePoller(b *testing.B) {
+	values := map[string]string{
+		"GetMdState": `{"state": 1}`,
+		"GetAiState": `{"channel": 0, "people": {"alarm_state": 1, "support": 1}, "vehicle": {"alarm_state": 0, "support": 1}}`,
+	}
+	channels := []int{0, 1, 2, 3, 4, 5, 6, 7}
+
+	b.Run("StatePoller", func(b *testing.B) {
+		server := newUnstartedCmdServer(values)
+		server.Start()
+		defer server.Close()
+		poller := newTestClient(server.Server).Alarm.NewStatePoller(true, channels...)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := poller.Poll(b.Context()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("PerChannel", func(b *testing.B) {
+		server := newUnstartedCmdServer(values)
+		server.Start()
+		defer server.Close()
+		client := newTestClient(server.Server)
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, ch := range channels {
+				if _, err := client.Alarm.GetMdState(b.Context(), ch); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := client.AI.GetAiState(b.Context(), ch); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
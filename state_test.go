@@ -0,0 +1,238 @@
+package reolink
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStateStore_RoundTrip(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore failed: %v", err)
+	}
+
+	empty, err := store.Load("admin@192.168.1.100:443")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if empty.Token != "" {
+		t.Errorf("expected empty state for unknown host, got %+v", empty)
+	}
+
+	expiry := time.Now().Add(time.Hour).Round(time.Second)
+	state := &HostState{
+		Token:       "abc123",
+		TokenExpiry: expiry,
+	}
+	if err := store.Save("admin@192.168.1.100:443", state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := store.Load("admin@192.168.1.100:443")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Token != "abc123" || !loaded.TokenExpiry.Equal(expiry) {
+		t.Errorf("unexpected token state: %+v", loaded)
+	}
+
+	info, err := os.Stat(store.path("admin@192.168.1.100:443"))
+	if err != nil {
+		t.Fatalf("state file missing: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("expected state file to be private, got %v", perm)
+	}
+}
+
+func TestFileStateStore_DistinctKeys(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore failed: %v", err)
+	}
+
+	// Keys that only differ in characters unsafe in file names
+	keys := []string{"admin@cam_1", "admin@cam:1", "admin@cam/1", "ad/min@cam", "ad_min@cam", "admin@cam%3A1"}
+	for i, key := range keys {
+		if err := store.Save(key, &HostState{Token: fmt.Sprintf("token-%d", i)}); err != nil {
+			t.Fatalf("Save(%q) failed: %v", key, err)
+		}
+	}
+	for i, key := range keys {
+		state, err := store.Load(key)
+		if err != nil {
+			t.Fatalf("Load(%q) failed: %v", key, err)
+		}
+		if want := fmt.Sprintf("token-%d", i); state.Token != want {
+			t.Errorf("Load(%q) token = %q, want %q", key, state.Token, want)
+		}
+		if dir := filepath.Dir(store.path(key)); dir != store.Dir() {
+			t.Errorf("state file of %q outside the store directory: %s", key, store.path(key))
+		}
+	}
+}
+
+func TestHostState_TokenValid(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		state HostState
+		want  bool
+	}{
+		{"no token", HostState{TokenExpiry: now.Add(time.Hour)}, false},
+		{"valid", HostState{Token: "t", TokenExpiry: now.Add(time.Hour)}, true},
+		{"expiring", HostState{Token: "t", TokenExpiry: now.Add(30 * time.Second)}, false},
+		{"expired", HostState{Token: "t", TokenExpiry: now.Add(-time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.TokenValid(now); got != tt.want {
+				t.Errorf("TokenValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithStateStore_ReusesSession(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"Login":  `{"Token": {"name": "session-1", "leaseTime": 3600}}`,
+		"Logout": "",
+	})
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore failed: %v", err)
+	}
+
+	newClient := func() *Client {
+		c := srv.client()
+		c.host = "camera"
+		c.username = "admin"
+		c.password = "password"
+		WithStateStore(store)(c)
+		return c
+	}
+
+	first := newClient()
+	if err := first.Login(t.Context()); err != nil {
+		t.Fatalf("first Login failed: %v", err)
+	}
+
+	second := newClient()
+	if err := second.Login(t.Context()); err != nil {
+		t.Fatalf("second Login failed: %v", err)
+	}
+	if n := srv.callCount("Login"); n != 1 {
+		t.Errorf("expected stored session to be reused, got %d logins", n)
+	}
+	if second.GetToken() != "session-1" {
+		t.Errorf("expected reused token session-1, got %q", second.GetToken())
+	}
+
+	// Another user of the same camera gets its own session
+	operator := newClient()
+	operator.username = "operator"
+	if err := operator.Login(t.Context()); err != nil {
+		t.Fatalf("operator Login failed: %v", err)
+	}
+	if n := srv.callCount("Login"); n != 2 {
+		t.Errorf("expected a separate login for another user, got %d logins", n)
+	}
+
+	if err := second.Logout(t.Context()); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if err := newClient().Login(t.Context()); err != nil {
+		t.Fatalf("third Login failed: %v", err)
+	}
+	if n := srv.callCount("Login"); n != 3 {
+		t.Errorf("expected a fresh login after Logout, got %d logins", n)
+	}
+}
+
+func TestWithStateStore_ForgetsRejectedToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"cmd": "GetDevInfo", "code": 1, "error": {"rspCode": -6, "detail": "please login first"}}]`))
+	}))
+	defer server.Close()
+
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore failed: %v", err)
+	}
+	if err := store.Save("admin@camera", &HostState{Token: "stale", TokenExpiry: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	client := newTestClient(server)
+	client.host = "camera"
+	client.username = "admin"
+	client.password = "password"
+	WithStateStore(store)(client)
+
+	if err := client.Login(t.Context()); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if _, err := client.System.GetDeviceInfo(t.Context()); err == nil {
+		t.Fatal("expected GetDeviceInfo to fail with login required")
+	}
+
+	state, err := store.Load("admin@camera")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if state.Token != "" {
+		t.Errorf("expected rejected token to be cleared, got %q", state.Token)
+	}
+}
+
+func TestWithStateStore_ReusesPortsAndAbility(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"Login":      `{"Token": {"name": "session-1", "leaseTime": 3600}}`,
+		"GetNetPort": `{"NetPort": {"httpEnable": 1, "httpPort": 8080, "httpsEnable": 1, "httpsPort": 443}}`,
+		"GetAbility": `{"Ability": {"supportBuzzer": {"permit": 0, "ver": 0}}}`,
+	})
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore failed: %v", err)
+	}
+
+	newClient := func() *Client {
+		c := srv.client()
+		c.host = "camera"
+		c.username = "admin"
+		c.password = "password"
+		WithStateStore(store)(c)
+		return c
+	}
+
+	first := newClient()
+	if err := first.Login(t.Context()); err != nil {
+		t.Fatalf("first Login failed: %v", err)
+	}
+	if _, err := first.Network.GetNetPort(t.Context()); err != nil {
+		t.Fatalf("GetNetPort failed: %v", err)
+	}
+	if _, err := first.System.GetAbility(t.Context()); err != nil {
+		t.Fatalf("GetAbility failed: %v", err)
+	}
+
+	second := newClient()
+	if err := second.Login(t.Context()); err != nil {
+		t.Fatalf("second Login failed: %v", err)
+	}
+	if port := second.httpPort.Load(); port != 8080 {
+		t.Errorf("expected stored HTTP port 8080, got %d", port)
+	}
+	if err := second.Alarm.TriggerBuzzer(t.Context(), 1); !IsNotSupported(err) {
+		t.Errorf("expected not supported from the stored abilities, got %v", err)
+	}
+	if n := srv.callCount("GetAbility"); n != 1 {
+		t.Errorf("expected stored abilities to be reused, got %d GetAbility calls", n)
+	}
+}
@@ -0,0 +1,271 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// StorageAPI forecasts recording retention from disk capacity, recording
+// configuration and encoder bitrates, and manages the overwrite policy
+type StorageAPI struct {
+	client  *Client
+	minDays atomic.Uint64 // float64 bits of the SetMinRetention threshold
+}
+
+// StorageForecast is the outcome of Storage.Forecast
+type StorageForecast struct {
+	Disks          int     // Mounted, formatted disks
+	CapacityMB     int     // Total capacity of those disks in MB
+	UsedMB         int     // Space in use in MB
+	Channels       []int   // Channels with recording enabled
+	BitRateKbps    int     // Combined main and sub stream bitrate of those channels
+	Overwrite      bool    // All recording channels overwrite the oldest footage when full
+	RetentionDays  float64 // Days of continuous footage the disks hold
+	DaysUntilFull  float64 // Days until the free space is used up
+	BelowThreshold bool    // RetentionDays is below the threshold set with SetMinRetention
+}
+
+// SetMinRetention sets the retention in days below which Forecast warns and
+// sets BelowThreshold. Zero disables the check.
+func (s *StorageAPI) SetMinRetention(days float64) {
+	s.minDays.Store(math.Float64bits(days))
+}
+
+// Forecast estimates how long recordings are kept on the given channels
+// (all online channels if none are given). Channels whose recording
+// schedule is disabled do not contribute to the bitrate. The estimate
+// assumes continuous recording at constant bitrate, so it is a lower bound
+// for motion-only recording. Results are also reported to the client's
+// MetricsRecorder.
+func (s *StorageAPI) Forecast(ctx context.Context, channels ...int) (*StorageForecast, error) {
+	if len(channels) == 0 {
+		var err error
+		if channels, err = s.client.onlineChannels(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	s.client.log(ctx).Debug("forecasting storage: channels=%v", channels)
+
+	disks, err := s.client.System.GetHddInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read disk info: %w", err)
+	}
+
+	forecast := &StorageForecast{Overwrite: true}
+	for _, d := range disks {
+		if d.Mount == 1 && d.Format == 1 {
+			forecast.Disks++
+			forecast.CapacityMB += d.Capacity
+			forecast.UsedMB += d.Size
+		}
+	}
+
+	for _, ch := range channels {
+		rec, err := s.client.Recording.GetRec(ctx, ch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read channel %d recording configuration: %w", ch, err)
+		}
+		if !rec.Schedule.Enable.Bool() {
+			continue
+		}
+
+		enc, err := s.client.Encoding.GetEnc(ctx, ch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read channel %d encoding: %w", ch, err)
+		}
+
+		forecast.Channels = append(forecast.Channels, ch)
+		forecast.BitRateKbps += enc.MainStream.BitRate + enc.SubStream.BitRate
+		forecast.Overwrite = forecast.Overwrite && rec.Overwrite == 1
+	}
+
+	forecast.RetentionDays = EstimateStorageDays(forecast.CapacityMB, forecast.BitRateKbps)
+	forecast.DaysUntilFull = EstimateStorageDays(max(forecast.CapacityMB-forecast.UsedMB, 0), forecast.BitRateKbps)
+	minDays := math.Float64frombits(s.minDays.Load())
+	forecast.BelowThreshold = minDays > 0 && len(forecast.Channels) > 0 && forecast.RetentionDays < minDays
+
+	if forecast.BelowThreshold {
+		s.client.log(ctx).Warn("storage retention below threshold: days=%.1f min=%.1f", forecast.RetentionDays, minDays)
+	}
+	if !forecast.Overwrite && len(forecast.Channels) > 0 {
+		s.client.log(ctx).Warn("recording stops when disks are full: days until full=%.1f", forecast.DaysUntilFull)
+	}
+
+	s.client.setGauge("reolink_storage_capacity_mb", float64(forecast.CapacityMB), nil)
+	s.client.setGauge("reolink_storage_used_mb", float64(forecast.UsedMB), nil)
+	s.client.setGauge("reolink_storage_bitrate_kbps", float64(forecast.BitRateKbps), nil)
+	s.client.setGauge("reolink_storage_retention_days", forecast.RetentionDays, nil)
+	s.client.setGauge("reolink_storage_days_until_full", forecast.DaysUntilFull, nil)
+
+	s.client.log(ctx).Info("successfully forecast storage: capacity=%dMB bitrate=%dkbps retention=%.1f days",
+		forecast.CapacityMB, forecast.BitRateKbps, forecast.RetentionDays)
+	return forecast, nil
+}
+
+// EstimateStorageDays returns how many days of continuous recording fit in
+// capacityMB at the combined bitrate of the given streams (in kbps). It
+// assumes constant bitrate, which makes it a lower bound for variable
+// bitrate or motion-only recording.
+func EstimateStorageDays(capacityMB int, bitRatesKbps ...int) float64 {
+	total := 0
+	for _, b := range bitRatesKbps {
+		total += b
+	}
+	if total <= 0 {
+		return 0
+	}
+	bits := float64(capacityMB) * 1024 * 1024 * 8
+	seconds := bits / (float64(total) * 1000)
+	return seconds / 86400
+}
+
+// SetOverwrite sets whether recording overwrites the oldest footage when the
+// disks are full on the given channels (all online channels if none are
+// given). Channels that already have the requested policy are left alone.
+func (s *StorageAPI) SetOverwrite(ctx context.Context, overwrite bool, channels ...int) error {
+	if len(channels) == 0 {
+		var err error
+		if channels, err = s.client.onlineChannels(ctx); err != nil {
+			return err
+		}
+	}
+
+	value := boolToInt(overwrite)
+	s.client.log(ctx).Info("setting recording overwrite: overwrite=%t channels=%v", overwrite, channels)
+
+	for _, ch := range channels {
+		rec, err := s.client.Recording.GetRec(ctx, ch)
+		if err != nil {
+			return fmt.Errorf("failed to read channel %d recording configuration: %w", ch, err)
+		}
+		if rec.Overwrite != value {
+			rec.Channel = ch
+			rec.Overwrite = value
+			if err := s.client.Recording.SetRec(ctx, *rec); err != nil {
+				return fmt.Errorf("failed to set channel %d overwrite: %w", ch, err)
+			}
+		}
+		s.client.setGauge("reolink_recording_overwrite", float64(value), map[string]string{"channel": strconv.Itoa(ch)})
+	}
+
+	s.client.log(ctx).Info("successfully set recording overwrite")
+	return nil
+}
+
+// DiskHealth is the health of a disk as derived from GetHddInfo
+type DiskHealth string
+
+// Disk health states, from best to worst
+const (
+	DiskHealthUnknown DiskHealth = "unknown" // Nothing reported, e.g. the disk is not mounted
+	DiskHealthy       DiskHealth = "healthy"
+	DiskWarning       DiskHealth = "warning" // SMART warning or running hot
+	DiskFailing       DiskHealth = "failing" // SMART failure or disk error
+)
+
+// DiskHotCelsius is the temperature from which a disk counts as DiskWarning;
+// drive vendors rate disks for 55–60°C
+const DiskHotCelsius = 55
+
+// severity orders health states for comparison
+func (h DiskHealth) severity() int {
+	switch h {
+	case DiskHealthy:
+		return 1
+	case DiskWarning:
+		return 2
+	case DiskFailing:
+		return 3
+	}
+	return 0
+}
+
+// Health derives the disk's health from its status, SMART status and
+// temperature
+func (d HddInfo) Health() DiskHealth {
+	smart := strings.ToLower(d.Smart)
+	status := strings.ToLower(d.Status)
+	switch {
+	case smart == "bad" || smart == "fail" || smart == "failed" || status == "error" || status == "abnormal":
+		return DiskFailing
+	case smart == "warning" || smart == "caution" || int(d.Temperature) >= DiskHotCelsius:
+		return DiskWarning
+	case smart == "ok" || smart == "good" || status == "ok" || (d.Mount == 1 && status == ""):
+		return DiskHealthy
+	}
+	return DiskHealthUnknown
+}
+
+// WatchDiskHealth polls GetHddInfo every interval, records every disk's
+// health (reolink_disk_health: 0 unknown, 1 healthy, 2 warning, 3 failing)
+// and temperature (reolink_disk_temperature_celsius, if reported) with a
+// "disk" label, and calls handler with EventDiskUnhealthy when a disk
+// degrades to DiskWarning or DiskFailing and EventDiskHealthy when it
+// recovers. An unhealthy first sample is reported too. Polling errors are
+// logged and retried on the next tick. It blocks until ctx is done and
+// returns ctx.Err().
+func (s *StorageAPI) WatchDiskHealth(ctx context.Context, interval time.Duration, handler EventHandler) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler must not be nil")
+	}
+
+	last := make(map[int]DiskHealth)
+	poll := func() {
+		disks, err := s.client.System.GetHddInfo(ctx)
+		if err != nil {
+			s.client.log(ctx).Warn("disk health poll failed: %v", err)
+			return
+		}
+		for i, d := range disks {
+			disk := i
+			if d.Number != 0 {
+				disk = d.Number
+			}
+			health := d.Health()
+			labels := map[string]string{"disk": strconv.Itoa(disk)}
+			s.client.setGauge("reolink_disk_health", float64(health.severity()), labels)
+			if d.Temperature != 0 {
+				s.client.setGauge("reolink_disk_temperature_celsius", float64(d.Temperature), labels)
+			}
+
+			previous, seen := last[disk]
+			last[disk] = health
+			if health == DiskHealthUnknown || health == previous {
+				continue
+			}
+			ev := Event{Host: s.client.Host(), Channel: disk, Time: time.Now(), Detail: string(health)}
+			switch {
+			case health.severity() > max(previous.severity(), DiskHealthy.severity()):
+				ev.Type = EventDiskUnhealthy
+				s.client.log(ctx).Warn("disk %d health %s: status=%s smart=%s temperature=%d", disk, health, d.Status, d.Smart, d.Temperature)
+			case seen && health == DiskHealthy && previous.severity() > DiskHealthy.severity():
+				ev.Type = EventDiskHealthy
+			default:
+				continue
+			}
+			handler(ev)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
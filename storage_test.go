@@ -0,0 +1,222 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func newStorageServer(t *testing.T) *cmdServer {
+	t.Helper()
+	return newCmdServer(t, map[string]string{
+		"GetHddInfo": `{"HddInfo": [{"capacity": 953674, "format": 1, "mount": 1, "size": 476837}]}`,
+		"GetRec":     `{"Rec": {"channel": 0, "overwrite": 0, "postRec": "30 Seconds", "preRec": 1, "schedule": {"enable": 1, "table": ""}}}`,
+		"GetEnc":     testEncValue,
+		"SetRec":     "",
+	})
+}
+
+func TestStorageAPI_Forecast(t *testing.T) {
+	server := newStorageServer(t)
+	metrics := NewMetrics()
+	client := server.client()
+	client.metrics = metrics
+	client.Storage.SetMinRetention(30)
+
+	forecast, err := client.Storage.Forecast(t.Context())
+	if err != nil {
+		t.Fatalf("Forecast failed: %v", err)
+	}
+	if forecast.Disks != 1 || forecast.CapacityMB != 953674 || forecast.UsedMB != 476837 {
+		t.Errorf("unexpected disk totals: %+v", forecast)
+	}
+	if len(forecast.Channels) != 1 || forecast.Channels[0] != 0 {
+		t.Errorf("expected channel 0 to record (camera without channel status), got %v", forecast.Channels)
+	}
+	if forecast.BitRateKbps != 4352 {
+		t.Errorf("expected main+sub bitrate 4352, got %d", forecast.BitRateKbps)
+	}
+	if forecast.Overwrite {
+		t.Error("expected overwrite to be reported as disabled")
+	}
+	if want := EstimateStorageDays(953674, 4352); forecast.RetentionDays != want {
+		t.Errorf("expected %.2f retention days, got %.2f", want, forecast.RetentionDays)
+	}
+	if forecast.DaysUntilFull >= forecast.RetentionDays || forecast.DaysUntilFull <= 0 {
+		t.Errorf("expected days until full below retention, got %.2f", forecast.DaysUntilFull)
+	}
+	if !forecast.BelowThreshold {
+		t.Error("expected retention below the 30 day threshold")
+	}
+
+	found := false
+	for _, m := range metrics.Snapshot() {
+		if m.Name == "reolink_storage_retention_days" {
+			found = true
+			if m.Value != forecast.RetentionDays {
+				t.Errorf("expected retention gauge %.2f, got %.2f", forecast.RetentionDays, m.Value)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected retention days gauge")
+	}
+}
+
+func TestStorageAPI_SetMinRetention_Concurrent(t *testing.T) {
+	server := newStorageServer(t)
+	client := server.client()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			client.Storage.SetMinRetention(float64(i))
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Storage.Forecast(t.Context(), 0); err != nil {
+			t.Fatalf("Forecast failed: %v", err)
+		}
+	}
+	<-done
+
+	client.Storage.SetMinRetention(1000)
+	if forecast, err := client.Storage.Forecast(t.Context(), 0); err != nil || !forecast.BelowThreshold {
+		t.Errorf("expected the last threshold to apply, got %+v, %v", forecast, err)
+	}
+}
+
+func TestStorageAPI_Forecast_RecordingDisabled(t *testing.T) {
+	server := newStorageServer(t)
+	server.set("GetRec", `{"Rec": {"channel": 0, "overwrite": 1, "schedule": {"enable": 0, "table": ""}}}`)
+	client := server.client()
+	client.Storage.SetMinRetention(30)
+
+	forecast, err := client.Storage.Forecast(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("Forecast failed: %v", err)
+	}
+	if len(forecast.Channels) != 0 || forecast.BitRateKbps != 0 || forecast.BelowThreshold {
+		t.Errorf("expected no recording channels, got %+v", forecast)
+	}
+	if server.callCount("GetEnc") != 0 {
+		t.Error("expected no GetEnc for channels that do not record")
+	}
+}
+
+func TestStorageAPI_SetOverwrite(t *testing.T) {
+	server := newStorageServer(t)
+	client := server.client()
+
+	if err := client.Storage.SetOverwrite(t.Context(), true, 0); err != nil {
+		t.Fatalf("SetOverwrite failed: %v", err)
+	}
+	var param RecValue
+	if err := json.Unmarshal(server.lastParam("SetRec"), &param); err != nil {
+		t.Fatalf("failed to parse SetRec param: %v", err)
+	}
+	if param.Rec.Overwrite != 1 || param.Rec.PostRec != "30 Seconds" {
+		t.Errorf("expected overwrite enabled with other settings kept, got %+v", param.Rec)
+	}
+
+	// Already enabled: no further SetRec
+	if err := client.Storage.SetOverwrite(t.Context(), true, 0); err != nil {
+		t.Fatalf("SetOverwrite failed: %v", err)
+	}
+	if n := server.callCount("SetRec"); n != 1 {
+		t.Errorf("expected 1 SetRec, got %d", n)
+	}
+}
+
+func TestHddInfo_Health(t *testing.T) {
+	tests := []struct {
+		disk HddInfo
+		want DiskHealth
+	}{
+		{HddInfo{Mount: 1, Status: "ok"}, DiskHealthy},
+		{HddInfo{Mount: 1}, DiskHealthy},
+		{HddInfo{Mount: 1, Smart: "OK", Temperature: 41}, DiskHealthy},
+		{HddInfo{Mount: 1, Status: "ok", Temperature: 58}, DiskWarning},
+		{HddInfo{Mount: 1, Smart: "warning"}, DiskWarning},
+		{HddInfo{Mount: 1, Status: "ok", Smart: "bad"}, DiskFailing},
+		{HddInfo{Status: "error"}, DiskFailing},
+		{HddInfo{}, DiskHealthUnknown},
+	}
+	for _, tt := range tests {
+		if got := tt.disk.Health(); got != tt.want {
+			t.Errorf("%+v: expected %s, got %s", tt.disk, tt.want, got)
+		}
+	}
+}
+
+func TestStorageAPI_WatchDiskHealth(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetHddInfo": `{"HddInfo": [{"number": 1, "mount": 1, "status": "ok", "smart": "ok", "temperature": "38"}]}`,
+	})
+	client := srv.client()
+	metrics := NewMetrics()
+	WithMetrics(metrics)(client)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan Event, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Storage.WatchDiskHealth(ctx, 10*time.Millisecond, func(ev Event) {
+			events <- ev
+		})
+	}()
+
+	waitEvent := func(want EventType, detail string) {
+		t.Helper()
+		select {
+		case ev := <-events:
+			if ev.Type != want || ev.Detail != detail || ev.Channel != 1 {
+				t.Errorf("unexpected event: %+v", ev)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	for srv.callCount("GetHddInfo") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.set("GetHddInfo", `{"HddInfo": [{"number": 1, "mount": 1, "status": "ok", "smart": "ok", "temperature": 61}]}`)
+	waitEvent(EventDiskUnhealthy, string(DiskWarning))
+	srv.set("GetHddInfo", `{"HddInfo": [{"number": 1, "mount": 1, "status": "ok", "smart": "bad", "temperature": 61}]}`)
+	waitEvent(EventDiskUnhealthy, string(DiskFailing))
+	srv.set("GetHddInfo", `{"HddInfo": [{"number": 1, "mount": 1, "status": "ok", "smart": "ok", "temperature": 40}]}`)
+	waitEvent(EventDiskHealthy, string(DiskHealthy))
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	gauges := make(map[string]float64)
+	for _, m := range metrics.Snapshot() {
+		if m.Labels["disk"] == "1" {
+			gauges[m.Name] = m.Value
+		}
+	}
+	if gauges["reolink_disk_health"] != 1 || gauges["reolink_disk_temperature_celsius"] != 40 {
+		t.Errorf("unexpected disk gauges: %v", gauges)
+	}
+}
+
+func TestEstimateStorageDays(t *testing.T) {
+	// 1 TiB at 8192 kbps ≈ 12.4 days
+	days := EstimateStorageDays(1024*1024, 4096, 4096)
+	if math.Abs(days-12.43) > 0.01 {
+		t.Errorf("expected about 12.43 days, got %.2f", days)
+	}
+	if EstimateStorageDays(1024, 0) != 0 {
+		t.Error("expected 0 days for zero bitrate")
+	}
+}
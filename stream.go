@@ -0,0 +1,139 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// streamResponse is a response envelope whose value is decoded straight
+// into the struct Value points to, without an intermediate json.RawMessage
+type streamResponse struct {
+	Cmd   string       `json:"cmd"`
+	Code  FlexInt      `json:"code"`
+	Error *ErrorDetail `json:"error,omitempty"`
+	Value interface{}  `json:"value"` // Pointer to the destination struct
+}
+
+// ToAPIError converts an error response to an APIError
+func (r *streamResponse) ToAPIError() *APIError {
+	if r.Error != nil {
+		return NewAPIError(r.Cmd, int(r.Code), r.Error.RspCode, r.Error.Detail)
+	}
+	if r.Code != 0 {
+		return NewAPIError(r.Cmd, int(r.Code), int(r.Code), "")
+	}
+	return nil
+}
+
+// doStream executes a single-command request for commands with large
+// responses, such as GetAbility and Search. The response is decoded from
+// the connection as it arrives, directly into resp.Value, so neither the
+// whole body nor a json.RawMessage copy of the value is held next to the
+// decoded result. Requests that need the raw body (a custom Codec, caching,
+// WithRawMeta or the decode mode, see streamDecodable) and the legacy API
+// take the regular buffered path instead.
+//
+// In lenient mode, a streamed value with mismatched types is requested
+// again on the buffered path, which converts them, and values of its type
+// are no longer streamed.
+func (c *Client) doStream(ctx context.Context, req Request, resp *streamResponse) error {
+	if err := c.checkPolicy(ctx, req.Cmd); err != nil {
+		return err
+	}
+	req = c.quirkRequests([]Request{req})[0]
+	_, capture := ctx.Value(rawMetaKey{}).(*RawMeta)
+	if key, _ := c.cacheLookup([]Request{req}); c.codec != nil || key != "" || capture || c.legacy.Load() || !c.streamDecodable(resp.Value) {
+		return c.doBuffered(ctx, req, resp)
+	}
+
+	err := withCmdLabels(ctx, req.Cmd, func(ctx context.Context) error {
+		if c.sleep != nil {
+			return c.doSleepAware(ctx, func(ctx context.Context) error {
+				return c.streamRequest(ctx, req, resp)
+			})
+		}
+		return c.streamRequest(ctx, req, resp)
+	})
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		c.decodeMismatched.Store(reflect.TypeOf(resp.Value), struct{}{})
+		c.log(ctx).Debug("streamed %s response has mismatched types, requesting it again: %v", req.Cmd, err)
+		reflect.ValueOf(resp.Value).Elem().SetZero()
+		return c.doBuffered(ctx, req, resp)
+	}
+	return err
+}
+
+// streamRequest sends req and decodes the response as it arrives; see
+// doStream
+func (c *Client) streamRequest(ctx context.Context, req Request, resp *streamResponse) error {
+	c.tokenMu.RLock()
+	token := c.token
+	c.tokenMu.RUnlock()
+	req.Token = token
+
+	body, err := c.marshal([]Request{req})
+	if err != nil {
+		c.log(ctx).Error("failed to marshal request: %v", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	c.log(ctx).Debug("API request (streamed): cmd=%s", req.Cmd)
+	httpResp, err := c.send(ctx, req.Cmd, token, body)
+	if err != nil {
+		return err
+	}
+	respBody := c.closeOnCancel(ctx, httpResp.Body)
+	defer respBody.Close()
+
+	dec := json.NewDecoder(respBody)
+	if tok, err := dec.Token(); err != nil {
+		c.log(ctx).Error("failed to unmarshal response: %v", err)
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	} else if tok != json.Delim('[') {
+		return fmt.Errorf("failed to unmarshal response: expected array, got %v", tok)
+	}
+	if !dec.More() {
+		return fmt.Errorf("empty response")
+	}
+	if err := dec.Decode(resp); err != nil {
+		c.log(ctx).Error("failed to unmarshal response: %v", err)
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	// A rejected token must not be reused by the next run
+	if apiErr := resp.ToAPIError(); c.state != nil && token != "" && apiErr != nil &&
+		(apiErr.RspCode == ErrCodeLoginRequired || apiErr.RspCode == ErrCodeTokenError) {
+		c.updateState(ctx, func(s *HostState) {
+			if s.Token == token {
+				s.Token = ""
+				s.TokenExpiry = time.Time{}
+			}
+		})
+	}
+	return nil
+}
+
+// doBuffered is the doStream fallback through do
+func (c *Client) doBuffered(ctx context.Context, req Request, resp *streamResponse) error {
+	var resps []Response
+	if err := c.do(ctx, []Request{req}, &resps); err != nil {
+		return err
+	}
+	if len(resps) == 0 {
+		return fmt.Errorf("empty response")
+	}
+	resp.Cmd, resp.Code, resp.Error = resps[0].Cmd, FlexInt(resps[0].Code), resps[0].Error
+	if resp.Error != nil || resp.Code != 0 {
+		return nil
+	}
+	if err := c.unmarshal(resps[0].Value, resp.Value); err != nil {
+		c.log(ctx).Error("failed to unmarshal response: %v", err)
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package reolink
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSearchValue builds a Search response value with n results
+func testSearchValue(n int) string {
+	var b strings.Builder
+	b.WriteString(`{"SearchResult": [`)
+	for i := range n {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"channel": 0, "fileName": "Mp4Record/2024-01-15/RecM01_%06d.mp4", "fileSize": 104857600,
+			"startTime": "2024-01-15T10:00:00Z", "endTime": "2024-01-15T10:05:00Z", "type": "MD"}`, i)
+	}
+	b.WriteString(`]}`)
+	return b.String()
+}
+
+func TestRecordingAPI_Search_Streamed(t *testing.T) {
+	server := newCmdServer(t, map[string]string{"Search": testSearchValue(500)})
+	client := server.client()
+
+	results, err := client.Recording.Search(t.Context(), 0, time.Now().Add(-time.Hour), time.Now(), "main")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 500 || results[499].FileName != "Mp4Record/2024-01-15/RecM01_000499.mp4" {
+		t.Errorf("unexpected results: %d", len(results))
+	}
+
+	// Buffered fallback gives the same result
+	var meta RawMeta
+	results, err = client.Recording.Search(WithRawMeta(t.Context(), &meta), 0, time.Now().Add(-time.Hour), time.Now(), "main")
+	if err != nil || len(results) != 500 || meta.Value() == nil {
+		t.Errorf("unexpected buffered result: %d results, err %v", len(results), err)
+	}
+}
+
+func TestSystemAPI_GetAbility_Streamed(t *testing.T) {
+	server := newCmdServer(t, nil)
+	client := server.client()
+
+	if _, err := client.System.GetAbility(t.Context()); err == nil {
+		t.Error("expected API error for an unsupported command")
+	} else if _, ok := err.(*APIError); !ok {
+		t.Errorf("expected *APIError, got %T: %v", err, err)
+	}
+
+	server.set("GetAbility", `{"Ability": {"Ability": {"abilityChn": [{"ptzCtrl": {"permit": 6, "ver": 1}}]}}}`)
+	ability, err := client.System.GetAbility(t.Context())
+	if err != nil {
+		t.Fatalf("GetAbility failed: %v", err)
+	}
+	if _, ok := ability.AbilityInfo["abilityChn"]; !ok {
+		t.Errorf("unexpected ability: %v", ability.AbilityInfo)
+	}
+
+	// Cached commands take the buffered path
+	WithCache(NewMemoryCache(), map[string]time.Duration{"GetAbility": time.Minute})(client)
+	client.System.GetAbility(t.Context())
+	if _, err := client.System.GetAbility(t.Context()); err != nil {
+		t.Fatalf("cached GetAbility failed: %v", err)
+	}
+	if n := server.callCount("GetAbility"); n != 3 {
+		t.Errorf("expected the second cached call to be served from cache, got %d requests", n)
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	server := newUnstartedCmdServer(map[string]string{"Search": testSearchValue(2000)})
+	server.Start()
+	defer server.Close()
+	client := newTestClient(server.Server)
+	start, end := time.Now().Add(-24*time.Hour), time.Now()
+
+	b.Run("Streamed", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := client.Recording.Search(b.Context(), 0, start, end, "main"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Buffered", func(b *testing.B) {
+		var meta RawMeta
+		ctx := WithRawMeta(b.Context(), &meta)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := client.Recording.Search(ctx, 0, start, end, "main"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -2,8 +2,8 @@ package reolink
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"sort"
 )
 
 // SystemAPI provides access to system-related API endpoints
@@ -27,7 +27,7 @@ type SystemAPI struct {
 //
 // Returns an error if the request fails or if the camera returns an error code.
 func (s *SystemAPI) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
-	s.client.logger.Debug("getting device info")
+	s.client.log(ctx).Debug("getting device info")
 
 	req := []Request{{
 		Cmd:    "GetDevInfo",
@@ -36,34 +36,36 @@ func (s *SystemAPI) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get device info: %v", err)
+		s.client.log(ctx).Error("failed to get device info: %v", err)
 		return nil, fmt.Errorf("GetDevInfo request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get device info: %v", err)
+		s.client.log(ctx).Error("failed to get device info: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get device info: %v", apiErr)
+		s.client.log(ctx).Error("failed to get device info: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value DeviceInfoValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse device info response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse device info response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	s.client.logger.Info("successfully retrieved device info: model=%s firmware=%s", value.DevInfo.Model, value.DevInfo.FirmVer)
+	s.client.applyQuirks(ctx, &value.DevInfo)
+
+	s.client.log(ctx).Info("successfully retrieved device info: model=%s firmware=%s", value.DevInfo.Model, value.DevInfo.FirmVer)
 	return &value.DevInfo, nil
 }
 
 // GetDeviceName retrieves the device name
 func (s *SystemAPI) GetDeviceName(ctx context.Context) (string, error) {
-	s.client.logger.Debug("getting device name")
+	s.client.log(ctx).Debug("getting device name")
 
 	req := []Request{{
 		Cmd:    "GetDevName",
@@ -72,33 +74,41 @@ func (s *SystemAPI) GetDeviceName(ctx context.Context) (string, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get device name: %v", err)
+		s.client.log(ctx).Error("failed to get device name: %v", err)
 		return "", fmt.Errorf("GetDevName request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get device name: %v", err)
+		s.client.log(ctx).Error("failed to get device name: %v", err)
 		return "", err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get device name: %v", apiErr)
+		s.client.log(ctx).Error("failed to get device name: %v", apiErr)
 		return "", apiErr
 	}
 
 	var value DeviceNameValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse device name response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse device name response: %v", err)
 		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	return value.DevName.Name, nil
 }
 
-// SetDeviceName sets the device name
+// SetDeviceName sets the device name. The name is normalized with
+// NormalizeDeviceName and checked with ValidateDeviceName, ASCII-only on
+// firmware with the ASCIINames quirk; with WithNameTransliteration, names
+// the device cannot store are transliterated instead of rejected.
 func (s *SystemAPI) SetDeviceName(ctx context.Context, name string) error {
-	s.client.logger.Info("setting device name to: %s", name)
+	name, err := s.client.prepareDeviceName(name)
+	if err != nil {
+		return err
+	}
+
+	s.client.log(ctx).Info("setting device name to: %s", name)
 
 	req := []Request{{
 		Cmd: "SetDevName",
@@ -111,28 +121,29 @@ func (s *SystemAPI) SetDeviceName(ctx context.Context, name string) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to set device name: %v", err)
+		s.client.log(ctx).Error("failed to set device name: %v", err)
 		return fmt.Errorf("SetDevName request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to set device name: %v", err)
+		s.client.log(ctx).Error("failed to set device name: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to set device name: %v", apiErr)
+		s.client.log(ctx).Error("failed to set device name: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully set device name")
+	s.client.log(ctx).Info("successfully set device name")
 	return nil
 }
 
-// GetTime retrieves the current time configuration
+// GetTime retrieves the current time configuration, with the DST rule in
+// TimeConfig.Dst
 func (s *SystemAPI) GetTime(ctx context.Context) (*TimeConfig, error) {
-	s.client.logger.Debug("getting time configuration")
+	s.client.log(ctx).Debug("getting time configuration")
 
 	req := []Request{{
 		Cmd:    "GetTime",
@@ -141,33 +152,34 @@ func (s *SystemAPI) GetTime(ctx context.Context) (*TimeConfig, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get time configuration: %v", err)
+		s.client.log(ctx).Error("failed to get time configuration: %v", err)
 		return nil, fmt.Errorf("GetTime request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get time configuration: %v", err)
+		s.client.log(ctx).Error("failed to get time configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get time configuration: %v", apiErr)
+		s.client.log(ctx).Error("failed to get time configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value TimeValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse time configuration response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse time configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	value.Time.Dst = value.Dst
 
 	return &value.Time, nil
 }
 
 // SetTime sets the time configuration
 func (s *SystemAPI) SetTime(ctx context.Context, timeConfig *TimeConfig) error {
-	s.client.logger.Info("setting time configuration")
+	s.client.log(ctx).Info("setting time configuration")
 
 	req := []Request{{
 		Cmd: "SetTime",
@@ -178,28 +190,28 @@ func (s *SystemAPI) SetTime(ctx context.Context, timeConfig *TimeConfig) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to set time configuration: %v", err)
+		s.client.log(ctx).Error("failed to set time configuration: %v", err)
 		return fmt.Errorf("SetTime request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to set time configuration: %v", err)
+		s.client.log(ctx).Error("failed to set time configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to set time configuration: %v", apiErr)
+		s.client.log(ctx).Error("failed to set time configuration: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully set time configuration")
+	s.client.log(ctx).Info("successfully set time configuration")
 	return nil
 }
 
 // GetHddInfo retrieves hard disk information
 func (s *SystemAPI) GetHddInfo(ctx context.Context) ([]HddInfo, error) {
-	s.client.logger.Debug("getting HDD info")
+	s.client.log(ctx).Debug("getting HDD info")
 
 	req := []Request{{
 		Cmd:    "GetHddInfo",
@@ -208,36 +220,36 @@ func (s *SystemAPI) GetHddInfo(ctx context.Context) ([]HddInfo, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get HDD info: %v", err)
+		s.client.log(ctx).Error("failed to get HDD info: %v", err)
 		return nil, fmt.Errorf("GetHddInfo request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get HDD info: %v", err)
+		s.client.log(ctx).Error("failed to get HDD info: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get HDD info: %v", apiErr)
+		s.client.log(ctx).Error("failed to get HDD info: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value HddInfoValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse HDD info response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse HDD info response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if len(value.HddInfo) > 0 {
-		s.client.logger.Info("successfully retrieved HDD info: count=%d", len(value.HddInfo))
+		s.client.log(ctx).Info("successfully retrieved HDD info: count=%d", len(value.HddInfo))
 	}
 	return value.HddInfo, nil
 }
 
 // Format formats a storage device
 func (s *SystemAPI) Format(ctx context.Context, hddID int) error {
-	s.client.logger.Warn("formatting disk (destructive operation): hdd_id=%d", hddID)
+	s.client.log(ctx).Warn("formatting disk (destructive operation): hdd_id=%d", hddID)
 
 	req := []Request{{
 		Cmd: "Format",
@@ -252,28 +264,128 @@ func (s *SystemAPI) Format(ctx context.Context, hddID int) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to format disk: %v", err)
+		s.client.log(ctx).Error("failed to format disk: %v", err)
 		return fmt.Errorf("Format request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to format disk: %v", err)
+		s.client.log(ctx).Error("failed to format disk: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		s.client.log(ctx).Error("failed to format disk: %v", apiErr)
+		return apiErr
+	}
+
+	s.client.log(ctx).Info("successfully formatted disk")
+	return nil
+}
+
+// SdCardLock is the SD card lock of models that can encrypt recordings
+// with a password, so a stolen card cannot be read elsewhere. The commands
+// are not part of the public API guide; other models reject them as not
+// supported.
+type SdCardLock struct {
+	Enable BoolInt `json:"enable"` // 0=unlocked, 1=locked
+	// Password locks the card; it is only sent, cameras never return it
+	Password string `json:"password,omitempty"`
+}
+
+// SdCardLockValue represents the response value for GetSdCardLock
+type SdCardLockValue struct {
+	SdCardLock SdCardLock `json:"SdCardLock"`
+}
+
+// Validate checks that a lock being enabled has a valid password
+func (l *SdCardLock) Validate() error {
+	if !l.Enable.Bool() {
+		return nil
+	}
+	if l.Password == "" {
+		return fmt.Errorf("password is required to lock the SD card")
+	}
+	return ValidatePassword(l.Password)
+}
+
+// GetSdCardLock gets the SD card lock configuration
+func (s *SystemAPI) GetSdCardLock(ctx context.Context) (*SdCardLock, error) {
+	s.client.log(ctx).Debug("getting SD card lock")
+
+	req := []Request{{
+		Cmd:    "GetSdCardLock",
+		Action: 0,
+	}}
+
+	var resp []Response
+	if err := s.client.do(ctx, req, &resp); err != nil {
+		s.client.log(ctx).Error("failed to get SD card lock: %v", err)
+		return nil, fmt.Errorf("GetSdCardLock request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetSdCardLock")
+		s.client.log(ctx).Error("failed to get SD card lock: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		s.client.log(ctx).Error("failed to get SD card lock: %v", apiErr)
+		return nil, apiErr
+	}
+
+	var value SdCardLockValue
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse SD card lock response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetSdCardLock response: %w", err)
+	}
+
+	s.client.log(ctx).Info("successfully retrieved SD card lock: enable=%d", value.SdCardLock.Enable)
+	return &value.SdCardLock, nil
+}
+
+// SetSdCardLock locks or unlocks the SD card. Locking with a new password
+// makes recordings written under the old one unreadable on other devices;
+// unlocking may require the current password on some firmware.
+func (s *SystemAPI) SetSdCardLock(ctx context.Context, lock SdCardLock) error {
+	if err := lock.Validate(); err != nil {
+		return err
+	}
+
+	s.client.log(ctx).Info("setting SD card lock: enable=%d", lock.Enable)
+
+	req := []Request{{
+		Cmd: "SetSdCardLock",
+		Param: SdCardLockValue{
+			SdCardLock: lock,
+		},
+	}}
+
+	var resp []Response
+	if err := s.client.do(ctx, req, &resp); err != nil {
+		s.client.log(ctx).Error("failed to set SD card lock: %v", err)
+		return fmt.Errorf("SetSdCardLock request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetSdCardLock")
+		s.client.log(ctx).Error("failed to set SD card lock: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to format disk: %v", apiErr)
+		s.client.log(ctx).Error("failed to set SD card lock: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully formatted disk")
+	s.client.log(ctx).Info("successfully set SD card lock")
 	return nil
 }
 
 // Reboot reboots the device
 func (s *SystemAPI) Reboot(ctx context.Context) error {
-	s.client.logger.Warn("rebooting device (system restart)")
+	s.client.log(ctx).Warn("rebooting device (system restart)")
 
 	req := []Request{{
 		Cmd: "Reboot",
@@ -281,28 +393,28 @@ func (s *SystemAPI) Reboot(ctx context.Context) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to reboot device: %v", err)
+		s.client.log(ctx).Error("failed to reboot device: %v", err)
 		return fmt.Errorf("Reboot request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to reboot device: %v", err)
+		s.client.log(ctx).Error("failed to reboot device: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to reboot device: %v", apiErr)
+		s.client.log(ctx).Error("failed to reboot device: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully initiated device reboot")
+	s.client.log(ctx).Info("successfully initiated device reboot")
 	return nil
 }
 
 // Restore restores factory default settings
 func (s *SystemAPI) Restore(ctx context.Context) error {
-	s.client.logger.Warn("restoring factory defaults (destructive operation)")
+	s.client.log(ctx).Warn("restoring factory defaults (destructive operation)")
 
 	req := []Request{{
 		Cmd: "Restore",
@@ -310,64 +422,71 @@ func (s *SystemAPI) Restore(ctx context.Context) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to restore factory defaults: %v", err)
+		s.client.log(ctx).Error("failed to restore factory defaults: %v", err)
 		return fmt.Errorf("Restore request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to restore factory defaults: %v", err)
+		s.client.log(ctx).Error("failed to restore factory defaults: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to restore factory defaults: %v", apiErr)
+		s.client.log(ctx).Error("failed to restore factory defaults: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully initiated factory restore")
+	s.client.log(ctx).Info("successfully initiated factory restore")
 	return nil
 }
 
 // GetAbility retrieves system capabilities
 func (s *SystemAPI) GetAbility(ctx context.Context) (*Ability, error) {
-	s.client.logger.Debug("getting system capabilities")
+	s.client.log(ctx).Debug("getting system capabilities")
 
-	req := []Request{{
+	req := Request{
 		Cmd:    "GetAbility",
 		Action: 0,
-	}}
-
-	var resp []Response
-	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get system capabilities: %v", err)
-		return nil, fmt.Errorf("GetAbility request failed: %w", err)
 	}
 
-	if len(resp) == 0 {
-		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get system capabilities: %v", err)
-		return nil, err
+	// Ability sets run to hundreds of KB; decode them as they arrive
+	var value AbilityValue
+	resp := streamResponse{Value: &value}
+	if err := s.client.doStream(ctx, req, &resp); err != nil {
+		s.client.log(ctx).Error("failed to get system capabilities: %v", err)
+		return nil, fmt.Errorf("GetAbility request failed: %w", err)
 	}
 
-	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get system capabilities: %v", apiErr)
+	if apiErr := resp.ToAPIError(); apiErr != nil {
+		s.client.log(ctx).Error("failed to get system capabilities: %v", apiErr)
 		return nil, apiErr
 	}
 
-	var value AbilityValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse system capabilities response: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if s.client.state != nil {
+		s.client.ability.Store(&value.Ability)
+		s.client.updateState(ctx, func(state *HostState) {
+			state.Ability = &value.Ability
+		})
 	}
 
-	s.client.logger.Info("successfully retrieved system capabilities")
+	s.client.log(ctx).Info("successfully retrieved system capabilities")
 	return &value.Ability, nil
 }
 
+// abilities returns the ability snapshot kept with a state store, from an
+// earlier GetAbility or a previous run, and queries the camera if there is
+// none. Without a state store every call queries the camera.
+func (c *Client) abilities(ctx context.Context) (*Ability, error) {
+	if ability := c.ability.Load(); ability != nil {
+		return ability, nil
+	}
+	return c.System.GetAbility(ctx)
+}
+
 // GetAutoMaint gets automatic maintenance configuration
 func (s *SystemAPI) GetAutoMaint(ctx context.Context) (*AutoMaint, error) {
-	s.client.logger.Debug("getting automatic maintenance configuration")
+	s.client.log(ctx).Debug("getting automatic maintenance configuration")
 
 	req := []Request{{
 		Cmd:    "GetAutoMaint",
@@ -376,24 +495,24 @@ func (s *SystemAPI) GetAutoMaint(ctx context.Context) (*AutoMaint, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get automatic maintenance configuration: %v", err)
+		s.client.log(ctx).Error("failed to get automatic maintenance configuration: %v", err)
 		return nil, fmt.Errorf("GetAutoMaint request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get automatic maintenance configuration: %v", err)
+		s.client.log(ctx).Error("failed to get automatic maintenance configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get automatic maintenance configuration: %v", apiErr)
+		s.client.log(ctx).Error("failed to get automatic maintenance configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value AutoMaintValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse automatic maintenance configuration response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse automatic maintenance configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -402,7 +521,7 @@ func (s *SystemAPI) GetAutoMaint(ctx context.Context) (*AutoMaint, error) {
 
 // SetAutoMaint sets automatic maintenance configuration
 func (s *SystemAPI) SetAutoMaint(ctx context.Context, config AutoMaint) error {
-	s.client.logger.Info("setting automatic maintenance configuration")
+	s.client.log(ctx).Info("setting automatic maintenance configuration")
 
 	req := []Request{{
 		Cmd: "SetAutoMaint",
@@ -413,28 +532,28 @@ func (s *SystemAPI) SetAutoMaint(ctx context.Context, config AutoMaint) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to set automatic maintenance configuration: %v", err)
+		s.client.log(ctx).Error("failed to set automatic maintenance configuration: %v", err)
 		return fmt.Errorf("SetAutoMaint request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to set automatic maintenance configuration: %v", err)
+		s.client.log(ctx).Error("failed to set automatic maintenance configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to set automatic maintenance configuration: %v", apiErr)
+		s.client.log(ctx).Error("failed to set automatic maintenance configuration: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully set automatic maintenance configuration")
+	s.client.log(ctx).Info("successfully set automatic maintenance configuration")
 	return nil
 }
 
 // GetChannelStatus gets status of all channels (for NVR)
 func (s *SystemAPI) GetChannelStatus(ctx context.Context) (*ChannelStatusValue, error) {
-	s.client.logger.Debug("getting channel status")
+	s.client.log(ctx).Debug("getting channel status")
 
 	req := []Request{{
 		Cmd: "Getchannelstatus",
@@ -442,33 +561,54 @@ func (s *SystemAPI) GetChannelStatus(ctx context.Context) (*ChannelStatusValue,
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get channel status: %v", err)
+		s.client.log(ctx).Error("failed to get channel status: %v", err)
 		return nil, fmt.Errorf("GetChannelStatus request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get channel status: %v", err)
+		s.client.log(ctx).Error("failed to get channel status: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get channel status: %v", apiErr)
+		s.client.log(ctx).Error("failed to get channel status: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value ChannelStatusValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse channel status response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse channel status response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	normalizeChannelStatus(&value)
+	if value.Count != len(value.Status) {
+		s.client.log(ctx).Warn("channel status count mismatch: count=%d entries=%d", value.Count, len(value.Status))
+	}
+
 	return &value, nil
 }
 
+// normalizeChannelStatus sorts channel entries by channel number and drops
+// duplicates, which some firmware emits when the list spans several pages
+func normalizeChannelStatus(value *ChannelStatusValue) {
+	seen := make(map[int]bool, len(value.Status))
+	status := make([]ChannelStatus, 0, len(value.Status))
+	for _, s := range value.Status {
+		if seen[s.Channel] {
+			continue
+		}
+		seen[s.Channel] = true
+		status = append(status, s)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Channel < status[j].Channel })
+	value.Status = status
+}
+
 // AutoUpgrade represents automatic upgrade configuration
 type AutoUpgrade struct {
-	Enable int `json:"enable"` // 0=disabled, 1=enabled
+	Enable BoolInt `json:"enable"` // 0=disabled, 1=enabled
 }
 
 // AutoUpgradeValue wraps AutoUpgrade for API response
@@ -494,7 +634,7 @@ type UpgradeStatusValue struct {
 
 // GetAutoUpgrade gets automatic upgrade configuration
 func (s *SystemAPI) GetAutoUpgrade(ctx context.Context) (*AutoUpgrade, error) {
-	s.client.logger.Debug("getting automatic upgrade configuration")
+	s.client.log(ctx).Debug("getting automatic upgrade configuration")
 
 	req := []Request{{
 		Cmd: "GetAutoUpgrade",
@@ -502,24 +642,24 @@ func (s *SystemAPI) GetAutoUpgrade(ctx context.Context) (*AutoUpgrade, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get automatic upgrade configuration: %v", err)
+		s.client.log(ctx).Error("failed to get automatic upgrade configuration: %v", err)
 		return nil, fmt.Errorf("GetAutoUpgrade request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get automatic upgrade configuration: %v", err)
+		s.client.log(ctx).Error("failed to get automatic upgrade configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get automatic upgrade configuration: %v", apiErr)
+		s.client.log(ctx).Error("failed to get automatic upgrade configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value AutoUpgradeValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse automatic upgrade configuration response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse automatic upgrade configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -528,7 +668,7 @@ func (s *SystemAPI) GetAutoUpgrade(ctx context.Context) (*AutoUpgrade, error) {
 
 // SetAutoUpgrade sets automatic upgrade configuration
 func (s *SystemAPI) SetAutoUpgrade(ctx context.Context, enable bool) error {
-	s.client.logger.Info("setting automatic upgrade: enabled=%v", enable)
+	s.client.log(ctx).Info("setting automatic upgrade: enabled=%v", enable)
 
 	enableInt := 0
 	if enable {
@@ -546,28 +686,28 @@ func (s *SystemAPI) SetAutoUpgrade(ctx context.Context, enable bool) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to set automatic upgrade: %v", err)
+		s.client.log(ctx).Error("failed to set automatic upgrade: %v", err)
 		return fmt.Errorf("SetAutoUpgrade request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to set automatic upgrade: %v", err)
+		s.client.log(ctx).Error("failed to set automatic upgrade: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to set automatic upgrade: %v", apiErr)
+		s.client.log(ctx).Error("failed to set automatic upgrade: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully set automatic upgrade configuration")
+	s.client.log(ctx).Info("successfully set automatic upgrade configuration")
 	return nil
 }
 
 // CheckFirmware checks for new firmware online
 func (s *SystemAPI) CheckFirmware(ctx context.Context) (*FirmwareCheck, error) {
-	s.client.logger.Info("checking for firmware updates")
+	s.client.log(ctx).Info("checking for firmware updates")
 
 	req := []Request{{
 		Cmd: "CheckFirmware",
@@ -575,38 +715,38 @@ func (s *SystemAPI) CheckFirmware(ctx context.Context) (*FirmwareCheck, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to check firmware: %v", err)
+		s.client.log(ctx).Error("failed to check firmware: %v", err)
 		return nil, fmt.Errorf("CheckFirmware request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to check firmware: %v", err)
+		s.client.log(ctx).Error("failed to check firmware: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to check firmware: %v", apiErr)
+		s.client.log(ctx).Error("failed to check firmware: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value FirmwareCheck
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse firmware check response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse firmware check response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if value.NewFirmware == 1 {
-		s.client.logger.Info("firmware check complete: new firmware available")
+		s.client.log(ctx).Info("firmware check complete: new firmware available")
 	} else {
-		s.client.logger.Info("firmware check complete: no new firmware available")
+		s.client.log(ctx).Info("firmware check complete: no new firmware available")
 	}
 	return &value, nil
 }
 
 // UpgradeOnline starts online firmware upgrade
 func (s *SystemAPI) UpgradeOnline(ctx context.Context) error {
-	s.client.logger.Warn("starting online firmware upgrade (system change)")
+	s.client.log(ctx).Warn("starting online firmware upgrade (system change)")
 
 	req := []Request{{
 		Cmd: "UpgradeOnline",
@@ -614,28 +754,28 @@ func (s *SystemAPI) UpgradeOnline(ctx context.Context) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to start online firmware upgrade: %v", err)
+		s.client.log(ctx).Error("failed to start online firmware upgrade: %v", err)
 		return fmt.Errorf("UpgradeOnline request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to start online firmware upgrade: %v", err)
+		s.client.log(ctx).Error("failed to start online firmware upgrade: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to start online firmware upgrade: %v", apiErr)
+		s.client.log(ctx).Error("failed to start online firmware upgrade: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully started online firmware upgrade")
+	s.client.log(ctx).Info("successfully started online firmware upgrade")
 	return nil
 }
 
 // UpgradeStatus gets current firmware upgrade status
 func (s *SystemAPI) UpgradeStatus(ctx context.Context) (*UpgradeStatusInfo, error) {
-	s.client.logger.Debug("getting firmware upgrade status")
+	s.client.log(ctx).Debug("getting firmware upgrade status")
 
 	req := []Request{{
 		Cmd: "UpgradeStatus",
@@ -643,24 +783,24 @@ func (s *SystemAPI) UpgradeStatus(ctx context.Context) (*UpgradeStatusInfo, erro
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get firmware upgrade status: %v", err)
+		s.client.log(ctx).Error("failed to get firmware upgrade status: %v", err)
 		return nil, fmt.Errorf("UpgradeStatus request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get firmware upgrade status: %v", err)
+		s.client.log(ctx).Error("failed to get firmware upgrade status: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get firmware upgrade status: %v", apiErr)
+		s.client.log(ctx).Error("failed to get firmware upgrade status: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value UpgradeStatusValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse firmware upgrade status response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse firmware upgrade status response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -671,7 +811,7 @@ func (s *SystemAPI) UpgradeStatus(ctx context.Context) (*UpgradeStatusInfo, erro
 // restoreCfg: whether to restore configuration (false=keep config, true=reset to defaults)
 // fileName: firmware file name (e.g., "firmware.pak")
 func (s *SystemAPI) UpgradePrepare(ctx context.Context, restoreCfg bool, fileName string) error {
-	s.client.logger.Info("preparing firmware upgrade: file=%s restore_cfg=%v", fileName, restoreCfg)
+	s.client.log(ctx).Info("preparing firmware upgrade: file=%s restore_cfg=%v", fileName, restoreCfg)
 
 	restoreCfgInt := 0
 	if restoreCfg {
@@ -689,22 +829,22 @@ func (s *SystemAPI) UpgradePrepare(ctx context.Context, restoreCfg bool, fileNam
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to prepare firmware upgrade: %v", err)
+		s.client.log(ctx).Error("failed to prepare firmware upgrade: %v", err)
 		return fmt.Errorf("UpgradePrepare request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to prepare firmware upgrade: %v", err)
+		s.client.log(ctx).Error("failed to prepare firmware upgrade: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to prepare firmware upgrade: %v", apiErr)
+		s.client.log(ctx).Error("failed to prepare firmware upgrade: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully prepared firmware upgrade")
+	s.client.log(ctx).Info("successfully prepared firmware upgrade")
 	return nil
 }
 
@@ -722,7 +862,7 @@ type SysCfgValue struct {
 
 // GetSysCfg gets system configuration (login lock settings)
 func (s *SystemAPI) GetSysCfg(ctx context.Context) (*SysCfg, error) {
-	s.client.logger.Debug("getting system configuration")
+	s.client.log(ctx).Debug("getting system configuration")
 
 	req := []Request{{
 		Cmd:    "GetSysCfg",
@@ -731,24 +871,24 @@ func (s *SystemAPI) GetSysCfg(ctx context.Context) (*SysCfg, error) {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to get system configuration: %v", err)
+		s.client.log(ctx).Error("failed to get system configuration: %v", err)
 		return nil, fmt.Errorf("GetSysCfg request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to get system configuration: %v", err)
+		s.client.log(ctx).Error("failed to get system configuration: %v", err)
 		return nil, err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to get system configuration: %v", apiErr)
+		s.client.log(ctx).Error("failed to get system configuration: %v", apiErr)
 		return nil, apiErr
 	}
 
 	var value SysCfgValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		s.client.logger.Error("failed to parse system configuration response: %v", err)
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse system configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
@@ -757,7 +897,7 @@ func (s *SystemAPI) GetSysCfg(ctx context.Context) (*SysCfg, error) {
 
 // SetSysCfg sets system configuration (login lock settings)
 func (s *SystemAPI) SetSysCfg(ctx context.Context, cfg SysCfg) error {
-	s.client.logger.Info("setting system configuration")
+	s.client.log(ctx).Info("setting system configuration")
 
 	req := []Request{{
 		Cmd:    "SetSysCfg",
@@ -769,22 +909,22 @@ func (s *SystemAPI) SetSysCfg(ctx context.Context, cfg SysCfg) error {
 
 	var resp []Response
 	if err := s.client.do(ctx, req, &resp); err != nil {
-		s.client.logger.Error("failed to set system configuration: %v", err)
+		s.client.log(ctx).Error("failed to set system configuration: %v", err)
 		return fmt.Errorf("SetSysCfg request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response")
-		s.client.logger.Error("failed to set system configuration: %v", err)
+		s.client.log(ctx).Error("failed to set system configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		s.client.logger.Error("failed to set system configuration: %v", apiErr)
+		s.client.log(ctx).Error("failed to set system configuration: %v", apiErr)
 		return apiErr
 	}
 
-	s.client.logger.Info("successfully set system configuration")
+	s.client.log(ctx).Info("successfully set system configuration")
 	return nil
 }
 
@@ -793,9 +933,60 @@ func (s *SystemAPI) SetSysCfg(ctx context.Context, cfg SysCfg) error {
 // It needs to be called several times to complete the device update for larger firmware files.
 // The firmware parameter should be the raw firmware file bytes (.pak file)
 func (s *SystemAPI) Upgrade(ctx context.Context, firmware []byte) error {
-	s.client.logger.Warn("Upgrade endpoint not yet implemented (stub)")
+	s.client.log(ctx).Warn("Upgrade endpoint not yet implemented (stub)")
 	// This is a complex multipart/form-data upload that requires special handling
 	// For now, we return an error indicating this is not yet implemented
 	// Users should use UpgradePrepare + UpgradeOnline + UpgradeStatus instead
+	// An upload must wrap its response body with closeOnCancel like Snap and
+	// Download, so cancelling it does not leave the camera's socket open
 	return fmt.Errorf("Upgrade endpoint not yet implemented - use UpgradePrepare/UpgradeOnline/UpgradeStatus for firmware upgrades")
 }
+
+// Performance represents device load statistics
+type Performance struct {
+	CPUUsed       int `json:"cpuUsed"`       // CPU usage in percent
+	CodeRate      int `json:"codeRate"`      // Combined encoder bitrate in kbps
+	NetThroughput int `json:"netThroughput"` // Network throughput in kbps
+}
+
+// PerformanceValue wraps Performance for API response
+type PerformanceValue struct {
+	Performance Performance `json:"Performance"`
+}
+
+// GetPerformance gets device load statistics. It is not in the API guide;
+// firmware without it returns an APIError with ErrCodeNotSupported.
+func (s *SystemAPI) GetPerformance(ctx context.Context) (*Performance, error) {
+	s.client.log(ctx).Debug("getting performance statistics")
+
+	req := []Request{{
+		Cmd:    "GetPerformance",
+		Action: 0,
+	}}
+
+	var resp []Response
+	if err := s.client.do(ctx, req, &resp); err != nil {
+		s.client.log(ctx).Error("failed to get performance statistics: %v", err)
+		return nil, fmt.Errorf("GetPerformance request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response")
+		s.client.log(ctx).Error("failed to get performance statistics: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		s.client.log(ctx).Error("failed to get performance statistics: %v", apiErr)
+		return nil, apiErr
+	}
+
+	var value PerformanceValue
+	if err := s.client.unmarshal(resp[0].Value, &value); err != nil {
+		s.client.log(ctx).Error("failed to parse performance statistics response: %v", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	s.client.log(ctx).Info("successfully retrieved performance statistics: cpu=%d%%", value.Performance.CPUUsed)
+	return &value.Performance, nil
+}
@@ -762,3 +762,41 @@ func TestSystemAPI_GetChannelStatus(t *testing.T) {
 		t.Errorf("expected online 1, got %d", channelStatus.Status[0].Online)
 	}
 }
+
+func TestSystemAPI_SdCardLock(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetSdCardLock": `{"SdCardLock": {"enable": 0}}`,
+		"SetSdCardLock": "",
+	})
+	client := server.client()
+
+	for _, lock := range []SdCardLock{
+		{Enable: 1},
+		{Enable: 1, Password: "a&b"},
+		{Enable: 1, Password: "with space"},
+	} {
+		if err := client.System.SetSdCardLock(t.Context(), lock); err == nil {
+			t.Errorf("expected error for %+v", lock)
+		}
+	}
+	if server.callCount("SetSdCardLock") != 0 {
+		t.Error("invalid SD card lock was sent")
+	}
+
+	if err := client.System.SetSdCardLock(t.Context(), SdCardLock{Enable: 1, Password: "Secret-123"}); err != nil {
+		t.Fatalf("SetSdCardLock failed: %v", err)
+	}
+	var sent SdCardLockValue
+	json.Unmarshal(server.lastParam("SetSdCardLock"), &sent)
+	if !sent.SdCardLock.Enable.Bool() || sent.SdCardLock.Password != "Secret-123" {
+		t.Errorf("unexpected request %+v", sent)
+	}
+
+	lock, err := client.System.GetSdCardLock(t.Context())
+	if err != nil {
+		t.Fatalf("GetSdCardLock failed: %v", err)
+	}
+	if !lock.Enable.Bool() {
+		t.Errorf("expected the card to be locked, got %+v", lock)
+	}
+}
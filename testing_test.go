@@ -27,6 +27,9 @@ func newTestClient(server *httptest.Server) *Client {
 	client.LED = &LEDAPI{client: client}
 	client.AI = &AIAPI{client: client}
 	client.Streaming = &StreamingAPI{client: client}
+	client.ONVIF = &ONVIFAPI{client: client}
+	client.Storage = &StorageAPI{client: client}
+	client.Diagnostics = &DiagnosticsAPI{client: client}
 
 	return client
 }
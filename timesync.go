@@ -0,0 +1,198 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Time returns the instant represented by the camera time configuration.
+// The camera reports local wall-clock time and TimeZone as seconds west of
+// UTC (e.g. -28800 for UTC+8). If Dst is set and enabled, the DST offset
+// is taken off wall-clock times within the DST period.
+func (t *TimeConfig) Time() time.Time {
+	local := time.Date(t.Year, time.Month(t.Mon), t.Day, t.Hour, t.Min, t.Sec, 0, time.UTC)
+	if t.Dst != nil && t.Dst.Enable.Bool() {
+		if standard := local.Add(-t.Dst.offset()); t.Dst.InEffect(standard) {
+			local = standard
+		}
+	}
+	return local.Add(time.Duration(t.TimeZone) * time.Second)
+}
+
+// SetFromTime sets the wall-clock fields to instant tm expressed in the
+// configuration's current TimeZone, plus the DST offset if Dst is set,
+// enabled and in effect at tm
+func (t *TimeConfig) SetFromTime(tm time.Time) {
+	local := tm.UTC().Add(-time.Duration(t.TimeZone) * time.Second)
+	if t.Dst != nil && t.Dst.Enable.Bool() && t.Dst.InEffect(local) {
+		local = local.Add(t.Dst.offset())
+	}
+	t.Year = local.Year()
+	t.Mon = int(local.Month())
+	t.Day = local.Day()
+	t.Hour = local.Hour()
+	t.Min = local.Minute()
+	t.Sec = local.Second()
+}
+
+// InEffect reports whether DST applies at standard, a local standard time
+// with its wall-clock fields in UTC. DST begins at the begin time in
+// standard time and ends at the end time in DST wall-clock time; periods
+// that span the new year, as in the southern hemisphere, are supported.
+// Enable is not consulted.
+func (d *DstConfig) InEffect(standard time.Time) bool {
+	year := standard.Year()
+	begin := nthWeekday(year, d.BeginMon, d.BeginWeek, d.BeginDay).
+		Add(time.Duration(d.BeginHour)*time.Hour + time.Duration(d.BeginMin)*time.Minute + time.Duration(d.BeginSec)*time.Second)
+	end := nthWeekday(year, d.EndMon, d.EndWeek, d.EndDay).
+		Add(time.Duration(d.EndHour)*time.Hour + time.Duration(d.EndMin)*time.Minute + time.Duration(d.EndSec)*time.Second).
+		Add(-d.offset())
+	if begin.Before(end) {
+		return !standard.Before(begin) && standard.Before(end)
+	}
+	return !standard.Before(begin) || standard.Before(end)
+}
+
+// offset returns how far the clock moves forward during DST
+func (d *DstConfig) offset() time.Duration {
+	return time.Duration(d.Offset) * time.Hour
+}
+
+// nthWeekday returns midnight of the week-th weekday (0=Sunday) of month,
+// in UTC. Week 5 is the last such weekday of the month.
+func nthWeekday(year, month, week, weekday int) time.Time {
+	first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	day := 1 + (weekday-int(first.Weekday())+7)%7 + (week-1)*7
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day -= 7
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// TimeSyncSource selects how Fleet.SyncTime sets camera clocks
+type TimeSyncSource struct {
+	// NTPServer, when set, configures every camera to sync from this NTP
+	// server instead of pushing the time directly
+	NTPServer string
+	// NTPPort is the NTP server port (default: 123)
+	NTPPort int
+	// NTPInterval is the sync interval in seconds (0 syncs immediately)
+	NTPInterval int
+
+	// Reference is the reference clock pushed with SetTime and used to
+	// measure skew (default: time.Now)
+	Reference func() time.Time
+}
+
+// TimeSyncResult is the outcome of syncing one camera
+type TimeSyncResult struct {
+	Name string        // Camera name in the fleet
+	Skew time.Duration // Camera clock minus reference clock after sync
+	Err  error         // Set if the sync or verification failed
+}
+
+// TimeSyncReport summarizes a fleet-wide time sync
+type TimeSyncReport struct {
+	Results []TimeSyncResult // Per-camera results sorted by name
+}
+
+// MaxSkew returns the largest absolute skew among successfully synced cameras
+func (r *TimeSyncReport) MaxSkew() time.Duration {
+	var maxSkew time.Duration
+	for _, res := range r.Results {
+		if res.Err != nil {
+			continue
+		}
+		skew := res.Skew
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > maxSkew {
+			maxSkew = skew
+		}
+	}
+	return maxSkew
+}
+
+// Failed returns the results of cameras that could not be synced
+func (r *TimeSyncReport) Failed() []TimeSyncResult {
+	var failed []TimeSyncResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// SyncTime sets the clock of every camera in the fleet concurrently, either
+// by configuring NTP or by pushing the reference time with SetTime, then
+// reads each clock back with GetTime and reports the per-camera skew against
+// the reference clock. The cameras' time zones are left unchanged.
+func (f *Fleet) SyncTime(ctx context.Context, source TimeSyncSource) *TimeSyncReport {
+	if source.Reference == nil {
+		source.Reference = time.Now
+	}
+	if source.NTPPort == 0 {
+		source.NTPPort = 123
+	}
+
+	var (
+		mu      sync.Mutex
+		results []TimeSyncResult
+	)
+
+	errs := f.Each(ctx, func(ctx context.Context, name string, c *Client) error {
+		skew, err := syncCameraTime(ctx, c, source)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		results = append(results, TimeSyncResult{Name: name, Skew: skew})
+		mu.Unlock()
+		return nil
+	})
+	for name, err := range errs {
+		results = append(results, TimeSyncResult{Name: name, Err: err})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return &TimeSyncReport{Results: results}
+}
+
+// syncCameraTime syncs a single camera and returns its measured skew
+func syncCameraTime(ctx context.Context, c *Client, source TimeSyncSource) (time.Duration, error) {
+	if source.NTPServer != "" {
+		ntp := Ntp{
+			Enable:   1,
+			Server:   source.NTPServer,
+			Port:     source.NTPPort,
+			Interval: source.NTPInterval,
+		}
+		if err := c.Network.SetNtp(ctx, ntp); err != nil {
+			return 0, fmt.Errorf("failed to configure NTP: %w", err)
+		}
+	} else {
+		current, err := c.System.GetTime(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read time zone: %w", err)
+		}
+		current.SetFromTime(source.Reference())
+		if err := c.System.SetTime(ctx, current); err != nil {
+			return 0, fmt.Errorf("failed to set time: %w", err)
+		}
+	}
+
+	before := source.Reference()
+	after, err := c.System.GetTime(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify time: %w", err)
+	}
+	// Compare against the midpoint of the request to cancel out latency
+	reference := before.Add(source.Reference().Sub(before) / 2)
+
+	return after.Time().Sub(reference).Truncate(time.Second), nil
+}
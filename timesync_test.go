@@ -0,0 +1,192 @@
+package reolink
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeConfig_Time(t *testing.T) {
+	// 08:00 local in UTC+8 is midnight UTC
+	tc := TimeConfig{Year: 2024, Mon: 3, Day: 1, Hour: 8, Min: 0, Sec: 0, TimeZone: -28800}
+
+	want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if !tc.Time().Equal(want) {
+		t.Errorf("expected %v, got %v", want, tc.Time())
+	}
+
+	tc.SetFromTime(want.Add(90 * time.Minute))
+	if tc.Hour != 9 || tc.Min != 30 {
+		t.Errorf("expected 09:30 local, got %02d:%02d", tc.Hour, tc.Min)
+	}
+}
+
+func TestTimeConfig_TimeDST(t *testing.T) {
+	// Central European Time: UTC+1, DST from the last Sunday of March at
+	// 02:00 to the last Sunday of October at 03:00
+	dst := &DstConfig{
+		Enable: 1, Offset: 1,
+		BeginMon: 3, BeginWeek: 5, BeginDay: 0, BeginHour: 2,
+		EndMon: 10, EndWeek: 5, EndDay: 0, EndHour: 3,
+	}
+	tests := []struct {
+		name    string
+		utc     time.Time
+		hour    int
+		enabled bool
+	}{
+		{"summer", time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC), 12, true},
+		{"winter", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), 11, true},
+		{"after begin", time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC), 3, true},
+		{"before begin", time.Date(2024, 3, 31, 0, 59, 0, 0, time.UTC), 1, true},
+		{"before end", time.Date(2024, 10, 27, 0, 59, 0, 0, time.UTC), 2, true},
+		{"after end", time.Date(2024, 10, 27, 2, 0, 0, 0, time.UTC), 3, true},
+		{"disabled", time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC), 11, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := *dst
+			rule.Enable = BoolOf(tt.enabled)
+			tc := TimeConfig{TimeZone: -3600, Dst: &rule}
+			tc.SetFromTime(tt.utc)
+			if tc.Hour != tt.hour {
+				t.Errorf("SetFromTime(%v) hour = %d, want %d", tt.utc, tc.Hour, tt.hour)
+			}
+			if !tc.Time().Equal(tt.utc) {
+				t.Errorf("Time() = %v, want %v", tc.Time(), tt.utc)
+			}
+		})
+	}
+
+	// Southern hemisphere: DST spans the new year
+	south := &DstConfig{
+		Enable: 1, Offset: 1,
+		BeginMon: 10, BeginWeek: 1, BeginDay: 0, BeginHour: 2,
+		EndMon: 4, EndWeek: 1, EndDay: 0, EndHour: 3,
+	}
+	for _, tt := range []struct {
+		standard time.Time
+		want     bool
+	}{
+		{time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2024, 12, 10, 12, 0, 0, 0, time.UTC), true},
+	} {
+		if got := south.InEffect(tt.standard); got != tt.want {
+			t.Errorf("InEffect(%v) = %v, want %v", tt.standard, got, tt.want)
+		}
+	}
+}
+
+func TestFleet_SyncTime_DST(t *testing.T) {
+	// 14:00 summer time in UTC+1 is 12:00 UTC
+	const getTime = `{"Time": {"year": 2024, "mon": 7, "day": 1, "hour": 14, "min": 0, "sec": 0, "timeZone": -3600},
+		"Dst": {"enable": 1, "offset": 1, "startMon": 3, "startWeek": 5, "startWeekday": 0, "startHour": 2,
+			"endMon": 10, "endWeek": 5, "endWeekday": 0, "endHour": 3}}`
+	reference := func() time.Time { return time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC) }
+
+	ntp := newCmdServer(t, map[string]string{"SetNtp": "", "GetTime": getTime})
+	fleet := NewFleet()
+	fleet.Add("cam", ntp.client())
+	report := fleet.SyncTime(t.Context(), TimeSyncSource{NTPServer: "pool.ntp.org", Reference: reference})
+	if len(report.Failed()) != 0 {
+		t.Fatalf("unexpected failures: %+v", report.Failed())
+	}
+	if report.Results[0].Skew != 0 {
+		t.Errorf("expected zero skew during DST, got %v", report.Results[0].Skew)
+	}
+
+	server := newCmdServer(t, map[string]string{"SetTime": "", "GetTime": getTime})
+	fleet = NewFleet()
+	fleet.Add("cam", server.client())
+	fleet.SyncTime(t.Context(), TimeSyncSource{Reference: reference})
+
+	var param TimeParam
+	if err := json.Unmarshal(server.lastParam("SetTime"), &param); err != nil {
+		t.Fatalf("failed to decode SetTime param: %v", err)
+	}
+	if param.Time.Hour != 14 {
+		t.Errorf("expected 14:00 summer time to be pushed, got %02d:00", param.Time.Hour)
+	}
+	if strings.Contains(string(server.lastParam("SetTime")), "Dst") {
+		t.Error("SetTime must not send the DST rule")
+	}
+}
+
+func TestFleet_SyncTime_SetTime(t *testing.T) {
+	reference := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	fleet := NewFleet()
+
+	servers := map[string]*cmdServer{}
+	for _, name := range []string{"front", "back"} {
+		server := newCmdServer(t, map[string]string{
+			"GetTime": `{"Time": {"year": 2020, "mon": 1, "day": 1, "hour": 0, "min": 0, "sec": 0, "timeZone": 18000}}`,
+			"SetTime": "",
+		})
+		servers[name] = server
+		fleet.Add(name, server.client())
+	}
+	// A camera that rejects SetTime
+	broken := newCmdServer(t, map[string]string{
+		"GetTime": `{"Time": {"year": 2020, "mon": 1, "day": 1, "timeZone": 0}}`,
+	})
+	fleet.Add("broken", broken.client())
+
+	report := fleet.SyncTime(t.Context(), TimeSyncSource{
+		Reference: func() time.Time { return reference },
+	})
+
+	if len(report.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(report.Results))
+	}
+	if report.Results[0].Name != "back" || report.Results[1].Name != "broken" {
+		t.Errorf("expected results sorted by name: %+v", report.Results)
+	}
+	if report.MaxSkew() != 0 {
+		t.Errorf("expected zero skew, got %v", report.MaxSkew())
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0].Name != "broken" {
+		t.Errorf("expected broken camera to fail, got %+v", failed)
+	}
+
+	var param TimeParam
+	if err := json.Unmarshal(servers["front"].lastParam("SetTime"), &param); err != nil {
+		t.Fatalf("failed to decode SetTime param: %v", err)
+	}
+	// UTC-5: 12:00 UTC is 07:00 local, and the time zone is preserved
+	if param.Time.Hour != 7 || param.Time.TimeZone != 18000 {
+		t.Errorf("unexpected pushed time: %+v", param.Time)
+	}
+}
+
+func TestFleet_SyncTime_NTP(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"SetNtp":  "",
+		"GetTime": `{"Time": {"year": 2024, "mon": 6, "day": 1, "hour": 12, "min": 0, "sec": 5, "timeZone": 0}}`,
+	})
+	fleet := NewFleet()
+	fleet.Add("cam", server.client())
+
+	report := fleet.SyncTime(t.Context(), TimeSyncSource{
+		NTPServer: "pool.ntp.org",
+		Reference: func() time.Time { return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC) },
+	})
+
+	if len(report.Failed()) != 0 {
+		t.Fatalf("unexpected failures: %+v", report.Failed())
+	}
+	if report.Results[0].Skew != 5*time.Second {
+		t.Errorf("expected 5s skew, got %v", report.Results[0].Skew)
+	}
+
+	var param struct {
+		Ntp Ntp `json:"Ntp"`
+	}
+	if err := json.Unmarshal(server.lastParam("SetNtp"), &param); err != nil {
+		t.Fatalf("failed to decode SetNtp param: %v", err)
+	}
+	if param.Ntp.Server != "pool.ntp.org" || param.Ntp.Port != 123 || param.Ntp.Enable != 1 {
+		t.Errorf("unexpected NTP config: %+v", param.Ntp)
+	}
+}
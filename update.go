@@ -0,0 +1,185 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// updateAttempts is how often Update re-reads settings that changed while
+// it was updating them
+const updateAttempts = 3
+
+// ErrUpdateConflict is returned by Update when the settings kept changing
+// on the camera between reading and writing them
+var ErrUpdateConflict = errors.New("settings changed on the camera during update")
+
+// Update changes settings by reading them with get, calling update on a
+// copy and writing the copy back with set if update changed it. Set
+// commands replace the whole configuration, so fields update leaves alone
+// keep their current values instead of being reset to zero. If the settings
+// have a Validate method it must accept the result.
+//
+// Before writing, the settings are read again; if they changed in the
+// meantime, e.g. in the camera's web UI, the update is retried on the new
+// values and ErrUpdateConflict returned after a few attempts. The camera
+// has no compare-and-set, so this narrows the window for lost updates but
+// cannot close it, and a read served by WithCache is not re-checked.
+//
+// The modules' Update methods, such as Video.UpdateOsd, bind Update to one
+// configuration:
+//
+//	err := client.Video.UpdateOsd(ctx, 0, func(osd *reolink.Osd) {
+//		osd.OsdTime.Enable.Set(false)
+//	})
+func Update[T any](ctx context.Context, get func(context.Context) (*T, error), set func(context.Context, T) error, update func(*T)) error {
+	if update == nil {
+		return fmt.Errorf("update must not be nil")
+	}
+
+	current, err := get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read current settings: %w", err)
+	}
+	for attempt := 1; ; attempt++ {
+		desired, err := cloneSettings(current)
+		if err != nil {
+			return err
+		}
+		update(desired)
+		if reflect.DeepEqual(current, desired) {
+			return nil
+		}
+		if v, ok := any(desired).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return err
+			}
+		}
+
+		latest, err := get(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to re-read settings: %w", err)
+		}
+		if reflect.DeepEqual(current, latest) {
+			return set(ctx, *desired)
+		}
+		if attempt == updateAttempts {
+			return ErrUpdateConflict
+		}
+		current = latest
+	}
+}
+
+// cloneSettings deep-copies settings through their JSON form, so update
+// cannot modify the slices and maps of the original
+func cloneSettings[T any](v *T) (*T, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy settings: %w", err)
+	}
+	clone := new(T)
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, fmt.Errorf("failed to copy settings: %w", err)
+	}
+	return clone, nil
+}
+
+// UpdateOsd changes the channel's OSD configuration with Update
+func (v *VideoAPI) UpdateOsd(ctx context.Context, channel int, update func(*Osd)) error {
+	return Update(ctx,
+		func(ctx context.Context) (*Osd, error) { return v.GetOsd(ctx, channel) },
+		func(ctx context.Context, osd Osd) error {
+			osd.Channel = channel
+			return v.SetOsd(ctx, osd)
+		},
+		update)
+}
+
+// UpdateImage changes the channel's image settings with Update
+func (v *VideoAPI) UpdateImage(ctx context.Context, channel int, update func(*Image)) error {
+	return Update(ctx,
+		func(ctx context.Context) (*Image, error) { return v.GetImage(ctx, channel) },
+		func(ctx context.Context, image Image) error {
+			image.Channel = channel
+			return v.SetImage(ctx, image)
+		},
+		update)
+}
+
+// UpdateIsp changes the channel's ISP settings with Update
+func (v *VideoAPI) UpdateIsp(ctx context.Context, channel int, update func(*Isp)) error {
+	return Update(ctx,
+		func(ctx context.Context) (*Isp, error) { return v.GetIsp(ctx, channel) },
+		func(ctx context.Context, isp Isp) error {
+			isp.Channel = channel
+			return v.SetIsp(ctx, isp)
+		},
+		update)
+}
+
+// UpdateEnc changes the channel's encoding configuration with Update
+func (e *EncodingAPI) UpdateEnc(ctx context.Context, channel int, update func(*EncConfig)) error {
+	return Update(ctx,
+		func(ctx context.Context) (*EncConfig, error) { return e.GetEnc(ctx, channel) },
+		func(ctx context.Context, config EncConfig) error {
+			config.Channel = channel
+			return e.SetEnc(ctx, config)
+		},
+		update)
+}
+
+// UpdateEmail changes the email configuration with Update
+func (n *NetworkAPI) UpdateEmail(ctx context.Context, update func(*Email)) error {
+	return Update(ctx, n.GetEmail, n.SetEmail, update)
+}
+
+// UpdateFtp changes the FTP configuration with Update
+func (n *NetworkAPI) UpdateFtp(ctx context.Context, update func(*Ftp)) error {
+	return Update(ctx, n.GetFtp, n.SetFtp, update)
+}
+
+// UpdateNtp changes the NTP configuration with Update
+func (n *NetworkAPI) UpdateNtp(ctx context.Context, update func(*Ntp)) error {
+	return Update(ctx, n.GetNtp, n.SetNtp, update)
+}
+
+// UpdatePush changes the push notification configuration with Update
+func (n *NetworkAPI) UpdatePush(ctx context.Context, update func(*Push)) error {
+	return Update(ctx, n.GetPush, n.SetPush, update)
+}
+
+// UpdateRec changes the channel's recording configuration with Update
+func (r *RecordingAPI) UpdateRec(ctx context.Context, channel int, update func(*Rec)) error {
+	return Update(ctx,
+		func(ctx context.Context) (*Rec, error) { return r.GetRec(ctx, channel) },
+		func(ctx context.Context, rec Rec) error {
+			rec.Channel = channel
+			return r.SetRec(ctx, rec)
+		},
+		update)
+}
+
+// UpdateMdAlarm changes the channel's motion detection configuration with
+// Update
+func (a *AlarmAPI) UpdateMdAlarm(ctx context.Context, channel int, update func(*MdAlarm)) error {
+	return Update(ctx,
+		func(ctx context.Context) (*MdAlarm, error) { return a.GetMdAlarm(ctx, channel) },
+		func(ctx context.Context, config MdAlarm) error {
+			config.Channel = channel
+			return a.SetMdAlarm(ctx, config)
+		},
+		update)
+}
+
+// UpdateWhiteLed changes the channel's white LED configuration with Update
+func (l *LEDAPI) UpdateWhiteLed(ctx context.Context, channel int, update func(*WhiteLed)) error {
+	return Update(ctx,
+		func(ctx context.Context) (*WhiteLed, error) { return l.GetWhiteLed(ctx, channel) },
+		func(ctx context.Context, config WhiteLed) error {
+			config.Channel = channel
+			return l.SetWhiteLed(ctx, config)
+		},
+		update)
+}
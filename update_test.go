@@ -0,0 +1,87 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetOsd": `{"Osd": {"channel": 0, "bgcolor": 0, "osdChannel": {"enable": 1, "name": "Front", "pos": "Lower Right"}, "osdTime": {"enable": 1, "pos": "Top Center"}, "watermark": 1}}`,
+		"SetOsd": "",
+	})
+	client := server.client()
+
+	err := client.Video.UpdateOsd(t.Context(), 0, func(osd *Osd) {
+		osd.OsdTime.Enable = 0
+	})
+	if err != nil {
+		t.Fatalf("UpdateOsd failed: %v", err)
+	}
+	var param struct{ Osd Osd }
+	if err := json.Unmarshal(server.lastParam("SetOsd"), &param); err != nil {
+		t.Fatal(err)
+	}
+	if param.Osd.OsdTime.Enable != 0 || param.Osd.OsdChannel.Name != "Front" || param.Osd.Watermark != 1 {
+		t.Errorf("unexpected OSD written: %+v", param.Osd)
+	}
+
+	// No change, no write
+	if err := client.Video.UpdateOsd(t.Context(), 0, func(*Osd) {}); err != nil {
+		t.Fatal(err)
+	}
+	if n := server.callCount("SetOsd"); n != 1 {
+		t.Errorf("expected 1 SetOsd request, got %d", n)
+	}
+}
+
+func TestUpdate_Conflict(t *testing.T) {
+	type settings struct{ Value int }
+	reads := 0
+	get := func(context.Context) (*settings, error) {
+		reads++
+		return &settings{Value: reads}, nil // Changes on every read
+	}
+	set := func(context.Context, settings) error {
+		t.Error("set called despite conflicting changes")
+		return nil
+	}
+	err := Update(t.Context(), get, set, func(s *settings) { s.Value = 100 })
+	if !errors.Is(err, ErrUpdateConflict) {
+		t.Errorf("expected ErrUpdateConflict, got %v", err)
+	}
+
+	// Changed once: retried on the new values
+	reads = 0
+	var written *settings
+	get = func(context.Context) (*settings, error) {
+		reads++
+		return &settings{Value: min(reads, 2)}, nil
+	}
+	set = func(_ context.Context, s settings) error {
+		written = &s
+		return nil
+	}
+	if err := Update(t.Context(), get, set, func(s *settings) { s.Value *= 10 }); err != nil {
+		t.Fatal(err)
+	}
+	if written == nil || written.Value != 20 {
+		t.Errorf("expected the retried value 20, got %+v", written)
+	}
+}
+
+func TestUpdate_Validate(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetNetPort": `{"NetPort": {"httpEnable": 1, "httpPort": 80, "mediaPort": 9000}}`,
+	})
+	client := server.client()
+
+	err := Update(t.Context(), client.Network.GetNetPort, client.Network.SetNetPort, func(p *NetPort) {
+		p.HTTPPort = 9000
+	})
+	if err == nil {
+		t.Error("expected NetPort.Validate to reject a port conflict")
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // VideoAPI provides access to video input and encoding API endpoints
@@ -22,15 +26,54 @@ type Osd struct {
 
 // OsdChannel represents camera name display settings
 type OsdChannel struct {
-	Enable int    `json:"enable"` // 0=disabled, 1=enabled
-	Name   string `json:"name"`   // Camera name
-	Pos    string `json:"pos"`    // Position: "Upper Left", "Upper Right", "Lower Left", "Lower Right", "Top Center", "Bottom Center"
+	Enable BoolInt `json:"enable"` // 0=disabled, 1=enabled
+	Name   string  `json:"name"`   // Camera name
+	Pos    OsdPos  `json:"pos"`    // Position of the name
 }
 
 // OsdTime represents timestamp display settings
 type OsdTime struct {
-	Enable int    `json:"enable"` // 0=disabled, 1=enabled
-	Pos    string `json:"pos"`    // Position: "Upper Left", "Upper Right", "Lower Left", "Lower Right", "Top Center", "Bottom Center"
+	Enable BoolInt `json:"enable"` // 0=disabled, 1=enabled
+	Pos    OsdPos  `json:"pos"`    // Position of the timestamp
+}
+
+// OsdPos is the position of an OSD element
+type OsdPos string
+
+// OSD positions
+const (
+	OsdPosUpperLeft    OsdPos = "Upper Left"
+	OsdPosTopCenter    OsdPos = "Top Center"
+	OsdPosUpperRight   OsdPos = "Upper Right"
+	OsdPosLowerLeft    OsdPos = "Lower Left"
+	OsdPosBottomCenter OsdPos = "Bottom Center"
+	OsdPosLowerRight   OsdPos = "Lower Right"
+	// OsdPosOther is reported for a position set outside the presets
+	OsdPosOther OsdPos = "Other Configuration"
+)
+
+// OsdPositions lists the valid OSD positions
+var OsdPositions = []OsdPos{
+	OsdPosUpperLeft, OsdPosTopCenter, OsdPosUpperRight,
+	OsdPosLowerLeft, OsdPosBottomCenter, OsdPosLowerRight, OsdPosOther,
+}
+
+// Validate checks that p is one of OsdPositions. An empty position leaves
+// the camera's choice and is accepted.
+func (p OsdPos) Validate() error {
+	return validateEnum("OSD position", p, OsdPositions)
+}
+
+// Validate checks the positions of the name and timestamp. Cameras accept
+// and silently ignore unknown positions, so SetOsd rejects them first.
+func (o *Osd) Validate() error {
+	if err := o.OsdChannel.Pos.Validate(); err != nil {
+		return fmt.Errorf("osdChannel: %w", err)
+	}
+	if err := o.OsdTime.Pos.Validate(); err != nil {
+		return fmt.Errorf("osdTime: %w", err)
+	}
+	return nil
 }
 
 // OsdValue represents the response value for GetOsd
@@ -61,17 +104,112 @@ type IspGain struct {
 
 // Isp represents Image Signal Processor settings
 type Isp struct {
-	Channel     int     `json:"channel"`     // Channel number
-	AntiFlicker string  `json:"antiFlicker"` // "Outdoor", "50Hz", "60Hz"
-	Exposure    string  `json:"exposure"`    // "Auto", "Manual"
-	Gain        IspGain `json:"gain"`        // Gain range (min/max)
-	DayNight    string  `json:"dayNight"`    // "Auto", "Color", "Black&White"
-	BackLight   string  `json:"backLight"`   // "Off", "BackLightControl", "DynamicRangeControl", "Off"
-	Blc         int     `json:"blc"`         // Backlight compensation (0-255)
-	Drc         int     `json:"drc"`         // Dynamic range control (0-255)
-	Rotation    int     `json:"rotation"`    // Rotation angle (0, 90, 180, 270)
-	Mirroring   int     `json:"mirroring"`   // Mirror (0=off, 1=on)
-	Nr3d        int     `json:"nr3d"`        // 3D noise reduction (0-100)
+	Channel     int         `json:"channel"`     // Channel number
+	AntiFlicker AntiFlicker `json:"antiFlicker"` // Flicker prevention
+	Exposure    string      `json:"exposure"`    // "Auto", "Manual"
+	Gain        IspGain     `json:"gain"`        // Gain range (min/max)
+	DayNight    DayNight    `json:"dayNight"`    // Day and night mode
+	BackLight   BackLight   `json:"backLight"`   // Backlight handling
+	Blc         int         `json:"blc"`         // Backlight compensation (0-255)
+	Drc         int         `json:"drc"`         // Dynamic range control (0-255)
+	Rotation    int         `json:"rotation"`    // Rotation angle (0, 90, 180, 270)
+	Mirroring   int         `json:"mirroring"`   // Mirror (0=off, 1=on)
+	Nr3d        int         `json:"nr3d"`        // 3D noise reduction (0-100)
+}
+
+// AntiFlicker is the flicker prevention mode of Isp, matching the mains
+// frequency of artificial light
+type AntiFlicker string
+
+// Anti-flicker modes
+const (
+	AntiFlickerOff     AntiFlicker = "Off"
+	AntiFlicker50Hz    AntiFlicker = "50HZ"
+	AntiFlicker60Hz    AntiFlicker = "60HZ"
+	AntiFlickerOutdoor AntiFlicker = "Outdoor"
+	// AntiFlickerOther is reported by firmware that names the outdoor mode
+	// "Other"
+	AntiFlickerOther AntiFlicker = "Other"
+)
+
+// AntiFlickerModes lists the valid anti-flicker modes
+var AntiFlickerModes = []AntiFlicker{
+	AntiFlickerOff, AntiFlicker50Hz, AntiFlicker60Hz, AntiFlickerOutdoor, AntiFlickerOther,
+}
+
+// Validate checks that a is one of AntiFlickerModes. An empty mode is
+// accepted.
+func (a AntiFlicker) Validate() error {
+	return validateEnum("anti-flicker mode", a, AntiFlickerModes)
+}
+
+// DayNight is the day and night mode of Isp
+type DayNight string
+
+// Day and night modes
+const (
+	// DayNightAuto switches to black and white when it gets dark
+	DayNightAuto DayNight = "Auto"
+	// DayNightColor always records in color
+	DayNightColor DayNight = "Color"
+	// DayNightBlackWhite always records in black and white
+	DayNightBlackWhite DayNight = "Black&White"
+)
+
+// DayNightModes lists the valid day and night modes
+var DayNightModes = []DayNight{DayNightAuto, DayNightColor, DayNightBlackWhite}
+
+// Validate checks that d is one of DayNightModes. An empty mode is
+// accepted.
+func (d DayNight) Validate() error {
+	return validateEnum("day/night mode", d, DayNightModes)
+}
+
+// BackLight is the backlight handling of Isp
+type BackLight string
+
+// Backlight modes
+const (
+	BackLightOff BackLight = "Off"
+	// BackLightControl compensates with the level in Isp.Blc
+	BackLightControl BackLight = "BackLightControl"
+	// BackLightDynamicRange applies dynamic range control with the level in
+	// Isp.Drc
+	BackLightDynamicRange BackLight = "DynamicRangeControl"
+)
+
+// BackLightModes lists the valid backlight modes
+var BackLightModes = []BackLight{BackLightOff, BackLightControl, BackLightDynamicRange}
+
+// Validate checks that b is one of BackLightModes. An empty mode is
+// accepted.
+func (b BackLight) Validate() error {
+	return validateEnum("backlight mode", b, BackLightModes)
+}
+
+// Validate checks the anti-flicker, day/night and backlight modes. Cameras
+// accept and silently ignore unknown modes, so SetIsp rejects them first.
+func (i *Isp) Validate() error {
+	if err := i.AntiFlicker.Validate(); err != nil {
+		return err
+	}
+	if err := i.DayNight.Validate(); err != nil {
+		return err
+	}
+	return i.BackLight.Validate()
+}
+
+// validateEnum checks that v is empty or one of allowed, listing them in
+// the error otherwise
+func validateEnum[T ~string](name string, v T, allowed []T) error {
+	if v == "" || slices.Contains(allowed, v) {
+		return nil
+	}
+	quoted := make([]string, len(allowed))
+	for i, a := range allowed {
+		quoted[i] = strconv.Quote(string(a))
+	}
+	return fmt.Errorf("invalid %s %q, must be one of %s", name, v, strings.Join(quoted, ", "))
 }
 
 // IspValue represents the response value for GetIsp
@@ -82,8 +220,8 @@ type IspValue struct {
 // Mask represents privacy mask configuration
 type Mask struct {
 	Channel int        `json:"channel"` // Channel number
-	Enable  int        `json:"enable"`  // 0=disabled, 1=enabled
-	Area    []MaskArea `json:"area"`    // Privacy mask areas (up to 4)
+	Enable  BoolInt    `json:"enable"`  // 0=disabled, 1=enabled
+	Area    []MaskArea `json:"area"`    // Privacy mask areas (up to 4; see MaskV20 for more)
 }
 
 // MaskArea represents a single privacy mask area
@@ -95,6 +233,60 @@ type MaskArea struct {
 	Height int        `json:"height"` // Height
 }
 
+// maskAreaJSON is the wire format of MaskArea, with the rectangle in a
+// "block" object as in the API guide
+type maskAreaJSON struct {
+	Screen MaskScreen `json:"screen"`
+	Block  *struct {
+		X      int `json:"x"`
+		Y      int `json:"y"`
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"block,omitempty"`
+}
+
+// MarshalJSON encodes the rectangle as a "block" object
+func (a MaskArea) MarshalJSON() ([]byte, error) {
+	var w maskAreaJSON
+	w.Screen = a.Screen
+	w.Block = &struct {
+		X      int `json:"x"`
+		Y      int `json:"y"`
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	}{a.X, a.Y, a.Width, a.Height}
+	return json.Marshal(w)
+}
+
+// jsonShape returns the wire format of MaskArea, with the rectangle in a
+// "block" object or inline, for decode modes
+func (MaskArea) jsonShape(interface{}) reflect.Type {
+	return reflect.TypeOf(struct {
+		maskAreaJSON
+		X      int `json:"x"`
+		Y      int `json:"y"`
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	}{})
+}
+
+// UnmarshalJSON accepts the rectangle in a "block" object or, as some
+// firmware returns it, inline
+func (a *MaskArea) UnmarshalJSON(data []byte) error {
+	type flat MaskArea
+	if err := json.Unmarshal(data, (*flat)(a)); err != nil {
+		return err
+	}
+	var w maskAreaJSON
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	if w.Block != nil {
+		a.X, a.Y, a.Width, a.Height = w.Block.X, w.Block.Y, w.Block.Width, w.Block.Height
+	}
+	return nil
+}
+
 // MaskScreen represents screen dimensions for mask area
 type MaskScreen struct {
 	Height int `json:"height"` // Screen height
@@ -108,7 +300,7 @@ type MaskValue struct {
 
 // GetOsd gets On-Screen Display configuration
 func (v *VideoAPI) GetOsd(ctx context.Context, channel int) (*Osd, error) {
-	v.client.logger.Debug("getting OSD configuration: channel=%d", channel)
+	v.client.log(ctx).Debug("getting OSD configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetOsd",
@@ -120,24 +312,24 @@ func (v *VideoAPI) GetOsd(ctx context.Context, channel int) (*Osd, error) {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to get OSD configuration: %v", err)
+		v.client.log(ctx).Error("failed to get OSD configuration: %v", err)
 		return nil, fmt.Errorf("GetOsd request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetOsd")
-		v.client.logger.Error("failed to get OSD configuration: %v", err)
+		v.client.log(ctx).Error("failed to get OSD configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		v.client.logger.Error("failed to get OSD configuration: %v", err)
+		v.client.log(ctx).Error("failed to get OSD configuration: %v", err)
 		return nil, err
 	}
 
 	var value OsdValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		v.client.logger.Error("failed to parse OSD configuration response: %v", err)
+	if err := v.client.unmarshal(resp[0].Value, &value); err != nil {
+		v.client.log(ctx).Error("failed to parse OSD configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetOsd response: %w", err)
 	}
 
@@ -146,7 +338,11 @@ func (v *VideoAPI) GetOsd(ctx context.Context, channel int) (*Osd, error) {
 
 // SetOsd sets On-Screen Display configuration
 func (v *VideoAPI) SetOsd(ctx context.Context, osd Osd) error {
-	v.client.logger.Info("setting OSD configuration: channel=%d", osd.Channel)
+	if err := osd.Validate(); err != nil {
+		return err
+	}
+
+	v.client.log(ctx).Info("setting OSD configuration: channel=%d", osd.Channel)
 
 	req := []Request{{
 		Cmd: "SetOsd",
@@ -157,28 +353,28 @@ func (v *VideoAPI) SetOsd(ctx context.Context, osd Osd) error {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to set OSD configuration: %v", err)
+		v.client.log(ctx).Error("failed to set OSD configuration: %v", err)
 		return fmt.Errorf("SetOsd request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetOsd")
-		v.client.logger.Error("failed to set OSD configuration: %v", err)
+		v.client.log(ctx).Error("failed to set OSD configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		v.client.logger.Error("failed to set OSD configuration: %v", apiErr)
+		v.client.log(ctx).Error("failed to set OSD configuration: %v", apiErr)
 		return apiErr
 	}
 
-	v.client.logger.Info("successfully set OSD configuration")
+	v.client.log(ctx).Info("successfully set OSD configuration")
 	return nil
 }
 
 // GetImage gets image quality settings
 func (v *VideoAPI) GetImage(ctx context.Context, channel int) (*Image, error) {
-	v.client.logger.Debug("getting image settings: channel=%d", channel)
+	v.client.log(ctx).Debug("getting image settings: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetImage",
@@ -190,24 +386,24 @@ func (v *VideoAPI) GetImage(ctx context.Context, channel int) (*Image, error) {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to get image settings: %v", err)
+		v.client.log(ctx).Error("failed to get image settings: %v", err)
 		return nil, fmt.Errorf("GetImage request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetImage")
-		v.client.logger.Error("failed to get image settings: %v", err)
+		v.client.log(ctx).Error("failed to get image settings: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		v.client.logger.Error("failed to get image settings: %v", err)
+		v.client.log(ctx).Error("failed to get image settings: %v", err)
 		return nil, err
 	}
 
 	var value ImageValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		v.client.logger.Error("failed to parse image settings response: %v", err)
+	if err := v.client.unmarshal(resp[0].Value, &value); err != nil {
+		v.client.log(ctx).Error("failed to parse image settings response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetImage response: %w", err)
 	}
 
@@ -216,7 +412,7 @@ func (v *VideoAPI) GetImage(ctx context.Context, channel int) (*Image, error) {
 
 // SetImage sets image quality settings
 func (v *VideoAPI) SetImage(ctx context.Context, image Image) error {
-	v.client.logger.Info("setting image settings: channel=%d", image.Channel)
+	v.client.log(ctx).Info("setting image settings: channel=%d", image.Channel)
 
 	req := []Request{{
 		Cmd: "SetImage",
@@ -227,28 +423,28 @@ func (v *VideoAPI) SetImage(ctx context.Context, image Image) error {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to set image settings: %v", err)
+		v.client.log(ctx).Error("failed to set image settings: %v", err)
 		return fmt.Errorf("SetImage request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetImage")
-		v.client.logger.Error("failed to set image settings: %v", err)
+		v.client.log(ctx).Error("failed to set image settings: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		v.client.logger.Error("failed to set image settings: %v", apiErr)
+		v.client.log(ctx).Error("failed to set image settings: %v", apiErr)
 		return apiErr
 	}
 
-	v.client.logger.Info("successfully set image settings")
+	v.client.log(ctx).Info("successfully set image settings")
 	return nil
 }
 
 // GetIsp gets Image Signal Processor settings
 func (v *VideoAPI) GetIsp(ctx context.Context, channel int) (*Isp, error) {
-	v.client.logger.Debug("getting ISP settings: channel=%d", channel)
+	v.client.log(ctx).Debug("getting ISP settings: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetIsp",
@@ -260,24 +456,24 @@ func (v *VideoAPI) GetIsp(ctx context.Context, channel int) (*Isp, error) {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to get ISP settings: %v", err)
+		v.client.log(ctx).Error("failed to get ISP settings: %v", err)
 		return nil, fmt.Errorf("GetIsp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetIsp")
-		v.client.logger.Error("failed to get ISP settings: %v", err)
+		v.client.log(ctx).Error("failed to get ISP settings: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		v.client.logger.Error("failed to get ISP settings: %v", err)
+		v.client.log(ctx).Error("failed to get ISP settings: %v", err)
 		return nil, err
 	}
 
 	var value IspValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		v.client.logger.Error("failed to parse ISP settings response: %v", err)
+	if err := v.client.unmarshal(resp[0].Value, &value); err != nil {
+		v.client.log(ctx).Error("failed to parse ISP settings response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetIsp response: %w", err)
 	}
 
@@ -286,7 +482,11 @@ func (v *VideoAPI) GetIsp(ctx context.Context, channel int) (*Isp, error) {
 
 // SetIsp sets Image Signal Processor settings
 func (v *VideoAPI) SetIsp(ctx context.Context, isp Isp) error {
-	v.client.logger.Info("setting ISP settings: channel=%d", isp.Channel)
+	if err := isp.Validate(); err != nil {
+		return err
+	}
+
+	v.client.log(ctx).Info("setting ISP settings: channel=%d", isp.Channel)
 
 	req := []Request{{
 		Cmd: "SetIsp",
@@ -297,28 +497,28 @@ func (v *VideoAPI) SetIsp(ctx context.Context, isp Isp) error {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to set ISP settings: %v", err)
+		v.client.log(ctx).Error("failed to set ISP settings: %v", err)
 		return fmt.Errorf("SetIsp request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetIsp")
-		v.client.logger.Error("failed to set ISP settings: %v", err)
+		v.client.log(ctx).Error("failed to set ISP settings: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		v.client.logger.Error("failed to set ISP settings: %v", apiErr)
+		v.client.log(ctx).Error("failed to set ISP settings: %v", apiErr)
 		return apiErr
 	}
 
-	v.client.logger.Info("successfully set ISP settings")
+	v.client.log(ctx).Info("successfully set ISP settings")
 	return nil
 }
 
 // GetMask gets privacy mask configuration
 func (v *VideoAPI) GetMask(ctx context.Context, channel int) (*Mask, error) {
-	v.client.logger.Debug("getting privacy mask configuration: channel=%d", channel)
+	v.client.log(ctx).Debug("getting privacy mask configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetMask",
@@ -330,24 +530,24 @@ func (v *VideoAPI) GetMask(ctx context.Context, channel int) (*Mask, error) {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to get privacy mask configuration: %v", err)
+		v.client.log(ctx).Error("failed to get privacy mask configuration: %v", err)
 		return nil, fmt.Errorf("GetMask request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetMask")
-		v.client.logger.Error("failed to get privacy mask configuration: %v", err)
+		v.client.log(ctx).Error("failed to get privacy mask configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		v.client.logger.Error("failed to get privacy mask configuration: %v", err)
+		v.client.log(ctx).Error("failed to get privacy mask configuration: %v", err)
 		return nil, err
 	}
 
 	var value MaskValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		v.client.logger.Error("failed to parse privacy mask configuration response: %v", err)
+	if err := v.client.unmarshal(resp[0].Value, &value); err != nil {
+		v.client.log(ctx).Error("failed to parse privacy mask configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetMask response: %w", err)
 	}
 
@@ -356,7 +556,7 @@ func (v *VideoAPI) GetMask(ctx context.Context, channel int) (*Mask, error) {
 
 // SetMask sets privacy mask configuration
 func (v *VideoAPI) SetMask(ctx context.Context, mask Mask) error {
-	v.client.logger.Info("setting privacy mask configuration: channel=%d", mask.Channel)
+	v.client.log(ctx).Info("setting privacy mask configuration: channel=%d", mask.Channel)
 
 	req := []Request{{
 		Cmd: "SetMask",
@@ -367,25 +567,163 @@ func (v *VideoAPI) SetMask(ctx context.Context, mask Mask) error {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to set privacy mask configuration: %v", err)
+		v.client.log(ctx).Error("failed to set privacy mask configuration: %v", err)
 		return fmt.Errorf("SetMask request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetMask")
-		v.client.logger.Error("failed to set privacy mask configuration: %v", err)
+		v.client.log(ctx).Error("failed to set privacy mask configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		v.client.logger.Error("failed to set privacy mask configuration: %v", apiErr)
+		v.client.log(ctx).Error("failed to set privacy mask configuration: %v", apiErr)
 		return apiErr
 	}
 
-	v.client.logger.Info("successfully set privacy mask configuration")
+	v.client.log(ctx).Info("successfully set privacy mask configuration")
 	return nil
 }
 
+// maxMaskAreas is the number of privacy mask areas SetMask accepts
+const maxMaskAreas = 4
+
+// MaskV20 represents privacy mask configuration in the v2.0 format of newer
+// firmware, which allows more areas than Mask and can describe them as a
+// block grid like the motion detection scope
+type MaskV20 struct {
+	Channel int        `json:"channel"`         // Channel number
+	Enable  BoolInt    `json:"enable"`          // 0=disabled, 1=enabled
+	Area    []MaskArea `json:"area,omitempty"`  // Privacy mask areas
+	Scope   *MdScope   `json:"scope,omitempty"` // Masked blocks of the grid; nil if areas are used
+}
+
+// MaskV20Value represents the response value for GetMaskV20
+type MaskV20Value struct {
+	Mask MaskV20 `json:"Mask"`
+}
+
+// GetMaskV20 gets privacy mask configuration (v2.0). The command is not in
+// the API guide; GetPrivacyMask picks it on firmware reporting mask ver 2.
+func (v *VideoAPI) GetMaskV20(ctx context.Context, channel int) (*MaskV20, error) {
+	v.client.log(ctx).Debug("getting privacy mask configuration (v2.0): channel=%d", channel)
+
+	req := []Request{{
+		Cmd:    "GetMaskV20",
+		Action: 0,
+		Param: map[string]interface{}{
+			"channel": channel,
+		},
+	}}
+
+	var resp []Response
+	if err := v.client.do(ctx, req, &resp); err != nil {
+		v.client.log(ctx).Error("failed to get privacy mask configuration (v2.0): %v", err)
+		return nil, fmt.Errorf("GetMaskV20 request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetMaskV20")
+		v.client.log(ctx).Error("failed to get privacy mask configuration (v2.0): %v", err)
+		return nil, err
+	}
+
+	if err := resp[0].ToAPIError(); err != nil {
+		v.client.log(ctx).Error("failed to get privacy mask configuration (v2.0): %v", err)
+		return nil, err
+	}
+
+	var value MaskV20Value
+	if err := v.client.unmarshal(resp[0].Value, &value); err != nil {
+		v.client.log(ctx).Error("failed to parse privacy mask configuration (v2.0) response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetMaskV20 response: %w", err)
+	}
+
+	return &value.Mask, nil
+}
+
+// SetMaskV20 sets privacy mask configuration (v2.0)
+func (v *VideoAPI) SetMaskV20(ctx context.Context, mask MaskV20) error {
+	v.client.log(ctx).Info("setting privacy mask configuration (v2.0): channel=%d", mask.Channel)
+
+	req := []Request{{
+		Cmd: "SetMaskV20",
+		Param: map[string]interface{}{
+			"Mask": mask,
+		},
+	}}
+
+	var resp []Response
+	if err := v.client.do(ctx, req, &resp); err != nil {
+		v.client.log(ctx).Error("failed to set privacy mask configuration (v2.0): %v", err)
+		return fmt.Errorf("SetMaskV20 request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetMaskV20")
+		v.client.log(ctx).Error("failed to set privacy mask configuration (v2.0): %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		v.client.log(ctx).Error("failed to set privacy mask configuration (v2.0): %v", apiErr)
+		return apiErr
+	}
+
+	v.client.log(ctx).Info("successfully set privacy mask configuration (v2.0)")
+	return nil
+}
+
+// GetPrivacyMask gets privacy mask configuration with GetMaskV20 if the
+// channel's mask ability is ver 2 or later, and with GetMask otherwise
+func (v *VideoAPI) GetPrivacyMask(ctx context.Context, channel int) (*MaskV20, error) {
+	v20, err := v.maskV20(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	if v20 {
+		return v.GetMaskV20(ctx, channel)
+	}
+
+	mask, err := v.GetMask(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	return &MaskV20{Channel: mask.Channel, Enable: mask.Enable, Area: mask.Area}, nil
+}
+
+// SetPrivacyMask sets privacy mask configuration with SetMaskV20 if the
+// channel's mask ability is ver 2 or later, and with SetMask otherwise, in
+// which case a block grid or more than 4 areas are rejected
+func (v *VideoAPI) SetPrivacyMask(ctx context.Context, mask MaskV20) error {
+	v20, err := v.maskV20(ctx, mask.Channel)
+	if err != nil {
+		return err
+	}
+	if v20 {
+		return v.SetMaskV20(ctx, mask)
+	}
+
+	if mask.Scope != nil {
+		return fmt.Errorf("channel %d does not support block grid privacy masks", mask.Channel)
+	}
+	if len(mask.Area) > maxMaskAreas {
+		return fmt.Errorf("channel %d supports at most %d privacy mask areas, got %d", mask.Channel, maxMaskAreas, len(mask.Area))
+	}
+	return v.SetMask(ctx, Mask{Channel: mask.Channel, Enable: mask.Enable, Area: mask.Area})
+}
+
+// maskV20 reports whether channel supports the v2.0 mask commands
+func (v *VideoAPI) maskV20(ctx context.Context, channel int) (bool, error) {
+	ability, err := v.client.abilities(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get abilities: %w", err)
+	}
+	ver, _, ok := ability.Version(channel, "mask")
+	return ok && ver >= 2, nil
+}
+
 // Crop represents video crop/zoom configuration
 type Crop struct {
 	Channel      int `json:"channel"`      // Channel number
@@ -416,7 +754,7 @@ type StitchValue struct {
 
 // GetCrop gets video crop/zoom configuration
 func (v *VideoAPI) GetCrop(ctx context.Context, channel int) (*Crop, error) {
-	v.client.logger.Debug("getting crop configuration: channel=%d", channel)
+	v.client.log(ctx).Debug("getting crop configuration: channel=%d", channel)
 
 	req := []Request{{
 		Cmd:    "GetCrop",
@@ -428,24 +766,24 @@ func (v *VideoAPI) GetCrop(ctx context.Context, channel int) (*Crop, error) {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to get crop configuration: %v", err)
+		v.client.log(ctx).Error("failed to get crop configuration: %v", err)
 		return nil, fmt.Errorf("GetCrop request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetCrop")
-		v.client.logger.Error("failed to get crop configuration: %v", err)
+		v.client.log(ctx).Error("failed to get crop configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		v.client.logger.Error("failed to get crop configuration: %v", err)
+		v.client.log(ctx).Error("failed to get crop configuration: %v", err)
 		return nil, err
 	}
 
 	var value CropValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		v.client.logger.Error("failed to parse crop configuration response: %v", err)
+	if err := v.client.unmarshal(resp[0].Value, &value); err != nil {
+		v.client.log(ctx).Error("failed to parse crop configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetCrop response: %w", err)
 	}
 
@@ -454,7 +792,7 @@ func (v *VideoAPI) GetCrop(ctx context.Context, channel int) (*Crop, error) {
 
 // SetCrop sets video crop/zoom configuration
 func (v *VideoAPI) SetCrop(ctx context.Context, crop Crop) error {
-	v.client.logger.Info("setting crop configuration: channel=%d", crop.Channel)
+	v.client.log(ctx).Info("setting crop configuration: channel=%d", crop.Channel)
 
 	req := []Request{{
 		Cmd: "SetCrop",
@@ -465,28 +803,28 @@ func (v *VideoAPI) SetCrop(ctx context.Context, crop Crop) error {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to set crop configuration: %v", err)
+		v.client.log(ctx).Error("failed to set crop configuration: %v", err)
 		return fmt.Errorf("SetCrop request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetCrop")
-		v.client.logger.Error("failed to set crop configuration: %v", err)
+		v.client.log(ctx).Error("failed to set crop configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		v.client.logger.Error("failed to set crop configuration: %v", apiErr)
+		v.client.log(ctx).Error("failed to set crop configuration: %v", apiErr)
 		return apiErr
 	}
 
-	v.client.logger.Info("successfully set crop configuration")
+	v.client.log(ctx).Info("successfully set crop configuration")
 	return nil
 }
 
 // GetStitch gets video stitching configuration (for multi-lens cameras)
 func (v *VideoAPI) GetStitch(ctx context.Context) (*Stitch, error) {
-	v.client.logger.Debug("getting stitch configuration")
+	v.client.log(ctx).Debug("getting stitch configuration")
 
 	req := []Request{{
 		Cmd:    "GetStitch",
@@ -495,24 +833,24 @@ func (v *VideoAPI) GetStitch(ctx context.Context) (*Stitch, error) {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to get stitch configuration: %v", err)
+		v.client.log(ctx).Error("failed to get stitch configuration: %v", err)
 		return nil, fmt.Errorf("GetStitch request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from GetStitch")
-		v.client.logger.Error("failed to get stitch configuration: %v", err)
+		v.client.log(ctx).Error("failed to get stitch configuration: %v", err)
 		return nil, err
 	}
 
 	if err := resp[0].ToAPIError(); err != nil {
-		v.client.logger.Error("failed to get stitch configuration: %v", err)
+		v.client.log(ctx).Error("failed to get stitch configuration: %v", err)
 		return nil, err
 	}
 
 	var value StitchValue
-	if err := json.Unmarshal(resp[0].Value, &value); err != nil {
-		v.client.logger.Error("failed to parse stitch configuration response: %v", err)
+	if err := v.client.unmarshal(resp[0].Value, &value); err != nil {
+		v.client.log(ctx).Error("failed to parse stitch configuration response: %v", err)
 		return nil, fmt.Errorf("failed to parse GetStitch response: %w", err)
 	}
 
@@ -521,7 +859,7 @@ func (v *VideoAPI) GetStitch(ctx context.Context) (*Stitch, error) {
 
 // SetStitch sets video stitching configuration (for multi-lens cameras)
 func (v *VideoAPI) SetStitch(ctx context.Context, stitch Stitch) error {
-	v.client.logger.Info("setting stitch configuration")
+	v.client.log(ctx).Info("setting stitch configuration")
 
 	req := []Request{{
 		Cmd: "SetStitch",
@@ -532,21 +870,21 @@ func (v *VideoAPI) SetStitch(ctx context.Context, stitch Stitch) error {
 
 	var resp []Response
 	if err := v.client.do(ctx, req, &resp); err != nil {
-		v.client.logger.Error("failed to set stitch configuration: %v", err)
+		v.client.log(ctx).Error("failed to set stitch configuration: %v", err)
 		return fmt.Errorf("SetStitch request failed: %w", err)
 	}
 
 	if len(resp) == 0 {
 		err := fmt.Errorf("empty response from SetStitch")
-		v.client.logger.Error("failed to set stitch configuration: %v", err)
+		v.client.log(ctx).Error("failed to set stitch configuration: %v", err)
 		return err
 	}
 
 	if apiErr := resp[0].ToAPIError(); apiErr != nil {
-		v.client.logger.Error("failed to set stitch configuration: %v", apiErr)
+		v.client.log(ctx).Error("failed to set stitch configuration: %v", apiErr)
 		return apiErr
 	}
 
-	v.client.logger.Info("successfully set stitch configuration")
+	v.client.log(ctx).Info("successfully set stitch configuration")
 	return nil
 }
@@ -0,0 +1,272 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// VideoLossAlarm is the video loss alarm of an NVR channel, raised when the
+// camera's video signal is lost. The commands are not part of the public
+// API guide.
+type VideoLossAlarm struct {
+	Channel int     `json:"channel"` // Channel number
+	Enable  BoolInt `json:"enable"`  // 0=disabled, 1=enabled
+	State   BoolInt `json:"state"`   // Current state (read-only): 0=video present, 1=video lost
+}
+
+// VideoLossAlarmValue wraps VideoLossAlarm for API response
+type VideoLossAlarmValue struct {
+	VideoLoss VideoLossAlarm `json:"VideoLoss"`
+}
+
+// TamperAlarm is the tamper (scene change) alarm of a camera, raised when
+// the lens is covered, sprayed or turned away. The commands are not part of
+// the public API guide.
+type TamperAlarm struct {
+	Channel     int     `json:"channel"`     // Channel number
+	Enable      BoolInt `json:"enable"`      // 0=disabled, 1=enabled
+	Sensitivity int     `json:"sensitivity"` // Sensitivity (1-100)
+	State       BoolInt `json:"state"`       // Current state (read-only): 0=idle, 1=tampered
+}
+
+// TamperAlarmValue wraps TamperAlarm for API response
+type TamperAlarmValue struct {
+	Tamper TamperAlarm `json:"Tamper"`
+}
+
+// GetVideoLossAlarm gets the video loss alarm configuration and state
+func (a *AlarmAPI) GetVideoLossAlarm(ctx context.Context, channel int) (*VideoLossAlarm, error) {
+	a.client.log(ctx).Debug("getting video loss alarm: channel=%d", channel)
+
+	req := []Request{{
+		Cmd:    "GetVideoLoss",
+		Action: 0,
+		Param: map[string]interface{}{
+			"channel": channel,
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to get video loss alarm: %v", err)
+		return nil, fmt.Errorf("GetVideoLoss request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetVideoLoss")
+		a.client.log(ctx).Error("failed to get video loss alarm: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to get video loss alarm: %v", apiErr)
+		return nil, apiErr
+	}
+
+	var value VideoLossAlarmValue
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse video loss alarm response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetVideoLoss response: %w", err)
+	}
+
+	return &value.VideoLoss, nil
+}
+
+// SetVideoLossAlarm sets the video loss alarm configuration. State is
+// ignored by the device.
+func (a *AlarmAPI) SetVideoLossAlarm(ctx context.Context, config VideoLossAlarm) error {
+	a.client.log(ctx).Info("setting video loss alarm: channel=%d enable=%d", config.Channel, config.Enable)
+
+	req := []Request{{
+		Cmd: "SetVideoLoss",
+		Param: map[string]interface{}{
+			"VideoLoss": config,
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to set video loss alarm: %v", err)
+		return fmt.Errorf("SetVideoLoss request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetVideoLoss")
+		a.client.log(ctx).Error("failed to set video loss alarm: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to set video loss alarm: %v", apiErr)
+		return apiErr
+	}
+
+	a.client.log(ctx).Info("successfully set video loss alarm")
+	return nil
+}
+
+// GetTamperAlarm gets the tamper alarm configuration and state
+func (a *AlarmAPI) GetTamperAlarm(ctx context.Context, channel int) (*TamperAlarm, error) {
+	a.client.log(ctx).Debug("getting tamper alarm: channel=%d", channel)
+
+	req := []Request{{
+		Cmd:    "GetTamper",
+		Action: 0,
+		Param: map[string]interface{}{
+			"channel": channel,
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to get tamper alarm: %v", err)
+		return nil, fmt.Errorf("GetTamper request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from GetTamper")
+		a.client.log(ctx).Error("failed to get tamper alarm: %v", err)
+		return nil, err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to get tamper alarm: %v", apiErr)
+		return nil, apiErr
+	}
+
+	var value TamperAlarmValue
+	if err := a.client.unmarshal(resp[0].Value, &value); err != nil {
+		a.client.log(ctx).Error("failed to parse tamper alarm response: %v", err)
+		return nil, fmt.Errorf("failed to parse GetTamper response: %w", err)
+	}
+
+	return &value.Tamper, nil
+}
+
+// SetTamperAlarm sets the tamper alarm configuration. State is ignored by
+// the device.
+func (a *AlarmAPI) SetTamperAlarm(ctx context.Context, config TamperAlarm) error {
+	if config.Sensitivity < 0 || config.Sensitivity > 100 {
+		return fmt.Errorf("sensitivity must be between 0 and 100")
+	}
+
+	a.client.log(ctx).Info("setting tamper alarm: channel=%d enable=%d sensitivity=%d", config.Channel, config.Enable, config.Sensitivity)
+
+	req := []Request{{
+		Cmd: "SetTamper",
+		Param: map[string]interface{}{
+			"Tamper": config,
+		},
+	}}
+
+	var resp []Response
+	if err := a.client.do(ctx, req, &resp); err != nil {
+		a.client.log(ctx).Error("failed to set tamper alarm: %v", err)
+		return fmt.Errorf("SetTamper request failed: %w", err)
+	}
+
+	if len(resp) == 0 {
+		err := fmt.Errorf("empty response from SetTamper")
+		a.client.log(ctx).Error("failed to set tamper alarm: %v", err)
+		return err
+	}
+
+	if apiErr := resp[0].ToAPIError(); apiErr != nil {
+		a.client.log(ctx).Error("failed to set tamper alarm: %v", apiErr)
+		return apiErr
+	}
+
+	a.client.log(ctx).Info("successfully set tamper alarm")
+	return nil
+}
+
+// WatchVideoAlarms polls the video loss and tamper alarm states of channel
+// every interval and calls handler with EventVideoLoss/EventVideoRestored
+// and EventTamperStart/EventTamperStop whenever one changes. An alarm the
+// device does not support is no longer polled; if neither is supported an
+// error is returned. Other polling errors are logged and retried on the
+// next tick. It blocks until ctx is done and returns ctx.Err().
+func (a *AlarmAPI) WatchVideoAlarms(ctx context.Context, channel int, interval time.Duration, handler EventHandler) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler must not be nil")
+	}
+
+	watchers := []struct {
+		name        string
+		get         func() (bool, error)
+		start, stop EventType
+		supported   bool
+		known, on   bool
+	}{
+		{
+			name: "video loss",
+			get: func() (bool, error) {
+				v, err := a.GetVideoLossAlarm(ctx, channel)
+				return err == nil && v.State.Bool(), err
+			},
+			start: EventVideoLoss, stop: EventVideoRestored, supported: true,
+		},
+		{
+			name: "tamper",
+			get: func() (bool, error) {
+				t, err := a.GetTamperAlarm(ctx, channel)
+				return err == nil && t.State.Bool(), err
+			},
+			start: EventTamperStart, stop: EventTamperStop, supported: true,
+		},
+	}
+
+	poll := func() error {
+		supported := 0
+		for i := range watchers {
+			w := &watchers[i]
+			if !w.supported {
+				continue
+			}
+			on, err := w.get()
+			switch {
+			case IsNotSupported(err):
+				a.client.log(ctx).Info("%s alarm not supported on channel %d, no longer watching it", w.name, channel)
+				w.supported = false
+				continue
+			case err != nil:
+				a.client.log(ctx).Warn("%s alarm poll failed: %v", w.name, err)
+			case w.known && on != w.on:
+				typ := w.stop
+				if on {
+					typ = w.start
+				}
+				handler(Event{Type: typ, Host: a.client.Host(), Channel: channel, Time: time.Now()})
+				fallthrough
+			default:
+				w.known, w.on = true, on
+			}
+			supported++
+		}
+		if supported == 0 {
+			return fmt.Errorf("channel %d supports neither video loss nor tamper alarms", channel)
+		}
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if err := poll(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := poll(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAlarmAPI_VideoLossAlarm(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetVideoLoss": `{"VideoLoss": {"channel": 2, "enable": 1, "state": 1}}`,
+		"SetVideoLoss": "",
+	})
+	client := server.client()
+
+	alarm, err := client.Alarm.GetVideoLossAlarm(t.Context(), 2)
+	if err != nil {
+		t.Fatalf("GetVideoLossAlarm failed: %v", err)
+	}
+	if alarm.Channel != 2 || alarm.Enable != 1 || alarm.State != 1 {
+		t.Errorf("unexpected alarm: %+v", alarm)
+	}
+
+	if err := client.Alarm.SetVideoLossAlarm(t.Context(), VideoLossAlarm{Channel: 2, Enable: 0}); err != nil {
+		t.Fatalf("SetVideoLossAlarm failed: %v", err)
+	}
+	var param struct{ VideoLoss VideoLossAlarm }
+	if err := json.Unmarshal(server.lastParam("SetVideoLoss"), &param); err != nil {
+		t.Fatal(err)
+	}
+	if param.VideoLoss.Channel != 2 || param.VideoLoss.Enable != 0 {
+		t.Errorf("unexpected SetVideoLoss param: %+v", param.VideoLoss)
+	}
+}
+
+func TestAlarmAPI_TamperAlarm(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetTamper": `{"Tamper": {"channel": 0, "enable": 1, "sensitivity": 50, "state": 0}}`,
+		"SetTamper": "",
+	})
+	client := server.client()
+
+	alarm, err := client.Alarm.GetTamperAlarm(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("GetTamperAlarm failed: %v", err)
+	}
+	if alarm.Sensitivity != 50 || alarm.State != 0 {
+		t.Errorf("unexpected alarm: %+v", alarm)
+	}
+
+	if err := client.Alarm.SetTamperAlarm(t.Context(), TamperAlarm{Sensitivity: 101}); err == nil {
+		t.Error("expected error for sensitivity 101")
+	}
+	if err := client.Alarm.SetTamperAlarm(t.Context(), TamperAlarm{Enable: 1, Sensitivity: 80}); err != nil {
+		t.Fatalf("SetTamperAlarm failed: %v", err)
+	}
+	if server.callCount("SetTamper") != 1 {
+		t.Errorf("expected 1 SetTamper call, got %d", server.callCount("SetTamper"))
+	}
+}
+
+func TestAlarmAPI_WatchVideoAlarms(t *testing.T) {
+	// Tamper is not supported: only video loss is watched
+	server := newCmdServer(t, map[string]string{
+		"GetVideoLoss": `{"VideoLoss": {"channel": 1, "enable": 1, "state": 0}}`,
+	})
+	client := server.client()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	events := make(chan Event, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Alarm.WatchVideoAlarms(ctx, 1, 10*time.Millisecond, func(ev Event) {
+			events <- ev
+		})
+	}()
+
+	for server.callCount("GetVideoLoss") < 2 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	server.set("GetVideoLoss", `{"VideoLoss": {"channel": 1, "enable": 1, "state": 1}}`)
+
+	select {
+	case ev := <-events:
+		if ev.Type != EventVideoLoss || ev.Channel != 1 {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for EventVideoLoss")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n := server.callCount("GetTamper"); n != 1 {
+		t.Errorf("expected unsupported tamper alarm to be polled once, got %d", n)
+	}
+
+	unsupported := newCmdServer(t, map[string]string{}).client()
+	if err := unsupported.Alarm.WatchVideoAlarms(t.Context(), 0, time.Second, func(Event) {}); err == nil {
+		t.Error("expected error when neither alarm is supported")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -439,3 +440,101 @@ func TestVideoAPI_SetStitch(t *testing.T) {
 		t.Fatalf("SetStitch failed: %v", err)
 	}
 }
+
+func TestMaskArea_JSON(t *testing.T) {
+	var area MaskArea
+	if err := json.Unmarshal([]byte(`{"screen": {"height": 1080, "width": 1920}, "block": {"x": 10, "y": 20, "width": 30, "height": 40}}`), &area); err != nil {
+		t.Fatal(err)
+	}
+	if area.X != 10 || area.Y != 20 || area.Width != 30 || area.Height != 40 || area.Screen.Width != 1920 {
+		t.Errorf("unexpected area: %+v", area)
+	}
+
+	data, err := json.Marshal(area)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"screen":{"height":1080,"width":1920},"block":{"x":10,"y":20,"width":30,"height":40}}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestVideoAPI_PrivacyMask(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetAbility": `{"Ability": {"abilityChn": [{"mask": {"permit": 6, "ver": 1}}, {"mask": {"permit": 6, "ver": 2}}]}}`,
+		"GetMask":    `{"Mask": {"channel": 0, "enable": 1, "area": [{"screen": {"height": 1080, "width": 1920}, "block": {"x": 1, "y": 2, "width": 3, "height": 4}}]}}`,
+		"SetMask":    "",
+		"GetMaskV20": `{"Mask": {"channel": 1, "enable": 1, "scope": {"cols": 80, "rows": 60, "table": "0011"}}}`,
+		"SetMaskV20": "",
+	})
+	client := server.client()
+
+	mask, err := client.Video.GetPrivacyMask(t.Context(), 0)
+	if err != nil {
+		t.Fatalf("GetPrivacyMask(0) failed: %v", err)
+	}
+	if len(mask.Area) != 1 || mask.Area[0].Height != 4 || mask.Scope != nil {
+		t.Errorf("unexpected v1 mask: %+v", mask)
+	}
+	mask, err = client.Video.GetPrivacyMask(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("GetPrivacyMask(1) failed: %v", err)
+	}
+	if mask.Scope == nil || mask.Scope.Table != "0011" {
+		t.Errorf("unexpected v2.0 mask: %+v", mask)
+	}
+
+	areas := make([]MaskArea, 6)
+	if err := client.Video.SetPrivacyMask(t.Context(), MaskV20{Channel: 0, Enable: 1, Area: areas}); err == nil {
+		t.Error("expected error for 6 areas on a v1 channel")
+	}
+	if err := client.Video.SetPrivacyMask(t.Context(), MaskV20{Channel: 0, Scope: &MdScope{}}); err == nil {
+		t.Error("expected error for a block grid on a v1 channel")
+	}
+	if err := client.Video.SetPrivacyMask(t.Context(), MaskV20{Channel: 0, Enable: 1, Area: areas[:2]}); err != nil {
+		t.Errorf("SetPrivacyMask(0) failed: %v", err)
+	}
+	if err := client.Video.SetPrivacyMask(t.Context(), MaskV20{Channel: 1, Enable: 1, Area: areas}); err != nil {
+		t.Errorf("SetPrivacyMask(1) failed: %v", err)
+	}
+	if server.callCount("SetMask") != 1 || server.callCount("SetMaskV20") != 1 {
+		t.Errorf("unexpected calls: SetMask=%d SetMaskV20=%d", server.callCount("SetMask"), server.callCount("SetMaskV20"))
+	}
+}
+
+func TestVideoAPI_EnumValidation(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"SetOsd": "",
+		"SetIsp": "",
+	})
+	client := server.client()
+
+	osd := Osd{OsdChannel: OsdChannel{Pos: "Middle"}, OsdTime: OsdTime{Pos: OsdPosTopCenter}}
+	err := client.Video.SetOsd(t.Context(), osd)
+	if err == nil || !strings.Contains(err.Error(), `"Lower Right"`) {
+		t.Errorf("expected error listing the valid positions, got %v", err)
+	}
+
+	for _, isp := range []Isp{
+		{AntiFlicker: "50Hz"},
+		{DayNight: "Night"},
+		{BackLight: "WDR"},
+	} {
+		if err := client.Video.SetIsp(t.Context(), isp); err == nil {
+			t.Errorf("expected error for %+v", isp)
+		}
+	}
+	if n := server.callCount("SetOsd") + server.callCount("SetIsp"); n != 0 {
+		t.Errorf("invalid settings were sent %d times", n)
+	}
+
+	osd.OsdChannel.Pos = OsdPosLowerRight
+	if err := client.Video.SetOsd(t.Context(), osd); err != nil {
+		t.Errorf("SetOsd failed: %v", err)
+	}
+	isp := Isp{AntiFlicker: AntiFlicker60Hz, DayNight: DayNightBlackWhite, BackLight: BackLightDynamicRange}
+	if err := client.Video.SetIsp(t.Context(), isp); err != nil {
+		t.Errorf("SetIsp failed: %v", err)
+	}
+}
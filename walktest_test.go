@@ -0,0 +1,99 @@
+package reolink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAlarmAPI_WalkTest(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetMdAlarm":  `{"MdAlarm": {"channel": 0, "newSens": {"sens": [{"id": 0, "sensitivity": 40}, {"id": 1, "sensitivity": 60}]}}}`,
+		"SetMdAlarm":  "",
+		"GetAiAlarm":  `{"AiAlarm": {"channel": 0, "ai_type": "people", "sensitivity": 50, "stay_time": 3}}`,
+		"SetAiAlarm":  "",
+		"GetWhiteLed": `{"WhiteLed": {"channel": 0, "state": 0, "mode": 1, "bright": 80}}`,
+		"SetWhiteLed": "",
+		"GetMdState":  `{"state": 0}`,
+	})
+	client := server.client()
+
+	var (
+		mu     sync.Mutex
+		events []Event
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Alarm.WalkTest(t.Context(), WalkTestOptions{
+			Duration:      300 * time.Millisecond,
+			AITypes:       []string{AITypePeople},
+			Interval:      10 * time.Millisecond,
+			Light:         true,
+			BlinkDuration: 10 * time.Millisecond,
+		}, func(ev Event) {
+			mu.Lock()
+			events = append(events, ev)
+			mu.Unlock()
+		})
+	}()
+
+	for server.callCount("GetMdState") < 2 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	var raised struct{ MdAlarm MdAlarm }
+	if err := json.Unmarshal(server.lastParam("SetMdAlarm"), &raised); err != nil {
+		t.Fatal(err)
+	}
+	if s := raised.MdAlarm.NewSens.Sens; len(s) != 2 || s[0].Sensitivity != 100 || s[1].Sensitivity != 100 {
+		t.Errorf("unexpected raised motion sensitivity: %+v", s)
+	}
+	server.set("GetMdState", `{"state": 1}`)
+
+	if err := <-done; err != nil {
+		t.Fatalf("WalkTest failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventMotionStart {
+		t.Errorf("unexpected events: %+v", events)
+	}
+	if n := server.callCount("SetWhiteLed"); n != 2 {
+		t.Errorf("expected the white LED to be flashed once, got %d SetWhiteLed calls", n)
+	}
+
+	// Settings are restored
+	md, err := client.Alarm.GetMdAlarm(t.Context(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.NewSens.Sens[0].Sensitivity != 40 || md.NewSens.Sens[1].Sensitivity != 60 {
+		t.Errorf("motion sensitivity not restored: %+v", md.NewSens.Sens)
+	}
+	ai, err := client.AI.GetAiSensitivity(t.Context(), 0, AITypePeople)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ai.Sensitivity != 50 || ai.StayTime != 3 {
+		t.Errorf("AI sensitivity not restored: %+v", ai)
+	}
+}
+
+func TestAlarmAPI_WalkTestCancelled(t *testing.T) {
+	server := newCmdServer(t, map[string]string{
+		"GetMdAlarm": `{"MdAlarm": {"channel": 0, "newSens": {"sens": [{"id": 0, "sensitivity": 40}]}}}`,
+		"SetMdAlarm": "",
+		"GetMdState": `{"state": 0}`,
+	})
+	client := server.client()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	err := client.Alarm.WalkTest(ctx, WalkTestOptions{Interval: 10 * time.Millisecond}, func(Event) {})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	if n := server.callCount("SetMdAlarm"); n != 2 {
+		t.Errorf("expected motion sensitivity to be raised and restored, got %d SetMdAlarm calls", n)
+	}
+}
@@ -0,0 +1,236 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Watchdog recovery actions reported in the Detail of EventWatchdogAction
+const (
+	WatchdogRelogin = "relogin"
+	WatchdogReboot  = "reboot"
+)
+
+// WatchdogPolicy configures System.Watchdog and Fleet.Watchdog, which probe
+// cameras periodically and try to recover the ones that keep failing:
+// after ReloginAfter consecutive failures the session is dropped and the
+// client logs in again, and after EscalateAfter failures the camera is
+// rebooted if AllowReboot is set and EventWatchdogEscalation is raised.
+type WatchdogPolicy struct {
+	Interval time.Duration // Probe interval (default: 1m)
+	// Probe checks that a camera works (default: System.GetTime, which is
+	// never cached)
+	Probe func(ctx context.Context, client *Client) error
+
+	// ReloginAfter is the number of consecutive failures after which, and
+	// after every further multiple of which, the client logs in again; 0
+	// disables re-login. Logins are spaced out like this because cameras
+	// lock accounts after repeated failed logins.
+	ReloginAfter int
+	// EscalateAfter is the number of consecutive failures after which the
+	// outage is escalated, once per outage; 0 disables escalation
+	EscalateAfter int
+	// AllowReboot lets escalation reboot the camera
+	AllowReboot bool
+	// RebootCooldown is the minimum time between two reboots of a camera
+	// (default: 1h)
+	RebootCooldown time.Duration
+	// RebootGrace is how long failures are not counted after a reboot,
+	// while the camera starts up (default: 3m)
+	RebootGrace time.Duration
+
+	// Handler, if not nil, receives EventWatchdogAction,
+	// EventWatchdogEscalation and EventWatchdogRecovered
+	Handler EventHandler
+}
+
+// Validate checks the failure thresholds
+func (p *WatchdogPolicy) Validate() error {
+	if p.Interval < 0 || p.RebootCooldown < 0 || p.RebootGrace < 0 {
+		return fmt.Errorf("durations must not be negative")
+	}
+	if p.ReloginAfter < 0 || p.EscalateAfter < 0 {
+		return fmt.Errorf("failure thresholds must not be negative")
+	}
+	if p.AllowReboot && p.EscalateAfter == 0 {
+		return fmt.Errorf("reboot requires a positive EscalateAfter")
+	}
+	return nil
+}
+
+// withDefaults returns the policy with unset fields defaulted
+func (p WatchdogPolicy) withDefaults() WatchdogPolicy {
+	if p.Interval == 0 {
+		p.Interval = time.Minute
+	}
+	if p.Probe == nil {
+		p.Probe = func(ctx context.Context, client *Client) error {
+			_, err := client.System.GetTime(ctx)
+			return err
+		}
+	}
+	if p.RebootCooldown == 0 {
+		p.RebootCooldown = time.Hour
+	}
+	if p.RebootGrace == 0 {
+		p.RebootGrace = 3 * time.Minute
+	}
+	return p
+}
+
+// watchdogState tracks one camera's outage
+type watchdogState struct {
+	failures   int
+	acted      bool // A recovery action or escalation happened this outage
+	rebootedAt time.Time
+}
+
+// check probes client once and takes the recovery actions its failure
+// count calls for. Failures of sleeping battery cameras and of cameras
+// that are still rebooting are not counted.
+func (p *WatchdogPolicy) check(ctx context.Context, client *Client, st *watchdogState) {
+	emit := func(typ EventType, detail string) {
+		if p.Handler != nil {
+			p.Handler(Event{Type: typ, Host: client.Host(), Time: time.Now(), Detail: detail})
+		}
+	}
+
+	err := p.Probe(ctx, client)
+	now := time.Now()
+	if err == nil {
+		if st.acted {
+			client.log(ctx).Info("watchdog: camera recovered after %d failures", st.failures)
+			emit(EventWatchdogRecovered, "")
+		}
+		st.failures, st.acted = 0, false
+		client.setGauge("reolink_watchdog_failures", 0, nil)
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if errors.Is(err, ErrCameraAsleep) {
+		client.log(ctx).Debug("watchdog: camera asleep, not counted as failure")
+		return
+	}
+	if !st.rebootedAt.IsZero() && now.Sub(st.rebootedAt) < p.RebootGrace {
+		client.log(ctx).Debug("watchdog: camera still rebooting: %v", err)
+		return
+	}
+
+	st.failures++
+	client.setGauge("reolink_watchdog_failures", float64(st.failures), nil)
+	client.log(ctx).Warn("watchdog: probe failed (%d consecutive): %v", st.failures, err)
+
+	if p.ReloginAfter > 0 && st.failures%p.ReloginAfter == 0 && (p.EscalateAfter == 0 || st.failures < p.EscalateAfter) {
+		st.acted = true
+		client.addCounter("reolink_watchdog_actions_total", 1, map[string]string{"action": WatchdogRelogin})
+		emit(EventWatchdogAction, WatchdogRelogin)
+		if err := client.relogin(ctx); err != nil {
+			client.log(ctx).Warn("watchdog: re-login failed: %v", err)
+		}
+	}
+
+	if p.EscalateAfter > 0 && st.failures == p.EscalateAfter {
+		st.acted = true
+		if p.AllowReboot && (st.rebootedAt.IsZero() || now.Sub(st.rebootedAt) >= p.RebootCooldown) {
+			client.addCounter("reolink_watchdog_actions_total", 1, map[string]string{"action": WatchdogReboot})
+			emit(EventWatchdogAction, WatchdogReboot)
+			if err := client.System.Reboot(ctx); err != nil {
+				client.log(ctx).Warn("watchdog: reboot failed: %v", err)
+			} else {
+				st.rebootedAt = now
+			}
+		}
+		client.log(ctx).Error("watchdog: escalating after %d consecutive failures: %v", st.failures, err)
+		client.addCounter("reolink_watchdog_escalations_total", 1, nil)
+		emit(EventWatchdogEscalation, err.Error())
+	}
+}
+
+// relogin drops the session, including a stored one, and logs in again
+func (c *Client) relogin(ctx context.Context) error {
+	c.SetToken("")
+	c.updateState(ctx, func(s *HostState) {
+		s.Token = ""
+		s.TokenExpiry = time.Time{}
+	})
+	return c.Login(ctx)
+}
+
+// Watchdog probes the camera every policy.Interval and takes the recovery
+// actions of policy when probes keep failing. It blocks until ctx is done
+// and returns ctx.Err().
+func (s *SystemAPI) Watchdog(ctx context.Context, policy WatchdogPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	policy = policy.withDefaults()
+
+	var st watchdogState
+	ticker := time.NewTicker(policy.Interval)
+	defer ticker.Stop()
+
+	policy.check(ctx, s.client, &st)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			policy.check(ctx, s.client, &st)
+		}
+	}
+}
+
+// Watchdog runs System.Watchdog's checks for every camera of the fleet
+// concurrently, picking up cameras added and removed while it runs. It
+// blocks until ctx is done and returns ctx.Err().
+func (f *Fleet) Watchdog(ctx context.Context, policy WatchdogPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	policy = policy.withDefaults()
+
+	var (
+		mu     sync.Mutex
+		states = make(map[string]*watchdogState)
+	)
+	run := func() {
+		f.Each(ctx, func(ctx context.Context, name string, client *Client) error {
+			mu.Lock()
+			st, ok := states[name]
+			if !ok {
+				st = &watchdogState{}
+				states[name] = st
+			}
+			mu.Unlock()
+			policy.check(ctx, client, st)
+			return nil
+		})
+
+		// Forget cameras that left the fleet
+		mu.Lock()
+		for name := range states {
+			if _, ok := f.Get(name); !ok {
+				delete(states, name)
+			}
+		}
+		mu.Unlock()
+	}
+
+	ticker := time.NewTicker(policy.Interval)
+	defer ticker.Stop()
+
+	run()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			run()
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchdogPolicy_Check(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"Login":  `{"Token": {"name": "fresh", "leaseTime": 3600}}`,
+		"Reboot": "",
+	})
+	client := srv.client()
+	client.username, client.password = "admin", "secret"
+	client.SetToken("stale")
+
+	var (
+		failing = true
+		events  []Event
+	)
+	policy := WatchdogPolicy{
+		Probe: func(ctx context.Context, c *Client) error {
+			if failing {
+				return errors.New("probe failed")
+			}
+			return nil
+		},
+		ReloginAfter:  2,
+		EscalateAfter: 5,
+		AllowReboot:   true,
+		Handler:       func(ev Event) { events = append(events, ev) },
+	}.withDefaults()
+
+	var st watchdogState
+	for range 5 {
+		policy.check(t.Context(), client, &st)
+	}
+
+	if n := srv.callCount("Login"); n != 2 {
+		t.Errorf("expected re-login after 2 and 4 failures, got %d logins", n)
+	}
+	if client.GetToken() != "fresh" {
+		t.Errorf("expected new token, got %q", client.GetToken())
+	}
+	if n := srv.callCount("Reboot"); n != 1 {
+		t.Errorf("expected one reboot, got %d", n)
+	}
+	want := []struct {
+		typ    EventType
+		detail string
+	}{
+		{EventWatchdogAction, WatchdogRelogin},
+		{EventWatchdogAction, WatchdogRelogin},
+		{EventWatchdogAction, WatchdogReboot},
+		{EventWatchdogEscalation, "probe failed"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Type != w.typ || events[i].Detail != w.detail {
+			t.Errorf("event %d: expected %s/%q, got %s/%q", i, w.typ, w.detail, events[i].Type, events[i].Detail)
+		}
+	}
+
+	// Failures while the camera reboots are not counted
+	policy.check(t.Context(), client, &st)
+	if st.failures != 5 {
+		t.Errorf("expected failures to pause during reboot grace, got %d", st.failures)
+	}
+
+	failing = false
+	events = nil
+	policy.check(t.Context(), client, &st)
+	if len(events) != 1 || events[0].Type != EventWatchdogRecovered || st.failures != 0 {
+		t.Errorf("expected recovery, got %+v (failures %d)", events, st.failures)
+	}
+
+	// A second outage within the cooldown escalates without rebooting
+	failing = true
+	st.rebootedAt = time.Now().Add(-10 * time.Minute)
+	for range 5 {
+		policy.check(t.Context(), client, &st)
+	}
+	if n := srv.callCount("Reboot"); n != 1 {
+		t.Errorf("expected no reboot within the cooldown, got %d reboots", n)
+	}
+	if last := events[len(events)-1]; last.Type != EventWatchdogEscalation {
+		t.Errorf("expected escalation, got %s", last.Type)
+	}
+}
+
+func TestWatchdogPolicy_Validate(t *testing.T) {
+	invalid := []WatchdogPolicy{
+		{Interval: -time.Second},
+		{ReloginAfter: -1},
+		{AllowReboot: true},
+	}
+	for i, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("policy %d: expected error", i)
+		}
+	}
+	if err := (&WatchdogPolicy{ReloginAfter: 3, EscalateAfter: 10, AllowReboot: true}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFleet_Watchdog(t *testing.T) {
+	healthy := newCmdServer(t, map[string]string{"GetTime": `{"Time": {"year": 2024}}`})
+	broken := newCmdServer(t, map[string]string{})
+
+	fleet := NewFleet()
+	fleet.Add("healthy", healthy.client())
+	fleet.Add("broken", broken.client())
+
+	var (
+		mu          sync.Mutex
+		escalations []string
+	)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	policy := WatchdogPolicy{
+		Interval:      5 * time.Millisecond,
+		EscalateAfter: 3,
+		Handler: func(ev Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			if ev.Type == EventWatchdogEscalation {
+				escalations = append(escalations, ev.Host)
+				cancel()
+			}
+		},
+	}
+
+	if err := fleet.Watchdog(ctx, policy); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(escalations) != 1 {
+		t.Fatalf("expected one escalation, got %v", escalations)
+	}
+	if n := broken.callCount("GetTime"); n < 3 {
+		t.Errorf("expected at least 3 probes of the broken camera, got %d", n)
+	}
+	if n := broken.callCount("Reboot"); n != 0 {
+		t.Errorf("reboot not allowed, got %d reboots", n)
+	}
+}
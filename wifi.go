@@ -0,0 +1,100 @@
+package reolink
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// WatchWifiSignal polls GetWifiSignal every interval, records it in the
+// reolink_wifi_signal gauge and calls handler with EventWeakSignal when the
+// signal drops below threshold and EventSignalRestored when it is back at or
+// above it. A weak first sample is reported too. Polling errors are logged
+// and retried on the next tick. It blocks until ctx is done and returns
+// ctx.Err().
+func (n *NetworkAPI) WatchWifiSignal(ctx context.Context, interval time.Duration, threshold int, handler EventHandler) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler must not be nil")
+	}
+
+	weak := false
+	poll := func() {
+		signal, err := n.GetWifiSignal(ctx)
+		if err != nil {
+			n.client.log(ctx).Warn("WiFi signal poll failed: %v", err)
+			return
+		}
+		n.client.setGauge("reolink_wifi_signal", float64(signal.Signal), nil)
+
+		nowWeak := signal.Signal < threshold
+		if nowWeak == weak {
+			return
+		}
+		weak = nowWeak
+		ev := Event{Type: EventSignalRestored, Host: n.client.Host(), Time: time.Now(), Detail: strconv.Itoa(signal.Signal)}
+		if weak {
+			ev.Type = EventWeakSignal
+			n.client.log(ctx).Warn("weak WiFi signal: %d < %d", signal.Signal, threshold)
+		}
+		handler(ev)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+// RoamingReport is the result of CheckRoaming
+type RoamingReport struct {
+	SSID     string       // Network the camera is configured for
+	Signal   int          // Current signal strength
+	Best     *WifiNetwork // Strongest scanned access point of SSID, nil if none was seen
+	Stronger bool         // Best is stronger than the current signal by at least the margin
+}
+
+// CheckRoaming scans for access points of the camera's configured network
+// and reports whether one is stronger than the current signal by at least
+// margin. Cameras pick an access point when they associate, so a stronger
+// one is typically joined by reapplying the WiFi settings or rebooting.
+func (n *NetworkAPI) CheckRoaming(ctx context.Context, margin int) (*RoamingReport, error) {
+	wifi, err := n.GetWifi(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WiFi configuration: %w", err)
+	}
+	signal, err := n.GetWifiSignal(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WiFi signal: %w", err)
+	}
+	networks, err := n.ScanWifi(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan WiFi: %w", err)
+	}
+
+	report := &RoamingReport{SSID: wifi.SSID, Signal: signal.Signal}
+	for i := range networks {
+		ap := networks[i]
+		if ap.SSID != wifi.SSID {
+			continue
+		}
+		if report.Best == nil || ap.Signal > report.Best.Signal {
+			report.Best = &ap
+		}
+	}
+	report.Stronger = report.Best != nil && report.Best.Signal >= signal.Signal+margin
+
+	n.client.log(ctx).Info("WiFi roaming check: ssid=%s signal=%d stronger=%t", report.SSID, report.Signal, report.Stronger)
+	return report, nil
+}
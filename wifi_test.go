@@ -0,0 +1,100 @@
+package reolink
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatchWifiSignal(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetWifiSignal": `{"signal": 80}`,
+	})
+	client := srv.client()
+	metrics := NewMetrics()
+	WithMetrics(metrics)(client)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan Event, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Network.WatchWifiSignal(ctx, 10*time.Millisecond, 40, func(ev Event) {
+			events <- ev
+		})
+	}()
+
+	waitEvent := func(want EventType, detail string) {
+		t.Helper()
+		select {
+		case ev := <-events:
+			if ev.Type != want || ev.Detail != detail {
+				t.Errorf("unexpected event: %+v", ev)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	for srv.callCount("GetWifiSignal") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.set("GetWifiSignal", `{"signal": 25}`)
+	waitEvent(EventWeakSignal, "25")
+	srv.set("GetWifiSignal", `{"signal": 55}`)
+	waitEvent(EventSignalRestored, "55")
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	found := false
+	for _, m := range metrics.Snapshot() {
+		if m.Name == "reolink_wifi_signal" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected reolink_wifi_signal gauge")
+	}
+}
+
+func TestWatchWifiSignal_InvalidArgs(t *testing.T) {
+	client := newCmdServer(t, nil).client()
+
+	if err := client.Network.WatchWifiSignal(t.Context(), 0, 40, func(Event) {}); err == nil {
+		t.Error("expected error for zero interval")
+	}
+	if err := client.Network.WatchWifiSignal(t.Context(), time.Second, 40, nil); err == nil {
+		t.Error("expected error for nil handler")
+	}
+}
+
+func TestNetworkAPI_CheckRoaming(t *testing.T) {
+	srv := newCmdServer(t, map[string]string{
+		"GetWifi":       `{"Wifi": {"ssid": "home", "password": ""}}`,
+		"GetWifiSignal": `{"signal": 40}`,
+		"ScanWifi":      `[{"ssid": "home", "signal": 45}, {"ssid": "neighbour", "signal": 90}, {"ssid": "home", "signal": 70}]`,
+	})
+	client := srv.client()
+
+	report, err := client.Network.CheckRoaming(t.Context(), 10)
+	if err != nil {
+		t.Fatalf("CheckRoaming failed: %v", err)
+	}
+	if !report.Stronger || report.Best == nil || report.Best.Signal != 70 || report.Signal != 40 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	srv.set("ScanWifi", `[{"ssid": "home", "signal": 45}]`)
+	report, err = client.Network.CheckRoaming(t.Context(), 10)
+	if err != nil {
+		t.Fatalf("CheckRoaming failed: %v", err)
+	}
+	if report.Stronger {
+		t.Errorf("expected no stronger AP: %+v", report)
+	}
+}